@@ -32,13 +32,20 @@ import (
 	"github.com/devtron-labs/devtron/api/connector"
 	"github.com/devtron-labs/devtron/api/dashboardEvent"
 	"github.com/devtron-labs/devtron/api/deployment"
+	"github.com/devtron-labs/devtron/api/deploymentApproval"
+	"github.com/devtron-labs/devtron/api/deploymentSchedule"
+	"github.com/devtron-labs/devtron/api/deploymentWindow"
 	"github.com/devtron-labs/devtron/api/externalLink"
 	client "github.com/devtron-labs/devtron/api/helm-app"
+	"github.com/devtron-labs/devtron/api/imageSignature"
+	"github.com/devtron-labs/devtron/api/manifestScan"
 	"github.com/devtron-labs/devtron/api/module"
 	"github.com/devtron-labs/devtron/api/restHandler"
 	pipeline2 "github.com/devtron-labs/devtron/api/restHandler/app"
 	"github.com/devtron-labs/devtron/api/router"
 	"github.com/devtron-labs/devtron/api/router/pubsub"
+	"github.com/devtron-labs/devtron/api/sbom"
+	"github.com/devtron-labs/devtron/api/secretScan"
 	"github.com/devtron-labs/devtron/api/server"
 	"github.com/devtron-labs/devtron/api/sse"
 	"github.com/devtron-labs/devtron/api/sso"
@@ -113,6 +120,13 @@ func InitializeApp() (*App, error) {
 		sql.PgSqlWireSet,
 		user.SelfRegistrationWireSet,
 		externalLink.ExternalLinkWireSet,
+		deploymentWindow.DeploymentWindowWireSet,
+		deploymentApproval.DeploymentApprovalWireSet,
+		sbom.SbomWireSet,
+		imageSignature.ImageSignatureWireSet,
+		manifestScan.ManifestScanWireSet,
+		secretScan.SecretScanWireSet,
+		deploymentSchedule.DeploymentScheduleWireSet,
 		team.TeamsWireSet,
 		AuthWireSet,
 		user.UserWireSet,
@@ -260,6 +274,8 @@ func InitializeApp() (*App, error) {
 
 		eClient.NewEventRESTClientImpl,
 		wire.Bind(new(eClient.EventClient), new(*eClient.EventRESTClientImpl)),
+		eClient.NewNotificationInboxWriterImpl,
+		wire.Bind(new(eClient.NotificationInboxWriter), new(*eClient.NotificationInboxWriterImpl)),
 
 		util3.NewTokenCache,
 
@@ -346,6 +362,8 @@ func InitializeApp() (*App, error) {
 		wire.Bind(new(router.NotificationRouter), new(*router.NotificationRouterImpl)),
 		restHandler.NewNotificationRestHandlerImpl,
 		wire.Bind(new(restHandler.NotificationRestHandler), new(*restHandler.NotificationRestHandlerImpl)),
+		restHandler.NewNotificationInboxRestHandlerImpl,
+		wire.Bind(new(restHandler.NotificationInboxRestHandler), new(*restHandler.NotificationInboxRestHandlerImpl)),
 
 		notifier.NewSlackNotificationServiceImpl,
 		wire.Bind(new(notifier.SlackNotificationService), new(*notifier.SlackNotificationServiceImpl)),
@@ -399,6 +417,9 @@ func InitializeApp() (*App, error) {
 		pubsub.NewCiEventHandlerImpl,
 		wire.Bind(new(pubsub.CiEventHandler), new(*pubsub.CiEventHandlerImpl)),
 
+		pubsub.NewSecretScanEventHandlerImpl,
+		wire.Bind(new(pubsub.SecretScanEventHandler), new(*pubsub.SecretScanEventHandlerImpl)),
+
 		rbac.NewEnforcerUtilImpl,
 		wire.Bind(new(rbac.EnforcerUtil), new(*rbac.EnforcerUtilImpl)),
 
@@ -436,6 +457,25 @@ func InitializeApp() (*App, error) {
 		repository.NewSMTPNotificationRepositoryImpl,
 		wire.Bind(new(repository.SMTPNotificationRepository), new(*repository.SMTPNotificationRepositoryImpl)),
 
+		notifier.NewWebhookNotificationServiceImpl,
+		wire.Bind(new(notifier.WebhookNotificationService), new(*notifier.WebhookNotificationServiceImpl)),
+
+		repository.NewWebhookNotificationRepositoryImpl,
+		wire.Bind(new(repository.WebhookNotificationRepository), new(*repository.WebhookNotificationRepositoryImpl)),
+
+		notifier.NewNotificationDeliveryServiceImpl,
+		wire.Bind(new(notifier.NotificationDeliveryService), new(*notifier.NotificationDeliveryServiceImpl)),
+
+		repository.NewNotificationOutboxRepositoryImpl,
+		wire.Bind(new(repository.NotificationOutboxRepository), new(*repository.NotificationOutboxRepositoryImpl)),
+		repository.NewNotificationBatchRepositoryImpl,
+		wire.Bind(new(repository.NotificationBatchRepository), new(*repository.NotificationBatchRepositoryImpl)),
+
+		notifier.NewNotificationInboxServiceImpl,
+		wire.Bind(new(notifier.NotificationInboxService), new(*notifier.NotificationInboxServiceImpl)),
+		repository.NewNotificationInboxRepositoryImpl,
+		wire.Bind(new(repository.NotificationInboxRepository), new(*repository.NotificationInboxRepositoryImpl)),
+
 		notifier.NewNotificationConfigBuilderImpl,
 		wire.Bind(new(notifier.NotificationConfigBuilder), new(*notifier.NotificationConfigBuilderImpl)),
 
@@ -489,6 +529,14 @@ func InitializeApp() (*App, error) {
 
 		pipeline.NewWorkflowDagExecutorImpl,
 		wire.Bind(new(pipeline.WorkflowDagExecutor), new(*pipeline.WorkflowDagExecutorImpl)),
+		repository5.NewAutoRollbackRepositoryImpl,
+		wire.Bind(new(repository5.AutoRollbackRepository), new(*repository5.AutoRollbackRepositoryImpl)),
+		pipeline.NewAutoRollbackServiceImpl,
+		wire.Bind(new(pipeline.AutoRollbackService), new(*pipeline.AutoRollbackServiceImpl)),
+		repository5.NewCanaryAnalysisRepositoryImpl,
+		wire.Bind(new(repository5.CanaryAnalysisRepository), new(*repository5.CanaryAnalysisRepositoryImpl)),
+		pipeline.NewCanaryAnalysisServiceImpl,
+		wire.Bind(new(pipeline.CanaryAnalysisService), new(*pipeline.CanaryAnalysisServiceImpl)),
 		appClone.NewAppCloneServiceImpl,
 		wire.Bind(new(appClone.AppCloneService), new(*appClone.AppCloneServiceImpl)),
 		pipeline.GetCdConfig,
@@ -562,6 +610,8 @@ func InitializeApp() (*App, error) {
 		wire.Bind(new(security2.CveStoreRepository), new(*security2.CveStoreRepositoryImpl)),
 		security2.NewImageScanDeployInfoRepositoryImpl,
 		wire.Bind(new(security2.ImageScanDeployInfoRepository), new(*security2.ImageScanDeployInfoRepositoryImpl)),
+		security2.NewSecurityPostureRepositoryImpl,
+		wire.Bind(new(security2.SecurityPostureRepository), new(*security2.SecurityPostureRepositoryImpl)),
 		router.NewPolicyRouterImpl,
 		wire.Bind(new(router.PolicyRouter), new(*router.PolicyRouterImpl)),
 		restHandler.NewPolicyRestHandlerImpl,
@@ -570,6 +620,12 @@ func InitializeApp() (*App, error) {
 		wire.Bind(new(security.PolicyService), new(*security.PolicyServiceImpl)),
 		security2.NewPolicyRepositoryImpl,
 		wire.Bind(new(security2.CvePolicyRepository), new(*security2.CvePolicyRepositoryImpl)),
+		security2.NewCvePolicyRuleRepositoryImpl,
+		wire.Bind(new(security2.CvePolicyRuleRepository), new(*security2.CvePolicyRuleRepositoryImpl)),
+		security2.NewLicensePolicyRepositoryImpl,
+		wire.Bind(new(security2.LicensePolicyRepository), new(*security2.LicensePolicyRepositoryImpl)),
+		security2.NewManifestScanPolicyRepositoryImpl,
+		wire.Bind(new(security2.ManifestScanPolicyRepository), new(*security2.ManifestScanPolicyRepositoryImpl)),
 
 		argocdServer.NewArgoK8sClientImpl,
 		wire.Bind(new(argocdServer.ArgoK8sClient), new(*argocdServer.ArgoK8sClientImpl)),
@@ -722,6 +778,31 @@ func InitializeApp() (*App, error) {
 		cron.GetAppStatusConfig,
 		cron.NewCdApplicationStatusUpdateHandlerImpl,
 		wire.Bind(new(cron.CdApplicationStatusUpdateHandler), new(*cron.CdApplicationStatusUpdateHandlerImpl)),
+		cron.GetDeploymentQueueConfig,
+		cron.NewDeploymentQueueHandlerImpl,
+		wire.Bind(new(cron.DeploymentQueueHandler), new(*cron.DeploymentQueueHandlerImpl)),
+		cron.GetCiScheduleConfig,
+		cron.NewCiScheduleHandlerImpl,
+		wire.Bind(new(cron.CiScheduleHandler), new(*cron.CiScheduleHandlerImpl)),
+		cron.GetCanaryAnalysisConfig,
+		cron.NewCanaryAnalysisHandlerImpl,
+		wire.Bind(new(cron.CanaryAnalysisHandler), new(*cron.CanaryAnalysisHandlerImpl)),
+		cron.GetCveExceptionExpiryConfig,
+		cron.NewCveExceptionExpiryHandlerImpl,
+		wire.Bind(new(cron.CveExceptionExpiryHandler), new(*cron.CveExceptionExpiryHandlerImpl)),
+		cron.GetImageRescanConfig,
+		cron.NewImageRescanHandlerImpl,
+		wire.Bind(new(cron.ImageRescanHandler), new(*cron.ImageRescanHandlerImpl)),
+		cron.GetSecurityPostureConfig,
+		cron.NewSecurityPostureHandlerImpl,
+		wire.Bind(new(cron.SecurityPostureHandler), new(*cron.SecurityPostureHandlerImpl)),
+		cron.GetNotificationOutboxConfig,
+		cron.NewNotificationOutboxHandlerImpl,
+		wire.Bind(new(cron.NotificationOutboxHandler), new(*cron.NotificationOutboxHandlerImpl)),
+
+		cron.GetClusterUnreachableConfig,
+		cron.NewClusterUnreachableHandlerImpl,
+		wire.Bind(new(cron.ClusterUnreachableHandler), new(*cron.ClusterUnreachableHandlerImpl)),
 
 		restHandler.NewPipelineStatusTimelineRestHandlerImpl,
 		wire.Bind(new(restHandler.PipelineStatusTimelineRestHandler), new(*restHandler.PipelineStatusTimelineRestHandlerImpl)),
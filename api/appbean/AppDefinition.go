@@ -0,0 +1,167 @@
+package appbean
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+const (
+	AppDefinitionApiVersion = "app/v1beta1"
+	AppDefinitionKind       = "Application"
+)
+
+type AppDefinitionChangeOperation string
+
+const (
+	APP_DEFINITION_CHANGE_ADD    AppDefinitionChangeOperation = "ADD"
+	APP_DEFINITION_CHANGE_UPDATE AppDefinitionChangeOperation = "UPDATE"
+)
+
+// AppDefinition is the declarative, versioned form of an app's complete setup used for export and apply
+type AppDefinition struct {
+	ApiVersion string     `json:"apiVersion" validate:"required"`
+	Kind       string     `json:"kind" validate:"required"`
+	Spec       *AppDetail `json:"spec" validate:"required"`
+}
+
+type AppDefinitionChange struct {
+	Path      string                       `json:"path"`
+	Operation AppDefinitionChangeOperation `json:"operation"`
+	Current   interface{}                  `json:"current,omitempty"`
+	Desired   interface{}                  `json:"desired,omitempty"`
+}
+
+type AppDefinitionApplyResponse struct {
+	AppId      int                    `json:"appId,omitempty"`
+	AppName    string                 `json:"appName"`
+	DryRun     bool                   `json:"dryRun"`
+	AppCreated bool                   `json:"appCreated"`
+	Changes    []*AppDefinitionChange `json:"changes"`
+}
+
+// identity keys of list items, list items having one of these are matched by it instead of comparing whole lists
+var appDefinitionIdentityKeys = []string{"name", "checkoutPath", "environmentName"}
+
+// DiffAppDefinition lists the changes needed to move current to desired. Only fields present in desired are compared,
+// anything missing from desired is left as it is on apply
+func DiffAppDefinition(path string, current interface{}, desired interface{}) ([]*AppDefinitionChange, error) {
+	currentObj, err := toGenericObject(current)
+	if err != nil {
+		return nil, err
+	}
+	desiredObj, err := toGenericObject(desired)
+	if err != nil {
+		return nil, err
+	}
+	var changes []*AppDefinitionChange
+	diffGenericObjects(path, currentObj, desiredObj, &changes)
+	return changes, nil
+}
+
+// IsAppDefinitionChanged tells if applying desired over current would change anything
+func IsAppDefinitionChanged(current interface{}, desired interface{}) (bool, error) {
+	changes, err := DiffAppDefinition("", current, desired)
+	if err != nil {
+		return false, err
+	}
+	return len(changes) > 0, nil
+}
+
+func toGenericObject(obj interface{}) (interface{}, error) {
+	if obj == nil || (reflect.ValueOf(obj).Kind() == reflect.Ptr && reflect.ValueOf(obj).IsNil()) {
+		return nil, nil
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var genericObj interface{}
+	err = json.Unmarshal(data, &genericObj)
+	return genericObj, err
+}
+
+func diffGenericObjects(path string, current interface{}, desired interface{}, changes *[]*AppDefinitionChange) {
+	if desired == nil {
+		return
+	}
+	if current == nil {
+		*changes = append(*changes, &AppDefinitionChange{Path: path, Operation: APP_DEFINITION_CHANGE_ADD, Desired: desired})
+		return
+	}
+	switch desiredValue := desired.(type) {
+	case map[string]interface{}:
+		currentValue, ok := current.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(desiredValue))
+		for key := range desiredValue {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			diffGenericObjects(joinAppDefinitionPath(path, key), currentValue[key], desiredValue[key], changes)
+		}
+		return
+	case []interface{}:
+		currentValue, ok := current.([]interface{})
+		if !ok {
+			break
+		}
+		identityKey := getListIdentityKey(desiredValue)
+		if len(identityKey) == 0 {
+			break
+		}
+		for _, desiredItem := range desiredValue {
+			identity := desiredItem.(map[string]interface{})[identityKey]
+			itemPath := fmt.Sprintf("%s[%s=%v]", path, identityKey, identity)
+			diffGenericObjects(itemPath, findListItemByIdentity(currentValue, identityKey, identity), desiredItem, changes)
+		}
+		return
+	}
+	if !reflect.DeepEqual(current, desired) {
+		*changes = append(*changes, &AppDefinitionChange{Path: path, Operation: APP_DEFINITION_CHANGE_UPDATE, Current: current, Desired: desired})
+	}
+}
+
+// getListIdentityKey returns the identity key shared by all items of the list, empty if there is none
+func getListIdentityKey(list []interface{}) string {
+	if len(list) == 0 {
+		return ""
+	}
+	for _, key := range appDefinitionIdentityKeys {
+		sharedByAll := true
+		for _, item := range list {
+			itemObj, ok := item.(map[string]interface{})
+			if !ok {
+				return ""
+			}
+			if value, ok := itemObj[key]; !ok || value == nil || value == "" {
+				sharedByAll = false
+				break
+			}
+		}
+		if sharedByAll {
+			return key
+		}
+	}
+	return ""
+}
+
+func findListItemByIdentity(list []interface{}, identityKey string, identity interface{}) interface{} {
+	for _, item := range list {
+		if itemObj, ok := item.(map[string]interface{}); ok && reflect.DeepEqual(itemObj[identityKey], identity) {
+			return item
+		}
+	}
+	return nil
+}
+
+func joinAppDefinitionPath(path string, key string) string {
+	if len(path) == 0 {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,99 @@
+package appbean
+
+import (
+	"testing"
+)
+
+func TestDiffAppDefinition(t *testing.T) {
+	current := &AppDetail{
+		Metadata: &AppMetadata{AppName: "demo", ProjectName: "default"},
+		GlobalConfigMaps: []*ConfigMap{
+			{Name: "app-cm", UsageType: "environment", Data: map[string]interface{}{"LOG_LEVEL": "info"}},
+			{Name: "feature-cm", UsageType: "environment", Data: map[string]interface{}{"NEW_UI": "false"}},
+		},
+		AppWorkflows: []*AppWorkflow{
+			{Name: "build", CdPipelines: []*CdPipelineDetails{{Name: "dev", EnvironmentName: "dev", TriggerType: "AUTOMATIC"}}},
+		},
+	}
+	tests := []struct {
+		name        string
+		desired     *AppDetail
+		wantPaths   []string
+		wantChanges int
+	}{
+		{
+			name:        "same definition",
+			desired:     current,
+			wantChanges: 0,
+		},
+		{
+			name: "missing sections are not managed",
+			desired: &AppDetail{
+				Metadata: &AppMetadata{AppName: "demo", ProjectName: "default"},
+			},
+			wantChanges: 0,
+		},
+		{
+			name: "config map value updated and config map reordered",
+			desired: &AppDetail{
+				GlobalConfigMaps: []*ConfigMap{
+					{Name: "feature-cm", UsageType: "environment", Data: map[string]interface{}{"NEW_UI": "false"}},
+					{Name: "app-cm", UsageType: "environment", Data: map[string]interface{}{"LOG_LEVEL": "debug"}},
+				},
+			},
+			wantPaths:   []string{"globalConfigMaps[name=app-cm].data.LOG_LEVEL"},
+			wantChanges: 1,
+		},
+		{
+			name: "cd pipeline added to workflow",
+			desired: &AppDetail{
+				AppWorkflows: []*AppWorkflow{
+					{Name: "build", CdPipelines: []*CdPipelineDetails{
+						{Name: "dev", EnvironmentName: "dev", TriggerType: "AUTOMATIC"},
+						{Name: "qa", EnvironmentName: "qa", TriggerType: "MANUAL"},
+					}},
+				},
+			},
+			wantPaths:   []string{"workflows[name=build].cdPipelines[name=qa]"},
+			wantChanges: 1,
+		},
+		{
+			name: "project changed",
+			desired: &AppDetail{
+				Metadata: &AppMetadata{AppName: "demo", ProjectName: "payments"},
+			},
+			wantPaths:   []string{"metadata.projectName"},
+			wantChanges: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := DiffAppDefinition("", current, tt.desired)
+			if err != nil {
+				t.Errorf("DiffAppDefinition() error = %v", err)
+				return
+			}
+			if len(changes) != tt.wantChanges {
+				t.Errorf("DiffAppDefinition() got %d changes, want %d, changes %v", len(changes), tt.wantChanges, changes)
+				return
+			}
+			for i, path := range tt.wantPaths {
+				if changes[i].Path != path {
+					t.Errorf("DiffAppDefinition() got path %s, want %s", changes[i].Path, path)
+				}
+			}
+		})
+	}
+}
+
+func TestDiffAppDefinitionForNewApp(t *testing.T) {
+	desired := &AppDetail{Metadata: &AppMetadata{AppName: "demo", ProjectName: "default"}}
+	changes, err := DiffAppDefinition("spec", nil, desired)
+	if err != nil {
+		t.Errorf("DiffAppDefinition() error = %v", err)
+		return
+	}
+	if len(changes) != 1 || changes[0].Operation != APP_DEFINITION_CHANGE_ADD || changes[0].Path != "spec" {
+		t.Errorf("DiffAppDefinition() expected single ADD change for new app, got %v", changes)
+	}
+}
@@ -17,6 +17,8 @@
 
 package bean
 
+import "time"
+
 // CreateVulnerabilityPolicyRequest defines model for CreateVulnerabilityPolicyRequest.
 type CreateVulnerabilityPolicyRequest struct {
 	// actions which can be taken on vulnerabilities
@@ -26,6 +28,20 @@ type CreateVulnerabilityPolicyRequest struct {
 	CveId     string               `json:"cveId,omitempty"`
 	EnvId     int                  `json:"envId,omitempty"`
 	Severity  string               `json:"severity,omitempty"`
+	// makes an allow policy time bound
+	Exception *VulnerabilityPolicyException `json:"exception,omitempty"`
+}
+
+// VulnerabilityPolicyException defines model for VulnerabilityPolicyException.
+type VulnerabilityPolicyException struct {
+	// why the vulnerability is allowed
+	Justification string `json:"justification"`
+
+	// email of the person accountable for the exception
+	Owner string `json:"owner"`
+
+	// exception is ignored after expiry
+	ExpiresOn time.Time `json:"expiresOn"`
 }
 
 // CreateVulnerabilityPolicyResponse defines model for CreateVulnerabilityPolicyResponse.
@@ -91,6 +107,9 @@ type SeverityPolicy struct {
 	Policy       *VulnerabilityPermission `json:"policy"`
 	PolicyOrigin string                   `json:"policyOrigin"`
 	Severity     string                   `json:"severity"`
+
+	// set in case policy is a time bound exception
+	Exception *VulnerabilityPolicyException `json:"exception,omitempty"`
 }
 
 // UpdateVulnerabilityPolicyResponse defines model for UpdateVulnerabilityPolicyResponse.
@@ -121,8 +140,77 @@ type VulnerabilityPolicy struct {
 	// Is name of cluster or environment or application/environment
 	Name       string            `json:"name,omitempty"`
 	Severities []*SeverityPolicy `json:"severities"`
-	AppId      int               `json:"-"`
-	ClusterId  int               `json:"-"`
+	// rules applicable along with the level they are inherited from
+	Rules []*VulnerabilityPolicyRule `json:"rules"`
+	// license policies applicable along with the level they are inherited from
+	Licenses []*LicensePolicy `json:"licenses"`
+	// manifest scan policies applicable along with the level they are inherited from
+	ManifestScanPolicies []*ManifestScanPolicy `json:"manifestScanPolicies"`
+	AppId                int                   `json:"-"`
+	ClusterId            int                   `json:"-"`
+}
+
+// VulnerabilityPolicyRuleType defines model for VulnerabilityPolicyRuleType.
+type VulnerabilityPolicyRuleType string
+
+const (
+	VulnerabilityPolicyRuleFixAvailable VulnerabilityPolicyRuleType = "FIX_AVAILABLE"
+	VulnerabilityPolicyRulePackage      VulnerabilityPolicyRuleType = "PACKAGE"
+	VulnerabilityPolicyRuleThreshold    VulnerabilityPolicyRuleType = "THRESHOLD"
+)
+
+// VulnerabilityPolicyRule defines model for VulnerabilityPolicyRule.
+type VulnerabilityPolicyRule struct {
+	Id        int `json:"id,omitempty"`
+	ClusterId int `json:"clusterId,omitempty"`
+	EnvId     int `json:"envId,omitempty"`
+	AppId     int `json:"appId,omitempty"`
+
+	// FIX_AVAILABLE blocks a severity only when a fixed version exists, PACKAGE allows or blocks findings of a
+	// package, THRESHOLD blocks a severity when findings exceed the threshold
+	RuleType VulnerabilityPolicyRuleType `json:"ruleType"`
+	Action   VulnerabilityAction         `json:"action"`
+
+	// required for FIX_AVAILABLE and THRESHOLD, optional for PACKAGE
+	Severity  string `json:"severity,omitempty"`
+	Package   string `json:"package,omitempty"`
+	Threshold int    `json:"threshold,omitempty"`
+
+	PolicyOrigin string `json:"policyOrigin,omitempty"`
+	Inherited    bool   `json:"inherited"`
+}
+
+// LicensePolicy defines model for LicensePolicy.
+type LicensePolicy struct {
+	Id        int `json:"id,omitempty"`
+	ClusterId int `json:"clusterId,omitempty"`
+	EnvId     int `json:"envId,omitempty"`
+	AppId     int `json:"appId,omitempty"`
+
+	// spdx license identifier like AGPL-3.0-only, * applies to every license without a policy of its own
+	License string              `json:"license"`
+	Action  VulnerabilityAction `json:"action"`
+
+	PolicyOrigin string `json:"policyOrigin,omitempty"`
+	Inherited    bool   `json:"inherited"`
+}
+
+// ManifestScanPolicy defines model for ManifestScanPolicy.
+type ManifestScanPolicy struct {
+	Id        int `json:"id,omitempty"`
+	ClusterId int `json:"clusterId,omitempty"`
+	EnvId     int `json:"envId,omitempty"`
+	AppId     int `json:"appId,omitempty"`
+
+	// built-in manifest scan rule like PRIVILEGED_CONTAINER, * applies to every rule without a policy of its own
+	RuleId string `json:"ruleId"`
+	// warn only reports findings of the rule, block fails the deployment
+	Action VulnerabilityAction `json:"action"`
+	// a block policy fails the deployment when manifests can not be rendered or scanned unless failOpen is set
+	FailOpen bool `json:"failOpen"`
+
+	PolicyOrigin string `json:"policyOrigin,omitempty"`
+	Inherited    bool   `json:"inherited"`
 }
 
 // DeletePolicyParams defines parameters for DeletePolicy.
@@ -138,6 +226,7 @@ type FetchPolicyParams struct {
 
 // UpdatePolicyParams defines parameters for UpdatePolicy.
 type UpdatePolicyParams struct {
-	Id     int    `json:"id"`
-	Action string `json:"action"`
+	Id        int                           `json:"id"`
+	Action    string                        `json:"action"`
+	Exception *VulnerabilityPolicyException `json:"exception,omitempty"`
 }
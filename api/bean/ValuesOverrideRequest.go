@@ -43,6 +43,8 @@ type ValuesOverrideRequest struct {
 	CdWorkflowId       int                   `json:"cdWorkflowId"`
 	UserId             int32                 `json:"-"`
 	DeploymentType     models.DeploymentType `json:"-"`
+	// CdWorkflowRunnerId is set by the trigger to the deploy runner once the release is triggered
+	CdWorkflowRunnerId int                   `json:"-"`
 }
 
 type ReleaseStatusUpdateRequest struct {
@@ -0,0 +1,315 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package deploymentApproval
+
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/deploymentApproval"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/juju/errors"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+)
+
+type DeploymentApprovalRestHandler interface {
+	GetApprovalPolicy(w http.ResponseWriter, r *http.Request)
+	SaveApprovalPolicy(w http.ResponseWriter, r *http.Request)
+	DeleteApprovalPolicy(w http.ResponseWriter, r *http.Request)
+	RequestApproval(w http.ResponseWriter, r *http.Request)
+	ApproveRequest(w http.ResponseWriter, r *http.Request)
+	RejectRequest(w http.ResponseWriter, r *http.Request)
+	GetApprovalRequests(w http.ResponseWriter, r *http.Request)
+}
+
+type DeploymentApprovalRestHandlerImpl struct {
+	logger                    *zap.SugaredLogger
+	deploymentApprovalService deploymentApproval.DeploymentApprovalService
+	workflowDagExecutor       pipeline.WorkflowDagExecutor
+	userService               user.UserService
+	enforcer                  casbin.Enforcer
+	enforcerUtil              rbac.EnforcerUtil
+	validator                 *validator.Validate
+}
+
+func NewDeploymentApprovalRestHandlerImpl(logger *zap.SugaredLogger,
+	deploymentApprovalService deploymentApproval.DeploymentApprovalService,
+	workflowDagExecutor pipeline.WorkflowDagExecutor,
+	userService user.UserService,
+	enforcer casbin.Enforcer,
+	enforcerUtil rbac.EnforcerUtil,
+	validator *validator.Validate,
+) *DeploymentApprovalRestHandlerImpl {
+	return &DeploymentApprovalRestHandlerImpl{
+		logger:                    logger,
+		deploymentApprovalService: deploymentApprovalService,
+		workflowDagExecutor:       workflowDagExecutor,
+		userService:               userService,
+		enforcer:                  enforcer,
+		enforcerUtil:              enforcerUtil,
+		validator:                 validator,
+	}
+}
+
+func (impl DeploymentApprovalRestHandlerImpl) GetApprovalPolicy(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(r.URL.Query().Get("pipelineId"))
+	if err != nil {
+		impl.logger.Errorw("request err, GetApprovalPolicy", "err", err, "pipelineId", r.URL.Query().Get("pipelineId"))
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	token := r.Header.Get("token")
+	if ok := impl.enforcePipeline(token, pipelineId, casbin.ActionGet); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+
+	res, err := impl.deploymentApprovalService.GetPolicy(pipelineId)
+	if err != nil {
+		impl.logger.Errorw("service err, GetApprovalPolicy", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl DeploymentApprovalRestHandlerImpl) SaveApprovalPolicy(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	decoder := json.NewDecoder(r.Body)
+	var bean deploymentApproval.ApprovalPolicyDto
+	err = decoder.Decode(&bean)
+	if err != nil {
+		impl.logger.Errorw("request err, SaveApprovalPolicy", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = impl.validator.Struct(bean)
+	if err != nil {
+		impl.logger.Errorw("validation err, SaveApprovalPolicy", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	token := r.Header.Get("token")
+	if ok := impl.enforcePipeline(token, bean.PipelineId, casbin.ActionUpdate); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+
+	res, err := impl.deploymentApprovalService.SavePolicy(&bean, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, SaveApprovalPolicy", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl DeploymentApprovalRestHandlerImpl) DeleteApprovalPolicy(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(r.URL.Query().Get("pipelineId"))
+	if err != nil {
+		impl.logger.Errorw("request err, DeleteApprovalPolicy", "err", err, "pipelineId", r.URL.Query().Get("pipelineId"))
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	token := r.Header.Get("token")
+	if ok := impl.enforcePipeline(token, pipelineId, casbin.ActionUpdate); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+
+	err = impl.deploymentApprovalService.DeletePolicy(pipelineId, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, DeleteApprovalPolicy", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, pipelineId, http.StatusOK)
+}
+
+func (impl DeploymentApprovalRestHandlerImpl) RequestApproval(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	decoder := json.NewDecoder(r.Body)
+	var bean deploymentApproval.ApprovalRequestDto
+	err = decoder.Decode(&bean)
+	if err != nil {
+		impl.logger.Errorw("request err, RequestApproval", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = impl.validator.Struct(bean)
+	if err != nil {
+		impl.logger.Errorw("validation err, RequestApproval", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	token := r.Header.Get("token")
+	if ok := impl.enforcePipeline(token, bean.PipelineId, casbin.ActionTrigger); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+
+	res, err := impl.deploymentApprovalService.RequestApproval(bean.PipelineId, bean.CiArtifactId, userId, false, 0)
+	if err != nil {
+		impl.logger.Errorw("service err, RequestApproval", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl DeploymentApprovalRestHandlerImpl) ApproveRequest(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	decoder := json.NewDecoder(r.Body)
+	var bean deploymentApproval.ApprovalActionDto
+	err = decoder.Decode(&bean)
+	if err != nil {
+		impl.logger.Errorw("request err, ApproveRequest", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = impl.validator.Struct(bean)
+	if err != nil {
+		impl.logger.Errorw("validation err, ApproveRequest", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	// approvers are governed by approval policy of the pipeline, checked in service
+
+	res, err := impl.deploymentApprovalService.ApproveRequest(&bean, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, ApproveRequest", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if res.Status == deploymentApproval.APPROVAL_STATUS_APPROVED && res.AutoTrigger {
+		//approval is saved already, failure of held deployment does not fail the approval
+		triggerErr := impl.workflowDagExecutor.TriggerDeploymentOnApproval(res, userId)
+		if triggerErr != nil {
+			impl.logger.Errorw("error in triggering deployment on approval", "err", triggerErr, "approvalRequest", res)
+		}
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl DeploymentApprovalRestHandlerImpl) RejectRequest(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	decoder := json.NewDecoder(r.Body)
+	var bean deploymentApproval.ApprovalActionDto
+	err = decoder.Decode(&bean)
+	if err != nil {
+		impl.logger.Errorw("request err, RejectRequest", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = impl.validator.Struct(bean)
+	if err != nil {
+		impl.logger.Errorw("validation err, RejectRequest", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	// approvers are governed by approval policy of the pipeline, checked in service
+
+	res, err := impl.deploymentApprovalService.RejectRequest(&bean, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, RejectRequest", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl DeploymentApprovalRestHandlerImpl) GetApprovalRequests(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(r.URL.Query().Get("pipelineId"))
+	if err != nil {
+		impl.logger.Errorw("request err, GetApprovalRequests", "err", err, "pipelineId", r.URL.Query().Get("pipelineId"))
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	ciArtifactId := 0
+	if ciArtifactIdParam := r.URL.Query().Get("ciArtifactId"); len(ciArtifactIdParam) > 0 {
+		ciArtifactId, err = strconv.Atoi(ciArtifactIdParam)
+		if err != nil {
+			impl.logger.Errorw("request err, GetApprovalRequests", "err", err, "ciArtifactId", ciArtifactIdParam)
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+
+	token := r.Header.Get("token")
+	if ok := impl.enforcePipeline(token, pipelineId, casbin.ActionGet); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+
+	res, err := impl.deploymentApprovalService.GetApprovalRequests(pipelineId, ciArtifactId)
+	if err != nil {
+		impl.logger.Errorw("service err, GetApprovalRequests", "err", err, "pipelineId", pipelineId, "ciArtifactId", ciArtifactId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl DeploymentApprovalRestHandlerImpl) enforcePipeline(token string, pipelineId int, action string) bool {
+	appObject, envObject := impl.enforcerUtil.GetTeamAndEnvironmentRbacObjectByCDPipelineId(pipelineId)
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceApplications, action, appObject); !ok {
+		return false
+	}
+	return impl.enforcer.Enforce(token, casbin.ResourceEnvironment, action, envObject)
+}
@@ -0,0 +1,27 @@
+package deploymentApproval
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type DeploymentApprovalRouter interface {
+	InitDeploymentApprovalRouter(router *mux.Router)
+}
+
+type DeploymentApprovalRouterImpl struct {
+	deploymentApprovalRestHandler DeploymentApprovalRestHandler
+}
+
+func NewDeploymentApprovalRouterImpl(deploymentApprovalRestHandler DeploymentApprovalRestHandler) *DeploymentApprovalRouterImpl {
+	return &DeploymentApprovalRouterImpl{deploymentApprovalRestHandler: deploymentApprovalRestHandler}
+}
+
+func (impl DeploymentApprovalRouterImpl) InitDeploymentApprovalRouter(router *mux.Router) {
+	router.Path("/policy").HandlerFunc(impl.deploymentApprovalRestHandler.GetApprovalPolicy).Queries("pipelineId", "{pipelineId}").Methods("GET")
+	router.Path("/policy").HandlerFunc(impl.deploymentApprovalRestHandler.SaveApprovalPolicy).Methods("PUT")
+	router.Path("/policy").HandlerFunc(impl.deploymentApprovalRestHandler.DeleteApprovalPolicy).Queries("pipelineId", "{pipelineId}").Methods("DELETE")
+	router.Path("/request").HandlerFunc(impl.deploymentApprovalRestHandler.RequestApproval).Methods("POST")
+	router.Path("/request").HandlerFunc(impl.deploymentApprovalRestHandler.GetApprovalRequests).Queries("pipelineId", "{pipelineId}").Methods("GET")
+	router.Path("/approve").HandlerFunc(impl.deploymentApprovalRestHandler.ApproveRequest).Methods("PUT")
+	router.Path("/reject").HandlerFunc(impl.deploymentApprovalRestHandler.RejectRequest).Methods("PUT")
+}
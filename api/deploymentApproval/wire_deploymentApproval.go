@@ -0,0 +1,20 @@
+package deploymentApproval
+
+import (
+	"github.com/devtron-labs/devtron/pkg/deploymentApproval"
+	"github.com/google/wire"
+)
+
+var DeploymentApprovalWireSet = wire.NewSet(
+	deploymentApproval.NewDeploymentApprovalPolicyRepositoryImpl,
+	wire.Bind(new(deploymentApproval.DeploymentApprovalPolicyRepository), new(*deploymentApproval.DeploymentApprovalPolicyRepositoryImpl)),
+	deploymentApproval.NewDeploymentApprovalRepositoryImpl,
+	wire.Bind(new(deploymentApproval.DeploymentApprovalRepository), new(*deploymentApproval.DeploymentApprovalRepositoryImpl)),
+
+	deploymentApproval.NewDeploymentApprovalServiceImpl,
+	wire.Bind(new(deploymentApproval.DeploymentApprovalService), new(*deploymentApproval.DeploymentApprovalServiceImpl)),
+	NewDeploymentApprovalRestHandlerImpl,
+	wire.Bind(new(DeploymentApprovalRestHandler), new(*DeploymentApprovalRestHandlerImpl)),
+	NewDeploymentApprovalRouterImpl,
+	wire.Bind(new(DeploymentApprovalRouter), new(*DeploymentApprovalRouterImpl)),
+)
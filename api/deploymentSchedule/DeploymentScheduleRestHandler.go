@@ -0,0 +1,277 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package deploymentSchedule
+
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/deploymentSchedule"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/gorilla/mux"
+	"github.com/juju/errors"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+)
+
+type DeploymentScheduleRestHandler interface {
+	CreateDeploymentSchedule(w http.ResponseWriter, r *http.Request)
+	UpdateDeploymentSchedule(w http.ResponseWriter, r *http.Request)
+	DeleteDeploymentSchedule(w http.ResponseWriter, r *http.Request)
+	GetDeploymentScheduleById(w http.ResponseWriter, r *http.Request)
+	GetDeploymentSchedulesByPipelineId(w http.ResponseWriter, r *http.Request)
+	GetDeploymentSchedulesByDeploymentGroupId(w http.ResponseWriter, r *http.Request)
+	GetDeploymentScheduleRuns(w http.ResponseWriter, r *http.Request)
+}
+
+type DeploymentScheduleRestHandlerImpl struct {
+	logger                    *zap.SugaredLogger
+	deploymentScheduleService deploymentSchedule.DeploymentScheduleService
+	userService               user.UserService
+	enforcer                  casbin.Enforcer
+	validator                 *validator.Validate
+}
+
+func NewDeploymentScheduleRestHandlerImpl(logger *zap.SugaredLogger,
+	deploymentScheduleService deploymentSchedule.DeploymentScheduleService,
+	userService user.UserService,
+	enforcer casbin.Enforcer,
+	validator *validator.Validate,
+) *DeploymentScheduleRestHandlerImpl {
+	return &DeploymentScheduleRestHandlerImpl{
+		logger:                    logger,
+		deploymentScheduleService: deploymentScheduleService,
+		userService:               userService,
+		enforcer:                  enforcer,
+		validator:                 validator,
+	}
+}
+
+func (impl DeploymentScheduleRestHandlerImpl) CreateDeploymentSchedule(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	decoder := json.NewDecoder(r.Body)
+	var bean deploymentSchedule.DeploymentScheduleDto
+	err = decoder.Decode(&bean)
+	if err != nil {
+		impl.logger.Errorw("request err, CreateDeploymentSchedule", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = impl.validator.Struct(bean)
+	if err != nil {
+		impl.logger.Errorw("validation err, CreateDeploymentSchedule", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionCreate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+
+	res, err := impl.deploymentScheduleService.Create(&bean, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, CreateDeploymentSchedule", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl DeploymentScheduleRestHandlerImpl) UpdateDeploymentSchedule(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	decoder := json.NewDecoder(r.Body)
+	var bean deploymentSchedule.DeploymentScheduleDto
+	err = decoder.Decode(&bean)
+	if err != nil {
+		impl.logger.Errorw("request err, UpdateDeploymentSchedule", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = impl.validator.Struct(bean)
+	if err != nil {
+		impl.logger.Errorw("validation err, UpdateDeploymentSchedule", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+
+	res, err := impl.deploymentScheduleService.Update(&bean, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, UpdateDeploymentSchedule", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl DeploymentScheduleRestHandlerImpl) DeleteDeploymentSchedule(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		impl.logger.Errorw("request err, DeleteDeploymentSchedule", "err", err, "id", mux.Vars(r)["id"])
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionDelete, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+
+	err = impl.deploymentScheduleService.Delete(id, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, DeleteDeploymentSchedule", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, id, http.StatusOK)
+}
+
+func (impl DeploymentScheduleRestHandlerImpl) GetDeploymentScheduleById(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		impl.logger.Errorw("request err, GetDeploymentScheduleById", "err", err, "id", mux.Vars(r)["id"])
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+
+	res, err := impl.deploymentScheduleService.GetById(id)
+	if err != nil {
+		impl.logger.Errorw("service err, GetDeploymentScheduleById", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl DeploymentScheduleRestHandlerImpl) GetDeploymentSchedulesByPipelineId(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(r.URL.Query().Get("pipelineId"))
+	if err != nil {
+		impl.logger.Errorw("request err, GetDeploymentSchedulesByPipelineId", "err", err, "pipelineId", r.URL.Query().Get("pipelineId"))
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+
+	res, err := impl.deploymentScheduleService.GetByPipelineId(pipelineId)
+	if err != nil {
+		impl.logger.Errorw("service err, GetDeploymentSchedulesByPipelineId", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl DeploymentScheduleRestHandlerImpl) GetDeploymentSchedulesByDeploymentGroupId(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	deploymentGroupId, err := strconv.Atoi(r.URL.Query().Get("deploymentGroupId"))
+	if err != nil {
+		impl.logger.Errorw("request err, GetDeploymentSchedulesByDeploymentGroupId", "err", err, "deploymentGroupId", r.URL.Query().Get("deploymentGroupId"))
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+
+	res, err := impl.deploymentScheduleService.GetByDeploymentGroupId(deploymentGroupId)
+	if err != nil {
+		impl.logger.Errorw("service err, GetDeploymentSchedulesByDeploymentGroupId", "err", err, "deploymentGroupId", deploymentGroupId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl DeploymentScheduleRestHandlerImpl) GetDeploymentScheduleRuns(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		impl.logger.Errorw("request err, GetDeploymentScheduleRuns", "err", err, "id", mux.Vars(r)["id"])
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+
+	res, err := impl.deploymentScheduleService.GetRuns(id)
+	if err != nil {
+		impl.logger.Errorw("service err, GetDeploymentScheduleRuns", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
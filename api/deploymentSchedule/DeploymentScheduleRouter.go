@@ -0,0 +1,27 @@
+package deploymentSchedule
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type DeploymentScheduleRouter interface {
+	InitDeploymentScheduleRouter(router *mux.Router)
+}
+
+type DeploymentScheduleRouterImpl struct {
+	deploymentScheduleRestHandler DeploymentScheduleRestHandler
+}
+
+func NewDeploymentScheduleRouterImpl(deploymentScheduleRestHandler DeploymentScheduleRestHandler) *DeploymentScheduleRouterImpl {
+	return &DeploymentScheduleRouterImpl{deploymentScheduleRestHandler: deploymentScheduleRestHandler}
+}
+
+func (impl DeploymentScheduleRouterImpl) InitDeploymentScheduleRouter(router *mux.Router) {
+	router.Path("").HandlerFunc(impl.deploymentScheduleRestHandler.CreateDeploymentSchedule).Methods("POST")
+	router.Path("").HandlerFunc(impl.deploymentScheduleRestHandler.UpdateDeploymentSchedule).Methods("PUT")
+	router.Path("").HandlerFunc(impl.deploymentScheduleRestHandler.GetDeploymentSchedulesByPipelineId).Queries("pipelineId", "{pipelineId}").Methods("GET")
+	router.Path("").HandlerFunc(impl.deploymentScheduleRestHandler.GetDeploymentSchedulesByDeploymentGroupId).Queries("deploymentGroupId", "{deploymentGroupId}").Methods("GET")
+	router.Path("/{id}").HandlerFunc(impl.deploymentScheduleRestHandler.GetDeploymentScheduleById).Methods("GET")
+	router.Path("/{id}").HandlerFunc(impl.deploymentScheduleRestHandler.DeleteDeploymentSchedule).Methods("DELETE")
+	router.Path("/{id}/runs").HandlerFunc(impl.deploymentScheduleRestHandler.GetDeploymentScheduleRuns).Methods("GET")
+}
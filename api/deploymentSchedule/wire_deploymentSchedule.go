@@ -0,0 +1,23 @@
+package deploymentSchedule
+
+import (
+	"github.com/devtron-labs/devtron/pkg/deploymentSchedule"
+	"github.com/google/wire"
+)
+
+var DeploymentScheduleWireSet = wire.NewSet(
+	deploymentSchedule.NewDeploymentScheduleRepositoryImpl,
+	wire.Bind(new(deploymentSchedule.DeploymentScheduleRepository), new(*deploymentSchedule.DeploymentScheduleRepositoryImpl)),
+	deploymentSchedule.NewDeploymentScheduleRunRepositoryImpl,
+	wire.Bind(new(deploymentSchedule.DeploymentScheduleRunRepository), new(*deploymentSchedule.DeploymentScheduleRunRepositoryImpl)),
+
+	deploymentSchedule.NewDeploymentScheduleServiceImpl,
+	wire.Bind(new(deploymentSchedule.DeploymentScheduleService), new(*deploymentSchedule.DeploymentScheduleServiceImpl)),
+	deploymentSchedule.GetDeploymentScheduleCronConfig,
+	deploymentSchedule.NewDeploymentScheduleCronServiceImpl,
+	wire.Bind(new(deploymentSchedule.DeploymentScheduleCronService), new(*deploymentSchedule.DeploymentScheduleCronServiceImpl)),
+	NewDeploymentScheduleRestHandlerImpl,
+	wire.Bind(new(DeploymentScheduleRestHandler), new(*DeploymentScheduleRestHandlerImpl)),
+	NewDeploymentScheduleRouterImpl,
+	wire.Bind(new(DeploymentScheduleRouter), new(*DeploymentScheduleRouterImpl)),
+)
@@ -0,0 +1,245 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package deploymentWindow
+
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/deploymentWindow"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/gorilla/mux"
+	"github.com/juju/errors"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type DeploymentWindowRestHandler interface {
+	CreateDeploymentWindow(w http.ResponseWriter, r *http.Request)
+	UpdateDeploymentWindow(w http.ResponseWriter, r *http.Request)
+	DeleteDeploymentWindow(w http.ResponseWriter, r *http.Request)
+	GetDeploymentWindowById(w http.ResponseWriter, r *http.Request)
+	GetDeploymentWindowsByEnvId(w http.ResponseWriter, r *http.Request)
+	EvaluateDeploymentWindow(w http.ResponseWriter, r *http.Request)
+}
+
+type DeploymentWindowRestHandlerImpl struct {
+	logger                  *zap.SugaredLogger
+	deploymentWindowService deploymentWindow.DeploymentWindowService
+	userService             user.UserService
+	enforcer                casbin.Enforcer
+	validator               *validator.Validate
+}
+
+func NewDeploymentWindowRestHandlerImpl(logger *zap.SugaredLogger,
+	deploymentWindowService deploymentWindow.DeploymentWindowService,
+	userService user.UserService,
+	enforcer casbin.Enforcer,
+	validator *validator.Validate,
+) *DeploymentWindowRestHandlerImpl {
+	return &DeploymentWindowRestHandlerImpl{
+		logger:                  logger,
+		deploymentWindowService: deploymentWindowService,
+		userService:             userService,
+		enforcer:                enforcer,
+		validator:               validator,
+	}
+}
+
+func (impl DeploymentWindowRestHandlerImpl) CreateDeploymentWindow(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	decoder := json.NewDecoder(r.Body)
+	var bean deploymentWindow.DeploymentWindowDto
+	err = decoder.Decode(&bean)
+	if err != nil {
+		impl.logger.Errorw("request err, CreateDeploymentWindow", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = impl.validator.Struct(bean)
+	if err != nil {
+		impl.logger.Errorw("validation err, CreateDeploymentWindow", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionCreate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+
+	res, err := impl.deploymentWindowService.Create(&bean, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, CreateDeploymentWindow", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl DeploymentWindowRestHandlerImpl) UpdateDeploymentWindow(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	decoder := json.NewDecoder(r.Body)
+	var bean deploymentWindow.DeploymentWindowDto
+	err = decoder.Decode(&bean)
+	if err != nil {
+		impl.logger.Errorw("request err, UpdateDeploymentWindow", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = impl.validator.Struct(bean)
+	if err != nil {
+		impl.logger.Errorw("validation err, UpdateDeploymentWindow", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+
+	res, err := impl.deploymentWindowService.Update(&bean, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, UpdateDeploymentWindow", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl DeploymentWindowRestHandlerImpl) DeleteDeploymentWindow(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		impl.logger.Errorw("request err, DeleteDeploymentWindow", "err", err, "id", mux.Vars(r)["id"])
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionDelete, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+
+	err = impl.deploymentWindowService.Delete(id, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, DeleteDeploymentWindow", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, id, http.StatusOK)
+}
+
+func (impl DeploymentWindowRestHandlerImpl) GetDeploymentWindowById(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		impl.logger.Errorw("request err, GetDeploymentWindowById", "err", err, "id", mux.Vars(r)["id"])
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+
+	res, err := impl.deploymentWindowService.GetById(id)
+	if err != nil {
+		impl.logger.Errorw("service err, GetDeploymentWindowById", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl DeploymentWindowRestHandlerImpl) GetDeploymentWindowsByEnvId(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	envId, err := strconv.Atoi(r.URL.Query().Get("envId"))
+	if err != nil {
+		impl.logger.Errorw("request err, GetDeploymentWindowsByEnvId", "err", err, "envId", r.URL.Query().Get("envId"))
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+
+	res, err := impl.deploymentWindowService.GetByEnvironmentId(envId)
+	if err != nil {
+		impl.logger.Errorw("service err, GetDeploymentWindowsByEnvId", "err", err, "envId", envId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl DeploymentWindowRestHandlerImpl) EvaluateDeploymentWindow(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	envId, err := strconv.Atoi(r.URL.Query().Get("envId"))
+	if err != nil {
+		impl.logger.Errorw("request err, EvaluateDeploymentWindow", "err", err, "envId", r.URL.Query().Get("envId"))
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	// auth free api as deployment window state is shown on trigger page to all users
+
+	res, err := impl.deploymentWindowService.EvaluateDeploymentWindow(envId, time.Now())
+	if err != nil {
+		impl.logger.Errorw("service err, EvaluateDeploymentWindow", "err", err, "envId", envId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
@@ -0,0 +1,26 @@
+package deploymentWindow
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type DeploymentWindowRouter interface {
+	InitDeploymentWindowRouter(router *mux.Router)
+}
+
+type DeploymentWindowRouterImpl struct {
+	deploymentWindowRestHandler DeploymentWindowRestHandler
+}
+
+func NewDeploymentWindowRouterImpl(deploymentWindowRestHandler DeploymentWindowRestHandler) *DeploymentWindowRouterImpl {
+	return &DeploymentWindowRouterImpl{deploymentWindowRestHandler: deploymentWindowRestHandler}
+}
+
+func (impl DeploymentWindowRouterImpl) InitDeploymentWindowRouter(router *mux.Router) {
+	router.Path("").HandlerFunc(impl.deploymentWindowRestHandler.CreateDeploymentWindow).Methods("POST")
+	router.Path("").HandlerFunc(impl.deploymentWindowRestHandler.UpdateDeploymentWindow).Methods("PUT")
+	router.Path("").HandlerFunc(impl.deploymentWindowRestHandler.GetDeploymentWindowsByEnvId).Queries("envId", "{envId}").Methods("GET")
+	router.Path("/evaluate").HandlerFunc(impl.deploymentWindowRestHandler.EvaluateDeploymentWindow).Queries("envId", "{envId}").Methods("GET")
+	router.Path("/{id}").HandlerFunc(impl.deploymentWindowRestHandler.GetDeploymentWindowById).Methods("GET")
+	router.Path("/{id}").HandlerFunc(impl.deploymentWindowRestHandler.DeleteDeploymentWindow).Methods("DELETE")
+}
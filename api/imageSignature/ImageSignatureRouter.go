@@ -0,0 +1,25 @@
+package imageSignature
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type ImageSignatureRouter interface {
+	InitImageSignatureRouter(router *mux.Router)
+}
+
+type ImageSignatureRouterImpl struct {
+	imageSignatureRestHandler ImageSignatureRestHandler
+}
+
+func NewImageSignatureRouterImpl(imageSignatureRestHandler ImageSignatureRestHandler) *ImageSignatureRouterImpl {
+	return &ImageSignatureRouterImpl{imageSignatureRestHandler: imageSignatureRestHandler}
+}
+
+func (impl ImageSignatureRouterImpl) InitImageSignatureRouter(router *mux.Router) {
+	router.Path("/policy").HandlerFunc(impl.imageSignatureRestHandler.CreatePolicy).Methods("POST")
+	router.Path("/policy").HandlerFunc(impl.imageSignatureRestHandler.UpdatePolicy).Methods("PUT")
+	router.Path("/policy").HandlerFunc(impl.imageSignatureRestHandler.GetPolicies).Methods("GET")
+	router.Path("/policy/{id}").HandlerFunc(impl.imageSignatureRestHandler.GetPolicyById).Methods("GET")
+	router.Path("/policy/{id}").HandlerFunc(impl.imageSignatureRestHandler.DeletePolicy).Methods("DELETE")
+}
@@ -0,0 +1,21 @@
+package imageSignature
+
+import (
+	"github.com/devtron-labs/devtron/pkg/imageSignature"
+	"github.com/google/wire"
+)
+
+var ImageSignatureWireSet = wire.NewSet(
+	imageSignature.GetImageSignatureConfig,
+	imageSignature.NewImageSignaturePolicyRepositoryImpl,
+	wire.Bind(new(imageSignature.ImageSignaturePolicyRepository), new(*imageSignature.ImageSignaturePolicyRepositoryImpl)),
+	imageSignature.NewImageSignatureVerifierImpl,
+	wire.Bind(new(imageSignature.ImageSignatureVerifier), new(*imageSignature.ImageSignatureVerifierImpl)),
+
+	imageSignature.NewImageSignatureServiceImpl,
+	wire.Bind(new(imageSignature.ImageSignatureService), new(*imageSignature.ImageSignatureServiceImpl)),
+	NewImageSignatureRestHandlerImpl,
+	wire.Bind(new(ImageSignatureRestHandler), new(*ImageSignatureRestHandlerImpl)),
+	NewImageSignatureRouterImpl,
+	wire.Bind(new(ImageSignatureRouter), new(*ImageSignatureRouterImpl)),
+)
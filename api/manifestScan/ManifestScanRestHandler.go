@@ -0,0 +1,93 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package manifestScan
+
+import (
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/manifestScan"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+)
+
+type ManifestScanRestHandler interface {
+	GetScanResult(w http.ResponseWriter, r *http.Request)
+	GetRules(w http.ResponseWriter, r *http.Request)
+}
+
+type ManifestScanRestHandlerImpl struct {
+	logger              *zap.SugaredLogger
+	manifestScanService manifestScan.ManifestScanService
+	userService         user.UserService
+	enforcer            casbin.Enforcer
+	enforcerUtil        rbac.EnforcerUtil
+}
+
+func NewManifestScanRestHandlerImpl(logger *zap.SugaredLogger,
+	manifestScanService manifestScan.ManifestScanService,
+	userService user.UserService,
+	enforcer casbin.Enforcer,
+	enforcerUtil rbac.EnforcerUtil,
+) *ManifestScanRestHandlerImpl {
+	return &ManifestScanRestHandlerImpl{
+		logger:              logger,
+		manifestScanService: manifestScanService,
+		userService:         userService,
+		enforcer:            enforcer,
+		enforcerUtil:        enforcerUtil,
+	}
+}
+
+func (impl ManifestScanRestHandlerImpl) GetScanResult(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	cdWorkflowRunnerId, err := strconv.Atoi(mux.Vars(r)["cdWorkflowRunnerId"])
+	if err != nil {
+		impl.logger.Errorw("request err, GetScanResult", "err", err, "cdWorkflowRunnerId", mux.Vars(r)["cdWorkflowRunnerId"])
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := impl.manifestScanService.GetScanResult(cdWorkflowRunnerId)
+	if err != nil {
+		impl.logger.Errorw("service err, GetScanResult", "err", err, "cdWorkflowRunnerId", cdWorkflowRunnerId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, impl.enforcerUtil.GetAppRBACNameByAppId(res.AppId)); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl ManifestScanRestHandlerImpl) GetRules(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	common.WriteJsonResp(w, nil, manifestScan.ManifestScanRules, http.StatusOK)
+}
@@ -0,0 +1,22 @@
+package manifestScan
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type ManifestScanRouter interface {
+	InitManifestScanRouter(router *mux.Router)
+}
+
+type ManifestScanRouterImpl struct {
+	manifestScanRestHandler ManifestScanRestHandler
+}
+
+func NewManifestScanRouterImpl(manifestScanRestHandler ManifestScanRestHandler) *ManifestScanRouterImpl {
+	return &ManifestScanRouterImpl{manifestScanRestHandler: manifestScanRestHandler}
+}
+
+func (impl ManifestScanRouterImpl) InitManifestScanRouter(router *mux.Router) {
+	router.Path("/rules").HandlerFunc(impl.manifestScanRestHandler.GetRules).Methods("GET")
+	router.Path("/result/{cdWorkflowRunnerId}").HandlerFunc(impl.manifestScanRestHandler.GetScanResult).Methods("GET")
+}
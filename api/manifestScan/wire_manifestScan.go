@@ -0,0 +1,19 @@
+package manifestScan
+
+import (
+	"github.com/devtron-labs/devtron/pkg/manifestScan"
+	"github.com/google/wire"
+)
+
+var ManifestScanWireSet = wire.NewSet(
+	manifestScan.GetManifestScanConfig,
+	manifestScan.NewManifestScanResultRepositoryImpl,
+	wire.Bind(new(manifestScan.ManifestScanResultRepository), new(*manifestScan.ManifestScanResultRepositoryImpl)),
+
+	manifestScan.NewManifestScanServiceImpl,
+	wire.Bind(new(manifestScan.ManifestScanService), new(*manifestScan.ManifestScanServiceImpl)),
+	NewManifestScanRestHandlerImpl,
+	wire.Bind(new(ManifestScanRestHandler), new(*ManifestScanRestHandlerImpl)),
+	NewManifestScanRouterImpl,
+	wire.Bind(new(ManifestScanRouter), new(*ManifestScanRouterImpl)),
+)
@@ -0,0 +1,845 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package restHandler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	appBean "github.com/devtron-labs/devtron/api/appbean"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	appWorkflow2 "github.com/devtron-labs/devtron/internal/sql/repository/appWorkflow"
+	"github.com/devtron-labs/devtron/pkg/bean"
+	"github.com/devtron-labs/devtron/pkg/chart"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/ghodss/yaml"
+	"github.com/go-pg/pg"
+	"github.com/gorilla/mux"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+//ExportAppDefinition returns the complete setup of an app as a versioned yaml definition, which can be applied back
+func (handler CoreAppRestHandlerImpl) ExportAppDefinition(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	appId, err := strconv.Atoi(vars["appId"])
+	if err != nil {
+		handler.logger.Errorw("request err, ExportAppDefinition", "err", err, "appId", appId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	includeSecretData := false
+	includeSecretDataParam := r.URL.Query().Get("includeSecretData")
+	if len(includeSecretDataParam) > 0 {
+		includeSecretData, err = strconv.ParseBool(includeSecretDataParam)
+		if err != nil {
+			handler.logger.Errorw("request err, ExportAppDefinition", "err", err, "includeSecretData", includeSecretDataParam)
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+
+	//rbac implementation for app (user should be admin)
+	token := r.Header.Get("token")
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionUpdate, object); !ok {
+		handler.logger.Errorw("Unauthorized User for app update action", "appId", appId)
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	//rbac implementation ends here for app
+
+	appDetail, err, statusCode := handler.buildAppDetail(appId, token)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, statusCode)
+		return
+	}
+	if !includeSecretData {
+		removeSecretData(appDetail.GlobalSecrets)
+		for _, environmentOverride := range appDetail.EnvironmentOverrides {
+			removeSecretData(environmentOverride.Secrets)
+		}
+	}
+
+	appDefinition := &appBean.AppDefinition{
+		ApiVersion: appBean.AppDefinitionApiVersion,
+		Kind:       appBean.AppDefinitionKind,
+		Spec:       appDetail,
+	}
+	appDefinitionYaml, err := yaml.Marshal(appDefinition)
+	if err != nil {
+		handler.logger.Errorw("error in marshaling app definition to yaml", "err", err, "appId", appId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-yaml")
+	w.Header().Set("Content-Disposition", "attachment; filename="+appDetail.Metadata.AppName+".yaml")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(appDefinitionYaml)
+	if err != nil {
+		handler.logger.Errorw("error in writing app definition", "err", err, "appId", appId)
+	}
+}
+
+//ApplyAppDefinition creates the app of a yaml/json definition or reconciles the existing app with it,
+//with dryRun=true only the changes are returned
+func (handler CoreAppRestHandlerImpl) ApplyAppDefinition(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	dryRun := false
+	dryRunParam := r.URL.Query().Get("dryRun")
+	if len(dryRunParam) > 0 {
+		dryRun, err = strconv.ParseBool(dryRunParam)
+		if err != nil {
+			handler.logger.Errorw("request err, ApplyAppDefinition", "err", err, "dryRun", dryRunParam)
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+
+	appDefinition, err := handler.decodeAppDefinition(r)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	desired := appDefinition.Spec
+	token := r.Header.Get("token")
+	acdToken, err := handler.argoUserService.GetLatestDevtronArgoCdUserToken()
+	if err != nil {
+		handler.logger.Errorw("error in getting acd token", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	ctx := context.WithValue(r.Context(), "token", acdToken)
+
+	existingApp, err := handler.appRepository.FindActiveByName(desired.Metadata.AppName)
+	if err != nil && err != pg.ErrNoRows {
+		handler.logger.Errorw("error in fetching app by name", "err", err, "appName", desired.Metadata.AppName)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	resp := &appBean.AppDefinitionApplyResponse{
+		AppName: desired.Metadata.AppName,
+		DryRun:  dryRun,
+	}
+
+	if err == pg.ErrNoRows || existingApp == nil || existingApp.Id == 0 {
+		//rbac starts
+		team, err := handler.teamService.FindByTeamName(desired.Metadata.ProjectName)
+		if err != nil || team == nil {
+			handler.logger.Errorw("no project found by name in ApplyAppDefinition", "err", err, "projectName", desired.Metadata.ProjectName)
+			common.WriteJsonResp(w, fmt.Errorf("no project found by name %s", desired.Metadata.ProjectName), nil, http.StatusBadRequest)
+			return
+		}
+		if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionCreate, fmt.Sprintf("%s/%s", strings.ToLower(team.Name), "*")); !ok {
+			common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+			return
+		}
+		//rbac ends
+
+		resp.AppCreated = true
+		resp.Changes, err = appBean.DiffAppDefinition("spec", nil, desired)
+		if err != nil {
+			handler.logger.Errorw("error in computing app definition diff", "err", err, "appName", desired.Metadata.AppName)
+			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+			return
+		}
+		if !dryRun {
+			appId, err, statusCode := handler.createAppWithComponents(ctx, desired, userId, token)
+			if err != nil {
+				common.WriteJsonResp(w, err, nil, statusCode)
+				return
+			}
+			resp.AppId = appId
+		}
+		common.WriteJsonResp(w, nil, resp, http.StatusOK)
+		return
+	}
+
+	appId := existingApp.Id
+	resp.AppId = appId
+	//rbac implementation for app (user should be admin)
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionUpdate, object); !ok {
+		handler.logger.Errorw("Unauthorized User for app update action", "appId", appId)
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	//rbac implementation ends here for app
+
+	current, err, statusCode := handler.buildAppDetail(appId, token)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, statusCode)
+		return
+	}
+	resp.Changes, err = appBean.DiffAppDefinition("spec", current, desired)
+	if err != nil {
+		handler.logger.Errorw("error in computing app definition diff", "err", err, "appId", appId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if !dryRun && len(resp.Changes) > 0 {
+		err, statusCode = handler.reconcileApp(ctx, appId, userId, current, desired, token)
+		if err != nil {
+			common.WriteJsonResp(w, err, nil, statusCode)
+			return
+		}
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler CoreAppRestHandlerImpl) decodeAppDefinition(r *http.Request) (*appBean.AppDefinition, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		handler.logger.Errorw("request err, ApplyAppDefinition", "err", err)
+		return nil, err
+	}
+	//yaml being a superset of json, both formats are accepted
+	appDefinitionJson, err := yaml.YAMLToJSON(body)
+	if err != nil {
+		handler.logger.Errorw("request err, invalid yaml in ApplyAppDefinition", "err", err)
+		return nil, err
+	}
+	appDefinition := &appBean.AppDefinition{}
+	err = json.Unmarshal(appDefinitionJson, appDefinition)
+	if err != nil {
+		handler.logger.Errorw("request err, ApplyAppDefinition", "err", err)
+		return nil, err
+	}
+	err = handler.validator.Struct(appDefinition)
+	if err != nil {
+		handler.logger.Errorw("validation err, ApplyAppDefinition", "err", err, "appDefinition", appDefinition)
+		return nil, err
+	}
+	if appDefinition.ApiVersion != appBean.AppDefinitionApiVersion || appDefinition.Kind != appBean.AppDefinitionKind {
+		return nil, fmt.Errorf("unsupported apiVersion %s or kind %s, expected %s and %s", appDefinition.ApiVersion, appDefinition.Kind, appBean.AppDefinitionApiVersion, appBean.AppDefinitionKind)
+	}
+	err = handler.validator.Struct(appDefinition.Spec)
+	if err != nil {
+		handler.logger.Errorw("validation err, ApplyAppDefinition", "err", err, "appDefinition", appDefinition)
+		return nil, err
+	}
+	return appDefinition, nil
+}
+
+//reconcile existing app with desired definition, sections not present in desired definition are left as they are
+func (handler CoreAppRestHandlerImpl) reconcileApp(ctx context.Context, appId int, userId int32, current *appBean.AppDetail, desired *appBean.AppDetail, token string) (error, int) {
+	handler.logger.Infow("Apply App - reconciling app", "appId", appId)
+
+	//reconciling metadata starts
+	if changed, err := appBean.IsAppDefinitionChanged(current.Metadata, desired.Metadata); err != nil || changed {
+		if err != nil {
+			return err, http.StatusInternalServerError
+		}
+		err, statusCode := handler.updateAppMetadata(appId, userId, desired.Metadata)
+		if err != nil {
+			return err, statusCode
+		}
+	}
+	//reconciling metadata ends
+
+	//reconciling git materials starts
+	var newGitMaterials []*appBean.GitMaterial
+	for _, gitMaterial := range desired.GitMaterials {
+		currentGitMaterial := findGitMaterialByCheckoutPath(current.GitMaterials, gitMaterial.CheckoutPath)
+		if currentGitMaterial == nil {
+			newGitMaterials = append(newGitMaterials, gitMaterial)
+			continue
+		}
+		changed, err := appBean.IsAppDefinitionChanged(currentGitMaterial, gitMaterial)
+		if err != nil {
+			return err, http.StatusInternalServerError
+		}
+		if changed {
+			err, statusCode := handler.updateGitMaterial(appId, gitMaterial, userId)
+			if err != nil {
+				return err, statusCode
+			}
+		}
+	}
+	if len(newGitMaterials) > 0 {
+		err, statusCode := handler.createGitMaterials(appId, newGitMaterials, userId)
+		if err != nil {
+			return err, statusCode
+		}
+	}
+	//reconciling git materials ends
+
+	//reconciling docker config starts
+	if desired.DockerConfig != nil {
+		if current.DockerConfig == nil {
+			err, statusCode := handler.createDockerConfig(appId, desired.DockerConfig, userId)
+			if err != nil {
+				return err, statusCode
+			}
+		} else if changed, err := appBean.IsAppDefinitionChanged(current.DockerConfig, desired.DockerConfig); err != nil || changed {
+			if err != nil {
+				return err, http.StatusInternalServerError
+			}
+			err, statusCode := handler.updateDockerConfig(appId, desired.DockerConfig, userId)
+			if err != nil {
+				return err, statusCode
+			}
+		}
+	}
+	//reconciling docker config ends
+
+	//reconciling deployment template starts
+	if desired.GlobalDeploymentTemplate != nil {
+		if current.GlobalDeploymentTemplate == nil || current.GlobalDeploymentTemplate.ChartRefId != desired.GlobalDeploymentTemplate.ChartRefId {
+			err, statusCode := handler.createDeploymentTemplate(ctx, appId, desired.GlobalDeploymentTemplate, userId)
+			if err != nil {
+				return err, statusCode
+			}
+		} else if changed, err := appBean.IsAppDefinitionChanged(current.GlobalDeploymentTemplate, desired.GlobalDeploymentTemplate); err != nil || changed {
+			if err != nil {
+				return err, http.StatusInternalServerError
+			}
+			err, statusCode := handler.updateDeploymentTemplate(appId, desired.GlobalDeploymentTemplate, userId)
+			if err != nil {
+				return err, statusCode
+			}
+		}
+	}
+	//reconciling deployment template ends
+
+	//reconciling global configMaps starts
+	changedConfigMaps, err := filterChangedConfigMaps(current.GlobalConfigMaps, desired.GlobalConfigMaps)
+	if err != nil {
+		return err, http.StatusInternalServerError
+	}
+	if len(changedConfigMaps) > 0 {
+		err, statusCode := handler.createGlobalConfigMaps(appId, userId, changedConfigMaps)
+		if err != nil {
+			return err, statusCode
+		}
+	}
+	//reconciling global configMaps ends
+
+	//reconciling global secrets starts
+	changedSecrets, err := filterChangedSecrets(current.GlobalSecrets, desired.GlobalSecrets)
+	if err != nil {
+		return err, http.StatusInternalServerError
+	}
+	if len(changedSecrets) > 0 {
+		err, statusCode := handler.createGlobalSecrets(appId, userId, changedSecrets)
+		if err != nil {
+			return err, statusCode
+		}
+	}
+	//reconciling global secrets ends
+
+	//reconciling workflows starts
+	if len(desired.AppWorkflows) > 0 {
+		err, statusCode := handler.reconcileWorkflows(ctx, appId, userId, current.AppWorkflows, desired.AppWorkflows, token, desired.Metadata.AppName)
+		if err != nil {
+			return err, statusCode
+		}
+	}
+	//reconciling workflows ends
+
+	//reconciling environment overrides starts
+	changedEnvironmentOverrides := make(map[string]*appBean.EnvironmentOverride)
+	for envName, environmentOverride := range desired.EnvironmentOverrides {
+		currentEnvironmentOverride := current.EnvironmentOverrides[envName]
+		if currentEnvironmentOverride == nil {
+			changedEnvironmentOverrides[envName] = environmentOverride
+			continue
+		}
+		changed, err := appBean.IsAppDefinitionChanged(currentEnvironmentOverride, environmentOverride)
+		if err != nil {
+			return err, http.StatusInternalServerError
+		}
+		if !changed {
+			continue
+		}
+		changedEnvConfigMaps, err := filterChangedConfigMaps(currentEnvironmentOverride.ConfigMaps, environmentOverride.ConfigMaps)
+		if err != nil {
+			return err, http.StatusInternalServerError
+		}
+		changedEnvSecrets, err := filterChangedSecrets(currentEnvironmentOverride.Secrets, environmentOverride.Secrets)
+		if err != nil {
+			return err, http.StatusInternalServerError
+		}
+		changedEnvironmentOverride := &appBean.EnvironmentOverride{
+			ConfigMaps: changedEnvConfigMaps,
+			Secrets:    changedEnvSecrets,
+		}
+		if changed, err = appBean.IsAppDefinitionChanged(currentEnvironmentOverride.DeploymentTemplate, environmentOverride.DeploymentTemplate); err != nil {
+			return err, http.StatusInternalServerError
+		} else if changed {
+			changedEnvironmentOverride.DeploymentTemplate = environmentOverride.DeploymentTemplate
+		}
+		changedEnvironmentOverrides[envName] = changedEnvironmentOverride
+	}
+	if len(changedEnvironmentOverrides) > 0 {
+		err, statusCode := handler.createEnvOverrides(ctx, appId, userId, changedEnvironmentOverrides, token)
+		if err != nil {
+			return err, statusCode
+		}
+	}
+	//reconciling environment overrides ends
+
+	return nil, http.StatusOK
+}
+
+//update project and labels of app
+func (handler CoreAppRestHandlerImpl) updateAppMetadata(appId int, userId int32, appMetadata *appBean.AppMetadata) (error, int) {
+	handler.logger.Infow("Apply App - updating app metadata", "appId", appId, "appMetadata", appMetadata)
+
+	team, err := handler.teamService.FindByTeamName(appMetadata.ProjectName)
+	if err != nil || team == nil {
+		handler.logger.Errorw("no project found by name in updateAppMetadata", "err", err, "projectName", appMetadata.ProjectName)
+		return fmt.Errorf("no project found by name %s", appMetadata.ProjectName), http.StatusBadRequest
+	}
+	var appLabels []*bean.Label
+	for _, requestLabel := range appMetadata.Labels {
+		appLabels = append(appLabels, &bean.Label{
+			Key:   requestLabel.Key,
+			Value: requestLabel.Value,
+		})
+	}
+	updateAppRequest := &bean.CreateAppDTO{
+		Id:        appId,
+		AppName:   appMetadata.AppName,
+		TeamId:    team.Id,
+		AppLabels: appLabels,
+		UserId:    userId,
+	}
+	_, err = handler.appCrudOperationService.UpdateApp(updateAppRequest)
+	if err != nil {
+		handler.logger.Errorw("service err, UpdateApp in updateAppMetadata", "err", err, "payload", updateAppRequest)
+		return err, http.StatusInternalServerError
+	}
+	return nil, http.StatusOK
+}
+
+//update git material identified by checkout path
+func (handler CoreAppRestHandlerImpl) updateGitMaterial(appId int, gitMaterial *appBean.GitMaterial, userId int32) (error, int) {
+	handler.logger.Infow("Apply App - updating git material", "appId", appId, "gitMaterial", gitMaterial)
+
+	err := handler.validator.Struct(gitMaterial)
+	if err != nil {
+		handler.logger.Errorw("validation err, gitMaterial in updateGitMaterial", "err", err, "gitMaterial", gitMaterial)
+		return err, http.StatusBadRequest
+	}
+	existingMaterial, err := handler.materialRepository.FindByAppIdAndCheckoutPath(appId, gitMaterial.CheckoutPath)
+	if err != nil {
+		handler.logger.Errorw("service err, FindByAppIdAndCheckoutPath in updateGitMaterial", "err", err, "appId", appId)
+		return err, http.StatusInternalServerError
+	}
+	gitProvider, err := handler.gitProviderRepo.FindByUrl(gitMaterial.GitProviderUrl)
+	if err != nil {
+		handler.logger.Errorw("service err, FindByUrl in updateGitMaterial", "err", err, "gitProviderUrl", gitMaterial.GitProviderUrl)
+		return err, http.StatusInternalServerError
+	}
+	updateMaterialRequest := &bean.UpdateMaterialDTO{
+		AppId:  appId,
+		UserId: userId,
+		Material: &bean.GitMaterial{
+			Id:              existingMaterial.Id,
+			Url:             gitMaterial.GitRepoUrl,
+			GitProviderId:   gitProvider.Id,
+			CheckoutPath:    gitMaterial.CheckoutPath,
+			FetchSubmodules: gitMaterial.FetchSubmodules,
+		},
+	}
+	_, err = handler.pipelineBuilder.UpdateMaterialsForApp(updateMaterialRequest)
+	if err != nil {
+		handler.logger.Errorw("service err, UpdateMaterialsForApp in updateGitMaterial", "err", err, "payload", updateMaterialRequest)
+		return err, http.StatusInternalServerError
+	}
+	return nil, http.StatusOK
+}
+
+//update docker config of existing ci template
+func (handler CoreAppRestHandlerImpl) updateDockerConfig(appId int, dockerConfig *appBean.DockerConfig, userId int32) (error, int) {
+	handler.logger.Infow("Apply App - updating docker config", "appId", appId, "dockerConfig", dockerConfig)
+
+	ciConfig, err := handler.pipelineBuilder.GetCiPipeline(appId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetCiPipeline in updateDockerConfig", "err", err, "appId", appId)
+		return err, http.StatusInternalServerError
+	}
+	gitMaterial, err := handler.materialRepository.FindByAppIdAndCheckoutPath(appId, dockerConfig.BuildConfig.GitCheckoutPath)
+	if err != nil {
+		handler.logger.Errorw("service err, FindByAppIdAndCheckoutPath in updateDockerConfig", "err", err, "appId", appId)
+		return err, http.StatusInternalServerError
+	}
+	dockerBuildArgs := make(map[string]string)
+	if dockerConfig.BuildConfig.Args != nil {
+		dockerBuildArgs = dockerConfig.BuildConfig.Args
+	}
+	ciConfig.UserId = userId
+	ciConfig.DockerRegistry = dockerConfig.DockerRegistry
+	ciConfig.DockerRepository = dockerConfig.DockerRepository
+	ciConfig.DockerBuildConfig = &bean.DockerBuildConfig{
+		GitMaterialId:  gitMaterial.Id,
+		DockerfilePath: dockerConfig.BuildConfig.DockerfileRelativePath,
+		Args:           dockerBuildArgs,
+		TargetPlatform: dockerConfig.BuildConfig.TargetPlatform,
+	}
+	_, err = handler.pipelineBuilder.UpdateCiTemplate(ciConfig)
+	if err != nil {
+		handler.logger.Errorw("service err, UpdateCiTemplate in updateDockerConfig", "err", err, "appId", appId)
+		return err, http.StatusInternalServerError
+	}
+	return nil, http.StatusOK
+}
+
+//update latest global template, chart ref is unchanged
+func (handler CoreAppRestHandlerImpl) updateDeploymentTemplate(appId int, deploymentTemplate *appBean.DeploymentTemplate, userId int32) (error, int) {
+	handler.logger.Infow("Apply App - updating deployment template", "appId", appId)
+
+	latestChart, err := handler.chartService.FindLatestChartForAppByAppId(appId)
+	if err != nil {
+		handler.logger.Errorw("service err, FindLatestChartForAppByAppId in updateDeploymentTemplate", "err", err, "appId", appId)
+		return err, http.StatusInternalServerError
+	}
+	template, err := json.Marshal(deploymentTemplate.Template)
+	if err != nil {
+		handler.logger.Errorw("service err, could not json marshal template in updateDeploymentTemplate", "err", err, "appId", appId)
+		return err, http.StatusInternalServerError
+	}
+	updateRequest := &chart.TemplateRequest{
+		Id:                  latestChart.Id,
+		AppId:               appId,
+		ChartRefId:          deploymentTemplate.ChartRefId,
+		ValuesOverride:      json.RawMessage(template),
+		IsAppMetricsEnabled: deploymentTemplate.ShowAppMetrics,
+		UserId:              userId,
+	}
+	_, err = handler.chartService.UpdateAppOverride(updateRequest)
+	if err != nil {
+		handler.logger.Errorw("service err, UpdateAppOverride in updateDeploymentTemplate", "err", err, "appId", appId)
+		return err, http.StatusInternalServerError
+	}
+
+	//updating app metrics
+	appMetricsRequest := chart.AppMetricEnableDisableRequest{
+		AppId:               appId,
+		UserId:              userId,
+		IsAppMetricsEnabled: deploymentTemplate.ShowAppMetrics,
+	}
+	_, err = handler.chartService.AppMetricsEnableDisable(appMetricsRequest)
+	if err != nil {
+		handler.logger.Errorw("service err, AppMetricsEnableDisable in updateDeploymentTemplate", "err", err, "appId", appId, "payload", appMetricsRequest)
+		return err, http.StatusInternalServerError
+	}
+	return nil, http.StatusOK
+}
+
+//create new workflows and update ci & cd pipelines of existing workflows, matched by name
+func (handler CoreAppRestHandlerImpl) reconcileWorkflows(ctx context.Context, appId int, userId int32, currentWorkflows []*appBean.AppWorkflow, desiredWorkflows []*appBean.AppWorkflow, token string, appName string) (error, int) {
+	handler.logger.Infow("Apply App - reconciling workflows", "appId", appId)
+
+	workflowsList, err := handler.appWorkflowService.FindAppWorkflows(appId)
+	if err != nil {
+		handler.logger.Errorw("error in fetching workflows for app in reconcileWorkflows", "err", err, "appId", appId)
+		return err, http.StatusInternalServerError
+	}
+
+	var newWorkflows []*appBean.AppWorkflow
+	for _, desiredWorkflow := range desiredWorkflows {
+		currentWorkflow := findWorkflowByName(currentWorkflows, desiredWorkflow.Name)
+		if currentWorkflow == nil {
+			newWorkflows = append(newWorkflows, desiredWorkflow)
+			continue
+		}
+		changed, err := appBean.IsAppDefinitionChanged(currentWorkflow, desiredWorkflow)
+		if err != nil {
+			return err, http.StatusInternalServerError
+		}
+		if !changed {
+			continue
+		}
+
+		//finding ids of existing workflow and its pipelines
+		var workflowId, ciPipelineId int
+		cdPipelinesByName := make(map[string]*bean.CDPipelineConfigObject)
+		for _, workflow := range workflowsList {
+			if workflow.Name != desiredWorkflow.Name {
+				continue
+			}
+			workflowId = workflow.Id
+			for _, workflowMapping := range workflow.AppWorkflowMappingDto {
+				if workflowMapping.Type == appWorkflow2.CIPIPELINE {
+					ciPipelineId = workflowMapping.ComponentId
+				} else if workflowMapping.Type == appWorkflow2.CDPIPELINE {
+					cdPipeline, err := handler.pipelineBuilder.GetCdPipelineById(workflowMapping.ComponentId)
+					if err != nil {
+						handler.logger.Errorw("service err, GetCdPipelineById in reconcileWorkflows", "err", err, "appId", appId)
+						return err, http.StatusInternalServerError
+					}
+					cdPipelinesByName[cdPipeline.Name] = cdPipeline
+				}
+			}
+		}
+
+		if desiredWorkflow.CiPipeline != nil && ciPipelineId > 0 {
+			changed, err = appBean.IsAppDefinitionChanged(currentWorkflow.CiPipeline, desiredWorkflow.CiPipeline)
+			if err != nil {
+				return err, http.StatusInternalServerError
+			}
+			if changed {
+				err = handler.updateCiPipeline(appId, userId, workflowId, ciPipelineId, desiredWorkflow.CiPipeline)
+				if err != nil {
+					return err, http.StatusInternalServerError
+				}
+			}
+		}
+
+		var newCdPipelines []*appBean.CdPipelineDetails
+		for _, desiredCdPipeline := range desiredWorkflow.CdPipelines {
+			existingCdPipeline, ok := cdPipelinesByName[desiredCdPipeline.Name]
+			if !ok {
+				newCdPipelines = append(newCdPipelines, desiredCdPipeline)
+				continue
+			}
+			changed, err = appBean.IsAppDefinitionChanged(findCdPipelineByName(currentWorkflow.CdPipelines, desiredCdPipeline.Name), desiredCdPipeline)
+			if err != nil {
+				return err, http.StatusInternalServerError
+			}
+			if changed {
+				err = handler.updateCdPipeline(ctx, appId, userId, existingCdPipeline, desiredCdPipeline, token, appName)
+				if err != nil {
+					return err, http.StatusInternalServerError
+				}
+			}
+		}
+		if len(newCdPipelines) > 0 {
+			err = handler.createCdPipelines(ctx, appId, userId, workflowId, ciPipelineId, newCdPipelines, token, appName)
+			if err != nil {
+				handler.logger.Errorw("err in saving cd pipelines", "err", err, "appId", appId)
+				return err, http.StatusInternalServerError
+			}
+		}
+	}
+
+	if len(newWorkflows) > 0 {
+		return handler.createWorkflows(ctx, appId, userId, newWorkflows, token, appName)
+	}
+	return nil, http.StatusOK
+}
+
+//update existing ci pipeline with desired details, materials are matched by checkout path
+func (handler CoreAppRestHandlerImpl) updateCiPipeline(appId int, userId int32, workflowId int, ciPipelineId int, ciPipelineData *appBean.CiPipelineDetails) error {
+	handler.logger.Infow("Apply App - updating ci pipeline", "appId", appId, "ciPipelineId", ciPipelineId)
+
+	if ciPipelineData.IsExternal {
+		err := errors.New("external ci pipeline update is not supported yet")
+		handler.logger.Errorw("external ci pipeline update is not supported yet", "ciPipelineId", ciPipelineId)
+		return err
+	}
+	ciPipeline, err := handler.pipelineBuilder.GetCiPipelineById(ciPipelineId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetCiPipelineById in updateCiPipeline", "err", err, "ciPipelineId", ciPipelineId)
+		return err
+	}
+
+	for _, ciMaterial := range ciPipelineData.CiPipelineMaterialsConfig {
+		gitMaterial, err := handler.materialRepository.FindByAppIdAndCheckoutPath(appId, ciMaterial.CheckoutPath)
+		if err != nil {
+			handler.logger.Errorw("service err, FindByAppIdAndCheckoutPath in updateCiPipeline", "err", err, "appId", appId)
+			return err
+		}
+		matched := false
+		for _, existingCiMaterial := range ciPipeline.CiMaterial {
+			if existingCiMaterial.GitMaterialId == gitMaterial.Id {
+				existingCiMaterial.Source = &bean.SourceTypeConfig{
+					Type:  ciMaterial.Type,
+					Value: ciMaterial.Value,
+				}
+				matched = true
+			}
+		}
+		if !matched {
+			return fmt.Errorf("git material %s is not used by ci pipeline %s, adding materials to existing ci pipeline is not supported", ciMaterial.CheckoutPath, ciPipeline.Name)
+		}
+	}
+
+	ciPipeline.IsManual = ciPipelineData.IsManual
+	ciPipeline.DockerArgs = ciPipelineData.DockerBuildArgs
+	ciPipeline.ScanEnabled = ciPipelineData.VulnerabilityScanEnabled
+	ciPipeline.BeforeDockerBuildScripts = convertCiBuildScripts(ciPipelineData.BeforeDockerBuildScripts)
+	ciPipeline.AfterDockerBuildScripts = convertCiBuildScripts(ciPipelineData.AfterDockerBuildScripts)
+	if ciPipelineData.PreBuildStage != nil {
+		ciPipeline.PreBuildStage = ciPipelineData.PreBuildStage
+	}
+	if ciPipelineData.PostBuildStage != nil {
+		ciPipeline.PostBuildStage = ciPipelineData.PostBuildStage
+	}
+
+	ciPipelineRequest := &bean.CiPatchRequest{
+		AppId:         appId,
+		UserId:        userId,
+		AppWorkflowId: workflowId,
+		Action:        bean.UPDATE_SOURCE,
+		CiPipeline:    ciPipeline,
+	}
+	_, err = handler.pipelineBuilder.PatchCiPipeline(ciPipelineRequest)
+	if err != nil {
+		handler.logger.Errorw("service err, PatchCiPipeline in updateCiPipeline", "err", err, "appId", appId)
+		return err
+	}
+	return nil
+}
+
+//update existing cd pipeline with desired details, environment of cd pipeline can not be changed
+func (handler CoreAppRestHandlerImpl) updateCdPipeline(ctx context.Context, appId int, userId int32, cdPipeline *bean.CDPipelineConfigObject, cdPipelineData *appBean.CdPipelineDetails, token string, appName string) error {
+	handler.logger.Infow("Apply App - updating cd pipeline", "appId", appId, "cdPipelineId", cdPipeline.Id)
+
+	if len(cdPipelineData.EnvironmentName) > 0 && cdPipelineData.EnvironmentName != cdPipeline.EnvironmentName {
+		return fmt.Errorf("environment of cd pipeline %s can not be changed from %s to %s", cdPipeline.Name, cdPipeline.EnvironmentName, cdPipelineData.EnvironmentName)
+	}
+
+	// RBAC starts
+	object := handler.enforcerUtil.GetAppRBACByAppNameAndEnvId(appName, cdPipeline.EnvironmentId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionUpdate, object); !ok {
+		return errors.New("unauthorized User")
+	}
+	// RBAC ends
+
+	convertedDeploymentStrategies, err := convertCdDeploymentStrategies(cdPipelineData.DeploymentStrategies)
+	if err != nil {
+		handler.logger.Errorw("err in converting deployment strategies for updating cd pipeline", "appId", appId, "Strategies", cdPipelineData.DeploymentStrategies)
+		return err
+	}
+	cdPipeline.TriggerType = cdPipelineData.TriggerType
+	cdPipeline.DeploymentTemplate = cdPipelineData.DeploymentType
+	cdPipeline.Strategies = convertedDeploymentStrategies
+	cdPipeline.CdArgoSetup = cdPipelineData.IsClusterCdActive
+	cdPipeline.RunPreStageInEnv = cdPipelineData.RunPreStageInEnv
+	cdPipeline.RunPostStageInEnv = cdPipelineData.RunPostStageInEnv
+	cdPipeline.PreStage = convertCdStages(cdPipelineData.PreStage)
+	cdPipeline.PostStage = convertCdStages(cdPipelineData.PostStage)
+	cdPipeline.PreStageConfigMapSecretNames = convertCdPreStageCMorCSNames(cdPipelineData.PreStageConfigMapSecretNames)
+	cdPipeline.PostStageConfigMapSecretNames = convertCdPostStageCMorCSNames(cdPipelineData.PostStageConfigMapSecretNames)
+
+	cdPipelineUpdateRequest := &bean.CDPatchRequest{
+		AppId:    appId,
+		UserId:   userId,
+		Action:   bean.CD_UPDATE,
+		Pipeline: cdPipeline,
+	}
+	_, err = handler.pipelineBuilder.PatchCdPipelines(cdPipelineUpdateRequest, ctx)
+	if err != nil {
+		handler.logger.Errorw("service err, PatchCdPipelines in updateCdPipeline", "err", err, "appId", appId, "cdPipelineId", cdPipeline.Id)
+		return err
+	}
+	return nil
+}
+
+//private methods for apply below
+
+//data of non external secrets is dropped from export unless asked for explicitly
+func removeSecretData(secrets []*appBean.Secret) {
+	for _, secret := range secrets {
+		if !secret.IsExternal {
+			secret.Data = nil
+		}
+	}
+}
+
+func filterChangedConfigMaps(currentConfigMaps []*appBean.ConfigMap, desiredConfigMaps []*appBean.ConfigMap) ([]*appBean.ConfigMap, error) {
+	var changedConfigMaps []*appBean.ConfigMap
+	for _, desiredConfigMap := range desiredConfigMaps {
+		var currentConfigMap *appBean.ConfigMap
+		for _, configMap := range currentConfigMaps {
+			if configMap.Name == desiredConfigMap.Name {
+				currentConfigMap = configMap
+				break
+			}
+		}
+		changed, err := appBean.IsAppDefinitionChanged(currentConfigMap, desiredConfigMap)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			changedConfigMaps = append(changedConfigMaps, desiredConfigMap)
+		}
+	}
+	return changedConfigMaps, nil
+}
+
+//secrets without data in desired definition (exported without data) keep their current data
+func filterChangedSecrets(currentSecrets []*appBean.Secret, desiredSecrets []*appBean.Secret) ([]*appBean.Secret, error) {
+	var changedSecrets []*appBean.Secret
+	for _, desiredSecret := range desiredSecrets {
+		var currentSecret *appBean.Secret
+		for _, secret := range currentSecrets {
+			if secret.Name == desiredSecret.Name {
+				currentSecret = secret
+				break
+			}
+		}
+		changed, err := appBean.IsAppDefinitionChanged(currentSecret, desiredSecret)
+		if err != nil {
+			return nil, err
+		}
+		if !changed {
+			continue
+		}
+		if desiredSecret.Data == nil && !desiredSecret.IsExternal && currentSecret != nil {
+			secretWithCurrentData := *desiredSecret
+			secretWithCurrentData.Data = currentSecret.Data
+			desiredSecret = &secretWithCurrentData
+		}
+		changedSecrets = append(changedSecrets, desiredSecret)
+	}
+	return changedSecrets, nil
+}
+
+func findGitMaterialByCheckoutPath(gitMaterials []*appBean.GitMaterial, checkoutPath string) *appBean.GitMaterial {
+	for _, gitMaterial := range gitMaterials {
+		if gitMaterial.CheckoutPath == checkoutPath {
+			return gitMaterial
+		}
+	}
+	return nil
+}
+
+func findWorkflowByName(workflows []*appBean.AppWorkflow, name string) *appBean.AppWorkflow {
+	for _, workflow := range workflows {
+		if workflow.Name == name {
+			return workflow
+		}
+	}
+	return nil
+}
+
+func findCdPipelineByName(cdPipelines []*appBean.CdPipelineDetails, name string) *appBean.CdPipelineDetails {
+	for _, cdPipeline := range cdPipelines {
+		if cdPipeline.Name == name {
+			return cdPipeline
+		}
+	}
+	return nil
+}
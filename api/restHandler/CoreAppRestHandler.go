@@ -27,6 +27,7 @@ import (
 	"github.com/devtron-labs/devtron/api/restHandler/common"
 	"github.com/devtron-labs/devtron/internal/sql/models"
 	"github.com/devtron-labs/devtron/internal/sql/repository"
+	app3 "github.com/devtron-labs/devtron/internal/sql/repository/app"
 	appWorkflow2 "github.com/devtron-labs/devtron/internal/sql/repository/appWorkflow"
 	"github.com/devtron-labs/devtron/internal/sql/repository/chartConfig"
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
@@ -64,6 +65,8 @@ const (
 type CoreAppRestHandler interface {
 	GetAppAllDetail(w http.ResponseWriter, r *http.Request)
 	CreateApp(w http.ResponseWriter, r *http.Request)
+	ExportAppDefinition(w http.ResponseWriter, r *http.Request)
+	ApplyAppDefinition(w http.ResponseWriter, r *http.Request)
 }
 
 type CoreAppRestHandlerImpl struct {
@@ -90,6 +93,7 @@ type CoreAppRestHandlerImpl struct {
 	teamService             team.TeamService
 	argoUserService         argo.ArgoUserService
 	pipelineStageService    pipeline.PipelineStageService
+	appRepository           app3.AppRepository
 }
 
 func NewCoreAppRestHandlerImpl(logger *zap.SugaredLogger, userAuthService user.UserService, validator *validator.Validate, enforcerUtil rbac.EnforcerUtil,
@@ -99,7 +103,7 @@ func NewCoreAppRestHandlerImpl(logger *zap.SugaredLogger, userAuthService user.U
 	materialRepository pipelineConfig.MaterialRepository, gitProviderRepo repository.GitProviderRepository,
 	appWorkflowRepository appWorkflow2.AppWorkflowRepository, environmentRepository repository2.EnvironmentRepository, configMapRepository chartConfig.ConfigMapRepository,
 	envConfigRepo chartConfig.EnvConfigOverrideRepository, chartRepo chartRepoRepository.ChartRepository, teamService team.TeamService,
-	argoUserService argo.ArgoUserService, pipelineStageService pipeline.PipelineStageService) *CoreAppRestHandlerImpl {
+	argoUserService argo.ArgoUserService, pipelineStageService pipeline.PipelineStageService, appRepository app3.AppRepository) *CoreAppRestHandlerImpl {
 	handler := &CoreAppRestHandlerImpl{
 		logger:                  logger,
 		userAuthService:         userAuthService,
@@ -124,6 +128,7 @@ func NewCoreAppRestHandlerImpl(logger *zap.SugaredLogger, userAuthService user.U
 		teamService:             teamService,
 		argoUserService:         argoUserService,
 		pipelineStageService:    pipelineStageService,
+		appRepository:           appRepository,
 	}
 	return handler
 }
@@ -154,84 +159,11 @@ func (handler CoreAppRestHandlerImpl) GetAppAllDetail(w http.ResponseWriter, r *
 	}
 	//rbac implementation ends here for app
 
-	handler.logger.Debugw("Getting app detail v2", "appId", appId)
-
-	//get/build app metadata starts
-	appMetadataResp, err, statusCode := handler.buildAppMetadata(appId)
-	if err != nil {
-		common.WriteJsonResp(w, err, nil, statusCode)
-		return
-	}
-	//get/build app metadata ends
-
-	//get/build git materials starts
-	gitMaterialsResp, err, statusCode := handler.buildAppGitMaterials(appId)
-	if err != nil {
-		common.WriteJsonResp(w, err, nil, statusCode)
-		return
-	}
-	//get/build git materials ends
-
-	//get/build docker config starts
-	dockerConfig, err, statusCode := handler.buildDockerConfig(appId)
-	if err != nil {
-		common.WriteJsonResp(w, err, nil, statusCode)
-		return
-	}
-	//get/build docker config ends
-
-	//get/build global deployment template starts
-	globalDeploymentTemplateResp, err, statusCode := handler.buildAppDeploymentTemplate(appId)
-	if err != nil {
-		common.WriteJsonResp(w, err, nil, statusCode)
-		return
-	}
-	//get/build global deployment template ends
-
-	//get/build app workflows starts
-	appWorkflows, err, statusCode := handler.buildAppWorkflows(appId)
-	if err != nil {
-		common.WriteJsonResp(w, err, nil, statusCode)
-		return
-	}
-	//get/build app workflows ends
-
-	//get/build global config maps starts
-	globalConfigMapsResp, err, statusCode := handler.buildAppGlobalConfigMaps(appId)
-	if err != nil {
-		common.WriteJsonResp(w, err, nil, statusCode)
-		return
-	}
-	//get/build global config maps ends
-
-	//get/build global secrets starts
-	globalSecretsResp, err, statusCode := handler.buildAppGlobalSecrets(appId)
-	if err != nil {
-		common.WriteJsonResp(w, err, nil, statusCode)
-		return
-	}
-	//get/build global secrets ends
-
-	//get/build environment override starts
-	environmentOverrides, err, statusCode := handler.buildEnvironmentOverrides(appId, token)
+	appDetail, err, statusCode := handler.buildAppDetail(appId, token)
 	if err != nil {
 		common.WriteJsonResp(w, err, nil, statusCode)
 		return
 	}
-	//get/build environment override ends
-
-	//build full object for response
-	appDetail := &appBean.AppDetail{
-		Metadata:                 appMetadataResp,
-		GitMaterials:             gitMaterialsResp,
-		DockerConfig:             dockerConfig,
-		GlobalDeploymentTemplate: globalDeploymentTemplateResp,
-		AppWorkflows:             appWorkflows,
-		GlobalConfigMaps:         globalConfigMapsResp,
-		GlobalSecrets:            globalSecretsResp,
-		EnvironmentOverrides:     environmentOverrides,
-	}
-	//end
 
 	common.WriteJsonResp(w, nil, appDetail, http.StatusOK)
 }
@@ -287,130 +219,92 @@ func (handler CoreAppRestHandlerImpl) CreateApp(w http.ResponseWriter, r *http.R
 	}
 	//rbac ends
 
-	handler.logger.Infow("creating app v2", "createAppRequest", createAppRequest)
-
-	//creating blank app starts
-	createBlankAppResp, err, statusCode := handler.createBlankApp(createAppRequest.Metadata, userId)
+	_, err, statusCode := handler.createAppWithComponents(ctx, &createAppRequest, userId, token)
 	if err != nil {
 		common.WriteJsonResp(w, err, nil, statusCode)
 		return
 	}
-	//creating blank app ends
 
-	//declaring appId for creating other components of app
-	appId := createBlankAppResp.Id
+	common.WriteJsonResp(w, nil, APP_CREATE_SUCCESSFUL_RESP, http.StatusOK)
+}
 
-	var errResp *multierror.Error
+//GetApp related methods starts
 
-	//creating git material starts
-	if createAppRequest.GitMaterials != nil {
-		err, statusCode = handler.createGitMaterials(appId, createAppRequest.GitMaterials, userId)
-		if err != nil {
-			errResp = multierror.Append(errResp, err)
-			errInAppDelete := handler.deleteApp(ctx, appId, userId)
-			if errInAppDelete != nil {
-				errResp = multierror.Append(errResp, fmt.Errorf("%s : %w", APP_DELETE_FAILED_RESP, errInAppDelete))
-			}
-			common.WriteJsonResp(w, errResp, nil, statusCode)
-			return
-		}
+//get/build full app detail
+func (handler CoreAppRestHandlerImpl) buildAppDetail(appId int, token string) (*appBean.AppDetail, error, int) {
+	handler.logger.Debugw("Getting app detail v2", "appId", appId)
+
+	//get/build app metadata starts
+	appMetadataResp, err, statusCode := handler.buildAppMetadata(appId)
+	if err != nil {
+		return nil, err, statusCode
 	}
-	//creating git material ends
+	//get/build app metadata ends
 
-	//creating docker config
-	if createAppRequest.DockerConfig != nil {
-		err, statusCode = handler.createDockerConfig(appId, createAppRequest.DockerConfig, userId)
-		if err != nil {
-			errResp = multierror.Append(errResp, err)
-			errInAppDelete := handler.deleteApp(ctx, appId, userId)
-			if errInAppDelete != nil {
-				errResp = multierror.Append(errResp, fmt.Errorf("%s : %w", APP_DELETE_FAILED_RESP, errInAppDelete))
-			}
-			common.WriteJsonResp(w, errResp, nil, statusCode)
-			return
-		}
+	//get/build git materials starts
+	gitMaterialsResp, err, statusCode := handler.buildAppGitMaterials(appId)
+	if err != nil {
+		return nil, err, statusCode
 	}
-	//creating docker config ends
+	//get/build git materials ends
 
-	//creating deployment template starts
-	if createAppRequest.GlobalDeploymentTemplate != nil {
-		err, statusCode = handler.createDeploymentTemplate(ctx, appId, createAppRequest.GlobalDeploymentTemplate, userId)
-		if err != nil {
-			errResp = multierror.Append(errResp, err)
-			errInAppDelete := handler.deleteApp(ctx, appId, userId)
-			if errInAppDelete != nil {
-				errResp = multierror.Append(errResp, fmt.Errorf("%s : %w", APP_DELETE_FAILED_RESP, errInAppDelete))
-			}
-			common.WriteJsonResp(w, errResp, nil, statusCode)
-			return
-		}
+	//get/build docker config starts
+	dockerConfig, err, statusCode := handler.buildDockerConfig(appId)
+	if err != nil {
+		return nil, err, statusCode
 	}
-	//creating deployment template ends
+	//get/build docker config ends
 
-	//creating global configMaps starts
-	if createAppRequest.GlobalConfigMaps != nil {
-		err, statusCode = handler.createGlobalConfigMaps(appId, userId, createAppRequest.GlobalConfigMaps)
-		if err != nil {
-			errResp = multierror.Append(errResp, err)
-			errInAppDelete := handler.deleteApp(ctx, appId, userId)
-			if errInAppDelete != nil {
-				errResp = multierror.Append(errResp, fmt.Errorf("%s : %w", APP_DELETE_FAILED_RESP, errInAppDelete))
-			}
-			common.WriteJsonResp(w, errResp, nil, statusCode)
-			return
-		}
+	//get/build global deployment template starts
+	globalDeploymentTemplateResp, err, statusCode := handler.buildAppDeploymentTemplate(appId)
+	if err != nil {
+		return nil, err, statusCode
 	}
-	//creating global configMaps ends
+	//get/build global deployment template ends
 
-	//creating global secrets starts
-	if createAppRequest.GlobalSecrets != nil {
-		err, statusCode = handler.createGlobalSecrets(appId, userId, createAppRequest.GlobalSecrets)
-		if err != nil {
-			errResp = multierror.Append(errResp, err)
-			errInAppDelete := handler.deleteApp(ctx, appId, userId)
-			if errInAppDelete != nil {
-				errResp = multierror.Append(errResp, fmt.Errorf("%s : %w", APP_DELETE_FAILED_RESP, errInAppDelete))
-			}
-			common.WriteJsonResp(w, errResp, nil, statusCode)
-			return
-		}
+	//get/build app workflows starts
+	appWorkflows, err, statusCode := handler.buildAppWorkflows(appId)
+	if err != nil {
+		return nil, err, statusCode
 	}
-	//creating global secrets ends
+	//get/build app workflows ends
 
-	//creating workflow starts
-	if createAppRequest.AppWorkflows != nil {
-		err, statusCode = handler.createWorkflows(ctx, appId, userId, createAppRequest.AppWorkflows, token, createAppRequest.Metadata.AppName)
-		if err != nil {
-			errResp = multierror.Append(errResp, err)
-			errInAppDelete := handler.deleteApp(ctx, appId, userId)
-			if errInAppDelete != nil {
-				errResp = multierror.Append(errResp, fmt.Errorf("%s : %w", APP_DELETE_FAILED_RESP, errInAppDelete))
-			}
-			common.WriteJsonResp(w, errResp, nil, statusCode)
-			return
-		}
+	//get/build global config maps starts
+	globalConfigMapsResp, err, statusCode := handler.buildAppGlobalConfigMaps(appId)
+	if err != nil {
+		return nil, err, statusCode
 	}
-	//creating workflow ends
+	//get/build global config maps ends
 
-	//creating environment override starts
-	if createAppRequest.EnvironmentOverrides != nil {
-		err, statusCode = handler.createEnvOverrides(ctx, appId, userId, createAppRequest.EnvironmentOverrides, token)
-		if err != nil {
-			errResp = multierror.Append(errResp, err)
-			errInAppDelete := handler.deleteApp(ctx, appId, userId)
-			if errInAppDelete != nil {
-				errResp = multierror.Append(errResp, fmt.Errorf("%s : %w", APP_DELETE_FAILED_RESP, errInAppDelete))
-			}
-			common.WriteJsonResp(w, errResp, nil, statusCode)
-			return
-		}
+	//get/build global secrets starts
+	globalSecretsResp, err, statusCode := handler.buildAppGlobalSecrets(appId)
+	if err != nil {
+		return nil, err, statusCode
 	}
-	//creating environment override ends
+	//get/build global secrets ends
 
-	common.WriteJsonResp(w, nil, APP_CREATE_SUCCESSFUL_RESP, http.StatusOK)
-}
+	//get/build environment override starts
+	environmentOverrides, err, statusCode := handler.buildEnvironmentOverrides(appId, token)
+	if err != nil {
+		return nil, err, statusCode
+	}
+	//get/build environment override ends
 
-//GetApp related methods starts
+	//build full object for response
+	appDetail := &appBean.AppDetail{
+		Metadata:                 appMetadataResp,
+		GitMaterials:             gitMaterialsResp,
+		DockerConfig:             dockerConfig,
+		GlobalDeploymentTemplate: globalDeploymentTemplateResp,
+		AppWorkflows:             appWorkflows,
+		GlobalConfigMaps:         globalConfigMapsResp,
+		GlobalSecrets:            globalSecretsResp,
+		EnvironmentOverrides:     environmentOverrides,
+	}
+	//end
+
+	return appDetail, nil, http.StatusOK
+}
 
 //get/build app metadata
 func (handler CoreAppRestHandlerImpl) buildAppMetadata(appId int) (*appBean.AppMetadata, error, int) {
@@ -1062,6 +956,123 @@ func (handler CoreAppRestHandlerImpl) buildEnvironmentOverrides(appId int, token
 
 //Create App related methods starts
 
+//create app with all of its components, app is deleted if any of the components fails
+func (handler CoreAppRestHandlerImpl) createAppWithComponents(ctx context.Context, createAppRequest *appBean.AppDetail, userId int32, token string) (int, error, int) {
+	handler.logger.Infow("creating app v2", "createAppRequest", createAppRequest)
+
+	//creating blank app starts
+	createBlankAppResp, err, statusCode := handler.createBlankApp(createAppRequest.Metadata, userId)
+	if err != nil {
+		return 0, err, statusCode
+	}
+	//creating blank app ends
+
+	//declaring appId for creating other components of app
+	appId := createBlankAppResp.Id
+
+	var errResp *multierror.Error
+
+	//creating git material starts
+	if createAppRequest.GitMaterials != nil {
+		err, statusCode = handler.createGitMaterials(appId, createAppRequest.GitMaterials, userId)
+		if err != nil {
+			errResp = multierror.Append(errResp, err)
+			errInAppDelete := handler.deleteApp(ctx, appId, userId)
+			if errInAppDelete != nil {
+				errResp = multierror.Append(errResp, fmt.Errorf("%s : %w", APP_DELETE_FAILED_RESP, errInAppDelete))
+			}
+			return 0, errResp, statusCode
+		}
+	}
+	//creating git material ends
+
+	//creating docker config
+	if createAppRequest.DockerConfig != nil {
+		err, statusCode = handler.createDockerConfig(appId, createAppRequest.DockerConfig, userId)
+		if err != nil {
+			errResp = multierror.Append(errResp, err)
+			errInAppDelete := handler.deleteApp(ctx, appId, userId)
+			if errInAppDelete != nil {
+				errResp = multierror.Append(errResp, fmt.Errorf("%s : %w", APP_DELETE_FAILED_RESP, errInAppDelete))
+			}
+			return 0, errResp, statusCode
+		}
+	}
+	//creating docker config ends
+
+	//creating deployment template starts
+	if createAppRequest.GlobalDeploymentTemplate != nil {
+		err, statusCode = handler.createDeploymentTemplate(ctx, appId, createAppRequest.GlobalDeploymentTemplate, userId)
+		if err != nil {
+			errResp = multierror.Append(errResp, err)
+			errInAppDelete := handler.deleteApp(ctx, appId, userId)
+			if errInAppDelete != nil {
+				errResp = multierror.Append(errResp, fmt.Errorf("%s : %w", APP_DELETE_FAILED_RESP, errInAppDelete))
+			}
+			return 0, errResp, statusCode
+		}
+	}
+	//creating deployment template ends
+
+	//creating global configMaps starts
+	if createAppRequest.GlobalConfigMaps != nil {
+		err, statusCode = handler.createGlobalConfigMaps(appId, userId, createAppRequest.GlobalConfigMaps)
+		if err != nil {
+			errResp = multierror.Append(errResp, err)
+			errInAppDelete := handler.deleteApp(ctx, appId, userId)
+			if errInAppDelete != nil {
+				errResp = multierror.Append(errResp, fmt.Errorf("%s : %w", APP_DELETE_FAILED_RESP, errInAppDelete))
+			}
+			return 0, errResp, statusCode
+		}
+	}
+	//creating global configMaps ends
+
+	//creating global secrets starts
+	if createAppRequest.GlobalSecrets != nil {
+		err, statusCode = handler.createGlobalSecrets(appId, userId, createAppRequest.GlobalSecrets)
+		if err != nil {
+			errResp = multierror.Append(errResp, err)
+			errInAppDelete := handler.deleteApp(ctx, appId, userId)
+			if errInAppDelete != nil {
+				errResp = multierror.Append(errResp, fmt.Errorf("%s : %w", APP_DELETE_FAILED_RESP, errInAppDelete))
+			}
+			return 0, errResp, statusCode
+		}
+	}
+	//creating global secrets ends
+
+	//creating workflow starts
+	if createAppRequest.AppWorkflows != nil {
+		err, statusCode = handler.createWorkflows(ctx, appId, userId, createAppRequest.AppWorkflows, token, createAppRequest.Metadata.AppName)
+		if err != nil {
+			errResp = multierror.Append(errResp, err)
+			errInAppDelete := handler.deleteApp(ctx, appId, userId)
+			if errInAppDelete != nil {
+				errResp = multierror.Append(errResp, fmt.Errorf("%s : %w", APP_DELETE_FAILED_RESP, errInAppDelete))
+			}
+			return 0, errResp, statusCode
+		}
+	}
+	//creating workflow ends
+
+	//creating environment override starts
+	if createAppRequest.EnvironmentOverrides != nil {
+		err, statusCode = handler.createEnvOverrides(ctx, appId, userId, createAppRequest.EnvironmentOverrides, token)
+		if err != nil {
+			errResp = multierror.Append(errResp, err)
+			errInAppDelete := handler.deleteApp(ctx, appId, userId)
+			if errInAppDelete != nil {
+				errResp = multierror.Append(errResp, fmt.Errorf("%s : %w", APP_DELETE_FAILED_RESP, errInAppDelete))
+			}
+			return 0, errResp, statusCode
+		}
+	}
+	//creating environment override ends
+
+	return appId, nil, http.StatusOK
+}
+
 //create a blank app with metadata
 func (handler CoreAppRestHandlerImpl) createBlankApp(appMetadata *appBean.AppMetadata, userId int32) (*bean.CreateAppDTO, error, int) {
 	handler.logger.Infow("Create App - creating blank app", "appMetadata", appMetadata)
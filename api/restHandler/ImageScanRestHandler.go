@@ -31,6 +31,7 @@ import (
 	"go.uber.org/zap"
 	"net/http"
 	"strconv"
+	"strings"
 )
 
 type ImageScanRestHandler interface {
@@ -38,6 +39,8 @@ type ImageScanRestHandler interface {
 	FetchExecutionDetail(w http.ResponseWriter, r *http.Request)
 	FetchMinScanResultByAppIdAndEnvId(w http.ResponseWriter, r *http.Request)
 	VulnerabilityExposure(w http.ResponseWriter, r *http.Request)
+	ExportVulnerabilityReport(w http.ResponseWriter, r *http.Request)
+	GetSecurityPosture(w http.ResponseWriter, r *http.Request)
 }
 
 type ImageScanRestHandlerImpl struct {
@@ -326,3 +329,189 @@ func (impl ImageScanRestHandlerImpl) VulnerabilityExposure(w http.ResponseWriter
 	results.VulnerabilityExposure = vulnerabilityExposure
 	common.WriteJsonResp(w, err, results, http.StatusOK)
 }
+
+// ExportVulnerabilityReport downloads a scan, or current exposure of an app on an environment, as sarif, csv or json
+func (impl ImageScanRestHandlerImpl) ExportVulnerabilityReport(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	v := r.URL.Query()
+	ids := make(map[string]int)
+	for _, key := range []string{"imageScanDeployInfoId", "artifactId", "appId", "envId"} {
+		if len(v.Get(key)) == 0 {
+			continue
+		}
+		ids[key], err = strconv.Atoi(v.Get(key))
+		if err != nil {
+			impl.logger.Errorw("request err, ExportVulnerabilityReport", "err", err, key, v.Get(key))
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	request := &security.VulnerabilityReportRequest{
+		ImageScanDeployInfoId: ids["imageScanDeployInfoId"],
+		ArtifactId:            ids["artifactId"],
+		AppId:                 ids["appId"],
+		EnvId:                 ids["envId"],
+	}
+	if severities := v.Get("severity"); len(severities) > 0 {
+		request.Severities = strings.Split(severities, ",")
+	}
+	request.Package = v.Get("package")
+	if fixAvailable := v.Get("fixAvailable"); len(fixAvailable) > 0 {
+		fix, err := strconv.ParseBool(fixAvailable)
+		if err != nil {
+			impl.logger.Errorw("request err, ExportVulnerabilityReport", "err", err, "fixAvailable", fixAvailable)
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+		request.FixAvailable = &fix
+	}
+	format := security.VulnerabilityReportFormat(strings.ToLower(v.Get("format")))
+	if len(format) == 0 {
+		format = security.VulnerabilityReportFormatJson
+	} else if format != security.VulnerabilityReportFormatSarif && format != security.VulnerabilityReportFormatCsv && format != security.VulnerabilityReportFormatJson {
+		common.WriteJsonResp(w, fmt.Errorf("unsupported report format %s", format), nil, http.StatusBadRequest)
+		return
+	}
+
+	report, err := impl.imageScanService.ExportVulnerabilityReport(request)
+	if err != nil {
+		impl.logger.Errorw("service err, ExportVulnerabilityReport", "err", err, "request", request)
+		if util.IsErrNoRows(err) {
+			common.WriteJsonResp(w, err, nil, http.StatusNotFound)
+		} else {
+			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		}
+		return
+	}
+	//RBAC
+	token := r.Header.Get("token")
+	appId, envId := report.Target.AppId, report.Target.EnvId
+	if appId == 0 {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, impl.enforcerUtil.GetAppRBACNameByAppId(appId)); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	if envId > 0 {
+		if ok := impl.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionGet, impl.enforcerUtil.GetEnvRBACNameByAppId(appId, envId)); !ok {
+			common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+			return
+		}
+	}
+	//RBAC
+
+	content, contentType, extension, err := security.RenderVulnerabilityReport(report, format)
+	if err != nil {
+		impl.logger.Errorw("error in rendering vulnerability report", "err", err, "format", format)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", "attachment; filename=vulnerability-report."+extension)
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(content)
+	if err != nil {
+		impl.logger.Errorw("error in writing vulnerability report", "err", err)
+	}
+}
+
+func (impl ImageScanRestHandlerImpl) GetSecurityPosture(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	v := r.URL.Query()
+	request := &security.SecurityPostureRequest{ScopeType: security2.PostureScopeType(strings.ToLower(v.Get("scopeType")))}
+	if days := v.Get("days"); len(days) > 0 {
+		request.Days, err = strconv.Atoi(days)
+		if err != nil {
+			impl.logger.Errorw("request err, GetSecurityPosture", "err", err, "days", days)
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	if scopeIds := v.Get("scopeIds"); len(scopeIds) > 0 {
+		for _, item := range strings.Split(scopeIds, ",") {
+			scopeId, err := strconv.Atoi(item)
+			if err != nil {
+				impl.logger.Errorw("request err, GetSecurityPosture", "err", err, "scopeIds", scopeIds)
+				common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+				return
+			}
+			request.ScopeIds = append(request.ScopeIds, scopeId)
+		}
+	}
+	response, err := impl.imageScanService.GetSecurityPosture(request)
+	if err != nil {
+		impl.logger.Errorw("service err, GetSecurityPosture", "err", err, "request", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	//RBAC - super admin sees every scope, others see the apps, teams, environments and clusters they have access to
+	isSuperAdmin, err := impl.userService.IsSuperAdmin(int(userId))
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if !isSuperAdmin {
+		emailId, err := impl.userService.GetEmailFromToken(r.Header.Get("token"))
+		if err != nil {
+			common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+			return
+		}
+		objects := make(map[int]string)
+		resource := casbin.ResourceApplications
+		switch request.ScopeType {
+		case security2.PostureScopeApp:
+			objects = impl.enforcerUtil.GetRbacObjectsForAllApps()
+		case security2.PostureScopeTeam:
+			resource = casbin.ResourceTeam
+			for _, scope := range response.Scopes {
+				objects[scope.ScopeId] = strings.ToLower(scope.ScopeName)
+			}
+		case security2.PostureScopeEnvironment, security2.PostureScopeCluster:
+			environments, err := impl.environmentService.GetAllActive()
+			if err != nil {
+				impl.logger.Errorw("service err, GetSecurityPosture", "err", err)
+				common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+				return
+			}
+			//environment posture covers all apps of environment, cluster posture is seen by cluster viewers
+			if request.ScopeType == security2.PostureScopeEnvironment {
+				resource = casbin.ResourceEnvironment
+				for _, environment := range environments {
+					objects[environment.Id] = fmt.Sprintf("%s/*", strings.ToLower(environment.EnvironmentIdentifier))
+				}
+			} else {
+				resource = casbin.ResourceCluster
+				for _, environment := range environments {
+					objects[environment.ClusterId] = strings.ToLower(environment.ClusterName)
+				}
+			}
+		default:
+			common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+			return
+		}
+		var objectArray []string
+		for _, scope := range response.Scopes {
+			objectArray = append(objectArray, objects[scope.ScopeId])
+		}
+		resultMap := impl.enforcer.EnforceByEmailInBatch(emailId, resource, casbin.ActionGet, objectArray)
+		authorizedScopes := make([]*security.ScopeSecurityPosture, 0)
+		for _, scope := range response.Scopes {
+			if object, ok := objects[scope.ScopeId]; ok && resultMap[object] {
+				authorizedScopes = append(authorizedScopes, scope)
+			}
+		}
+		response.Scopes = authorizedScopes
+	}
+	//RBAC
+	common.WriteJsonResp(w, nil, response, http.StatusOK)
+}
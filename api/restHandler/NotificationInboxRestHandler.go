@@ -0,0 +1,177 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package restHandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/api/sse"
+	client "github.com/devtron-labs/devtron/client/events"
+	"github.com/devtron-labs/devtron/pkg/notifier"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"go.uber.org/zap"
+)
+
+type NotificationInboxRestHandler interface {
+	GetInbox(w http.ResponseWriter, r *http.Request)
+	MarkInboxRead(w http.ResponseWriter, r *http.Request)
+	GetInboxPreferences(w http.ResponseWriter, r *http.Request)
+	UpdateInboxPreferences(w http.ResponseWriter, r *http.Request)
+	SubscribeInbox(w http.ResponseWriter, r *http.Request)
+}
+
+type NotificationInboxRestHandlerImpl struct {
+	logger       *zap.SugaredLogger
+	userService  user.UserService
+	inboxService notifier.NotificationInboxService
+	sse          *sse.SSE
+}
+
+func NewNotificationInboxRestHandlerImpl(logger *zap.SugaredLogger, userService user.UserService,
+	inboxService notifier.NotificationInboxService, sse *sse.SSE) *NotificationInboxRestHandlerImpl {
+	return &NotificationInboxRestHandlerImpl{
+		logger:       logger,
+		userService:  userService,
+		inboxService: inboxService,
+		sse:          sse,
+	}
+}
+
+// GetInbox lists inbox of logged in user, newest first, with unreadOnly, offset and size query params
+func (impl NotificationInboxRestHandlerImpl) GetInbox(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	v := r.URL.Query()
+	unreadOnly := v.Get("unreadOnly") == "true"
+	offset, size := 0, 20
+	intParams := map[string]*int{"offset": &offset, "size": &size}
+	for param, value := range intParams {
+		if len(v.Get(param)) == 0 {
+			continue
+		}
+		*value, err = strconv.Atoi(v.Get(param))
+		if err != nil {
+			impl.logger.Errorw("request err, GetInbox", "err", err, param, v.Get(param))
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	inbox, err := impl.inboxService.FindInboxItems(userId, unreadOnly, offset, size)
+	if err != nil {
+		impl.logger.Errorw("service err, GetInbox", "err", err, "userId", userId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, inbox, http.StatusOK)
+}
+
+func (impl NotificationInboxRestHandlerImpl) MarkInboxRead(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request notifier.NotificationInboxReadRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		impl.logger.Errorw("request err, MarkInboxRead", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	count, err := impl.inboxService.MarkRead(userId, request.Ids)
+	if err != nil {
+		impl.logger.Errorw("service err, MarkInboxRead", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, map[string]int{"read": count}, http.StatusOK)
+}
+
+func (impl NotificationInboxRestHandlerImpl) GetInboxPreferences(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	emailId, err := impl.userService.GetEmailFromToken(r.Header.Get("token"))
+	if err != nil {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	preferences, err := impl.inboxService.GetPreferences(emailId)
+	if err != nil {
+		impl.logger.Errorw("service err, GetInboxPreferences", "err", err, "userId", userId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, preferences, http.StatusOK)
+}
+
+// UpdateInboxPreferences saves inbox preferences of logged in user in user attributes, events of apps the user cannot
+// view are not added to inbox whatever the preferences are
+func (impl NotificationInboxRestHandlerImpl) UpdateInboxPreferences(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	emailId, err := impl.userService.GetEmailFromToken(r.Header.Get("token"))
+	if err != nil {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	var preferences client.NotificationInboxPreferences
+	err = json.NewDecoder(r.Body).Decode(&preferences)
+	if err != nil {
+		impl.logger.Errorw("request err, UpdateInboxPreferences", "err", err, "payload", preferences)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	resp, err := impl.inboxService.UpdatePreferences(emailId, userId, &preferences)
+	if err != nil {
+		impl.logger.Errorw("service err, UpdateInboxPreferences", "err", err, "payload", preferences)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+// SubscribeInbox streams items added to inbox of logged in user as server sent events till the client disconnects
+func (impl NotificationInboxRestHandlerImpl) SubscribeInbox(w http.ResponseWriter, r *http.Request) {
+	sse.SubscribeHandler(impl.sse.Broker, impl.inboxNamespace, waitForInboxUnsubscribe).ServeHTTP(w, r)
+}
+
+func (impl NotificationInboxRestHandlerImpl) inboxNamespace(r *http.Request) (string, error) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		return "", errors.New("unauthorized user")
+	}
+	return client.NotificationInboxNamespace(userId), nil
+}
+
+// waitForInboxUnsubscribe keeps inbox stream open, items are pushed to the stream by the inbox writer
+func waitForInboxUnsubscribe(r *http.Request, receive <-chan int, send chan<- int) {
+	<-receive
+}
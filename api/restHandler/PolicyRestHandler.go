@@ -39,6 +39,12 @@ type PolicyRestHandler interface {
 	UpdatePolicy(w http.ResponseWriter, r *http.Request)
 	GetPolicy(w http.ResponseWriter, r *http.Request)
 	VerifyImage(w http.ResponseWriter, r *http.Request)
+	SavePolicyRule(w http.ResponseWriter, r *http.Request)
+	DeletePolicyRule(w http.ResponseWriter, r *http.Request)
+	SaveLicensePolicy(w http.ResponseWriter, r *http.Request)
+	DeleteLicensePolicy(w http.ResponseWriter, r *http.Request)
+	SaveManifestScanPolicy(w http.ResponseWriter, r *http.Request)
+	DeleteManifestScanPolicy(w http.ResponseWriter, r *http.Request)
 }
 type PolicyRestHandlerImpl struct {
 	logger             *zap.SugaredLogger
@@ -200,6 +206,298 @@ func (impl PolicyRestHandlerImpl) UpdatePolicy(w http.ResponseWriter, r *http.Re
 	common.WriteJsonResp(w, err, res, http.StatusOK)
 }
 
+func (impl PolicyRestHandlerImpl) SavePolicyRule(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var req bean.VulnerabilityPolicyRule
+	err = decoder.Decode(&req)
+	if err != nil {
+		impl.logger.Errorw("request err, SavePolicyRule", "err", err, "payload", req)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	impl.logger.Infow("request payload, SavePolicyRule", "payload", req)
+	err = security.ValidateVulnerabilityPolicyRule(&req)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	action := casbin.ActionCreate
+	appId, envId := req.AppId, req.EnvId
+	if req.Id > 0 {
+		rule, err := impl.policyService.GetPolicyRule(req.Id)
+		if err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+			return
+		}
+		action = casbin.ActionUpdate
+		appId, envId = rule.AppId, rule.EnvironmentId
+	}
+	//AUTH - check from casbin db
+	if ok, err := impl.isPolicyRuleAuthorized(r.Header.Get("token"), userId, appId, envId, action); err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	} else if !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	//AUTH
+
+	res, err := impl.policyService.SavePolicyRule(&req, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, SavePolicyRule", "err", err, "payload", req)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl PolicyRestHandlerImpl) DeletePolicyRule(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var req bean.DeletePolicyParams
+	err = decoder.Decode(&req)
+	if err != nil {
+		impl.logger.Errorw("request err, DeletePolicyRule", "err", err, "payload", req)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	impl.logger.Infow("request payload, DeletePolicyRule", "payload", req)
+	rule, err := impl.policyService.GetPolicyRule(req.Id)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	//AUTH - check from casbin db
+	if ok, err := impl.isPolicyRuleAuthorized(r.Header.Get("token"), userId, rule.AppId, rule.EnvironmentId, casbin.ActionDelete); err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	} else if !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	//AUTH
+
+	res, err := impl.policyService.DeletePolicyRule(req.Id, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, DeletePolicyRule", "err", err, "payload", req)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl PolicyRestHandlerImpl) SaveLicensePolicy(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var req bean.LicensePolicy
+	err = decoder.Decode(&req)
+	if err != nil {
+		impl.logger.Errorw("request err, SaveLicensePolicy", "err", err, "payload", req)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	impl.logger.Infow("request payload, SaveLicensePolicy", "payload", req)
+	err = security.ValidateLicensePolicy(&req)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	action := casbin.ActionCreate
+	appId, envId := req.AppId, req.EnvId
+	if req.Id > 0 {
+		policy, err := impl.policyService.GetLicensePolicy(req.Id)
+		if err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+			return
+		}
+		action = casbin.ActionUpdate
+		appId, envId = policy.AppId, policy.EnvironmentId
+	}
+	//AUTH - check from casbin db
+	if ok, err := impl.isPolicyRuleAuthorized(r.Header.Get("token"), userId, appId, envId, action); err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	} else if !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	//AUTH
+
+	res, err := impl.policyService.SaveLicensePolicy(&req, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, SaveLicensePolicy", "err", err, "payload", req)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl PolicyRestHandlerImpl) DeleteLicensePolicy(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var req bean.DeletePolicyParams
+	err = decoder.Decode(&req)
+	if err != nil {
+		impl.logger.Errorw("request err, DeleteLicensePolicy", "err", err, "payload", req)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	impl.logger.Infow("request payload, DeleteLicensePolicy", "payload", req)
+	policy, err := impl.policyService.GetLicensePolicy(req.Id)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	//AUTH - check from casbin db
+	if ok, err := impl.isPolicyRuleAuthorized(r.Header.Get("token"), userId, policy.AppId, policy.EnvironmentId, casbin.ActionDelete); err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	} else if !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	//AUTH
+
+	res, err := impl.policyService.DeleteLicensePolicy(req.Id, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, DeleteLicensePolicy", "err", err, "payload", req)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl PolicyRestHandlerImpl) SaveManifestScanPolicy(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var req bean.ManifestScanPolicy
+	err = decoder.Decode(&req)
+	if err != nil {
+		impl.logger.Errorw("request err, SaveManifestScanPolicy", "err", err, "payload", req)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	impl.logger.Infow("request payload, SaveManifestScanPolicy", "payload", req)
+	err = security.ValidateManifestScanPolicy(&req)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	action := casbin.ActionCreate
+	appId, envId := req.AppId, req.EnvId
+	if req.Id > 0 {
+		policy, err := impl.policyService.GetManifestScanPolicy(req.Id)
+		if err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+			return
+		}
+		action = casbin.ActionUpdate
+		appId, envId = policy.AppId, policy.EnvironmentId
+	}
+	//AUTH - check from casbin db
+	if ok, err := impl.isPolicyRuleAuthorized(r.Header.Get("token"), userId, appId, envId, action); err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	} else if !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	//AUTH
+
+	res, err := impl.policyService.SaveManifestScanPolicy(&req, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, SaveManifestScanPolicy", "err", err, "payload", req)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl PolicyRestHandlerImpl) DeleteManifestScanPolicy(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var req bean.DeletePolicyParams
+	err = decoder.Decode(&req)
+	if err != nil {
+		impl.logger.Errorw("request err, DeleteManifestScanPolicy", "err", err, "payload", req)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	impl.logger.Infow("request payload, DeleteManifestScanPolicy", "payload", req)
+	policy, err := impl.policyService.GetManifestScanPolicy(req.Id)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	//AUTH - check from casbin db
+	if ok, err := impl.isPolicyRuleAuthorized(r.Header.Get("token"), userId, policy.AppId, policy.EnvironmentId, casbin.ActionDelete); err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	} else if !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	//AUTH
+
+	res, err := impl.policyService.DeleteManifestScanPolicy(req.Id, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, DeleteManifestScanPolicy", "err", err, "payload", req)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+// isPolicyRuleAuthorized applies the access checks of policies, app env rules need app and env access, env rules need
+// global env access and cluster or global rules need super admin
+func (impl PolicyRestHandlerImpl) isPolicyRuleAuthorized(token string, userId int32, appId, envId int, action string) (bool, error) {
+	if appId > 0 && envId > 0 {
+		object := impl.enforcerUtil.GetAppRBACNameByAppId(appId)
+		if ok := impl.enforcer.Enforce(token, casbin.ResourceApplications, action, object); !ok {
+			return false, nil
+		}
+		object = impl.enforcerUtil.GetEnvRBACNameByAppId(appId, envId)
+		return impl.enforcer.Enforce(token, casbin.ResourceEnvironment, action, object), nil
+	} else if appId == 0 && envId > 0 {
+		return impl.enforcer.Enforce(token, casbin.ResourceGlobalEnvironment, action, "*"), nil
+	}
+	roles, err := impl.userService.CheckUserRoles(userId)
+	if err != nil {
+		return false, err
+	}
+	for _, item := range roles {
+		if item == bean.SUPERADMIN {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func (impl PolicyRestHandlerImpl) GetPolicy(w http.ResponseWriter, r *http.Request) {
 	userId, err := impl.userService.GetLoggedInUser(r)
 	if userId == 0 || err != nil {
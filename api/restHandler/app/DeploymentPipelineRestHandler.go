@@ -730,6 +730,10 @@ func (handler PipelineConfigRestHandlerImpl) GetArtifactsByCDPipeline(w http.Res
 		if err != nil {
 			handler.Logger.Errorw("service err, GetArtifactsByCDPipeline", "err", err, "cdPipelineId", cdPipelineId, "stage", stage)
 		}
+		rules, err := handler.policyService.GetApplicableRules(pipelineModel.Environment.ClusterId, pipelineModel.EnvironmentId, pipelineModel.AppId, pipelineModel.App.AppStore)
+		if err != nil {
+			handler.Logger.Errorw("service err, GetArtifactsByCDPipeline", "err", err, "cdPipelineId", cdPipelineId, "stage", stage)
+		}
 
 		// get image scan results from DB for given digests
 		imageScanResults, err := handler.scanResultRepository.FindByImageDigests(digests)
@@ -757,7 +761,7 @@ func (handler PipelineConfigRestHandlerImpl) GetArtifactsByCDPipeline(w http.Res
 			for _, item := range scanResults {
 				cveStores = append(cveStores, &item.CveStore)
 			}
-			vulnerableMap[digest] = handler.policyService.HasBlockedCVE(cveStores, cvePolicy, severityPolicy)
+			vulnerableMap[digest] = handler.policyService.HasBlockedCVE(cveStores, cvePolicy, severityPolicy, rules)
 		}
 
 		var ciArtifactsFinal []bean.CiArtifactBean
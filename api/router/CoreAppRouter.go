@@ -38,4 +38,6 @@ func NewCoreAppRouterImpl(restHandler restHandler.CoreAppRestHandler) *CoreAppRo
 func (router CoreAppRouterImpl) initCoreAppRouter(configRouter *mux.Router) {
 	configRouter.Path("/v1beta1/application").HandlerFunc(router.restHandler.CreateApp).Methods("POST")
 	configRouter.Path("/v1beta1/application/{appId}").HandlerFunc(router.restHandler.GetAppAllDetail).Methods("GET")
+	configRouter.Path("/v1beta1/application/{appId}/export").HandlerFunc(router.restHandler.ExportAppDefinition).Methods("GET")
+	configRouter.Path("/v1beta1/application/apply").HandlerFunc(router.restHandler.ApplyAppDefinition).Methods("POST")
 }
@@ -42,4 +42,10 @@ func (impl ImageScanRouterImpl) InitImageScanRouter(configRouter *mux.Router) {
 
 	configRouter.Path("/cve/exposure").HandlerFunc(impl.imageScanRestHandler.VulnerabilityExposure).Methods("POST")
 
+	//format=sarif&imageScanDeployInfoId=10&severity=critical,moderate&package=openssl&fixAvailable=true
+	configRouter.Path("/export").HandlerFunc(impl.imageScanRestHandler.ExportVulnerabilityReport).Methods("GET")
+
+	//scopeType=team&scopeIds=1,2&days=30
+	configRouter.Path("/posture").HandlerFunc(impl.imageScanRestHandler.GetSecurityPosture).Methods("GET")
+
 }
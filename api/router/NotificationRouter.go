@@ -26,11 +26,13 @@ type NotificationRouter interface {
 	InitNotificationRegRouter(gocdRouter *mux.Router)
 }
 type NotificationRouterImpl struct {
-	notificationRestHandler restHandler.NotificationRestHandler
+	notificationRestHandler      restHandler.NotificationRestHandler
+	notificationInboxRestHandler restHandler.NotificationInboxRestHandler
 }
 
-func NewNotificationRouterImpl(notificationRestHandler restHandler.NotificationRestHandler) *NotificationRouterImpl {
-	return &NotificationRouterImpl{notificationRestHandler: notificationRestHandler}
+func NewNotificationRouterImpl(notificationRestHandler restHandler.NotificationRestHandler,
+	notificationInboxRestHandler restHandler.NotificationInboxRestHandler) *NotificationRouterImpl {
+	return &NotificationRouterImpl{notificationRestHandler: notificationRestHandler, notificationInboxRestHandler: notificationInboxRestHandler}
 }
 func (impl NotificationRouterImpl) InitNotificationRegRouter(configRouter *mux.Router) {
 	configRouter.Path("").
@@ -63,6 +65,9 @@ func (impl NotificationRouterImpl) InitNotificationRegRouter(configRouter *mux.R
 	configRouter.Path("/channel/smtp/{id}").
 		HandlerFunc(impl.notificationRestHandler.FindSMTPConfig).
 		Methods("GET")
+	configRouter.Path("/channel/webhook/{id}").
+		HandlerFunc(impl.notificationRestHandler.FindWebhookConfig).
+		Methods("GET")
 	configRouter.Path("/channel").
 		HandlerFunc(impl.notificationRestHandler.DeleteNotificationChannelConfig).
 		Methods("DELETE")
@@ -78,4 +83,27 @@ func (impl NotificationRouterImpl) InitNotificationRegRouter(configRouter *mux.R
 		HandlerFunc(impl.notificationRestHandler.GetOptionsForNotificationSettings).
 		Methods("POST")
 
+	configRouter.Path("/delivery").
+		HandlerFunc(impl.notificationRestHandler.GetNotificationDeliveries).
+		Methods("GET")
+	configRouter.Path("/delivery/replay/{eventId}").
+		HandlerFunc(impl.notificationRestHandler.ReplayNotificationEvent).
+		Methods("PUT")
+
+	configRouter.Path("/inbox").
+		HandlerFunc(impl.notificationInboxRestHandler.GetInbox).
+		Methods("GET")
+	configRouter.Path("/inbox/read").
+		HandlerFunc(impl.notificationInboxRestHandler.MarkInboxRead).
+		Methods("PUT")
+	configRouter.Path("/inbox/preferences").
+		HandlerFunc(impl.notificationInboxRestHandler.GetInboxPreferences).
+		Methods("GET")
+	configRouter.Path("/inbox/preferences").
+		HandlerFunc(impl.notificationInboxRestHandler.UpdateInboxPreferences).
+		Methods("PUT")
+	configRouter.Path("/inbox/stream").
+		HandlerFunc(impl.notificationInboxRestHandler.SubscribeInbox).
+		Methods("GET")
+
 }
@@ -37,6 +37,12 @@ func NewPolicyRouterImpl(policyRestHandler restHandler.PolicyRestHandler) *Polic
 func (impl PolicyRouterImpl) InitPolicyRouter(configRouter *mux.Router) {
 	configRouter.Path("/save").HandlerFunc(impl.policyRestHandler.SavePolicy).Methods("POST")
 	configRouter.Path("/update").HandlerFunc(impl.policyRestHandler.UpdatePolicy).Methods("POST")
+	configRouter.Path("/rule/save").HandlerFunc(impl.policyRestHandler.SavePolicyRule).Methods("POST")
+	configRouter.Path("/rule/delete").HandlerFunc(impl.policyRestHandler.DeletePolicyRule).Methods("POST")
+	configRouter.Path("/license/save").HandlerFunc(impl.policyRestHandler.SaveLicensePolicy).Methods("POST")
+	configRouter.Path("/license/delete").HandlerFunc(impl.policyRestHandler.DeleteLicensePolicy).Methods("POST")
+	configRouter.Path("/manifest/save").HandlerFunc(impl.policyRestHandler.SaveManifestScanPolicy).Methods("POST")
+	configRouter.Path("/manifest/delete").HandlerFunc(impl.policyRestHandler.DeleteManifestScanPolicy).Methods("POST")
 	configRouter.Path("/list").HandlerFunc(impl.policyRestHandler.GetPolicy).Methods("GET")
 	configRouter.Path("/verify/webhook").HandlerFunc(impl.policyRestHandler.VerifyImage).Methods("POST")
 }
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 
 	v1alpha12 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/gitops-engine/pkg/health"
 	"github.com/devtron-labs/devtron/client/pubsub"
 	"github.com/devtron-labs/devtron/pkg/app"
 	"github.com/devtron-labs/devtron/pkg/appStore/deployment/service"
@@ -41,16 +42,19 @@ type ApplicationStatusUpdateHandlerImpl struct {
 	appService          app.AppService
 	workflowDagExecutor pipeline.WorkflowDagExecutor
 	installedAppService service.InstalledAppService
+	autoRollbackService pipeline.AutoRollbackService
 }
 
 func NewApplicationStatusUpdateHandlerImpl(logger *zap.SugaredLogger, pubsubClient *pubsub.PubSubClient, appService app.AppService,
-	workflowDagExecutor pipeline.WorkflowDagExecutor, installedAppService service.InstalledAppService) *ApplicationStatusUpdateHandlerImpl {
+	workflowDagExecutor pipeline.WorkflowDagExecutor, installedAppService service.InstalledAppService,
+	autoRollbackService pipeline.AutoRollbackService) *ApplicationStatusUpdateHandlerImpl {
 	appStatusUpdateHandlerImpl := &ApplicationStatusUpdateHandlerImpl{
 		logger:              logger,
 		pubsubClient:        pubsubClient,
 		appService:          appService,
 		workflowDagExecutor: workflowDagExecutor,
 		installedAppService: installedAppService,
+		autoRollbackService: autoRollbackService,
 	}
 	err := util.AddStream(appStatusUpdateHandlerImpl.pubsubClient.JetStrCtxt, util.KUBEWATCH_STREAM)
 	if err != nil {
@@ -119,6 +123,13 @@ func (impl *ApplicationStatusUpdateHandlerImpl) Subscribe() error {
 				impl.logger.Errorw("deployment success event error", "gitHash", gitHash, "err", err)
 				return
 			}
+		} else if newApp.Status.Health.Status == health.HealthStatusDegraded {
+			// rolls back the degraded deployment if its pipeline has opted in for auto rollback
+			err = impl.autoRollbackService.HandleDegradedArgoApp(newApp.Status.Sync.Revision)
+			if err != nil {
+				impl.logger.Errorw("error in handling auto rollback for degraded app", "app", newApp.Name, "err", err)
+				return
+			}
 		}
 		impl.logger.Debugw("application status update completed", "app", newApp.Name)
 	}, nats.Durable(util.APPLICATION_STATUS_UPDATE_DURABLE), nats.DeliverLast(), nats.ManualAck(), nats.BindStream(util.KUBEWATCH_STREAM))
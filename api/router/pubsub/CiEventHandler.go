@@ -52,6 +52,7 @@ type CiCompleteEvent struct {
 	PipelineName     string                      `json:"pipelineName"`
 	DataSource       string                      `json:"dataSource"`
 	MaterialType     string                      `json:"materialType" validate:"required"`
+	SbomDigest       string                      `json:"sbomDigest,omitempty"` //sha256 digest of sbom uploaded to sbomLocation of workflow request
 }
 
 func NewCiEventHandlerImpl(logger *zap.SugaredLogger, pubsubClient *pubsub.PubSubClient, webhookService pipeline.WebhookService) *CiEventHandlerImpl {
@@ -164,6 +165,7 @@ func (impl *CiEventHandlerImpl) BuildCiArtifactRequest(event CiCompleteEvent) (*
 		MaterialInfo: rawMaterialInfo,
 		UserId:       event.TriggeredBy,
 		WorkflowId:   event.WorkflowId,
+		SbomDigest:   event.SbomDigest,
 	}
 	return request, nil
 }
@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pubsub
+
+import (
+	"encoding/json"
+
+	"github.com/devtron-labs/devtron/client/pubsub"
+	"github.com/devtron-labs/devtron/pkg/secretScan"
+	"github.com/devtron-labs/devtron/util"
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+)
+
+type SecretScanEventHandler interface {
+	Subscribe() error
+}
+
+type SecretScanEventHandlerImpl struct {
+	logger            *zap.SugaredLogger
+	pubsubClient      *pubsub.PubSubClient
+	secretScanService secretScan.SecretScanService
+}
+
+func NewSecretScanEventHandlerImpl(logger *zap.SugaredLogger, pubsubClient *pubsub.PubSubClient, secretScanService secretScan.SecretScanService) *SecretScanEventHandlerImpl {
+	secretScanEventHandlerImpl := &SecretScanEventHandlerImpl{
+		logger:            logger,
+		pubsubClient:      pubsubClient,
+		secretScanService: secretScanService,
+	}
+	err := util.AddStream(secretScanEventHandlerImpl.pubsubClient.JetStrCtxt, util.CI_RUNNER_STREAM)
+	if err != nil {
+		logger.Error(err)
+		return nil
+	}
+	err = secretScanEventHandlerImpl.Subscribe()
+	if err != nil {
+		logger.Error(err)
+		return nil
+	}
+	return secretScanEventHandlerImpl
+}
+
+func (impl *SecretScanEventHandlerImpl) Subscribe() error {
+	_, err := impl.pubsubClient.JetStrCtxt.QueueSubscribe(util.CI_SECRET_SCAN_TOPIC, util.CI_SECRET_SCAN_GROUP, func(msg *nats.Msg) {
+		impl.logger.Debug("secret scan event received")
+		defer msg.Ack()
+		secretScanEvent := &secretScan.SecretScanEvent{}
+		err := json.Unmarshal(msg.Data, secretScanEvent)
+		if err != nil {
+			impl.logger.Errorw("error while unmarshalling secret scan event", "err", err)
+			return
+		}
+		err = impl.secretScanService.SaveScanResult(secretScanEvent)
+		if err != nil {
+			impl.logger.Errorw("error in saving secret scan result", "err", err, "ciWorkflowId", secretScanEvent.WorkflowId)
+		}
+	}, nats.Durable(util.CI_SECRET_SCAN_DURABLE), nats.DeliverLast(), nats.ManualAck(), nats.BindStream(util.CI_RUNNER_STREAM))
+	if err != nil {
+		impl.logger.Error(err)
+		return err
+	}
+	return nil
+}
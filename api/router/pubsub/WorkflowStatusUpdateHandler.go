@@ -44,10 +44,12 @@ type WorkflowStatusUpdateHandlerImpl struct {
 	eventFactory         client.EventFactory
 	eventClient          client.EventClient
 	cdWorkflowRepository pipelineConfig.CdWorkflowRepository
+	webhookService       pipeline.WebhookService
 }
 
 func NewWorkflowStatusUpdateHandlerImpl(logger *zap.SugaredLogger, pubsubClient *pubsub.PubSubClient, ciHandler pipeline.CiHandler, cdHandler pipeline.CdHandler,
-	eventFactory client.EventFactory, eventClient client.EventClient, cdWorkflowRepository pipelineConfig.CdWorkflowRepository) *WorkflowStatusUpdateHandlerImpl {
+	eventFactory client.EventFactory, eventClient client.EventClient, cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
+	webhookService pipeline.WebhookService) *WorkflowStatusUpdateHandlerImpl {
 	workflowStatusUpdateHandlerImpl := &WorkflowStatusUpdateHandlerImpl{
 		logger:               logger,
 		pubsubClient:         pubsubClient,
@@ -56,6 +58,7 @@ func NewWorkflowStatusUpdateHandlerImpl(logger *zap.SugaredLogger, pubsubClient
 		eventFactory:         eventFactory,
 		eventClient:          eventClient,
 		cdWorkflowRepository: cdWorkflowRepository,
+		webhookService:       webhookService,
 	}
 	err := util1.AddStream(workflowStatusUpdateHandlerImpl.pubsubClient.JetStrCtxt, util1.KUBEWATCH_STREAM)
 	if err != nil {
@@ -86,11 +89,19 @@ func (impl *WorkflowStatusUpdateHandlerImpl) Subscribe() error {
 			return
 		}
 
-		_, err = impl.ciHandler.UpdateWorkflow(wfStatus)
+		workflowId, err := impl.ciHandler.UpdateWorkflow(wfStatus)
 		if err != nil {
 			impl.logger.Errorw("error on update workflow status", "err", err, "msg", string(msg.Data))
 			return
 		}
+		if string(wfStatus.Phase) == string(v1alpha1.NodeSucceeded) {
+			//manifest list of a matrix build is only complete once every leg has succeeded
+			_, err = impl.webhookService.SaveCiBuildMatrixManifestArtifact(workflowId)
+			if err != nil {
+				impl.logger.Errorw("error on saving matrix build manifest artifact", "err", err, "workflowId", workflowId)
+				return
+			}
+		}
 	}, nats.Durable(util1.WORKFLOW_STATUS_UPDATE_DURABLE), nats.DeliverLast(), nats.ManualAck(), nats.BindStream(util1.KUBEWATCH_STREAM))
 
 	if err != nil {
@@ -26,11 +26,18 @@ import (
 	"github.com/devtron-labs/devtron/api/cluster"
 	"github.com/devtron-labs/devtron/api/dashboardEvent"
 	"github.com/devtron-labs/devtron/api/deployment"
+	"github.com/devtron-labs/devtron/api/deploymentApproval"
+	"github.com/devtron-labs/devtron/api/deploymentSchedule"
+	"github.com/devtron-labs/devtron/api/deploymentWindow"
 	"github.com/devtron-labs/devtron/api/externalLink"
 	client "github.com/devtron-labs/devtron/api/helm-app"
+	"github.com/devtron-labs/devtron/api/imageSignature"
+	"github.com/devtron-labs/devtron/api/manifestScan"
 	"github.com/devtron-labs/devtron/api/module"
 	"github.com/devtron-labs/devtron/api/restHandler/common"
 	"github.com/devtron-labs/devtron/api/router/pubsub"
+	"github.com/devtron-labs/devtron/api/sbom"
+	"github.com/devtron-labs/devtron/api/secretScan"
 	"github.com/devtron-labs/devtron/api/server"
 	"github.com/devtron-labs/devtron/api/sso"
 	"github.com/devtron-labs/devtron/api/team"
@@ -40,6 +47,7 @@ import (
 	"github.com/devtron-labs/devtron/client/dashboard"
 	pubsub2 "github.com/devtron-labs/devtron/client/pubsub"
 	"github.com/devtron-labs/devtron/client/telemetry"
+	deploymentSchedule2 "github.com/devtron-labs/devtron/pkg/deploymentSchedule"
 	"github.com/devtron-labs/devtron/pkg/terminal"
 	"github.com/devtron-labs/devtron/util"
 	"github.com/devtron-labs/devtron/util/k8s"
@@ -112,6 +120,23 @@ type MuxRouter struct {
 	helmApplicationStatusUpdateHandler cron.CdApplicationStatusUpdateHandler
 	k8sCapacityRouter                  k8s.K8sCapacityRouter
 	webhookHelmRouter                  webhookHelm.WebhookHelmRouter
+	deploymentWindowRouter             deploymentWindow.DeploymentWindowRouter
+	deploymentQueueHandler             cron.DeploymentQueueHandler
+	deploymentApprovalRouter           deploymentApproval.DeploymentApprovalRouter
+	ciScheduleHandler                  cron.CiScheduleHandler
+	deploymentScheduleRouter           deploymentSchedule.DeploymentScheduleRouter
+	deploymentScheduleCronService      deploymentSchedule2.DeploymentScheduleCronService
+	canaryAnalysisHandler              cron.CanaryAnalysisHandler
+	cveExceptionExpiryHandler          cron.CveExceptionExpiryHandler
+	imageRescanHandler                 cron.ImageRescanHandler
+	securityPostureHandler             cron.SecurityPostureHandler
+	sbomRouter                         sbom.SbomRouter
+	imageSignatureRouter               imageSignature.ImageSignatureRouter
+	manifestScanRouter                 manifestScan.ManifestScanRouter
+	secretScanRouter                   secretScan.SecretScanRouter
+	secretScanEventHandler             pubsub.SecretScanEventHandler
+	notificationOutboxHandler          cron.NotificationOutboxHandler
+	clusterUnreachableHandler          cron.ClusterUnreachableHandler
 }
 
 func NewMuxRouter(logger *zap.SugaredLogger, HelmRouter HelmRouter, PipelineConfigRouter PipelineConfigRouter,
@@ -137,7 +162,16 @@ func NewMuxRouter(logger *zap.SugaredLogger, HelmRouter HelmRouter, PipelineConf
 	commonDeploymentRouter appStoreDeployment.CommonDeploymentRouter, externalLinkRouter externalLink.ExternalLinkRouter,
 	globalPluginRouter GlobalPluginRouter, moduleRouter module.ModuleRouter,
 	serverRouter server.ServerRouter, apiTokenRouter apiToken.ApiTokenRouter,
-	helmApplicationStatusUpdateHandler cron.CdApplicationStatusUpdateHandler, k8sCapacityRouter k8s.K8sCapacityRouter, webhookHelmRouter webhookHelm.WebhookHelmRouter) *MuxRouter {
+	helmApplicationStatusUpdateHandler cron.CdApplicationStatusUpdateHandler, k8sCapacityRouter k8s.K8sCapacityRouter, webhookHelmRouter webhookHelm.WebhookHelmRouter,
+	deploymentWindowRouter deploymentWindow.DeploymentWindowRouter, deploymentQueueHandler cron.DeploymentQueueHandler,
+	deploymentApprovalRouter deploymentApproval.DeploymentApprovalRouter, ciScheduleHandler cron.CiScheduleHandler,
+	deploymentScheduleRouter deploymentSchedule.DeploymentScheduleRouter, deploymentScheduleCronService deploymentSchedule2.DeploymentScheduleCronService,
+	canaryAnalysisHandler cron.CanaryAnalysisHandler, cveExceptionExpiryHandler cron.CveExceptionExpiryHandler,
+	imageRescanHandler cron.ImageRescanHandler, sbomRouter sbom.SbomRouter,
+	imageSignatureRouter imageSignature.ImageSignatureRouter, securityPostureHandler cron.SecurityPostureHandler,
+	manifestScanRouter manifestScan.ManifestScanRouter, secretScanRouter secretScan.SecretScanRouter,
+	secretScanEventHandler pubsub.SecretScanEventHandler, notificationOutboxHandler cron.NotificationOutboxHandler,
+	clusterUnreachableHandler cron.ClusterUnreachableHandler) *MuxRouter {
 	r := &MuxRouter{
 		Router:                             mux.NewRouter(),
 		HelmRouter:                         HelmRouter,
@@ -201,6 +235,23 @@ func NewMuxRouter(logger *zap.SugaredLogger, HelmRouter HelmRouter, PipelineConf
 		helmApplicationStatusUpdateHandler: helmApplicationStatusUpdateHandler,
 		k8sCapacityRouter:                  k8sCapacityRouter,
 		webhookHelmRouter:                  webhookHelmRouter,
+		deploymentWindowRouter:             deploymentWindowRouter,
+		deploymentQueueHandler:             deploymentQueueHandler,
+		deploymentApprovalRouter:           deploymentApprovalRouter,
+		ciScheduleHandler:                  ciScheduleHandler,
+		deploymentScheduleRouter:           deploymentScheduleRouter,
+		deploymentScheduleCronService:      deploymentScheduleCronService,
+		canaryAnalysisHandler:              canaryAnalysisHandler,
+		cveExceptionExpiryHandler:          cveExceptionExpiryHandler,
+		imageRescanHandler:                 imageRescanHandler,
+		securityPostureHandler:             securityPostureHandler,
+		sbomRouter:                         sbomRouter,
+		imageSignatureRouter:               imageSignatureRouter,
+		manifestScanRouter:                 manifestScanRouter,
+		secretScanRouter:                   secretScanRouter,
+		secretScanEventHandler:             secretScanEventHandler,
+		notificationOutboxHandler:          notificationOutboxHandler,
+		clusterUnreachableHandler:          clusterUnreachableHandler,
 	}
 	return r
 }
@@ -398,4 +449,26 @@ func (r MuxRouter) Init() {
 	// webhook helm app router
 	webhookHelmRouter := r.Router.PathPrefix("/orchestrator/webhook/helm").Subrouter()
 	r.webhookHelmRouter.InitWebhookHelmRouter(webhookHelmRouter)
+
+	// deployment window router
+	deploymentWindowRouter := r.Router.PathPrefix("/orchestrator/deployment-window").Subrouter()
+	r.deploymentWindowRouter.InitDeploymentWindowRouter(deploymentWindowRouter)
+
+	deploymentApprovalRouter := r.Router.PathPrefix("/orchestrator/deployment-approval").Subrouter()
+	r.deploymentApprovalRouter.InitDeploymentApprovalRouter(deploymentApprovalRouter)
+
+	deploymentScheduleRouter := r.Router.PathPrefix("/orchestrator/deployment-schedule").Subrouter()
+	r.deploymentScheduleRouter.InitDeploymentScheduleRouter(deploymentScheduleRouter)
+
+	sbomRouter := r.Router.PathPrefix("/orchestrator/sbom").Subrouter()
+	r.sbomRouter.InitSbomRouter(sbomRouter)
+
+	imageSignatureRouter := r.Router.PathPrefix("/orchestrator/image-signature").Subrouter()
+	r.imageSignatureRouter.InitImageSignatureRouter(imageSignatureRouter)
+
+	manifestScanRouter := r.Router.PathPrefix("/orchestrator/manifest-scan").Subrouter()
+	r.manifestScanRouter.InitManifestScanRouter(manifestScanRouter)
+
+	secretScanRouter := r.Router.PathPrefix("/orchestrator/secret-scan").Subrouter()
+	r.secretScanRouter.InitSecretScanRouter(secretScanRouter)
 }
@@ -0,0 +1,129 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package sbom
+
+import (
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/sbom"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+)
+
+type SbomRestHandler interface {
+	DownloadSbom(w http.ResponseWriter, r *http.Request)
+	FindDeployedArtifactsByPackage(w http.ResponseWriter, r *http.Request)
+}
+
+type SbomRestHandlerImpl struct {
+	logger       *zap.SugaredLogger
+	sbomService  sbom.SbomService
+	userService  user.UserService
+	enforcer     casbin.Enforcer
+	enforcerUtil rbac.EnforcerUtil
+}
+
+func NewSbomRestHandlerImpl(logger *zap.SugaredLogger,
+	sbomService sbom.SbomService,
+	userService user.UserService,
+	enforcer casbin.Enforcer,
+	enforcerUtil rbac.EnforcerUtil,
+) *SbomRestHandlerImpl {
+	return &SbomRestHandlerImpl{
+		logger:       logger,
+		sbomService:  sbomService,
+		userService:  userService,
+		enforcer:     enforcer,
+		enforcerUtil: enforcerUtil,
+	}
+}
+
+func (impl SbomRestHandlerImpl) DownloadSbom(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	ciArtifactId, err := strconv.Atoi(mux.Vars(r)["ciArtifactId"])
+	if err != nil {
+		impl.logger.Errorw("request err, DownloadSbom", "err", err, "ciArtifactId", mux.Vars(r)["ciArtifactId"])
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := impl.sbomService.GetSbom(ciArtifactId)
+	if err != nil {
+		impl.logger.Errorw("service err, DownloadSbom", "err", err, "ciArtifactId", ciArtifactId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, impl.enforcerUtil.GetAppRBACNameByAppId(res.AppId)); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", "attachment; filename=sbom-"+strconv.Itoa(ciArtifactId)+".json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(res.Content)
+	if err != nil {
+		impl.logger.Errorw("error in writing sbom", "err", err, "ciArtifactId", ciArtifactId)
+	}
+}
+
+// FindDeployedArtifactsByPackage lists deployed artifacts containing a package, results are limited to apps the user
+// can view, deployments not linked to an app need global environment access
+func (impl SbomRestHandlerImpl) FindDeployedArtifactsByPackage(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	name := r.URL.Query().Get("package")
+	version := r.URL.Query().Get("version")
+	artifacts, err := impl.sbomService.FindDeployedArtifactsByPackage(name, version)
+	if err != nil {
+		impl.logger.Errorw("service err, FindDeployedArtifactsByPackage", "err", err, "package", name, "version", version)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	token := r.Header.Get("token")
+	globalEnvAccess := impl.enforcer.Enforce(token, casbin.ResourceGlobalEnvironment, casbin.ActionGet, "*")
+	appAccess := make(map[int]bool)
+	res := make([]*sbom.DeployedPackageArtifact, 0)
+	for _, artifact := range artifacts {
+		if artifact.AppId == 0 {
+			if globalEnvAccess {
+				res = append(res, artifact)
+			}
+			continue
+		}
+		ok, checked := appAccess[artifact.AppId]
+		if !checked {
+			ok = impl.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, impl.enforcerUtil.GetAppRBACNameByAppId(artifact.AppId))
+			appAccess[artifact.AppId] = ok
+		}
+		if ok {
+			res = append(res, artifact)
+		}
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
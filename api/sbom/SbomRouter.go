@@ -0,0 +1,22 @@
+package sbom
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type SbomRouter interface {
+	InitSbomRouter(router *mux.Router)
+}
+
+type SbomRouterImpl struct {
+	sbomRestHandler SbomRestHandler
+}
+
+func NewSbomRouterImpl(sbomRestHandler SbomRestHandler) *SbomRouterImpl {
+	return &SbomRouterImpl{sbomRestHandler: sbomRestHandler}
+}
+
+func (impl SbomRouterImpl) InitSbomRouter(router *mux.Router) {
+	router.Path("/artifact/{ciArtifactId}").HandlerFunc(impl.sbomRestHandler.DownloadSbom).Methods("GET")
+	router.Path("/deployed").HandlerFunc(impl.sbomRestHandler.FindDeployedArtifactsByPackage).Queries("package", "{package}").Methods("GET")
+}
@@ -0,0 +1,17 @@
+package sbom
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sbom"
+	"github.com/google/wire"
+)
+
+var SbomWireSet = wire.NewSet(
+	sbom.NewSbomRepositoryImpl,
+	wire.Bind(new(sbom.SbomRepository), new(*sbom.SbomRepositoryImpl)),
+	sbom.NewSbomServiceImpl,
+	wire.Bind(new(sbom.SbomService), new(*sbom.SbomServiceImpl)),
+	NewSbomRestHandlerImpl,
+	wire.Bind(new(SbomRestHandler), new(*SbomRestHandlerImpl)),
+	NewSbomRouterImpl,
+	wire.Bind(new(SbomRouter), new(*SbomRouterImpl)),
+)
@@ -0,0 +1,212 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package secretScan
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/secretScan"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+)
+
+type SecretScanRestHandler interface {
+	GetScanResult(w http.ResponseWriter, r *http.Request)
+	GetRules(w http.ResponseWriter, r *http.Request)
+	GetAllowList(w http.ResponseWriter, r *http.Request)
+	SaveAllowListEntry(w http.ResponseWriter, r *http.Request)
+	DeleteAllowListEntry(w http.ResponseWriter, r *http.Request)
+}
+
+type SecretScanRestHandlerImpl struct {
+	logger            *zap.SugaredLogger
+	secretScanService secretScan.SecretScanService
+	userService       user.UserService
+	enforcer          casbin.Enforcer
+	enforcerUtil      rbac.EnforcerUtil
+	validator         *validator.Validate
+}
+
+func NewSecretScanRestHandlerImpl(logger *zap.SugaredLogger,
+	secretScanService secretScan.SecretScanService,
+	userService user.UserService,
+	enforcer casbin.Enforcer,
+	enforcerUtil rbac.EnforcerUtil,
+	validator *validator.Validate,
+) *SecretScanRestHandlerImpl {
+	return &SecretScanRestHandlerImpl{
+		logger:            logger,
+		secretScanService: secretScanService,
+		userService:       userService,
+		enforcer:          enforcer,
+		enforcerUtil:      enforcerUtil,
+		validator:         validator,
+	}
+}
+
+func (impl SecretScanRestHandlerImpl) GetScanResult(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	ciWorkflowId, err := strconv.Atoi(mux.Vars(r)["ciWorkflowId"])
+	if err != nil {
+		impl.logger.Errorw("request err, GetScanResult", "err", err, "ciWorkflowId", mux.Vars(r)["ciWorkflowId"])
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := impl.secretScanService.GetScanResult(ciWorkflowId)
+	if err != nil {
+		impl.logger.Errorw("service err, GetScanResult", "err", err, "ciWorkflowId", ciWorkflowId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, impl.enforcerUtil.GetAppRBACNameByAppId(res.AppId)); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl SecretScanRestHandlerImpl) GetRules(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	common.WriteJsonResp(w, nil, secretScan.SecretScanRules, http.StatusOK)
+}
+
+func (impl SecretScanRestHandlerImpl) GetAllowList(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	gitMaterialId, err := strconv.Atoi(r.URL.Query().Get("gitMaterialId"))
+	if err != nil {
+		impl.logger.Errorw("request err, GetAllowList", "err", err, "gitMaterialId", r.URL.Query().Get("gitMaterialId"))
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if ok, err := impl.isAuthorized(r.Header.Get("token"), gitMaterialId, casbin.ActionGet); err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	} else if !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	res, err := impl.secretScanService.GetAllowList(gitMaterialId)
+	if err != nil {
+		impl.logger.Errorw("service err, GetAllowList", "err", err, "gitMaterialId", gitMaterialId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl SecretScanRestHandlerImpl) SaveAllowListEntry(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	decoder := json.NewDecoder(r.Body)
+	var bean secretScan.AllowListEntry
+	err = decoder.Decode(&bean)
+	if err != nil {
+		impl.logger.Errorw("request err, SaveAllowListEntry", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = impl.validator.Struct(bean)
+	if err != nil {
+		impl.logger.Errorw("validation err, SaveAllowListEntry", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = secretScan.ValidateAllowListEntry(&bean)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if ok, err := impl.isAuthorized(r.Header.Get("token"), bean.GitMaterialId, casbin.ActionUpdate); err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	} else if !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	res, err := impl.secretScanService.SaveAllowListEntry(&bean, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, SaveAllowListEntry", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl SecretScanRestHandlerImpl) DeleteAllowListEntry(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		impl.logger.Errorw("request err, DeleteAllowListEntry", "err", err, "id", mux.Vars(r)["id"])
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	entry, err := impl.secretScanService.GetAllowListEntry(id)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if ok, err := impl.isAuthorized(r.Header.Get("token"), entry.GitMaterialId, casbin.ActionUpdate); err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	} else if !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	err = impl.secretScanService.DeleteAllowListEntry(id, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, DeleteAllowListEntry", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, id, http.StatusOK)
+}
+
+// isAuthorized checks access on the app of the git material whose allow list is read or changed
+func (impl SecretScanRestHandlerImpl) isAuthorized(token string, gitMaterialId int, action string) (bool, error) {
+	appId, err := impl.secretScanService.GetAppIdByGitMaterialId(gitMaterialId)
+	if err != nil {
+		return false, err
+	}
+	return impl.enforcer.Enforce(token, casbin.ResourceApplications, action, impl.enforcerUtil.GetAppRBACNameByAppId(appId)), nil
+}
@@ -0,0 +1,25 @@
+package secretScan
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type SecretScanRouter interface {
+	InitSecretScanRouter(router *mux.Router)
+}
+
+type SecretScanRouterImpl struct {
+	secretScanRestHandler SecretScanRestHandler
+}
+
+func NewSecretScanRouterImpl(secretScanRestHandler SecretScanRestHandler) *SecretScanRouterImpl {
+	return &SecretScanRouterImpl{secretScanRestHandler: secretScanRestHandler}
+}
+
+func (impl SecretScanRouterImpl) InitSecretScanRouter(router *mux.Router) {
+	router.Path("/rules").HandlerFunc(impl.secretScanRestHandler.GetRules).Methods("GET")
+	router.Path("/result/{ciWorkflowId}").HandlerFunc(impl.secretScanRestHandler.GetScanResult).Methods("GET")
+	router.Path("/allow-list").HandlerFunc(impl.secretScanRestHandler.GetAllowList).Methods("GET")
+	router.Path("/allow-list").HandlerFunc(impl.secretScanRestHandler.SaveAllowListEntry).Methods("POST")
+	router.Path("/allow-list/{id}").HandlerFunc(impl.secretScanRestHandler.DeleteAllowListEntry).Methods("DELETE")
+}
@@ -0,0 +1,19 @@
+package secretScan
+
+import (
+	"github.com/devtron-labs/devtron/pkg/secretScan"
+	"github.com/google/wire"
+)
+
+var SecretScanWireSet = wire.NewSet(
+	secretScan.GetSecretScanConfig,
+	secretScan.NewSecretScanRepositoryImpl,
+	wire.Bind(new(secretScan.SecretScanRepository), new(*secretScan.SecretScanRepositoryImpl)),
+
+	secretScan.NewSecretScanServiceImpl,
+	wire.Bind(new(secretScan.SecretScanService), new(*secretScan.SecretScanServiceImpl)),
+	NewSecretScanRestHandlerImpl,
+	wire.Bind(new(SecretScanRestHandler), new(*SecretScanRestHandlerImpl)),
+	NewSecretScanRouterImpl,
+	wire.Bind(new(SecretScanRouter), new(*SecretScanRouterImpl)),
+)
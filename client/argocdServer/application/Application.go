@@ -81,6 +81,8 @@ type ServiceClient interface {
 	DeleteResource(ctx context.Context, query *application.ApplicationResourceDeleteRequest) (*application.ApplicationResponse, error)
 	// Delete deletes an application
 	Delete(ctx context.Context, query *application.ApplicationDeleteRequest) (*application.ApplicationResponse, error)
+	// RunResourceAction runs a resource action (like resume or abort of a rollout) on a single application resource
+	RunResourceAction(ctx context.Context, query *application.ResourceActionRunRequest) (*application.ApplicationResponse, error)
 }
 
 type Result struct {
@@ -266,6 +268,20 @@ func (c ServiceClientImpl) PatchResource(ctxt context.Context, query *applicatio
 	return resp, err
 }
 
+func (c ServiceClientImpl) RunResourceAction(ctxt context.Context, query *application.ResourceActionRunRequest) (*application.ApplicationResponse, error) {
+	ctx, cancel := context.WithTimeout(ctxt, TimeoutFast)
+	defer cancel()
+	token, ok := ctxt.Value("token").(string)
+	if !ok {
+		return nil, errors.New("Unauthorized")
+	}
+	conn := argocdServer.GetConnection(token, c.settings)
+	defer util.Close(conn, c.logger)
+	asc := application.NewApplicationServiceClient(conn)
+	resp, err := asc.RunResourceAction(ctx, query)
+	return resp, err
+}
+
 func (c ServiceClientImpl) DeleteResource(ctxt context.Context, query *application.ApplicationResourceDeleteRequest) (*application.ApplicationResponse, error) {
 	ctx, cancel := context.WithTimeout(ctxt, TimeoutSlow)
 	defer cancel()
@@ -0,0 +1,60 @@
+package cron
+
+import (
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+type CanaryAnalysisHandler interface {
+	AnalyseCanaryDeployments()
+}
+
+type CanaryAnalysisHandlerImpl struct {
+	logger                *zap.SugaredLogger
+	cron                  *cron.Cron
+	canaryAnalysisService pipeline.CanaryAnalysisService
+}
+
+type CanaryAnalysisConfig struct {
+	CanaryAnalysisCronTime string `env:"CANARY_ANALYSIS_CRON_TIME" envDefault:"*/1 * * * *"`
+}
+
+func GetCanaryAnalysisConfig() (*CanaryAnalysisConfig, error) {
+	cfg := &CanaryAnalysisConfig{}
+	err := env.Parse(cfg)
+	if err != nil {
+		fmt.Println("failed to parse canary analysis config: " + err.Error())
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func NewCanaryAnalysisHandlerImpl(logger *zap.SugaredLogger, canaryAnalysisService pipeline.CanaryAnalysisService,
+	canaryAnalysisConfig *CanaryAnalysisConfig) *CanaryAnalysisHandlerImpl {
+	cron := cron.New(
+		cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger)))
+	cron.Start()
+	impl := &CanaryAnalysisHandlerImpl{
+		logger:                logger,
+		cron:                  cron,
+		canaryAnalysisService: canaryAnalysisService,
+	}
+	_, err := cron.AddFunc(canaryAnalysisConfig.CanaryAnalysisCronTime, impl.AnalyseCanaryDeployments)
+	if err != nil {
+		logger.Errorw("error in starting canary analysis cron job", "err", err)
+		return nil
+	}
+	return impl
+}
+
+func (impl *CanaryAnalysisHandlerImpl) AnalyseCanaryDeployments() {
+	err := impl.canaryAnalysisService.AnalyseCanaryDeployments()
+	if err != nil {
+		impl.logger.Errorw("error in analysing canary deployments - cron job", "err", err)
+		return
+	}
+	return
+}
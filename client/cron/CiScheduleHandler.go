@@ -0,0 +1,66 @@
+package cron
+
+import (
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"time"
+)
+
+type CiScheduleHandler interface {
+	TriggerScheduledCiPipelines()
+}
+
+type CiScheduleHandlerImpl struct {
+	logger    *zap.SugaredLogger
+	cron      *cron.Cron
+	ciHandler pipeline.CiHandler
+	lastRunOn time.Time
+}
+
+type CiScheduleConfig struct {
+	CiScheduleCronTime string `env:"CI_SCHEDULE_CRON_TIME" envDefault:"*/1 * * * *"`
+}
+
+func GetCiScheduleConfig() (*CiScheduleConfig, error) {
+	cfg := &CiScheduleConfig{}
+	err := env.Parse(cfg)
+	if err != nil {
+		fmt.Println("failed to parse ci schedule config: " + err.Error())
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func NewCiScheduleHandlerImpl(logger *zap.SugaredLogger, ciHandler pipeline.CiHandler,
+	ciScheduleConfig *CiScheduleConfig) *CiScheduleHandlerImpl {
+	cron := cron.New(
+		cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger)))
+	cron.Start()
+	impl := &CiScheduleHandlerImpl{
+		logger:    logger,
+		cron:      cron,
+		ciHandler: ciHandler,
+		lastRunOn: time.Now(),
+	}
+	_, err := cron.AddFunc(ciScheduleConfig.CiScheduleCronTime, impl.TriggerScheduledCiPipelines)
+	if err != nil {
+		logger.Errorw("error in starting ci schedule cron job", "err", err)
+		return nil
+	}
+	return impl
+}
+
+func (impl *CiScheduleHandlerImpl) TriggerScheduledCiPipelines() {
+	//schedules due since last run are triggered, so a slow run does not miss any schedule
+	now := time.Now()
+	err := impl.ciHandler.TriggerScheduledCiPipelines(impl.lastRunOn, now)
+	impl.lastRunOn = now
+	if err != nil {
+		impl.logger.Errorw("error in triggering scheduled ci pipelines - cron job", "err", err)
+		return
+	}
+	return
+}
@@ -0,0 +1,91 @@
+package cron
+
+import (
+	"fmt"
+	"github.com/caarlos0/env"
+	client "github.com/devtron-labs/devtron/client/events"
+	"github.com/devtron-labs/devtron/pkg/cluster/repository"
+	util "github.com/devtron-labs/devtron/util/event"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"time"
+)
+
+type ClusterUnreachableHandler interface {
+	NotifyUnreachableClusters()
+}
+
+type ClusterUnreachableHandlerImpl struct {
+	logger                   *zap.SugaredLogger
+	cron                     *cron.Cron
+	clusterRepository        repository.ClusterRepository
+	eventClient              client.EventClient
+	eventFactory             client.EventFactory
+	clusterUnreachableConfig *ClusterUnreachableConfig
+}
+
+type ClusterUnreachableConfig struct {
+	ClusterUnreachableCronTime string `env:"CLUSTER_UNREACHABLE_CRON_TIME" envDefault:"*/15 * * * *"`
+}
+
+func GetClusterUnreachableConfig() (*ClusterUnreachableConfig, error) {
+	cfg := &ClusterUnreachableConfig{}
+	err := env.Parse(cfg)
+	if err != nil {
+		fmt.Println("failed to parse cluster unreachable config: " + err.Error())
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func NewClusterUnreachableHandlerImpl(logger *zap.SugaredLogger, clusterRepository repository.ClusterRepository,
+	eventClient client.EventClient, eventFactory client.EventFactory,
+	clusterUnreachableConfig *ClusterUnreachableConfig) *ClusterUnreachableHandlerImpl {
+	cron := cron.New(
+		cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger)))
+	cron.Start()
+	impl := &ClusterUnreachableHandlerImpl{
+		logger:                   logger,
+		cron:                     cron,
+		clusterRepository:        clusterRepository,
+		eventClient:              eventClient,
+		eventFactory:             eventFactory,
+		clusterUnreachableConfig: clusterUnreachableConfig,
+	}
+	_, err := cron.AddFunc(clusterUnreachableConfig.ClusterUnreachableCronTime, impl.NotifyUnreachableClusters)
+	if err != nil {
+		logger.Errorw("error in starting cluster unreachable cron job", "err", err)
+		return nil
+	}
+	return impl
+}
+
+// NotifyUnreachableClusters sends a notification once for every cluster whose connection check failed, the event is
+// not scoped to an app or environment. Connection status of clusters is updated by the cluster cron service
+func (impl *ClusterUnreachableHandlerImpl) NotifyUnreachableClusters() {
+	clusters, err := impl.clusterRepository.FindAllActive()
+	if err != nil {
+		impl.logger.Errorw("error in fetching clusters - cron job", "err", err)
+		return
+	}
+	for i := range clusters {
+		cluster := &clusters[i]
+		var err error
+		if len(cluster.ErrorInConnecting) > 0 && cluster.UnreachableNotifiedOn.IsZero() {
+			event := impl.eventFactory.Build(util.ClusterUnreachable, nil, 0, nil, util.CD)
+			event = impl.eventFactory.BuildClusterUnreachableData(event, cluster)
+			_, err = impl.eventClient.WriteNotificationEvent(event)
+			if err != nil {
+				impl.logger.Errorw("error in writing cluster unreachable event", "err", err, "clusterId", cluster.Id)
+				continue
+			}
+			notifiedOn := time.Now()
+			err = impl.clusterRepository.UpdateClusterUnreachableNotifiedOn(cluster.Id, &notifiedOn)
+		} else if len(cluster.ErrorInConnecting) == 0 && !cluster.UnreachableNotifiedOn.IsZero() {
+			err = impl.clusterRepository.UpdateClusterUnreachableNotifiedOn(cluster.Id, nil)
+		}
+		if err != nil {
+			impl.logger.Errorw("error in updating cluster unreachable notified on", "err", err, "clusterId", cluster.Id)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package cron
+
+import (
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/pkg/security"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"time"
+)
+
+type CveExceptionExpiryHandler interface {
+	NotifyExpiringCveExceptions()
+}
+
+type CveExceptionExpiryHandlerImpl struct {
+	logger                   *zap.SugaredLogger
+	cron                     *cron.Cron
+	policyService            security.PolicyService
+	cveExceptionExpiryConfig *CveExceptionExpiryConfig
+}
+
+type CveExceptionExpiryConfig struct {
+	CveExceptionExpiryCronTime          string `env:"CVE_EXCEPTION_EXPIRY_CRON_TIME" envDefault:"0 * * * *"`
+	CveExceptionExpiryNotifyBeforeHours int    `env:"CVE_EXCEPTION_EXPIRY_NOTIFY_BEFORE_HOURS" envDefault:"72"`
+}
+
+func GetCveExceptionExpiryConfig() (*CveExceptionExpiryConfig, error) {
+	cfg := &CveExceptionExpiryConfig{}
+	err := env.Parse(cfg)
+	if err != nil {
+		fmt.Println("failed to parse cve exception expiry config: " + err.Error())
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func NewCveExceptionExpiryHandlerImpl(logger *zap.SugaredLogger, policyService security.PolicyService,
+	cveExceptionExpiryConfig *CveExceptionExpiryConfig) *CveExceptionExpiryHandlerImpl {
+	cron := cron.New(
+		cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger)))
+	cron.Start()
+	impl := &CveExceptionExpiryHandlerImpl{
+		logger:                   logger,
+		cron:                     cron,
+		policyService:            policyService,
+		cveExceptionExpiryConfig: cveExceptionExpiryConfig,
+	}
+	_, err := cron.AddFunc(cveExceptionExpiryConfig.CveExceptionExpiryCronTime, impl.NotifyExpiringCveExceptions)
+	if err != nil {
+		logger.Errorw("error in starting cve exception expiry cron job", "err", err)
+		return nil
+	}
+	return impl
+}
+
+func (impl *CveExceptionExpiryHandlerImpl) NotifyExpiringCveExceptions() {
+	notifyBefore := time.Duration(impl.cveExceptionExpiryConfig.CveExceptionExpiryNotifyBeforeHours) * time.Hour
+	err := impl.policyService.NotifyExpiringCveExceptions(notifyBefore)
+	if err != nil {
+		impl.logger.Errorw("error in notifying expiring cve exceptions - cron job", "err", err)
+		return
+	}
+	return
+}
@@ -0,0 +1,60 @@
+package cron
+
+import (
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+type DeploymentQueueHandler interface {
+	TriggerQueuedDeployments()
+}
+
+type DeploymentQueueHandlerImpl struct {
+	logger              *zap.SugaredLogger
+	cron                *cron.Cron
+	workflowDagExecutor pipeline.WorkflowDagExecutor
+}
+
+type DeploymentQueueConfig struct {
+	DeploymentQueueCronTime string `env:"DEPLOYMENT_QUEUE_CRON_TIME" envDefault:"*/1 * * * *"`
+}
+
+func GetDeploymentQueueConfig() (*DeploymentQueueConfig, error) {
+	cfg := &DeploymentQueueConfig{}
+	err := env.Parse(cfg)
+	if err != nil {
+		fmt.Println("failed to parse deployment queue config: " + err.Error())
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func NewDeploymentQueueHandlerImpl(logger *zap.SugaredLogger, workflowDagExecutor pipeline.WorkflowDagExecutor,
+	deploymentQueueConfig *DeploymentQueueConfig) *DeploymentQueueHandlerImpl {
+	cron := cron.New(
+		cron.WithChain())
+	cron.Start()
+	impl := &DeploymentQueueHandlerImpl{
+		logger:              logger,
+		cron:                cron,
+		workflowDagExecutor: workflowDagExecutor,
+	}
+	_, err := cron.AddFunc(deploymentQueueConfig.DeploymentQueueCronTime, impl.TriggerQueuedDeployments)
+	if err != nil {
+		logger.Errorw("error in starting deployment queue cron job", "err", err)
+		return nil
+	}
+	return impl
+}
+
+func (impl *DeploymentQueueHandlerImpl) TriggerQueuedDeployments() {
+	err := impl.workflowDagExecutor.TriggerQueuedDeployments()
+	if err != nil {
+		impl.logger.Errorw("error in triggering queued deployments - cron job", "err", err)
+		return
+	}
+	return
+}
@@ -0,0 +1,66 @@
+package cron
+
+import (
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/pkg/security"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+type ImageRescanHandler interface {
+	RescanDeployedImages()
+}
+
+type ImageRescanHandlerImpl struct {
+	logger            *zap.SugaredLogger
+	cron              *cron.Cron
+	imageScanService  security.ImageScanService
+	imageRescanConfig *ImageRescanConfig
+}
+
+type ImageRescanConfig struct {
+	ImageRescanEnabled  bool   `env:"IMAGE_RESCAN_ENABLED" envDefault:"true"`
+	ImageRescanCronTime string `env:"IMAGE_RESCAN_CRON_TIME" envDefault:"0 */12 * * *"`
+}
+
+func GetImageRescanConfig() (*ImageRescanConfig, error) {
+	cfg := &ImageRescanConfig{}
+	err := env.Parse(cfg)
+	if err != nil {
+		fmt.Println("failed to parse image rescan config: " + err.Error())
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func NewImageRescanHandlerImpl(logger *zap.SugaredLogger, imageScanService security.ImageScanService,
+	imageRescanConfig *ImageRescanConfig) *ImageRescanHandlerImpl {
+	cron := cron.New(
+		cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger)))
+	cron.Start()
+	impl := &ImageRescanHandlerImpl{
+		logger:            logger,
+		cron:              cron,
+		imageScanService:  imageScanService,
+		imageRescanConfig: imageRescanConfig,
+	}
+	if !imageRescanConfig.ImageRescanEnabled {
+		return impl
+	}
+	_, err := cron.AddFunc(imageRescanConfig.ImageRescanCronTime, impl.RescanDeployedImages)
+	if err != nil {
+		logger.Errorw("error in starting image rescan cron job", "err", err)
+		return nil
+	}
+	return impl
+}
+
+func (impl *ImageRescanHandlerImpl) RescanDeployedImages() {
+	err := impl.imageScanService.RescanDeployedImages()
+	if err != nil {
+		impl.logger.Errorw("error in rescanning deployed images - cron job", "err", err)
+		return
+	}
+	return
+}
@@ -0,0 +1,61 @@
+package cron
+
+import (
+	"fmt"
+	"github.com/caarlos0/env"
+	client "github.com/devtron-labs/devtron/client/events"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+type NotificationOutboxHandler interface {
+	DeliverPendingNotifications()
+}
+
+type NotificationOutboxHandlerImpl struct {
+	logger                   *zap.SugaredLogger
+	cron                     *cron.Cron
+	eventClient              client.EventClient
+	notificationOutboxConfig *NotificationOutboxConfig
+}
+
+type NotificationOutboxConfig struct {
+	NotificationOutboxCronTime  string `env:"NOTIFICATION_OUTBOX_CRON_TIME" envDefault:"@every 15s"`
+	NotificationOutboxBatchSize int    `env:"NOTIFICATION_OUTBOX_BATCH_SIZE" envDefault:"50"`
+}
+
+func GetNotificationOutboxConfig() (*NotificationOutboxConfig, error) {
+	cfg := &NotificationOutboxConfig{}
+	err := env.Parse(cfg)
+	if err != nil {
+		fmt.Println("failed to parse notification outbox config: " + err.Error())
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func NewNotificationOutboxHandlerImpl(logger *zap.SugaredLogger, eventClient client.EventClient,
+	notificationOutboxConfig *NotificationOutboxConfig) *NotificationOutboxHandlerImpl {
+	cron := cron.New(
+		cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger)))
+	cron.Start()
+	impl := &NotificationOutboxHandlerImpl{
+		logger:                   logger,
+		cron:                     cron,
+		eventClient:              eventClient,
+		notificationOutboxConfig: notificationOutboxConfig,
+	}
+	_, err := cron.AddFunc(notificationOutboxConfig.NotificationOutboxCronTime, impl.DeliverPendingNotifications)
+	if err != nil {
+		logger.Errorw("error in starting notification outbox cron job", "err", err)
+		return nil
+	}
+	return impl
+}
+
+// DeliverPendingNotifications flushes due notification batches before delivering so that their digests go out in the
+// same run
+func (impl *NotificationOutboxHandlerImpl) DeliverPendingNotifications() {
+	impl.eventClient.FlushNotificationBatches(impl.notificationOutboxConfig.NotificationOutboxBatchSize)
+	impl.eventClient.DeliverPendingNotifications(impl.notificationOutboxConfig.NotificationOutboxBatchSize)
+}
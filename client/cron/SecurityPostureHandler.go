@@ -0,0 +1,66 @@
+package cron
+
+import (
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/pkg/security"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+type SecurityPostureHandler interface {
+	MaterialiseSecurityPosture()
+}
+
+type SecurityPostureHandlerImpl struct {
+	logger                *zap.SugaredLogger
+	cron                  *cron.Cron
+	imageScanService      security.ImageScanService
+	securityPostureConfig *SecurityPostureConfig
+}
+
+type SecurityPostureConfig struct {
+	SecurityPostureEnabled  bool   `env:"SECURITY_POSTURE_ENABLED" envDefault:"true"`
+	SecurityPostureCronTime string `env:"SECURITY_POSTURE_CRON_TIME" envDefault:"30 0 * * *"`
+}
+
+func GetSecurityPostureConfig() (*SecurityPostureConfig, error) {
+	cfg := &SecurityPostureConfig{}
+	err := env.Parse(cfg)
+	if err != nil {
+		fmt.Println("failed to parse security posture config: " + err.Error())
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func NewSecurityPostureHandlerImpl(logger *zap.SugaredLogger, imageScanService security.ImageScanService,
+	securityPostureConfig *SecurityPostureConfig) *SecurityPostureHandlerImpl {
+	cron := cron.New(
+		cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger)))
+	cron.Start()
+	impl := &SecurityPostureHandlerImpl{
+		logger:                logger,
+		cron:                  cron,
+		imageScanService:      imageScanService,
+		securityPostureConfig: securityPostureConfig,
+	}
+	if !securityPostureConfig.SecurityPostureEnabled {
+		return impl
+	}
+	_, err := cron.AddFunc(securityPostureConfig.SecurityPostureCronTime, impl.MaterialiseSecurityPosture)
+	if err != nil {
+		logger.Errorw("error in starting security posture cron job", "err", err)
+		return nil
+	}
+	return impl
+}
+
+func (impl *SecurityPostureHandlerImpl) MaterialiseSecurityPosture() {
+	err := impl.imageScanService.MaterialiseSecurityPosture()
+	if err != nil {
+		impl.logger.Errorw("error in materialising security posture - cron job", "err", err)
+		return
+	}
+	return
+}
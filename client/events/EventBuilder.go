@@ -20,9 +20,12 @@ package client
 import (
 	"fmt"
 	bean2 "github.com/devtron-labs/devtron/api/bean"
+	repository2 "github.com/devtron-labs/devtron/internal/sql/repository"
 	"github.com/devtron-labs/devtron/internal/sql/repository/chartConfig"
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/security"
 	"github.com/devtron-labs/devtron/pkg/bean"
+	clusterRepository "github.com/devtron-labs/devtron/pkg/cluster/repository"
 	"github.com/devtron-labs/devtron/pkg/user/repository"
 	"github.com/devtron-labs/devtron/util/event"
 	"github.com/satori/go.uuid"
@@ -35,6 +38,18 @@ type EventFactory interface {
 	Build(eventType util.EventType, sourceId *int, appId int, envId *int, pipelineType util.PipelineType) Event
 	BuildExtraCDData(event Event, wfr *pipelineConfig.CdWorkflowRunner, pipelineOverrideId int, stage bean2.WorkflowType) Event
 	BuildExtraCIData(event Event, material *MaterialTriggerInfo, dockerImage string) Event
+	// BuildDeploymentHealthData adds health status reached by a deployment after sync, used by deployment degraded
+	// and deployment healthy events
+	BuildDeploymentHealthData(event Event, wfr *pipelineConfig.CdWorkflowRunner, healthStatus string) Event
+	BuildApprovalRequestedData(event Event, artifact *repository2.CiArtifact, approvalRequestId int, approverEmailIds []string) Event
+	// BuildImageScanBlockedData adds the cves of an artifact which failed its deployment due to cve policy
+	BuildImageScanBlockedData(event Event, wfr *pipelineConfig.CdWorkflowRunner, artifact *repository2.CiArtifact, blockedCves []*security.BlockedCve) Event
+	BuildPipelineConfigChangedData(event Event, configChange string, userId int32) Event
+	BuildAppHibernatedData(event Event, userId int32) Event
+	BuildClusterUnreachableData(event Event, cluster *clusterRepository.Cluster) Event
+	BuildCveExceptionExpiringData(event Event, exception *security.CvePolicy) Event
+	// BuildNewCveDiscoveredData adds the cves found on rescan of a deployed image which were not present in its previous scan
+	BuildNewCveDiscoveredData(event Event, image string, imageDigest string, newCves []*security.CveStore) Event
 	//BuildFinalData(event Event) *Payload
 }
 
@@ -192,6 +207,112 @@ func (impl *EventSimpleFactoryImpl) BuildExtraCIData(event Event, material *Mate
 	return event
 }
 
+func (impl *EventSimpleFactoryImpl) BuildDeploymentHealthData(event Event, wfr *pipelineConfig.CdWorkflowRunner, healthStatus string) Event {
+	event = impl.BuildExtraCDData(event, wfr, 0, bean2.CD_WORKFLOW_TYPE_DEPLOY)
+	event.Payload.HealthStatus = healthStatus
+	return event
+}
+
+func (impl *EventSimpleFactoryImpl) BuildApprovalRequestedData(event Event, artifact *repository2.CiArtifact, approvalRequestId int, approverEmailIds []string) Event {
+	event.CdWorkflowType = bean2.CD_WORKFLOW_TYPE_DEPLOY
+	event.CiArtifactId = artifact.Id
+	event.Payload = &Payload{
+		DockerImageUrl:    artifact.Image,
+		ApprovalRequestId: approvalRequestId,
+		ApproverEmailIds:  approverEmailIds,
+	}
+	return impl.buildTriggeredBy(event)
+}
+
+func (impl *EventSimpleFactoryImpl) BuildImageScanBlockedData(event Event, wfr *pipelineConfig.CdWorkflowRunner, artifact *repository2.CiArtifact, blockedCves []*security.BlockedCve) Event {
+	event.CdWorkflowType = bean2.CD_WORKFLOW_TYPE_DEPLOY
+	event.CdWorkflowRunnerId = wfr.Id
+	event.CiArtifactId = artifact.Id
+	event.UserId = int(wfr.TriggeredBy)
+	payload := &Payload{
+		Stage:          string(bean2.CD_WORKFLOW_TYPE_DEPLOY),
+		DockerImageUrl: artifact.Image,
+		FailureReason:  wfr.Message,
+	}
+	for _, blockedCve := range blockedCves {
+		payload.BlockedCves = append(payload.BlockedCves, &BlockedCve{
+			Name:     blockedCve.Cve.Name,
+			Severity: blockedCve.Cve.Severity.String(),
+			Package:  blockedCve.Cve.Package,
+			Version:  blockedCve.Cve.Version,
+			Rule:     blockedCve.Rule,
+		})
+	}
+	event.Payload = payload
+	return impl.buildTriggeredBy(event)
+}
+
+func (impl *EventSimpleFactoryImpl) BuildPipelineConfigChangedData(event Event, configChange string, userId int32) Event {
+	event.UserId = int(userId)
+	event.Payload = &Payload{ConfigChange: configChange}
+	return impl.buildTriggeredBy(event)
+}
+
+func (impl *EventSimpleFactoryImpl) BuildAppHibernatedData(event Event, userId int32) Event {
+	event.UserId = int(userId)
+	event.Payload = &Payload{}
+	return impl.buildTriggeredBy(event)
+}
+
+func (impl *EventSimpleFactoryImpl) BuildClusterUnreachableData(event Event, cluster *clusterRepository.Cluster) Event {
+	event.Payload = &Payload{
+		Cluster: &ClusterPayload{
+			ClusterId:         cluster.Id,
+			ClusterName:       cluster.ClusterName,
+			ServerUrl:         cluster.ServerUrl,
+			ErrorInConnecting: cluster.ErrorInConnecting,
+		},
+	}
+	return event
+}
+
+func (impl *EventSimpleFactoryImpl) BuildCveExceptionExpiringData(event Event, exception *security.CvePolicy) Event {
+	event.Payload = &Payload{
+		CveException: &CveExceptionPayload{
+			CveId:         exception.CVEStoreId,
+			Severity:      exception.Severity.String(),
+			PolicyLevel:   exception.PolicyLevel().String(),
+			Justification: exception.Justification,
+			Owner:         exception.Owner,
+			ExpiresOn:     exception.ExpiresOn.Format(time.RFC3339),
+		},
+	}
+	return event
+}
+
+func (impl *EventSimpleFactoryImpl) BuildNewCveDiscoveredData(event Event, image string, imageDigest string, newCves []*security.CveStore) Event {
+	newCvePayload := &NewCvePayload{Image: image, ImageDigest: imageDigest}
+	for _, cve := range newCves {
+		newCvePayload.Cves = append(newCvePayload.Cves, &NewCve{
+			Name:         cve.Name,
+			Severity:     cve.Severity.String(),
+			Package:      cve.Package,
+			Version:      cve.Version,
+			FixedVersion: cve.FixedVersion,
+		})
+	}
+	event.Payload = &Payload{DockerImageUrl: image, NewCves: newCvePayload}
+	return event
+}
+
+// buildTriggeredBy sets email of the user who caused the event, failing to find the user is skipped
+func (impl *EventSimpleFactoryImpl) buildTriggeredBy(event Event) Event {
+	if event.UserId > 0 {
+		user, err := impl.userRepository.GetById(int32(event.UserId))
+		if err != nil {
+			impl.logger.Errorw("found error on payload build, skipping this error ", "userId", event.UserId, "err", err)
+			return event
+		}
+		event.Payload.TriggeredBy = user.EmailId
+	}
+	return event
+}
+
 func (impl *EventSimpleFactoryImpl) getCiMaterialInfo(ciPipelineId int, ciArtifactId int) (*MaterialTriggerInfo, error) {
 	materialTriggerInfo := &MaterialTriggerInfo{}
 	if ciPipelineId > 0 {
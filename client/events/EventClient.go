@@ -33,6 +33,8 @@ import (
 	"github.com/devtron-labs/devtron/internal/sql/repository"
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
 	"github.com/devtron-labs/devtron/pkg/attributes"
+	repository2 "github.com/devtron-labs/devtron/pkg/pipeline/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
 	util1 "github.com/devtron-labs/devtron/util"
 	util "github.com/devtron-labs/devtron/util/event"
 	"github.com/nats-io/nats.go"
@@ -42,6 +44,16 @@ import (
 type EventClientConfig struct {
 	DestinationURL string `env:"EVENT_URL" envDefault:"http://localhost:3000/notify"`
 	TestSuitURL    string `env:"TEST_SUIT_URL" envDefault:"http://localhost:3000"`
+	// failed deliveries of outbox are retried after base delay doubled on every attempt, up to max delay, and dead
+	// lettered after max attempts
+	OutboxMaxAttempts      int `env:"NOTIFICATION_OUTBOX_MAX_ATTEMPTS" envDefault:"6"`
+	OutboxRetryBaseSeconds int `env:"NOTIFICATION_OUTBOX_RETRY_BASE_SECONDS" envDefault:"30"`
+	OutboxRetryMaxSeconds  int `env:"NOTIFICATION_OUTBOX_RETRY_MAX_SECONDS" envDefault:"3600"`
+	// InboxEnabled stores events in in-app inbox of subscribed users, inbox does not need notification module
+	InboxEnabled bool `env:"NOTIFICATION_INBOX_ENABLED" envDefault:"true"`
+	// NotifierContractVersion is the event contract supported by deployed notifier, delivery modes other than
+	// immediate are used only when notifier sends events for a setting to providers of that setting
+	NotifierContractVersion int `env:"NOTIFIER_CONTRACT_VERSION" envDefault:"1"`
 }
 
 func GetEventClientConfig() (*EventClientConfig, error) {
@@ -57,6 +69,10 @@ type EventClient interface {
 	WriteNotificationEvent(event Event) (bool, error)
 	WriteNatsEvent(channel string, payload interface{}) error
 	SendTestSuite(reqBody []byte) (bool, error)
+	// DeliverPendingNotifications attempts a batch of outbox deliveries which are due
+	DeliverPendingNotifications(batchSize int)
+	// FlushNotificationBatches writes digests of de-duplicated and digest notification batches which are due to outbox
+	FlushNotificationBatches(batchSize int)
 }
 
 type Event struct {
@@ -75,7 +91,9 @@ type Event struct {
 	CiWorkflowRunnerId int               `json:"ciWorkflowRunnerId"`
 	CiArtifactId       int               `json:"ciArtifactId"`
 	BaseUrl            string            `json:"baseUrl"`
-	UserId             int               `json:"-"`
+	// ContractVersion is set on events sent for a notification setting, notifier must not resolve settings of them
+	ContractVersion int `json:"contractVersion,omitempty"`
+	UserId          int `json:"-"`
 }
 
 type Payload struct {
@@ -91,6 +109,98 @@ type Payload struct {
 	DownloadLink          string               `json:"downloadLink"`
 	BuildHistoryLink      string               `json:"buildHistoryLink"`
 	MaterialTriggerInfo   *MaterialTriggerInfo `json:"material"`
+	ApprovalRequestId     int                  `json:"approvalRequestId,omitempty"`
+	ApproverEmailIds      []string             `json:"approverEmailIds,omitempty"`
+	CveException          *CveExceptionPayload `json:"cveException,omitempty"`
+	NewCves               *NewCvePayload       `json:"newCves,omitempty"`
+	FailureReason         string               `json:"failureReason,omitempty"`
+	SecretLeaks           []*SecretLeak        `json:"secretLeaks,omitempty"`
+	HealthStatus          string               `json:"healthStatus,omitempty"`
+	BlockedCves           []*BlockedCve        `json:"blockedCves,omitempty"`
+	ConfigChange          string               `json:"configChange,omitempty"`
+	Cluster               *ClusterPayload      `json:"cluster,omitempty"`
+	Digest                *DigestPayload       `json:"digest,omitempty"`
+	// Providers are set when event is sent for a notification setting, notifier sends it to these providers instead
+	// of resolving settings subscribed to the event
+	Providers []*NotificationProvider `json:"providers,omitempty"`
+}
+
+type CveExceptionPayload struct {
+	CveId         string `json:"cveId,omitempty"`
+	Severity      string `json:"severity"`
+	PolicyLevel   string `json:"policyLevel"`
+	Justification string `json:"justification"`
+	Owner         string `json:"owner"`
+	ExpiresOn     string `json:"expiresOn"`
+}
+
+type NewCvePayload struct {
+	Image       string    `json:"image"`
+	ImageDigest string    `json:"imageDigest"`
+	Cves        []*NewCve `json:"cves"`
+}
+
+type NewCve struct {
+	Name         string `json:"name"`
+	Severity     string `json:"severity"`
+	Package      string `json:"package,omitempty"`
+	Version      string `json:"version,omitempty"`
+	FixedVersion string `json:"fixedVersion,omitempty"`
+}
+
+// SecretLeak is a hard-coded secret found in git materials which failed the build, Match is redacted
+type SecretLeak struct {
+	RuleId string `json:"ruleId"`
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Match  string `json:"match"`
+}
+
+// BlockedCve is a vulnerability of the deployed image which is blocked by cve policy
+type BlockedCve struct {
+	Name     string `json:"name"`
+	Severity string `json:"severity"`
+	Package  string `json:"package,omitempty"`
+	Version  string `json:"version,omitempty"`
+	Rule     string `json:"rule"`
+}
+
+type ClusterPayload struct {
+	ClusterId         int    `json:"clusterId"`
+	ClusterName       string `json:"clusterName"`
+	ServerUrl         string `json:"serverUrl"`
+	ErrorInConnecting string `json:"errorInConnecting"`
+}
+
+// DigestPayload summarises events batched by a notification setting between From and To
+type DigestPayload struct {
+	Title        string        `json:"title"`
+	DeliveryMode string        `json:"deliveryMode"`
+	From         string        `json:"from"`
+	To           string        `json:"to"`
+	TotalCount   int           `json:"totalCount"`
+	Items        []*DigestItem `json:"items"`
+}
+
+// DigestItem is the count of events of a pipeline and event type in a digest
+type DigestItem struct {
+	EventTypeId   int    `json:"eventTypeId"`
+	EventName     string `json:"eventName"`
+	PipelineType  string `json:"pipelineType"`
+	PipelineId    int    `json:"pipelineId"`
+	AppName       string `json:"appName"`
+	EnvName       string `json:"envName"`
+	PipelineName  string `json:"pipelineName"`
+	Count         int    `json:"count"`
+	LastEventTime string `json:"lastEventTime"`
+}
+
+// NotificationProvider is a recipient of a notification setting
+type NotificationProvider struct {
+	Destination util.Channel `json:"dest"`
+	Rule        string       `json:"rule"`
+	ConfigId    int          `json:"configId"`
+	Recipient   string       `json:"recipient"`
 }
 
 type CiPipelineMaterialResponse struct {
@@ -124,14 +234,28 @@ type EventRESTClientImpl struct {
 	pipelineRepository   pipelineConfig.PipelineRepository
 	attributesRepository repository.AttributesRepository
 	moduleService        module.ModuleService
+	// notificationSettingsRepository and webhookRepository resolve webhooks of an event, webhooks are not sent
+	// through notifier
+	notificationSettingsRepository repository.NotificationSettingsRepository
+	webhookRepository              repository.WebhookNotificationRepository
+	notificationOutboxRepository   repository.NotificationOutboxRepository
+	notificationBatchRepository    repository.NotificationBatchRepository
+	notificationInboxWriter        NotificationInboxWriter
+	pipelineStageRepository        repository2.PipelineStageRepository
 }
 
 func NewEventRESTClientImpl(logger *zap.SugaredLogger, client *http.Client, config *EventClientConfig, pubsubClient *pubsub.PubSubClient,
 	ciPipelineRepository pipelineConfig.CiPipelineRepository, pipelineRepository pipelineConfig.PipelineRepository,
-	attributesRepository repository.AttributesRepository, moduleService module.ModuleService) *EventRESTClientImpl {
+	attributesRepository repository.AttributesRepository, moduleService module.ModuleService,
+	notificationSettingsRepository repository.NotificationSettingsRepository, webhookRepository repository.WebhookNotificationRepository,
+	notificationOutboxRepository repository.NotificationOutboxRepository, notificationBatchRepository repository.NotificationBatchRepository,
+	notificationInboxWriter NotificationInboxWriter, pipelineStageRepository repository2.PipelineStageRepository) *EventRESTClientImpl {
 	return &EventRESTClientImpl{logger: logger, client: client, config: config, pubsubClient: pubsubClient,
 		ciPipelineRepository: ciPipelineRepository, pipelineRepository: pipelineRepository,
-		attributesRepository: attributesRepository, moduleService: moduleService}
+		attributesRepository: attributesRepository, moduleService: moduleService,
+		notificationSettingsRepository: notificationSettingsRepository, webhookRepository: webhookRepository,
+		notificationOutboxRepository: notificationOutboxRepository, notificationBatchRepository: notificationBatchRepository,
+		notificationInboxWriter: notificationInboxWriter, pipelineStageRepository: pipelineStageRepository}
 }
 
 func (impl *EventRESTClientImpl) buildFinalPayload(event Event, cdPipeline *pipelineConfig.Pipeline, ciPipeline *pipelineConfig.CiPipeline) *Payload {
@@ -162,13 +286,14 @@ func (impl *EventRESTClientImpl) buildFinalPayload(event Event, cdPipeline *pipe
 }
 
 func (impl *EventRESTClientImpl) WriteNotificationEvent(event Event) (bool, error) {
-	// if notification integration is not installed then do not send the notification
+	// if notification integration is not installed then do not send the notification, in-app inbox is still written
 	moduleInfo, err := impl.moduleService.GetModuleInfo(module.ModuleNameNotification)
 	if err != nil {
 		impl.logger.Errorw("error while getting notification module status", "err", err)
 		return false, err
 	}
-	if moduleInfo.Status != module.ModuleStatusInstalled {
+	notifierInstalled := moduleInfo.Status == module.ModuleStatusInstalled
+	if !notifierInstalled && !impl.config.InboxEnabled {
 		impl.logger.Warnw("Notification module is not installed, hence skipping sending notification", "currentModuleStatus", moduleInfo.Status)
 		return false, nil
 	}
@@ -200,13 +325,9 @@ func (impl *EventRESTClientImpl) WriteNotificationEvent(event Event) (bool, erro
 	payload := impl.buildFinalPayload(event, cdPipeline, ciPipeline)
 	event.Payload = payload
 
-	isPreStageExist := false
-	isPostStageExist := false
-	if cdPipeline != nil && len(cdPipeline.PreStageConfig) > 0 {
-		isPreStageExist = true
-	}
-	if cdPipeline != nil && len(cdPipeline.PostStageConfig) > 0 {
-		isPostStageExist = true
+	isPreStageExist, isPostStageExist, err := impl.getConfiguredCdStages(cdPipeline)
+	if err != nil {
+		return false, err
 	}
 
 	attribute, err := impl.attributesRepository.FindByKey(attributes.HostUrlKey)
@@ -218,12 +339,12 @@ func (impl *EventRESTClientImpl) WriteNotificationEvent(event Event) (bool, erro
 		event.BaseUrl = attribute.Value
 	}
 	if event.CdWorkflowType == "" {
-		_, err = impl.sendEvent(event)
+		_, err = impl.publishEvent(event, notifierInstalled)
 	} else if event.CdWorkflowType == bean.CD_WORKFLOW_TYPE_PRE {
 		if event.EventTypeId == int(util.Success) {
 			impl.logger.Debug("skip - will send from deployment or post stage")
 		} else {
-			_, err = impl.sendEvent(event)
+			_, err = impl.publishEvent(event, notifierInstalled)
 		}
 	} else if event.CdWorkflowType == bean.CD_WORKFLOW_TYPE_DEPLOY {
 		if isPreStageExist && event.EventTypeId == int(util.Trigger) {
@@ -231,40 +352,121 @@ func (impl *EventRESTClientImpl) WriteNotificationEvent(event Event) (bool, erro
 		} else if isPostStageExist && event.EventTypeId == int(util.Success) {
 			impl.logger.Debug("skip - will send from post stage")
 		} else {
-			_, err = impl.sendEvent(event)
+			_, err = impl.publishEvent(event, notifierInstalled)
 		}
 	} else if event.CdWorkflowType == bean.CD_WORKFLOW_TYPE_POST {
 		if event.EventTypeId == int(util.Trigger) {
 			impl.logger.Debug("skip - already sent from pre or deployment stage")
 		} else {
-			_, err = impl.sendEvent(event)
+			_, err = impl.publishEvent(event, notifierInstalled)
 		}
 	}
 	return true, err
 }
 
+// getConfiguredCdStages checks both yaml based and plugin based pre and post deploy stages of pipeline
+func (impl *EventRESTClientImpl) getConfiguredCdStages(cdPipeline *pipelineConfig.Pipeline) (isPreStageExist bool, isPostStageExist bool, err error) {
+	if cdPipeline == nil {
+		return false, false, nil
+	}
+	isPreStageExist = len(cdPipeline.PreStageConfig) > 0
+	isPostStageExist = len(cdPipeline.PostStageConfig) > 0
+	if isPreStageExist && isPostStageExist {
+		return isPreStageExist, isPostStageExist, nil
+	}
+	stages, err := impl.pipelineStageRepository.GetCdStagesWithStepsByCdPipelineIds([]int{cdPipeline.Id})
+	if err != nil {
+		impl.logger.Errorw("error in fetching cd stages of pipeline", "err", err, "pipelineId", cdPipeline.Id)
+		return false, false, err
+	}
+	for _, stage := range stages {
+		if stage.Type == repository2.PIPELINE_STAGE_TYPE_PRE_CD {
+			isPreStageExist = true
+		} else if stage.Type == repository2.PIPELINE_STAGE_TYPE_POST_CD {
+			isPostStageExist = true
+		}
+	}
+	return isPreStageExist, isPostStageExist, nil
+}
+
+// publishEvent writes event to in-app inbox of subscribed users and to outbox when notification module is installed
+func (impl *EventRESTClientImpl) publishEvent(event Event, notifierInstalled bool) (bool, error) {
+	impl.notificationInboxWriter.WriteInboxItems(event)
+	if !notifierInstalled {
+		return false, nil
+	}
+	return impl.sendEvent(event)
+}
+
 // do not call this method if notification module is not installed
+// sendEvent writes event to outbox with a delivery to notifier and to every webhook subscribed to it, deliveries are
+// sent by DeliverPendingNotifications. When a subscribed setting is not immediate, notifier deliveries are made per
+// setting and event is added to batches of settings which hold it back
 func (impl *EventRESTClientImpl) sendEvent(event Event) (bool, error) {
 	impl.logger.Debugw("event before send", "event", event)
-	body, err := json.Marshal(event)
+	settings, err := impl.notificationSettingsRepository.FindNotificationSettingsByEvent(event.PipelineType, event.EventTypeId, event.PipelineId, event.TeamId, event.AppId, event.EnvId)
 	if err != nil {
-		impl.logger.Errorw("error while marshaling event request ", "err", err)
+		impl.logger.Errorw("error in fetching notification settings of event", "err", err, "pipelineId", event.PipelineId, "eventTypeId", event.EventTypeId)
 		return false, err
 	}
-	var reqBody = []byte(body)
-	req, err := http.NewRequest(http.MethodPost, impl.config.DestinationURL, bytes.NewBuffer(reqBody))
+	immediateSettings, batchedSettings := SplitSettingsByDeliveryMode(settings)
+	var deliveries []*repository.NotificationDelivery
+	var batches []*repository.NotificationBatch
+	if len(batchedSettings) > 0 && !SupportsDeliveryModes(impl.config.NotifierContractVersion) {
+		impl.logger.Debugw("notifier does not support delivery modes, sending event to all settings immediately", "notifierContractVersion", impl.config.NotifierContractVersion, "eventTypeId", event.EventTypeId)
+		batchedSettings = nil
+	}
+	if len(batchedSettings) == 0 {
+		deliveries = []*repository.NotificationDelivery{{Channel: repository.NotifierDeliveryChannel}}
+		for _, webhookId := range impl.getWebhookIdsOfSettings(settings) {
+			deliveries = append(deliveries, &repository.NotificationDelivery{Channel: string(util.Webhook), ConfigId: webhookId})
+		}
+	} else {
+		var sendNowSettings []*repository.NotificationSettings
+		sendNowSettings, batches, err = impl.resolveNotificationBatches(event, batchedSettings)
+		if err != nil {
+			return false, err
+		}
+		deliveries = impl.getDeliveriesOfSettings(append(immediateSettings, sendNowSettings...))
+	}
+	notificationEvent, err := impl.buildOutboxEvent(event, deliveries)
 	if err != nil {
-		impl.logger.Errorw("error while writing event", "err", err)
 		return false, err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := impl.client.Do(req)
+	err = impl.notificationOutboxRepository.SaveEvent(notificationEvent, deliveries)
 	if err != nil {
-		impl.logger.Errorw("error while UpdateJiraTransition request ", "err", err)
+		impl.logger.Errorw("error in writing event to notification outbox", "err", err, "pipelineId", event.PipelineId, "eventTypeId", event.EventTypeId)
 		return false, err
 	}
-	impl.logger.Debugw("event completed", "event resp", resp)
-	return true, err
+	impl.addEventToNotificationBatches(notificationEvent, batches)
+	return true, nil
+}
+
+// buildOutboxEvent builds outbox row of an event and marks its deliveries pending
+func (impl *EventRESTClientImpl) buildOutboxEvent(event Event, deliveries []*repository.NotificationDelivery) (*repository.NotificationEvent, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		impl.logger.Errorw("error while marshaling event request ", "err", err)
+		return nil, err
+	}
+	now := time.Now()
+	auditLog := sql.AuditLog{CreatedOn: now, CreatedBy: int32(event.UserId), UpdatedOn: now, UpdatedBy: int32(event.UserId)}
+	for _, delivery := range deliveries {
+		delivery.Status = repository.NotificationDeliveryPending
+		delivery.NextAttemptOn = now
+		delivery.AuditLog = auditLog
+	}
+	notificationEvent := &repository.NotificationEvent{
+		EventTypeId:  event.EventTypeId,
+		PipelineType: event.PipelineType,
+		PipelineId:   event.PipelineId,
+		AppId:        event.AppId,
+		EnvId:        event.EnvId,
+		TeamId:       event.TeamId,
+		Payload:      string(body),
+		AuditLog:     auditLog,
+	}
+	return notificationEvent, nil
 }
 
 func (impl *EventRESTClientImpl) WriteNatsEvent(topic string, payload interface{}) error {
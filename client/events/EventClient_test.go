@@ -0,0 +1,76 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package client
+
+import (
+	"testing"
+
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	repository2 "github.com/devtron-labs/devtron/pkg/pipeline/repository"
+	"go.uber.org/zap"
+)
+
+type fakePipelineStageRepository struct {
+	repository2.PipelineStageRepository
+	stages []*repository2.PipelineStage
+}
+
+func (repo *fakePipelineStageRepository) GetCdStagesWithStepsByCdPipelineIds(cdPipelineIds []int) ([]*repository2.PipelineStage, error) {
+	var stages []*repository2.PipelineStage
+	for _, stage := range repo.stages {
+		for _, cdPipelineId := range cdPipelineIds {
+			if stage.CdPipelineId == cdPipelineId {
+				stages = append(stages, stage)
+			}
+		}
+	}
+	return stages, nil
+}
+
+func TestGetConfiguredCdStages(t *testing.T) {
+	stageRepository := &fakePipelineStageRepository{stages: []*repository2.PipelineStage{
+		{Id: 1, CdPipelineId: 2, Type: repository2.PIPELINE_STAGE_TYPE_PRE_CD},
+		{Id: 2, CdPipelineId: 3, Type: repository2.PIPELINE_STAGE_TYPE_PRE_CD},
+		{Id: 3, CdPipelineId: 3, Type: repository2.PIPELINE_STAGE_TYPE_POST_CD},
+	}}
+	tests := []struct {
+		name          string
+		cdPipeline    *pipelineConfig.Pipeline
+		wantPreStage  bool
+		wantPostStage bool
+	}{
+		{name: "no pipeline"},
+		{name: "no stages", cdPipeline: &pipelineConfig.Pipeline{Id: 1}},
+		{name: "yaml stages", cdPipeline: &pipelineConfig.Pipeline{Id: 1, PreStageConfig: "pre", PostStageConfig: "post"}, wantPreStage: true, wantPostStage: true},
+		{name: "plugin pre stage", cdPipeline: &pipelineConfig.Pipeline{Id: 2}, wantPreStage: true},
+		{name: "yaml post and plugin pre stage", cdPipeline: &pipelineConfig.Pipeline{Id: 2, PostStageConfig: "post"}, wantPreStage: true, wantPostStage: true},
+		{name: "plugin pre and post stages", cdPipeline: &pipelineConfig.Pipeline{Id: 3}, wantPreStage: true, wantPostStage: true},
+	}
+	impl := &EventRESTClientImpl{logger: zap.NewNop().Sugar(), pipelineStageRepository: stageRepository}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isPreStageExist, isPostStageExist, err := impl.getConfiguredCdStages(tt.cdPipeline)
+			if err != nil {
+				t.Fatalf("getConfiguredCdStages() error = %v", err)
+			}
+			if isPreStageExist != tt.wantPreStage || isPostStageExist != tt.wantPostStage {
+				t.Errorf("getConfiguredCdStages() = %v, %v, want %v, %v", isPreStageExist, isPostStageExist, tt.wantPreStage, tt.wantPostStage)
+			}
+		})
+	}
+}
@@ -0,0 +1,298 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/pkg/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	util "github.com/devtron-labs/devtron/util/event"
+	"github.com/go-pg/pg"
+)
+
+// notificationBatchFlushLease is the time a claimed batch is hidden from other workers while it is being flushed
+const notificationBatchFlushLease = 5 * time.Minute
+
+// digestBatchKey is the batch key of digest settings, all events of a setting go in one digest
+const digestBatchKey = "digest"
+
+// NotifierProvidersContractVersion is the first notifier contract which sends events carrying payload providers only
+// to these providers, per setting deliveries and digests need it
+const NotifierProvidersContractVersion = 2
+
+// SupportsDeliveryModes returns true when notifier of contract version delivers events to providers of one setting
+func SupportsDeliveryModes(notifierContractVersion int) bool {
+	return notifierContractVersion >= NotifierProvidersContractVersion
+}
+
+// IsImmediateDeliveryMode returns true when events of a setting with delivery mode are sent as they happen
+func IsImmediateDeliveryMode(deliveryMode string) bool {
+	return len(deliveryMode) == 0 || deliveryMode == string(util.DeliveryImmediate)
+}
+
+// SplitSettingsByDeliveryMode separates settings which send events immediately from settings which batch them
+func SplitSettingsByDeliveryMode(settings []*repository.NotificationSettings) (immediate []*repository.NotificationSettings, batched []*repository.NotificationSettings) {
+	for _, setting := range settings {
+		if IsImmediateDeliveryMode(setting.DeliveryMode) {
+			immediate = append(immediate, setting)
+		} else {
+			batched = append(batched, setting)
+		}
+	}
+	return immediate, batched
+}
+
+// NotificationBatchKey groups events of a setting in batches, de-duplicated events are grouped by their source and
+// event type while digests hold every event of the setting
+func NotificationBatchKey(deliveryMode string, event Event) string {
+	if deliveryMode == string(util.DeliveryDedup) {
+		return fmt.Sprintf("%s/%d/%d/%d/%d", event.PipelineType, event.PipelineId, event.AppId, event.EnvId, event.EventTypeId)
+	}
+	return digestBatchKey
+}
+
+// NotificationBatchFlushOn returns when a batch opened at now is flushed, de-duplication windows start with the first
+// event while hourly and daily digests are flushed at start of the next hour or day
+func NotificationBatchFlushOn(deliveryMode string, dedupWindowMinutes int, now time.Time) time.Time {
+	switch util.DeliveryMode(deliveryMode) {
+	case util.DeliveryDedup:
+		return now.Add(time.Duration(dedupWindowMinutes) * time.Minute)
+	case util.DeliveryHourlyDigest:
+		return now.Truncate(time.Hour).Add(time.Hour)
+	default:
+		return time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+	}
+}
+
+// BuildDigestPayload summarises batched events with a count per pipeline and event type, items are in order of
+// their first event
+func BuildDigestPayload(deliveryMode string, from time.Time, to time.Time, events []Event) *DigestPayload {
+	digest := &DigestPayload{
+		Title:        digestTitle(deliveryMode),
+		DeliveryMode: deliveryMode,
+		From:         from.Format(bean.LayoutRFC3339),
+		To:           to.Format(bean.LayoutRFC3339),
+		Items:        []*DigestItem{},
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].EventTime < events[j].EventTime
+	})
+	itemsMap := make(map[string]*DigestItem)
+	for _, event := range events {
+		key := fmt.Sprintf("%s/%d/%d/%d/%d", event.PipelineType, event.PipelineId, event.AppId, event.EnvId, event.EventTypeId)
+		item, ok := itemsMap[key]
+		if !ok {
+			item = &DigestItem{
+				EventTypeId:  event.EventTypeId,
+				EventName:    util.EventType(event.EventTypeId).String(),
+				PipelineType: event.PipelineType,
+				PipelineId:   event.PipelineId,
+			}
+			if event.Payload != nil {
+				item.AppName = event.Payload.AppName
+				item.EnvName = event.Payload.EnvName
+				item.PipelineName = event.Payload.PipelineName
+			}
+			itemsMap[key] = item
+			digest.Items = append(digest.Items, item)
+		}
+		item.Count = item.Count + 1
+		item.LastEventTime = event.EventTime
+		digest.TotalCount = digest.TotalCount + 1
+	}
+	return digest
+}
+
+func digestTitle(deliveryMode string) string {
+	switch util.DeliveryMode(deliveryMode) {
+	case util.DeliveryDedup:
+		return "Repeated notifications"
+	case util.DeliveryHourlyDigest:
+		return "Hourly notification digest"
+	default:
+		return "Daily notification digest"
+	}
+}
+
+func getProvidersOfSetting(setting *repository.NotificationSettings) ([]*NotificationProvider, error) {
+	var providers []*NotificationProvider
+	err := json.Unmarshal([]byte(setting.Config), &providers)
+	return providers, err
+}
+
+// getDeliveriesOfSettings returns a notifier delivery for every setting with slack, ses or smtp recipients, notifier
+// sends these to recipients of the setting only, and a delivery for every webhook of settings
+func (impl *EventRESTClientImpl) getDeliveriesOfSettings(settings []*repository.NotificationSettings) []*repository.NotificationDelivery {
+	var deliveries []*repository.NotificationDelivery
+	for _, setting := range settings {
+		providers, err := getProvidersOfSetting(setting)
+		if err != nil {
+			impl.logger.Errorw("error in unmarshal notification setting providers", "err", err, "id", setting.Id)
+			continue
+		}
+		for _, provider := range providers {
+			if provider.Destination != util.Webhook {
+				deliveries = append(deliveries, &repository.NotificationDelivery{Channel: repository.NotifierDeliveryChannel, ConfigId: setting.Id})
+				break
+			}
+		}
+	}
+	for _, webhookId := range impl.getWebhookIdsOfSettings(settings) {
+		deliveries = append(deliveries, &repository.NotificationDelivery{Channel: string(util.Webhook), ConfigId: webhookId})
+	}
+	return deliveries
+}
+
+// resolveNotificationBatches finds batches an event goes to, batches which are not open yet are returned unsaved.
+// First event of a de-duplication window is sent right away so its setting is returned in sendNowSettings and its
+// batch only counts repeats
+func (impl *EventRESTClientImpl) resolveNotificationBatches(event Event, settings []*repository.NotificationSettings) (sendNowSettings []*repository.NotificationSettings, batches []*repository.NotificationBatch, err error) {
+	now := time.Now()
+	for _, setting := range settings {
+		batchKey := NotificationBatchKey(setting.DeliveryMode, event)
+		batch, err := impl.notificationBatchRepository.FindBatch(setting.Id, batchKey)
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error in fetching notification batch", "err", err, "notificationSettingId", setting.Id, "batchKey", batchKey)
+			return nil, nil, err
+		}
+		if err == pg.ErrNoRows {
+			batch = &repository.NotificationBatch{
+				NotificationSettingId: setting.Id,
+				BatchKey:              batchKey,
+				DeliveryMode:          setting.DeliveryMode,
+				WindowStart:           now,
+				FlushOn:               NotificationBatchFlushOn(setting.DeliveryMode, setting.DedupWindowMinutes, now),
+				AuditLog:              sql.AuditLog{CreatedOn: now, CreatedBy: int32(event.UserId), UpdatedOn: now, UpdatedBy: int32(event.UserId)},
+			}
+			if setting.DeliveryMode == string(util.DeliveryDedup) {
+				sendNowSettings = append(sendNowSettings, setting)
+			}
+		}
+		batches = append(batches, batch)
+	}
+	return sendNowSettings, batches, nil
+}
+
+// addEventToNotificationBatches opens new batches and adds event to open ones, event which opened a de-duplication
+// window was already sent and is not added
+func (impl *EventRESTClientImpl) addEventToNotificationBatches(notificationEvent *repository.NotificationEvent, batches []*repository.NotificationBatch) {
+	for _, batch := range batches {
+		var err error
+		if batch.Id == 0 {
+			if batch.DeliveryMode != string(util.DeliveryDedup) {
+				batch.EventIds = []int{notificationEvent.Id}
+				batch.EventCount = 1
+			}
+			err = impl.notificationBatchRepository.SaveBatch(batch)
+		} else {
+			err = impl.notificationBatchRepository.AddEventToBatch(batch.Id, notificationEvent.Id)
+		}
+		if err != nil {
+			impl.logger.Errorw("error in adding event to notification batch", "err", err, "eventId", notificationEvent.Id, "notificationSettingId", batch.NotificationSettingId, "batchKey", batch.BatchKey)
+		}
+	}
+}
+
+func (impl *EventRESTClientImpl) FlushNotificationBatches(batchSize int) {
+	if !SupportsDeliveryModes(impl.config.NotifierContractVersion) {
+		return
+	}
+	batches, err := impl.notificationBatchRepository.ClaimDueBatches(batchSize, time.Now().Add(notificationBatchFlushLease))
+	if err != nil {
+		impl.logger.Errorw("error in claiming notification batches", "err", err)
+		return
+	}
+	if len(batches) == 0 {
+		return
+	}
+	var settingIds []int
+	for _, batch := range batches {
+		settingIds = append(settingIds, batch.NotificationSettingId)
+	}
+	settings, err := impl.notificationSettingsRepository.FindNotificationSettingsByIds(settingIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching notification settings of batches", "err", err, "ids", settingIds)
+		return
+	}
+	settingsMap := make(map[int]*repository.NotificationSettings)
+	for _, setting := range settings {
+		settingsMap[setting.Id] = setting
+	}
+	for _, batch := range batches {
+		err = impl.flushNotificationBatch(batch, settingsMap[batch.NotificationSettingId])
+		if err != nil {
+			impl.logger.Errorw("error in flushing notification batch", "err", err, "batchId", batch.Id)
+		}
+	}
+}
+
+// flushNotificationBatch writes digest of a batch to outbox, batches without events or whose setting was deleted are
+// dropped
+func (impl *EventRESTClientImpl) flushNotificationBatch(batch *repository.NotificationBatch, setting *repository.NotificationSettings) error {
+	if setting == nil || batch.EventCount == 0 {
+		return impl.notificationBatchRepository.FlushBatch(batch, nil, nil)
+	}
+	notificationEvents, err := impl.notificationOutboxRepository.FindEventsByIds(batch.EventIds)
+	if err != nil {
+		return err
+	}
+	var events []Event
+	baseUrl := ""
+	for _, notificationEvent := range notificationEvents {
+		var event Event
+		err = json.Unmarshal([]byte(notificationEvent.Payload), &event)
+		if err != nil {
+			impl.logger.Errorw("error in unmarshal batched notification event", "err", err, "eventId", notificationEvent.Id)
+			continue
+		}
+		baseUrl = event.BaseUrl
+		events = append(events, event)
+	}
+	now := time.Now()
+	digestEvent := Event{
+		EventTypeId:   int(util.NotificationDigest),
+		PipelineType:  setting.PipelineType,
+		CorrelationId: fmt.Sprintf("notification-batch-%d", batch.Id),
+		EventTime:     now.Format(bean.LayoutRFC3339),
+		BaseUrl:       baseUrl,
+		Payload:       &Payload{Digest: BuildDigestPayload(batch.DeliveryMode, batch.WindowStart, now, events)},
+	}
+	if setting.PipelineId != nil {
+		digestEvent.PipelineId = *setting.PipelineId
+	}
+	if setting.TeamId != nil {
+		digestEvent.TeamId = *setting.TeamId
+	}
+	if setting.AppId != nil {
+		digestEvent.AppId = *setting.AppId
+	}
+	if setting.EnvId != nil {
+		digestEvent.EnvId = *setting.EnvId
+	}
+	deliveries := impl.getDeliveriesOfSettings([]*repository.NotificationSettings{setting})
+	notificationEvent, err := impl.buildOutboxEvent(digestEvent, deliveries)
+	if err != nil {
+		return err
+	}
+	return impl.notificationBatchRepository.FlushBatch(batch, notificationEvent, deliveries)
+}
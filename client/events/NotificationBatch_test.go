@@ -0,0 +1,88 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package client
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	util "github.com/devtron-labs/devtron/util/event"
+)
+
+func TestNotificationBatchFlushOn(t *testing.T) {
+	now := time.Date(2022, 9, 14, 10, 25, 30, 0, time.UTC)
+	tests := []struct {
+		name         string
+		deliveryMode util.DeliveryMode
+		want         time.Time
+	}{
+		{name: "dedup window", deliveryMode: util.DeliveryDedup, want: time.Date(2022, 9, 14, 10, 35, 30, 0, time.UTC)},
+		{name: "hourly digest", deliveryMode: util.DeliveryHourlyDigest, want: time.Date(2022, 9, 14, 11, 0, 0, 0, time.UTC)},
+		{name: "daily digest", deliveryMode: util.DeliveryDailyDigest, want: time.Date(2022, 9, 15, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NotificationBatchFlushOn(string(tt.deliveryMode), 10, now); !got.Equal(tt.want) {
+				t.Errorf("NotificationBatchFlushOn() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildDigestPayload(t *testing.T) {
+	failure := func(pipelineId int, eventTime string) Event {
+		return Event{EventTypeId: int(util.Fail), PipelineType: string(util.CI), PipelineId: pipelineId, EventTime: eventTime,
+			Payload: &Payload{AppName: "payments", PipelineName: "build"}}
+	}
+	events := []Event{
+		failure(2, "2022-09-14T10:20:00Z"),
+		failure(1, "2022-09-14T10:05:00Z"),
+		{EventTypeId: int(util.Success), PipelineType: string(util.CI), PipelineId: 1, EventTime: "2022-09-14T10:30:00Z"},
+		failure(1, "2022-09-14T10:10:00Z"),
+	}
+	digest := BuildDigestPayload(string(util.DeliveryHourlyDigest), time.Now(), time.Now(), events)
+	var got []string
+	for _, item := range digest.Items {
+		got = append(got, item.EventName+":"+item.LastEventTime)
+	}
+	want := []string{"Failure:2022-09-14T10:10:00Z", "Failure:2022-09-14T10:20:00Z", "Success:2022-09-14T10:30:00Z"}
+	if !reflect.DeepEqual(got, want) || digest.TotalCount != 4 || digest.Items[0].Count != 2 || digest.Items[0].AppName != "payments" {
+		t.Errorf("BuildDigestPayload() = %v with total %d, want %v with total 4", got, digest.TotalCount, want)
+	}
+}
+
+func TestSupportsDeliveryModes(t *testing.T) {
+	tests := []struct {
+		name                    string
+		notifierContractVersion int
+		want                    bool
+	}{
+		{name: "unset contract", notifierContractVersion: 0},
+		{name: "notifier resolving settings", notifierContractVersion: 1},
+		{name: "notifier honouring providers", notifierContractVersion: 2, want: true},
+		{name: "newer notifier", notifierContractVersion: 3, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SupportsDeliveryModes(tt.notifierContractVersion); got != tt.want {
+				t.Errorf("SupportsDeliveryModes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/devtron-labs/devtron/api/sse"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/pkg/attributes"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	repository2 "github.com/devtron-labs/devtron/pkg/user/repository"
+	util "github.com/devtron-labs/devtron/util/event"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"go.uber.org/zap"
+)
+
+// NotificationInboxPreferencesKey is the user attribute holding NotificationInboxPreferences of a user as json
+const NotificationInboxPreferencesKey = "notificationInboxPreferences"
+
+// NotificationInboxSSEEvent is the server sent event name of items pushed to inbox stream
+const NotificationInboxSSEEvent = "inbox"
+
+// NotificationInboxPreferences select events which go to in-app inbox of a user, events of subscribed teams, apps
+// and environments and events of user's own triggers. EventTypeIds limits inbox to these event types when set
+type NotificationInboxPreferences struct {
+	Enabled      bool  `json:"enabled"`
+	TeamIds      []int `json:"teamIds"`
+	AppIds       []int `json:"appIds"`
+	EnvIds       []int `json:"envIds"`
+	OwnTriggers  bool  `json:"ownTriggers"`
+	EventTypeIds []int `json:"eventTypeIds"`
+}
+
+// DefaultNotificationInboxPreferences are used for users who have not set preferences, they get their own triggers
+func DefaultNotificationInboxPreferences() *NotificationInboxPreferences {
+	return &NotificationInboxPreferences{Enabled: true, OwnTriggers: true}
+}
+
+// NotificationInboxNamespace is the sse namespace of inbox stream of a user, it ends with a slash so that namespace
+// of a user is not a prefix of another user's namespace
+func NotificationInboxNamespace(userId int32) string {
+	return fmt.Sprintf("/notification/inbox/%d/", userId)
+}
+
+// MatchesInboxPreferences returns true when event goes to inbox of user with preferences
+func MatchesInboxPreferences(preferences *NotificationInboxPreferences, event Event, userId int32) bool {
+	if preferences == nil || !preferences.Enabled {
+		return false
+	}
+	if len(preferences.EventTypeIds) > 0 && !containsId(preferences.EventTypeIds, event.EventTypeId) {
+		return false
+	}
+	if preferences.OwnTriggers && event.UserId > 0 && int32(event.UserId) == userId {
+		return true
+	}
+	return (event.TeamId > 0 && containsId(preferences.TeamIds, event.TeamId)) ||
+		(event.AppId > 0 && containsId(preferences.AppIds, event.AppId)) ||
+		(event.EnvId > 0 && containsId(preferences.EnvIds, event.EnvId))
+}
+
+func containsId(ids []int, id int) bool {
+	for _, item := range ids {
+		if item == id {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildInboxItem builds inbox item of an event for a user, link points to build or deployment history of event
+func BuildInboxItem(event Event, userId int32) *repository.NotificationInboxItem {
+	now := time.Now()
+	item := &repository.NotificationInboxItem{
+		UserId:       userId,
+		EventTypeId:  event.EventTypeId,
+		PipelineType: event.PipelineType,
+		PipelineId:   event.PipelineId,
+		AppId:        event.AppId,
+		EnvId:        event.EnvId,
+		TeamId:       event.TeamId,
+		Title:        util.EventType(event.EventTypeId).String(),
+		AuditLog:     sql.AuditLog{CreatedOn: now, CreatedBy: 1, UpdatedOn: now, UpdatedBy: 1},
+	}
+	if payload := event.Payload; payload != nil {
+		item.AppName = payload.AppName
+		item.EnvName = payload.EnvName
+		item.PipelineName = payload.PipelineName
+		item.Stage = payload.Stage
+		item.TriggeredBy = payload.TriggeredBy
+		if event.PipelineType == string(util.CI) {
+			item.Link = payload.BuildHistoryLink
+		} else if event.CdWorkflowRunnerId > 0 {
+			item.Link = payload.DeploymentHistoryLink
+		} else {
+			item.Link = payload.AppDetailLink
+		}
+	}
+	return item
+}
+
+type NotificationInboxWriter interface {
+	// WriteInboxItems stores event in inbox of every user subscribed to it and pushes it to their inbox streams
+	WriteInboxItems(event Event)
+}
+
+type NotificationInboxWriterImpl struct {
+	logger                      *zap.SugaredLogger
+	config                      *EventClientConfig
+	notificationInboxRepository repository.NotificationInboxRepository
+	userAttributesService       attributes.UserAttributesService
+	userRepository              repository2.UserRepository
+	enforcer                    casbin.Enforcer
+	enforcerUtil                rbac.EnforcerUtil
+	sse                         *sse.SSE
+}
+
+func NewNotificationInboxWriterImpl(logger *zap.SugaredLogger, config *EventClientConfig,
+	notificationInboxRepository repository.NotificationInboxRepository, userAttributesService attributes.UserAttributesService,
+	userRepository repository2.UserRepository, enforcer casbin.Enforcer, enforcerUtil rbac.EnforcerUtil, sse *sse.SSE) *NotificationInboxWriterImpl {
+	return &NotificationInboxWriterImpl{
+		logger:                      logger,
+		config:                      config,
+		notificationInboxRepository: notificationInboxRepository,
+		userAttributesService:       userAttributesService,
+		userRepository:              userRepository,
+		enforcer:                    enforcer,
+		enforcerUtil:                enforcerUtil,
+		sse:                         sse,
+	}
+}
+
+func (impl *NotificationInboxWriterImpl) WriteInboxItems(event Event) {
+	if !impl.config.InboxEnabled {
+		return
+	}
+	userAttributes, err := impl.userAttributesService.GetUserAttributesByKey(NotificationInboxPreferencesKey)
+	if err != nil {
+		impl.logger.Errorw("error in fetching inbox preferences of users", "err", err)
+		return
+	}
+	emailIds := make(map[int32]string)
+	preferences := make(map[int32]*NotificationInboxPreferences)
+	for _, userAttribute := range userAttributes {
+		userPreferences := &NotificationInboxPreferences{}
+		err = json.Unmarshal([]byte(userAttribute.Value), userPreferences)
+		if err != nil {
+			impl.logger.Errorw("error in unmarshal inbox preferences", "err", err, "emailId", userAttribute.EmailId)
+			continue
+		}
+		emailIds[userAttribute.UserId] = userAttribute.EmailId
+		preferences[userAttribute.UserId] = userPreferences
+	}
+	triggeredBy := int32(event.UserId)
+	if _, ok := preferences[triggeredBy]; !ok && triggeredBy > 1 {
+		user, err := impl.userRepository.GetById(triggeredBy)
+		if err == nil && user.Active {
+			emailIds[triggeredBy] = user.EmailId
+			preferences[triggeredBy] = DefaultNotificationInboxPreferences()
+		}
+	}
+	var items []*repository.NotificationInboxItem
+	for userId, userPreferences := range preferences {
+		if !MatchesInboxPreferences(userPreferences, event, userId) {
+			continue
+		}
+		if userId != triggeredBy && !impl.canViewEvent(emailIds[userId], event) {
+			continue
+		}
+		items = append(items, BuildInboxItem(event, userId))
+	}
+	if len(items) == 0 {
+		return
+	}
+	err = impl.notificationInboxRepository.SaveItems(items)
+	if err != nil {
+		impl.logger.Errorw("error in saving inbox items", "err", err, "pipelineId", event.PipelineId, "eventTypeId", event.EventTypeId)
+		return
+	}
+	for _, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			impl.logger.Errorw("error in marshal inbox item", "err", err, "id", item.Id)
+			continue
+		}
+		impl.sse.OutboundChannel <- sse.SSEMessage{Event: NotificationInboxSSEEvent, Data: data, Namespace: NotificationInboxNamespace(item.UserId)}
+	}
+}
+
+// canViewEvent checks that a subscriber can view app of event, events without an app need view access on their
+// cluster or on notifications
+func (impl *NotificationInboxWriterImpl) canViewEvent(emailId string, event Event) bool {
+	if event.AppId > 0 {
+		return impl.enforcer.EnforceByEmail(emailId, casbin.ResourceApplications, casbin.ActionGet, impl.enforcerUtil.GetAppRBACNameByAppId(event.AppId))
+	}
+	if event.Payload != nil && event.Payload.Cluster != nil {
+		return impl.enforcer.EnforceByEmail(emailId, casbin.ResourceCluster, casbin.ActionGet, strings.ToLower(event.Payload.Cluster.ClusterName))
+	}
+	return impl.enforcer.EnforceByEmail(emailId, casbin.ResourceNotification, casbin.ActionGet, "*")
+}
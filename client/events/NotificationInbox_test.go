@@ -0,0 +1,50 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package client
+
+import (
+	"testing"
+)
+
+func TestMatchesInboxPreferences(t *testing.T) {
+	event := Event{EventTypeId: 3, TeamId: 2, AppId: 5, EnvId: 7, UserId: 10}
+	tests := []struct {
+		name        string
+		preferences *NotificationInboxPreferences
+		userId      int32
+		want        bool
+	}{
+		{name: "nil preferences", preferences: nil, userId: 10, want: false},
+		{name: "disabled", preferences: &NotificationInboxPreferences{Enabled: false, OwnTriggers: true}, userId: 10, want: false},
+		{name: "own trigger", preferences: DefaultNotificationInboxPreferences(), userId: 10, want: true},
+		{name: "other user trigger", preferences: DefaultNotificationInboxPreferences(), userId: 11, want: false},
+		{name: "team subscription", preferences: &NotificationInboxPreferences{Enabled: true, TeamIds: []int{2}}, userId: 11, want: true},
+		{name: "app subscription", preferences: &NotificationInboxPreferences{Enabled: true, AppIds: []int{4, 5}}, userId: 11, want: true},
+		{name: "env subscription", preferences: &NotificationInboxPreferences{Enabled: true, EnvIds: []int{7}}, userId: 11, want: true},
+		{name: "unrelated subscription", preferences: &NotificationInboxPreferences{Enabled: true, AppIds: []int{6}}, userId: 11, want: false},
+		{name: "event type filtered", preferences: &NotificationInboxPreferences{Enabled: true, TeamIds: []int{2}, EventTypeIds: []int{1}}, userId: 11, want: false},
+		{name: "event type allowed", preferences: &NotificationInboxPreferences{Enabled: true, TeamIds: []int{2}, EventTypeIds: []int{3}}, userId: 11, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchesInboxPreferences(tt.preferences, event, tt.userId); got != tt.want {
+				t.Errorf("MatchesInboxPreferences() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
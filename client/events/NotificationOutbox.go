@@ -0,0 +1,177 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	util "github.com/devtron-labs/devtron/util/event"
+)
+
+// outboxDeliveryLease is the time a claimed delivery is hidden from other workers while it is being sent
+const outboxDeliveryLease = 5 * time.Minute
+
+// NextNotificationAttemptDelay returns the delay before retrying a delivery which failed attempts times, delay starts
+// at base and doubles on every attempt up to max
+func NextNotificationAttemptDelay(attempts int, base time.Duration, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < max; i++ {
+		delay = delay * 2
+	}
+	if delay > max {
+		delay = max
+	}
+	return delay
+}
+
+func (impl *EventRESTClientImpl) DeliverPendingNotifications(batchSize int) {
+	deliveries, err := impl.notificationOutboxRepository.ClaimDueDeliveries(batchSize, time.Now().Add(outboxDeliveryLease))
+	if err != nil {
+		impl.logger.Errorw("error in claiming notification deliveries", "err", err)
+		return
+	}
+	if len(deliveries) == 0 {
+		return
+	}
+	var eventIds []int
+	for _, delivery := range deliveries {
+		eventIds = append(eventIds, delivery.EventId)
+	}
+	events, err := impl.notificationOutboxRepository.FindEventsByIds(eventIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching notification events", "err", err, "eventIds", eventIds)
+		return
+	}
+	eventsMap := make(map[int]*repository.NotificationEvent)
+	for _, event := range events {
+		eventsMap[event.Id] = event
+	}
+	for _, delivery := range deliveries {
+		err = impl.deliver(delivery, eventsMap[delivery.EventId])
+		impl.updateDeliveryAttempt(delivery, err)
+	}
+}
+
+func (impl *EventRESTClientImpl) deliver(delivery *repository.NotificationDelivery, notificationEvent *repository.NotificationEvent) error {
+	if notificationEvent == nil {
+		return fmt.Errorf("notification event %d not found", delivery.EventId)
+	}
+	if delivery.Channel == repository.NotifierDeliveryChannel && delivery.ConfigId > 0 {
+		body, err := impl.buildNotifierEventOfSetting(delivery.ConfigId, notificationEvent)
+		if err != nil {
+			return err
+		}
+		return impl.postNotifierEvent(body)
+	} else if delivery.Channel == repository.NotifierDeliveryChannel {
+		return impl.postNotifierEvent([]byte(notificationEvent.Payload))
+	} else if delivery.Channel == string(util.Webhook) {
+		webhookConfig, err := impl.webhookRepository.FindOne(delivery.ConfigId)
+		if err != nil {
+			return fmt.Errorf("webhook config %d not found: %s", delivery.ConfigId, err.Error())
+		}
+		var event Event
+		err = json.Unmarshal([]byte(notificationEvent.Payload), &event)
+		if err != nil {
+			return err
+		}
+		return impl.postWebhook(webhookConfig, event)
+	}
+	return fmt.Errorf("unsupported delivery channel %s", delivery.Channel)
+}
+
+// buildNotifierEventOfSetting sets slack, ses and smtp recipients of a notification setting on event, notifier sends
+// it to them instead of resolving settings subscribed to the event
+func (impl *EventRESTClientImpl) buildNotifierEventOfSetting(settingId int, notificationEvent *repository.NotificationEvent) ([]byte, error) {
+	settings, err := impl.notificationSettingsRepository.FindNotificationSettingsByIds([]int{settingId})
+	if err != nil {
+		return nil, err
+	}
+	if len(settings) == 0 {
+		return nil, fmt.Errorf("notification setting %d not found", settingId)
+	}
+	providers, err := getProvidersOfSetting(settings[0])
+	if err != nil {
+		return nil, err
+	}
+	var event Event
+	err = json.Unmarshal([]byte(notificationEvent.Payload), &event)
+	if err != nil {
+		return nil, err
+	}
+	if event.Payload == nil {
+		event.Payload = &Payload{}
+	}
+	event.ContractVersion = NotifierProvidersContractVersion
+	event.Payload.Providers = nil
+	for _, provider := range providers {
+		if provider.Destination != util.Webhook {
+			event.Payload.Providers = append(event.Payload.Providers, provider)
+		}
+	}
+	return json.Marshal(event)
+}
+
+// updateDeliveryAttempt records outcome of an attempt, failed deliveries are scheduled with backoff and dead lettered
+// once they run out of attempts
+func (impl *EventRESTClientImpl) updateDeliveryAttempt(delivery *repository.NotificationDelivery, deliveryErr error) {
+	now := time.Now()
+	delivery.Attempts = delivery.Attempts + 1
+	delivery.UpdatedOn = now
+	if deliveryErr == nil {
+		delivery.Status = repository.NotificationDeliveryDelivered
+		delivery.DeliveredOn = now
+		delivery.LastError = ""
+	} else {
+		impl.logger.Errorw("error in delivering notification", "err", deliveryErr, "deliveryId", delivery.Id, "eventId", delivery.EventId, "channel", delivery.Channel, "attempts", delivery.Attempts)
+		delivery.LastError = deliveryErr.Error()
+		if delivery.Attempts >= impl.config.OutboxMaxAttempts {
+			delivery.Status = repository.NotificationDeliveryDeadLetter
+		} else {
+			base := time.Duration(impl.config.OutboxRetryBaseSeconds) * time.Second
+			max := time.Duration(impl.config.OutboxRetryMaxSeconds) * time.Second
+			delivery.NextAttemptOn = now.Add(NextNotificationAttemptDelay(delivery.Attempts, base, max))
+		}
+	}
+	err := impl.notificationOutboxRepository.UpdateDelivery(delivery)
+	if err != nil {
+		impl.logger.Errorw("error in updating notification delivery", "err", err, "deliveryId", delivery.Id)
+	}
+}
+
+func (impl *EventRESTClientImpl) postNotifierEvent(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, impl.config.DestinationURL, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := impl.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	impl.logger.Debugw("event completed", "event resp", resp)
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("notifier responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
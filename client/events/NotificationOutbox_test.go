@@ -0,0 +1,42 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextNotificationAttemptDelay(t *testing.T) {
+	tests := []struct {
+		name     string
+		attempts int
+		want     time.Duration
+	}{
+		{name: "first attempt", attempts: 1, want: 30 * time.Second},
+		{name: "third attempt", attempts: 3, want: 2 * time.Minute},
+		{name: "capped", attempts: 10, want: time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NextNotificationAttemptDelay(tt.attempts, 30*time.Second, time.Hour); got != tt.want {
+				t.Errorf("NextNotificationAttemptDelay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
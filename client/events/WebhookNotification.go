@@ -0,0 +1,120 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package client
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"text/template"
+
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	util "github.com/devtron-labs/devtron/util/event"
+)
+
+// WEBHOOK_SIGNATURE_HEADER carries hex encoded HMAC-SHA256 of the request body keyed with secret of the webhook
+const WEBHOOK_SIGNATURE_HEADER = "X-Devtron-Signature-256"
+
+var webhookTemplateFuncs = template.FuncMap{
+	// json quotes a value so that templates render valid json from free text fields like app name or failure reason
+	"json": func(value interface{}) (string, error) {
+		data, err := json.Marshal(value)
+		return string(data), err
+	},
+}
+
+// ParseWebhookPayloadTemplate parses payload template of a webhook, template is executed with Event
+func ParseWebhookPayloadTemplate(payload string) (*template.Template, error) {
+	return template.New("webhook").Funcs(webhookTemplateFuncs).Option("missingkey=zero").Parse(payload)
+}
+
+// RenderWebhookPayload renders body of a webhook request, event is sent as json when webhook has no payload template
+func RenderWebhookPayload(payload string, event Event) ([]byte, error) {
+	if len(payload) == 0 {
+		return json.Marshal(event)
+	}
+	tmpl, err := ParseWebhookPayloadTemplate(payload)
+	if err != nil {
+		return nil, err
+	}
+	var body bytes.Buffer
+	err = tmpl.Execute(&body, event)
+	if err != nil {
+		return nil, err
+	}
+	return body.Bytes(), nil
+}
+
+// SignWebhookPayload returns value of signature header for a request body
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// getWebhookIdsOfSettings returns webhooks of notification settings, webhooks are delivered by orchestrator as
+// payload templates are go templates
+func (impl *EventRESTClientImpl) getWebhookIdsOfSettings(settings []*repository.NotificationSettings) []int {
+	webhookIdsMap := map[int]bool{}
+	var webhookIds []int
+	for _, setting := range settings {
+		providers, err := getProvidersOfSetting(setting)
+		if err != nil {
+			impl.logger.Errorw("error in unmarshal notification setting providers", "err", err, "id", setting.Id)
+			continue
+		}
+		for _, provider := range providers {
+			if provider.Destination == util.Webhook && provider.ConfigId > 0 && !webhookIdsMap[provider.ConfigId] {
+				webhookIdsMap[provider.ConfigId] = true
+				webhookIds = append(webhookIds, provider.ConfigId)
+			}
+		}
+	}
+	return webhookIds
+}
+
+func (impl *EventRESTClientImpl) postWebhook(webhookConfig *repository.WebhookConfig, event Event) error {
+	body, err := RenderWebhookPayload(webhookConfig.Payload, event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, webhookConfig.WebHookUrl, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range webhookConfig.Header {
+		req.Header.Set(key, value)
+	}
+	if len(webhookConfig.Secret) > 0 {
+		req.Header.Set(WEBHOOK_SIGNATURE_HEADER, SignWebhookPayload(webhookConfig.Secret, body))
+	}
+	resp, err := impl.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package client
+
+import (
+	"testing"
+)
+
+func TestRenderWebhookPayload(t *testing.T) {
+	event := Event{
+		EventTypeId:  3,
+		PipelineType: "CI",
+		AppId:        1,
+		Payload:      &Payload{AppName: "payments", FailureReason: `exit code "1"`},
+	}
+	tests := []struct {
+		name    string
+		payload string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "template",
+			payload: `{"summary": {{json .Payload.AppName}}, "reason": {{json .Payload.FailureReason}}, "type": {{.EventTypeId}}}`,
+			want:    `{"summary": "payments", "reason": "exit code \"1\"", "type": 3}`,
+		},
+		{name: "unknown field", payload: `{{.Payload.Unknown}}`, wantErr: true},
+		{name: "invalid template", payload: `{{.Payload.AppName`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderWebhookPayload(tt.payload, event)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RenderWebhookPayload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("RenderWebhookPayload() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	got := SignWebhookPayload("It's a Secret to Everybody", []byte("Hello, World!"))
+	want := "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17"
+	if got != want {
+		t.Errorf("SignWebhookPayload() = %s, want %s", got, want)
+	}
+}
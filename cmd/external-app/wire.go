@@ -29,12 +29,14 @@ import (
 	"github.com/devtron-labs/devtron/internal/sql/repository"
 	app2 "github.com/devtron-labs/devtron/internal/sql/repository/app"
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/security"
 	"github.com/devtron-labs/devtron/internal/util"
 	appStoreDeploymentTool "github.com/devtron-labs/devtron/pkg/appStore/deployment/tool"
 	appStoreDeploymentGitopsTool "github.com/devtron-labs/devtron/pkg/appStore/deployment/tool/gitops"
 	"github.com/devtron-labs/devtron/pkg/attributes"
 	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
 	delete2 "github.com/devtron-labs/devtron/pkg/delete"
+	"github.com/devtron-labs/devtron/pkg/manifestScan"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	util2 "github.com/devtron-labs/devtron/pkg/util"
 	util3 "github.com/devtron-labs/devtron/util"
@@ -107,6 +109,18 @@ func InitializeApp() (*App, error) {
 		// binding gitops to helm (for hyperion)
 		wire.Bind(new(appStoreDeploymentGitopsTool.AppStoreDeploymentArgoCdService), new(*appStoreDeploymentTool.AppStoreDeploymentHelmServiceImpl)),
 
+		// needed for manifest scan of helm apps
+		manifestScan.GetManifestScanConfig,
+		security.NewManifestScanPolicyRepositoryImpl,
+		wire.Bind(new(security.ManifestScanPolicyRepository), new(*security.ManifestScanPolicyRepositoryImpl)),
+		manifestScan.NewManifestScanResultRepositoryImpl,
+		wire.Bind(new(manifestScan.ManifestScanResultRepository), new(*manifestScan.ManifestScanResultRepositoryImpl)),
+		pipelineConfig.NewCdWorkflowRepositoryImpl,
+		wire.Bind(new(pipelineConfig.CdWorkflowRepository), new(*pipelineConfig.CdWorkflowRepositoryImpl)),
+		manifestScan.NewManifestScanServiceImpl,
+		wire.Bind(new(manifestScan.ManifestScanService), new(*manifestScan.ManifestScanServiceImpl)),
+		// manifest scan of helm apps ends
+
 		wire.Value(chartRepoRepository.RefChartDir("scripts/devtron-reference-helm-charts")),
 
 		router.NewTelemetryRouterImpl,
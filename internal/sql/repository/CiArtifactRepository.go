@@ -63,6 +63,7 @@ type CiArtifactRepository interface {
 	FinDByParentCiArtifactAndCiId(parentCiArtifact int, ciPipelineIds []int) ([]*CiArtifact, error)
 	GetLatest(cdPipelineId int) (int, error)
 	GetByImageDigest(imageDigest string) (artifact *CiArtifact, err error)
+	GetByImage(image string) (artifact *CiArtifact, err error)
 	GetByIds(ids []int) ([]*CiArtifact, error)
 	GetArtifactByCdWorkflowId(cdWorkflowId int) (artifact *CiArtifact, err error)
 }
@@ -517,6 +518,16 @@ func (impl CiArtifactRepositoryImpl) GetByImageDigest(imageDigest string) (*CiAr
 	return artifact, err
 }
 
+func (impl CiArtifactRepositoryImpl) GetByImage(image string) (*CiArtifact, error) {
+	artifact := &CiArtifact{}
+	err := impl.dbConnection.Model(artifact).
+		Column("ci_artifact.*").
+		Where("ci_artifact.image = ? ", image).
+		Order("ci_artifact.id desc").Limit(1).
+		Select()
+	return artifact, err
+}
+
 func (impl CiArtifactRepositoryImpl) GetByIds(ids []int) ([]*CiArtifact, error) {
 	var artifact []*CiArtifact
 	err := impl.dbConnection.Model(&artifact).
@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package repository
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"time"
+)
+
+// NotificationBatch holds events of a notification setting which are not sent immediately, it is flushed as one
+// digest event at FlushOn. BatchKey groups events of a setting, de-duplicated events are grouped per pipeline and
+// event type
+type NotificationBatch struct {
+	tableName             struct{}  `sql:"notification_batch" pg:",discard_unknown_columns"`
+	Id                    int       `sql:"id,pk"`
+	NotificationSettingId int       `sql:"notification_setting_id,notnull"`
+	BatchKey              string    `sql:"batch_key,notnull"`
+	DeliveryMode          string    `sql:"delivery_mode,notnull"`
+	EventIds              []int     `sql:"event_ids,array"`
+	EventCount            int       `sql:"event_count,notnull"`
+	WindowStart           time.Time `sql:"window_start"`
+	FlushOn               time.Time `sql:"flush_on"`
+	sql.AuditLog
+}
+
+type NotificationBatchRepository interface {
+	FindBatch(notificationSettingId int, batchKey string) (*NotificationBatch, error)
+	SaveBatch(batch *NotificationBatch) error
+	AddEventToBatch(batchId int, eventId int) error
+	// ClaimDueBatches returns batches due for flush and moves their flush to leaseUntil so that other replicas do
+	// not pick them while they are being flushed
+	ClaimDueBatches(limit int, leaseUntil time.Time) ([]*NotificationBatch, error)
+	// FlushBatch writes digest event of a batch to outbox and deletes the batch, it fails when events were added to
+	// batch after it was claimed so that the batch is flushed again with them
+	FlushBatch(batch *NotificationBatch, event *NotificationEvent, deliveries []*NotificationDelivery) error
+}
+
+type NotificationBatchRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewNotificationBatchRepositoryImpl(dbConnection *pg.DB) *NotificationBatchRepositoryImpl {
+	return &NotificationBatchRepositoryImpl{dbConnection: dbConnection}
+}
+
+func (impl *NotificationBatchRepositoryImpl) FindBatch(notificationSettingId int, batchKey string) (*NotificationBatch, error) {
+	batch := &NotificationBatch{}
+	err := impl.dbConnection.Model(batch).
+		Where("notification_setting_id = ?", notificationSettingId).
+		Where("batch_key = ?", batchKey).
+		Select()
+	return batch, err
+}
+
+func (impl *NotificationBatchRepositoryImpl) SaveBatch(batch *NotificationBatch) error {
+	return impl.dbConnection.Insert(batch)
+}
+
+func (impl *NotificationBatchRepositoryImpl) AddEventToBatch(batchId int, eventId int) error {
+	_, err := impl.dbConnection.Model((*NotificationBatch)(nil)).
+		Set("event_ids = array_append(event_ids, ?)", eventId).
+		Set("event_count = event_count + 1").
+		Set("updated_on = ?", time.Now()).
+		Where("id = ?", batchId).
+		Update()
+	return err
+}
+
+func (impl *NotificationBatchRepositoryImpl) ClaimDueBatches(limit int, leaseUntil time.Time) ([]*NotificationBatch, error) {
+	var batches []*NotificationBatch
+	query := "UPDATE notification_batch SET flush_on = ? WHERE id IN (" +
+		" SELECT id FROM notification_batch WHERE flush_on <= ? ORDER BY id LIMIT ? FOR UPDATE SKIP LOCKED" +
+		") RETURNING *;"
+	_, err := impl.dbConnection.Query(&batches, query, leaseUntil, time.Now(), limit)
+	return batches, err
+}
+
+func (impl *NotificationBatchRepositoryImpl) FlushBatch(batch *NotificationBatch, event *NotificationEvent, deliveries []*NotificationDelivery) error {
+	return impl.dbConnection.RunInTransaction(func(tx *pg.Tx) error {
+		result, err := tx.Model((*NotificationBatch)(nil)).
+			Where("id = ?", batch.Id).
+			Where("event_count = ?", batch.EventCount).
+			Delete()
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() == 0 {
+			return fmt.Errorf("notification batch %d changed while flushing", batch.Id)
+		}
+		if event == nil {
+			return nil
+		}
+		err = tx.Insert(event)
+		if err != nil {
+			return err
+		}
+		for _, delivery := range deliveries {
+			delivery.EventId = event.Id
+			err = tx.Insert(delivery)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"time"
+)
+
+// NotificationInboxItem is an event in in-app inbox of a user, it is unread till the user marks it read
+type NotificationInboxItem struct {
+	tableName    struct{}  `sql:"notification_inbox" pg:",discard_unknown_columns"`
+	Id           int       `sql:"id,pk"`
+	UserId       int32     `sql:"user_id,notnull"`
+	EventTypeId  int       `sql:"event_type_id,notnull"`
+	PipelineType string    `sql:"pipeline_type"`
+	PipelineId   int       `sql:"pipeline_id"`
+	AppId        int       `sql:"app_id"`
+	EnvId        int       `sql:"env_id"`
+	TeamId       int       `sql:"team_id"`
+	Title        string    `sql:"title,notnull"`
+	AppName      string    `sql:"app_name"`
+	EnvName      string    `sql:"env_name"`
+	PipelineName string    `sql:"pipeline_name"`
+	Stage        string    `sql:"stage"`
+	TriggeredBy  string    `sql:"triggered_by"`
+	Link         string    `sql:"link"`
+	Read         bool      `sql:"read,notnull"`
+	ReadOn       time.Time `sql:"read_on"`
+	sql.AuditLog
+}
+
+type NotificationInboxRepository interface {
+	SaveItems(items []*NotificationInboxItem) error
+	FindByUserId(userId int32, unreadOnly bool, offset int, size int) ([]*NotificationInboxItem, error)
+	CountUnread(userId int32) (int, error)
+	// MarkRead marks items of a user read, every unread item of the user is marked when ids is empty
+	MarkRead(userId int32, ids []int) (int, error)
+}
+
+type NotificationInboxRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewNotificationInboxRepositoryImpl(dbConnection *pg.DB) *NotificationInboxRepositoryImpl {
+	return &NotificationInboxRepositoryImpl{dbConnection: dbConnection}
+}
+
+func (impl *NotificationInboxRepositoryImpl) SaveItems(items []*NotificationInboxItem) error {
+	return impl.dbConnection.RunInTransaction(func(tx *pg.Tx) error {
+		for _, item := range items {
+			err := tx.Insert(item)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (impl *NotificationInboxRepositoryImpl) FindByUserId(userId int32, unreadOnly bool, offset int, size int) ([]*NotificationInboxItem, error) {
+	var items []*NotificationInboxItem
+	query := impl.dbConnection.Model(&items).Where("user_id = ?", userId)
+	if unreadOnly {
+		query = query.Where("read = ?", false)
+	}
+	err := query.Order("id DESC").Offset(offset).Limit(size).Select()
+	return items, err
+}
+
+func (impl *NotificationInboxRepositoryImpl) CountUnread(userId int32) (int, error) {
+	return impl.dbConnection.Model((*NotificationInboxItem)(nil)).
+		Where("user_id = ?", userId).
+		Where("read = ?", false).
+		Count()
+}
+
+func (impl *NotificationInboxRepositoryImpl) MarkRead(userId int32, ids []int) (int, error) {
+	query := impl.dbConnection.Model((*NotificationInboxItem)(nil)).
+		Set("read = ?", true).
+		Set("read_on = ?", time.Now()).
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", userId).
+		Where("user_id = ?", userId).
+		Where("read = ?", false)
+	if len(ids) > 0 {
+		query = query.Where("id in (?)", pg.In(ids))
+	}
+	result, err := query.Update()
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
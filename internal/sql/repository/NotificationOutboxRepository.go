@@ -0,0 +1,183 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"time"
+)
+
+type NotificationDeliveryStatus string
+
+const (
+	NotificationDeliveryPending    NotificationDeliveryStatus = "PENDING"
+	NotificationDeliveryDelivered  NotificationDeliveryStatus = "DELIVERED"
+	NotificationDeliveryDeadLetter NotificationDeliveryStatus = "DEAD_LETTER"
+)
+
+// NotifierDeliveryChannel is the delivery of an event to notifier, which sends it to slack, ses and smtp recipients
+const NotifierDeliveryChannel = "notifier"
+
+// NotificationEvent is an event written to outbox, Payload is the event json delivered to every channel
+type NotificationEvent struct {
+	tableName    struct{} `sql:"notification_event" pg:",discard_unknown_columns"`
+	Id           int      `sql:"id,pk"`
+	EventTypeId  int      `sql:"event_type_id,notnull"`
+	PipelineType string   `sql:"pipeline_type"`
+	PipelineId   int      `sql:"pipeline_id"`
+	AppId        int      `sql:"app_id"`
+	EnvId        int      `sql:"env_id"`
+	TeamId       int      `sql:"team_id"`
+	Payload      string   `sql:"payload,notnull"`
+	sql.AuditLog
+}
+
+// NotificationDelivery is the delivery of an outbox event to a channel, ConfigId is set for webhooks. Pending
+// deliveries are retried at NextAttemptOn till they are delivered or dead lettered
+type NotificationDelivery struct {
+	tableName     struct{}                   `sql:"notification_delivery" pg:",discard_unknown_columns"`
+	Id            int                        `sql:"id,pk"`
+	EventId       int                        `sql:"event_id,notnull"`
+	Channel       string                     `sql:"channel,notnull"`
+	ConfigId      int                        `sql:"config_id"`
+	Status        NotificationDeliveryStatus `sql:"status,notnull"`
+	Attempts      int                        `sql:"attempts,notnull"`
+	NextAttemptOn time.Time                  `sql:"next_attempt_on"`
+	DeliveredOn   time.Time                  `sql:"delivered_on"`
+	LastError     string                     `sql:"last_error"`
+	sql.AuditLog
+}
+
+type NotificationDeliveryFilter struct {
+	EventId      int
+	PipelineId   int
+	PipelineType string
+	Channel      string
+	Status       string
+	Offset       int
+	Size         int
+}
+
+type NotificationOutboxRepository interface {
+	SaveEvent(event *NotificationEvent, deliveries []*NotificationDelivery) error
+	// ClaimDueDeliveries returns pending deliveries due for an attempt and moves their next attempt to leaseUntil so
+	// that other replicas do not pick them while they are being delivered
+	ClaimDueDeliveries(limit int, leaseUntil time.Time) ([]*NotificationDelivery, error)
+	UpdateDelivery(delivery *NotificationDelivery) error
+	FindEventById(id int) (*NotificationEvent, error)
+	FindEventsByIds(ids []int) ([]*NotificationEvent, error)
+	FindDeliveries(filter *NotificationDeliveryFilter) ([]*NotificationDelivery, error)
+	ResetDeliveriesOfEvent(eventId int, userId int32) (int, error)
+}
+
+type NotificationOutboxRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewNotificationOutboxRepositoryImpl(dbConnection *pg.DB) *NotificationOutboxRepositoryImpl {
+	return &NotificationOutboxRepositoryImpl{dbConnection: dbConnection}
+}
+
+func (impl *NotificationOutboxRepositoryImpl) SaveEvent(event *NotificationEvent, deliveries []*NotificationDelivery) error {
+	return impl.dbConnection.RunInTransaction(func(tx *pg.Tx) error {
+		err := tx.Insert(event)
+		if err != nil {
+			return err
+		}
+		for _, delivery := range deliveries {
+			delivery.EventId = event.Id
+			err = tx.Insert(delivery)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (impl *NotificationOutboxRepositoryImpl) ClaimDueDeliveries(limit int, leaseUntil time.Time) ([]*NotificationDelivery, error) {
+	var deliveries []*NotificationDelivery
+	query := "UPDATE notification_delivery SET next_attempt_on = ? WHERE id IN (" +
+		" SELECT id FROM notification_delivery WHERE status = ? AND next_attempt_on <= ? ORDER BY id LIMIT ? FOR UPDATE SKIP LOCKED" +
+		") RETURNING *;"
+	_, err := impl.dbConnection.Query(&deliveries, query, leaseUntil, NotificationDeliveryPending, time.Now(), limit)
+	return deliveries, err
+}
+
+func (impl *NotificationOutboxRepositoryImpl) UpdateDelivery(delivery *NotificationDelivery) error {
+	return impl.dbConnection.Update(delivery)
+}
+
+func (impl *NotificationOutboxRepositoryImpl) FindEventById(id int) (*NotificationEvent, error) {
+	event := &NotificationEvent{}
+	err := impl.dbConnection.Model(event).Where("id = ?", id).Select()
+	return event, err
+}
+
+func (impl *NotificationOutboxRepositoryImpl) FindEventsByIds(ids []int) ([]*NotificationEvent, error) {
+	var events []*NotificationEvent
+	if len(ids) == 0 {
+		return events, nil
+	}
+	err := impl.dbConnection.Model(&events).Where("id in (?)", pg.In(ids)).Select()
+	return events, err
+}
+
+func (impl *NotificationOutboxRepositoryImpl) FindDeliveries(filter *NotificationDeliveryFilter) ([]*NotificationDelivery, error) {
+	var deliveries []*NotificationDelivery
+	query := impl.dbConnection.Model(&deliveries)
+	if filter.EventId > 0 {
+		query = query.Where("notification_delivery.event_id = ?", filter.EventId)
+	}
+	if filter.PipelineId > 0 || len(filter.PipelineType) > 0 {
+		query = query.Join("INNER JOIN notification_event ne ON ne.id = notification_delivery.event_id")
+		if filter.PipelineId > 0 {
+			query = query.Where("ne.pipeline_id = ?", filter.PipelineId)
+		}
+		if len(filter.PipelineType) > 0 {
+			query = query.Where("ne.pipeline_type = ?", filter.PipelineType)
+		}
+	}
+	if len(filter.Channel) > 0 {
+		query = query.Where("notification_delivery.channel = ?", filter.Channel)
+	}
+	if len(filter.Status) > 0 {
+		query = query.Where("notification_delivery.status = ?", filter.Status)
+	}
+	err := query.Order("notification_delivery.id DESC").Offset(filter.Offset).Limit(filter.Size).Select()
+	return deliveries, err
+}
+
+// ResetDeliveriesOfEvent makes every delivery of an event pending again with no attempts, delivered channels are
+// sent again as well
+func (impl *NotificationOutboxRepositoryImpl) ResetDeliveriesOfEvent(eventId int, userId int32) (int, error) {
+	result, err := impl.dbConnection.Model((*NotificationDelivery)(nil)).
+		Set("status = ?", NotificationDeliveryPending).
+		Set("attempts = 0").
+		Set("next_attempt_on = ?", time.Now()).
+		Set("last_error = NULL").
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", userId).
+		Where("event_id = ?", eventId).
+		Update()
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
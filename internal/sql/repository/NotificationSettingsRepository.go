@@ -20,6 +20,7 @@ package repository
 import (
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/orm"
 	"strconv"
 )
 
@@ -41,6 +42,8 @@ type NotificationSettingsRepository interface {
 	FindNotificationSettingBuildOptions(settingRequest *SearchRequest) ([]*SettingOptionDTO, error)
 	FetchNotificationSettingGroupBy(viewId int) ([]NotificationSettings, error)
 	FindNotificationSettingsByConfigIdAndConfigType(configId int, configType string) ([]*NotificationSettings, error)
+	FindNotificationSettingsByEvent(pipelineType string, eventTypeId int, pipelineId int, teamId int, appId int, envId int) ([]*NotificationSettings, error)
+	FindNotificationSettingsByIds(ids []int) ([]*NotificationSettings, error)
 }
 
 type NotificationSettingsRepositoryImpl struct {
@@ -83,6 +86,9 @@ type NotificationSettings struct {
 	EventTypeId  int      `sql:"event_type_id"`
 	Config       string   `sql:"config"`
 	ViewId       int      `sql:"view_id"`
+	// DeliveryMode is one of util.DeliveryMode, empty is immediate. DedupWindowMinutes is used by de-duplicating mode
+	DeliveryMode       string `sql:"delivery_mode"`
+	DedupWindowMinutes int    `sql:"dedup_window_minutes"`
 }
 
 type SettingOptionDTO struct {
@@ -316,4 +322,37 @@ func (impl *NotificationSettingsRepositoryImpl) FindNotificationSettingsByConfig
 		return nil, err
 	}
 	return notificationSettings, nil
-}
\ No newline at end of file
+}
+
+// FindNotificationSettingsByEvent returns settings subscribed to an event, either by its pipeline or by team, app and
+// env where a missing value matches every team, app or env
+func (impl *NotificationSettingsRepositoryImpl) FindNotificationSettingsByEvent(pipelineType string, eventTypeId int, pipelineId int, teamId int, appId int, envId int) ([]*NotificationSettings, error) {
+	var notificationSettings []*NotificationSettings
+	err := impl.dbConnection.Model(&notificationSettings).
+		Where("pipeline_type = ?", pipelineType).
+		Where("event_type_id = ?", eventTypeId).
+		WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+			q = q.WhereOr("pipeline_id = ?", pipelineId).
+				WhereOrGroup(func(q *orm.Query) (*orm.Query, error) {
+					q = q.Where("pipeline_id is null").
+						Where("team_id is null or team_id = ?", teamId).
+						Where("app_id is null or app_id = ?", appId).
+						Where("env_id is null or env_id = ?", envId)
+					return q, nil
+				})
+			return q, nil
+		}).Select()
+	if err != nil {
+		return nil, err
+	}
+	return notificationSettings, nil
+}
+
+func (impl *NotificationSettingsRepositoryImpl) FindNotificationSettingsByIds(ids []int) ([]*NotificationSettings, error) {
+	var notificationSettings []*NotificationSettings
+	if len(ids) == 0 {
+		return notificationSettings, nil
+	}
+	err := impl.dbConnection.Model(&notificationSettings).Where("id in (?)", pg.In(ids)).Select()
+	return notificationSettings, err
+}
@@ -43,6 +43,8 @@ type UserAttributesRepository interface {
 	AddUserAttribute(attrDto *UserAttributesDao) (*UserAttributesDao, error)
 	UpdateDataValByKey(attrDto *UserAttributesDao) error
 	GetDataValueByKey(attrDto *UserAttributesDao) (string, error)
+	// GetDataValuesByKey returns value of a key for every active user who has set it
+	GetDataValuesByKey(key string) ([]*UserAttributesDao, error)
 }
 
 type UserAttributesRepositoryImpl struct {
@@ -115,3 +117,18 @@ func (repo UserAttributesRepositoryImpl) GetDataValueByKey(attrDto *UserAttribut
 	}
 	return response, err
 }
+
+func (repo UserAttributesRepositoryImpl) GetDataValuesByKey(key string) ([]*UserAttributesDao, error) {
+	var attributes []*UserAttributesDao
+	query := "SELECT ua.email_id, u.id AS user_id, ua.user_data::jsonb ->> ? AS value FROM user_attributes ua" +
+		" INNER JOIN users u ON u.email_id = ua.email_id AND u.active = true" +
+		" WHERE jsonb_exists(ua.user_data::jsonb, ?);"
+	_, err := repo.dbConnection.Query(&attributes, query, key, key)
+	if err != nil {
+		return nil, err
+	}
+	for _, attribute := range attributes {
+		attribute.Key = key
+	}
+	return attributes, nil
+}
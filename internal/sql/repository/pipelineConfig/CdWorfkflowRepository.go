@@ -59,6 +59,7 @@ type CdWorkflowRepository interface {
 	FetchAllCdStagesLatestEntity(pipelineIds []int) ([]*CdWorkflowStatus, error)
 	FetchAllCdStagesLatestEntityStatus(wfrIds []int) ([]*CdWorkflowRunner, error)
 	ExistsByStatus(status string) (bool, error)
+	FindArtifactIdsByPipelineIdAndRunnerStatus(pipelineId int, runnerType bean.WorkflowType, statuses []string) ([]int, error)
 }
 
 type CdWorkflowRepositoryImpl struct {
@@ -180,10 +181,12 @@ type CdWorkflowStatus struct {
 }
 
 type CiWorkflowStatus struct {
-	CiPipelineId      int    `json:"ciPipelineId"`
-	CiPipelineName    string `json:"ciPipelineName,omitempty"`
-	CiStatus          string `json:"ciStatus"`
-	StorageConfigured bool   `json:"storageConfigured"`
+	CiPipelineId       int        `json:"ciPipelineId"`
+	CiPipelineName     string     `json:"ciPipelineName,omitempty"`
+	CiStatus           string     `json:"ciStatus"`
+	StorageConfigured  bool       `json:"storageConfigured"`
+	CronSchedule       string     `json:"cronSchedule,omitempty"`
+	NextScheduledRunOn *time.Time `json:"nextScheduledRunOn,omitempty"`
 }
 
 func NewCdWorkflowRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *CdWorkflowRepositoryImpl {
@@ -501,3 +504,12 @@ func (impl *CdWorkflowRepositoryImpl) ExistsByStatus(status string) (bool, error
 		Exists()
 	return exists, err
 }
+
+func (impl *CdWorkflowRepositoryImpl) FindArtifactIdsByPipelineIdAndRunnerStatus(pipelineId int, runnerType bean.WorkflowType, statuses []string) ([]int, error) {
+	var artifactIds []int
+	query := "SELECT DISTINCT wf.ci_artifact_id FROM cd_workflow_runner wfr" +
+		" INNER JOIN cd_workflow wf ON wf.id = wfr.cd_workflow_id" +
+		" WHERE wf.pipeline_id = ? AND wfr.workflow_type = ? AND wfr.status IN (?);"
+	_, err := impl.dbConnection.Query(&artifactIds, query, pipelineId, runnerType, pg.In(statuses))
+	return artifactIds, err
+}
@@ -43,6 +43,8 @@ type CiPipeline struct {
 	ParentCiPipeline         int    `sql:"parent_ci_pipeline"`
 	ScanEnabled              bool   `sql:"scan_enabled,notnull"`
 	IsDockerConfigOverridden bool   `sql:"is_docker_config_overridden, notnull"`
+	CronSchedule             string `sql:"cron_schedule,notnull"` //standard 5 field cron spec, empty if the pipeline is not scheduled
+	BuildMatrix              string `sql:"build_matrix,notnull"`  //json of bean.CiBuildMatrix, empty if builds are not fanned out
 	sql.AuditLog
 	CiPipelineMaterials []*CiPipelineMaterial
 	CiTemplate          *CiTemplate
@@ -96,6 +98,7 @@ type CiPipelineRepository interface {
 	FinDByParentCiPipelineAndAppId(parentCiPipeline int, appIds []int) ([]*CiPipeline, error)
 	FindAllPipelineInLast24Hour() (pipelines []*CiPipeline, err error)
 	FindNumberOfAppsWithCiPipeline(appIds []int) (count int, err error)
+	FindAllScheduled() (pipelines []*CiPipeline, err error)
 }
 type CiPipelineRepositoryImpl struct {
 	dbConnection *pg.DB
@@ -324,6 +327,16 @@ func (impl CiPipelineRepositoryImpl) FindAllPipelineInLast24Hour() (pipelines []
 	return pipelines, err
 }
 
+func (impl CiPipelineRepositoryImpl) FindAllScheduled() (pipelines []*CiPipeline, err error) {
+	err = impl.dbConnection.Model(&pipelines).
+		Column("ci_pipeline.*").
+		Where("cron_schedule <> ?", "").
+		Where("active = ?", true).
+		Where("deleted = ?", false).
+		Select()
+	return pipelines, err
+}
+
 func (impl CiPipelineRepositoryImpl) FindNumberOfAppsWithCiPipeline(appIds []int) (count int, err error) {
 	var ciPipelines []*CiPipeline
 	count, err = impl.dbConnection.
@@ -38,6 +38,8 @@ type CiWorkflowRepository interface {
 	FindLastTriggeredWorkflowByCiIds(pipelineId []int) (ciWorkflow []*CiWorkflow, err error)
 	FindLastTriggeredWorkflowByArtifactId(ciArtifactId int) (ciWorkflow *CiWorkflow, err error)
 	ExistsByStatus(status string) (bool, error)
+	FindByParentCiWorkflowId(parentCiWorkflowId int) ([]*CiWorkflow, error)
+	FindMatrixLegsByParentIds(parentCiWorkflowIds []int) ([]WorkflowWithArtifact, error)
 }
 
 type CiWorkflowRepositoryImpl struct {
@@ -61,6 +63,9 @@ type CiWorkflow struct {
 	GitTriggers        map[int]GitCommit `sql:"git_triggers"`
 	TriggeredBy        int32             `sql:"triggered_by"`
 	CiArtifactLocation string            `sql:"ci_artifact_location"`
+	ParentCiWorkflowId int               `sql:"parent_ci_workflow_id"` //set for legs of a matrix build
+	MatrixLeg          string            `sql:"matrix_leg"`
+	MatrixImage        string            `sql:"matrix_image"` //image pushed by a matrix leg, manifest list image for parent of a manifest list matrix build
 	CiPipeline         *CiPipeline
 }
 
@@ -82,6 +87,8 @@ type WorkflowWithArtifact struct {
 	CiArtifactLocation string            `json:"ci_artifact_location"`
 	CiArtifactId       int               `json:"ci_artifact_d"`
 	BlobStorageEnabled bool              `json:"blobStorageEnabled"`
+	ParentCiWorkflowId int               `json:"parent_ci_workflow_id"`
+	MatrixLeg          string            `json:"matrix_leg"`
 }
 
 type GitCommit struct {
@@ -136,6 +143,7 @@ func (impl *CiWorkflowRepositoryImpl) FindLastTriggeredWorkflow(pipelineId int)
 	err = impl.dbConnection.Model(workflow).
 		Column("ci_workflow.*", "CiPipeline").
 		Where("ci_workflow.ci_pipeline_id = ? ", pipelineId).
+		Where("ci_workflow.parent_ci_workflow_id is null").
 		Order("ci_workflow.started_on Desc").
 		Limit(1).
 		Select()
@@ -153,7 +161,7 @@ func (impl *CiWorkflowRepositoryImpl) FindByStatusesIn(activeStatuses []string)
 
 func (impl *CiWorkflowRepositoryImpl) FindByPipelineId(pipelineId int, offset int, limit int) ([]WorkflowWithArtifact, error) {
 	var wfs []WorkflowWithArtifact
-	queryTemp := "select cia.id as ci_artifact_id, cia.image, wf.*, u.email_id from ci_workflow wf left join users u on u.id = wf.triggered_by left join ci_artifact cia on wf.id = cia.ci_workflow_id where wf.ci_pipeline_id = ? order by wf.started_on desc offset ? limit ?;"
+	queryTemp := "select cia.id as ci_artifact_id, cia.image, wf.*, u.email_id from ci_workflow wf left join users u on u.id = wf.triggered_by left join ci_artifact cia on wf.id = cia.ci_workflow_id where wf.ci_pipeline_id = ? and wf.parent_ci_workflow_id is null order by wf.started_on desc offset ? limit ?;"
 	_, err := impl.dbConnection.Query(&wfs, queryTemp, pipelineId, offset, limit)
 	if err != nil {
 		return nil, err
@@ -205,6 +213,7 @@ func (impl *CiWorkflowRepositoryImpl) FindLastTriggeredWorkflowByCiIds(pipelineI
 	err = impl.dbConnection.Model(workflow).
 		Column("ci_workflow.*", "CiPipeline").
 		Where("ci_workflow.ci_pipeline_id = ? ", pg.In(pipelineId)).
+		Where("ci_workflow.parent_ci_workflow_id is null").
 		Order("ci_workflow.started_on Desc").
 		Select()
 	return workflow, err
@@ -226,3 +235,26 @@ func (impl *CiWorkflowRepositoryImpl) ExistsByStatus(status string) (bool, error
 		Exists()
 	return exists, err
 }
+
+func (impl *CiWorkflowRepositoryImpl) FindByParentCiWorkflowId(parentCiWorkflowId int) ([]*CiWorkflow, error) {
+	var ciWorkFlows []*CiWorkflow
+	err := impl.dbConnection.Model(&ciWorkFlows).
+		Column("ci_workflow.*").
+		Where("ci_workflow.parent_ci_workflow_id = ?", parentCiWorkflowId).
+		Order("ci_workflow.id").
+		Select()
+	return ciWorkFlows, err
+}
+
+func (impl *CiWorkflowRepositoryImpl) FindMatrixLegsByParentIds(parentCiWorkflowIds []int) ([]WorkflowWithArtifact, error) {
+	var wfs []WorkflowWithArtifact
+	if len(parentCiWorkflowIds) == 0 {
+		return wfs, nil
+	}
+	queryTemp := "select cia.id as ci_artifact_id, cia.image, wf.*, u.email_id from ci_workflow wf left join users u on u.id = wf.triggered_by left join ci_artifact cia on wf.id = cia.ci_workflow_id where wf.parent_ci_workflow_id in (?) order by wf.id;"
+	_, err := impl.dbConnection.Query(&wfs, queryTemp, pg.In(parentCiWorkflowIds))
+	if err != nil {
+		return nil, err
+	}
+	return wfs, err
+}
@@ -18,6 +18,16 @@ const (
 	TIMELINE_STATUS_APP_HEALTHY           TimelineStatus = "HEALTHY"
 	TIMELINE_STATUS_APP_DEGRADED          TimelineStatus = "DEGRADED"
 	TIMELINE_STATUS_DEPLOYMENT_FAILED     TimelineStatus = "FAILED"
+	TIMELINE_STATUS_DEPLOYMENT_QUEUED     TimelineStatus = "DEPLOYMENT_QUEUED"
+	TIMELINE_STATUS_DEPLOYMENT_BLOCKED    TimelineStatus = "DEPLOYMENT_BLOCKED"
+	TIMELINE_STATUS_WINDOW_OVERRIDDEN     TimelineStatus = "DEPLOYMENT_WINDOW_OVERRIDDEN"
+	TIMELINE_STATUS_AUTO_ROLLBACK         TimelineStatus = "AUTO_ROLLBACK_TRIGGERED"
+	TIMELINE_STATUS_AUTO_ROLLBACK_FAILED  TimelineStatus = "AUTO_ROLLBACK_FAILED"
+	TIMELINE_STATUS_CANARY_ANALYSIS       TimelineStatus = "CANARY_ANALYSIS_STARTED"
+	TIMELINE_STATUS_CANARY_STEP_PASSED    TimelineStatus = "CANARY_STEP_PASSED"
+	TIMELINE_STATUS_CANARY_PROMOTED       TimelineStatus = "CANARY_PROMOTED"
+	TIMELINE_STATUS_CANARY_ABORTED        TimelineStatus = "CANARY_ABORTED"
+	TIMELINE_STATUS_SBOM_MISSING          TimelineStatus = "SBOM_MISSING"
 )
 
 type PipelineStatusTimelineRepository interface {
@@ -36,10 +36,25 @@ type CvePolicy struct {
 	Action        PolicyAction `sql:"action, notnull"`
 	Severity      *Severity    `sql:"severity, notnull "`
 	Deleted       bool         `sql:"deleted, notnull"`
+	//exception fields, only set for time bound allow policies
+	Justification    string    `sql:"justification"`
+	Owner            string    `sql:"owner"`
+	ExpiresOn        time.Time `sql:"expires_on"`
+	ExpiryNotifiedOn time.Time `sql:"expiry_notified_on"`
 	sql.AuditLog
 	CveStore *CveStore
 }
 
+// IsException returns true for time bound allow policies
+func (policy *CvePolicy) IsException() bool {
+	return !policy.ExpiresOn.IsZero()
+}
+
+// IsExpired returns true once expiry of an exception has passed, expired exceptions are ignored while applying policies
+func (policy *CvePolicy) IsExpired(now time.Time) bool {
+	return policy.IsException() && !now.Before(policy.ExpiresOn)
+}
+
 type PolicyAction int
 
 const (
@@ -112,13 +127,16 @@ type CvePolicyRepository interface {
 	UpdatePolicy(policy *CvePolicy) (*CvePolicy, error)
 	GetById(id int) (*CvePolicy, error)
 	GetBlockedCVEList(cves []*CveStore, clusterId, envId, appId int, isAppstore bool) ([]*CveStore, error)
+	GetBlockedCves(cves []*CveStore, clusterId, envId, appId int, isAppstore bool) ([]*BlockedCve, error)
+	GetExceptionsToNotifyExpiry(from time.Time, to time.Time) (policies []*CvePolicy, err error)
 }
 type CvePolicyRepositoryImpl struct {
-	dbConnection *pg.DB
+	dbConnection            *pg.DB
+	cvePolicyRuleRepository CvePolicyRuleRepository
 }
 
-func NewPolicyRepositoryImpl(dbConnection *pg.DB) *CvePolicyRepositoryImpl {
-	return &CvePolicyRepositoryImpl{dbConnection: dbConnection}
+func NewPolicyRepositoryImpl(dbConnection *pg.DB, cvePolicyRuleRepository CvePolicyRuleRepository) *CvePolicyRepositoryImpl {
+	return &CvePolicyRepositoryImpl{dbConnection: dbConnection, cvePolicyRuleRepository: cvePolicyRuleRepository}
 }
 func (impl *CvePolicyRepositoryImpl) GetGlobalPolicies() (policies []*CvePolicy, err error) {
 	err = impl.dbConnection.Model(&policies).
@@ -210,6 +228,10 @@ func (impl *CvePolicyRepositoryImpl) SavePolicy(policy *CvePolicy) (*CvePolicy,
 			cvePolicyToUpdate.UpdatedOn = time.Now()
 			cvePolicyToUpdate.UpdatedBy = policy.UpdatedBy
 			cvePolicyToUpdate.Action = policy.Action
+			cvePolicyToUpdate.Justification = policy.Justification
+			cvePolicyToUpdate.Owner = policy.Owner
+			cvePolicyToUpdate.ExpiresOn = policy.ExpiresOn
+			cvePolicyToUpdate.ExpiryNotifiedOn = time.Time{}
 			policy, err = impl.UpdatePolicy(cvePolicyToUpdate)
 		} else {
 			err = impl.dbConnection.Insert(policy)
@@ -219,8 +241,36 @@ func (impl *CvePolicyRepositoryImpl) SavePolicy(policy *CvePolicy) (*CvePolicy,
 }
 func (impl *CvePolicyRepositoryImpl) UpdatePolicy(policy *CvePolicy) (*CvePolicy, error) {
 	_, err := impl.dbConnection.Model(policy).WherePK().UpdateNotNull()
+	if err != nil {
+		return policy, err
+	}
+	if !policy.IsException() {
+		//exception fields are cleared when an exception is turned into a permanent policy
+		_, err = impl.dbConnection.Model(policy).
+			Set("justification = NULL").
+			Set("owner = NULL").
+			Set("expires_on = NULL").
+			Set("expiry_notified_on = NULL").
+			WherePK().
+			Update()
+	} else if policy.ExpiryNotifiedOn.IsZero() {
+		//expiry of an extended exception is notified again
+		_, err = impl.dbConnection.Model(policy).Set("expiry_notified_on = NULL").WherePK().Update()
+	}
 	return policy, err
 }
+
+func (impl *CvePolicyRepositoryImpl) GetExceptionsToNotifyExpiry(from time.Time, to time.Time) (policies []*CvePolicy, err error) {
+	err = impl.dbConnection.Model(&policies).
+		Column("cve_policy.*").
+		Relation("CveStore").
+		Where("deleted = false").
+		Where("expires_on > ?", from).
+		Where("expires_on <= ?", to).
+		Where("expiry_notified_on is null").
+		Select()
+	return policies, err
+}
 func (impl *CvePolicyRepositoryImpl) GetById(id int) (*CvePolicy, error) {
 	cvePolicy := &CvePolicy{Id: id}
 	err := impl.dbConnection.Model(cvePolicy).WherePK().Select()
@@ -228,53 +278,31 @@ func (impl *CvePolicyRepositoryImpl) GetById(id int) (*CvePolicy, error) {
 }
 
 func (impl *CvePolicyRepositoryImpl) GetBlockedCVEList(cves []*CveStore, clusterId, envId, appId int, isAppstore bool) ([]*CveStore, error) {
-
-	cvePolicy, severityPolicy, err := impl.getApplicablePolicy(clusterId, envId, appId, isAppstore)
+	blockedCves, err := impl.GetBlockedCves(cves, clusterId, envId, appId, isAppstore)
 	if err != nil {
 		return nil, err
 	}
-	blockedCve := impl.enforceCvePolicy(cves, cvePolicy, severityPolicy)
-	return blockedCve, nil
-}
-
-func (impl *CvePolicyRepositoryImpl) enforceCvePolicy(cves []*CveStore, cvePolicy map[string]*CvePolicy, severityPolicy map[Severity]*CvePolicy) (blockedCVE []*CveStore) {
-
-	for _, cve := range cves {
-		if policy, ok := cvePolicy[cve.Name]; ok {
-			if policy.Action == Allow {
-				continue
-			} else {
-				blockedCVE = append(blockedCVE, cve)
-			}
-		} else {
-			if severityPolicy[cve.Severity] != nil && severityPolicy[cve.Severity].Action == Allow {
-				continue
-			} else {
-				blockedCVE = append(blockedCVE, cve)
-			}
-		}
+	var blockedCveList []*CveStore
+	for _, blockedCve := range blockedCves {
+		blockedCveList = append(blockedCveList, blockedCve.Cve)
 	}
-	return blockedCVE
+	return blockedCveList, nil
 }
 
-func (impl *CvePolicyRepositoryImpl) getApplicablePolicy(clusterId, envId, appId int, isAppstore bool) (map[string]*CvePolicy, map[Severity]*CvePolicy, error) {
-
-	var policyLevel PolicyLevel
-	if isAppstore && appId > 0 && envId > 0 && clusterId > 0 {
-		policyLevel = Environment
-	} else if appId > 0 && envId > 0 && clusterId > 0 {
-		policyLevel = Application
-	} else if envId > 0 && clusterId > 0 {
-		policyLevel = Environment
-	} else if clusterId > 0 {
-		policyLevel = Cluster
-	} else {
-		//error in case of global or other policy
-		return nil, nil, fmt.Errorf("policy not identified")
+func (impl *CvePolicyRepositoryImpl) GetBlockedCves(cves []*CveStore, clusterId, envId, appId int, isAppstore bool) ([]*BlockedCve, error) {
+	policyLevel, err := GetApplicablePolicyLevel(clusterId, envId, appId, isAppstore)
+	if err != nil {
+		return nil, err
 	}
-
 	cvePolicy, severityPolicy, err := impl.getPolicies(policyLevel, clusterId, envId, appId)
-	return cvePolicy, severityPolicy, err
+	if err != nil {
+		return nil, err
+	}
+	rules, err := impl.cvePolicyRuleRepository.GetRules(policyLevel, clusterId, envId, appId)
+	if err != nil && err != pg.ErrNoRows {
+		return nil, err
+	}
+	return EnforceCvePolicy(cves, cvePolicy, severityPolicy, GetApplicableRules(rules), time.Now()), nil
 }
 
 func (impl *CvePolicyRepositoryImpl) getPolicies(policyLevel PolicyLevel, clusterId, environmentId, appId int) (map[string]*CvePolicy, map[Severity]*CvePolicy, error) {
@@ -304,7 +332,11 @@ func (impl *CvePolicyRepositoryImpl) getPolicies(policyLevel PolicyLevel, cluste
 func (impl *CvePolicyRepositoryImpl) getApplicablePolicies(policies []*CvePolicy) (map[string]*CvePolicy, map[Severity]*CvePolicy) {
 	cvePolicy := make(map[string][]*CvePolicy)
 	severityPolicy := make(map[Severity][]*CvePolicy)
+	now := time.Now()
 	for _, policy := range policies {
+		if policy.IsExpired(now) {
+			continue
+		}
 		if policy.CVEStoreId != "" {
 			cvePolicy[policy.CveStore.Name] = append(cvePolicy[policy.CveStore.Name], policy)
 		} else {
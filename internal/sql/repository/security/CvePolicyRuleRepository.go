@@ -0,0 +1,265 @@
+package security
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/orm"
+	"time"
+)
+
+type CvePolicyRuleType string
+
+const (
+	CvePolicyRuleFixAvailable CvePolicyRuleType = "FIX_AVAILABLE"
+	CvePolicyRulePackage      CvePolicyRuleType = "PACKAGE"
+	CvePolicyRuleThreshold    CvePolicyRuleType = "THRESHOLD"
+)
+
+// rule reported for cves blocked by cve or severity policies
+const (
+	CveBlockedByCvePolicy      = "CVE_POLICY"
+	CveBlockedBySeverityPolicy = "SEVERITY_POLICY"
+)
+
+// CvePolicyRule decides on cves by fix availability, package or count of findings, rules are scoped like CvePolicy
+type CvePolicyRule struct {
+	tableName     struct{}          `sql:"cve_policy_rule" pg:",discard_unknown_columns"`
+	Id            int               `sql:"id,pk"`
+	Global        bool              `sql:"global,notnull"`
+	ClusterId     int               `sql:"cluster_id"`
+	EnvironmentId int               `sql:"env_id"`
+	AppId         int               `sql:"app_id"`
+	RuleType      CvePolicyRuleType `sql:"rule_type,notnull"`
+	Action        PolicyAction      `sql:"action,notnull"`
+	Severity      *Severity         `sql:"severity"`
+	Package       string            `sql:"package"`
+	Threshold     int               `sql:"threshold,notnull"`
+	Deleted       bool              `sql:"deleted,notnull"`
+	sql.AuditLog
+}
+
+func (rule *CvePolicyRule) PolicyLevel() PolicyLevel {
+	if rule.ClusterId != 0 {
+		return Cluster
+	} else if rule.AppId != 0 {
+		return Application
+	} else if rule.EnvironmentId != 0 {
+		return Environment
+	} else {
+		return Global
+	}
+}
+
+// String is the rule reported against a blocked cve
+func (rule *CvePolicyRule) String() string {
+	switch rule.RuleType {
+	case CvePolicyRuleFixAvailable:
+		return fmt.Sprintf("%s(severity=%s)", rule.RuleType, rule.Severity)
+	case CvePolicyRulePackage:
+		if rule.Severity != nil {
+			return fmt.Sprintf("%s(package=%s,severity=%s)", rule.RuleType, rule.Package, rule.Severity)
+		}
+		return fmt.Sprintf("%s(package=%s)", rule.RuleType, rule.Package)
+	case CvePolicyRuleThreshold:
+		return fmt.Sprintf("%s(severity=%s,threshold=%d)", rule.RuleType, rule.Severity, rule.Threshold)
+	}
+	return string(rule.RuleType)
+}
+
+func (rule *CvePolicyRule) key() string {
+	key := string(rule.RuleType) + "/" + rule.Package
+	if rule.Severity != nil {
+		key = key + "/" + rule.Severity.String()
+	}
+	return key
+}
+
+// BlockedCve is a cve blocked by policy along with the rule which blocked it
+type BlockedCve struct {
+	Cve  *CveStore
+	Rule string
+}
+
+// GetApplicablePolicyLevel returns the most specific level at which policies apply for a deployment
+func GetApplicablePolicyLevel(clusterId, envId, appId int, isAppstore bool) (PolicyLevel, error) {
+	if isAppstore && appId > 0 && envId > 0 && clusterId > 0 {
+		return Environment, nil
+	} else if appId > 0 && envId > 0 && clusterId > 0 {
+		return Application, nil
+	} else if envId > 0 && clusterId > 0 {
+		return Environment, nil
+	} else if clusterId > 0 {
+		return Cluster, nil
+	}
+	return Global, fmt.Errorf("policy not identified")
+}
+
+// GetApplicableRules keeps the most specific rule of every rule type, package and severity
+func GetApplicableRules(rules []*CvePolicyRule) []*CvePolicyRule {
+	applicableRules := make(map[string]*CvePolicyRule)
+	var keys []string
+	for _, rule := range rules {
+		key := rule.key()
+		applicableRule, ok := applicableRules[key]
+		if !ok {
+			keys = append(keys, key)
+		}
+		if !ok || rule.PolicyLevel() > applicableRule.PolicyLevel() {
+			applicableRules[key] = rule
+		}
+	}
+	var result []*CvePolicyRule
+	for _, key := range keys {
+		result = append(result, applicableRules[key])
+	}
+	return result
+}
+
+// EnforceCvePolicy returns blocked cves, a cve policy decides first, then package rules, fix available rules and
+// severity policies. Threshold rules block all findings of a severity which are not explicitly allowed once their
+// count exceeds the threshold
+func EnforceCvePolicy(cves []*CveStore, cvePolicy map[string]*CvePolicy, severityPolicy map[Severity]*CvePolicy, rules []*CvePolicyRule, now time.Time) []*BlockedCve {
+	packageRules := make(map[string][]*CvePolicyRule)
+	fixAvailableRules := make(map[Severity]*CvePolicyRule)
+	thresholdRules := make(map[Severity]*CvePolicyRule)
+	for _, rule := range rules {
+		switch rule.RuleType {
+		case CvePolicyRulePackage:
+			packageRules[rule.Package] = append(packageRules[rule.Package], rule)
+		case CvePolicyRuleFixAvailable:
+			fixAvailableRules[*rule.Severity] = rule
+		case CvePolicyRuleThreshold:
+			thresholdRules[*rule.Severity] = rule
+		}
+	}
+
+	var blockedCves []*BlockedCve
+	blocked := make(map[*CveStore]bool)
+	block := func(cve *CveStore, rule string) {
+		blockedCves = append(blockedCves, &BlockedCve{Cve: cve, Rule: rule})
+		blocked[cve] = true
+	}
+	countedCves := make(map[Severity][]*CveStore)
+	for _, cve := range cves {
+		if policy, ok := cvePolicy[cve.Name]; ok && !policy.IsExpired(now) {
+			if policy.Action != Allow {
+				block(cve, CveBlockedByCvePolicy)
+			}
+			continue
+		}
+		if rule := matchPackageRule(packageRules[cve.Package], cve); rule != nil {
+			if rule.Action != Allow {
+				block(cve, rule.String())
+				countedCves[cve.Severity] = append(countedCves[cve.Severity], cve)
+			}
+			continue
+		}
+		countedCves[cve.Severity] = append(countedCves[cve.Severity], cve)
+		if rule, ok := fixAvailableRules[cve.Severity]; ok {
+			if len(cve.FixedVersion) > 0 {
+				block(cve, rule.String())
+			}
+			continue
+		}
+		if policy := severityPolicy[cve.Severity]; policy != nil && !policy.IsExpired(now) && policy.Action == Allow {
+			continue
+		}
+		block(cve, CveBlockedBySeverityPolicy)
+	}
+	for _, severity := range []Severity{Critical, Moderate, Low} {
+		rule, ok := thresholdRules[severity]
+		if !ok || len(countedCves[severity]) <= rule.Threshold {
+			continue
+		}
+		for _, cve := range countedCves[severity] {
+			if !blocked[cve] {
+				block(cve, rule.String())
+			}
+		}
+	}
+	return blockedCves
+}
+
+func matchPackageRule(rules []*CvePolicyRule, cve *CveStore) *CvePolicyRule {
+	var matchedRule *CvePolicyRule
+	for _, rule := range rules {
+		if rule.Severity == nil {
+			if matchedRule == nil {
+				matchedRule = rule
+			}
+		} else if *rule.Severity == cve.Severity {
+			//severity specific rule overrides rule for whole package
+			return rule
+		}
+	}
+	return matchedRule
+}
+
+type CvePolicyRuleRepository interface {
+	SaveRule(rule *CvePolicyRule) (*CvePolicyRule, error)
+	UpdateRule(rule *CvePolicyRule) (*CvePolicyRule, error)
+	GetRuleById(id int) (*CvePolicyRule, error)
+	GetRules(policyLevel PolicyLevel, clusterId, environmentId, appId int) ([]*CvePolicyRule, error)
+}
+
+type CvePolicyRuleRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewCvePolicyRuleRepositoryImpl(dbConnection *pg.DB) *CvePolicyRuleRepositoryImpl {
+	return &CvePolicyRuleRepositoryImpl{dbConnection: dbConnection}
+}
+
+func (impl *CvePolicyRuleRepositoryImpl) SaveRule(rule *CvePolicyRule) (*CvePolicyRule, error) {
+	err := impl.dbConnection.Insert(rule)
+	return rule, err
+}
+
+func (impl *CvePolicyRuleRepositoryImpl) UpdateRule(rule *CvePolicyRule) (*CvePolicyRule, error) {
+	err := impl.dbConnection.Update(rule)
+	return rule, err
+}
+
+func (impl *CvePolicyRuleRepositoryImpl) GetRuleById(id int) (*CvePolicyRule, error) {
+	rule := &CvePolicyRule{}
+	err := impl.dbConnection.Model(rule).
+		Where("id = ?", id).
+		Where("deleted = false").
+		Select()
+	return rule, err
+}
+
+// GetRules returns rules of a level along with rules inherited from higher levels
+func (impl *CvePolicyRuleRepositoryImpl) GetRules(policyLevel PolicyLevel, clusterId, environmentId, appId int) ([]*CvePolicyRule, error) {
+	var rules []*CvePolicyRule
+	query := impl.dbConnection.Model(&rules).Where("deleted = false")
+	switch policyLevel {
+	case Global:
+		query = query.Where("global = true")
+	case Cluster:
+		query = query.WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+			return q.WhereOr("cluster_id = ?", clusterId).WhereOr("global = true"), nil
+		})
+	case Environment:
+		query = query.WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+			return q.WhereOr("cluster_id = ?", clusterId).WhereOr("env_id = ?", environmentId).WhereOr("global = true"), nil
+		}).Where("app_id is null")
+	case Application:
+		query = query.WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+			q = q.WhereOr("cluster_id = ?", clusterId).
+				WhereOrGroup(func(sq *orm.Query) (*orm.Query, error) {
+					return sq.Where("env_id = ?", environmentId).Where("app_id is null"), nil
+				}).
+				WhereOr("global = true").
+				WhereOrGroup(func(sq *orm.Query) (*orm.Query, error) {
+					return sq.Where("app_id = ?", appId).Where("env_id = ?", environmentId), nil
+				})
+			return q, nil
+		})
+	default:
+		return nil, fmt.Errorf("unsupported policy level: %s", policyLevel)
+	}
+	err := query.Order("id ASC").Select()
+	return rules, err
+}
@@ -36,6 +36,7 @@ type ImageScanHistoryRepository interface {
 	Save(model *ImageScanExecutionHistory) error
 	FindAll() ([]*ImageScanExecutionHistory, error)
 	FindOne(id int) (*ImageScanExecutionHistory, error)
+	FindByIds(ids []int) ([]*ImageScanExecutionHistory, error)
 	FindByImageDigest(image string) (*ImageScanExecutionHistory, error)
 	FindByImageDigests(digest []string) ([]*ImageScanExecutionHistory, error)
 	Update(model *ImageScanExecutionHistory) error
@@ -72,6 +73,13 @@ func (impl ImageScanHistoryRepositoryImpl) FindOne(id int) (*ImageScanExecutionH
 	return &model, err
 }
 
+func (impl ImageScanHistoryRepositoryImpl) FindByIds(ids []int) ([]*ImageScanExecutionHistory, error) {
+	var models []*ImageScanExecutionHistory
+	err := impl.dbConnection.Model(&models).
+		Where("id in (?)", pg.In(ids)).Select()
+	return models, err
+}
+
 func (impl ImageScanHistoryRepositoryImpl) FindByImageDigest(image string) (*ImageScanExecutionHistory, error) {
 	var model ImageScanExecutionHistory
 	err := impl.dbConnection.Model(&model).
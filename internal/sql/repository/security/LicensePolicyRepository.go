@@ -0,0 +1,293 @@
+package security
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/orm"
+	"strings"
+)
+
+// AnyLicense is the license of a policy applied to every declared license without a policy of its own
+const AnyLicense = "*"
+
+// LicenseBlockedByLicensePolicy is the rule reported for packages blocked by license policies
+const LicenseBlockedByLicensePolicy = "LICENSE_POLICY"
+
+// MissingSbomLicense is the license of a policy applied to artifacts without sbom, a blocking policy blocks them while
+// otherwise they are deployed with a warning
+const MissingSbomLicense = "NO-SBOM"
+
+// LicenseBlockedByMissingSbomPolicy is the rule reported for artifacts blocked for not having a sbom
+const LicenseBlockedByMissingSbomPolicy = "MISSING_SBOM_POLICY"
+
+// LicensePolicy allows or blocks a spdx license, policies are scoped like CvePolicy
+type LicensePolicy struct {
+	tableName     struct{}     `sql:"license_policy" pg:",discard_unknown_columns"`
+	Id            int          `sql:"id,pk"`
+	Global        bool         `sql:"global,notnull"`
+	ClusterId     int          `sql:"cluster_id"`
+	EnvironmentId int          `sql:"env_id"`
+	AppId         int          `sql:"app_id"`
+	License       string       `sql:"license,notnull"`
+	Action        PolicyAction `sql:"action,notnull"`
+	Deleted       bool         `sql:"deleted,notnull"`
+	sql.AuditLog
+}
+
+func (policy *LicensePolicy) PolicyLevel() PolicyLevel {
+	if policy.ClusterId != 0 {
+		return Cluster
+	} else if policy.AppId != 0 {
+		return Application
+	} else if policy.EnvironmentId != 0 {
+		return Environment
+	} else {
+		return Global
+	}
+}
+
+// PackageLicense is a package of an image along with its spdx license expression
+type PackageLicense struct {
+	Package string
+	Version string
+	License string
+}
+
+// BlockedLicense is a package blocked by license policy along with the licenses which blocked it
+type BlockedLicense struct {
+	Package  *PackageLicense
+	Licenses []string
+	Rule     string
+}
+
+// GetApplicableLicensePolicies keeps the most specific policy of every license, licenses are matched case insensitively
+func GetApplicableLicensePolicies(policies []*LicensePolicy) map[string]*LicensePolicy {
+	applicablePolicies := make(map[string]*LicensePolicy)
+	for _, policy := range policies {
+		key := strings.ToLower(policy.License)
+		if applicablePolicy, ok := applicablePolicies[key]; !ok || policy.PolicyLevel() > applicablePolicy.PolicyLevel() {
+			applicablePolicies[key] = policy
+		}
+	}
+	return applicablePolicies
+}
+
+// IsMissingSbomBlocked tells if the policies block artifacts without sbom, the policy on any license does not apply
+func IsMissingSbomBlocked(licensePolicy map[string]*LicensePolicy) bool {
+	policy := licensePolicy[strings.ToLower(MissingSbomLicense)]
+	return policy != nil && policy.Action == Block
+}
+
+// EnforceMissingSbomPolicy returns the artifact as blocked when the policies block artifacts without sbom
+func EnforceMissingSbomPolicy(licensePolicy map[string]*LicensePolicy) []*BlockedLicense {
+	if !IsMissingSbomBlocked(licensePolicy) {
+		return nil
+	}
+	return []*BlockedLicense{{Package: &PackageLicense{}, Licenses: []string{MissingSbomLicense}, Rule: LicenseBlockedByMissingSbomPolicy}}
+}
+
+// EnforceLicensePolicy returns packages whose license expression is not satisfied by the policies. One allowed
+// alternative of an OR expression is enough while every term of an AND expression has to be allowed. Packages without
+// declared license are not enforced
+func EnforceLicensePolicy(packages []*PackageLicense, licensePolicy map[string]*LicensePolicy) []*BlockedLicense {
+	var blockedLicenses []*BlockedLicense
+	for _, pkg := range packages {
+		if len(strings.TrimSpace(pkg.License)) == 0 {
+			continue
+		}
+		if licenses := evaluateLicenseExpression(pkg.License, licensePolicy); len(licenses) > 0 {
+			blockedLicenses = append(blockedLicenses, &BlockedLicense{Package: pkg, Licenses: licenses, Rule: LicenseBlockedByLicensePolicy})
+		}
+	}
+	return blockedLicenses
+}
+
+// evaluateLicenseExpression returns the blocked licenses of an expression, nothing is returned if it is allowed
+func evaluateLicenseExpression(expression string, licensePolicy map[string]*LicensePolicy) []string {
+	tokens := strings.Fields(strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expression))
+	parser := &licenseExpressionParser{tokens: tokens, licensePolicy: licensePolicy}
+	blocked := parser.parseOr()
+	if parser.invalid || parser.position != len(tokens) {
+		//malformed expressions are matched as a single license
+		return isLicenseBlocked(strings.TrimSpace(expression), licensePolicy)
+	}
+	return blocked
+}
+
+type licenseExpressionParser struct {
+	tokens        []string
+	position      int
+	invalid       bool
+	licensePolicy map[string]*LicensePolicy
+}
+
+func (parser *licenseExpressionParser) next(operator string) bool {
+	if parser.position < len(parser.tokens) && strings.EqualFold(parser.tokens[parser.position], operator) {
+		parser.position++
+		return true
+	}
+	return false
+}
+
+func (parser *licenseExpressionParser) parseOr() []string {
+	blocked := parser.parseAnd()
+	for parser.next("OR") {
+		alternative := parser.parseAnd()
+		if len(blocked) == 0 || len(alternative) == 0 {
+			blocked = nil
+		} else {
+			blocked = append(blocked, alternative...)
+		}
+	}
+	return blocked
+}
+
+func (parser *licenseExpressionParser) parseAnd() []string {
+	blocked := parser.parseTerm()
+	for parser.next("AND") {
+		blocked = append(blocked, parser.parseTerm()...)
+	}
+	return blocked
+}
+
+func (parser *licenseExpressionParser) parseTerm() []string {
+	if parser.next("(") {
+		blocked := parser.parseOr()
+		if !parser.next(")") {
+			parser.invalid = true
+		}
+		return blocked
+	}
+	if parser.position >= len(parser.tokens) || parser.tokens[parser.position] == ")" {
+		parser.invalid = true
+		return nil
+	}
+	license := parser.tokens[parser.position]
+	parser.position++
+	if parser.next("WITH") {
+		if parser.position >= len(parser.tokens) {
+			parser.invalid = true
+			return nil
+		}
+		exception := parser.tokens[parser.position]
+		parser.position++
+		//a policy on license with exception overrides the policy on license
+		if _, ok := parser.licensePolicy[strings.ToLower(license+" WITH "+exception)]; ok {
+			return isLicenseBlocked(license+" WITH "+exception, parser.licensePolicy)
+		}
+	}
+	return isLicenseBlocked(license, parser.licensePolicy)
+}
+
+func isLicenseBlocked(license string, licensePolicy map[string]*LicensePolicy) []string {
+	policy, ok := licensePolicy[strings.ToLower(license)]
+	if !ok {
+		policy = licensePolicy[AnyLicense]
+	}
+	if policy != nil && policy.Action == Block {
+		return []string{license}
+	}
+	return nil
+}
+
+type LicensePolicyRepository interface {
+	SavePolicy(policy *LicensePolicy) (*LicensePolicy, error)
+	UpdatePolicy(policy *LicensePolicy) (*LicensePolicy, error)
+	GetById(id int) (*LicensePolicy, error)
+	GetPolicies(policyLevel PolicyLevel, clusterId, environmentId, appId int) ([]*LicensePolicy, error)
+	GetApplicablePolicies(clusterId, envId, appId int, isAppstore bool) (map[string]*LicensePolicy, error)
+}
+
+type LicensePolicyRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewLicensePolicyRepositoryImpl(dbConnection *pg.DB) *LicensePolicyRepositoryImpl {
+	return &LicensePolicyRepositoryImpl{dbConnection: dbConnection}
+}
+
+// SavePolicy updates the action of an existing policy of the license in same scope, or inserts the policy
+func (impl *LicensePolicyRepositoryImpl) SavePolicy(policy *LicensePolicy) (*LicensePolicy, error) {
+	existingPolicy := &LicensePolicy{}
+	err := impl.dbConnection.Model(existingPolicy).
+		Where("deleted = false").
+		Where("global = ?", policy.Global).
+		Where("COALESCE(cluster_id, 0) = ?", policy.ClusterId).
+		Where("COALESCE(env_id, 0) = ?", policy.EnvironmentId).
+		Where("COALESCE(app_id, 0) = ?", policy.AppId).
+		Where("lower(license) = lower(?)", policy.License).
+		Order("id DESC").Limit(1).
+		Select()
+	if err == pg.ErrNoRows {
+		err = impl.dbConnection.Insert(policy)
+		return policy, err
+	} else if err != nil {
+		return nil, err
+	}
+	existingPolicy.Action = policy.Action
+	existingPolicy.UpdatedOn = policy.UpdatedOn
+	existingPolicy.UpdatedBy = policy.UpdatedBy
+	return impl.UpdatePolicy(existingPolicy)
+}
+
+func (impl *LicensePolicyRepositoryImpl) UpdatePolicy(policy *LicensePolicy) (*LicensePolicy, error) {
+	err := impl.dbConnection.Update(policy)
+	return policy, err
+}
+
+func (impl *LicensePolicyRepositoryImpl) GetById(id int) (*LicensePolicy, error) {
+	policy := &LicensePolicy{}
+	err := impl.dbConnection.Model(policy).
+		Where("id = ?", id).
+		Where("deleted = false").
+		Select()
+	return policy, err
+}
+
+// GetPolicies returns policies of a level along with policies inherited from higher levels
+func (impl *LicensePolicyRepositoryImpl) GetPolicies(policyLevel PolicyLevel, clusterId, environmentId, appId int) ([]*LicensePolicy, error) {
+	var policies []*LicensePolicy
+	query := impl.dbConnection.Model(&policies).Where("deleted = false")
+	switch policyLevel {
+	case Global:
+		query = query.Where("global = true")
+	case Cluster:
+		query = query.WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+			return q.WhereOr("cluster_id = ?", clusterId).WhereOr("global = true"), nil
+		})
+	case Environment:
+		query = query.WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+			return q.WhereOr("cluster_id = ?", clusterId).WhereOr("env_id = ?", environmentId).WhereOr("global = true"), nil
+		}).Where("app_id is null")
+	case Application:
+		query = query.WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+			q = q.WhereOr("cluster_id = ?", clusterId).
+				WhereOrGroup(func(sq *orm.Query) (*orm.Query, error) {
+					return sq.Where("env_id = ?", environmentId).Where("app_id is null"), nil
+				}).
+				WhereOr("global = true").
+				WhereOrGroup(func(sq *orm.Query) (*orm.Query, error) {
+					return sq.Where("app_id = ?", appId).Where("env_id = ?", environmentId), nil
+				})
+			return q, nil
+		})
+	default:
+		return nil, fmt.Errorf("unsupported policy level: %s", policyLevel)
+	}
+	err := query.Order("id ASC").Select()
+	return policies, err
+}
+
+// GetApplicablePolicies returns the most specific policy of every license applicable to the deployment scope
+func (impl *LicensePolicyRepositoryImpl) GetApplicablePolicies(clusterId, envId, appId int, isAppstore bool) (map[string]*LicensePolicy, error) {
+	policyLevel, err := GetApplicablePolicyLevel(clusterId, envId, appId, isAppstore)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := impl.GetPolicies(policyLevel, clusterId, envId, appId)
+	if err != nil && err != pg.ErrNoRows {
+		return nil, err
+	}
+	return GetApplicableLicensePolicies(policies), nil
+}
@@ -0,0 +1,152 @@
+package security
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/orm"
+)
+
+// AnyManifestScanRule is the rule of a policy applied to every manifest scan rule without a policy of its own
+const AnyManifestScanRule = "*"
+
+// ManifestScanPolicy decides whether findings of a manifest scan rule only warn (Allow) or block the deployment (Block),
+// policies are scoped like CvePolicy. A blocking policy also blocks deployments whose manifests could not be scanned
+// unless it is FailOpen
+type ManifestScanPolicy struct {
+	tableName     struct{}     `sql:"manifest_scan_policy" pg:",discard_unknown_columns"`
+	Id            int          `sql:"id,pk"`
+	Global        bool         `sql:"global,notnull"`
+	ClusterId     int          `sql:"cluster_id"`
+	EnvironmentId int          `sql:"env_id"`
+	AppId         int          `sql:"app_id"`
+	RuleId        string       `sql:"rule_id,notnull"`
+	Action        PolicyAction `sql:"action,notnull"`
+	FailOpen      bool         `sql:"fail_open,notnull"`
+	Deleted       bool         `sql:"deleted,notnull"`
+	sql.AuditLog
+}
+
+func (policy *ManifestScanPolicy) PolicyLevel() PolicyLevel {
+	if policy.ClusterId != 0 {
+		return Cluster
+	} else if policy.AppId != 0 {
+		return Application
+	} else if policy.EnvironmentId != 0 {
+		return Environment
+	} else {
+		return Global
+	}
+}
+
+// GetApplicableManifestScanPolicies keeps the most specific policy of every rule
+func GetApplicableManifestScanPolicies(policies []*ManifestScanPolicy) map[string]*ManifestScanPolicy {
+	applicablePolicies := make(map[string]*ManifestScanPolicy)
+	for _, policy := range policies {
+		if applicablePolicy, ok := applicablePolicies[policy.RuleId]; !ok || policy.PolicyLevel() > applicablePolicy.PolicyLevel() {
+			applicablePolicies[policy.RuleId] = policy
+		}
+	}
+	return applicablePolicies
+}
+
+type ManifestScanPolicyRepository interface {
+	SavePolicy(policy *ManifestScanPolicy) (*ManifestScanPolicy, error)
+	UpdatePolicy(policy *ManifestScanPolicy) (*ManifestScanPolicy, error)
+	GetById(id int) (*ManifestScanPolicy, error)
+	GetPolicies(policyLevel PolicyLevel, clusterId, environmentId, appId int) ([]*ManifestScanPolicy, error)
+	GetApplicablePolicies(clusterId, envId, appId int, isAppstore bool) (map[string]*ManifestScanPolicy, error)
+}
+
+type ManifestScanPolicyRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewManifestScanPolicyRepositoryImpl(dbConnection *pg.DB) *ManifestScanPolicyRepositoryImpl {
+	return &ManifestScanPolicyRepositoryImpl{dbConnection: dbConnection}
+}
+
+// SavePolicy updates the action of an existing policy of the rule in same scope, or inserts the policy
+func (impl *ManifestScanPolicyRepositoryImpl) SavePolicy(policy *ManifestScanPolicy) (*ManifestScanPolicy, error) {
+	existingPolicy := &ManifestScanPolicy{}
+	err := impl.dbConnection.Model(existingPolicy).
+		Where("deleted = false").
+		Where("global = ?", policy.Global).
+		Where("COALESCE(cluster_id, 0) = ?", policy.ClusterId).
+		Where("COALESCE(env_id, 0) = ?", policy.EnvironmentId).
+		Where("COALESCE(app_id, 0) = ?", policy.AppId).
+		Where("rule_id = ?", policy.RuleId).
+		Order("id DESC").Limit(1).
+		Select()
+	if err == pg.ErrNoRows {
+		err = impl.dbConnection.Insert(policy)
+		return policy, err
+	} else if err != nil {
+		return nil, err
+	}
+	existingPolicy.Action = policy.Action
+	existingPolicy.FailOpen = policy.FailOpen
+	existingPolicy.UpdatedOn = policy.UpdatedOn
+	existingPolicy.UpdatedBy = policy.UpdatedBy
+	return impl.UpdatePolicy(existingPolicy)
+}
+
+func (impl *ManifestScanPolicyRepositoryImpl) UpdatePolicy(policy *ManifestScanPolicy) (*ManifestScanPolicy, error) {
+	err := impl.dbConnection.Update(policy)
+	return policy, err
+}
+
+func (impl *ManifestScanPolicyRepositoryImpl) GetById(id int) (*ManifestScanPolicy, error) {
+	policy := &ManifestScanPolicy{}
+	err := impl.dbConnection.Model(policy).
+		Where("id = ?", id).
+		Where("deleted = false").
+		Select()
+	return policy, err
+}
+
+// GetPolicies returns policies of a level along with policies inherited from higher levels
+func (impl *ManifestScanPolicyRepositoryImpl) GetPolicies(policyLevel PolicyLevel, clusterId, environmentId, appId int) ([]*ManifestScanPolicy, error) {
+	var policies []*ManifestScanPolicy
+	query := impl.dbConnection.Model(&policies).Where("deleted = false")
+	switch policyLevel {
+	case Global:
+		query = query.Where("global = true")
+	case Cluster:
+		query = query.WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+			return q.WhereOr("cluster_id = ?", clusterId).WhereOr("global = true"), nil
+		})
+	case Environment:
+		query = query.WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+			return q.WhereOr("cluster_id = ?", clusterId).WhereOr("env_id = ?", environmentId).WhereOr("global = true"), nil
+		}).Where("app_id is null")
+	case Application:
+		query = query.WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+			q = q.WhereOr("cluster_id = ?", clusterId).
+				WhereOrGroup(func(sq *orm.Query) (*orm.Query, error) {
+					return sq.Where("env_id = ?", environmentId).Where("app_id is null"), nil
+				}).
+				WhereOr("global = true").
+				WhereOrGroup(func(sq *orm.Query) (*orm.Query, error) {
+					return sq.Where("app_id = ?", appId).Where("env_id = ?", environmentId), nil
+				})
+			return q, nil
+		})
+	default:
+		return nil, fmt.Errorf("unsupported policy level: %s", policyLevel)
+	}
+	err := query.Order("id ASC").Select()
+	return policies, err
+}
+
+func (impl *ManifestScanPolicyRepositoryImpl) GetApplicablePolicies(clusterId, envId, appId int, isAppstore bool) (map[string]*ManifestScanPolicy, error) {
+	policyLevel, err := GetApplicablePolicyLevel(clusterId, envId, appId, isAppstore)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := impl.GetPolicies(policyLevel, clusterId, envId, appId)
+	if err != nil && err != pg.ErrNoRows {
+		return nil, err
+	}
+	return GetApplicableManifestScanPolicies(policies), nil
+}
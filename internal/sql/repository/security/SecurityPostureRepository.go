@@ -0,0 +1,131 @@
+package security
+
+import (
+	"github.com/go-pg/pg"
+	"time"
+)
+
+type PostureScopeType string
+
+const (
+	PostureScopeTeam        PostureScopeType = "team"
+	PostureScopeApp         PostureScopeType = "app"
+	PostureScopeEnvironment PostureScopeType = "environment"
+	PostureScopeCluster     PostureScopeType = "cluster"
+)
+
+// SecurityPostureFinding is a cve found in the image of a deployed object, it stays open until a scan of the deployed
+// image no longer reports it
+type SecurityPostureFinding struct {
+	tableName             struct{}  `sql:"security_posture_finding" pg:",discard_unknown_columns"`
+	Id                    int       `sql:"id,pk"`
+	ImageScanDeployInfoId int       `sql:"image_scan_deploy_info_id,notnull"`
+	AppId                 int       `sql:"app_id"`
+	TeamId                int       `sql:"team_id"`
+	EnvId                 int       `sql:"env_id"`
+	ClusterId             int       `sql:"cluster_id,notnull"`
+	CveStoreName          string    `sql:"cve_store_name,notnull"`
+	Severity              Severity  `sql:"severity,notnull"`
+	FirstSeenOn           time.Time `sql:"first_seen_on,notnull"`
+	RemediatedOn          time.Time `sql:"remediated_on"`
+}
+
+// IsRemediated returns true once the cve is no longer found in the deployed image
+func (finding *SecurityPostureFinding) IsRemediated() bool {
+	return !finding.RemediatedOn.IsZero()
+}
+
+// SecurityPostureSnapshot is the daily count of open cves of a team, app, environment or cluster along with cves
+// remediated on that day
+type SecurityPostureSnapshot struct {
+	tableName        struct{}         `sql:"security_posture_snapshot" pg:",discard_unknown_columns"`
+	Id               int              `sql:"id,pk"`
+	SnapshotDate     time.Time        `sql:"snapshot_date,notnull"`
+	ScopeType        PostureScopeType `sql:"scope_type,notnull"`
+	ScopeId          int              `sql:"scope_id,notnull"`
+	ScopeName        string           `sql:"scope_name"`
+	CriticalCount    int              `sql:"critical_count,notnull"`
+	ModerateCount    int              `sql:"moderate_count,notnull"`
+	LowCount         int              `sql:"low_count,notnull"`
+	RemediatedCount  int              `sql:"remediated_count,notnull"`
+	RemediationHours float64          `sql:"remediation_hours,notnull"`
+	CreatedOn        time.Time        `sql:"created_on,notnull"`
+}
+
+type SecurityPostureRepository interface {
+	FindOpenFindings() ([]*SecurityPostureFinding, error)
+	FindFindingsRemediatedBetween(from time.Time, to time.Time) ([]*SecurityPostureFinding, error)
+	SaveFindings(newFindings []*SecurityPostureFinding, remediatedFindings []*SecurityPostureFinding) error
+	SaveSnapshots(snapshotDate time.Time, snapshots []*SecurityPostureSnapshot) error
+	FindSnapshots(scopeType PostureScopeType, scopeIds []int, from time.Time) ([]*SecurityPostureSnapshot, error)
+}
+
+type SecurityPostureRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewSecurityPostureRepositoryImpl(dbConnection *pg.DB) *SecurityPostureRepositoryImpl {
+	return &SecurityPostureRepositoryImpl{dbConnection: dbConnection}
+}
+
+func (impl SecurityPostureRepositoryImpl) FindOpenFindings() ([]*SecurityPostureFinding, error) {
+	var findings []*SecurityPostureFinding
+	err := impl.dbConnection.Model(&findings).
+		Where("remediated_on is null").
+		Select()
+	return findings, err
+}
+
+func (impl SecurityPostureRepositoryImpl) FindFindingsRemediatedBetween(from time.Time, to time.Time) ([]*SecurityPostureFinding, error) {
+	var findings []*SecurityPostureFinding
+	err := impl.dbConnection.Model(&findings).
+		Where("remediated_on >= ?", from).
+		Where("remediated_on < ?", to).
+		Select()
+	return findings, err
+}
+
+func (impl SecurityPostureRepositoryImpl) SaveFindings(newFindings []*SecurityPostureFinding, remediatedFindings []*SecurityPostureFinding) error {
+	return impl.dbConnection.RunInTransaction(func(tx *pg.Tx) error {
+		if len(newFindings) > 0 {
+			_, err := tx.Model(&newFindings).Insert()
+			if err != nil {
+				return err
+			}
+		}
+		for _, finding := range remediatedFindings {
+			_, err := tx.Model(finding).Set("remediated_on = ?remediated_on").WherePK().Update()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SaveSnapshots replaces snapshots of a day, so that the job can run more than once a day
+func (impl SecurityPostureRepositoryImpl) SaveSnapshots(snapshotDate time.Time, snapshots []*SecurityPostureSnapshot) error {
+	return impl.dbConnection.RunInTransaction(func(tx *pg.Tx) error {
+		_, err := tx.Model((*SecurityPostureSnapshot)(nil)).Where("snapshot_date = ?", snapshotDate).Delete()
+		if err != nil {
+			return err
+		}
+		if len(snapshots) > 0 {
+			_, err = tx.Model(&snapshots).Insert()
+		}
+		return err
+	})
+}
+
+// FindSnapshots returns snapshots of a scope type since a day, snapshots of all scopes are returned if no scope id is set
+func (impl SecurityPostureRepositoryImpl) FindSnapshots(scopeType PostureScopeType, scopeIds []int, from time.Time) ([]*SecurityPostureSnapshot, error) {
+	var snapshots []*SecurityPostureSnapshot
+	query := impl.dbConnection.Model(&snapshots).
+		Where("scope_type = ?", scopeType).
+		Where("snapshot_date >= ?", from)
+	if len(scopeIds) > 0 {
+		query = query.Where("scope_id in (?)", pg.In(scopeIds))
+	}
+	err := query.Order("snapshot_date ASC").Order("scope_id ASC").Select()
+	return snapshots, err
+}
@@ -18,6 +18,7 @@
 package util
 
 import (
+	"encoding/base64"
 	"fmt"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -27,6 +28,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/ecr"
 	"github.com/juju/errors"
 	"log"
+	"strings"
 )
 
 //FIXME: this code is temp
@@ -99,3 +101,44 @@ func CreateEcrRepo(repoName string, reg string, accessKey string, secretKey stri
 	fmt.Println(result)
 	return err
 }
+
+//GetEcrLoginCredentials returns the username and password for docker login to ecr, password is valid for 12 hours
+func GetEcrLoginCredentials(region string, accessKey string, secretKey string) (string, string, error) {
+	var creds *credentials.Credentials
+	if len(accessKey) == 0 || len(secretKey) == 0 {
+		sess, err := session.NewSession(&aws.Config{
+			Region: &region,
+		})
+		if err != nil {
+			return "", "", err
+		}
+		creds = ec2rolecreds.NewCredentials(sess)
+	} else {
+		creds = credentials.NewStaticCredentials(accessKey, secretKey, "")
+	}
+	sess, err := session.NewSession(&aws.Config{
+		Region:      &region,
+		Credentials: creds,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	svc := ecr.New(sess)
+	output, err := svc.GetAuthorizationToken(&ecr.GetAuthorizationTokenInput{})
+	if err != nil {
+		return "", "", err
+	}
+	if len(output.AuthorizationData) == 0 || output.AuthorizationData[0].AuthorizationToken == nil {
+		return "", "", fmt.Errorf("no ecr authorization token found")
+	}
+	token, err := base64.StdEncoding.DecodeString(*output.AuthorizationData[0].AuthorizationToken)
+	if err != nil {
+		return "", "", err
+	}
+	//token is username:password
+	parts := strings.SplitN(string(token), ":", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("invalid ecr authorization token")
+	}
+	return parts[0], parts[1], nil
+}
@@ -42,6 +42,7 @@ import (
 	"github.com/devtron-labs/devtron/internal/sql/repository/helper"
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
 	"github.com/devtron-labs/devtron/internal/util"
+	repository3 "github.com/devtron-labs/devtron/pkg/pipeline/repository"
 	"github.com/devtron-labs/devtron/pkg/prometheus"
 	"github.com/go-pg/pg"
 	"github.com/pkg/errors"
@@ -147,6 +148,7 @@ type AppListingServiceImpl struct {
 	argoUserService            argo.ArgoUserService
 	envOverrideRepository      chartConfig.EnvConfigOverrideRepository
 	chartRepository            chartRepoRepository.ChartRepository
+	pipelineStageRepository    repository3.PipelineStageRepository
 }
 
 func NewAppListingServiceImpl(Logger *zap.SugaredLogger, appListingRepository repository.AppListingRepository,
@@ -156,7 +158,7 @@ func NewAppListingServiceImpl(Logger *zap.SugaredLogger, appListingRepository re
 	envLevelMetricsRepository repository.EnvLevelAppMetricsRepository, cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
 	pipelineOverrideRepository chartConfig.PipelineOverrideRepository, environmentRepository repository2.EnvironmentRepository,
 	argoUserService argo.ArgoUserService, envOverrideRepository chartConfig.EnvConfigOverrideRepository,
-	chartRepository chartRepoRepository.ChartRepository) *AppListingServiceImpl {
+	chartRepository chartRepoRepository.ChartRepository, pipelineStageRepository repository3.PipelineStageRepository) *AppListingServiceImpl {
 	serviceImpl := &AppListingServiceImpl{
 		Logger:                     Logger,
 		appListingRepository:       appListingRepository,
@@ -173,6 +175,7 @@ func NewAppListingServiceImpl(Logger *zap.SugaredLogger, appListingRepository re
 		argoUserService:            argoUserService,
 		envOverrideRepository:      envOverrideRepository,
 		chartRepository:            chartRepository,
+		pipelineStageRepository:    pipelineStageRepository,
 	}
 	return serviceImpl
 }
@@ -383,6 +386,21 @@ func (impl AppListingServiceImpl) fetchACDAppStatus(fetchAppListingRequest Fetch
 		}
 	}
 	releaseMap, _ := impl.ISLastReleaseStopTypeV2(pipelineIds)
+	// plugin based pre and post deploy stages are configured without stage yaml
+	pluginStagesMap := make(map[int]map[repository3.PipelineStageType]bool)
+	if len(pipelineIds) > 0 {
+		pluginStages, err := impl.pipelineStageRepository.GetCdStagesWithStepsByCdPipelineIds(pipelineIds)
+		if err != nil {
+			impl.Logger.Errorw("error in fetching cd stages of pipelines", "err", err, "pipelineIds", pipelineIds)
+			return nil, err
+		}
+		for _, stage := range pluginStages {
+			if _, ok := pluginStagesMap[stage.CdPipelineId]; !ok {
+				pluginStagesMap[stage.CdPipelineId] = make(map[repository3.PipelineStageType]bool)
+			}
+			pluginStagesMap[stage.CdPipelineId][stage.Type] = true
+		}
+	}
 
 	for _, env := range existingAppEnvContainers {
 		appKey := strconv.Itoa(env.AppId) + "_" + env.AppName
@@ -417,6 +435,8 @@ func (impl AppListingServiceImpl) fetchACDAppStatus(fetchAppListingRequest Fetch
 				break
 			}
 		}
+		preStageExists := pipeline.PreStageConfig != "" || pluginStagesMap[pipeline.Id][repository3.PIPELINE_STAGE_TYPE_PRE_CD]
+		postStageExists := pipeline.PostStageConfig != "" || pluginStagesMap[pipeline.Id][repository3.PIPELINE_STAGE_TYPE_POST_CD]
 		var preCdStageRunner, postCdStageRunner, cdStageRunner *pipelineConfig.CdWorkflowRunner
 		cdStageRunners := appEnvCdWorkflowRunnerMap[latestTriggeredWf.Id]
 		for _, runner := range cdStageRunners {
@@ -430,7 +450,7 @@ func (impl AppListingServiceImpl) fetchACDAppStatus(fetchAppListingRequest Fetch
 		}
 
 		if latestTriggeredWf.WorkflowStatus == pipelineConfig.WF_STARTED || latestTriggeredWf.WorkflowStatus == pipelineConfig.WF_UNKNOWN {
-			if pipeline.PreStageConfig != "" {
+			if preStageExists {
 				if preCdStageRunner != nil && preCdStageRunner.Id != 0 {
 					env.PreStageStatus = &preCdStageRunner.Status
 				} else {
@@ -438,7 +458,7 @@ func (impl AppListingServiceImpl) fetchACDAppStatus(fetchAppListingRequest Fetch
 					env.PreStageStatus = &status
 				}
 			}
-			if pipeline.PostStageConfig != "" {
+			if postStageExists {
 				if postCdStageRunner != nil && postCdStageRunner.Id != 0 {
 					env.PostStageStatus = &postCdStageRunner.Status
 				} else {
@@ -462,7 +482,7 @@ func (impl AppListingServiceImpl) fetchACDAppStatus(fetchAppListingRequest Fetch
 				env.CdStageStatus = &status
 			}
 		} else {
-			if pipeline.PreStageConfig != "" {
+			if preStageExists {
 				if preCdStageRunner != nil && preCdStageRunner.Id != 0 {
 					var status string = latestTriggeredWf.WorkflowStatus.String()
 					env.PreStageStatus = &status
@@ -471,7 +491,7 @@ func (impl AppListingServiceImpl) fetchACDAppStatus(fetchAppListingRequest Fetch
 					env.PreStageStatus = &status
 				}
 			}
-			if pipeline.PostStageConfig != "" {
+			if postStageExists {
 				if postCdStageRunner != nil && postCdStageRunner.Id != 0 {
 					var status string = latestTriggeredWf.WorkflowStatus.String()
 					env.PostStageStatus = &status
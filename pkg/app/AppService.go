@@ -18,6 +18,7 @@
 package app
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -25,6 +26,7 @@ import (
 	client2 "github.com/devtron-labs/devtron/api/helm-app"
 	"github.com/devtron-labs/devtron/pkg/chart"
 	"github.com/devtron-labs/devtron/util/argo"
+	"k8s.io/helm/pkg/chartutil"
 	chart2 "k8s.io/helm/pkg/proto/hapi/chart"
 	"net/url"
 	"path"
@@ -56,6 +58,7 @@ import (
 	"github.com/devtron-labs/devtron/internal/sql/repository/security"
 	. "github.com/devtron-labs/devtron/internal/util"
 	"github.com/devtron-labs/devtron/pkg/commonService"
+	"github.com/devtron-labs/devtron/pkg/manifestScan"
 	"github.com/devtron-labs/devtron/pkg/user"
 	util2 "github.com/devtron-labs/devtron/util"
 	util "github.com/devtron-labs/devtron/util/event"
@@ -112,6 +115,7 @@ type AppServiceImpl struct {
 	argoUserService                  argo.ArgoUserService
 	cdPipelineStatusTimelineRepo     pipelineConfig.PipelineStatusTimelineRepository
 	appCrudOperationService          AppCrudOperationService
+	manifestScanService              manifestScan.ManifestScanService
 }
 
 type AppService interface {
@@ -157,7 +161,8 @@ func NewAppService(
 	chartService chart.ChartService, helmAppClient client2.HelmAppClient,
 	argoUserService argo.ArgoUserService,
 	cdPipelineStatusTimelineRepo pipelineConfig.PipelineStatusTimelineRepository,
-	appCrudOperationService AppCrudOperationService) *AppServiceImpl {
+	appCrudOperationService AppCrudOperationService,
+	manifestScanService manifestScan.ManifestScanService) *AppServiceImpl {
 	appServiceImpl := &AppServiceImpl{
 		environmentConfigRepository:      environmentConfigRepository,
 		mergeUtil:                        mergeUtil,
@@ -201,6 +206,7 @@ func NewAppService(
 		argoUserService:                  argoUserService,
 		cdPipelineStatusTimelineRepo:     cdPipelineStatusTimelineRepo,
 		appCrudOperationService:          appCrudOperationService,
+		manifestScanService:              manifestScanService,
 	}
 	return appServiceImpl
 }
@@ -485,6 +491,7 @@ func (impl *AppServiceImpl) UpdatePipelineStatusTimelineForApplicationChanges(ne
 						return err
 					}
 					impl.logger.Infow("APP_STATUS_UPDATE_REQ", "stage", "terminal_status", "data", string(b), "status", timeline.Status)
+					impl.writeDeploymentHealthEvent(cdWfr, newApp.Status.Health.Status)
 				}
 			}
 		}
@@ -508,6 +515,21 @@ func (impl *AppServiceImpl) SavePipelineStatusTimelineIfNotAlreadyPresent(cdWork
 	return latestTimeline, nil
 }
 
+// writeDeploymentHealthEvent notifies the health status reached by a deployment after sync
+func (impl *AppServiceImpl) writeDeploymentHealthEvent(cdWfr pipelineConfig.CdWorkflowRunner, healthStatus health.HealthStatusCode) {
+	eventType := util.DeploymentHealthy
+	if healthStatus == health.HealthStatusDegraded {
+		eventType = util.DeploymentDegraded
+	}
+	pipeline := cdWfr.CdWorkflow.Pipeline
+	event := impl.eventFactory.Build(eventType, &pipeline.Id, pipeline.AppId, &pipeline.EnvironmentId, util.CD)
+	event = impl.eventFactory.BuildDeploymentHealthData(event, &cdWfr, string(healthStatus))
+	_, evtErr := impl.eventClient.WriteNotificationEvent(event)
+	if evtErr != nil {
+		impl.logger.Errorw("error in writing deployment health event", "event", event, "err", evtErr)
+	}
+}
+
 func (impl *AppServiceImpl) WriteCDSuccessEvent(appId int, envId int, override *chartConfig.PipelineOverride) {
 	event := impl.eventFactory.Build(util.Success, &override.PipelineId, appId, &envId, util.CD)
 	impl.logger.Debugw("event WriteCDSuccessEvent", "event", event, "override", override)
@@ -895,7 +917,7 @@ func (impl AppServiceImpl) TriggerRelease(overrideRequest *bean.ValuesOverrideRe
 		impl.logger.Errorw("error in fetching app labels for gitOps commit", "err", err)
 		appLabelJsonByte = nil
 	}
-	releaseId, pipelineOverrideId, mergeAndSave, saveErr := impl.mergeAndSave(envOverride, overrideRequest, dbMigrationOverride, artifact, pipeline, configMapJson, appLabelJsonByte, strategy, ctx, triggeredAt, deployedBy)
+	releaseId, pipelineOverrideId, mergeAndSave, saveErr := impl.mergeAndSave(envOverride, overrideRequest, dbMigrationOverride, artifact, pipeline, configMapJson, appLabelJsonByte, strategy, ctx, triggeredAt, deployedBy, wfrId)
 	if releaseId != 0 {
 		//updating the acd app with updated values and sync operation
 		if IsAcdApp(pipeline.DeploymentAppType) {
@@ -1429,7 +1451,7 @@ func (impl AppServiceImpl) mergeAndSave(envOverride *chartConfig.EnvConfigOverri
 	dbMigrationOverride []byte,
 	artifact *repository.CiArtifact,
 	pipeline *pipelineConfig.Pipeline, configMapJson, appLabelJsonByte []byte, strategy *chartConfig.PipelineStrategy, ctx context.Context,
-	triggeredAt time.Time, deployedBy int32) (releaseId int, overrideId int, mergedValues string, err error) {
+	triggeredAt time.Time, deployedBy int32, wfrId int) (releaseId int, overrideId int, mergedValues string, err error) {
 
 	//register release , obtain release id TODO: populate releaseId to template
 	override, err := impl.savePipelineOverride(overrideRequest, envOverride.Id, triggeredAt)
@@ -1491,6 +1513,11 @@ func (impl AppServiceImpl) mergeAndSave(envOverride *chartConfig.EnvConfigOverri
 	appName := fmt.Sprintf("%s-%s", pipeline.App.AppName, envOverride.Environment.Name)
 	merged = impl.hpaCheckBeforeTrigger(ctx, appName, envOverride.Namespace, merged, pipeline.AppId)
 
+	err = impl.scanManifests(envOverride, pipeline, merged, wfrId, deployedBy)
+	if err != nil {
+		return 0, 0, "", err
+	}
+
 	commitHash := ""
 	if IsAcdApp(pipeline.DeploymentAppType) {
 		chartRepoName := impl.GetChartRepoName(envOverride.Chart.GitRepoUrl)
@@ -1783,6 +1810,75 @@ func (impl AppServiceImpl) updatePipeline(pipeline *pipelineConfig.Pipeline, use
 	return true, nil
 }
 
+// scanManifests renders the chart with the merged values it is released with and fails the release when manifest scan
+// policies block a finding. When the chart can not be rendered or scanned, the release is failed only by blocking
+// policies which do not fail open
+func (impl AppServiceImpl) scanManifests(envOverride *chartConfig.EnvConfigOverride, pipeline *pipelineConfig.Pipeline, merged []byte, wfrId int, deployedBy int32) error {
+	var chrt *chart2.Chart
+	var err error
+	if len(envOverride.Chart.ReferenceChart) > 0 {
+		chrt, err = chartutil.LoadArchive(bytes.NewReader(envOverride.Chart.ReferenceChart))
+	} else {
+		chrt, err = chartutil.Load(path.Join(string(impl.refChartDir), envOverride.Chart.ReferenceTemplate))
+	}
+	if err != nil {
+		impl.logger.Errorw("error in loading chart for manifest scan", "err", err, "chartId", envOverride.Chart.Id)
+	} else {
+		chrt.Metadata.Name = pipeline.App.AppName
+		chrt.Metadata.Version = envOverride.Chart.ChartVersion
+	}
+	scanRequest := &manifestScan.ManifestScanRequest{
+		CdWorkflowRunnerId: wfrId,
+		AppId:              pipeline.AppId,
+		EnvironmentId:      envOverride.TargetEnvironment,
+		ClusterId:          envOverride.Environment.ClusterId,
+		ReleaseName:        fmt.Sprintf("%s-%s", pipeline.App.AppName, envOverride.Environment.Name),
+		Namespace:          envOverride.Namespace,
+		UserId:             deployedBy,
+	}
+	var evaluation *manifestScan.ManifestScanEvaluation
+	if chrt != nil {
+		evaluation, err = impl.manifestScanService.ScanChart(chrt, string(merged), scanRequest)
+	} else {
+		evaluation, err = impl.manifestScanService.EvaluateScanFailure(err, scanRequest)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in scanning manifests", "err", err, "pipelineId", pipeline.Id)
+		return err
+	}
+	if !evaluation.Blocked {
+		if len(evaluation.ScanError) > 0 {
+			impl.logger.Warnw("manifests could not be scanned, policies fail open", "scanError", evaluation.ScanError, "pipelineId", pipeline.Id)
+		}
+		return nil
+	}
+	var message, detail string
+	if len(evaluation.ScanError) > 0 {
+		message = manifestScan.BuildManifestScanFailureMessage(evaluation)
+		detail = manifestScan.BuildManifestScanFailureDetail(evaluation)
+	} else {
+		message = manifestScan.BuildManifestScanViolationMessage(evaluation.Findings)
+		detail = manifestScan.BuildManifestScanViolationDetail(evaluation.Findings)
+	}
+	timeline := &pipelineConfig.PipelineStatusTimeline{
+		CdWorkflowRunnerId: wfrId,
+		Status:             pipelineConfig.TIMELINE_STATUS_DEPLOYMENT_FAILED,
+		StatusDetail:       fmt.Sprintf("Deployment failed: %s", detail),
+		StatusTime:         time.Now(),
+		AuditLog: sql.AuditLog{
+			CreatedBy: deployedBy,
+			CreatedOn: time.Now(),
+			UpdatedBy: deployedBy,
+			UpdatedOn: time.Now(),
+		},
+	}
+	err = impl.cdPipelineStatusTimelineRepo.SaveTimeline(timeline)
+	if err != nil {
+		impl.logger.Errorw("error in creating timeline status for deployment fail - manifest scan policy violation", "err", err, "timeline", timeline)
+	}
+	return errors.New(message)
+}
+
 func (impl AppServiceImpl) createHelmAppForCdPipeline(overrideRequest *bean.ValuesOverrideRequest,
 	envOverride *chartConfig.EnvConfigOverride, referenceTemplatePath string, chartMetaData *chart2.Metadata,
 	triggeredAt time.Time, pipeline *pipelineConfig.Pipeline, mergeAndSave string, ctx context.Context) (bool, error) {
@@ -738,6 +738,8 @@ func (impl *AppCloneServiceImpl) CreateCiPipeline(req *cloneCiPipelineRequest) (
 					IsDockerConfigOverridden: refCiPipeline.IsDockerConfigOverridden,
 					PreBuildStage:            preStageDetail,
 					PostBuildStage:           postStageDetail,
+					CronSchedule:             refCiPipeline.CronSchedule,
+					BuildMatrix:              refCiPipeline.BuildMatrix,
 				},
 				AppId:         req.appId,
 				Action:        bean.CREATE,
@@ -802,6 +804,12 @@ func (impl *AppCloneServiceImpl) CreateCdPipeline(req *cloneCdPipelineRequest, c
 	if strings.HasPrefix(pipelineName, req.refAppName) {
 		pipelineName = strings.Replace(pipelineName, req.refAppName+"-", "", 1)
 	}
+	//getting pre & post deploy stage deep copy, new ids will be created for cloned pipeline
+	preDeployStage, postDeployStage, err := impl.pipelineStageService.GetCdPipelineStageDataDeepCopy(refCdPipeline.Id)
+	if err != nil {
+		impl.logger.Errorw("error in getting pre & post deploy stage data deep copy", "err", err, "refCdPipelineId", refCdPipeline.Id)
+		return nil, err
+	}
 	cdPipeline := &bean.CDPipelineConfigObject{
 		Id:                            0,
 		EnvironmentId:                 refCdPipeline.EnvironmentId,
@@ -818,6 +826,9 @@ func (impl *AppCloneServiceImpl) CreateCdPipeline(req *cloneCdPipelineRequest, c
 		PostStageConfigMapSecretNames: refCdPipeline.PostStageConfigMapSecretNames,
 		RunPostStageInEnv:             refCdPipeline.RunPostStageInEnv,
 		RunPreStageInEnv:              refCdPipeline.RunPreStageInEnv,
+		PreDeployStage:                preDeployStage,
+		PostDeployStage:               postDeployStage,
+		AutoRollbackPolicy:            refCdPipeline.AutoRollbackPolicy,
 	}
 	cdPipelineReq := &bean.CdPipelines{
 		Pipelines: []*bean.CDPipelineConfigObject{cdPipeline},
@@ -11,6 +11,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/appStore/deployment/repository"
 	appStoreDiscoverRepository "github.com/devtron-labs/devtron/pkg/appStore/discover/repository"
 	clusterRepository "github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/devtron-labs/devtron/pkg/manifestScan"
 	"github.com/ghodss/yaml"
 	"github.com/go-pg/pg"
 	"go.uber.org/zap"
@@ -38,10 +39,12 @@ type AppStoreDeploymentHelmServiceImpl struct {
 	environmentRepository                clusterRepository.EnvironmentRepository
 	helmAppClient                        client.HelmAppClient
 	installedAppRepository               repository.InstalledAppRepository
+	manifestScanService                  manifestScan.ManifestScanService
 }
 
 func NewAppStoreDeploymentHelmServiceImpl(logger *zap.SugaredLogger, helmAppService client.HelmAppService, appStoreApplicationVersionRepository appStoreDiscoverRepository.AppStoreApplicationVersionRepository,
-	environmentRepository clusterRepository.EnvironmentRepository, helmAppClient client.HelmAppClient, installedAppRepository repository.InstalledAppRepository) *AppStoreDeploymentHelmServiceImpl {
+	environmentRepository clusterRepository.EnvironmentRepository, helmAppClient client.HelmAppClient, installedAppRepository repository.InstalledAppRepository,
+	manifestScanService manifestScan.ManifestScanService) *AppStoreDeploymentHelmServiceImpl {
 	return &AppStoreDeploymentHelmServiceImpl{
 		Logger:                               logger,
 		helmAppService:                       helmAppService,
@@ -49,6 +52,7 @@ func NewAppStoreDeploymentHelmServiceImpl(logger *zap.SugaredLogger, helmAppServ
 		environmentRepository:                environmentRepository,
 		helmAppClient:                        helmAppClient,
 		installedAppRepository:               installedAppRepository,
+		manifestScanService:                  manifestScanService,
 	}
 }
 
@@ -76,6 +80,19 @@ func (impl AppStoreDeploymentHelmServiceImpl) InstallApp(installAppVersionReques
 		},
 	}
 
+	scanRequest := &manifestScan.ManifestScanRequest{
+		AppId:         installAppVersionRequest.AppId,
+		EnvironmentId: installAppVersionRequest.EnvironmentId,
+		ClusterId:     installAppVersionRequest.ClusterId,
+		IsAppstore:    true,
+		ReleaseName:   installAppVersionRequest.AppName,
+		Namespace:     installAppVersionRequest.Namespace,
+	}
+	err = impl.scanManifests(ctx, appStoreAppVersion.Id, installAppVersionRequest.ValuesOverrideYaml, scanRequest)
+	if err != nil {
+		return installAppVersionRequest, err
+	}
+
 	_, err = impl.helmAppService.InstallRelease(ctx, installAppVersionRequest.ClusterId, installReleaseRequest)
 	if err != nil {
 		return installAppVersionRequest, err
@@ -281,6 +298,18 @@ func (impl *AppStoreDeploymentHelmServiceImpl) updateApplicationWithChartInfo(ct
 			Password: chartRepo.Password,
 		},
 	}
+	scanRequest := &manifestScan.ManifestScanRequest{
+		AppId:         installedApp.AppId,
+		EnvironmentId: installedApp.EnvironmentId,
+		ClusterId:     installedApp.Environment.ClusterId,
+		IsAppstore:    true,
+		ReleaseName:   installedApp.App.AppName,
+		Namespace:     installedApp.Environment.Namespace,
+	}
+	err = impl.scanManifests(ctx, appStoreApplicationVersionId, valuesOverrideYaml, scanRequest)
+	if err != nil {
+		return err
+	}
 	res, err := impl.helmAppService.UpdateApplicationWithChartInfo(ctx, installedApp.Environment.ClusterId, updateReleaseRequest)
 	if err != nil {
 		impl.Logger.Errorw("error in updating helm application", "err", err)
@@ -291,3 +320,25 @@ func (impl *AppStoreDeploymentHelmServiceImpl) updateApplicationWithChartInfo(ct
 	}
 	return nil
 }
+
+// scanManifests templates the chart store chart through kubelink and fails the deployment when manifest scan policies
+// block a finding. When the chart can not be templated or scanned, the deployment is failed only by blocking policies
+// which do not fail open
+func (impl *AppStoreDeploymentHelmServiceImpl) scanManifests(ctx context.Context, appStoreApplicationVersionId int, valuesYaml string, scanRequest *manifestScan.ManifestScanRequest) error {
+	evaluation, err := impl.manifestScanService.ScanHelmChart(ctx, appStoreApplicationVersionId, valuesYaml, scanRequest)
+	if err != nil {
+		impl.Logger.Errorw("error in scanning manifests", "err", err, "releaseName", scanRequest.ReleaseName)
+		return err
+	}
+	if !evaluation.Blocked {
+		if len(evaluation.ScanError) > 0 {
+			impl.Logger.Warnw("manifests could not be scanned, policies fail open", "scanError", evaluation.ScanError, "releaseName", scanRequest.ReleaseName)
+		}
+		return nil
+	}
+	if len(evaluation.ScanError) > 0 {
+		return errors.New(manifestScan.BuildManifestScanFailureMessage(evaluation))
+	}
+	impl.Logger.Errorw("manifest scan policy violated", "releaseName", scanRequest.ReleaseName, "violations", manifestScan.BuildManifestScanViolationDetail(evaluation.Findings))
+	return errors.New(manifestScan.BuildManifestScanViolationMessage(evaluation.Findings))
+}
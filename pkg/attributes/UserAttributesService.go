@@ -28,6 +28,7 @@ type UserAttributesService interface {
 	AddUserAttributes(request *UserAttributesDto) (*UserAttributesDto, error)
 	UpdateUserAttributes(request *UserAttributesDto) (*UserAttributesDto, error)
 	GetUserAttribute(request *UserAttributesDto) (*UserAttributesDto, error)
+	GetUserAttributesByKey(key string) ([]*UserAttributesDto, error)
 }
 
 type UserAttributesServiceImpl struct {
@@ -119,3 +120,23 @@ func (impl UserAttributesServiceImpl) GetUserAttribute(request *UserAttributesDt
 	}
 	return resAttrDto, nil
 }
+
+// GetUserAttributesByKey returns attribute of every active user who has set the key, UserId is set on returned
+// attributes
+func (impl UserAttributesServiceImpl) GetUserAttributesByKey(key string) ([]*UserAttributesDto, error) {
+	models, err := impl.attributesRepository.GetDataValuesByKey(key)
+	if err != nil {
+		impl.logger.Errorw("error in fetching user attributes by key", "key", key, "error", err)
+		return nil, errors.New("error occurred while getting user attributes")
+	}
+	var attributes []*UserAttributesDto
+	for _, model := range models {
+		attributes = append(attributes, &UserAttributesDto{
+			EmailId: model.EmailId,
+			Key:     model.Key,
+			Value:   model.Value,
+			UserId:  model.UserId,
+		})
+	}
+	return attributes, nil
+}
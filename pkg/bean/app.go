@@ -107,6 +107,25 @@ type CiPipeline struct {
 	TargetPlatform           string                 `json:"targetPlatform,omitempty"`
 	IsDockerConfigOverridden bool                   `json:"isDockerConfigOverridden"`
 	DockerConfigOverride     DockerConfigOverride   `json:"dockerConfigOverride,omitempty"`
+	CronSchedule             string                 `json:"cronSchedule,omitempty"` //standard 5 field cron spec for scheduled builds of latest commit
+	BuildMatrix              *CiBuildMatrix         `json:"buildMatrix,omitempty"`
+}
+
+type CiBuildMatrixArtifactMode string
+
+const (
+	//legs push platform specific images which are combined in one multi-arch manifest list artifact
+	CI_BUILD_MATRIX_MANIFEST_LIST CiBuildMatrixArtifactMode = "MANIFEST_LIST"
+	//every leg creates its own artifact tagged with the leg name
+	CI_BUILD_MATRIX_SEPARATE_TAGS CiBuildMatrixArtifactMode = "SEPARATE_TAGS"
+)
+
+// CiBuildMatrix fans out one build in parallel legs, one leg for every combination of platform, build args and dockerfile path
+type CiBuildMatrix struct {
+	Platforms       []string                  `json:"platforms,omitempty"`
+	BuildArgs       []map[string]string       `json:"buildArgs,omitempty"`
+	DockerfilePaths []string                  `json:"dockerfilePaths,omitempty"`
+	ArtifactMode    CiBuildMatrixArtifactMode `json:"artifactMode,omitempty"`
 }
 
 type DockerConfigOverride struct {
@@ -457,6 +476,10 @@ type CDPipelineConfigObject struct {
 	ParentPipelineId              int                               `json:"parentPipelineId"`
 	ParentPipelineType            string                            `json:"parentPipelineType"`
 	DeploymentAppType             string                            `json:"deploymentAppType"`
+	PreDeployStage                *bean.PipelineStageDto            `json:"preDeployStage,omitempty"`
+	PostDeployStage               *bean.PipelineStageDto            `json:"postDeployStage,omitempty"`
+	AutoRollbackPolicy            *bean.AutoRollbackPolicyDto       `json:"autoRollbackPolicy,omitempty"`
+	CanaryAnalysisPolicy          *bean.CanaryAnalysisPolicyDto     `json:"canaryAnalysisPolicy,omitempty" validate:"omitempty"`
 	//Downstream         []int                             `json:"downstream"` //PipelineCounter of downstream	(for future reference only)
 }
 
@@ -548,12 +571,17 @@ type CiArtifactBean struct {
 	IsVulnerable                  bool            `json:"vulnerable,notnull"`
 	ScanEnabled                   bool            `json:"scanEnabled,notnull"`
 	Scanned                       bool            `json:"scanned,notnull"`
+	ApprovalStatus                string          `json:"approvalStatus,omitempty"`
+	ApprovalRequestId             int             `json:"approvalRequestId,omitempty"`
+	ApprovedBy                    []string        `json:"approvedBy,omitempty"`
 }
 
 type CiArtifactResponse struct {
 	//AppId           int      `json:"app_id"`
-	CdPipelineId int              `json:"cd_pipeline_id,notnull"`
-	CiArtifacts  []CiArtifactBean `json:"ci_artifacts,notnull"`
+	CdPipelineId      int              `json:"cd_pipeline_id,notnull"`
+	CiArtifacts       []CiArtifactBean `json:"ci_artifacts,notnull"`
+	ApprovalRequired  bool             `json:"approvalRequired,omitempty"`
+	RequiredApprovals int              `json:"requiredApprovals,omitempty"`
 }
 
 type AppLabelsDto struct {
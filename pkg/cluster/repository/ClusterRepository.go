@@ -21,6 +21,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/go-pg/pg"
 	"go.uber.org/zap"
+	"time"
 )
 
 type Cluster struct {
@@ -39,6 +40,7 @@ type Cluster struct {
 	AgentInstallationStage int               `sql:"agent_installation_stage"`
 	K8sVersion             string            `sql:"k8s_version"`
 	ErrorInConnecting      string            `sql:"error_in_connecting"`
+	UnreachableNotifiedOn  time.Time         `sql:"unreachable_notified_on"`
 	sql.AuditLog
 }
 
@@ -55,6 +57,9 @@ type ClusterRepository interface {
 	Delete(model *Cluster) error
 	MarkClusterDeleted(model *Cluster) error
 	UpdateClusterConnectionStatus(clusterId int, errorInConnecting string) error
+	// UpdateClusterUnreachableNotifiedOn records when unreachable cluster was notified, nil resets it once the
+	// cluster is reachable again
+	UpdateClusterUnreachableNotifiedOn(clusterId int, notifiedOn *time.Time) error
 }
 
 func NewClusterRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *ClusterRepositoryImpl {
@@ -154,3 +159,11 @@ func (impl ClusterRepositoryImpl) UpdateClusterConnectionStatus(clusterId int, e
 		Update()
 	return err
 }
+
+func (impl ClusterRepositoryImpl) UpdateClusterUnreachableNotifiedOn(clusterId int, notifiedOn *time.Time) error {
+	cluster := &Cluster{}
+	_, err := impl.dbConnection.Model(cluster).
+		Set("unreachable_notified_on = ?", notifiedOn).Where("id = ?", clusterId).
+		Update()
+	return err
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package deploymentApproval
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+)
+
+// DeploymentApprovalPolicy defines how many approvals an artifact needs before it can be deployed on a cd pipeline,
+// approvers are picked from the listed users and members of the listed role groups
+type DeploymentApprovalPolicy struct {
+	tableName            struct{} `sql:"deployment_approval_policy" pg:",discard_unknown_columns"`
+	Id                   int      `sql:"id,pk"`
+	PipelineId           int      `sql:"pipeline_id,notnull"`
+	RequiredApprovals    int      `sql:"required_approvals,notnull"`
+	ApproverUserIds      []int32  `sql:"approver_user_ids" pg:",array"`
+	ApproverRoleGroupIds []int32  `sql:"approver_role_group_ids" pg:",array"`
+	Active               bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type DeploymentApprovalPolicyRepository interface {
+	Save(policy *DeploymentApprovalPolicy) error
+	Update(policy *DeploymentApprovalPolicy) error
+	FindActiveByPipelineId(pipelineId int) (*DeploymentApprovalPolicy, error)
+}
+
+type DeploymentApprovalPolicyRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewDeploymentApprovalPolicyRepositoryImpl(dbConnection *pg.DB) *DeploymentApprovalPolicyRepositoryImpl {
+	return &DeploymentApprovalPolicyRepositoryImpl{dbConnection: dbConnection}
+}
+
+func (impl DeploymentApprovalPolicyRepositoryImpl) Save(policy *DeploymentApprovalPolicy) error {
+	return impl.dbConnection.Insert(policy)
+}
+
+func (impl DeploymentApprovalPolicyRepositoryImpl) Update(policy *DeploymentApprovalPolicy) error {
+	return impl.dbConnection.Update(policy)
+}
+
+func (impl DeploymentApprovalPolicyRepositoryImpl) FindActiveByPipelineId(pipelineId int) (*DeploymentApprovalPolicy, error) {
+	policy := &DeploymentApprovalPolicy{}
+	err := impl.dbConnection.Model(policy).
+		Where("pipeline_id = ?", pipelineId).
+		Where("active = ?", true).
+		Select()
+	return policy, err
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package deploymentApproval
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+)
+
+type ApprovalStatus string
+
+const (
+	APPROVAL_STATUS_REQUESTED ApprovalStatus = "REQUESTED"
+	APPROVAL_STATUS_APPROVED  ApprovalStatus = "APPROVED"
+	APPROVAL_STATUS_REJECTED  ApprovalStatus = "REJECTED"
+	// APPROVAL_STATUS_SUPERSEDED closes an auto trigger request whose pipeline deployed a newer artifact before approval
+	APPROVAL_STATUS_SUPERSEDED ApprovalStatus = "SUPERSEDED"
+)
+
+// DeploymentApprovalRequest is raised for an artifact on a cd pipeline having approval policy, only one request
+// per pipeline and artifact is active at a time
+type DeploymentApprovalRequest struct {
+	tableName    struct{}       `sql:"deployment_approval_request" pg:",discard_unknown_columns"`
+	Id           int            `sql:"id,pk"`
+	PipelineId   int            `sql:"pipeline_id,notnull"`
+	CiArtifactId int            `sql:"ci_artifact_id,notnull"`
+	Status       ApprovalStatus `sql:"status,notnull"`
+	RequestedBy  int32          `sql:"requested_by,notnull"`
+	AutoTrigger  bool           `sql:"auto_trigger,notnull"` // raised by automatic deployment, deployment is triggered once approved
+	CdWorkflowId int            `sql:"cd_workflow_id"`       // workflow of held automatic deployment, deployment continues in it once approved
+	Active       bool           `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+// DeploymentApprovalUserData is the response of an approver on an approval request
+type DeploymentApprovalUserData struct {
+	tableName         struct{}       `sql:"deployment_approval_user_data" pg:",discard_unknown_columns"`
+	Id                int            `sql:"id,pk"`
+	ApprovalRequestId int            `sql:"approval_request_id,notnull"`
+	UserId            int32          `sql:"user_id,notnull"`
+	UserResponse      ApprovalStatus `sql:"user_response,notnull"`
+	Comment           string         `sql:"comment"`
+	sql.AuditLog
+}
+
+type DeploymentApprovalRepository interface {
+	SaveRequest(request *DeploymentApprovalRequest) error
+	UpdateRequest(request *DeploymentApprovalRequest) error
+	FindRequestById(id int) (*DeploymentApprovalRequest, error)
+	FindActiveRequest(pipelineId int, ciArtifactId int) (*DeploymentApprovalRequest, error)
+	FindActiveRequestsByPipelineIdAndArtifactIds(pipelineId int, ciArtifactIds []int) ([]*DeploymentApprovalRequest, error)
+	FindAllRequestsByPipelineId(pipelineId int) ([]*DeploymentApprovalRequest, error)
+	SaveUserData(userData *DeploymentApprovalUserData) error
+	FindUserDataByRequestIds(requestIds []int) ([]*DeploymentApprovalUserData, error)
+}
+
+type DeploymentApprovalRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewDeploymentApprovalRepositoryImpl(dbConnection *pg.DB) *DeploymentApprovalRepositoryImpl {
+	return &DeploymentApprovalRepositoryImpl{dbConnection: dbConnection}
+}
+
+func (impl DeploymentApprovalRepositoryImpl) SaveRequest(request *DeploymentApprovalRequest) error {
+	return impl.dbConnection.Insert(request)
+}
+
+func (impl DeploymentApprovalRepositoryImpl) UpdateRequest(request *DeploymentApprovalRequest) error {
+	return impl.dbConnection.Update(request)
+}
+
+func (impl DeploymentApprovalRepositoryImpl) FindRequestById(id int) (*DeploymentApprovalRequest, error) {
+	request := &DeploymentApprovalRequest{}
+	err := impl.dbConnection.Model(request).
+		Where("id = ?", id).
+		Select()
+	return request, err
+}
+
+func (impl DeploymentApprovalRepositoryImpl) FindActiveRequest(pipelineId int, ciArtifactId int) (*DeploymentApprovalRequest, error) {
+	request := &DeploymentApprovalRequest{}
+	err := impl.dbConnection.Model(request).
+		Where("pipeline_id = ?", pipelineId).
+		Where("ci_artifact_id = ?", ciArtifactId).
+		Where("active = ?", true).
+		Order("id DESC").
+		Limit(1).
+		Select()
+	return request, err
+}
+
+func (impl DeploymentApprovalRepositoryImpl) FindActiveRequestsByPipelineIdAndArtifactIds(pipelineId int, ciArtifactIds []int) ([]*DeploymentApprovalRequest, error) {
+	var requests []*DeploymentApprovalRequest
+	if len(ciArtifactIds) == 0 {
+		return requests, nil
+	}
+	err := impl.dbConnection.Model(&requests).
+		Where("pipeline_id = ?", pipelineId).
+		Where("ci_artifact_id in (?)", pg.In(ciArtifactIds)).
+		Where("active = ?", true).
+		Select()
+	return requests, err
+}
+
+func (impl DeploymentApprovalRepositoryImpl) FindAllRequestsByPipelineId(pipelineId int) ([]*DeploymentApprovalRequest, error) {
+	var requests []*DeploymentApprovalRequest
+	err := impl.dbConnection.Model(&requests).
+		Where("pipeline_id = ?", pipelineId).
+		Order("id DESC").
+		Select()
+	return requests, err
+}
+
+func (impl DeploymentApprovalRepositoryImpl) SaveUserData(userData *DeploymentApprovalUserData) error {
+	return impl.dbConnection.Insert(userData)
+}
+
+func (impl DeploymentApprovalRepositoryImpl) FindUserDataByRequestIds(requestIds []int) ([]*DeploymentApprovalUserData, error) {
+	var userData []*DeploymentApprovalUserData
+	if len(requestIds) == 0 {
+		return userData, nil
+	}
+	err := impl.dbConnection.Model(&userData).
+		Where("approval_request_id in (?)", pg.In(requestIds)).
+		Order("id ASC").
+		Select()
+	return userData, err
+}
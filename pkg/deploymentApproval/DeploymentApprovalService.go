@@ -0,0 +1,621 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package deploymentApproval
+
+import (
+	"fmt"
+	client "github.com/devtron-labs/devtron/client/events"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/devtron-labs/devtron/pkg/user"
+	util2 "github.com/devtron-labs/devtron/util/event"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+type DeploymentApprovalService interface {
+	GetPolicy(pipelineId int) (*ApprovalPolicyDto, error)
+	SavePolicy(request *ApprovalPolicyDto, userId int32) (*ApprovalPolicyDto, error)
+	DeletePolicy(pipelineId int, userId int32) error
+
+	RequestApproval(pipelineId int, ciArtifactId int, userId int32, autoTrigger bool, cdWorkflowId int) (*ApprovalRequestDto, error)
+	ApproveRequest(request *ApprovalActionDto, userId int32) (*ApprovalRequestDto, error)
+	RejectRequest(request *ApprovalActionDto, userId int32) (*ApprovalRequestDto, error)
+	MarkRequestSuperseded(approvalRequestId int, userId int32) error
+	GetApprovalRequests(pipelineId int, ciArtifactId int) ([]*ApprovalRequestDto, error)
+
+	EvaluateApproval(pipelineId int, ciArtifactId int) (*ApprovalEvaluation, error)
+	GetApprovalStateForArtifacts(pipelineId int, ciArtifactIds []int) (map[int]*ApprovalEvaluation, error)
+}
+
+type DeploymentApprovalServiceImpl struct {
+	logger                             *zap.SugaredLogger
+	deploymentApprovalPolicyRepository DeploymentApprovalPolicyRepository
+	deploymentApprovalRepository       DeploymentApprovalRepository
+	pipelineRepository                 pipelineConfig.PipelineRepository
+	ciArtifactRepository               repository.CiArtifactRepository
+	userService                        user.UserService
+	roleGroupService                   user.RoleGroupService
+	eventFactory                       client.EventFactory
+	eventClient                        client.EventClient
+}
+
+type ApprovalPolicyDto struct {
+	PipelineId           int     `json:"pipelineId" validate:"number,gt=0"`
+	RequiredApprovals    int     `json:"requiredApprovals" validate:"number,gt=0"`
+	ApproverUserIds      []int32 `json:"approverUserIds"`
+	ApproverRoleGroupIds []int32 `json:"approverRoleGroupIds"`
+}
+
+type ApprovalRequestDto struct {
+	Id                int                    `json:"id"`
+	PipelineId        int                    `json:"pipelineId" validate:"number,gt=0"`
+	CiArtifactId      int                    `json:"ciArtifactId" validate:"number,gt=0"`
+	Status            ApprovalStatus         `json:"status"`
+	RequestedBy       string                 `json:"requestedBy,omitempty"`
+	RequestedOn       time.Time              `json:"requestedOn"`
+	Active            bool                   `json:"active"`
+	AutoTrigger       bool                   `json:"autoTrigger"`
+	CdWorkflowId      int                    `json:"cdWorkflowId,omitempty"`
+	RequiredApprovals int                    `json:"requiredApprovals"`
+	UserResponses     []*ApprovalUserDataDto `json:"userResponses"`
+}
+
+type ApprovalUserDataDto struct {
+	UserId      int32          `json:"userId"`
+	UserEmail   string         `json:"userEmail"`
+	Response    ApprovalStatus `json:"response"`
+	Comment     string         `json:"comment,omitempty"`
+	RespondedOn time.Time      `json:"respondedOn"`
+}
+
+type ApprovalActionDto struct {
+	ApprovalRequestId int    `json:"approvalRequestId" validate:"number,gt=0"`
+	Comment           string `json:"comment"`
+}
+
+// ApprovalEvaluation is the approval state of an artifact on a cd pipeline, Status is empty if approval is not requested yet
+type ApprovalEvaluation struct {
+	ApprovalRequired  bool           `json:"approvalRequired"`
+	Approved          bool           `json:"approved"`
+	Status            ApprovalStatus `json:"status,omitempty"`
+	ApprovalRequestId int            `json:"approvalRequestId,omitempty"`
+	ApprovalCount     int            `json:"approvalCount"`
+	RequiredApprovals int            `json:"requiredApprovals"`
+	ApprovedBy        []string       `json:"approvedBy,omitempty"`
+}
+
+func NewDeploymentApprovalServiceImpl(logger *zap.SugaredLogger,
+	deploymentApprovalPolicyRepository DeploymentApprovalPolicyRepository,
+	deploymentApprovalRepository DeploymentApprovalRepository,
+	pipelineRepository pipelineConfig.PipelineRepository,
+	ciArtifactRepository repository.CiArtifactRepository,
+	userService user.UserService, roleGroupService user.RoleGroupService,
+	eventFactory client.EventFactory, eventClient client.EventClient) *DeploymentApprovalServiceImpl {
+	return &DeploymentApprovalServiceImpl{
+		logger:                             logger,
+		deploymentApprovalPolicyRepository: deploymentApprovalPolicyRepository,
+		deploymentApprovalRepository:       deploymentApprovalRepository,
+		pipelineRepository:                 pipelineRepository,
+		ciArtifactRepository:               ciArtifactRepository,
+		userService:                        userService,
+		roleGroupService:                   roleGroupService,
+		eventFactory:                       eventFactory,
+		eventClient:                        eventClient,
+	}
+}
+
+func (impl DeploymentApprovalServiceImpl) GetPolicy(pipelineId int) (*ApprovalPolicyDto, error) {
+	policy, err := impl.deploymentApprovalPolicyRepository.FindActiveByPipelineId(pipelineId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching approval policy", "err", err, "pipelineId", pipelineId)
+		return nil, err
+	}
+	if err == pg.ErrNoRows {
+		return nil, nil
+	}
+	return &ApprovalPolicyDto{
+		PipelineId:           policy.PipelineId,
+		RequiredApprovals:    policy.RequiredApprovals,
+		ApproverUserIds:      policy.ApproverUserIds,
+		ApproverRoleGroupIds: policy.ApproverRoleGroupIds,
+	}, nil
+}
+
+func (impl DeploymentApprovalServiceImpl) SavePolicy(request *ApprovalPolicyDto, userId int32) (*ApprovalPolicyDto, error) {
+	if len(request.ApproverUserIds) == 0 && len(request.ApproverRoleGroupIds) == 0 {
+		return nil, &util.ApiError{
+			HttpStatusCode:  http.StatusBadRequest,
+			InternalMessage: "no approvers found in approval policy",
+			UserMessage:     "At least one approver user or role group is required",
+		}
+	}
+	if len(request.ApproverRoleGroupIds) == 0 && request.RequiredApprovals > len(request.ApproverUserIds) {
+		return nil, &util.ApiError{
+			HttpStatusCode:  http.StatusBadRequest,
+			InternalMessage: "required approvals are more than approvers",
+			UserMessage:     fmt.Sprintf("Required approvals can not be more than %d approvers", len(request.ApproverUserIds)),
+		}
+	}
+	policy, err := impl.deploymentApprovalPolicyRepository.FindActiveByPipelineId(request.PipelineId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching approval policy", "err", err, "pipelineId", request.PipelineId)
+		return nil, err
+	}
+	if err == pg.ErrNoRows {
+		policy = &DeploymentApprovalPolicy{
+			PipelineId: request.PipelineId,
+			Active:     true,
+			AuditLog:   sql.AuditLog{CreatedOn: time.Now(), CreatedBy: userId},
+		}
+	}
+	policy.RequiredApprovals = request.RequiredApprovals
+	policy.ApproverUserIds = request.ApproverUserIds
+	policy.ApproverRoleGroupIds = request.ApproverRoleGroupIds
+	policy.UpdatedOn = time.Now()
+	policy.UpdatedBy = userId
+	if policy.Id == 0 {
+		err = impl.deploymentApprovalPolicyRepository.Save(policy)
+	} else {
+		err = impl.deploymentApprovalPolicyRepository.Update(policy)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving approval policy", "err", err, "policy", policy)
+		return nil, err
+	}
+	return request, nil
+}
+
+func (impl DeploymentApprovalServiceImpl) DeletePolicy(pipelineId int, userId int32) error {
+	policy, err := impl.deploymentApprovalPolicyRepository.FindActiveByPipelineId(pipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching approval policy", "err", err, "pipelineId", pipelineId)
+		return err
+	}
+	policy.Active = false
+	policy.UpdatedOn = time.Now()
+	policy.UpdatedBy = userId
+	err = impl.deploymentApprovalPolicyRepository.Update(policy)
+	if err != nil {
+		impl.logger.Errorw("error in deleting approval policy", "err", err, "pipelineId", pipelineId)
+		return err
+	}
+	return nil
+}
+
+// RequestApproval raises approval request of an artifact, cdWorkflowId is the workflow of an automatic deployment held
+// for approval
+func (impl DeploymentApprovalServiceImpl) RequestApproval(pipelineId int, ciArtifactId int, userId int32, autoTrigger bool, cdWorkflowId int) (*ApprovalRequestDto, error) {
+	policy, err := impl.deploymentApprovalPolicyRepository.FindActiveByPipelineId(pipelineId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching approval policy", "err", err, "pipelineId", pipelineId)
+		return nil, err
+	}
+	if err == pg.ErrNoRows {
+		return nil, &util.ApiError{
+			HttpStatusCode:  http.StatusBadRequest,
+			InternalMessage: fmt.Sprintf("no approval policy found for pipeline %d", pipelineId),
+			UserMessage:     "Approval is not configured for this pipeline",
+		}
+	}
+	approvalRequest, err := impl.deploymentApprovalRepository.FindActiveRequest(pipelineId, ciArtifactId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching approval request", "err", err, "pipelineId", pipelineId, "ciArtifactId", ciArtifactId)
+		return nil, err
+	}
+	if err == nil {
+		existing, err := impl.buildApprovalRequestDtos([]*DeploymentApprovalRequest{approvalRequest}, policy)
+		if err != nil {
+			return nil, err
+		}
+		//pending or approved request is reused, a rejected artifact can be requested again
+		if existing[0].Status == APPROVAL_STATUS_REQUESTED && autoTrigger {
+			//latest held automatic deployment continues once approved
+			approvalRequest.AutoTrigger = true
+			approvalRequest.CdWorkflowId = cdWorkflowId
+			approvalRequest.UpdatedOn = time.Now()
+			approvalRequest.UpdatedBy = userId
+			err = impl.deploymentApprovalRepository.UpdateRequest(approvalRequest)
+			if err != nil {
+				impl.logger.Errorw("error in updating held workflow of approval request", "err", err, "approvalRequestId", approvalRequest.Id)
+				return nil, err
+			}
+			existing[0].AutoTrigger = true
+			existing[0].CdWorkflowId = cdWorkflowId
+		}
+		if existing[0].Status != APPROVAL_STATUS_REJECTED {
+			return existing[0], nil
+		}
+		approvalRequest.Active = false
+		approvalRequest.UpdatedOn = time.Now()
+		approvalRequest.UpdatedBy = userId
+		err = impl.deploymentApprovalRepository.UpdateRequest(approvalRequest)
+		if err != nil {
+			impl.logger.Errorw("error in deactivating rejected approval request", "err", err, "approvalRequestId", approvalRequest.Id)
+			return nil, err
+		}
+	}
+	approvalRequest = &DeploymentApprovalRequest{
+		PipelineId:   pipelineId,
+		CiArtifactId: ciArtifactId,
+		Status:       APPROVAL_STATUS_REQUESTED,
+		RequestedBy:  userId,
+		AutoTrigger:  autoTrigger,
+		CdWorkflowId: cdWorkflowId,
+		Active:       true,
+		AuditLog:     sql.AuditLog{CreatedOn: time.Now(), CreatedBy: userId, UpdatedOn: time.Now(), UpdatedBy: userId},
+	}
+	err = impl.deploymentApprovalRepository.SaveRequest(approvalRequest)
+	if err != nil {
+		impl.logger.Errorw("error in saving approval request", "err", err, "approvalRequest", approvalRequest)
+		return nil, err
+	}
+	impl.sendApprovalRequestEvent(approvalRequest, policy)
+	requests, err := impl.buildApprovalRequestDtos([]*DeploymentApprovalRequest{approvalRequest}, policy)
+	if err != nil {
+		return nil, err
+	}
+	return requests[0], nil
+}
+
+func (impl DeploymentApprovalServiceImpl) ApproveRequest(request *ApprovalActionDto, userId int32) (*ApprovalRequestDto, error) {
+	return impl.respondToApprovalRequest(request, APPROVAL_STATUS_APPROVED, userId)
+}
+
+func (impl DeploymentApprovalServiceImpl) RejectRequest(request *ApprovalActionDto, userId int32) (*ApprovalRequestDto, error) {
+	return impl.respondToApprovalRequest(request, APPROVAL_STATUS_REJECTED, userId)
+}
+
+func (impl DeploymentApprovalServiceImpl) respondToApprovalRequest(request *ApprovalActionDto, response ApprovalStatus, userId int32) (*ApprovalRequestDto, error) {
+	approvalRequest, err := impl.deploymentApprovalRepository.FindRequestById(request.ApprovalRequestId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching approval request", "err", err, "approvalRequestId", request.ApprovalRequestId)
+		return nil, err
+	}
+	policy, err := impl.deploymentApprovalPolicyRepository.FindActiveByPipelineId(approvalRequest.PipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching approval policy", "err", err, "pipelineId", approvalRequest.PipelineId)
+		return nil, err
+	}
+	userData, err := impl.deploymentApprovalRepository.FindUserDataByRequestIds([]int{approvalRequest.Id})
+	if err != nil {
+		impl.logger.Errorw("error in fetching approval user data", "err", err, "approvalRequestId", approvalRequest.Id)
+		return nil, err
+	}
+	status, _ := EvaluateApprovalStatus(policy.RequiredApprovals, userData)
+	if !approvalRequest.Active || status != APPROVAL_STATUS_REQUESTED {
+		return nil, &util.ApiError{
+			HttpStatusCode:  http.StatusBadRequest,
+			InternalMessage: fmt.Sprintf("approval request %d is not pending, status %s", approvalRequest.Id, status),
+			UserMessage:     "Approval request is not pending anymore",
+		}
+	}
+	if approvalRequest.RequestedBy == userId {
+		return nil, &util.ApiError{
+			HttpStatusCode:  http.StatusForbidden,
+			InternalMessage: fmt.Sprintf("user %d can not respond to own approval request", userId),
+			UserMessage:     "You can not approve or reject your own approval request",
+		}
+	}
+	for _, item := range userData {
+		if item.UserId == userId {
+			return nil, &util.ApiError{
+				HttpStatusCode:  http.StatusBadRequest,
+				InternalMessage: fmt.Sprintf("user %d has already responded to approval request", userId),
+				UserMessage:     "You have already responded to this approval request",
+			}
+		}
+	}
+	eligible, err := impl.isEligibleApprover(policy, userId)
+	if err != nil {
+		return nil, err
+	}
+	if !eligible {
+		return nil, &util.ApiError{
+			HttpStatusCode:  http.StatusForbidden,
+			InternalMessage: fmt.Sprintf("user %d is not an approver for pipeline %d", userId, approvalRequest.PipelineId),
+			UserMessage:     "You are not an approver for this pipeline",
+		}
+	}
+	approvalUserData := &DeploymentApprovalUserData{
+		ApprovalRequestId: approvalRequest.Id,
+		UserId:            userId,
+		UserResponse:      response,
+		Comment:           request.Comment,
+		AuditLog:          sql.AuditLog{CreatedOn: time.Now(), CreatedBy: userId, UpdatedOn: time.Now(), UpdatedBy: userId},
+	}
+	err = impl.deploymentApprovalRepository.SaveUserData(approvalUserData)
+	if err != nil {
+		impl.logger.Errorw("error in saving approval user data", "err", err, "approvalUserData", approvalUserData)
+		return nil, err
+	}
+	approvalRequest.Status, _ = EvaluateApprovalStatus(policy.RequiredApprovals, append(userData, approvalUserData))
+	approvalRequest.UpdatedOn = time.Now()
+	approvalRequest.UpdatedBy = userId
+	err = impl.deploymentApprovalRepository.UpdateRequest(approvalRequest)
+	if err != nil {
+		impl.logger.Errorw("error in updating approval request", "err", err, "approvalRequest", approvalRequest)
+		return nil, err
+	}
+	requests, err := impl.buildApprovalRequestDtos([]*DeploymentApprovalRequest{approvalRequest}, policy)
+	if err != nil {
+		return nil, err
+	}
+	return requests[0], nil
+}
+
+// MarkRequestSuperseded closes an approved auto trigger request without deploying it, the artifact has to be
+// requested again for a later deployment
+func (impl DeploymentApprovalServiceImpl) MarkRequestSuperseded(approvalRequestId int, userId int32) error {
+	approvalRequest, err := impl.deploymentApprovalRepository.FindRequestById(approvalRequestId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching approval request", "err", err, "approvalRequestId", approvalRequestId)
+		return err
+	}
+	approvalRequest.Status = APPROVAL_STATUS_SUPERSEDED
+	approvalRequest.Active = false
+	approvalRequest.UpdatedOn = time.Now()
+	approvalRequest.UpdatedBy = userId
+	err = impl.deploymentApprovalRepository.UpdateRequest(approvalRequest)
+	if err != nil {
+		impl.logger.Errorw("error in marking approval request superseded", "err", err, "approvalRequestId", approvalRequestId)
+		return err
+	}
+	return nil
+}
+
+func (impl DeploymentApprovalServiceImpl) GetApprovalRequests(pipelineId int, ciArtifactId int) ([]*ApprovalRequestDto, error) {
+	var requests []*DeploymentApprovalRequest
+	var err error
+	if ciArtifactId > 0 {
+		requests, err = impl.deploymentApprovalRepository.FindActiveRequestsByPipelineIdAndArtifactIds(pipelineId, []int{ciArtifactId})
+	} else {
+		requests, err = impl.deploymentApprovalRepository.FindAllRequestsByPipelineId(pipelineId)
+	}
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching approval requests", "err", err, "pipelineId", pipelineId, "ciArtifactId", ciArtifactId)
+		return nil, err
+	}
+	policy, err := impl.deploymentApprovalPolicyRepository.FindActiveByPipelineId(pipelineId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching approval policy", "err", err, "pipelineId", pipelineId)
+		return nil, err
+	}
+	if err == pg.ErrNoRows {
+		policy = nil
+	}
+	return impl.buildApprovalRequestDtos(requests, policy)
+}
+
+func (impl DeploymentApprovalServiceImpl) EvaluateApproval(pipelineId int, ciArtifactId int) (*ApprovalEvaluation, error) {
+	evaluations, err := impl.GetApprovalStateForArtifacts(pipelineId, []int{ciArtifactId})
+	if err != nil {
+		return nil, err
+	}
+	return evaluations[ciArtifactId], nil
+}
+
+func (impl DeploymentApprovalServiceImpl) GetApprovalStateForArtifacts(pipelineId int, ciArtifactIds []int) (map[int]*ApprovalEvaluation, error) {
+	evaluations := make(map[int]*ApprovalEvaluation)
+	policy, err := impl.deploymentApprovalPolicyRepository.FindActiveByPipelineId(pipelineId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching approval policy", "err", err, "pipelineId", pipelineId)
+		return nil, err
+	}
+	if err == pg.ErrNoRows {
+		for _, ciArtifactId := range ciArtifactIds {
+			evaluations[ciArtifactId] = &ApprovalEvaluation{Approved: true}
+		}
+		return evaluations, nil
+	}
+	for _, ciArtifactId := range ciArtifactIds {
+		evaluations[ciArtifactId] = &ApprovalEvaluation{ApprovalRequired: true, RequiredApprovals: policy.RequiredApprovals}
+	}
+	requests, err := impl.deploymentApprovalRepository.FindActiveRequestsByPipelineIdAndArtifactIds(pipelineId, ciArtifactIds)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching approval requests", "err", err, "pipelineId", pipelineId)
+		return nil, err
+	}
+	requestDtos, err := impl.buildApprovalRequestDtos(requests, policy)
+	if err != nil {
+		return nil, err
+	}
+	for _, request := range requestDtos {
+		evaluation := evaluations[request.CiArtifactId]
+		evaluation.Status = request.Status
+		evaluation.ApprovalRequestId = request.Id
+		evaluation.Approved = request.Status == APPROVAL_STATUS_APPROVED
+		for _, userResponse := range request.UserResponses {
+			if userResponse.Response == APPROVAL_STATUS_APPROVED {
+				evaluation.ApprovalCount++
+				evaluation.ApprovedBy = append(evaluation.ApprovedBy, userResponse.UserEmail)
+			}
+		}
+	}
+	return evaluations, nil
+}
+
+// buildApprovalRequestDtos evaluates status of requests against the current policy, status of requests of
+// pipelines without policy is left as stored
+func (impl DeploymentApprovalServiceImpl) buildApprovalRequestDtos(requests []*DeploymentApprovalRequest, policy *DeploymentApprovalPolicy) ([]*ApprovalRequestDto, error) {
+	var requestIds []int
+	userIds := make(map[int32]bool)
+	for _, request := range requests {
+		requestIds = append(requestIds, request.Id)
+		userIds[request.RequestedBy] = true
+	}
+	userData, err := impl.deploymentApprovalRepository.FindUserDataByRequestIds(requestIds)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching approval user data", "err", err, "requestIds", requestIds)
+		return nil, err
+	}
+	userDataByRequestId := make(map[int][]*DeploymentApprovalUserData)
+	for _, item := range userData {
+		userDataByRequestId[item.ApprovalRequestId] = append(userDataByRequestId[item.ApprovalRequestId], item)
+		userIds[item.UserId] = true
+	}
+	emailIds, err := impl.getUserEmailIds(userIds)
+	if err != nil {
+		return nil, err
+	}
+	requestDtos := make([]*ApprovalRequestDto, 0, len(requests))
+	for _, request := range requests {
+		requestDto := &ApprovalRequestDto{
+			Id:            request.Id,
+			PipelineId:    request.PipelineId,
+			CiArtifactId:  request.CiArtifactId,
+			Status:        request.Status,
+			RequestedBy:   emailIds[request.RequestedBy],
+			RequestedOn:   request.CreatedOn,
+			Active:        request.Active,
+			AutoTrigger:   request.AutoTrigger,
+			CdWorkflowId:  request.CdWorkflowId,
+			UserResponses: []*ApprovalUserDataDto{},
+		}
+		//superseded is not derived from responses of approvers
+		if policy != nil && request.Status != APPROVAL_STATUS_SUPERSEDED {
+			requestDto.RequiredApprovals = policy.RequiredApprovals
+			requestDto.Status, _ = EvaluateApprovalStatus(policy.RequiredApprovals, userDataByRequestId[request.Id])
+		}
+		for _, item := range userDataByRequestId[request.Id] {
+			requestDto.UserResponses = append(requestDto.UserResponses, &ApprovalUserDataDto{
+				UserId:      item.UserId,
+				UserEmail:   emailIds[item.UserId],
+				Response:    item.UserResponse,
+				Comment:     item.Comment,
+				RespondedOn: item.CreatedOn,
+			})
+		}
+		requestDtos = append(requestDtos, requestDto)
+	}
+	return requestDtos, nil
+}
+
+func (impl DeploymentApprovalServiceImpl) getUserEmailIds(userIds map[int32]bool) (map[int32]string, error) {
+	emailIds := make(map[int32]string)
+	if len(userIds) == 0 {
+		return emailIds, nil
+	}
+	var ids []int32
+	for id := range userIds {
+		ids = append(ids, id)
+	}
+	users, err := impl.userService.GetByIds(ids)
+	if err != nil {
+		impl.logger.Errorw("error in fetching users", "err", err, "userIds", ids)
+		return nil, err
+	}
+	for _, item := range users {
+		emailIds[item.Id] = item.EmailId
+	}
+	return emailIds, nil
+}
+
+func (impl DeploymentApprovalServiceImpl) isEligibleApprover(policy *DeploymentApprovalPolicy, userId int32) (bool, error) {
+	userInfo, err := impl.userService.GetById(userId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching user", "err", err, "userId", userId)
+		return false, err
+	}
+	var approverGroups []string
+	for _, roleGroupId := range policy.ApproverRoleGroupIds {
+		roleGroup, err := impl.roleGroupService.FetchRoleGroupsById(roleGroupId)
+		if err != nil {
+			//role group might have been deleted after policy creation
+			impl.logger.Warnw("error in fetching approver role group, skipping", "err", err, "roleGroupId", roleGroupId)
+			continue
+		}
+		approverGroups = append(approverGroups, roleGroup.Name)
+	}
+	return IsEligibleApprover(policy, userId, userInfo.Groups, approverGroups), nil
+}
+
+func (impl DeploymentApprovalServiceImpl) sendApprovalRequestEvent(approvalRequest *DeploymentApprovalRequest, policy *DeploymentApprovalPolicy) {
+	pipeline, err := impl.pipelineRepository.FindById(approvalRequest.PipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching pipeline for approval event", "err", err, "pipelineId", approvalRequest.PipelineId)
+		return
+	}
+	artifact, err := impl.ciArtifactRepository.Get(approvalRequest.CiArtifactId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching artifact for approval event", "err", err, "ciArtifactId", approvalRequest.CiArtifactId)
+		return
+	}
+	userIds := map[int32]bool{}
+	for _, approverUserId := range policy.ApproverUserIds {
+		userIds[approverUserId] = true
+	}
+	emailIds, err := impl.getUserEmailIds(userIds)
+	if err != nil {
+		return
+	}
+	var approverEmailIds []string
+	for _, approverUserId := range policy.ApproverUserIds {
+		if emailId, ok := emailIds[approverUserId]; ok {
+			approverEmailIds = append(approverEmailIds, emailId)
+		}
+	}
+	event := impl.eventFactory.Build(util2.ApprovalRequested, &pipeline.Id, pipeline.AppId, &pipeline.EnvironmentId, util2.CD)
+	event.UserId = int(approvalRequest.RequestedBy)
+	event = impl.eventFactory.BuildApprovalRequestedData(event, artifact, approvalRequest.Id, approverEmailIds)
+	_, err = impl.eventClient.WriteNotificationEvent(event)
+	if err != nil {
+		impl.logger.Errorw("error in writing approval request event", "err", err, "approvalRequestId", approvalRequest.Id)
+	}
+}
+
+// EvaluateApprovalStatus returns status of an approval request and approvals collected, a single rejection rejects
+// the request
+func EvaluateApprovalStatus(requiredApprovals int, userData []*DeploymentApprovalUserData) (ApprovalStatus, int) {
+	approvals := 0
+	rejected := false
+	for _, item := range userData {
+		if item.UserResponse == APPROVAL_STATUS_REJECTED {
+			rejected = true
+		} else if item.UserResponse == APPROVAL_STATUS_APPROVED {
+			approvals++
+		}
+	}
+	if rejected {
+		return APPROVAL_STATUS_REJECTED, approvals
+	}
+	if approvals >= requiredApprovals {
+		return APPROVAL_STATUS_APPROVED, approvals
+	}
+	return APPROVAL_STATUS_REQUESTED, approvals
+}
+
+// IsEligibleApprover checks if user is listed in policy or belongs to any of the approver role groups
+func IsEligibleApprover(policy *DeploymentApprovalPolicy, userId int32, userGroups []string, approverGroups []string) bool {
+	for _, approverUserId := range policy.ApproverUserIds {
+		if approverUserId == userId {
+			return true
+		}
+	}
+	for _, approverGroup := range approverGroups {
+		for _, userGroup := range userGroups {
+			if approverGroup == userGroup {
+				return true
+			}
+		}
+	}
+	return false
+}
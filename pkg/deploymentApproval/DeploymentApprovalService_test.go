@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package deploymentApproval
+
+import (
+	"testing"
+)
+
+func TestEvaluateApprovalStatus(t *testing.T) {
+	approved := &DeploymentApprovalUserData{UserResponse: APPROVAL_STATUS_APPROVED}
+	rejected := &DeploymentApprovalUserData{UserResponse: APPROVAL_STATUS_REJECTED}
+	tests := []struct {
+		name              string
+		requiredApprovals int
+		userData          []*DeploymentApprovalUserData
+		wantStatus        ApprovalStatus
+		wantApprovals     int
+	}{
+		{name: "no responses", requiredApprovals: 2, userData: nil, wantStatus: APPROVAL_STATUS_REQUESTED, wantApprovals: 0},
+		{name: "partially approved", requiredApprovals: 2, userData: []*DeploymentApprovalUserData{approved}, wantStatus: APPROVAL_STATUS_REQUESTED, wantApprovals: 1},
+		{name: "approved", requiredApprovals: 2, userData: []*DeploymentApprovalUserData{approved, approved}, wantStatus: APPROVAL_STATUS_APPROVED, wantApprovals: 2},
+		{name: "more approvals than required", requiredApprovals: 1, userData: []*DeploymentApprovalUserData{approved, approved}, wantStatus: APPROVAL_STATUS_APPROVED, wantApprovals: 2},
+		{name: "single rejection", requiredApprovals: 2, userData: []*DeploymentApprovalUserData{rejected}, wantStatus: APPROVAL_STATUS_REJECTED, wantApprovals: 0},
+		{name: "rejection after approvals", requiredApprovals: 3, userData: []*DeploymentApprovalUserData{approved, approved, rejected}, wantStatus: APPROVAL_STATUS_REJECTED, wantApprovals: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStatus, gotApprovals := EvaluateApprovalStatus(tt.requiredApprovals, tt.userData)
+			if gotStatus != tt.wantStatus || gotApprovals != tt.wantApprovals {
+				t.Errorf("EvaluateApprovalStatus() = %v, %v, want %v, %v", gotStatus, gotApprovals, tt.wantStatus, tt.wantApprovals)
+			}
+		})
+	}
+}
+
+func TestIsEligibleApprover(t *testing.T) {
+	policy := &DeploymentApprovalPolicy{ApproverUserIds: []int32{2, 3}, ApproverRoleGroupIds: []int32{1}}
+	tests := []struct {
+		name           string
+		userId         int32
+		userGroups     []string
+		approverGroups []string
+		want           bool
+	}{
+		{name: "listed user", userId: 2, want: true},
+		{name: "member of approver group", userId: 5, userGroups: []string{"dev", "release-managers"}, approverGroups: []string{"release-managers"}, want: true},
+		{name: "member of other group", userId: 5, userGroups: []string{"dev"}, approverGroups: []string{"release-managers"}, want: false},
+		{name: "approver group deleted", userId: 5, userGroups: []string{"release-managers"}, approverGroups: nil, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsEligibleApprover(policy, tt.userId, tt.userGroups, tt.approverGroups); got != tt.want {
+				t.Errorf("IsEligibleApprover() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
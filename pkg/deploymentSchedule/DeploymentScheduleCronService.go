@@ -0,0 +1,86 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package deploymentSchedule
+
+import (
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"time"
+)
+
+type DeploymentScheduleCronService interface {
+	ExecuteDeploymentSchedules()
+}
+
+type DeploymentScheduleCronServiceImpl struct {
+	logger                    *zap.SugaredLogger
+	deploymentScheduleService DeploymentScheduleService
+	lastRunOn                 time.Time
+}
+
+type DeploymentScheduleCronConfig struct {
+	DeploymentScheduleCronTime string `env:"DEPLOYMENT_SCHEDULE_CRON_TIME" envDefault:"*/1 * * * *"`
+}
+
+func GetDeploymentScheduleCronConfig() (*DeploymentScheduleCronConfig, error) {
+	cfg := &DeploymentScheduleCronConfig{}
+	err := env.Parse(cfg)
+	if err != nil {
+		fmt.Println("failed to parse deployment schedule cron config: " + err.Error())
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func NewDeploymentScheduleCronServiceImpl(logger *zap.SugaredLogger, deploymentScheduleService DeploymentScheduleService,
+	deploymentScheduleCronConfig *DeploymentScheduleCronConfig) (*DeploymentScheduleCronServiceImpl, error) {
+	deploymentScheduleCronServiceImpl := &DeploymentScheduleCronServiceImpl{
+		logger:                    logger,
+		deploymentScheduleService: deploymentScheduleService,
+		lastRunOn:                 time.Now(),
+	}
+	// initialise cron, a tick still running is skipped so that no scheduled action is executed twice
+	newCron := cron.New(cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger)))
+	newCron.Start()
+
+	_, err := newCron.AddFunc(deploymentScheduleCronConfig.DeploymentScheduleCronTime, deploymentScheduleCronServiceImpl.ExecuteDeploymentSchedules)
+	if err != nil {
+		fmt.Println("error in adding cron function into deployment schedule cron service")
+		return deploymentScheduleCronServiceImpl, err
+	}
+	return deploymentScheduleCronServiceImpl, nil
+}
+
+func (impl *DeploymentScheduleCronServiceImpl) ExecuteDeploymentSchedules() {
+	impl.logger.Debug("starting deployment schedule execution")
+	defer impl.logger.Debug("stopped deployment schedule execution")
+
+	//schedules due since last run are executed, so a slow run does not miss any schedule
+	now := time.Now()
+	err := impl.deploymentScheduleService.ExecuteDueSchedules(impl.lastRunOn, now)
+	impl.lastRunOn = now
+	if err != nil {
+		impl.logger.Errorw("error in executing due deployment schedules", "err", err)
+	}
+	err = impl.deploymentScheduleService.RetryFailedRuns(now)
+	if err != nil {
+		impl.logger.Errorw("error in retrying failed deployment schedule runs", "err", err)
+	}
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package deploymentSchedule
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+)
+
+type DeploymentScheduleTargetType string
+
+const (
+	DEPLOYMENT_SCHEDULE_TARGET_PIPELINE         DeploymentScheduleTargetType = "PIPELINE"
+	DEPLOYMENT_SCHEDULE_TARGET_DEPLOYMENT_GROUP DeploymentScheduleTargetType = "DEPLOYMENT_GROUP"
+)
+
+type DeploymentScheduleAction string
+
+const (
+	DEPLOYMENT_SCHEDULE_ACTION_DEPLOY      DeploymentScheduleAction = "DEPLOY"
+	DEPLOYMENT_SCHEDULE_ACTION_HIBERNATE   DeploymentScheduleAction = "HIBERNATE"
+	DEPLOYMENT_SCHEDULE_ACTION_UNHIBERNATE DeploymentScheduleAction = "UNHIBERNATE"
+)
+
+// DeploymentSchedule runs an action on a cd pipeline or on all apps of a deployment group whenever its cron schedule
+// fires, cron schedule is evaluated in the given timezone
+type DeploymentSchedule struct {
+	tableName         struct{}                     `sql:"deployment_schedule" pg:",discard_unknown_columns"`
+	Id                int                          `sql:"id,pk"`
+	Name              string                       `sql:"name,notnull"`
+	TargetType        DeploymentScheduleTargetType `sql:"target_type,notnull"`
+	PipelineId        int                          `sql:"pipeline_id"`
+	DeploymentGroupId int                          `sql:"deployment_group_id"`
+	Action            DeploymentScheduleAction     `sql:"action,notnull"`
+	CronSchedule      string                       `sql:"cron_schedule,notnull"`
+	Timezone          string                       `sql:"timezone,notnull"`
+	MaxRetries        int                          `sql:"max_retries,notnull"`
+	Active            bool                         `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type DeploymentScheduleRepository interface {
+	Save(schedule *DeploymentSchedule) error
+	Update(schedule *DeploymentSchedule) error
+	FindById(id int) (*DeploymentSchedule, error)
+	FindAllActive() ([]*DeploymentSchedule, error)
+	FindAllActiveByPipelineId(pipelineId int) ([]*DeploymentSchedule, error)
+	FindAllActiveByDeploymentGroupId(deploymentGroupId int) ([]*DeploymentSchedule, error)
+}
+
+type DeploymentScheduleRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewDeploymentScheduleRepositoryImpl(dbConnection *pg.DB) *DeploymentScheduleRepositoryImpl {
+	return &DeploymentScheduleRepositoryImpl{dbConnection: dbConnection}
+}
+
+func (impl DeploymentScheduleRepositoryImpl) Save(schedule *DeploymentSchedule) error {
+	return impl.dbConnection.Insert(schedule)
+}
+
+func (impl DeploymentScheduleRepositoryImpl) Update(schedule *DeploymentSchedule) error {
+	return impl.dbConnection.Update(schedule)
+}
+
+func (impl DeploymentScheduleRepositoryImpl) FindById(id int) (*DeploymentSchedule, error) {
+	schedule := &DeploymentSchedule{}
+	err := impl.dbConnection.Model(schedule).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return schedule, err
+}
+
+func (impl DeploymentScheduleRepositoryImpl) FindAllActive() ([]*DeploymentSchedule, error) {
+	var schedules []*DeploymentSchedule
+	err := impl.dbConnection.Model(&schedules).
+		Where("active = ?", true).
+		Order("id ASC").
+		Select()
+	return schedules, err
+}
+
+func (impl DeploymentScheduleRepositoryImpl) FindAllActiveByPipelineId(pipelineId int) ([]*DeploymentSchedule, error) {
+	var schedules []*DeploymentSchedule
+	err := impl.dbConnection.Model(&schedules).
+		Where("target_type = ?", DEPLOYMENT_SCHEDULE_TARGET_PIPELINE).
+		Where("pipeline_id = ?", pipelineId).
+		Where("active = ?", true).
+		Order("id ASC").
+		Select()
+	return schedules, err
+}
+
+func (impl DeploymentScheduleRepositoryImpl) FindAllActiveByDeploymentGroupId(deploymentGroupId int) ([]*DeploymentSchedule, error) {
+	var schedules []*DeploymentSchedule
+	err := impl.dbConnection.Model(&schedules).
+		Where("target_type = ?", DEPLOYMENT_SCHEDULE_TARGET_DEPLOYMENT_GROUP).
+		Where("deployment_group_id = ?", deploymentGroupId).
+		Where("active = ?", true).
+		Order("id ASC").
+		Select()
+	return schedules, err
+}
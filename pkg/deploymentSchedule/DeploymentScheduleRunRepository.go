@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package deploymentSchedule
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"time"
+)
+
+type DeploymentScheduleRunStatus string
+
+const (
+	DEPLOYMENT_SCHEDULE_RUN_STATUS_SUCCEEDED DeploymentScheduleRunStatus = "SUCCEEDED"
+	DEPLOYMENT_SCHEDULE_RUN_STATUS_FAILED    DeploymentScheduleRunStatus = "FAILED"
+)
+
+// DeploymentScheduleRun is the audit of one attempt of a scheduled action, a failed attempt with retry pending is
+// picked up again at next retry time
+type DeploymentScheduleRun struct {
+	tableName    struct{}                    `sql:"deployment_schedule_run" pg:",discard_unknown_columns"`
+	Id           int                         `sql:"id,pk"`
+	ScheduleId   int                         `sql:"schedule_id,notnull"`
+	Action       DeploymentScheduleAction    `sql:"action,notnull"`
+	Attempt      int                         `sql:"attempt,notnull"`
+	Status       DeploymentScheduleRunStatus `sql:"status,notnull"`
+	Message      string                      `sql:"message"`
+	ScheduledOn  time.Time                   `sql:"scheduled_on,notnull"`
+	ExecutedOn   time.Time                   `sql:"executed_on,notnull"`
+	RetryPending bool                        `sql:"retry_pending,notnull"`
+	NextRetryOn  time.Time                   `sql:"next_retry_on"`
+	sql.AuditLog
+}
+
+type DeploymentScheduleRunRepository interface {
+	Save(run *DeploymentScheduleRun) error
+	Update(run *DeploymentScheduleRun) error
+	FindAllRetryPending(before time.Time) ([]*DeploymentScheduleRun, error)
+	FindAllByScheduleId(scheduleId int, limit int) ([]*DeploymentScheduleRun, error)
+	ClearRetryPendingByScheduleId(scheduleId int) error
+}
+
+type DeploymentScheduleRunRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewDeploymentScheduleRunRepositoryImpl(dbConnection *pg.DB) *DeploymentScheduleRunRepositoryImpl {
+	return &DeploymentScheduleRunRepositoryImpl{dbConnection: dbConnection}
+}
+
+func (impl DeploymentScheduleRunRepositoryImpl) Save(run *DeploymentScheduleRun) error {
+	return impl.dbConnection.Insert(run)
+}
+
+func (impl DeploymentScheduleRunRepositoryImpl) Update(run *DeploymentScheduleRun) error {
+	return impl.dbConnection.Update(run)
+}
+
+func (impl DeploymentScheduleRunRepositoryImpl) FindAllRetryPending(before time.Time) ([]*DeploymentScheduleRun, error) {
+	var runs []*DeploymentScheduleRun
+	err := impl.dbConnection.Model(&runs).
+		Where("retry_pending = ?", true).
+		Where("next_retry_on <= ?", before).
+		Order("id ASC").
+		Select()
+	return runs, err
+}
+
+func (impl DeploymentScheduleRunRepositoryImpl) FindAllByScheduleId(scheduleId int, limit int) ([]*DeploymentScheduleRun, error) {
+	var runs []*DeploymentScheduleRun
+	err := impl.dbConnection.Model(&runs).
+		Where("schedule_id = ?", scheduleId).
+		Order("id DESC").
+		Limit(limit).
+		Select()
+	return runs, err
+}
+
+// ClearRetryPendingByScheduleId drops pending retries of a schedule, used once a newer run of the schedule is due
+func (impl DeploymentScheduleRunRepositoryImpl) ClearRetryPendingByScheduleId(scheduleId int) error {
+	_, err := impl.dbConnection.Model((*DeploymentScheduleRun)(nil)).
+		Set("retry_pending = ?", false).
+		Set("updated_on = ?", time.Now()).
+		Where("schedule_id = ?", scheduleId).
+		Where("retry_pending = ?", true).
+		Update()
+	return err
+}
@@ -0,0 +1,554 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package deploymentSchedule
+
+import (
+	"context"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/deploymentGroup"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/devtron-labs/devtron/util/argo"
+	"github.com/go-pg/pg"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+const (
+	DeploymentScheduleMaxRetries     = 5
+	DeploymentScheduleRunAuditLimit  = 50
+	deploymentScheduleRetryBaseDelay = time.Minute
+	deploymentScheduleRetryMaxDelay  = 30 * time.Minute
+)
+
+type DeploymentScheduleService interface {
+	Create(request *DeploymentScheduleDto, userId int32) (*DeploymentScheduleDto, error)
+	Update(request *DeploymentScheduleDto, userId int32) (*DeploymentScheduleDto, error)
+	Delete(id int, userId int32) error
+	GetById(id int) (*DeploymentScheduleDto, error)
+	GetByPipelineId(pipelineId int) ([]*DeploymentScheduleDto, error)
+	GetByDeploymentGroupId(deploymentGroupId int) ([]*DeploymentScheduleDto, error)
+	GetRuns(scheduleId int) ([]*DeploymentScheduleRunDto, error)
+
+	// ExecuteDueSchedules runs all active schedules which have a run in (from, to]
+	ExecuteDueSchedules(from time.Time, to time.Time) error
+	// RetryFailedRuns runs again the failed attempts whose retry is due at now
+	RetryFailedRuns(now time.Time) error
+}
+
+type DeploymentScheduleServiceImpl struct {
+	logger                          *zap.SugaredLogger
+	deploymentScheduleRepository    DeploymentScheduleRepository
+	deploymentScheduleRunRepository DeploymentScheduleRunRepository
+	pipelineRepository              pipelineConfig.PipelineRepository
+	deploymentGroupRepository       repository.DeploymentGroupRepository
+	pipelineBuilder                 pipeline.PipelineBuilder
+	workflowDagExecutor             pipeline.WorkflowDagExecutor
+	deploymentGroupService          deploymentGroup.DeploymentGroupService
+	argoUserService                 argo.ArgoUserService
+}
+
+type DeploymentScheduleDto struct {
+	Id                int                          `json:"id"`
+	Name              string                       `json:"name" validate:"required"`
+	TargetType        DeploymentScheduleTargetType `json:"targetType" validate:"oneof=PIPELINE DEPLOYMENT_GROUP"`
+	PipelineId        int                          `json:"pipelineId,omitempty"`
+	DeploymentGroupId int                          `json:"deploymentGroupId,omitempty"`
+	Action            DeploymentScheduleAction     `json:"action" validate:"oneof=DEPLOY HIBERNATE UNHIBERNATE"`
+	CronSchedule      string                       `json:"cronSchedule" validate:"required"`
+	Timezone          string                       `json:"timezone,omitempty"`
+	MaxRetries        int                          `json:"maxRetries" validate:"min=0"`
+	NextRunOn         *time.Time                   `json:"nextRunOn,omitempty"`
+}
+
+type DeploymentScheduleRunDto struct {
+	Id           int                         `json:"id"`
+	ScheduleId   int                         `json:"scheduleId"`
+	Action       DeploymentScheduleAction    `json:"action"`
+	Attempt      int                         `json:"attempt"`
+	Status       DeploymentScheduleRunStatus `json:"status"`
+	Message      string                      `json:"message,omitempty"`
+	ScheduledOn  time.Time                   `json:"scheduledOn"`
+	ExecutedOn   time.Time                   `json:"executedOn"`
+	RetryPending bool                        `json:"retryPending"`
+	NextRetryOn  *time.Time                  `json:"nextRetryOn,omitempty"`
+}
+
+func NewDeploymentScheduleServiceImpl(logger *zap.SugaredLogger, deploymentScheduleRepository DeploymentScheduleRepository,
+	deploymentScheduleRunRepository DeploymentScheduleRunRepository, pipelineRepository pipelineConfig.PipelineRepository,
+	deploymentGroupRepository repository.DeploymentGroupRepository, pipelineBuilder pipeline.PipelineBuilder,
+	workflowDagExecutor pipeline.WorkflowDagExecutor, deploymentGroupService deploymentGroup.DeploymentGroupService,
+	argoUserService argo.ArgoUserService) *DeploymentScheduleServiceImpl {
+	return &DeploymentScheduleServiceImpl{
+		logger:                          logger,
+		deploymentScheduleRepository:    deploymentScheduleRepository,
+		deploymentScheduleRunRepository: deploymentScheduleRunRepository,
+		pipelineRepository:              pipelineRepository,
+		deploymentGroupRepository:       deploymentGroupRepository,
+		pipelineBuilder:                 pipelineBuilder,
+		workflowDagExecutor:             workflowDagExecutor,
+		deploymentGroupService:          deploymentGroupService,
+		argoUserService:                 argoUserService,
+	}
+}
+
+func (impl DeploymentScheduleServiceImpl) Create(request *DeploymentScheduleDto, userId int32) (*DeploymentScheduleDto, error) {
+	err := impl.validateDeploymentSchedule(request)
+	if err != nil {
+		impl.logger.Errorw("invalid deployment schedule request", "err", err, "request", request)
+		return nil, err
+	}
+	schedule := &DeploymentSchedule{
+		Active:   true,
+		AuditLog: sql.AuditLog{CreatedOn: time.Now(), CreatedBy: userId, UpdatedOn: time.Now(), UpdatedBy: userId},
+	}
+	copyDtoToDeploymentSchedule(request, schedule)
+	err = impl.deploymentScheduleRepository.Save(schedule)
+	if err != nil {
+		impl.logger.Errorw("error in saving deployment schedule", "err", err, "schedule", schedule)
+		return nil, err
+	}
+	return buildDeploymentScheduleDto(schedule, time.Now()), nil
+}
+
+func (impl DeploymentScheduleServiceImpl) Update(request *DeploymentScheduleDto, userId int32) (*DeploymentScheduleDto, error) {
+	err := impl.validateDeploymentSchedule(request)
+	if err != nil {
+		impl.logger.Errorw("invalid deployment schedule request", "err", err, "request", request)
+		return nil, err
+	}
+	schedule, err := impl.deploymentScheduleRepository.FindById(request.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment schedule", "err", err, "id", request.Id)
+		return nil, err
+	}
+	copyDtoToDeploymentSchedule(request, schedule)
+	schedule.UpdatedOn = time.Now()
+	schedule.UpdatedBy = userId
+	err = impl.deploymentScheduleRepository.Update(schedule)
+	if err != nil {
+		impl.logger.Errorw("error in updating deployment schedule", "err", err, "schedule", schedule)
+		return nil, err
+	}
+	return buildDeploymentScheduleDto(schedule, time.Now()), nil
+}
+
+func (impl DeploymentScheduleServiceImpl) Delete(id int, userId int32) error {
+	schedule, err := impl.deploymentScheduleRepository.FindById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment schedule", "err", err, "id", id)
+		return err
+	}
+	schedule.Active = false
+	schedule.UpdatedOn = time.Now()
+	schedule.UpdatedBy = userId
+	err = impl.deploymentScheduleRepository.Update(schedule)
+	if err != nil {
+		impl.logger.Errorw("error in deleting deployment schedule", "err", err, "id", id)
+		return err
+	}
+	err = impl.deploymentScheduleRunRepository.ClearRetryPendingByScheduleId(id)
+	if err != nil {
+		impl.logger.Errorw("error in clearing pending retries of deployment schedule", "err", err, "id", id)
+		return err
+	}
+	return nil
+}
+
+func (impl DeploymentScheduleServiceImpl) GetById(id int) (*DeploymentScheduleDto, error) {
+	schedule, err := impl.deploymentScheduleRepository.FindById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment schedule", "err", err, "id", id)
+		return nil, err
+	}
+	return buildDeploymentScheduleDto(schedule, time.Now()), nil
+}
+
+func (impl DeploymentScheduleServiceImpl) GetByPipelineId(pipelineId int) ([]*DeploymentScheduleDto, error) {
+	schedules, err := impl.deploymentScheduleRepository.FindAllActiveByPipelineId(pipelineId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching deployment schedules", "err", err, "pipelineId", pipelineId)
+		return nil, err
+	}
+	return buildDeploymentScheduleDtos(schedules), nil
+}
+
+func (impl DeploymentScheduleServiceImpl) GetByDeploymentGroupId(deploymentGroupId int) ([]*DeploymentScheduleDto, error) {
+	schedules, err := impl.deploymentScheduleRepository.FindAllActiveByDeploymentGroupId(deploymentGroupId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching deployment schedules", "err", err, "deploymentGroupId", deploymentGroupId)
+		return nil, err
+	}
+	return buildDeploymentScheduleDtos(schedules), nil
+}
+
+func (impl DeploymentScheduleServiceImpl) GetRuns(scheduleId int) ([]*DeploymentScheduleRunDto, error) {
+	runs, err := impl.deploymentScheduleRunRepository.FindAllByScheduleId(scheduleId, DeploymentScheduleRunAuditLimit)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching deployment schedule runs", "err", err, "scheduleId", scheduleId)
+		return nil, err
+	}
+	dtos := make([]*DeploymentScheduleRunDto, 0, len(runs))
+	for _, run := range runs {
+		dto := &DeploymentScheduleRunDto{
+			Id:           run.Id,
+			ScheduleId:   run.ScheduleId,
+			Action:       run.Action,
+			Attempt:      run.Attempt,
+			Status:       run.Status,
+			Message:      run.Message,
+			ScheduledOn:  run.ScheduledOn,
+			ExecutedOn:   run.ExecutedOn,
+			RetryPending: run.RetryPending,
+		}
+		if run.RetryPending {
+			nextRetryOn := run.NextRetryOn
+			dto.NextRetryOn = &nextRetryOn
+		}
+		dtos = append(dtos, dto)
+	}
+	return dtos, nil
+}
+
+func (impl DeploymentScheduleServiceImpl) ExecuteDueSchedules(from time.Time, to time.Time) error {
+	schedules, err := impl.deploymentScheduleRepository.FindAllActive()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching active deployment schedules", "err", err)
+		return err
+	}
+	for _, schedule := range schedules {
+		scheduledOn, isDue, err := GetDueScheduleRun(schedule.CronSchedule, schedule.Timezone, from, to)
+		if err != nil {
+			impl.logger.Errorw("error in evaluating deployment schedule, skipping", "err", err, "scheduleId", schedule.Id)
+			continue
+		}
+		if !isDue {
+			continue
+		}
+		//a newer run supersedes retries still pending from older runs of the schedule
+		err = impl.deploymentScheduleRunRepository.ClearRetryPendingByScheduleId(schedule.Id)
+		if err != nil {
+			impl.logger.Errorw("error in clearing pending retries of deployment schedule", "err", err, "scheduleId", schedule.Id)
+		}
+		impl.executeSchedule(schedule, scheduledOn, 1)
+	}
+	return nil
+}
+
+func (impl DeploymentScheduleServiceImpl) RetryFailedRuns(now time.Time) error {
+	runs, err := impl.deploymentScheduleRunRepository.FindAllRetryPending(now)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching deployment schedule runs pending retry", "err", err)
+		return err
+	}
+	for _, run := range runs {
+		//retry is consumed before executing so that a failure in saving it does not trigger the action twice
+		run.RetryPending = false
+		run.UpdatedOn = time.Now()
+		err = impl.deploymentScheduleRunRepository.Update(run)
+		if err != nil {
+			impl.logger.Errorw("error in updating deployment schedule run", "err", err, "runId", run.Id)
+			continue
+		}
+		schedule, err := impl.deploymentScheduleRepository.FindById(run.ScheduleId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching deployment schedule, skipping retry", "err", err, "scheduleId", run.ScheduleId)
+			continue
+		}
+		impl.executeSchedule(schedule, run.ScheduledOn, run.Attempt+1)
+	}
+	return nil
+}
+
+// executeSchedule runs the scheduled action once and audits the attempt, a failed attempt is marked for retry
+// until max retries of the schedule are exhausted
+func (impl DeploymentScheduleServiceImpl) executeSchedule(schedule *DeploymentSchedule, scheduledOn time.Time, attempt int) {
+	message, err := impl.triggerScheduledAction(schedule)
+	executedOn := time.Now()
+	run := &DeploymentScheduleRun{
+		ScheduleId:  schedule.Id,
+		Action:      schedule.Action,
+		Attempt:     attempt,
+		Status:      DEPLOYMENT_SCHEDULE_RUN_STATUS_SUCCEEDED,
+		Message:     message,
+		ScheduledOn: scheduledOn,
+		ExecutedOn:  executedOn,
+		AuditLog:    sql.AuditLog{CreatedOn: executedOn, CreatedBy: 1, UpdatedOn: executedOn, UpdatedBy: 1},
+	}
+	if err != nil {
+		impl.logger.Errorw("error in executing deployment schedule", "err", err, "scheduleId", schedule.Id, "attempt", attempt)
+		run.Status = DEPLOYMENT_SCHEDULE_RUN_STATUS_FAILED
+		run.Message = err.Error()
+		if attempt <= schedule.MaxRetries {
+			run.RetryPending = true
+			run.NextRetryOn = executedOn.Add(GetRetryBackoff(attempt))
+		}
+	}
+	err = impl.deploymentScheduleRunRepository.Save(run)
+	if err != nil {
+		impl.logger.Errorw("error in saving deployment schedule run", "err", err, "run", run)
+	}
+}
+
+func (impl DeploymentScheduleServiceImpl) triggerScheduledAction(schedule *DeploymentSchedule) (string, error) {
+	acdToken, err := impl.argoUserService.GetLatestDevtronArgoCdUserToken()
+	if err != nil {
+		impl.logger.Errorw("error in getting acd token", "err", err)
+		return "", err
+	}
+	ctx := context.WithValue(context.Background(), "token", acdToken)
+	//scheduled actions run on behalf of the user who configured the schedule
+	userId := schedule.CreatedBy
+	if schedule.TargetType == DEPLOYMENT_SCHEDULE_TARGET_DEPLOYMENT_GROUP {
+		return impl.triggerDeploymentGroupAction(schedule, userId, ctx)
+	}
+	return impl.triggerPipelineAction(schedule, userId, ctx)
+}
+
+func (impl DeploymentScheduleServiceImpl) triggerPipelineAction(schedule *DeploymentSchedule, userId int32, ctx context.Context) (string, error) {
+	cdPipeline, err := impl.pipelineRepository.FindById(schedule.PipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching pipeline", "err", err, "pipelineId", schedule.PipelineId)
+		return "", err
+	}
+	if cdPipeline.Deleted {
+		return "", fmt.Errorf("pipeline %d is deleted", cdPipeline.Id)
+	}
+	switch schedule.Action {
+	case DEPLOYMENT_SCHEDULE_ACTION_DEPLOY:
+		//deploying the latest artifact of parent stage as offered by cd material list, parent being ci, pre stage or parent cd
+		artifact, err := impl.pipelineBuilder.GetLatestArtifactOfParentStage(cdPipeline.Id, bean.CD_WORKFLOW_TYPE_DEPLOY)
+		if err != nil {
+			impl.logger.Errorw("error in fetching latest artifact of parent stage", "err", err, "pipelineId", cdPipeline.Id)
+			return "", err
+		}
+		if artifact == nil {
+			return "", fmt.Errorf("no artifact found for pipeline %d", cdPipeline.Id)
+		}
+		overrideRequest := &bean.ValuesOverrideRequest{
+			PipelineId:     cdPipeline.Id,
+			AppId:          cdPipeline.AppId,
+			CiArtifactId:   artifact.Id,
+			CdWorkflowType: bean.CD_WORKFLOW_TYPE_DEPLOY,
+			UserId:         userId,
+		}
+		_, err = impl.workflowDagExecutor.ManualCdTrigger(overrideRequest, ctx)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("deployed artifact %s", artifact.Image), nil
+	case DEPLOYMENT_SCHEDULE_ACTION_HIBERNATE, DEPLOYMENT_SCHEDULE_ACTION_UNHIBERNATE:
+		stopRequest := &pipeline.StopAppRequest{
+			AppId:         cdPipeline.AppId,
+			EnvironmentId: cdPipeline.EnvironmentId,
+			UserId:        userId,
+			RequestType:   getStopRequestType(schedule.Action),
+		}
+		_, err = impl.workflowDagExecutor.StopStartApp(stopRequest, ctx)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s triggered for app %d on environment %d", stopRequest.RequestType, cdPipeline.AppId, cdPipeline.EnvironmentId), nil
+	}
+	return "", fmt.Errorf("unsupported action %s", schedule.Action)
+}
+
+func (impl DeploymentScheduleServiceImpl) triggerDeploymentGroupAction(schedule *DeploymentSchedule, userId int32, ctx context.Context) (string, error) {
+	group, err := impl.deploymentGroupRepository.FindByIdWithApp(schedule.DeploymentGroupId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment group", "err", err, "deploymentGroupId", schedule.DeploymentGroupId)
+		return "", err
+	}
+	switch schedule.Action {
+	case DEPLOYMENT_SCHEDULE_ACTION_DEPLOY:
+		//deployment group material list offers artifacts of the ci pipeline shared by its apps
+		artifactsResponse, err := impl.deploymentGroupService.GetArtifactsByCiPipeline(group.CiPipelineId)
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error in fetching artifacts", "err", err, "ciPipelineId", group.CiPipelineId)
+			return "", err
+		}
+		artifacts := artifactsResponse.CiArtifacts
+		if len(artifacts) == 0 {
+			return "", fmt.Errorf("no artifact found for deployment group %d", group.Id)
+		}
+		triggerRequest := &deploymentGroup.DeploymentGroupTriggerRequest{
+			DeploymentGroupId: group.Id,
+			UserId:            userId,
+			CiArtifactId:      artifacts[0].Id,
+		}
+		_, err = impl.deploymentGroupService.TriggerReleaseForDeploymentGroup(triggerRequest)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("release of artifact %s triggered for %d apps", artifacts[0].Image, len(group.DeploymentGroupApps)), nil
+	case DEPLOYMENT_SCHEDULE_ACTION_HIBERNATE, DEPLOYMENT_SCHEDULE_ACTION_UNHIBERNATE:
+		stopRequest := pipeline.StopDeploymentGroupRequest{
+			DeploymentGroupId: group.Id,
+			UserId:            userId,
+			RequestType:       getStopRequestType(schedule.Action),
+		}
+		_, err = impl.workflowDagExecutor.TriggerBulkHibernateAsync(stopRequest, ctx)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s triggered for %d apps", stopRequest.RequestType, len(group.DeploymentGroupApps)), nil
+	}
+	return "", fmt.Errorf("unsupported action %s", schedule.Action)
+}
+
+func (impl DeploymentScheduleServiceImpl) validateDeploymentSchedule(request *DeploymentScheduleDto) error {
+	validationErr := ValidateDeploymentScheduleRequest(request)
+	if validationErr == nil {
+		if request.TargetType == DEPLOYMENT_SCHEDULE_TARGET_PIPELINE {
+			cdPipeline, err := impl.pipelineRepository.FindById(request.PipelineId)
+			if err != nil && err != pg.ErrNoRows {
+				impl.logger.Errorw("error in fetching pipeline", "err", err, "pipelineId", request.PipelineId)
+				return err
+			}
+			if err == pg.ErrNoRows || cdPipeline.Deleted {
+				validationErr = fmt.Errorf("pipeline %d not found", request.PipelineId)
+			}
+		} else {
+			_, err := impl.deploymentGroupRepository.GetById(request.DeploymentGroupId)
+			if err != nil && err != pg.ErrNoRows {
+				impl.logger.Errorw("error in fetching deployment group", "err", err, "deploymentGroupId", request.DeploymentGroupId)
+				return err
+			}
+			if err == pg.ErrNoRows {
+				validationErr = fmt.Errorf("deployment group %d not found", request.DeploymentGroupId)
+			}
+		}
+	}
+	if validationErr != nil {
+		return &util.ApiError{
+			HttpStatusCode:  http.StatusBadRequest,
+			InternalMessage: validationErr.Error(),
+			UserMessage:     validationErr.Error(),
+		}
+	}
+	return nil
+}
+
+// ValidateDeploymentScheduleRequest checks the target, cron schedule and timezone of a schedule, timezone defaults to UTC
+func ValidateDeploymentScheduleRequest(request *DeploymentScheduleDto) error {
+	if len(request.Timezone) == 0 {
+		request.Timezone = "UTC"
+	}
+	if request.TargetType == DEPLOYMENT_SCHEDULE_TARGET_PIPELINE && request.PipelineId <= 0 {
+		return fmt.Errorf("pipeline id is required for pipeline schedule")
+	}
+	if request.TargetType == DEPLOYMENT_SCHEDULE_TARGET_DEPLOYMENT_GROUP && request.DeploymentGroupId <= 0 {
+		return fmt.Errorf("deployment group id is required for deployment group schedule")
+	}
+	if _, err := time.LoadLocation(request.Timezone); err != nil {
+		return fmt.Errorf("invalid timezone %s", request.Timezone)
+	}
+	if _, err := cron.ParseStandard(request.CronSchedule); err != nil {
+		return fmt.Errorf("invalid cron schedule %s", request.CronSchedule)
+	}
+	if request.MaxRetries < 0 || request.MaxRetries > DeploymentScheduleMaxRetries {
+		return fmt.Errorf("max retries should be between 0 and %d", DeploymentScheduleMaxRetries)
+	}
+	return nil
+}
+
+// GetDueScheduleRun returns the run of the cron schedule in (from, to] evaluated in the timezone of the schedule
+func GetDueScheduleRun(cronSchedule string, timezone string, from time.Time, to time.Time) (time.Time, bool, error) {
+	schedule, err := parseCronSchedule(cronSchedule, timezone)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	scheduledOn := schedule.Next(from)
+	return scheduledOn, !scheduledOn.After(to), nil
+}
+
+// GetRetryBackoff doubles the wait before each retry of a failed attempt, capped at 30 minutes
+func GetRetryBackoff(attempt int) time.Duration {
+	delay := deploymentScheduleRetryBaseDelay
+	for i := 1; i < attempt && delay < deploymentScheduleRetryMaxDelay; i++ {
+		delay = delay * 2
+	}
+	if delay > deploymentScheduleRetryMaxDelay {
+		delay = deploymentScheduleRetryMaxDelay
+	}
+	return delay
+}
+
+func parseCronSchedule(cronSchedule string, timezone string) (cron.Schedule, error) {
+	if len(timezone) > 0 {
+		cronSchedule = fmt.Sprintf("CRON_TZ=%s %s", timezone, cronSchedule)
+	}
+	return cron.ParseStandard(cronSchedule)
+}
+
+func getStopRequestType(action DeploymentScheduleAction) pipeline.RequestType {
+	if action == DEPLOYMENT_SCHEDULE_ACTION_HIBERNATE {
+		return pipeline.STOP
+	}
+	return pipeline.START
+}
+
+func copyDtoToDeploymentSchedule(request *DeploymentScheduleDto, schedule *DeploymentSchedule) {
+	schedule.Name = request.Name
+	schedule.TargetType = request.TargetType
+	schedule.PipelineId = 0
+	schedule.DeploymentGroupId = 0
+	if request.TargetType == DEPLOYMENT_SCHEDULE_TARGET_PIPELINE {
+		schedule.PipelineId = request.PipelineId
+	} else {
+		schedule.DeploymentGroupId = request.DeploymentGroupId
+	}
+	schedule.Action = request.Action
+	schedule.CronSchedule = request.CronSchedule
+	schedule.Timezone = request.Timezone
+	schedule.MaxRetries = request.MaxRetries
+}
+
+func buildDeploymentScheduleDtos(schedules []*DeploymentSchedule) []*DeploymentScheduleDto {
+	now := time.Now()
+	dtos := make([]*DeploymentScheduleDto, 0, len(schedules))
+	for _, schedule := range schedules {
+		dtos = append(dtos, buildDeploymentScheduleDto(schedule, now))
+	}
+	return dtos
+}
+
+func buildDeploymentScheduleDto(schedule *DeploymentSchedule, now time.Time) *DeploymentScheduleDto {
+	dto := &DeploymentScheduleDto{
+		Id:                schedule.Id,
+		Name:              schedule.Name,
+		TargetType:        schedule.TargetType,
+		PipelineId:        schedule.PipelineId,
+		DeploymentGroupId: schedule.DeploymentGroupId,
+		Action:            schedule.Action,
+		CronSchedule:      schedule.CronSchedule,
+		Timezone:          schedule.Timezone,
+		MaxRetries:        schedule.MaxRetries,
+	}
+	if cronSchedule, err := parseCronSchedule(schedule.CronSchedule, schedule.Timezone); err == nil {
+		nextRunOn := cronSchedule.Next(now)
+		dto.NextRunOn = &nextRunOn
+	}
+	return dto
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package deploymentSchedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetDueScheduleRun(t *testing.T) {
+	//hibernate at 20:00 and wake up at 08:00 IST on weekdays
+	hibernate := "0 20 * * 1-5"
+	wakeUp := "0 8 * * 1-5"
+	tests := []struct {
+		name         string
+		cronSchedule string
+		timezone     string
+		from         time.Time
+		to           time.Time
+		want         bool
+		wantOn       time.Time
+	}{
+		//Monday 19:59 to 20:00 IST
+		{name: "hibernate due", cronSchedule: hibernate, timezone: "Asia/Kolkata", from: time.Date(2022, 8, 8, 14, 29, 0, 0, time.UTC), to: time.Date(2022, 8, 8, 14, 30, 0, 0, time.UTC), want: true, wantOn: time.Date(2022, 8, 8, 14, 30, 0, 0, time.UTC)},
+		//Monday 20:00 to 20:01 IST, already executed on previous tick
+		{name: "hibernate already run", cronSchedule: hibernate, timezone: "Asia/Kolkata", from: time.Date(2022, 8, 8, 14, 30, 0, 0, time.UTC), to: time.Date(2022, 8, 8, 14, 31, 0, 0, time.UTC), want: false},
+		//Monday 20:00 UTC is not 20:00 IST
+		{name: "hibernate in other timezone", cronSchedule: hibernate, timezone: "Asia/Kolkata", from: time.Date(2022, 8, 8, 19, 59, 0, 0, time.UTC), to: time.Date(2022, 8, 8, 20, 0, 0, 0, time.UTC), want: false},
+		//Saturday 07:59 to 08:00 IST
+		{name: "wake up on weekend", cronSchedule: wakeUp, timezone: "Asia/Kolkata", from: time.Date(2022, 8, 13, 2, 29, 0, 0, time.UTC), to: time.Date(2022, 8, 13, 2, 30, 0, 0, time.UTC), want: false},
+		//Tuesday 07:55 to 08:05 IST, slow tick
+		{name: "wake up within long tick", cronSchedule: wakeUp, timezone: "Asia/Kolkata", from: time.Date(2022, 8, 9, 2, 25, 0, 0, time.UTC), to: time.Date(2022, 8, 9, 2, 35, 0, 0, time.UTC), want: true, wantOn: time.Date(2022, 8, 9, 2, 30, 0, 0, time.UTC)},
+		{name: "no timezone is local", cronSchedule: "*/5 * * * *", timezone: "", from: time.Date(2022, 8, 9, 2, 24, 0, 0, time.Local), to: time.Date(2022, 8, 9, 2, 25, 0, 0, time.Local), want: true, wantOn: time.Date(2022, 8, 9, 2, 25, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotOn, got, err := GetDueScheduleRun(tt.cronSchedule, tt.timezone, tt.from, tt.to)
+			if err != nil {
+				t.Errorf("GetDueScheduleRun() error = %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetDueScheduleRun() got = %v, want %v", got, tt.want)
+			}
+			if tt.want && !gotOn.Equal(tt.wantOn) {
+				t.Errorf("GetDueScheduleRun() scheduledOn = %v, want %v", gotOn, tt.wantOn)
+			}
+		})
+	}
+	if _, _, err := GetDueScheduleRun("0 20 * * 1-5", "Mars/Olympus", time.Now(), time.Now()); err == nil {
+		t.Errorf("GetDueScheduleRun() expected error for invalid timezone")
+	}
+}
+
+func TestGetRetryBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: time.Minute},
+		{attempt: 2, want: 2 * time.Minute},
+		{attempt: 3, want: 4 * time.Minute},
+		{attempt: 5, want: 16 * time.Minute},
+		{attempt: 6, want: 30 * time.Minute},
+		{attempt: 20, want: 30 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := GetRetryBackoff(tt.attempt); got != tt.want {
+			t.Errorf("GetRetryBackoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestValidateDeploymentScheduleRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		request *DeploymentScheduleDto
+		wantErr bool
+	}{
+		{name: "valid pipeline schedule", request: &DeploymentScheduleDto{TargetType: DEPLOYMENT_SCHEDULE_TARGET_PIPELINE, PipelineId: 1, CronSchedule: "0 20 * * 1-5", Timezone: "Asia/Kolkata"}, wantErr: false},
+		{name: "default timezone", request: &DeploymentScheduleDto{TargetType: DEPLOYMENT_SCHEDULE_TARGET_DEPLOYMENT_GROUP, DeploymentGroupId: 1, CronSchedule: "0 8 * * 1-5"}, wantErr: false},
+		{name: "missing pipeline", request: &DeploymentScheduleDto{TargetType: DEPLOYMENT_SCHEDULE_TARGET_PIPELINE, CronSchedule: "0 20 * * 1-5"}, wantErr: true},
+		{name: "missing deployment group", request: &DeploymentScheduleDto{TargetType: DEPLOYMENT_SCHEDULE_TARGET_DEPLOYMENT_GROUP, PipelineId: 1, CronSchedule: "0 20 * * 1-5"}, wantErr: true},
+		{name: "invalid cron", request: &DeploymentScheduleDto{TargetType: DEPLOYMENT_SCHEDULE_TARGET_PIPELINE, PipelineId: 1, CronSchedule: "every evening"}, wantErr: true},
+		{name: "invalid timezone", request: &DeploymentScheduleDto{TargetType: DEPLOYMENT_SCHEDULE_TARGET_PIPELINE, PipelineId: 1, CronSchedule: "0 20 * * *", Timezone: "IST+1"}, wantErr: true},
+		{name: "too many retries", request: &DeploymentScheduleDto{TargetType: DEPLOYMENT_SCHEDULE_TARGET_PIPELINE, PipelineId: 1, CronSchedule: "0 20 * * *", MaxRetries: 10}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateDeploymentScheduleRequest(tt.request); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateDeploymentScheduleRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package deploymentWindow
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"time"
+)
+
+type DeploymentQueueStatus string
+
+const (
+	DEPLOYMENT_QUEUE_STATUS_QUEUED     DeploymentQueueStatus = "QUEUED"
+	DEPLOYMENT_QUEUE_STATUS_TRIGGERED  DeploymentQueueStatus = "TRIGGERED"
+	DEPLOYMENT_QUEUE_STATUS_SUPERSEDED DeploymentQueueStatus = "SUPERSEDED"
+	DEPLOYMENT_QUEUE_STATUS_FAILED     DeploymentQueueStatus = "FAILED"
+)
+
+// DeploymentQueue holds automatic deployments which were triggered outside the deployment window of their environment,
+// these are picked up again once the window opens
+type DeploymentQueue struct {
+	tableName          struct{}              `sql:"deployment_queue" pg:",discard_unknown_columns"`
+	Id                 int                   `sql:"id,pk"`
+	PipelineId         int                   `sql:"pipeline_id,notnull"`
+	EnvironmentId      int                   `sql:"environment_id,notnull"`
+	CiArtifactId       int                   `sql:"ci_artifact_id,notnull"`
+	CdWorkflowId       int                   `sql:"cd_workflow_id,notnull"`
+	CdWorkflowRunnerId int                   `sql:"cd_workflow_runner_id,notnull"`
+	TriggeredBy        int32                 `sql:"triggered_by"`
+	Status             DeploymentQueueStatus `sql:"status,notnull"`
+	QueuedOn           time.Time             `sql:"queued_on,notnull"`
+	ProcessedOn        time.Time             `sql:"processed_on"`
+	sql.AuditLog
+}
+
+type DeploymentQueueRepository interface {
+	Save(queue *DeploymentQueue) error
+	Update(queue *DeploymentQueue) error
+	FindAllByStatus(status DeploymentQueueStatus) ([]*DeploymentQueue, error)
+	UpdateStatusByPipelineIdAndStatus(pipelineId int, currentStatus DeploymentQueueStatus, newStatus DeploymentQueueStatus, userId int32) error
+}
+
+type DeploymentQueueRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewDeploymentQueueRepositoryImpl(dbConnection *pg.DB) *DeploymentQueueRepositoryImpl {
+	return &DeploymentQueueRepositoryImpl{dbConnection: dbConnection}
+}
+
+func (impl DeploymentQueueRepositoryImpl) Save(queue *DeploymentQueue) error {
+	return impl.dbConnection.Insert(queue)
+}
+
+func (impl DeploymentQueueRepositoryImpl) Update(queue *DeploymentQueue) error {
+	return impl.dbConnection.Update(queue)
+}
+
+func (impl DeploymentQueueRepositoryImpl) FindAllByStatus(status DeploymentQueueStatus) ([]*DeploymentQueue, error) {
+	var queues []*DeploymentQueue
+	err := impl.dbConnection.Model(&queues).
+		Where("status = ?", status).
+		Order("id ASC").
+		Select()
+	return queues, err
+}
+
+func (impl DeploymentQueueRepositoryImpl) UpdateStatusByPipelineIdAndStatus(pipelineId int, currentStatus DeploymentQueueStatus, newStatus DeploymentQueueStatus, userId int32) error {
+	_, err := impl.dbConnection.Model((*DeploymentQueue)(nil)).
+		Set("status = ?", newStatus).
+		Set("processed_on = ?", time.Now()).
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", userId).
+		Where("pipeline_id = ?", pipelineId).
+		Where("status = ?", currentStatus).
+		Update()
+	return err
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package deploymentWindow
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"time"
+)
+
+type DeploymentWindowType string
+
+const (
+	DEPLOYMENT_WINDOW_TYPE_ALLOWED  DeploymentWindowType = "ALLOWED"
+	DEPLOYMENT_WINDOW_TYPE_BLACKOUT DeploymentWindowType = "BLACKOUT"
+)
+
+type DeploymentWindow struct {
+	tableName     struct{}             `sql:"deployment_window" pg:",discard_unknown_columns"`
+	Id            int                  `sql:"id,pk"`
+	EnvironmentId int                  `sql:"environment_id,notnull"`
+	Name          string               `sql:"name,notnull"`
+	Type          DeploymentWindowType `sql:"type,notnull"`
+	Timezone      string               `sql:"timezone"`
+	WeekDays      []string             `sql:"week_days" pg:",array"`
+	StartTime     string               `sql:"start_time"` // HH:MM, used by ALLOWED windows
+	EndTime       string               `sql:"end_time"`   // HH:MM, used by ALLOWED windows
+	StartDate     time.Time            `sql:"start_date"` // used by BLACKOUT periods
+	EndDate       time.Time            `sql:"end_date"`   // used by BLACKOUT periods
+	Active        bool                 `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type DeploymentWindowRepository interface {
+	Save(window *DeploymentWindow) error
+	Update(window *DeploymentWindow) error
+	FindById(id int) (*DeploymentWindow, error)
+	FindAllActiveByEnvironmentId(envId int) ([]*DeploymentWindow, error)
+}
+
+type DeploymentWindowRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewDeploymentWindowRepositoryImpl(dbConnection *pg.DB) *DeploymentWindowRepositoryImpl {
+	return &DeploymentWindowRepositoryImpl{dbConnection: dbConnection}
+}
+
+func (impl DeploymentWindowRepositoryImpl) Save(window *DeploymentWindow) error {
+	return impl.dbConnection.Insert(window)
+}
+
+func (impl DeploymentWindowRepositoryImpl) Update(window *DeploymentWindow) error {
+	return impl.dbConnection.Update(window)
+}
+
+func (impl DeploymentWindowRepositoryImpl) FindById(id int) (*DeploymentWindow, error) {
+	window := &DeploymentWindow{}
+	err := impl.dbConnection.Model(window).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return window, err
+}
+
+func (impl DeploymentWindowRepositoryImpl) FindAllActiveByEnvironmentId(envId int) ([]*DeploymentWindow, error) {
+	var windows []*DeploymentWindow
+	err := impl.dbConnection.Model(&windows).
+		Where("environment_id = ?", envId).
+		Where("active = ?", true).
+		Order("id ASC").
+		Select()
+	return windows, err
+}
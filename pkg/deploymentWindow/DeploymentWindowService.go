@@ -0,0 +1,367 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package deploymentWindow
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type DeploymentWindowService interface {
+	Create(request *DeploymentWindowDto, userId int32) (*DeploymentWindowDto, error)
+	Update(request *DeploymentWindowDto, userId int32) (*DeploymentWindowDto, error)
+	Delete(id int, userId int32) error
+	GetById(id int) (*DeploymentWindowDto, error)
+	GetByEnvironmentId(envId int) ([]*DeploymentWindowDto, error)
+	EvaluateDeploymentWindow(envId int, evaluationTime time.Time) (*DeploymentWindowEvaluation, error)
+
+	QueueDeployment(queue *DeploymentQueue) error
+	GetAllQueuedDeployments() ([]*DeploymentQueue, error)
+	UpdateQueuedDeployment(queue *DeploymentQueue) error
+}
+
+type DeploymentWindowServiceImpl struct {
+	logger                     *zap.SugaredLogger
+	deploymentWindowRepository DeploymentWindowRepository
+	deploymentQueueRepository  DeploymentQueueRepository
+}
+
+type DeploymentWindowDto struct {
+	Id            int                  `json:"id"`
+	EnvironmentId int                  `json:"environmentId" validate:"number,gt=0"`
+	Name          string               `json:"name" validate:"required"`
+	Type          DeploymentWindowType `json:"type" validate:"oneof=ALLOWED BLACKOUT"`
+	Timezone      string               `json:"timezone,omitempty"`
+	WeekDays      []string             `json:"weekDays,omitempty"`
+	StartTime     string               `json:"startTime,omitempty"`
+	EndTime       string               `json:"endTime,omitempty"`
+	StartDate     time.Time            `json:"startDate,omitempty"`
+	EndDate       time.Time            `json:"endDate,omitempty"`
+}
+
+// DeploymentWindowEvaluation is the outcome of checking all windows of an environment at a given time
+type DeploymentWindowEvaluation struct {
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+func NewDeploymentWindowServiceImpl(logger *zap.SugaredLogger, deploymentWindowRepository DeploymentWindowRepository,
+	deploymentQueueRepository DeploymentQueueRepository) *DeploymentWindowServiceImpl {
+	return &DeploymentWindowServiceImpl{
+		logger:                     logger,
+		deploymentWindowRepository: deploymentWindowRepository,
+		deploymentQueueRepository:  deploymentQueueRepository,
+	}
+}
+
+func (impl DeploymentWindowServiceImpl) Create(request *DeploymentWindowDto, userId int32) (*DeploymentWindowDto, error) {
+	err := validateDeploymentWindow(request)
+	if err != nil {
+		impl.logger.Errorw("invalid deployment window request", "err", err, "request", request)
+		return nil, err
+	}
+	window := &DeploymentWindow{
+		Active:   true,
+		AuditLog: sql.AuditLog{CreatedOn: time.Now(), CreatedBy: userId, UpdatedOn: time.Now(), UpdatedBy: userId},
+	}
+	copyDtoToDeploymentWindow(request, window)
+	err = impl.deploymentWindowRepository.Save(window)
+	if err != nil {
+		impl.logger.Errorw("error in saving deployment window", "err", err, "window", window)
+		return nil, err
+	}
+	request.Id = window.Id
+	return request, nil
+}
+
+func (impl DeploymentWindowServiceImpl) Update(request *DeploymentWindowDto, userId int32) (*DeploymentWindowDto, error) {
+	err := validateDeploymentWindow(request)
+	if err != nil {
+		impl.logger.Errorw("invalid deployment window request", "err", err, "request", request)
+		return nil, err
+	}
+	window, err := impl.deploymentWindowRepository.FindById(request.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment window", "err", err, "id", request.Id)
+		return nil, err
+	}
+	copyDtoToDeploymentWindow(request, window)
+	window.UpdatedOn = time.Now()
+	window.UpdatedBy = userId
+	err = impl.deploymentWindowRepository.Update(window)
+	if err != nil {
+		impl.logger.Errorw("error in updating deployment window", "err", err, "window", window)
+		return nil, err
+	}
+	return request, nil
+}
+
+func (impl DeploymentWindowServiceImpl) Delete(id int, userId int32) error {
+	window, err := impl.deploymentWindowRepository.FindById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment window", "err", err, "id", id)
+		return err
+	}
+	window.Active = false
+	window.UpdatedOn = time.Now()
+	window.UpdatedBy = userId
+	err = impl.deploymentWindowRepository.Update(window)
+	if err != nil {
+		impl.logger.Errorw("error in deleting deployment window", "err", err, "id", id)
+		return err
+	}
+	return nil
+}
+
+func (impl DeploymentWindowServiceImpl) GetById(id int) (*DeploymentWindowDto, error) {
+	window, err := impl.deploymentWindowRepository.FindById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment window", "err", err, "id", id)
+		return nil, err
+	}
+	return buildDeploymentWindowDto(window), nil
+}
+
+func (impl DeploymentWindowServiceImpl) GetByEnvironmentId(envId int) ([]*DeploymentWindowDto, error) {
+	windows, err := impl.deploymentWindowRepository.FindAllActiveByEnvironmentId(envId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching deployment windows", "err", err, "envId", envId)
+		return nil, err
+	}
+	dtos := make([]*DeploymentWindowDto, 0, len(windows))
+	for _, window := range windows {
+		dtos = append(dtos, buildDeploymentWindowDto(window))
+	}
+	return dtos, nil
+}
+
+// EvaluateDeploymentWindow checks if a deployment is allowed on an environment at evaluationTime. A deployment is blocked
+// if any blackout period is active or, when allowed windows are configured, if none of them is open.
+func (impl DeploymentWindowServiceImpl) EvaluateDeploymentWindow(envId int, evaluationTime time.Time) (*DeploymentWindowEvaluation, error) {
+	windows, err := impl.deploymentWindowRepository.FindAllActiveByEnvironmentId(envId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching deployment windows", "err", err, "envId", envId)
+		return nil, err
+	}
+	return EvaluateWindows(windows, evaluationTime)
+}
+
+func (impl DeploymentWindowServiceImpl) QueueDeployment(queue *DeploymentQueue) error {
+	//only latest queued deployment of a pipeline is kept, older ones are superseded
+	err := impl.deploymentQueueRepository.UpdateStatusByPipelineIdAndStatus(queue.PipelineId, DEPLOYMENT_QUEUE_STATUS_QUEUED, DEPLOYMENT_QUEUE_STATUS_SUPERSEDED, queue.CreatedBy)
+	if err != nil {
+		impl.logger.Errorw("error in superseding older queued deployments", "err", err, "pipelineId", queue.PipelineId)
+		return err
+	}
+	queue.Status = DEPLOYMENT_QUEUE_STATUS_QUEUED
+	err = impl.deploymentQueueRepository.Save(queue)
+	if err != nil {
+		impl.logger.Errorw("error in saving queued deployment", "err", err, "queue", queue)
+		return err
+	}
+	return nil
+}
+
+func (impl DeploymentWindowServiceImpl) GetAllQueuedDeployments() ([]*DeploymentQueue, error) {
+	queues, err := impl.deploymentQueueRepository.FindAllByStatus(DEPLOYMENT_QUEUE_STATUS_QUEUED)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching queued deployments", "err", err)
+		return nil, err
+	}
+	return queues, nil
+}
+
+func (impl DeploymentWindowServiceImpl) UpdateQueuedDeployment(queue *DeploymentQueue) error {
+	err := impl.deploymentQueueRepository.Update(queue)
+	if err != nil {
+		impl.logger.Errorw("error in updating queued deployment", "err", err, "queue", queue)
+		return err
+	}
+	return nil
+}
+
+// EvaluateWindows applies blackout periods first and then allowed windows, an environment without allowed windows
+// is open at all times except during blackout periods
+func EvaluateWindows(windows []*DeploymentWindow, evaluationTime time.Time) (*DeploymentWindowEvaluation, error) {
+	var allowedWindowNames []string
+	for _, window := range windows {
+		if window.Type == DEPLOYMENT_WINDOW_TYPE_BLACKOUT {
+			if !evaluationTime.Before(window.StartDate) && evaluationTime.Before(window.EndDate) {
+				return &DeploymentWindowEvaluation{
+					Allowed: false,
+					Reason:  fmt.Sprintf("blackout period '%s' is active till %s", window.Name, window.EndDate.Format(time.RFC3339)),
+				}, nil
+			}
+		}
+	}
+	for _, window := range windows {
+		if window.Type == DEPLOYMENT_WINDOW_TYPE_ALLOWED {
+			isOpen, err := IsWindowOpen(window, evaluationTime)
+			if err != nil {
+				return nil, err
+			}
+			if isOpen {
+				return &DeploymentWindowEvaluation{Allowed: true}, nil
+			}
+			allowedWindowNames = append(allowedWindowNames, window.Name)
+		}
+	}
+	if len(allowedWindowNames) > 0 {
+		return &DeploymentWindowEvaluation{
+			Allowed: false,
+			Reason:  fmt.Sprintf("outside deployment window(s): %s", strings.Join(allowedWindowNames, ", ")),
+		}, nil
+	}
+	return &DeploymentWindowEvaluation{Allowed: true}, nil
+}
+
+// IsWindowOpen checks a recurring window in its own timezone, windows with end time before start time run past midnight
+// and are matched against the week day on which they start, so a Sunday window from 22:00 to 02:00 is open till
+// Monday 02:00 even if Monday is not one of its week days
+func IsWindowOpen(window *DeploymentWindow, evaluationTime time.Time) (bool, error) {
+	location, err := time.LoadLocation(window.Timezone)
+	if err != nil {
+		return false, err
+	}
+	startMinutes, err := parseClockMinutes(window.StartTime)
+	if err != nil {
+		return false, err
+	}
+	endMinutes, err := parseClockMinutes(window.EndTime)
+	if err != nil {
+		return false, err
+	}
+	localTime := evaluationTime.In(location)
+	year, month, day := localTime.Date()
+	//occurrence containing evaluation time starts either on the same day or, when running past midnight, a day before
+	for _, dayOffset := range []int{0, -1} {
+		windowStart := time.Date(year, month, day+dayOffset, startMinutes/60, startMinutes%60, 0, 0, location)
+		windowEnd := time.Date(year, month, day+dayOffset, endMinutes/60, endMinutes%60, 0, 0, location)
+		if !windowEnd.After(windowStart) {
+			windowEnd = time.Date(year, month, day+dayOffset+1, endMinutes/60, endMinutes%60, 0, 0, location)
+		}
+		if isWeekDayIncluded(window.WeekDays, windowStart.Weekday()) && !localTime.Before(windowStart) && localTime.Before(windowEnd) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func isWeekDayIncluded(weekDays []string, weekDay time.Weekday) bool {
+	//no week days means every day
+	if len(weekDays) == 0 {
+		return true
+	}
+	for _, day := range weekDays {
+		if includedDay, ok := parseWeekDay(day); ok && includedDay == weekDay {
+			return true
+		}
+	}
+	return false
+}
+
+func parseClockMinutes(clock string) (int, error) {
+	parsed, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %s, expected HH:MM", clock)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+func validateDeploymentWindow(request *DeploymentWindowDto) error {
+	var validationErr error
+	if request.Type == DEPLOYMENT_WINDOW_TYPE_ALLOWED {
+		if len(request.Timezone) == 0 {
+			request.Timezone = "UTC"
+		}
+		if _, err := time.LoadLocation(request.Timezone); err != nil {
+			validationErr = fmt.Errorf("invalid timezone %s", request.Timezone)
+		} else if _, err = parseClockMinutes(request.StartTime); err != nil {
+			validationErr = err
+		} else if _, err = parseClockMinutes(request.EndTime); err != nil {
+			validationErr = err
+		} else if request.StartTime == request.EndTime {
+			validationErr = fmt.Errorf("start time and end time of deployment window cannot be same")
+		}
+		for i, day := range request.WeekDays {
+			weekDay, ok := parseWeekDay(day)
+			if !ok {
+				validationErr = fmt.Errorf("invalid week day %s", day)
+				break
+			}
+			request.WeekDays[i] = weekDay.String()
+		}
+	} else if request.Type == DEPLOYMENT_WINDOW_TYPE_BLACKOUT {
+		if request.StartDate.IsZero() || request.EndDate.IsZero() || !request.EndDate.After(request.StartDate) {
+			validationErr = fmt.Errorf("blackout period needs a start date before its end date")
+		}
+	} else {
+		validationErr = fmt.Errorf("invalid deployment window type %s", request.Type)
+	}
+	if validationErr != nil {
+		return &util.ApiError{
+			HttpStatusCode:  http.StatusBadRequest,
+			InternalMessage: validationErr.Error(),
+			UserMessage:     validationErr.Error(),
+		}
+	}
+	return nil
+}
+
+// parseWeekDay accepts full or three letter week day names in any case
+func parseWeekDay(day string) (time.Weekday, bool) {
+	for weekDay := time.Sunday; weekDay <= time.Saturday; weekDay++ {
+		if strings.EqualFold(day, weekDay.String()) || strings.EqualFold(day, weekDay.String()[:3]) {
+			return weekDay, true
+		}
+	}
+	return 0, false
+}
+
+func copyDtoToDeploymentWindow(dto *DeploymentWindowDto, window *DeploymentWindow) {
+	window.EnvironmentId = dto.EnvironmentId
+	window.Name = dto.Name
+	window.Type = dto.Type
+	window.Timezone = dto.Timezone
+	window.WeekDays = dto.WeekDays
+	window.StartTime = dto.StartTime
+	window.EndTime = dto.EndTime
+	window.StartDate = dto.StartDate
+	window.EndDate = dto.EndDate
+}
+
+func buildDeploymentWindowDto(window *DeploymentWindow) *DeploymentWindowDto {
+	return &DeploymentWindowDto{
+		Id:            window.Id,
+		EnvironmentId: window.EnvironmentId,
+		Name:          window.Name,
+		Type:          window.Type,
+		Timezone:      window.Timezone,
+		WeekDays:      window.WeekDays,
+		StartTime:     window.StartTime,
+		EndTime:       window.EndTime,
+		StartDate:     window.StartDate,
+		EndDate:       window.EndDate,
+	}
+}
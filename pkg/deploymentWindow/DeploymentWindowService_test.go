@@ -0,0 +1,94 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package deploymentWindow
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEvaluateWindows(t *testing.T) {
+	officeHours := &DeploymentWindow{
+		Name:      "office-hours",
+		Type:      DEPLOYMENT_WINDOW_TYPE_ALLOWED,
+		Timezone:  "Asia/Kolkata",
+		WeekDays:  []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday"},
+		StartTime: "09:00",
+		EndTime:   "17:00",
+	}
+	nightly := &DeploymentWindow{
+		Name:      "nightly",
+		Type:      DEPLOYMENT_WINDOW_TYPE_ALLOWED,
+		Timezone:  "UTC",
+		WeekDays:  []string{"Saturday"},
+		StartTime: "22:00",
+		EndTime:   "02:00",
+	}
+	sundayNight := &DeploymentWindow{
+		Name:      "sunday-night",
+		Type:      DEPLOYMENT_WINDOW_TYPE_ALLOWED,
+		Timezone:  "UTC",
+		WeekDays:  []string{"Sunday"},
+		StartTime: "22:00",
+		EndTime:   "02:00",
+	}
+	freeze := &DeploymentWindow{
+		Name:      "release-freeze",
+		Type:      DEPLOYMENT_WINDOW_TYPE_BLACKOUT,
+		StartDate: time.Date(2022, 8, 10, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2022, 8, 12, 0, 0, 0, 0, time.UTC),
+	}
+	tests := []struct {
+		name    string
+		windows []*DeploymentWindow
+		at      time.Time
+		want    bool
+	}{
+		{name: "no windows", windows: nil, at: time.Date(2022, 8, 13, 3, 0, 0, 0, time.UTC), want: true},
+		//Monday 10:30 IST
+		{name: "inside office hours", windows: []*DeploymentWindow{officeHours}, at: time.Date(2022, 8, 8, 5, 0, 0, 0, time.UTC), want: true},
+		//Monday 18:30 IST
+		{name: "after office hours", windows: []*DeploymentWindow{officeHours}, at: time.Date(2022, 8, 8, 13, 0, 0, 0, time.UTC), want: false},
+		//Sunday 10:30 IST
+		{name: "office hours on weekend", windows: []*DeploymentWindow{officeHours}, at: time.Date(2022, 8, 7, 5, 0, 0, 0, time.UTC), want: false},
+		{name: "overnight window before midnight", windows: []*DeploymentWindow{nightly}, at: time.Date(2022, 8, 13, 23, 0, 0, 0, time.UTC), want: true},
+		{name: "overnight window after midnight", windows: []*DeploymentWindow{nightly}, at: time.Date(2022, 8, 14, 1, 0, 0, 0, time.UTC), want: true},
+		{name: "overnight window closed", windows: []*DeploymentWindow{nightly}, at: time.Date(2022, 8, 14, 3, 0, 0, 0, time.UTC), want: false},
+		//Sunday 23:00 to Monday 01:00, only Sunday is a window week day
+		{name: "sunday to monday window on sunday", windows: []*DeploymentWindow{sundayNight}, at: time.Date(2022, 8, 14, 23, 0, 0, 0, time.UTC), want: true},
+		{name: "sunday to monday window on monday", windows: []*DeploymentWindow{sundayNight}, at: time.Date(2022, 8, 15, 1, 0, 0, 0, time.UTC), want: true},
+		{name: "sunday to monday window closed on monday night", windows: []*DeploymentWindow{sundayNight}, at: time.Date(2022, 8, 15, 23, 0, 0, 0, time.UTC), want: false},
+		{name: "sunday to monday window closed on sunday morning", windows: []*DeploymentWindow{sundayNight}, at: time.Date(2022, 8, 14, 1, 0, 0, 0, time.UTC), want: false},
+		{name: "any allowed window open", windows: []*DeploymentWindow{officeHours, nightly}, at: time.Date(2022, 8, 13, 23, 0, 0, 0, time.UTC), want: true},
+		//Wednesday 10:30 IST, inside office hours but frozen
+		{name: "blackout overrides open window", windows: []*DeploymentWindow{officeHours, freeze}, at: time.Date(2022, 8, 10, 5, 0, 0, 0, time.UTC), want: false},
+		{name: "blackout ended", windows: []*DeploymentWindow{freeze}, at: time.Date(2022, 8, 12, 0, 0, 0, 0, time.UTC), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EvaluateWindows(tt.windows, tt.at)
+			if err != nil {
+				t.Errorf("EvaluateWindows() error = %v", err)
+				return
+			}
+			if got.Allowed != tt.want {
+				t.Errorf("EvaluateWindows() = %v, want %v, reason %s", got.Allowed, tt.want, got.Reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package imageSignature
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/orm"
+)
+
+type SignatureFormat string
+
+const (
+	SIGNATURE_FORMAT_COSIGN   SignatureFormat = "COSIGN"
+	SIGNATURE_FORMAT_NOTATION SignatureFormat = "NOTATION"
+)
+
+// ImageSignaturePolicy trusts a public key for images deployed to an environment, or to every environment of a cluster.
+// PublicKey is a PEM public key for cosign and a PEM certificate for notation
+type ImageSignaturePolicy struct {
+	tableName     struct{}        `sql:"image_signature_policy" pg:",discard_unknown_columns"`
+	Id            int             `sql:"id,pk"`
+	Name          string          `sql:"name,notnull"`
+	ClusterId     int             `sql:"cluster_id"`
+	EnvironmentId int             `sql:"environment_id"`
+	Format        SignatureFormat `sql:"format,notnull"`
+	PublicKey     string          `sql:"public_key,notnull"`
+	Active        bool            `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type ImageSignaturePolicyRepository interface {
+	Save(policy *ImageSignaturePolicy) error
+	Update(policy *ImageSignaturePolicy) error
+	FindById(id int) (*ImageSignaturePolicy, error)
+	FindAllActiveByClusterIdOrEnvironmentId(clusterId int, envId int) ([]*ImageSignaturePolicy, error)
+}
+
+type ImageSignaturePolicyRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewImageSignaturePolicyRepositoryImpl(dbConnection *pg.DB) *ImageSignaturePolicyRepositoryImpl {
+	return &ImageSignaturePolicyRepositoryImpl{dbConnection: dbConnection}
+}
+
+func (impl ImageSignaturePolicyRepositoryImpl) Save(policy *ImageSignaturePolicy) error {
+	return impl.dbConnection.Insert(policy)
+}
+
+func (impl ImageSignaturePolicyRepositoryImpl) Update(policy *ImageSignaturePolicy) error {
+	return impl.dbConnection.Update(policy)
+}
+
+func (impl ImageSignaturePolicyRepositoryImpl) FindById(id int) (*ImageSignaturePolicy, error) {
+	policy := &ImageSignaturePolicy{}
+	err := impl.dbConnection.Model(policy).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return policy, err
+}
+
+func (impl ImageSignaturePolicyRepositoryImpl) FindAllActiveByClusterIdOrEnvironmentId(clusterId int, envId int) ([]*ImageSignaturePolicy, error) {
+	var policies []*ImageSignaturePolicy
+	err := impl.dbConnection.Model(&policies).
+		WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+			return q.WhereOr("cluster_id = ?", clusterId).WhereOr("environment_id = ?", envId), nil
+		}).
+		Where("active = ?", true).
+		Order("id ASC").
+		Select()
+	return policies, err
+}
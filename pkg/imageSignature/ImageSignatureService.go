@@ -0,0 +1,314 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package imageSignature
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"github.com/juju/errors"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type ImageSignatureService interface {
+	Create(request *ImageSignaturePolicyDto, userId int32) (*ImageSignaturePolicyDto, error)
+	Update(request *ImageSignaturePolicyDto, userId int32) (*ImageSignaturePolicyDto, error)
+	Delete(id int, userId int32) error
+	GetById(id int) (*ImageSignaturePolicyDto, error)
+	GetPolicies(clusterId int, envId int) ([]*ImageSignaturePolicyDto, error)
+	VerifyImageSignature(image string, imageDigest string, appId int, clusterId int, envId int) (*ImageSignatureEvaluation, error)
+}
+
+type ImageSignatureServiceImpl struct {
+	logger                         *zap.SugaredLogger
+	imageSignaturePolicyRepository ImageSignaturePolicyRepository
+	imageSignatureVerifier         ImageSignatureVerifier
+	ciTemplateRepository           pipelineConfig.CiTemplateRepository
+}
+
+type ImageSignaturePolicyDto struct {
+	Id            int             `json:"id"`
+	Name          string          `json:"name" validate:"required"`
+	ClusterId     int             `json:"clusterId,omitempty"`
+	EnvironmentId int             `json:"environmentId,omitempty"`
+	Format        SignatureFormat `json:"format" validate:"oneof=COSIGN NOTATION"`
+	PublicKey     string          `json:"publicKey" validate:"required"`
+}
+
+// ImageSignatureEvaluation is the outcome of verifying an image against trusted keys of the deployment environment
+type ImageSignatureEvaluation struct {
+	Verified bool   `json:"verified"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+func NewImageSignatureServiceImpl(logger *zap.SugaredLogger, imageSignaturePolicyRepository ImageSignaturePolicyRepository,
+	imageSignatureVerifier ImageSignatureVerifier, ciTemplateRepository pipelineConfig.CiTemplateRepository) *ImageSignatureServiceImpl {
+	return &ImageSignatureServiceImpl{
+		logger:                         logger,
+		imageSignaturePolicyRepository: imageSignaturePolicyRepository,
+		imageSignatureVerifier:         imageSignatureVerifier,
+		ciTemplateRepository:           ciTemplateRepository,
+	}
+}
+
+func (impl ImageSignatureServiceImpl) Create(request *ImageSignaturePolicyDto, userId int32) (*ImageSignaturePolicyDto, error) {
+	err := validateImageSignaturePolicy(request)
+	if err != nil {
+		impl.logger.Errorw("invalid image signature policy request", "err", err, "name", request.Name)
+		return nil, err
+	}
+	policy := &ImageSignaturePolicy{
+		Active:   true,
+		AuditLog: sql.AuditLog{CreatedOn: time.Now(), CreatedBy: userId, UpdatedOn: time.Now(), UpdatedBy: userId},
+	}
+	copyDtoToImageSignaturePolicy(request, policy)
+	err = impl.imageSignaturePolicyRepository.Save(policy)
+	if err != nil {
+		impl.logger.Errorw("error in saving image signature policy", "err", err, "name", policy.Name)
+		return nil, err
+	}
+	request.Id = policy.Id
+	return request, nil
+}
+
+func (impl ImageSignatureServiceImpl) Update(request *ImageSignaturePolicyDto, userId int32) (*ImageSignaturePolicyDto, error) {
+	err := validateImageSignaturePolicy(request)
+	if err != nil {
+		impl.logger.Errorw("invalid image signature policy request", "err", err, "name", request.Name)
+		return nil, err
+	}
+	policy, err := impl.imageSignaturePolicyRepository.FindById(request.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching image signature policy", "err", err, "id", request.Id)
+		return nil, err
+	}
+	copyDtoToImageSignaturePolicy(request, policy)
+	policy.UpdatedOn = time.Now()
+	policy.UpdatedBy = userId
+	err = impl.imageSignaturePolicyRepository.Update(policy)
+	if err != nil {
+		impl.logger.Errorw("error in updating image signature policy", "err", err, "id", policy.Id)
+		return nil, err
+	}
+	return request, nil
+}
+
+func (impl ImageSignatureServiceImpl) Delete(id int, userId int32) error {
+	policy, err := impl.imageSignaturePolicyRepository.FindById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching image signature policy", "err", err, "id", id)
+		return err
+	}
+	policy.Active = false
+	policy.UpdatedOn = time.Now()
+	policy.UpdatedBy = userId
+	err = impl.imageSignaturePolicyRepository.Update(policy)
+	if err != nil {
+		impl.logger.Errorw("error in deleting image signature policy", "err", err, "id", id)
+		return err
+	}
+	return nil
+}
+
+func (impl ImageSignatureServiceImpl) GetById(id int) (*ImageSignaturePolicyDto, error) {
+	policy, err := impl.imageSignaturePolicyRepository.FindById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching image signature policy", "err", err, "id", id)
+		return nil, err
+	}
+	return buildImageSignaturePolicyDto(policy), nil
+}
+
+// GetPolicies returns policies configured directly on the cluster or the environment
+func (impl ImageSignatureServiceImpl) GetPolicies(clusterId int, envId int) ([]*ImageSignaturePolicyDto, error) {
+	policies, err := impl.imageSignaturePolicyRepository.FindAllActiveByClusterIdOrEnvironmentId(clusterId, envId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching image signature policies", "err", err, "clusterId", clusterId, "envId", envId)
+		return nil, err
+	}
+	dtos := make([]*ImageSignaturePolicyDto, 0, len(policies))
+	for _, policy := range policies {
+		dtos = append(dtos, buildImageSignaturePolicyDto(policy))
+	}
+	return dtos, nil
+}
+
+// VerifyImageSignature passes images deployed where no policy applies, otherwise the image has to be signed by any of
+// the trusted keys. Failure to run verification is treated as unverified image
+func (impl ImageSignatureServiceImpl) VerifyImageSignature(image string, imageDigest string, appId int, clusterId int, envId int) (*ImageSignatureEvaluation, error) {
+	policies, err := impl.imageSignaturePolicyRepository.FindAllActiveByClusterIdOrEnvironmentId(clusterId, envId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching image signature policies", "err", err, "clusterId", clusterId, "envId", envId)
+		return nil, err
+	}
+	policies = GetApplicablePolicies(policies, envId)
+	if len(policies) == 0 {
+		return &ImageSignatureEvaluation{Verified: true}, nil
+	}
+	imageReference := GetImageReference(image, imageDigest)
+	credential, err := impl.getRegistryCredential(image, appId)
+	if err != nil {
+		impl.logger.Errorw("error in getting registry credential for image signature verification", "err", err, "image", image, "appId", appId)
+		return nil, err
+	}
+	var failures []string
+	for _, policy := range policies {
+		err = impl.imageSignatureVerifier.Verify(imageReference, policy.Format, policy.PublicKey, credential)
+		if err == nil {
+			impl.logger.Infow("image signature verified", "image", imageReference, "policy", policy.Name)
+			return &ImageSignatureEvaluation{Verified: true}, nil
+		}
+		failures = append(failures, fmt.Sprintf("%s: %s", policy.Name, err.Error()))
+	}
+	return &ImageSignatureEvaluation{
+		Verified: false,
+		Reason:   fmt.Sprintf("image %s is not signed by any trusted key (%s)", imageReference, strings.Join(failures, "; ")),
+	}, nil
+}
+
+// getRegistryCredential returns login of the container registry of the app when the image is pushed to it, images of
+// other registries are verified anonymously
+func (impl ImageSignatureServiceImpl) getRegistryCredential(image string, appId int) (*RegistryCredential, error) {
+	ciTemplate, err := impl.ciTemplateRepository.FindByAppId(appId)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	if ciTemplate == nil || ciTemplate.DockerRegistry == nil {
+		return nil, nil
+	}
+	store := ciTemplate.DockerRegistry
+	host := GetRegistryHost(store.RegistryURL)
+	if len(host) == 0 || !strings.HasPrefix(image, host+"/") {
+		return nil, nil
+	}
+	credential := &RegistryCredential{Host: host, Username: store.Username, Password: store.Password}
+	if store.RegistryType == repository.REGISTRYTYPE_ECR {
+		credential.Username, credential.Password, err = util.GetEcrLoginCredentials(store.AWSRegion, store.AWSAccessKeyId, store.AWSSecretAccessKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return credential, nil
+}
+
+// GetRegistryHost strips scheme and trailing slash of a registry url
+func GetRegistryHost(registryUrl string) string {
+	host := strings.TrimPrefix(strings.TrimPrefix(registryUrl, "https://"), "http://")
+	return strings.TrimSuffix(host, "/")
+}
+
+// GetApplicablePolicies keeps policies of the environment, policies of the cluster apply only to environments
+// without policies of their own
+func GetApplicablePolicies(policies []*ImageSignaturePolicy, envId int) []*ImageSignaturePolicy {
+	var envPolicies, clusterPolicies []*ImageSignaturePolicy
+	for _, policy := range policies {
+		if policy.EnvironmentId > 0 {
+			if policy.EnvironmentId == envId {
+				envPolicies = append(envPolicies, policy)
+			}
+		} else if policy.ClusterId > 0 {
+			clusterPolicies = append(clusterPolicies, policy)
+		}
+	}
+	if len(envPolicies) > 0 {
+		return envPolicies
+	}
+	return clusterPolicies
+}
+
+// GetImageReference pins the image to its digest so that the verified image is the one being deployed
+func GetImageReference(image string, imageDigest string) string {
+	if len(imageDigest) == 0 || strings.Contains(image, "@") {
+		return image
+	}
+	repository := image
+	if index := strings.LastIndex(image, ":"); index > strings.LastIndex(image, "/") {
+		repository = image[:index]
+	}
+	return repository + "@" + imageDigest
+}
+
+func validateImageSignaturePolicy(request *ImageSignaturePolicyDto) error {
+	var validationErr error
+	if (request.ClusterId > 0) == (request.EnvironmentId > 0) {
+		validationErr = fmt.Errorf("image signature policy applies to either a cluster or an environment")
+	} else if block, _ := pem.Decode([]byte(strings.TrimSpace(request.PublicKey))); block == nil {
+		validationErr = fmt.Errorf("public key must be PEM encoded")
+	} else if request.Format != SIGNATURE_FORMAT_COSIGN && request.Format != SIGNATURE_FORMAT_NOTATION {
+		validationErr = fmt.Errorf("invalid signature format %s", request.Format)
+	} else {
+		validationErr = validateTrustedKey(request.Format, block)
+	}
+	if validationErr != nil {
+		return &util.ApiError{
+			HttpStatusCode:  http.StatusBadRequest,
+			InternalMessage: validationErr.Error(),
+			UserMessage:     validationErr.Error(),
+		}
+	}
+	return nil
+}
+
+// validateTrustedKey checks the key can be used by the signature tool, cosign verifies with a public key while notation
+// trust store only accepts X.509 certificates
+func validateTrustedKey(format SignatureFormat, block *pem.Block) error {
+	switch format {
+	case SIGNATURE_FORMAT_COSIGN:
+		if block.Type != "PUBLIC KEY" {
+			return fmt.Errorf("cosign policy needs a PEM public key")
+		}
+		if _, err := x509.ParsePKIXPublicKey(block.Bytes); err != nil {
+			return fmt.Errorf("invalid cosign public key: %s", err.Error())
+		}
+	case SIGNATURE_FORMAT_NOTATION:
+		if block.Type != "CERTIFICATE" {
+			return fmt.Errorf("notation policy needs a PEM X.509 certificate")
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return fmt.Errorf("invalid notation X.509 certificate: %s", err.Error())
+		}
+	}
+	return nil
+}
+
+func copyDtoToImageSignaturePolicy(dto *ImageSignaturePolicyDto, policy *ImageSignaturePolicy) {
+	policy.Name = dto.Name
+	policy.ClusterId = dto.ClusterId
+	policy.EnvironmentId = dto.EnvironmentId
+	policy.Format = dto.Format
+	policy.PublicKey = strings.TrimSpace(dto.PublicKey)
+}
+
+func buildImageSignaturePolicyDto(policy *ImageSignaturePolicy) *ImageSignaturePolicyDto {
+	return &ImageSignaturePolicyDto{
+		Id:            policy.Id,
+		Name:          policy.Name,
+		ClusterId:     policy.ClusterId,
+		EnvironmentId: policy.EnvironmentId,
+		Format:        policy.Format,
+		PublicKey:     policy.PublicKey,
+	}
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package imageSignature
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetImageReference(t *testing.T) {
+	digest := "sha256:0f1e2d"
+	tests := []struct {
+		name        string
+		image       string
+		imageDigest string
+		want        string
+	}{
+		{name: "tagged image", image: "docker.io/devtron/app:v1", imageDigest: digest, want: "docker.io/devtron/app@sha256:0f1e2d"},
+		{name: "registry with port", image: "registry.local:5000/devtron/app:v1", imageDigest: digest, want: "registry.local:5000/devtron/app@sha256:0f1e2d"},
+		{name: "untagged image with port", image: "registry.local:5000/devtron/app", imageDigest: digest, want: "registry.local:5000/devtron/app@sha256:0f1e2d"},
+		{name: "already pinned", image: "devtron/app@sha256:abc", imageDigest: digest, want: "devtron/app@sha256:abc"},
+		{name: "digest unknown", image: "devtron/app:v1", want: "devtron/app:v1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetImageReference(tt.image, tt.imageDigest); got != tt.want {
+				t.Errorf("GetImageReference() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetApplicablePolicies(t *testing.T) {
+	clusterPolicy := &ImageSignaturePolicy{Id: 1, ClusterId: 1}
+	envPolicy := &ImageSignaturePolicy{Id: 2, EnvironmentId: 2}
+	otherEnvPolicy := &ImageSignaturePolicy{Id: 3, EnvironmentId: 3}
+	tests := []struct {
+		name     string
+		policies []*ImageSignaturePolicy
+		envId    int
+		want     []*ImageSignaturePolicy
+	}{
+		{name: "environment overrides cluster", policies: []*ImageSignaturePolicy{clusterPolicy, envPolicy}, envId: 2, want: []*ImageSignaturePolicy{envPolicy}},
+		{name: "cluster inherited", policies: []*ImageSignaturePolicy{clusterPolicy, otherEnvPolicy}, envId: 2, want: []*ImageSignaturePolicy{clusterPolicy}},
+		{name: "no policies", envId: 2, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetApplicablePolicies(tt.policies, tt.envId); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetApplicablePolicies() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetRegistryHost(t *testing.T) {
+	tests := []struct {
+		registryUrl string
+		want        string
+	}{
+		{registryUrl: "https://registry.local:5000/", want: "registry.local:5000"},
+		{registryUrl: "http://registry.local", want: "registry.local"},
+		{registryUrl: "docker.io", want: "docker.io"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.registryUrl, func(t *testing.T) {
+			if got := GetRegistryHost(tt.registryUrl); got != tt.want {
+				t.Errorf("GetRegistryHost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
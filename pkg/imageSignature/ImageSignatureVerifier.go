@@ -0,0 +1,165 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package imageSignature
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/caarlos0/env"
+	"go.uber.org/zap"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type ImageSignatureConfig struct {
+	CosignPath           string `env:"IMAGE_SIGNATURE_COSIGN_PATH" envDefault:"cosign"`
+	NotationPath         string `env:"IMAGE_SIGNATURE_NOTATION_PATH" envDefault:"notation"`
+	VerifyTimeoutSeconds int    `env:"IMAGE_SIGNATURE_VERIFY_TIMEOUT_SECS" envDefault:"60"`
+}
+
+func GetImageSignatureConfig() (*ImageSignatureConfig, error) {
+	cfg := &ImageSignatureConfig{}
+	err := env.Parse(cfg)
+	if err != nil {
+		fmt.Println("failed to parse image signature config: " + err.Error())
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// notation only trusts certificates of a trust store named in its trust policy
+const notationTrustPolicy = `{"version":"1.0","trustPolicies":[{"name":"devtron","registryScopes":["*"],"signatureVerification":{"level":"strict"},"trustStores":["ca:devtron"],"trustedIdentities":["*"]}]}`
+
+// RegistryCredential is the login of the registry an image is pulled from, verification runs anonymously without it
+type RegistryCredential struct {
+	Host     string
+	Username string
+	Password string
+}
+
+// ImageSignatureVerifier checks the signature of an image reference against a trusted key using cosign or notation cli,
+// the cli runs with an isolated docker config holding only the given registry credential
+type ImageSignatureVerifier interface {
+	Verify(imageReference string, format SignatureFormat, publicKey string, credential *RegistryCredential) error
+}
+
+type ImageSignatureVerifierImpl struct {
+	logger *zap.SugaredLogger
+	config *ImageSignatureConfig
+}
+
+func NewImageSignatureVerifierImpl(logger *zap.SugaredLogger, config *ImageSignatureConfig) *ImageSignatureVerifierImpl {
+	return &ImageSignatureVerifierImpl{
+		logger: logger,
+		config: config,
+	}
+}
+
+func (impl ImageSignatureVerifierImpl) Verify(imageReference string, format SignatureFormat, publicKey string, credential *RegistryCredential) error {
+	workDir, err := ioutil.TempDir("", "image-signature-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(workDir)
+	dockerConfigDir := filepath.Join(workDir, "docker")
+	err = os.MkdirAll(dockerConfigDir, 0700)
+	if err != nil {
+		return err
+	}
+	dockerConfig, err := BuildDockerConfig(credential)
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(filepath.Join(dockerConfigDir, "config.json"), dockerConfig, 0600)
+	if err != nil {
+		return err
+	}
+	cmdEnv := append(os.Environ(), fmt.Sprintf("DOCKER_CONFIG=%s", dockerConfigDir))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(impl.config.VerifyTimeoutSeconds)*time.Second)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	switch format {
+	case SIGNATURE_FORMAT_COSIGN:
+		keyPath := filepath.Join(workDir, "cosign.pub")
+		err = ioutil.WriteFile(keyPath, []byte(publicKey), 0600)
+		if err != nil {
+			return err
+		}
+		cmd = exec.CommandContext(ctx, impl.config.CosignPath, "verify", "--key", keyPath, imageReference)
+		cmd.Env = cmdEnv
+	case SIGNATURE_FORMAT_NOTATION:
+		//isolated notation config having the key as only trusted certificate
+		notationDir := filepath.Join(workDir, "notation")
+		trustStoreDir := filepath.Join(notationDir, "truststore", "x509", "ca", "devtron")
+		err = os.MkdirAll(trustStoreDir, 0700)
+		if err != nil {
+			return err
+		}
+		err = ioutil.WriteFile(filepath.Join(trustStoreDir, "devtron.crt"), []byte(publicKey), 0600)
+		if err != nil {
+			return err
+		}
+		err = ioutil.WriteFile(filepath.Join(notationDir, "trustpolicy.json"), []byte(notationTrustPolicy), 0600)
+		if err != nil {
+			return err
+		}
+		cmd = exec.CommandContext(ctx, impl.config.NotationPath, "verify", imageReference)
+		cmd.Env = append(cmdEnv, fmt.Sprintf("XDG_CONFIG_HOME=%s", workDir))
+		if credential != nil {
+			cmd.Env = append(cmd.Env, fmt.Sprintf("NOTATION_USERNAME=%s", credential.Username), fmt.Sprintf("NOTATION_PASSWORD=%s", credential.Password))
+		}
+	default:
+		return fmt.Errorf("unsupported signature format %s", format)
+	}
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		impl.logger.Debugw("image signature verification failed", "image", imageReference, "format", format, "output", string(output), "err", err)
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("signature verification timed out")
+		}
+		if len(strings.TrimSpace(string(output))) > 0 {
+			return fmt.Errorf("%s", lastLine(string(output)))
+		}
+		return err
+	}
+	return nil
+}
+
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// BuildDockerConfig returns docker config json having auth of only the given registry, an empty config is used for
+// anonymous pulls so that credentials of the orchestrator are never picked
+func BuildDockerConfig(credential *RegistryCredential) ([]byte, error) {
+	auths := make(map[string]map[string]string)
+	if credential != nil && len(credential.Host) > 0 && len(credential.Username) > 0 {
+		auths[credential.Host] = map[string]string{
+			"auth": base64.StdEncoding.EncodeToString([]byte(credential.Username + ":" + credential.Password)),
+		}
+	}
+	return json.Marshal(map[string]interface{}{"auths": auths})
+}
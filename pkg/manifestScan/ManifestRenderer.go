@@ -0,0 +1,205 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package manifestScan
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"github.com/Masterminds/sprig/v3"
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// renderKubeVersion is reported to templates through .Capabilities, reference charts only use it to pick api versions
+var renderKubeVersion = &version.Info{Major: "1", Minor: "22", GitVersion: "v1.22.0"}
+
+type renderable struct {
+	tpl      string
+	vals     chartutil.Values
+	basePath string
+}
+
+// RenderChart renders the templates of a chart with the values it is deployed with the way helm template does, it is
+// used for charts which kubelink can not template like the reference charts of devtron apps. Rendered templates are
+// joined into a multi document yaml ordered by template name
+func RenderChart(chrt *chart.Chart, valuesYaml string, releaseName string, namespace string) (string, error) {
+	config := &chart.Config{Raw: valuesYaml}
+	err := chartutil.ProcessRequirementsEnabled(chrt, config)
+	if err != nil {
+		return "", err
+	}
+	options := chartutil.ReleaseOptions{Name: releaseName, Namespace: namespace, Revision: 1, IsInstall: true}
+	caps := &chartutil.Capabilities{APIVersions: chartutil.DefaultVersionSet, KubeVersion: renderKubeVersion}
+	values, err := chartutil.ToRenderValuesCaps(chrt, config, options, caps)
+	if err != nil {
+		return "", err
+	}
+	templates := make(map[string]renderable)
+	collectTemplates(chrt, "", values, templates)
+
+	var names []string
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	t := template.New("gotpl").Option("missingkey=zero")
+	t.Funcs(renderFuncMap(t))
+	for _, name := range names {
+		if _, err = t.New(name).Parse(templates[name].tpl); err != nil {
+			return "", fmt.Errorf("parse error in %q: %s", name, err)
+		}
+	}
+
+	var manifests strings.Builder
+	for _, name := range names {
+		baseName := path.Base(name)
+		if strings.HasPrefix(baseName, "_") || baseName == "NOTES.txt" {
+			continue
+		}
+		r := templates[name]
+		vals := chartutil.Values{}
+		for key, value := range r.vals {
+			vals[key] = value
+		}
+		vals["Template"] = map[string]interface{}{"Name": name, "BasePath": r.basePath}
+		var buf bytes.Buffer
+		if err = t.ExecuteTemplate(&buf, name, vals); err != nil {
+			return "", fmt.Errorf("render error in %q: %s", name, err)
+		}
+		rendered := strings.TrimSpace(strings.Replace(buf.String(), "<no value>", "", -1))
+		if len(rendered) == 0 {
+			continue
+		}
+		manifests.WriteString(fmt.Sprintf("---\n# Source: %s\n%s\n", name, rendered))
+	}
+	return manifests.String(), nil
+}
+
+// collectTemplates gathers templates of a chart and its sub charts along with the values they are rendered with
+func collectTemplates(c *chart.Chart, parentId string, parentVals chartutil.Values, templates map[string]renderable) {
+	next := chartutil.Values{
+		"Chart":        c.Metadata,
+		"Files":        chartutil.NewFiles(c.Files),
+		"Release":      parentVals["Release"],
+		"Capabilities": parentVals["Capabilities"],
+		"Values":       chartutil.Values{},
+	}
+	chartId := c.Metadata.Name
+	if len(parentId) == 0 {
+		next["Values"] = parentVals["Values"]
+	} else {
+		chartId = parentId + "/charts/" + c.Metadata.Name
+		if parentValues, ok := parentVals["Values"].(chartutil.Values); ok {
+			if values, err := parentValues.Table(c.Metadata.Name); err == nil {
+				next["Values"] = values
+			}
+		}
+	}
+	for _, child := range c.Dependencies {
+		collectTemplates(child, chartId, next, templates)
+	}
+	for _, t := range c.Templates {
+		templates[path.Join(chartId, t.Name)] = renderable{
+			tpl:      string(t.Data),
+			vals:     next,
+			basePath: path.Join(chartId, "templates"),
+		}
+	}
+}
+
+// renderFuncMap is the sprig function map along with functions helm adds for templates
+func renderFuncMap(t *template.Template) template.FuncMap {
+	funcMap := sprig.TxtFuncMap()
+	delete(funcMap, "env")
+	delete(funcMap, "expandenv")
+
+	funcMap["toToml"] = func(v interface{}) string {
+		b := bytes.NewBuffer(nil)
+		if err := toml.NewEncoder(b).Encode(v); err != nil {
+			return err.Error()
+		}
+		return b.String()
+	}
+	funcMap["toYaml"] = func(v interface{}) string {
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSuffix(string(data), "\n")
+	}
+	funcMap["fromYaml"] = func(str string) map[string]interface{} {
+		m := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(str), &m); err != nil {
+			m["Error"] = err.Error()
+		}
+		return m
+	}
+	funcMap["toJson"] = func(v interface{}) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	}
+	funcMap["fromJson"] = func(str string) map[string]interface{} {
+		m := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(str), &m); err != nil {
+			m["Error"] = err.Error()
+		}
+		return m
+	}
+	funcMap["include"] = func(name string, data interface{}) (string, error) {
+		var buf bytes.Buffer
+		err := t.ExecuteTemplate(&buf, name, data)
+		return buf.String(), err
+	}
+	funcMap["tpl"] = func(tpl string, vals chartutil.Values) (string, error) {
+		clone, err := t.Clone()
+		if err != nil {
+			return "", err
+		}
+		_, err = clone.New("_tpl").Parse(tpl)
+		if err != nil {
+			return "", fmt.Errorf("error in parsing tpl %q: %s", tpl, err)
+		}
+		var buf bytes.Buffer
+		err = clone.ExecuteTemplate(&buf, "_tpl", vals)
+		if err != nil {
+			return "", fmt.Errorf("error in rendering tpl %q: %s", tpl, err)
+		}
+		return strings.Replace(buf.String(), "<no value>", "", -1), nil
+	}
+	funcMap["required"] = func(warn string, val interface{}) (interface{}, error) {
+		if val == nil {
+			return val, fmt.Errorf(warn)
+		} else if s, ok := val.(string); ok && len(s) == 0 {
+			return val, fmt.Errorf(warn)
+		}
+		return val, nil
+	}
+	return funcMap
+}
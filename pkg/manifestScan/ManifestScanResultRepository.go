@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package manifestScan
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+)
+
+// ManifestScanResult is a finding of the manifest scan run before deployment of a cd workflow runner
+type ManifestScanResult struct {
+	tableName          struct{} `sql:"manifest_scan_result" pg:",discard_unknown_columns"`
+	Id                 int      `sql:"id,pk"`
+	CdWorkflowRunnerId int      `sql:"cd_workflow_runner_id,notnull"`
+	AppId              int      `sql:"app_id,notnull"`
+	EnvironmentId      int      `sql:"env_id,notnull"`
+	RuleId             string   `sql:"rule_id,notnull"`
+	Severity           string   `sql:"severity,notnull"`
+	Kind               string   `sql:"kind,notnull"`
+	ResourceName       string   `sql:"resource_name,notnull"`
+	Container          string   `sql:"container"`
+	Message            string   `sql:"message,notnull"`
+	Action             string   `sql:"action,notnull"`
+	sql.AuditLog
+}
+
+type ManifestScanResultRepository interface {
+	SaveResults(cdWorkflowRunnerId int, results []*ManifestScanResult) error
+	FindByCdWorkflowRunnerId(cdWorkflowRunnerId int) ([]*ManifestScanResult, error)
+}
+
+type ManifestScanResultRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewManifestScanResultRepositoryImpl(dbConnection *pg.DB) *ManifestScanResultRepositoryImpl {
+	return &ManifestScanResultRepositoryImpl{dbConnection: dbConnection}
+}
+
+// SaveResults replaces findings recorded for a runner
+func (impl ManifestScanResultRepositoryImpl) SaveResults(cdWorkflowRunnerId int, results []*ManifestScanResult) error {
+	return impl.dbConnection.RunInTransaction(func(tx *pg.Tx) error {
+		_, err := tx.Model((*ManifestScanResult)(nil)).Where("cd_workflow_runner_id = ?", cdWorkflowRunnerId).Delete()
+		if err != nil {
+			return err
+		}
+		for _, result := range results {
+			err = tx.Insert(result)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (impl ManifestScanResultRepositoryImpl) FindByCdWorkflowRunnerId(cdWorkflowRunnerId int) ([]*ManifestScanResult, error) {
+	var results []*ManifestScanResult
+	err := impl.dbConnection.Model(&results).
+		Where("cd_workflow_runner_id = ?", cdWorkflowRunnerId).
+		Order("id ASC").
+		Select()
+	return results, err
+}
@@ -0,0 +1,338 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package manifestScan
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/sql/repository/security"
+	"github.com/ghodss/yaml"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const manifestScanMessageMaxLength = 256
+
+const (
+	RULE_PRIVILEGED_CONTAINER    = "PRIVILEGED_CONTAINER"
+	RULE_PRIVILEGE_ESCALATION    = "PRIVILEGE_ESCALATION"
+	RULE_DANGEROUS_CAPABILITIES  = "DANGEROUS_CAPABILITIES"
+	RULE_RUN_AS_ROOT             = "RUN_AS_ROOT"
+	RULE_HOST_NAMESPACE          = "HOST_NAMESPACE"
+	RULE_HOST_PATH_MOUNT         = "HOST_PATH_MOUNT"
+	RULE_MISSING_RESOURCE_LIMITS = "MISSING_RESOURCE_LIMITS"
+	RULE_LATEST_IMAGE_TAG        = "LATEST_IMAGE_TAG"
+	MANIFEST_SCAN_ACTION_WARN    = "warn"
+	MANIFEST_SCAN_ACTION_BLOCK   = "block"
+	manifestScanSeverityCritical = "critical"
+	manifestScanSeverityModerate = "moderate"
+	manifestScanSeverityLow      = "low"
+)
+
+// ManifestScanRule is a built-in check run on rendered manifests
+type ManifestScanRule struct {
+	Id          string `json:"id"`
+	Severity    string `json:"severity"`
+	Description string `json:"description"`
+}
+
+var ManifestScanRules = []*ManifestScanRule{
+	{Id: RULE_PRIVILEGED_CONTAINER, Severity: manifestScanSeverityCritical, Description: "container runs in privileged mode"},
+	{Id: RULE_PRIVILEGE_ESCALATION, Severity: manifestScanSeverityModerate, Description: "container allows privilege escalation"},
+	{Id: RULE_DANGEROUS_CAPABILITIES, Severity: manifestScanSeverityCritical, Description: "container adds capabilities like SYS_ADMIN or ALL"},
+	{Id: RULE_RUN_AS_ROOT, Severity: manifestScanSeverityModerate, Description: "container runs as root user"},
+	{Id: RULE_HOST_NAMESPACE, Severity: manifestScanSeverityCritical, Description: "pod shares network, pid or ipc namespace of the host"},
+	{Id: RULE_HOST_PATH_MOUNT, Severity: manifestScanSeverityCritical, Description: "pod mounts a path of the host"},
+	{Id: RULE_MISSING_RESOURCE_LIMITS, Severity: manifestScanSeverityModerate, Description: "container has no cpu or memory limit"},
+	{Id: RULE_LATEST_IMAGE_TAG, Severity: manifestScanSeverityLow, Description: "container image uses latest tag or no tag"},
+}
+
+var dangerousCapabilities = map[string]bool{"ALL": true, "SYS_ADMIN": true, "NET_ADMIN": true, "SYS_MODULE": true, "SYS_PTRACE": true}
+
+var documentSeparator = regexp.MustCompile(`(?m)^---\s*$`)
+
+// GetManifestScanRule returns the built-in rule of an id, nil is returned for unknown rules
+func GetManifestScanRule(ruleId string) *ManifestScanRule {
+	for _, rule := range ManifestScanRules {
+		if rule.Id == ruleId {
+			return rule
+		}
+	}
+	return nil
+}
+
+// ManifestFinding is a violation of a rule by a rendered resource, container is empty for pod level violations
+type ManifestFinding struct {
+	RuleId    string `json:"ruleId"`
+	Severity  string `json:"severity"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Container string `json:"container,omitempty"`
+	Message   string `json:"message"`
+	Action    string `json:"action"`
+}
+
+// ScanManifests runs the built-in rules on pod templates of the workloads in a multi document yaml
+func ScanManifests(manifests string) ([]*ManifestFinding, error) {
+	var findings []*ManifestFinding
+	for _, document := range documentSeparator.Split(manifests, -1) {
+		if len(strings.TrimSpace(document)) == 0 {
+			continue
+		}
+		object := map[string]interface{}{}
+		err := yaml.Unmarshal([]byte(document), &object)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, scanObject(object)...)
+	}
+	return findings, nil
+}
+
+func scanObject(object map[string]interface{}) []*ManifestFinding {
+	kind, _ := object["kind"].(string)
+	if kind == "List" {
+		var findings []*ManifestFinding
+		items, _ := object["items"].([]interface{})
+		for _, item := range items {
+			if itemObject, ok := item.(map[string]interface{}); ok {
+				findings = append(findings, scanObject(itemObject)...)
+			}
+		}
+		return findings
+	}
+	podSpec := podSpecOf(kind, object)
+	if podSpec == nil {
+		return nil
+	}
+	name, _ := nestedMap(object, "metadata")["name"].(string)
+	scanner := &podSpecScanner{kind: kind, name: name}
+	scanner.scan(podSpec)
+	return scanner.findings
+}
+
+// podSpecOf returns the pod template of workload kinds, rollouts and other custom workloads are matched on the
+// spec.template.spec path used by deployments
+func podSpecOf(kind string, object map[string]interface{}) map[string]interface{} {
+	switch kind {
+	case "Pod":
+		return nestedMap(object, "spec")
+	case "CronJob":
+		return nestedMap(object, "spec", "jobTemplate", "spec", "template", "spec")
+	}
+	return nestedMap(object, "spec", "template", "spec")
+}
+
+type podSpecScanner struct {
+	kind     string
+	name     string
+	findings []*ManifestFinding
+}
+
+func (scanner *podSpecScanner) report(ruleId string, container string, message string) {
+	rule := GetManifestScanRule(ruleId)
+	scanner.findings = append(scanner.findings, &ManifestFinding{
+		RuleId:    ruleId,
+		Severity:  rule.Severity,
+		Kind:      scanner.kind,
+		Name:      scanner.name,
+		Container: container,
+		Message:   message,
+		Action:    MANIFEST_SCAN_ACTION_WARN,
+	})
+}
+
+func (scanner *podSpecScanner) scan(podSpec map[string]interface{}) {
+	for _, namespace := range []string{"hostNetwork", "hostPID", "hostIPC"} {
+		if enabled, _ := podSpec[namespace].(bool); enabled {
+			scanner.report(RULE_HOST_NAMESPACE, "", fmt.Sprintf("%s is enabled", namespace))
+		}
+	}
+	volumes, _ := podSpec["volumes"].([]interface{})
+	for _, volume := range volumes {
+		volumeMap, _ := volume.(map[string]interface{})
+		if hostPath := nestedMap(volumeMap, "hostPath"); hostPath != nil {
+			volumeName, _ := volumeMap["name"].(string)
+			hostPathValue, _ := hostPath["path"].(string)
+			scanner.report(RULE_HOST_PATH_MOUNT, "", fmt.Sprintf("volume %s mounts host path %s", volumeName, hostPathValue))
+		}
+	}
+	podRunAsUser, podRunAsUserSet := toInt(nestedMap(podSpec, "securityContext")["runAsUser"])
+	for _, containersKey := range []string{"initContainers", "containers"} {
+		containers, _ := podSpec[containersKey].([]interface{})
+		for _, container := range containers {
+			if containerMap, ok := container.(map[string]interface{}); ok {
+				scanner.scanContainer(containerMap, podRunAsUser, podRunAsUserSet)
+			}
+		}
+	}
+}
+
+func (scanner *podSpecScanner) scanContainer(container map[string]interface{}, podRunAsUser int, podRunAsUserSet bool) {
+	name, _ := container["name"].(string)
+	securityContext := nestedMap(container, "securityContext")
+	if privileged, _ := securityContext["privileged"].(bool); privileged {
+		scanner.report(RULE_PRIVILEGED_CONTAINER, name, "securityContext.privileged is true")
+	}
+	if escalation, _ := securityContext["allowPrivilegeEscalation"].(bool); escalation {
+		scanner.report(RULE_PRIVILEGE_ESCALATION, name, "securityContext.allowPrivilegeEscalation is true")
+	}
+	capabilities, _ := nestedMap(securityContext, "capabilities")["add"].([]interface{})
+	for _, capability := range capabilities {
+		if capabilityName, _ := capability.(string); dangerousCapabilities[strings.ToUpper(capabilityName)] {
+			scanner.report(RULE_DANGEROUS_CAPABILITIES, name, fmt.Sprintf("capability %s is added", capabilityName))
+		}
+	}
+	runAsUser, runAsUserSet := toInt(securityContext["runAsUser"])
+	if !runAsUserSet {
+		runAsUser, runAsUserSet = podRunAsUser, podRunAsUserSet
+	}
+	if runAsUserSet && runAsUser == 0 {
+		scanner.report(RULE_RUN_AS_ROOT, name, "runAsUser is 0")
+	}
+	limits := nestedMap(container, "resources", "limits")
+	for _, resource := range []string{"cpu", "memory"} {
+		if limit, ok := limits[resource]; !ok || limit == nil || fmt.Sprint(limit) == "" {
+			scanner.report(RULE_MISSING_RESOURCE_LIMITS, name, fmt.Sprintf("%s limit is not set", resource))
+		}
+	}
+	image, _ := container["image"].(string)
+	if isLatestImageTag(image) {
+		scanner.report(RULE_LATEST_IMAGE_TAG, name, fmt.Sprintf("image %s is not pinned to a version", image))
+	}
+}
+
+// isLatestImageTag returns true for images without digest whose tag is latest or missing
+func isLatestImageTag(image string) bool {
+	if len(image) == 0 || strings.Contains(image, "@") {
+		return false
+	}
+	repository := image[strings.LastIndex(image, "/")+1:]
+	separator := strings.LastIndex(repository, ":")
+	return separator < 0 || repository[separator+1:] == "latest"
+}
+
+// ApplyManifestScanPolicy sets the action of findings from the policy of their rule, falling back to the policy of
+// every rule. Findings of rules without policy only warn. Returns true when any finding blocks the deployment
+func ApplyManifestScanPolicy(findings []*ManifestFinding, policies map[string]*security.ManifestScanPolicy) bool {
+	blocked := false
+	for _, finding := range findings {
+		policy, ok := policies[finding.RuleId]
+		if !ok {
+			policy = policies[security.AnyManifestScanRule]
+		}
+		if policy != nil && policy.Action == security.Block {
+			finding.Action = MANIFEST_SCAN_ACTION_BLOCK
+			blocked = true
+		} else {
+			finding.Action = MANIFEST_SCAN_ACTION_WARN
+		}
+	}
+	return blocked
+}
+
+// GetFailClosedManifestScanRules returns the rules of blocking policies which are not fail open, a deployment whose
+// manifests could not be scanned is blocked when there is any
+func GetFailClosedManifestScanRules(policies map[string]*security.ManifestScanPolicy) []string {
+	var rules []string
+	for ruleId, policy := range policies {
+		if policy.Action == security.Block && !policy.FailOpen {
+			rules = append(rules, ruleId)
+		}
+	}
+	sort.Strings(rules)
+	return rules
+}
+
+// BuildManifestScanViolationMessage summarises findings which blocked a deployment by count and rule, it is kept within
+// the runner message length, see BuildManifestScanViolationDetail for the full list
+func BuildManifestScanViolationMessage(findings []*ManifestFinding) string {
+	var rules []string
+	ruleAdded := make(map[string]bool)
+	count := 0
+	for _, finding := range findings {
+		if finding.Action != MANIFEST_SCAN_ACTION_BLOCK {
+			continue
+		}
+		count++
+		if !ruleAdded[finding.RuleId] {
+			ruleAdded[finding.RuleId] = true
+			rules = append(rules, finding.RuleId)
+		}
+	}
+	return truncateMessage(fmt.Sprintf("manifest scan policy violated: %d findings blocked by rule %s", count, strings.Join(rules, ", ")))
+}
+
+// BuildManifestScanViolationDetail lists findings which blocked a deployment
+func BuildManifestScanViolationDetail(findings []*ManifestFinding) string {
+	var violations []string
+	for _, finding := range findings {
+		if finding.Action != MANIFEST_SCAN_ACTION_BLOCK {
+			continue
+		}
+		resource := fmt.Sprintf("%s/%s", finding.Kind, finding.Name)
+		if len(finding.Container) > 0 {
+			resource = fmt.Sprintf("%s container %s", resource, finding.Container)
+		}
+		violations = append(violations, fmt.Sprintf("%s in %s: %s", finding.RuleId, resource, finding.Message))
+	}
+	return fmt.Sprintf("manifest scan policy violated: %s", strings.Join(violations, "; "))
+}
+
+// BuildManifestScanFailureMessage explains a deployment blocked because its manifests could not be scanned, it is kept
+// within the runner message length, see BuildManifestScanFailureDetail for the full error
+func BuildManifestScanFailureMessage(evaluation *ManifestScanEvaluation) string {
+	return truncateMessage(BuildManifestScanFailureDetail(evaluation))
+}
+
+// BuildManifestScanFailureDetail explains a deployment blocked because its manifests could not be scanned
+func BuildManifestScanFailureDetail(evaluation *ManifestScanEvaluation) string {
+	return fmt.Sprintf("manifest scan failed and policy of rule %s does not fail open: %s", strings.Join(evaluation.FailClosedRules, ", "), evaluation.ScanError)
+}
+
+// truncateMessage caps a message to the length of cd_workflow_runner.message column
+func truncateMessage(message string) string {
+	runes := []rune(message)
+	if len(runes) <= manifestScanMessageMaxLength {
+		return message
+	}
+	return string(runes[:manifestScanMessageMaxLength-3]) + "..."
+}
+
+func nestedMap(object map[string]interface{}, fields ...string) map[string]interface{} {
+	current := object
+	for _, field := range fields {
+		if current == nil {
+			return nil
+		}
+		current, _ = current[field].(map[string]interface{})
+	}
+	return current
+}
+
+func toInt(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case float64:
+		return int(v), true
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	}
+	return 0, false
+}
@@ -0,0 +1,230 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package manifestScan
+
+import (
+	"context"
+	"fmt"
+	"github.com/caarlos0/env"
+	client "github.com/devtron-labs/devtron/api/helm-app"
+	openapi "github.com/devtron-labs/devtron/api/openapi/openapiClient"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/security"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"time"
+)
+
+type ManifestScanConfig struct {
+	Enabled bool `env:"MANIFEST_SCAN_ENABLED" envDefault:"true"`
+}
+
+func GetManifestScanConfig() (*ManifestScanConfig, error) {
+	cfg := &ManifestScanConfig{}
+	err := env.Parse(cfg)
+	if err != nil {
+		fmt.Println("failed to parse manifest scan config: " + err.Error())
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// ManifestScanRequest identifies the deployment whose manifests are scanned, findings are stored only when
+// CdWorkflowRunnerId is set
+type ManifestScanRequest struct {
+	CdWorkflowRunnerId int
+	AppId              int
+	EnvironmentId      int
+	ClusterId          int
+	IsAppstore         bool
+	ReleaseName        string
+	Namespace          string
+	UserId             int32
+}
+
+// ManifestScanEvaluation is the outcome of scanning rendered manifests against applicable manifest scan policies. When
+// the manifests could not be rendered or scanned ScanError is set and the deployment is blocked by FailClosedRules
+type ManifestScanEvaluation struct {
+	CdWorkflowRunnerId int                `json:"cdWorkflowRunnerId,omitempty"`
+	AppId              int                `json:"-"`
+	Blocked            bool               `json:"blocked"`
+	Findings           []*ManifestFinding `json:"findings"`
+	ScanError          string             `json:"scanError,omitempty"`
+	FailClosedRules    []string           `json:"failClosedRules,omitempty"`
+}
+
+type ManifestScanService interface {
+	// ScanChart renders a chart in process with the values it is deployed with and scans the rendered manifests
+	ScanChart(chrt *chart.Chart, valuesYaml string, request *ManifestScanRequest) (*ManifestScanEvaluation, error)
+	// ScanHelmChart templates a chart store chart through kubelink and scans the rendered manifests
+	ScanHelmChart(ctx context.Context, appStoreApplicationVersionId int, valuesYaml string, request *ManifestScanRequest) (*ManifestScanEvaluation, error)
+	// EvaluateScanFailure decides whether a deployment whose manifests could not be rendered or scanned is blocked
+	EvaluateScanFailure(scanErr error, request *ManifestScanRequest) (*ManifestScanEvaluation, error)
+	GetScanResult(cdWorkflowRunnerId int) (*ManifestScanEvaluation, error)
+}
+
+type ManifestScanServiceImpl struct {
+	logger                       *zap.SugaredLogger
+	manifestScanConfig           *ManifestScanConfig
+	manifestScanPolicyRepository security.ManifestScanPolicyRepository
+	manifestScanResultRepository ManifestScanResultRepository
+	cdWorkflowRepository         pipelineConfig.CdWorkflowRepository
+	helmAppService               client.HelmAppService
+}
+
+func NewManifestScanServiceImpl(logger *zap.SugaredLogger, manifestScanConfig *ManifestScanConfig,
+	manifestScanPolicyRepository security.ManifestScanPolicyRepository, manifestScanResultRepository ManifestScanResultRepository,
+	cdWorkflowRepository pipelineConfig.CdWorkflowRepository, helmAppService client.HelmAppService) *ManifestScanServiceImpl {
+	return &ManifestScanServiceImpl{
+		logger:                       logger,
+		manifestScanConfig:           manifestScanConfig,
+		manifestScanPolicyRepository: manifestScanPolicyRepository,
+		manifestScanResultRepository: manifestScanResultRepository,
+		cdWorkflowRepository:         cdWorkflowRepository,
+		helmAppService:               helmAppService,
+	}
+}
+
+func (impl ManifestScanServiceImpl) ScanChart(chrt *chart.Chart, valuesYaml string, request *ManifestScanRequest) (*ManifestScanEvaluation, error) {
+	if !impl.manifestScanConfig.Enabled {
+		return &ManifestScanEvaluation{}, nil
+	}
+	manifests, err := RenderChart(chrt, valuesYaml, request.ReleaseName, request.Namespace)
+	if err != nil {
+		impl.logger.Errorw("error in rendering chart for manifest scan", "err", err, "releaseName", request.ReleaseName)
+		return impl.EvaluateScanFailure(err, request)
+	}
+	return impl.scan(manifests, request)
+}
+
+func (impl ManifestScanServiceImpl) ScanHelmChart(ctx context.Context, appStoreApplicationVersionId int, valuesYaml string, request *ManifestScanRequest) (*ManifestScanEvaluation, error) {
+	if !impl.manifestScanConfig.Enabled {
+		return &ManifestScanEvaluation{}, nil
+	}
+	versionId := int32(appStoreApplicationVersionId)
+	clusterId := int32(request.ClusterId)
+	templateChartRequest := &openapi.TemplateChartRequest{
+		ClusterId:                    &clusterId,
+		Namespace:                    &request.Namespace,
+		ReleaseName:                  &request.ReleaseName,
+		AppStoreApplicationVersionId: &versionId,
+		ValuesYaml:                   &valuesYaml,
+	}
+	response, err := impl.helmAppService.TemplateChart(ctx, templateChartRequest)
+	if err != nil {
+		impl.logger.Errorw("error in templating chart for manifest scan", "err", err, "releaseName", request.ReleaseName)
+		return impl.EvaluateScanFailure(err, request)
+	}
+	return impl.scan(response.GetManifest(), request)
+}
+
+func (impl ManifestScanServiceImpl) scan(manifests string, request *ManifestScanRequest) (*ManifestScanEvaluation, error) {
+	findings, err := ScanManifests(manifests)
+	if err != nil {
+		impl.logger.Errorw("error in scanning rendered manifests", "err", err, "releaseName", request.ReleaseName)
+		return impl.EvaluateScanFailure(err, request)
+	}
+	policies, err := impl.manifestScanPolicyRepository.GetApplicablePolicies(request.ClusterId, request.EnvironmentId, request.AppId, request.IsAppstore)
+	if err != nil {
+		impl.logger.Errorw("error in fetching manifest scan policies", "err", err, "appId", request.AppId, "envId", request.EnvironmentId)
+		return nil, err
+	}
+	evaluation := &ManifestScanEvaluation{
+		CdWorkflowRunnerId: request.CdWorkflowRunnerId,
+		AppId:              request.AppId,
+		Blocked:            ApplyManifestScanPolicy(findings, policies),
+		Findings:           findings,
+	}
+	if request.CdWorkflowRunnerId > 0 {
+		var results []*ManifestScanResult
+		for _, finding := range findings {
+			results = append(results, &ManifestScanResult{
+				CdWorkflowRunnerId: request.CdWorkflowRunnerId,
+				AppId:              request.AppId,
+				EnvironmentId:      request.EnvironmentId,
+				RuleId:             finding.RuleId,
+				Severity:           finding.Severity,
+				Kind:               finding.Kind,
+				ResourceName:       finding.Name,
+				Container:          finding.Container,
+				Message:            finding.Message,
+				Action:             finding.Action,
+				AuditLog:           sql.AuditLog{CreatedOn: time.Now(), CreatedBy: request.UserId, UpdatedOn: time.Now(), UpdatedBy: request.UserId},
+			})
+		}
+		err = impl.manifestScanResultRepository.SaveResults(request.CdWorkflowRunnerId, results)
+		if err != nil {
+			impl.logger.Errorw("error in saving manifest scan results", "err", err, "cdWorkflowRunnerId", request.CdWorkflowRunnerId)
+			return nil, err
+		}
+	}
+	return evaluation, nil
+}
+
+// EvaluateScanFailure blocks a deployment whose manifests could not be scanned when any applicable blocking policy is
+// not fail open, otherwise the deployment goes ahead unscanned
+func (impl ManifestScanServiceImpl) EvaluateScanFailure(scanErr error, request *ManifestScanRequest) (*ManifestScanEvaluation, error) {
+	policies, err := impl.manifestScanPolicyRepository.GetApplicablePolicies(request.ClusterId, request.EnvironmentId, request.AppId, request.IsAppstore)
+	if err != nil {
+		impl.logger.Errorw("error in fetching manifest scan policies", "err", err, "appId", request.AppId, "envId", request.EnvironmentId)
+		return nil, err
+	}
+	failClosedRules := GetFailClosedManifestScanRules(policies)
+	return &ManifestScanEvaluation{
+		CdWorkflowRunnerId: request.CdWorkflowRunnerId,
+		AppId:              request.AppId,
+		Blocked:            len(failClosedRules) > 0,
+		Findings:           []*ManifestFinding{},
+		ScanError:          scanErr.Error(),
+		FailClosedRules:    failClosedRules,
+	}, nil
+}
+
+func (impl ManifestScanServiceImpl) GetScanResult(cdWorkflowRunnerId int) (*ManifestScanEvaluation, error) {
+	runner, err := impl.cdWorkflowRepository.FindWorkflowRunnerById(cdWorkflowRunnerId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching cd workflow runner", "err", err, "cdWorkflowRunnerId", cdWorkflowRunnerId)
+		return nil, err
+	}
+	results, err := impl.manifestScanResultRepository.FindByCdWorkflowRunnerId(cdWorkflowRunnerId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching manifest scan results", "err", err, "cdWorkflowRunnerId", cdWorkflowRunnerId)
+		return nil, err
+	}
+	evaluation := &ManifestScanEvaluation{CdWorkflowRunnerId: cdWorkflowRunnerId, Findings: []*ManifestFinding{}}
+	if runner.CdWorkflow != nil && runner.CdWorkflow.Pipeline != nil {
+		evaluation.AppId = runner.CdWorkflow.Pipeline.AppId
+	}
+	for _, result := range results {
+		evaluation.Findings = append(evaluation.Findings, &ManifestFinding{
+			RuleId:    result.RuleId,
+			Severity:  result.Severity,
+			Kind:      result.Kind,
+			Name:      result.ResourceName,
+			Container: result.Container,
+			Message:   result.Message,
+			Action:    result.Action,
+		})
+		if result.Action == MANIFEST_SCAN_ACTION_BLOCK {
+			evaluation.Blocked = true
+		}
+	}
+	return evaluation, nil
+}
@@ -0,0 +1,251 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package manifestScan
+
+import (
+	"github.com/devtron-labs/devtron/internal/sql/repository/security"
+	"k8s.io/helm/pkg/chartutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const scanTestDeployment = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: payments
+spec:
+  template:
+    spec:
+      hostNetwork: true
+      securityContext:
+        runAsUser: 0
+      volumes:
+        - name: docker
+          hostPath:
+            path: /var/run/docker.sock
+      initContainers:
+        - name: init
+          image: busybox@sha256:abc
+          securityContext:
+            runAsUser: 1000
+          resources:
+            limits:
+              cpu: 100m
+              memory: 64Mi
+      containers:
+        - name: app
+          image: registry:5000/devtron/payments
+          securityContext:
+            privileged: true
+            allowPrivilegeEscalation: true
+            capabilities:
+              add: ["NET_BIND_SERVICE", "SYS_ADMIN"]
+          resources:
+            limits:
+              cpu: "1"
+`
+
+func TestScanManifests(t *testing.T) {
+	tests := []struct {
+		name      string
+		manifests string
+		want      []string
+	}{
+		{
+			name:      "deployment",
+			manifests: scanTestDeployment,
+			want: []string{
+				"HOST_NAMESPACE:", "HOST_PATH_MOUNT:", "PRIVILEGED_CONTAINER:app", "PRIVILEGE_ESCALATION:app",
+				"DANGEROUS_CAPABILITIES:app", "RUN_AS_ROOT:app", "MISSING_RESOURCE_LIMITS:app", "LATEST_IMAGE_TAG:app",
+			},
+		},
+		{
+			name: "cronjob with latest tag",
+			manifests: `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  image: nginx:latest
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: report
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+            - name: report
+              image: nginx:latest
+              resources:
+                limits: {cpu: 100m, memory: 64Mi}
+`,
+			want: []string{"LATEST_IMAGE_TAG:report"},
+		},
+		{
+			name: "compliant pod",
+			manifests: `
+kind: Pod
+metadata:
+  name: web
+spec:
+  containers:
+    - name: web
+      image: nginx:1.23
+      securityContext: {runAsUser: 101, allowPrivilegeEscalation: false}
+      resources:
+        limits: {cpu: 100m, memory: 64Mi}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings, err := ScanManifests(tt.manifests)
+			if err != nil {
+				t.Fatalf("ScanManifests() error = %v", err)
+			}
+			var got []string
+			for _, finding := range findings {
+				got = append(got, finding.RuleId+":"+finding.Container)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ScanManifests() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyManifestScanPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		policies    map[string]*security.ManifestScanPolicy
+		wantBlocked bool
+		want        []string
+	}{
+		{name: "no policy warns", want: []string{"warn", "warn"}},
+		{
+			name:        "rule blocked",
+			policies:    map[string]*security.ManifestScanPolicy{RULE_PRIVILEGED_CONTAINER: {RuleId: RULE_PRIVILEGED_CONTAINER, Action: security.Block}},
+			wantBlocked: true,
+			want:        []string{"block", "warn"},
+		},
+		{
+			name: "rule allowed over any rule blocked",
+			policies: map[string]*security.ManifestScanPolicy{
+				security.AnyManifestScanRule: {RuleId: security.AnyManifestScanRule, Action: security.Block},
+				RULE_LATEST_IMAGE_TAG:        {RuleId: RULE_LATEST_IMAGE_TAG, Action: security.Allow},
+			},
+			wantBlocked: true,
+			want:        []string{"block", "warn"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := []*ManifestFinding{{RuleId: RULE_PRIVILEGED_CONTAINER}, {RuleId: RULE_LATEST_IMAGE_TAG}}
+			blocked := ApplyManifestScanPolicy(findings, tt.policies)
+			var got []string
+			for _, finding := range findings {
+				got = append(got, finding.Action)
+			}
+			if blocked != tt.wantBlocked || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ApplyManifestScanPolicy() = %v %v, want %v %v", blocked, got, tt.wantBlocked, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetFailClosedManifestScanRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		policies map[string]*security.ManifestScanPolicy
+		want     []string
+	}{
+		{name: "no policy fails open"},
+		{
+			name:     "warn policy fails open",
+			policies: map[string]*security.ManifestScanPolicy{RULE_PRIVILEGED_CONTAINER: {RuleId: RULE_PRIVILEGED_CONTAINER, Action: security.Allow}},
+		},
+		{
+			name: "block policy fails closed by default",
+			policies: map[string]*security.ManifestScanPolicy{
+				RULE_PRIVILEGED_CONTAINER: {RuleId: RULE_PRIVILEGED_CONTAINER, Action: security.Block},
+				RULE_LATEST_IMAGE_TAG:     {RuleId: RULE_LATEST_IMAGE_TAG, Action: security.Block, FailOpen: true},
+			},
+			want: []string{RULE_PRIVILEGED_CONTAINER},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetFailClosedManifestScanRules(tt.policies); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetFailClosedManifestScanRules() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildManifestScanViolationMessage(t *testing.T) {
+	var findings []*ManifestFinding
+	for i := 0; i < 50; i++ {
+		findings = append(findings, &ManifestFinding{RuleId: RULE_PRIVILEGED_CONTAINER, Kind: "Deployment", Name: "app", Container: strings.Repeat("c", i+1), Message: "container runs privileged", Action: MANIFEST_SCAN_ACTION_BLOCK})
+	}
+	findings = append(findings, &ManifestFinding{RuleId: RULE_LATEST_IMAGE_TAG, Kind: "Deployment", Name: "app", Message: "image uses latest tag", Action: MANIFEST_SCAN_ACTION_WARN})
+	want := "manifest scan policy violated: 50 findings blocked by rule PRIVILEGED_CONTAINER"
+	if got := BuildManifestScanViolationMessage(findings); got != want {
+		t.Errorf("BuildManifestScanViolationMessage() = %s, want %s", got, want)
+	}
+	if detail := BuildManifestScanViolationDetail(findings); !strings.Contains(detail, "Deployment/app container "+strings.Repeat("c", 50)) || strings.Contains(detail, RULE_LATEST_IMAGE_TAG) {
+		t.Errorf("BuildManifestScanViolationDetail() = %s", detail)
+	}
+	evaluation := &ManifestScanEvaluation{ScanError: strings.Repeat("e", 1000), FailClosedRules: []string{RULE_PRIVILEGED_CONTAINER}}
+	if got := BuildManifestScanFailureMessage(evaluation); len([]rune(got)) != manifestScanMessageMaxLength {
+		t.Errorf("BuildManifestScanFailureMessage() length = %d, want %d", len([]rune(got)), manifestScanMessageMaxLength)
+	}
+}
+
+func TestRenderChart(t *testing.T) {
+	chrt, err := chartutil.Load("../../scripts/devtron-reference-helm-charts/reference-chart_4-14-0")
+	if err != nil {
+		t.Fatalf("error in loading reference chart: %v", err)
+	}
+	chrt.Metadata.Name = "payments"
+	values := `{"server":{"deployment":{"image":"devtron/payments","image_tag":"latest"}},"resources":{"limits":{"cpu":"1","memory":"1Gi"}}}`
+	manifests, err := RenderChart(chrt, values, "payments-prod", "prod")
+	if err != nil {
+		t.Fatalf("RenderChart() error = %v", err)
+	}
+	if !strings.Contains(manifests, "# Source: payments/templates/deployment.yaml") || !strings.Contains(manifests, "release: payments-prod") {
+		t.Errorf("RenderChart() did not render deployment of release")
+	}
+	findings, err := ScanManifests(manifests)
+	if err != nil {
+		t.Fatalf("ScanManifests() error = %v", err)
+	}
+	var got []string
+	for _, finding := range findings {
+		got = append(got, finding.RuleId+":"+finding.Kind)
+	}
+	if want := []string{"LATEST_IMAGE_TAG:Rollout"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ScanManifests() of rendered chart = %v, want %v", got, want)
+	}
+}
@@ -28,7 +28,7 @@ import (
 type NotificationConfigBuilder interface {
 	BuildNotificationSettingsConfig(notificationSettingsRequest *NotificationConfigRequest, existingNotificationSettingsConfig *repository.NotificationSettingsView, userId int32) (*repository.NotificationSettingsView, error)
 	BuildNewNotificationSettings(notificationSettingsRequest *NotificationConfigRequest, notificationSettingsView *repository.NotificationSettingsView) ([]repository.NotificationSettings, error)
-	BuildNotificationSettingWithPipeline(teamId *int, envId *int, appId *int, pipelineId *int, pipelineType util.PipelineType, eventTypeId int, viewId int, providers []*Provider) (repository.NotificationSettings, error)
+	BuildNotificationSettingWithPipeline(teamId *int, envId *int, appId *int, pipelineId *int, pipelineType util.PipelineType, eventTypeId int, viewId int, providers []*Provider, deliveryMode util.DeliveryMode, dedupWindowMinutes int) (repository.NotificationSettings, error)
 }
 
 type NotificationConfigBuilderImpl struct {
@@ -42,13 +42,15 @@ func NewNotificationConfigBuilderImpl(logger *zap.SugaredLogger) *NotificationCo
 }
 
 type NSConfig struct {
-	TeamId       []*int            `json:"teamId"`
-	AppId        []*int            `json:"appId"`
-	EnvId        []*int            `json:"envId"`
-	PipelineId   *int              `json:"pipelineId"`
-	PipelineType util.PipelineType `json:"pipelineType" validate:"required"`
-	EventTypeIds []int             `json:"eventTypeIds" validate:"required"`
-	Providers    []*Provider       `json:"providers" validate:"required"`
+	TeamId             []*int            `json:"teamId"`
+	AppId              []*int            `json:"appId"`
+	EnvId              []*int            `json:"envId"`
+	PipelineId         *int              `json:"pipelineId"`
+	PipelineType       util.PipelineType `json:"pipelineType" validate:"required"`
+	EventTypeIds       []int             `json:"eventTypeIds" validate:"required"`
+	Providers          []*Provider       `json:"providers" validate:"required"`
+	DeliveryMode       util.DeliveryMode `json:"deliveryMode,omitempty"`
+	DedupWindowMinutes int               `json:"dedupWindowMinutes,omitempty"`
 }
 
 func (impl NotificationConfigBuilderImpl) BuildNotificationSettingsConfig(notificationSettingsRequest *NotificationConfigRequest, existingNotificationSettingsConfig *repository.NotificationSettingsView, userId int32) (*repository.NotificationSettingsView, error) {
@@ -60,6 +62,8 @@ func (impl NotificationConfigBuilderImpl) BuildNotificationSettingsConfig(notifi
 	nsConfig.PipelineType = notificationSettingsRequest.PipelineType
 	nsConfig.EventTypeIds = notificationSettingsRequest.EventTypeIds
 	nsConfig.Providers = notificationSettingsRequest.Providers
+	nsConfig.DeliveryMode = notificationSettingsRequest.DeliveryMode
+	nsConfig.DedupWindowMinutes = notificationSettingsRequest.DedupWindowMinutes
 
 	config, err := json.Marshal(nsConfig)
 	if err != nil {
@@ -140,7 +144,7 @@ func (impl NotificationConfigBuilderImpl) BuildNewNotificationSettings(notificat
 
 	for _, item := range tempRequest {
 		for _, e := range notificationSettingsRequest.EventTypeIds {
-			notificationSetting, err := impl.BuildNotificationSettingWithPipeline(item.TeamId, item.EnvId, item.AppId, item.PipelineId, notificationSettingsRequest.PipelineType, e, notificationSettingsView.Id, notificationSettingsRequest.Providers)
+			notificationSetting, err := impl.BuildNotificationSettingWithPipeline(item.TeamId, item.EnvId, item.AppId, item.PipelineId, notificationSettingsRequest.PipelineType, e, notificationSettingsView.Id, notificationSettingsRequest.Providers, notificationSettingsRequest.DeliveryMode, notificationSettingsRequest.DedupWindowMinutes)
 			if err != nil {
 				impl.logger.Error(err)
 				return nil, err
@@ -168,7 +172,7 @@ func (impl NotificationConfigBuilderImpl) buildNotificationSetting(notificationS
 	return notificationSetting, nil
 }
 
-func (impl NotificationConfigBuilderImpl) BuildNotificationSettingWithPipeline(teamId *int, envId *int, appId *int, pipelineId *int, pipelineType util.PipelineType, eventTypeId int, viewId int, providers []*Provider) (repository.NotificationSettings, error) {
+func (impl NotificationConfigBuilderImpl) BuildNotificationSettingWithPipeline(teamId *int, envId *int, appId *int, pipelineId *int, pipelineType util.PipelineType, eventTypeId int, viewId int, providers []*Provider, deliveryMode util.DeliveryMode, dedupWindowMinutes int) (repository.NotificationSettings, error) {
 	
 	providersJson, err := json.Marshal(providers)
 	if err != nil {
@@ -184,6 +188,10 @@ func (impl NotificationConfigBuilderImpl) BuildNotificationSettingWithPipeline(t
 		EventTypeId:  eventTypeId,
 		Config:       string(providersJson),
 		ViewId:       viewId,
+		DeliveryMode: string(deliveryMode),
+	}
+	if deliveryMode == util.DeliveryDedup {
+		notificationSetting.DedupWindowMinutes = dedupWindowMinutes
 	}
 	return notificationSetting, nil
 }
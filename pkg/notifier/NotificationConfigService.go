@@ -19,6 +19,8 @@ package notifier
 
 import (
 	"encoding/json"
+	"fmt"
+	client "github.com/devtron-labs/devtron/client/events"
 	"github.com/devtron-labs/devtron/internal/sql/repository/app"
 	repository3 "github.com/devtron-labs/devtron/pkg/cluster/repository"
 	repository2 "github.com/devtron-labs/devtron/pkg/team"
@@ -53,11 +55,13 @@ type NotificationConfigServiceImpl struct {
 	slackRepository                repository.SlackNotificationRepository
 	sesRepository                  repository.SESNotificationRepository
 	smtpRepository                 repository.SMTPNotificationRepository
+	webhookRepository              repository.WebhookNotificationRepository
 	teamRepository                 repository2.TeamRepository
 	environmentRepository          repository3.EnvironmentRepository
 	appRepository                  app.AppRepository
 	userRepository                 repository4.UserRepository
 	ciPipelineMaterialRepository   pipelineConfig.CiPipelineMaterialRepository
+	eventClientConfig              *client.EventClientConfig
 }
 
 type NotificationSettingRequest struct {
@@ -105,6 +109,9 @@ type NotificationConfigRequest struct {
 	PipelineType util.PipelineType `json:"pipelineType" validate:"required"`
 	EventTypeIds []int             `json:"eventTypeIds" validate:"required"`
 	Providers    []*Provider       `json:"providers"`
+	// DeliveryMode is immediate when empty, DedupWindowMinutes is required for de-duplicating mode
+	DeliveryMode       util.DeliveryMode `json:"deliveryMode,omitempty"`
+	DedupWindowMinutes int               `json:"dedupWindowMinutes,omitempty"`
 }
 
 type NSViewResponse struct {
@@ -113,15 +120,17 @@ type NSViewResponse struct {
 }
 
 type NotificationSettingsResponse struct {
-	Id               int                `json:"id"`
-	ConfigName       string             `json:"configName"`
-	TeamResponse     []*TeamResponse    `json:"team"`
-	AppResponse      []*AppResponse     `json:"app"`
-	EnvResponse      []*EnvResponse     `json:"environment"`
-	PipelineResponse *PipelineResponse  `json:"pipeline"`
-	PipelineType     string             `json:"pipelineType"`
-	ProvidersConfig  []*ProvidersConfig `json:"providerConfigs"`
-	EventTypes       []int              `json:"eventTypes"`
+	Id                 int                `json:"id"`
+	ConfigName         string             `json:"configName"`
+	TeamResponse       []*TeamResponse    `json:"team"`
+	AppResponse        []*AppResponse     `json:"app"`
+	EnvResponse        []*EnvResponse     `json:"environment"`
+	PipelineResponse   *PipelineResponse  `json:"pipeline"`
+	PipelineType       string             `json:"pipelineType"`
+	ProvidersConfig    []*ProvidersConfig `json:"providerConfigs"`
+	EventTypes         []int              `json:"eventTypes"`
+	DeliveryMode       util.DeliveryMode  `json:"deliveryMode"`
+	DedupWindowMinutes int                `json:"dedupWindowMinutes,omitempty"`
 }
 
 type SearchFilterResponse struct {
@@ -167,7 +176,8 @@ func NewNotificationConfigServiceImpl(logger *zap.SugaredLogger, notificationSet
 	sesRepository repository.SESNotificationRepository, smtpRepository repository.SMTPNotificationRepository,
 	teamRepository repository2.TeamRepository,
 	environmentRepository repository3.EnvironmentRepository, appRepository app.AppRepository,
-	userRepository repository4.UserRepository, ciPipelineMaterialRepository pipelineConfig.CiPipelineMaterialRepository) *NotificationConfigServiceImpl {
+	userRepository repository4.UserRepository, ciPipelineMaterialRepository pipelineConfig.CiPipelineMaterialRepository,
+	webhookRepository repository.WebhookNotificationRepository, eventClientConfig *client.EventClientConfig) *NotificationConfigServiceImpl {
 	return &NotificationConfigServiceImpl{
 		logger:                         logger,
 		notificationSettingsRepository: notificationSettingsRepository,
@@ -182,6 +192,8 @@ func NewNotificationConfigServiceImpl(logger *zap.SugaredLogger, notificationSet
 		appRepository:                  appRepository,
 		userRepository:                 userRepository,
 		ciPipelineMaterialRepository:   ciPipelineMaterialRepository,
+		webhookRepository:              webhookRepository,
+		eventClientConfig:              eventClientConfig,
 	}
 }
 
@@ -367,6 +379,7 @@ func (impl *NotificationConfigServiceImpl) BuildNotificationSettingsResponse(not
 			var slackIds []*int
 			var sesUserIds []int32
 			var smtpUserIds []int32
+			var webhookIds []*int
 			var providerConfigs []*ProvidersConfig
 			for _, item := range config.Providers {
 				// if item.ConfigId > 0 that means, user is of user repository, else user email is custom
@@ -377,6 +390,8 @@ func (impl *NotificationConfigServiceImpl) BuildNotificationSettingsResponse(not
 						sesUserIds = append(sesUserIds, int32(item.ConfigId))
 					} else if item.Destination == util.SMTP {
 						smtpUserIds = append(smtpUserIds, int32(item.ConfigId))
+					} else if item.Destination == util.Webhook {
+						webhookIds = append(webhookIds, &item.ConfigId)
 					}
 				} else {
 					providerConfigs = append(providerConfigs, &ProvidersConfig{Dest: string(item.Destination), Recipient: item.Recipient})
@@ -413,6 +428,16 @@ func (impl *NotificationConfigServiceImpl) BuildNotificationSettingsResponse(not
 					providerConfigs = append(providerConfigs, &ProvidersConfig{Id: int(item.Id), ConfigName: item.EmailId, Dest: string(util.SMTP)})
 				}
 			}
+			if len(webhookIds) > 0 {
+				webhookConfigs, err := impl.webhookRepository.FindByIds(webhookIds)
+				if err != nil && err != pg.ErrNoRows {
+					impl.logger.Errorw("error in fetching webhook config", "err", err)
+					return notificationSettingsResponses, deletedItemCount, err
+				}
+				for _, item := range webhookConfigs {
+					providerConfigs = append(providerConfigs, &ProvidersConfig{Id: item.Id, ConfigName: item.ConfigName, Dest: string(util.Webhook)})
+				}
+			}
 			notificationSettingsResponse.ProvidersConfig = providerConfigs
 		}
 
@@ -459,6 +484,11 @@ func (impl *NotificationConfigServiceImpl) BuildNotificationSettingsResponse(not
 
 		notificationSettingsResponse.PipelineType = string(config.PipelineType)
 		notificationSettingsResponse.EventTypes = config.EventTypeIds
+		notificationSettingsResponse.DeliveryMode = config.DeliveryMode
+		notificationSettingsResponse.DedupWindowMinutes = config.DedupWindowMinutes
+		if len(notificationSettingsResponse.DeliveryMode) == 0 {
+			notificationSettingsResponse.DeliveryMode = util.DeliveryImmediate
+		}
 
 		notificationSettingsResponses = append(notificationSettingsResponses, notificationSettingsResponse)
 	}
@@ -471,6 +501,7 @@ func (impl *NotificationConfigServiceImpl) buildProvidersConfig(config config) (
 		sesConfigNamesMap := map[int]string{}
 		slackConfigNameMap := map[int]string{}
 		smtpConfigNamesMap := map[int]string{}
+		webhookConfigNamesMap := map[int]string{}
 		for _, c := range config.Providers {
 			if util.Slack == c.Destination {
 				if _, ok := slackConfigNameMap[c.ConfigId]; ok {
@@ -487,12 +518,18 @@ func (impl *NotificationConfigServiceImpl) buildProvidersConfig(config config) (
 					continue
 				}
 				smtpConfigNamesMap[c.ConfigId] = ""
+			} else if util.Webhook == c.Destination {
+				if _, ok := webhookConfigNamesMap[c.ConfigId]; ok {
+					continue
+				}
+				webhookConfigNamesMap[c.ConfigId] = ""
 			}
 		}
 
 		slackIds := make([]int, 0, len(slackConfigNameMap))
 		sesIds := make([]int, 0, len(sesConfigNamesMap))
 		smtpIds := make([]int, 0, len(smtpConfigNamesMap))
+		webhookIds := make([]int, 0, len(webhookConfigNamesMap))
 
 		for k := range slackConfigNameMap {
 			slackIds = append(slackIds, k)
@@ -503,6 +540,9 @@ func (impl *NotificationConfigServiceImpl) buildProvidersConfig(config config) (
 		for k := range smtpConfigNamesMap {
 			smtpIds = append(smtpIds, k)
 		}
+		for k := range webhookConfigNamesMap {
+			webhookIds = append(webhookIds, k)
+		}
 
 		if len(slackIds) > 0 {
 			slackConfigs, err := impl.slackRepository.FindByIdsIn(slackIds)
@@ -534,6 +574,16 @@ func (impl *NotificationConfigServiceImpl) buildProvidersConfig(config config) (
 				smtpConfigNamesMap[s.Id] = s.ConfigName
 			}
 		}
+		if len(webhookIds) > 0 {
+			webhookConfigs, err := impl.webhookRepository.FindByIdsIn(webhookIds)
+			if err != nil {
+				impl.logger.Errorw("error on fetch webhook configs", "err", err)
+				return []ProvidersConfig{}, err
+			}
+			for _, s := range webhookConfigs {
+				webhookConfigNamesMap[s.Id] = s.ConfigName
+			}
+		}
 		for _, c := range config.Providers {
 			var configName string
 			if c.Destination == util.Slack {
@@ -542,6 +592,8 @@ func (impl *NotificationConfigServiceImpl) buildProvidersConfig(config config) (
 				configName = sesConfigNamesMap[c.ConfigId]
 			} else if c.Destination == util.SMTP {
 				configName = smtpConfigNamesMap[c.ConfigId]
+			} else if c.Destination == util.Webhook {
+				configName = webhookConfigNamesMap[c.ConfigId]
 			}
 			providerConfig := ProvidersConfig{
 				Id:         c.ConfigId,
@@ -598,9 +650,47 @@ func (impl *NotificationConfigServiceImpl) buildPipelineResponses(config config,
 	return pipelineType, pipelineResponses, nil
 }
 
+// ValidateEventTypes rejects event types which settings of a pipeline type cannot subscribe to
+func ValidateEventTypes(pipelineType util.PipelineType, eventTypeIds []int) error {
+	for _, eventTypeId := range eventTypeIds {
+		if !util.IsSubscribableEventType(pipelineType, eventTypeId) {
+			return fmt.Errorf("event type %d is not supported for %s pipelines", eventTypeId, pipelineType)
+		}
+	}
+	return nil
+}
+
+// maxDedupWindowMinutes caps de-duplication window to a day, daily digest covers longer windows
+const maxDedupWindowMinutes = 24 * 60
+
+// ValidateDeliveryMode rejects unknown delivery modes, de-duplication without a window and delivery modes other than
+// immediate when notifier of contract version cannot deliver events to providers of one setting
+func ValidateDeliveryMode(deliveryMode util.DeliveryMode, dedupWindowMinutes int, notifierContractVersion int) error {
+	if !client.IsImmediateDeliveryMode(string(deliveryMode)) && !client.SupportsDeliveryModes(notifierContractVersion) {
+		return fmt.Errorf("delivery mode %s needs notifier contract version %d or above", deliveryMode, client.NotifierProvidersContractVersion)
+	}
+	switch deliveryMode {
+	case "", util.DeliveryImmediate, util.DeliveryHourlyDigest, util.DeliveryDailyDigest:
+		return nil
+	case util.DeliveryDedup:
+		if dedupWindowMinutes <= 0 || dedupWindowMinutes > maxDedupWindowMinutes {
+			return fmt.Errorf("dedup window must be between 1 and %d minutes", maxDedupWindowMinutes)
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported delivery mode %s", deliveryMode)
+}
+
 func (impl *NotificationConfigServiceImpl) saveNotificationSetting(notificationSettingsRequest *NotificationConfigRequest, userId int32, tx *pg.Tx) (int, error) {
 	var existingNotificationSettingsConfig *repository.NotificationSettingsView
-	var err error
+	err := ValidateEventTypes(notificationSettingsRequest.PipelineType, notificationSettingsRequest.EventTypeIds)
+	if err != nil {
+		return 0, err
+	}
+	err = ValidateDeliveryMode(notificationSettingsRequest.DeliveryMode, notificationSettingsRequest.DedupWindowMinutes, impl.eventClientConfig.NotifierContractVersion)
+	if err != nil {
+		return 0, err
+	}
 	if notificationSettingsRequest.Id != 0 {
 		existingNotificationSettingsConfig, err = impl.notificationSettingsRepository.FindNotificationSettingsViewById(notificationSettingsRequest.Id)
 		if err != nil {
@@ -652,9 +742,20 @@ func (impl *NotificationConfigServiceImpl) updateNotificationSetting(notificatio
 	nsConfig := &NSConfig{}
 	err = json.Unmarshal([]byte(existingNotificationSettingsConfig.Config), nsConfig)
 	if updateType == util.UpdateEvents {
+		err = ValidateEventTypes(nsConfig.PipelineType, notificationSettingsRequest.EventTypeIds)
+		if err != nil {
+			return 0, err
+		}
 		nsConfig.EventTypeIds = notificationSettingsRequest.EventTypeIds
 	} else if updateType == util.UpdateRecipients {
 		nsConfig.Providers = notificationSettingsRequest.Providers
+	} else if updateType == util.UpdateDelivery {
+		err = ValidateDeliveryMode(notificationSettingsRequest.DeliveryMode, notificationSettingsRequest.DedupWindowMinutes, impl.eventClientConfig.NotifierContractVersion)
+		if err != nil {
+			return 0, err
+		}
+		nsConfig.DeliveryMode = notificationSettingsRequest.DeliveryMode
+		nsConfig.DedupWindowMinutes = notificationSettingsRequest.DedupWindowMinutes
 	}
 	config, err := json.Marshal(nsConfig)
 	if err != nil {
@@ -678,6 +779,8 @@ func (impl *NotificationConfigServiceImpl) updateNotificationSetting(notificatio
 		notificationSettingsRequest.PipelineId = nsConfig.PipelineId
 		notificationSettingsRequest.PipelineType = nsConfig.PipelineType
 		notificationSettingsRequest.Providers = nsConfig.Providers
+		notificationSettingsRequest.DeliveryMode = nsConfig.DeliveryMode
+		notificationSettingsRequest.DedupWindowMinutes = nsConfig.DedupWindowMinutes
 		var notificationSettings []repository.NotificationSettings
 		nsOptions, err := impl.notificationSettingsRepository.FetchNotificationSettingGroupBy(notificationSettingsRequest.Id)
 		if err != nil {
@@ -693,7 +796,7 @@ func (impl *NotificationConfigServiceImpl) updateNotificationSetting(notificatio
 		} else {
 			for _, item := range nsOptions {
 				for _, e := range notificationSettingsRequest.EventTypeIds {
-					notificationSetting, err := impl.notificationConfigBuilder.BuildNotificationSettingWithPipeline(item.TeamId, item.EnvId, item.AppId, item.PipelineId, util.PipelineType(item.PipelineType), e, notificationSettingsRequest.Id, nsConfig.Providers)
+					notificationSetting, err := impl.notificationConfigBuilder.BuildNotificationSettingWithPipeline(item.TeamId, item.EnvId, item.AppId, item.PipelineId, util.PipelineType(item.PipelineType), e, notificationSettingsRequest.Id, nsConfig.Providers, nsConfig.DeliveryMode, nsConfig.DedupWindowMinutes)
 					if err != nil {
 						impl.logger.Error(err)
 						return 0, err
@@ -743,6 +846,21 @@ func (impl *NotificationConfigServiceImpl) updateNotificationSetting(notificatio
 				return 0, err
 			}
 		}
+	} else if updateType == util.UpdateDelivery {
+		nsOptions, err := impl.notificationSettingsRepository.FindNotificationSettingsByViewId(notificationSettingsRequest.Id)
+		if err != nil {
+			impl.logger.Errorw("failed to fetch existing notification settings view", "err", err)
+			return 0, err
+		}
+		for _, ns := range nsOptions {
+			ns.DeliveryMode = string(nsConfig.DeliveryMode)
+			ns.DedupWindowMinutes = nsConfig.DedupWindowMinutes
+			_, err = impl.notificationSettingsRepository.UpdateNotificationSettings(&ns, tx)
+			if err != nil {
+				impl.logger.Errorw("failed to update delivery mode of notification settings", "err", err, "id", ns.Id)
+				return 0, err
+			}
+		}
 	}
 	return existingNotificationSettingsConfig.Id, nil
 }
@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package notifier
+
+import (
+	"testing"
+
+	util "github.com/devtron-labs/devtron/util/event"
+)
+
+func TestValidateEventTypes(t *testing.T) {
+	tests := []struct {
+		name         string
+		pipelineType util.PipelineType
+		eventTypeIds []int
+		wantErr      bool
+	}{
+		{name: "ci pipeline events", pipelineType: util.CI, eventTypeIds: []int{int(util.Trigger), int(util.Fail), int(util.PipelineConfigChanged)}},
+		{name: "cd deployment events", pipelineType: util.CD, eventTypeIds: []int{int(util.DeploymentDegraded), int(util.ImageScanBlocked), int(util.ClusterUnreachable)}},
+		{name: "cd only event on ci", pipelineType: util.CI, eventTypeIds: []int{int(util.Success), int(util.AppHibernated)}, wantErr: true},
+		{name: "unknown event", pipelineType: util.CD, eventTypeIds: []int{99}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateEventTypes(tt.pipelineType, tt.eventTypeIds)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateEventTypes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateDeliveryMode(t *testing.T) {
+	tests := []struct {
+		name                    string
+		deliveryMode            util.DeliveryMode
+		dedupWindowMinutes      int
+		notifierContractVersion int
+		wantErr                 bool
+	}{
+		{name: "default immediate", deliveryMode: "", notifierContractVersion: 2},
+		{name: "daily digest", deliveryMode: util.DeliveryDailyDigest, notifierContractVersion: 2},
+		{name: "dedup with window", deliveryMode: util.DeliveryDedup, dedupWindowMinutes: 10, notifierContractVersion: 2},
+		{name: "dedup without window", deliveryMode: util.DeliveryDedup, notifierContractVersion: 2, wantErr: true},
+		{name: "unknown mode", deliveryMode: "WEEKLY_DIGEST", notifierContractVersion: 2, wantErr: true},
+		{name: "immediate on old notifier", deliveryMode: util.DeliveryImmediate, notifierContractVersion: 1},
+		{name: "digest on old notifier", deliveryMode: util.DeliveryHourlyDigest, notifierContractVersion: 1, wantErr: true},
+		{name: "dedup on old notifier", deliveryMode: util.DeliveryDedup, dedupWindowMinutes: 10, notifierContractVersion: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDeliveryMode(tt.deliveryMode, tt.dedupWindowMinutes, tt.notifierContractVersion)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateDeliveryMode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package notifier
+
+import (
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/util"
+	"go.uber.org/zap"
+	"time"
+)
+
+type NotificationDeliveryService interface {
+	FindDeliveries(filter *repository.NotificationDeliveryFilter) ([]*NotificationDeliveryDto, error)
+	// ReplayEvent sends an outbox event again to every channel it was written for
+	ReplayEvent(eventId int, userId int32) (int, error)
+}
+
+type NotificationDeliveryServiceImpl struct {
+	logger                       *zap.SugaredLogger
+	notificationOutboxRepository repository.NotificationOutboxRepository
+}
+
+// NotificationDeliveryDto is the delivery status of an event on a channel, ConfigId is the webhook of webhook
+// deliveries
+type NotificationDeliveryDto struct {
+	Id            int        `json:"id"`
+	EventId       int        `json:"eventId"`
+	EventTypeId   int        `json:"eventTypeId"`
+	PipelineType  string     `json:"pipelineType"`
+	PipelineId    int        `json:"pipelineId"`
+	AppId         int        `json:"appId"`
+	EnvId         int        `json:"envId"`
+	Channel       string     `json:"channel"`
+	ConfigId      int        `json:"configId,omitempty"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"lastError,omitempty"`
+	NextAttemptOn *time.Time `json:"nextAttemptOn,omitempty"`
+	DeliveredOn   *time.Time `json:"deliveredOn,omitempty"`
+	CreatedOn     time.Time  `json:"createdOn"`
+}
+
+func NewNotificationDeliveryServiceImpl(logger *zap.SugaredLogger, notificationOutboxRepository repository.NotificationOutboxRepository) *NotificationDeliveryServiceImpl {
+	return &NotificationDeliveryServiceImpl{
+		logger:                       logger,
+		notificationOutboxRepository: notificationOutboxRepository,
+	}
+}
+
+func (impl *NotificationDeliveryServiceImpl) FindDeliveries(filter *repository.NotificationDeliveryFilter) ([]*NotificationDeliveryDto, error) {
+	deliveryDtos := make([]*NotificationDeliveryDto, 0)
+	deliveries, err := impl.notificationOutboxRepository.FindDeliveries(filter)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching notification deliveries", "err", err, "filter", filter)
+		return deliveryDtos, err
+	}
+	eventIdsMap := make(map[int]bool)
+	var eventIds []int
+	for _, delivery := range deliveries {
+		if !eventIdsMap[delivery.EventId] {
+			eventIdsMap[delivery.EventId] = true
+			eventIds = append(eventIds, delivery.EventId)
+		}
+	}
+	events, err := impl.notificationOutboxRepository.FindEventsByIds(eventIds)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching notification events", "err", err, "eventIds", eventIds)
+		return deliveryDtos, err
+	}
+	eventsMap := make(map[int]*repository.NotificationEvent)
+	for _, event := range events {
+		eventsMap[event.Id] = event
+	}
+	for _, delivery := range deliveries {
+		deliveryDto := &NotificationDeliveryDto{
+			Id:        delivery.Id,
+			EventId:   delivery.EventId,
+			Channel:   delivery.Channel,
+			ConfigId:  delivery.ConfigId,
+			Status:    string(delivery.Status),
+			Attempts:  delivery.Attempts,
+			LastError: delivery.LastError,
+			CreatedOn: delivery.CreatedOn,
+		}
+		if delivery.Status == repository.NotificationDeliveryPending {
+			nextAttemptOn := delivery.NextAttemptOn
+			deliveryDto.NextAttemptOn = &nextAttemptOn
+		}
+		if !delivery.DeliveredOn.IsZero() {
+			deliveredOn := delivery.DeliveredOn
+			deliveryDto.DeliveredOn = &deliveredOn
+		}
+		if event, ok := eventsMap[delivery.EventId]; ok {
+			deliveryDto.EventTypeId = event.EventTypeId
+			deliveryDto.PipelineType = event.PipelineType
+			deliveryDto.PipelineId = event.PipelineId
+			deliveryDto.AppId = event.AppId
+			deliveryDto.EnvId = event.EnvId
+		}
+		deliveryDtos = append(deliveryDtos, deliveryDto)
+	}
+	return deliveryDtos, nil
+}
+
+func (impl *NotificationDeliveryServiceImpl) ReplayEvent(eventId int, userId int32) (int, error) {
+	_, err := impl.notificationOutboxRepository.FindEventById(eventId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching notification event", "err", err, "eventId", eventId)
+		return 0, err
+	}
+	count, err := impl.notificationOutboxRepository.ResetDeliveriesOfEvent(eventId, userId)
+	if err != nil {
+		impl.logger.Errorw("error in replaying notification event", "err", err, "eventId", eventId)
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,141 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	client "github.com/devtron-labs/devtron/client/events"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/attributes"
+	util2 "github.com/devtron-labs/devtron/util/event"
+	"go.uber.org/zap"
+)
+
+type NotificationInboxService interface {
+	FindInboxItems(userId int32, unreadOnly bool, offset int, size int) (*NotificationInboxResponse, error)
+	// MarkRead marks items of a user read, every unread item is marked when ids is empty
+	MarkRead(userId int32, ids []int) (int, error)
+	GetPreferences(emailId string) (*client.NotificationInboxPreferences, error)
+	UpdatePreferences(emailId string, userId int32, preferences *client.NotificationInboxPreferences) (*client.NotificationInboxPreferences, error)
+}
+
+type NotificationInboxServiceImpl struct {
+	logger                      *zap.SugaredLogger
+	notificationInboxRepository repository.NotificationInboxRepository
+	userAttributesService       attributes.UserAttributesService
+}
+
+type NotificationInboxResponse struct {
+	UnreadCount int                                 `json:"unreadCount"`
+	Items       []*repository.NotificationInboxItem `json:"items"`
+}
+
+type NotificationInboxReadRequest struct {
+	Ids []int `json:"ids"`
+}
+
+func NewNotificationInboxServiceImpl(logger *zap.SugaredLogger, notificationInboxRepository repository.NotificationInboxRepository,
+	userAttributesService attributes.UserAttributesService) *NotificationInboxServiceImpl {
+	return &NotificationInboxServiceImpl{
+		logger:                      logger,
+		notificationInboxRepository: notificationInboxRepository,
+		userAttributesService:       userAttributesService,
+	}
+}
+
+func (impl *NotificationInboxServiceImpl) FindInboxItems(userId int32, unreadOnly bool, offset int, size int) (*NotificationInboxResponse, error) {
+	inbox := &NotificationInboxResponse{Items: make([]*repository.NotificationInboxItem, 0)}
+	items, err := impl.notificationInboxRepository.FindByUserId(userId, unreadOnly, offset, size)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching inbox items", "err", err, "userId", userId)
+		return inbox, err
+	}
+	if len(items) > 0 {
+		inbox.Items = items
+	}
+	inbox.UnreadCount, err = impl.notificationInboxRepository.CountUnread(userId)
+	if err != nil {
+		impl.logger.Errorw("error in counting unread inbox items", "err", err, "userId", userId)
+		return inbox, err
+	}
+	return inbox, nil
+}
+
+func (impl *NotificationInboxServiceImpl) MarkRead(userId int32, ids []int) (int, error) {
+	count, err := impl.notificationInboxRepository.MarkRead(userId, ids)
+	if err != nil {
+		impl.logger.Errorw("error in marking inbox items read", "err", err, "userId", userId, "ids", ids)
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetPreferences returns inbox preferences saved in user attributes, default preferences are returned when user has
+// not saved any
+func (impl *NotificationInboxServiceImpl) GetPreferences(emailId string) (*client.NotificationInboxPreferences, error) {
+	userAttribute, err := impl.userAttributesService.GetUserAttribute(&attributes.UserAttributesDto{EmailId: emailId, Key: client.NotificationInboxPreferencesKey})
+	if err != nil {
+		impl.logger.Errorw("error in fetching inbox preferences", "err", err, "emailId", emailId)
+		return nil, err
+	}
+	if userAttribute == nil || len(userAttribute.Value) == 0 {
+		return client.DefaultNotificationInboxPreferences(), nil
+	}
+	preferences := &client.NotificationInboxPreferences{}
+	err = json.Unmarshal([]byte(userAttribute.Value), preferences)
+	if err != nil {
+		impl.logger.Errorw("error in unmarshal inbox preferences", "err", err, "emailId", emailId)
+		return nil, err
+	}
+	return preferences, nil
+}
+
+func (impl *NotificationInboxServiceImpl) UpdatePreferences(emailId string, userId int32, preferences *client.NotificationInboxPreferences) (*client.NotificationInboxPreferences, error) {
+	err := ValidateInboxEventTypes(preferences.EventTypeIds)
+	if err != nil {
+		return nil, err
+	}
+	value, err := json.Marshal(preferences)
+	if err != nil {
+		impl.logger.Errorw("error in marshal inbox preferences", "err", err, "emailId", emailId)
+		return nil, err
+	}
+	_, err = impl.userAttributesService.UpdateUserAttributes(&attributes.UserAttributesDto{
+		EmailId: emailId,
+		Key:     client.NotificationInboxPreferencesKey,
+		Value:   string(value),
+		UserId:  userId,
+	})
+	if err != nil {
+		impl.logger.Errorw("error in saving inbox preferences", "err", err, "emailId", emailId)
+		return nil, err
+	}
+	return preferences, nil
+}
+
+// ValidateInboxEventTypes rejects event types which neither ci nor cd notifications can subscribe to
+func ValidateInboxEventTypes(eventTypeIds []int) error {
+	for _, eventTypeId := range eventTypeIds {
+		if !util2.IsSubscribableEventType(util2.CI, eventTypeId) && !util2.IsSubscribableEventType(util2.CD, eventTypeId) {
+			return fmt.Errorf("event type %d is not supported", eventTypeId)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package notifier
+
+import (
+	"fmt"
+	client "github.com/devtron-labs/devtron/client/events"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	util2 "github.com/devtron-labs/devtron/util/event"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+const WEBHOOK_CONFIG_TYPE = "webhook"
+
+// maskedWebhookSecret is returned in place of secrets, a config saved with it keeps its existing secret
+const maskedWebhookSecret = "**********"
+
+type WebhookNotificationService interface {
+	SaveOrEditNotificationConfig(channelReq []WebhookConfigDto, userId int32) ([]int, error)
+	FetchWebhookNotificationConfigById(id int) (*WebhookConfigDto, error)
+	FetchAllWebhookNotificationConfig() ([]*WebhookConfigDto, error)
+	FetchAllWebhookNotificationConfigAutocomplete() ([]*NotificationChannelAutoResponse, error)
+	DeleteNotificationConfig(deleteReq *WebhookConfigDto, userId int32) error
+}
+
+type WebhookNotificationServiceImpl struct {
+	logger                         *zap.SugaredLogger
+	webhookRepository              repository.WebhookNotificationRepository
+	notificationSettingsRepository repository.NotificationSettingsRepository
+}
+
+type WebhookChannelConfig struct {
+	Channel           util2.Channel      `json:"channel" validate:"required"`
+	WebhookConfigDtos []WebhookConfigDto `json:"configs"`
+}
+
+// WebhookConfigDto is an outgoing webhook, Payload is a go template executed with the event and defaults to the event
+// json. Requests are signed with Secret when it is set
+type WebhookConfigDto struct {
+	OwnerId     int32             `json:"userId" validate:"number"`
+	TeamId      int               `json:"teamId" validate:"required"`
+	WebhookUrl  string            `json:"webhookUrl" validate:"required,url"`
+	ConfigName  string            `json:"configName" validate:"required"`
+	Header      map[string]string `json:"header"`
+	Payload     string            `json:"payload"`
+	Secret      string            `json:"secret"`
+	Description string            `json:"description"`
+	Id          int               `json:"id" validate:"number"`
+}
+
+func NewWebhookNotificationServiceImpl(logger *zap.SugaredLogger, webhookRepository repository.WebhookNotificationRepository,
+	notificationSettingsRepository repository.NotificationSettingsRepository) *WebhookNotificationServiceImpl {
+	return &WebhookNotificationServiceImpl{
+		logger:                         logger,
+		webhookRepository:              webhookRepository,
+		notificationSettingsRepository: notificationSettingsRepository,
+	}
+}
+
+func (impl *WebhookNotificationServiceImpl) SaveOrEditNotificationConfig(channelReq []WebhookConfigDto, userId int32) ([]int, error) {
+	var responseIds []int
+	for _, c := range channelReq {
+		if _, err := client.ParseWebhookPayloadTemplate(c.Payload); err != nil {
+			return []int{}, fmt.Errorf("invalid payload template of webhook %s: %s", c.ConfigName, err.Error())
+		}
+	}
+	webhookConfigs := buildWebhookNewConfigs(channelReq, userId)
+	for _, config := range webhookConfigs {
+		if config.Id != 0 {
+			model, err := impl.webhookRepository.FindOne(config.Id)
+			if err != nil && !util.IsErrNoRows(err) {
+				impl.logger.Errorw("err while fetching webhook config", "err", err)
+				return []int{}, err
+			}
+			impl.buildConfigUpdateModel(config, model, userId)
+			model, uErr := impl.webhookRepository.UpdateWebhookConfig(model)
+			if uErr != nil {
+				impl.logger.Errorw("err while updating webhook config", "err", uErr)
+				return []int{}, uErr
+			}
+		} else {
+			_, iErr := impl.webhookRepository.SaveWebhookConfig(config)
+			if iErr != nil {
+				impl.logger.Errorw("err while inserting webhook config", "err", iErr)
+				return []int{}, iErr
+			}
+		}
+		responseIds = append(responseIds, config.Id)
+	}
+	return responseIds, nil
+}
+
+func (impl *WebhookNotificationServiceImpl) FetchWebhookNotificationConfigById(id int) (*WebhookConfigDto, error) {
+	webhookConfig, err := impl.webhookRepository.FindOne(id)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("cannot find webhook config", "err", err, "id", id)
+		return nil, err
+	}
+	webhookConfigDto := impl.adaptWebhookConfig(*webhookConfig)
+	return &webhookConfigDto, nil
+}
+
+func (impl *WebhookNotificationServiceImpl) FetchAllWebhookNotificationConfig() ([]*WebhookConfigDto, error) {
+	var responseDto []*WebhookConfigDto
+	webhookConfigs, err := impl.webhookRepository.FindAll()
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("cannot find all webhook config", "err", err)
+		return []*WebhookConfigDto{}, err
+	}
+	for _, webhookConfig := range webhookConfigs {
+		webhookConfigDto := impl.adaptWebhookConfig(webhookConfig)
+		responseDto = append(responseDto, &webhookConfigDto)
+	}
+	if responseDto == nil {
+		responseDto = make([]*WebhookConfigDto, 0)
+	}
+	return responseDto, nil
+}
+
+func (impl *WebhookNotificationServiceImpl) FetchAllWebhookNotificationConfigAutocomplete() ([]*NotificationChannelAutoResponse, error) {
+	var responseDto []*NotificationChannelAutoResponse
+	webhookConfigs, err := impl.webhookRepository.FindAll()
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("cannot find all webhook config", "err", err)
+		return []*NotificationChannelAutoResponse{}, err
+	}
+	for _, webhookConfig := range webhookConfigs {
+		webhookConfigDto := &NotificationChannelAutoResponse{
+			Id:         webhookConfig.Id,
+			ConfigName: webhookConfig.ConfigName,
+			TeamId:     webhookConfig.TeamId,
+		}
+		responseDto = append(responseDto, webhookConfigDto)
+	}
+	return responseDto, nil
+}
+
+func (impl *WebhookNotificationServiceImpl) adaptWebhookConfig(webhookConfig repository.WebhookConfig) WebhookConfigDto {
+	webhookConfigDto := WebhookConfigDto{
+		OwnerId:     webhookConfig.OwnerId,
+		TeamId:      webhookConfig.TeamId,
+		WebhookUrl:  webhookConfig.WebHookUrl,
+		ConfigName:  webhookConfig.ConfigName,
+		Header:      webhookConfig.Header,
+		Payload:     webhookConfig.Payload,
+		Description: webhookConfig.Description,
+		Id:          webhookConfig.Id,
+	}
+	if len(webhookConfig.Secret) > 0 {
+		webhookConfigDto.Secret = maskedWebhookSecret
+	}
+	return webhookConfigDto
+}
+
+func buildWebhookNewConfigs(webhookReq []WebhookConfigDto, userId int32) []*repository.WebhookConfig {
+	var webhookConfigs []*repository.WebhookConfig
+	for _, c := range webhookReq {
+		webhookConfig := &repository.WebhookConfig{
+			Id:          c.Id,
+			ConfigName:  c.ConfigName,
+			WebHookUrl:  c.WebhookUrl,
+			Header:      c.Header,
+			Payload:     c.Payload,
+			Secret:      c.Secret,
+			Description: c.Description,
+			AuditLog: sql.AuditLog{
+				CreatedBy: userId,
+				CreatedOn: time.Now(),
+				UpdatedOn: time.Now(),
+				UpdatedBy: userId,
+			},
+		}
+		if c.TeamId != 0 {
+			webhookConfig.TeamId = c.TeamId
+		} else {
+			webhookConfig.OwnerId = userId
+		}
+		webhookConfigs = append(webhookConfigs, webhookConfig)
+	}
+	return webhookConfigs
+}
+
+func (impl *WebhookNotificationServiceImpl) buildConfigUpdateModel(webhookConfig *repository.WebhookConfig, model *repository.WebhookConfig, userId int32) {
+	model.WebHookUrl = webhookConfig.WebHookUrl
+	model.ConfigName = webhookConfig.ConfigName
+	model.Header = webhookConfig.Header
+	model.Payload = webhookConfig.Payload
+	if webhookConfig.Secret != maskedWebhookSecret {
+		model.Secret = webhookConfig.Secret
+	}
+	model.Description = webhookConfig.Description
+	if webhookConfig.TeamId != 0 {
+		model.TeamId = webhookConfig.TeamId
+	} else {
+		model.OwnerId = webhookConfig.OwnerId
+	}
+	model.UpdatedOn = time.Now()
+	model.UpdatedBy = userId
+}
+
+func (impl *WebhookNotificationServiceImpl) DeleteNotificationConfig(deleteReq *WebhookConfigDto, userId int32) error {
+	existingConfig, err := impl.webhookRepository.FindOne(deleteReq.Id)
+	if err != nil {
+		impl.logger.Errorw("No matching entry found for delete", "err", err, "id", deleteReq.Id)
+		return err
+	}
+	notifications, err := impl.notificationSettingsRepository.FindNotificationSettingsByConfigIdAndConfigType(deleteReq.Id, WEBHOOK_CONFIG_TYPE)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in deleting webhook config", "config", deleteReq)
+		return err
+	}
+	if len(notifications) > 0 {
+		impl.logger.Errorw("found notifications using this config, cannot delete", "config", deleteReq)
+		return fmt.Errorf(" Please delete all notifications using this config before deleting")
+	}
+
+	existingConfig.UpdatedOn = time.Now()
+	existingConfig.UpdatedBy = userId
+	err = impl.webhookRepository.MarkWebhookConfigDeleted(existingConfig)
+	if err != nil {
+		impl.logger.Errorw("error in deleting webhook config", "err", err, "id", existingConfig.Id)
+		return err
+	}
+	return nil
+}
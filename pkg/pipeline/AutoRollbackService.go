@@ -0,0 +1,277 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/client/argocdServer/application"
+	"github.com/devtron-labs/devtron/internal/sql/models"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/chartConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	bean2 "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	repository2 "github.com/devtron-labs/devtron/pkg/pipeline/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/devtron-labs/devtron/util/argo"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+const DefaultAutoRollbackHealthCheckWindowInMinutes = 30
+
+type AutoRollbackService interface {
+	GetPolicy(pipelineId int) (*bean2.AutoRollbackPolicyDto, error)
+	SavePolicy(pipelineId int, policyDto *bean2.AutoRollbackPolicyDto, userId int32) error
+	//HandleDeploymentDegraded rolls the pipeline back to its previous healthy artifact if the given deploy runner
+	//degraded within the health check window of the pipeline's auto rollback policy
+	HandleDeploymentDegraded(cdWorkflowRunnerId int) error
+	HandleDegradedArgoApp(gitHash string) error
+}
+
+type AutoRollbackServiceImpl struct {
+	logger                           *zap.SugaredLogger
+	autoRollbackRepository           repository2.AutoRollbackRepository
+	cdWorkflowRepository             pipelineConfig.CdWorkflowRepository
+	ciArtifactRepository             repository.CiArtifactRepository
+	pipelineOverrideRepository       chartConfig.PipelineOverrideRepository
+	pipelineStatusTimelineRepository pipelineConfig.PipelineStatusTimelineRepository
+	workflowDagExecutor              WorkflowDagExecutor
+	argoUserService                  argo.ArgoUserService
+}
+
+func NewAutoRollbackServiceImpl(logger *zap.SugaredLogger,
+	autoRollbackRepository repository2.AutoRollbackRepository,
+	cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
+	ciArtifactRepository repository.CiArtifactRepository,
+	pipelineOverrideRepository chartConfig.PipelineOverrideRepository,
+	pipelineStatusTimelineRepository pipelineConfig.PipelineStatusTimelineRepository,
+	workflowDagExecutor WorkflowDagExecutor,
+	argoUserService argo.ArgoUserService) *AutoRollbackServiceImpl {
+	return &AutoRollbackServiceImpl{
+		logger:                           logger,
+		autoRollbackRepository:           autoRollbackRepository,
+		cdWorkflowRepository:             cdWorkflowRepository,
+		ciArtifactRepository:             ciArtifactRepository,
+		pipelineOverrideRepository:       pipelineOverrideRepository,
+		pipelineStatusTimelineRepository: pipelineStatusTimelineRepository,
+		workflowDagExecutor:              workflowDagExecutor,
+		argoUserService:                  argoUserService,
+	}
+}
+
+func (impl *AutoRollbackServiceImpl) GetPolicy(pipelineId int) (*bean2.AutoRollbackPolicyDto, error) {
+	policy, err := impl.autoRollbackRepository.FindPolicyByPipelineId(pipelineId)
+	if err != nil && err != pg.ErrNoRows {
+		return nil, err
+	} else if err == pg.ErrNoRows {
+		return nil, nil
+	}
+	return &bean2.AutoRollbackPolicyDto{
+		Enabled:                    policy.Enabled,
+		HealthCheckWindowInMinutes: policy.HealthCheckWindowInMinutes,
+	}, nil
+}
+
+func (impl *AutoRollbackServiceImpl) SavePolicy(pipelineId int, policyDto *bean2.AutoRollbackPolicyDto, userId int32) error {
+	if policyDto == nil {
+		return nil
+	}
+	window := policyDto.HealthCheckWindowInMinutes
+	if window <= 0 {
+		window = DefaultAutoRollbackHealthCheckWindowInMinutes
+	}
+	policy, err := impl.autoRollbackRepository.FindPolicyByPipelineId(pipelineId)
+	if err != nil && err != pg.ErrNoRows {
+		return err
+	} else if err == pg.ErrNoRows {
+		if !policyDto.Enabled {
+			//nothing to opt out of
+			return nil
+		}
+		policy = &repository2.AutoRollbackPolicy{
+			PipelineId:                 pipelineId,
+			Enabled:                    policyDto.Enabled,
+			HealthCheckWindowInMinutes: window,
+			AuditLog:                   sql.AuditLog{CreatedOn: time.Now(), CreatedBy: userId, UpdatedOn: time.Now(), UpdatedBy: userId},
+		}
+		return impl.autoRollbackRepository.SavePolicy(policy)
+	}
+	policy.Enabled = policyDto.Enabled
+	policy.HealthCheckWindowInMinutes = window
+	policy.UpdatedOn = time.Now()
+	policy.UpdatedBy = userId
+	return impl.autoRollbackRepository.UpdatePolicy(policy)
+}
+
+func (impl *AutoRollbackServiceImpl) HandleDegradedArgoApp(gitHash string) error {
+	pipelineOverride, err := impl.pipelineOverrideRepository.FindByPipelineTriggerGitHash(gitHash)
+	if err != nil {
+		impl.logger.Errorw("error in getting pipeline override by git hash", "err", err, "gitHash", gitHash)
+		return err
+	}
+	runner, err := impl.cdWorkflowRepository.FindByWorkflowIdAndRunnerType(pipelineOverride.CdWorkflowId, bean.CD_WORKFLOW_TYPE_DEPLOY)
+	if err != nil {
+		impl.logger.Errorw("error in getting cd workflow runner", "err", err, "cdWorkflowId", pipelineOverride.CdWorkflowId)
+		return err
+	}
+	return impl.HandleDeploymentDegraded(runner.Id)
+}
+
+func (impl *AutoRollbackServiceImpl) HandleDeploymentDegraded(cdWorkflowRunnerId int) error {
+	runner, err := impl.cdWorkflowRepository.FindWorkflowRunnerById(cdWorkflowRunnerId)
+	if err != nil {
+		impl.logger.Errorw("error in getting cd workflow runner", "err", err, "cdWorkflowRunnerId", cdWorkflowRunnerId)
+		return err
+	}
+	if runner.WorkflowType != bean.CD_WORKFLOW_TYPE_DEPLOY || runner.Status != application.Degraded {
+		return nil
+	}
+	pipelineId := runner.CdWorkflow.PipelineId
+	policy, err := impl.autoRollbackRepository.FindPolicyByPipelineId(pipelineId)
+	if err != nil && err != pg.ErrNoRows {
+		return err
+	} else if err == pg.ErrNoRows || !policy.Enabled {
+		return nil
+	}
+	if !IsWithinRollbackWindow(runner.StartedOn, time.Now(), policy.HealthCheckWindowInMinutes) {
+		impl.logger.Infow("deployment degraded after health check window, skipping auto rollback", "cdWorkflowRunnerId", runner.Id, "pipelineId", pipelineId)
+		return nil
+	}
+	//a deployment is rolled back only once, and a rollback deployment is never rolled back again
+	histories, err := impl.autoRollbackRepository.FindHistoryByCdWorkflowRunnerId(runner.Id)
+	if err != nil {
+		return err
+	}
+	if len(histories) > 0 {
+		impl.logger.Infow("auto rollback already handled for this deployment, skipping", "cdWorkflowRunnerId", runner.Id, "pipelineId", pipelineId)
+		return nil
+	}
+	//only the current deployment of the pipeline is rolled back, a newer deployment has already replaced this one
+	latestRunner, err := impl.cdWorkflowRepository.FindLastStatusByPipelineIdAndRunnerType(pipelineId, bean.CD_WORKFLOW_TYPE_DEPLOY)
+	if err != nil {
+		impl.logger.Errorw("error in getting latest deploy runner", "err", err, "pipelineId", pipelineId)
+		return err
+	}
+	if latestRunner.Id != runner.Id {
+		impl.logger.Infow("degraded deployment is not the latest deployment, skipping auto rollback", "cdWorkflowRunnerId", runner.Id, "latestCdWorkflowRunnerId", latestRunner.Id)
+		return nil
+	}
+
+	degradedArtifactId := runner.CdWorkflow.CiArtifactId
+	history := &repository2.AutoRollbackHistory{
+		PipelineId:                 pipelineId,
+		DegradedCdWorkflowRunnerId: runner.Id,
+		DegradedCiArtifactId:       degradedArtifactId,
+		AuditLog:                   sql.AuditLog{CreatedOn: time.Now(), CreatedBy: 1, UpdatedOn: time.Now(), UpdatedBy: 1},
+	}
+	candidates, err := impl.ciArtifactRepository.FetchArtifactForRollback(pipelineId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching artifacts for rollback", "err", err, "pipelineId", pipelineId)
+		return err
+	}
+	healthyArtifactIds, err := impl.cdWorkflowRepository.FindArtifactIdsByPipelineIdAndRunnerStatus(pipelineId, bean.CD_WORKFLOW_TYPE_DEPLOY, []string{application.Healthy})
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching healthy artifacts", "err", err, "pipelineId", pipelineId)
+		return err
+	}
+	rollbackArtifactId := SelectRollbackArtifact(candidates, degradedArtifactId, healthyArtifactIds)
+	if rollbackArtifactId == 0 {
+		history.Status = repository2.AUTO_ROLLBACK_STATUS_FAILED
+		history.Message = "no previously healthy artifact found to roll back to"
+		err = impl.autoRollbackRepository.SaveHistory(history)
+		if err != nil {
+			return err
+		}
+		impl.saveAutoRollbackTimeline(runner.Id, pipelineConfig.TIMELINE_STATUS_AUTO_ROLLBACK_FAILED, "Auto rollback failed: no previously healthy artifact found.")
+		return nil
+	}
+	history.RollbackCiArtifactId = rollbackArtifactId
+	history.Status = repository2.AUTO_ROLLBACK_STATUS_TRIGGERED
+	//history is unique per degraded runner, so concurrent status updates can not trigger two rollbacks
+	err = impl.autoRollbackRepository.SaveHistory(history)
+	if err != nil {
+		return err
+	}
+
+	acdToken, err := impl.argoUserService.GetLatestDevtronArgoCdUserToken()
+	if err != nil {
+		impl.logger.Errorw("error in getting acd token", "err", err)
+	}
+	ctx := context.WithValue(context.Background(), "token", acdToken)
+	overrideRequest := &bean.ValuesOverrideRequest{
+		PipelineId:     pipelineId,
+		AppId:          runner.CdWorkflow.Pipeline.AppId,
+		CiArtifactId:   rollbackArtifactId,
+		CdWorkflowType: bean.CD_WORKFLOW_TYPE_DEPLOY,
+		DeploymentType: models.DEPLOYMENTTYPE_ROLLBACK,
+		UserId:         1,
+	}
+	_, err = impl.workflowDagExecutor.ManualCdTrigger(overrideRequest, ctx)
+	//deploy gates (approval, deployment window, policies) reject the rollback with an error, a rollback without
+	//a deploy runner was never released
+	if err == nil && overrideRequest.CdWorkflowRunnerId == 0 {
+		err = fmt.Errorf("rollback deployment was not triggered")
+	}
+	if err != nil {
+		impl.logger.Errorw("error in triggering auto rollback", "err", err, "pipelineId", pipelineId, "rollbackArtifactId", rollbackArtifactId)
+		history.Status = repository2.AUTO_ROLLBACK_STATUS_FAILED
+		history.Message = err.Error()
+		history.UpdatedOn = time.Now()
+		_ = impl.autoRollbackRepository.UpdateHistory(history)
+		impl.saveAutoRollbackTimeline(runner.Id, pipelineConfig.TIMELINE_STATUS_AUTO_ROLLBACK_FAILED, fmt.Sprintf("Auto rollback failed: %s", err.Error()))
+		return err
+	}
+	history.RollbackCdWorkflowRunnerId = overrideRequest.CdWorkflowRunnerId
+	history.UpdatedOn = time.Now()
+	err = impl.autoRollbackRepository.UpdateHistory(history)
+	if err != nil {
+		return err
+	}
+	impl.saveAutoRollbackTimeline(overrideRequest.CdWorkflowRunnerId, pipelineConfig.TIMELINE_STATUS_AUTO_ROLLBACK, fmt.Sprintf("Auto rollback of degraded deployment %d.", runner.Id))
+	impl.saveAutoRollbackTimeline(runner.Id, pipelineConfig.TIMELINE_STATUS_AUTO_ROLLBACK, "Deployment degraded, auto rollback triggered to previous healthy artifact.")
+	return nil
+}
+
+func (impl *AutoRollbackServiceImpl) saveAutoRollbackTimeline(cdWorkflowRunnerId int, status pipelineConfig.TimelineStatus, statusDetail string) {
+	timeline := &pipelineConfig.PipelineStatusTimeline{
+		CdWorkflowRunnerId: cdWorkflowRunnerId,
+		Status:             status,
+		StatusDetail:       statusDetail,
+		StatusTime:         time.Now(),
+		AuditLog: sql.AuditLog{
+			CreatedBy: 1,
+			CreatedOn: time.Now(),
+			UpdatedBy: 1,
+			UpdatedOn: time.Now(),
+		},
+	}
+	err := impl.pipelineStatusTimelineRepository.SaveTimeline(timeline)
+	if err != nil {
+		impl.logger.Errorw("error in saving auto rollback timeline", "err", err, "timeline", timeline)
+	}
+}
+
+// IsWithinRollbackWindow tells if a deployment triggered at triggeredAt and found degraded at degradedAt is
+// still eligible for auto rollback
+func IsWithinRollbackWindow(triggeredAt time.Time, degradedAt time.Time, windowInMinutes int) bool {
+	if windowInMinutes <= 0 {
+		windowInMinutes = DefaultAutoRollbackHealthCheckWindowInMinutes
+	}
+	return !degradedAt.After(triggeredAt.Add(time.Duration(windowInMinutes) * time.Minute))
+}
+
+// SelectRollbackArtifact picks the most recent previously deployed artifact, other than the degraded one,
+// which has reached healthy state on this pipeline before. candidates are expected newest first.
+func SelectRollbackArtifact(candidates []repository.CiArtifact, degradedArtifactId int, healthyArtifactIds []int) int {
+	healthy := make(map[int]bool)
+	for _, id := range healthyArtifactIds {
+		healthy[id] = true
+	}
+	for _, candidate := range candidates {
+		if candidate.Id != degradedArtifactId && healthy[candidate.Id] {
+			return candidate.Id
+		}
+	}
+	return 0
+}
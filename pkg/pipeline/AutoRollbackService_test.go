@@ -0,0 +1,54 @@
+package pipeline
+
+import (
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"testing"
+	"time"
+)
+
+func TestSelectRollbackArtifact(t *testing.T) {
+	candidates := []repository.CiArtifact{{Id: 9}, {Id: 7}, {Id: 7}, {Id: 5}, {Id: 3}}
+	tests := []struct {
+		name               string
+		candidates         []repository.CiArtifact
+		degradedArtifactId int
+		healthyArtifactIds []int
+		want               int
+	}{
+		{name: "no candidates", candidates: nil, degradedArtifactId: 10, healthyArtifactIds: []int{3}, want: 0},
+		{name: "latest healthy candidate", candidates: candidates, degradedArtifactId: 10, healthyArtifactIds: []int{3, 7, 9}, want: 9},
+		{name: "skip never healthy candidates", candidates: candidates, degradedArtifactId: 10, healthyArtifactIds: []int{5, 3}, want: 5},
+		{name: "skip degraded artifact redeployed earlier", candidates: candidates, degradedArtifactId: 9, healthyArtifactIds: []int{9, 3}, want: 3},
+		{name: "no healthy candidate", candidates: candidates, degradedArtifactId: 10, healthyArtifactIds: []int{10}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SelectRollbackArtifact(tt.candidates, tt.degradedArtifactId, tt.healthyArtifactIds); got != tt.want {
+				t.Errorf("SelectRollbackArtifact() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsWithinRollbackWindow(t *testing.T) {
+	triggeredAt := time.Date(2022, 8, 8, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name            string
+		degradedAt      time.Time
+		windowInMinutes int
+		want            bool
+	}{
+		{name: "inside window", degradedAt: triggeredAt.Add(5 * time.Minute), windowInMinutes: 10, want: true},
+		{name: "at window end", degradedAt: triggeredAt.Add(10 * time.Minute), windowInMinutes: 10, want: true},
+		{name: "after window", degradedAt: triggeredAt.Add(11 * time.Minute), windowInMinutes: 10, want: false},
+		{name: "default window", degradedAt: triggeredAt.Add(20 * time.Minute), windowInMinutes: 0, want: true},
+		{name: "after default window", degradedAt: triggeredAt.Add(31 * time.Minute), windowInMinutes: 0, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsWithinRollbackWindow(triggeredAt, tt.degradedAt, tt.windowInMinutes); got != tt.want {
+				t.Errorf("IsWithinRollbackWindow() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
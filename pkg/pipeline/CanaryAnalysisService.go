@@ -0,0 +1,524 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	application2 "github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/client/argocdServer/application"
+	"github.com/devtron-labs/devtron/internal/sql/repository/chartConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	repository3 "github.com/devtron-labs/devtron/pkg/cluster/repository"
+	bean2 "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	repository2 "github.com/devtron-labs/devtron/pkg/pipeline/repository"
+	"github.com/devtron-labs/devtron/pkg/prometheus"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/devtron-labs/devtron/util/argo"
+	"github.com/go-pg/pg"
+	"github.com/prometheus/common/model"
+	"go.uber.org/zap"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultCanaryErrorRateQuery = `sum(rate(http_requests_total{namespace="$namespace",pod=~"$pods",status=~"5.."}[1m])) / sum(rate(http_requests_total{namespace="$namespace",pod=~"$pods"}[1m]))`
+	DefaultCanaryLatencyQuery   = `histogram_quantile(0.95, sum(rate(http_request_duration_seconds_bucket{namespace="$namespace",pod=~"$pods"}[1m])) by (le))`
+
+	RolloutResourceGroup   = "argoproj.io"
+	RolloutResourceVersion = "v1alpha1"
+	RolloutResourceKind    = "Rollout"
+	RolloutActionResume    = "resume"
+	RolloutActionPromote   = "promote-full"
+	RolloutActionAbort     = "abort"
+)
+
+type CanaryAnalysisService interface {
+	GetPolicy(pipelineId int) (*bean2.CanaryAnalysisPolicyDto, error)
+	SavePolicy(pipelineId int, policyDto *bean2.CanaryAnalysisPolicyDto, userId int32) error
+	//AnalyseCanaryDeployments starts analysis of new canary deployments of pipelines with canary analysis enabled and
+	//analyses the due steps of running analyses, promoting or aborting the canary as per the result
+	AnalyseCanaryDeployments() error
+}
+
+type CanaryAnalysisServiceImpl struct {
+	logger                           *zap.SugaredLogger
+	canaryAnalysisRepository         repository2.CanaryAnalysisRepository
+	cdWorkflowRepository             pipelineConfig.CdWorkflowRepository
+	pipelineRepository               pipelineConfig.PipelineRepository
+	pipelineConfigRepository         chartConfig.PipelineConfigRepository
+	environmentRepository            repository3.EnvironmentRepository
+	pipelineStatusTimelineRepository pipelineConfig.PipelineStatusTimelineRepository
+	application                      application.ServiceClient
+	argoUserService                  argo.ArgoUserService
+}
+
+func NewCanaryAnalysisServiceImpl(logger *zap.SugaredLogger,
+	canaryAnalysisRepository repository2.CanaryAnalysisRepository,
+	cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
+	pipelineRepository pipelineConfig.PipelineRepository,
+	pipelineConfigRepository chartConfig.PipelineConfigRepository,
+	environmentRepository repository3.EnvironmentRepository,
+	pipelineStatusTimelineRepository pipelineConfig.PipelineStatusTimelineRepository,
+	application application.ServiceClient,
+	argoUserService argo.ArgoUserService) *CanaryAnalysisServiceImpl {
+	return &CanaryAnalysisServiceImpl{
+		logger:                           logger,
+		canaryAnalysisRepository:         canaryAnalysisRepository,
+		cdWorkflowRepository:             cdWorkflowRepository,
+		pipelineRepository:               pipelineRepository,
+		pipelineConfigRepository:         pipelineConfigRepository,
+		environmentRepository:            environmentRepository,
+		pipelineStatusTimelineRepository: pipelineStatusTimelineRepository,
+		application:                      application,
+		argoUserService:                  argoUserService,
+	}
+}
+
+// CanaryMetrics are the metrics of either canary or stable pods, a metric is absent if its query returned no data
+type CanaryMetrics struct {
+	ErrorRate    float64
+	HasErrorRate bool
+	Latency      float64
+	HasLatency   bool
+}
+
+func (impl *CanaryAnalysisServiceImpl) GetPolicy(pipelineId int) (*bean2.CanaryAnalysisPolicyDto, error) {
+	policy, err := impl.canaryAnalysisRepository.FindPolicyByPipelineId(pipelineId)
+	if err != nil && err != pg.ErrNoRows {
+		return nil, err
+	} else if err == pg.ErrNoRows {
+		return nil, nil
+	}
+	return impl.buildPolicyDto(policy)
+}
+
+func (impl *CanaryAnalysisServiceImpl) SavePolicy(pipelineId int, policyDto *bean2.CanaryAnalysisPolicyDto, userId int32) error {
+	if policyDto == nil {
+		return nil
+	}
+	err := ValidateCanaryAnalysisPolicy(policyDto)
+	if err != nil {
+		return err
+	}
+	steps, err := json.Marshal(policyDto.Steps)
+	if err != nil {
+		impl.logger.Errorw("error in marshaling canary analysis steps", "err", err, "steps", policyDto.Steps)
+		return err
+	}
+	policy, err := impl.canaryAnalysisRepository.FindPolicyByPipelineId(pipelineId)
+	if err != nil && err != pg.ErrNoRows {
+		return err
+	} else if err == pg.ErrNoRows {
+		if !policyDto.Enabled {
+			//nothing to opt out of
+			return nil
+		}
+		policy = &repository2.CanaryAnalysisPolicy{
+			PipelineId: pipelineId,
+			AuditLog:   sql.AuditLog{CreatedOn: time.Now(), CreatedBy: userId, UpdatedOn: time.Now(), UpdatedBy: userId},
+		}
+	}
+	policy.Enabled = policyDto.Enabled
+	policy.ErrorRateQuery = policyDto.ErrorRateQuery
+	policy.LatencyQuery = policyDto.LatencyQuery
+	policy.MaxErrorRateIncrease = policyDto.MaxErrorRateIncrease
+	policy.MaxLatencyIncreasePercent = policyDto.MaxLatencyIncreasePercent
+	policy.Steps = string(steps)
+	policy.UpdatedOn = time.Now()
+	policy.UpdatedBy = userId
+	if policy.Id == 0 {
+		return impl.canaryAnalysisRepository.SavePolicy(policy)
+	}
+	return impl.canaryAnalysisRepository.UpdatePolicy(policy)
+}
+
+func (impl *CanaryAnalysisServiceImpl) AnalyseCanaryDeployments() error {
+	now := time.Now()
+	err := impl.startNewAnalyses(now)
+	if err != nil {
+		impl.logger.Errorw("error in starting canary analysis of new deployments", "err", err)
+	}
+	runs, err := impl.canaryAnalysisRepository.FindAllRunningRuns()
+	if err != nil {
+		return err
+	}
+	for _, run := range runs {
+		if run.NextAnalysisOn.After(now) {
+			continue
+		}
+		err = impl.analyseRun(run, now)
+		if err != nil {
+			//only log this error and continue for next run, the step is analysed again on next tick
+			impl.logger.Errorw("error in analysing canary deployment", "err", err, "runId", run.Id, "pipelineId", run.PipelineId)
+		}
+	}
+	return nil
+}
+
+func (impl *CanaryAnalysisServiceImpl) startNewAnalyses(now time.Time) error {
+	policies, err := impl.canaryAnalysisRepository.FindAllEnabledPolicies()
+	if err != nil {
+		return err
+	}
+	for _, policy := range policies {
+		policyDto, err := impl.buildPolicyDto(policy)
+		if err != nil || len(policyDto.Steps) == 0 {
+			continue
+		}
+		runner, err := impl.cdWorkflowRepository.FindLastStatusByPipelineIdAndRunnerType(policy.PipelineId, bean.CD_WORKFLOW_TYPE_DEPLOY)
+		if err != nil {
+			if err != pg.ErrNoRows {
+				impl.logger.Errorw("error in getting latest deploy runner", "err", err, "pipelineId", policy.PipelineId)
+			}
+			continue
+		}
+		//deployments triggered before the policy was last changed are not analysed
+		if runner.StartedOn.Before(policy.UpdatedOn) || runner.Status == WorkflowFailed || runner.Status == WorkflowAborted {
+			continue
+		}
+		_, err = impl.canaryAnalysisRepository.FindRunByCdWorkflowRunnerId(runner.Id)
+		if err == nil {
+			continue
+		} else if err != pg.ErrNoRows {
+			return err
+		}
+		strategy, err := impl.pipelineConfigRepository.GetDefaultStrategyByPipelineId(policy.PipelineId)
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error in getting default strategy of pipeline", "err", err, "pipelineId", policy.PipelineId)
+			continue
+		}
+		if err == pg.ErrNoRows || strategy.Strategy != pipelineConfig.DEPLOYMENT_TEMPLATE_CANARY {
+			continue
+		}
+		run := &repository2.CanaryAnalysisRun{
+			PipelineId:         policy.PipelineId,
+			CdWorkflowRunnerId: runner.Id,
+			CurrentStep:        0,
+			Status:             repository2.CANARY_ANALYSIS_STATUS_RUNNING,
+			NextAnalysisOn:     runner.StartedOn.Add(time.Duration(policyDto.Steps[0].IntervalInMinutes) * time.Minute),
+			AuditLog:           sql.AuditLog{CreatedOn: now, CreatedBy: 1, UpdatedOn: now, UpdatedBy: 1},
+		}
+		//run is unique per runner, so concurrent ticks can not start two analyses of a deployment
+		err = impl.canaryAnalysisRepository.SaveRun(run)
+		if err != nil {
+			return err
+		}
+		impl.saveCanaryAnalysisTimeline(runner.Id, pipelineConfig.TIMELINE_STATUS_CANARY_ANALYSIS, fmt.Sprintf("Canary analysis started with %d steps.", len(policyDto.Steps)))
+	}
+	return nil
+}
+
+func (impl *CanaryAnalysisServiceImpl) analyseRun(run *repository2.CanaryAnalysisRun, now time.Time) error {
+	policy, err := impl.canaryAnalysisRepository.FindPolicyByPipelineId(run.PipelineId)
+	if err != nil && err != pg.ErrNoRows {
+		return err
+	}
+	if err == pg.ErrNoRows || !policy.Enabled {
+		return impl.finishRun(run, repository2.CANARY_ANALYSIS_STATUS_CANCELLED, "canary analysis disabled for pipeline", "")
+	}
+	policyDto, err := impl.buildPolicyDto(policy)
+	if err != nil {
+		return err
+	}
+	if run.CurrentStep >= len(policyDto.Steps) {
+		return impl.finishRun(run, repository2.CANARY_ANALYSIS_STATUS_CANCELLED, "canary analysis steps changed for pipeline", "")
+	}
+	//only the current deployment of the pipeline is analysed, a newer deployment has already replaced this one
+	latestRunner, err := impl.cdWorkflowRepository.FindLastStatusByPipelineIdAndRunnerType(run.PipelineId, bean.CD_WORKFLOW_TYPE_DEPLOY)
+	if err != nil {
+		impl.logger.Errorw("error in getting latest deploy runner", "err", err, "pipelineId", run.PipelineId)
+		return err
+	}
+	if latestRunner.Id != run.CdWorkflowRunnerId {
+		return impl.finishRun(run, repository2.CANARY_ANALYSIS_STATUS_CANCELLED, "a newer deployment replaced this deployment", "")
+	}
+	pipeline, err := impl.pipelineRepository.FindById(run.PipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in getting pipeline", "err", err, "pipelineId", run.PipelineId)
+		return err
+	}
+	if !util.IsAcdApp(pipeline.DeploymentAppType) {
+		return impl.finishRun(run, repository2.CANARY_ANALYSIS_STATUS_CANCELLED, "canary analysis is supported only for argo cd deployments", "")
+	}
+	env, err := impl.environmentRepository.FindById(pipeline.EnvironmentId)
+	if err != nil {
+		impl.logger.Errorw("error in getting environment", "err", err, "envId", pipeline.EnvironmentId)
+		return err
+	}
+	if len(env.Cluster.PrometheusEndpoint) == 0 {
+		return impl.finishRun(run, repository2.CANARY_ANALYSIS_STATUS_CANCELLED, "prometheus is not configured for cluster of environment "+env.Name, "Canary analysis cancelled: prometheus is not configured for cluster.")
+	}
+
+	acdToken, err := impl.argoUserService.GetLatestDevtronArgoCdUserToken()
+	if err != nil {
+		impl.logger.Errorw("error in getting acd token", "err", err)
+		return err
+	}
+	ctx := context.WithValue(context.Background(), "token", acdToken)
+	acdAppName := fmt.Sprintf("%s-%s", pipeline.App.AppName, env.Name)
+	resourceTree, err := impl.application.ResourceTree(ctx, &application2.ResourcesQuery{ApplicationName: &acdAppName})
+	if err != nil {
+		impl.logger.Errorw("error in getting resource tree of acd app", "err", err, "appName", acdAppName)
+		return err
+	}
+	var rolloutName string
+	for _, node := range resourceTree.Nodes {
+		if node.Group == RolloutResourceGroup && node.Kind == RolloutResourceKind {
+			rolloutName = node.Name
+			break
+		}
+	}
+	if len(rolloutName) == 0 {
+		return impl.finishRun(run, repository2.CANARY_ANALYSIS_STATUS_CANCELLED, "no rollout found for deployment", "Canary analysis cancelled: no rollout found for deployment.")
+	}
+	var canaryPods, stablePods []string
+	for _, pod := range resourceTree.PodMetadata {
+		if pod.IsNew {
+			canaryPods = append(canaryPods, pod.Name)
+		} else {
+			stablePods = append(stablePods, pod.Name)
+		}
+	}
+
+	step := policyDto.Steps[run.CurrentStep]
+	canaryMetrics, err := impl.queryCanaryMetrics(env.Name, env.Cluster.PrometheusEndpoint, env.Namespace, canaryPods, policyDto)
+	if err != nil {
+		return err
+	}
+	stableMetrics, err := impl.queryCanaryMetrics(env.Name, env.Cluster.PrometheusEndpoint, env.Namespace, stablePods, policyDto)
+	if err != nil {
+		return err
+	}
+	stepName := fmt.Sprintf("%d/%d", run.CurrentStep+1, len(policyDto.Steps))
+	if !canaryMetrics.HasErrorRate && !canaryMetrics.HasLatency {
+		//inconclusive, step is analysed again on next tick till one more interval has passed
+		if now.Before(run.NextAnalysisOn.Add(time.Duration(step.IntervalInMinutes) * time.Minute)) {
+			impl.logger.Infow("no canary metrics found yet, analysing again on next tick", "runId", run.Id, "step", stepName)
+			return nil
+		}
+		message := fmt.Sprintf("no metrics found for canary pods in step %s", stepName)
+		return impl.abortCanary(ctx, run, acdAppName, env.Namespace, rolloutName, message)
+	}
+	passed, message := EvaluateCanaryStep(canaryMetrics, stableMetrics, policyDto.MaxErrorRateIncrease, policyDto.MaxLatencyIncreasePercent)
+	if !passed {
+		return impl.abortCanary(ctx, run, acdAppName, env.Namespace, rolloutName, fmt.Sprintf("step %s failed, %s", stepName, message))
+	}
+	if run.CurrentStep == len(policyDto.Steps)-1 {
+		err = impl.runRolloutAction(ctx, acdAppName, env.Namespace, rolloutName, RolloutActionPromote)
+		if err != nil {
+			return err
+		}
+		return impl.finishRun(run, repository2.CANARY_ANALYSIS_STATUS_PROMOTED, message,
+			fmt.Sprintf("Canary step %s passed, canary promoted: %s.", stepName, message))
+	}
+	err = impl.runRolloutAction(ctx, acdAppName, env.Namespace, rolloutName, RolloutActionResume)
+	if err != nil {
+		return err
+	}
+	run.CurrentStep = run.CurrentStep + 1
+	run.NextAnalysisOn = now.Add(time.Duration(policyDto.Steps[run.CurrentStep].IntervalInMinutes) * time.Minute)
+	run.Message = message
+	run.UpdatedOn = now
+	err = impl.canaryAnalysisRepository.UpdateRun(run)
+	if err != nil {
+		return err
+	}
+	impl.saveCanaryAnalysisTimeline(run.CdWorkflowRunnerId, pipelineConfig.TIMELINE_STATUS_CANARY_STEP_PASSED, fmt.Sprintf("Canary step %s passed: %s.", stepName, message))
+	return nil
+}
+
+func (impl *CanaryAnalysisServiceImpl) abortCanary(ctx context.Context, run *repository2.CanaryAnalysisRun, acdAppName string, namespace string, rolloutName string, message string) error {
+	err := impl.runRolloutAction(ctx, acdAppName, namespace, rolloutName, RolloutActionAbort)
+	if err != nil {
+		return err
+	}
+	return impl.finishRun(run, repository2.CANARY_ANALYSIS_STATUS_ABORTED, message, fmt.Sprintf("Canary aborted: %s.", message))
+}
+
+func (impl *CanaryAnalysisServiceImpl) finishRun(run *repository2.CanaryAnalysisRun, status repository2.CanaryAnalysisStatus, message string, timelineDetail string) error {
+	run.Status = status
+	run.Message = message
+	run.UpdatedOn = time.Now()
+	err := impl.canaryAnalysisRepository.UpdateRun(run)
+	if err != nil {
+		return err
+	}
+	if len(timelineDetail) > 0 {
+		timelineStatus := pipelineConfig.TIMELINE_STATUS_CANARY_ABORTED
+		if status == repository2.CANARY_ANALYSIS_STATUS_PROMOTED {
+			timelineStatus = pipelineConfig.TIMELINE_STATUS_CANARY_PROMOTED
+		}
+		impl.saveCanaryAnalysisTimeline(run.CdWorkflowRunnerId, timelineStatus, timelineDetail)
+	}
+	return nil
+}
+
+func (impl *CanaryAnalysisServiceImpl) runRolloutAction(ctx context.Context, acdAppName string, namespace string, rolloutName string, action string) error {
+	group := RolloutResourceGroup
+	version := RolloutResourceVersion
+	kind := RolloutResourceKind
+	request := &application2.ResourceActionRunRequest{
+		Name:         &acdAppName,
+		Namespace:    &namespace,
+		ResourceName: &rolloutName,
+		Group:        &group,
+		Version:      &version,
+		Kind:         &kind,
+		Action:       &action,
+	}
+	_, err := impl.application.RunResourceAction(ctx, request)
+	if err != nil {
+		impl.logger.Errorw("error in running rollout action", "err", err, "appName", acdAppName, "rollout", rolloutName, "action", action)
+		return err
+	}
+	return nil
+}
+
+func (impl *CanaryAnalysisServiceImpl) queryCanaryMetrics(envName string, prometheusUrl string, namespace string, pods []string, policyDto *bean2.CanaryAnalysisPolicyDto) (*CanaryMetrics, error) {
+	metrics := &CanaryMetrics{}
+	if len(pods) == 0 {
+		return metrics, nil
+	}
+	prometheusAPI, err := prometheus.ContextByEnv(envName, prometheusUrl)
+	if err != nil {
+		impl.logger.Errorw("error in getting prometheus api client:", "error", err)
+		return nil, err
+	}
+	errorRateQuery := policyDto.ErrorRateQuery
+	if len(errorRateQuery) == 0 {
+		errorRateQuery = DefaultCanaryErrorRateQuery
+	}
+	latencyQuery := policyDto.LatencyQuery
+	if len(latencyQuery) == 0 {
+		latencyQuery = DefaultCanaryLatencyQuery
+	}
+	for i, query := range []string{errorRateQuery, latencyQuery} {
+		out, _, err := prometheusAPI.Query(context.Background(), RenderCanaryQuery(query, namespace, pods), time.Now())
+		if err != nil {
+			impl.logger.Errorw("canary metric query failed in prometheus:", "error", err, "query", query)
+			return nil, err
+		}
+		value, found := getSingleSampleValue(out)
+		if i == 0 {
+			metrics.ErrorRate, metrics.HasErrorRate = value, found
+		} else {
+			metrics.Latency, metrics.HasLatency = value, found
+		}
+	}
+	return metrics, nil
+}
+
+func (impl *CanaryAnalysisServiceImpl) buildPolicyDto(policy *repository2.CanaryAnalysisPolicy) (*bean2.CanaryAnalysisPolicyDto, error) {
+	policyDto := &bean2.CanaryAnalysisPolicyDto{
+		Enabled:                   policy.Enabled,
+		ErrorRateQuery:            policy.ErrorRateQuery,
+		LatencyQuery:              policy.LatencyQuery,
+		MaxErrorRateIncrease:      policy.MaxErrorRateIncrease,
+		MaxLatencyIncreasePercent: policy.MaxLatencyIncreasePercent,
+	}
+	if len(policy.Steps) > 0 {
+		err := json.Unmarshal([]byte(policy.Steps), &policyDto.Steps)
+		if err != nil {
+			impl.logger.Errorw("error in unmarshalling canary analysis steps", "err", err, "pipelineId", policy.PipelineId)
+			return nil, err
+		}
+	}
+	return policyDto, nil
+}
+
+func (impl *CanaryAnalysisServiceImpl) saveCanaryAnalysisTimeline(cdWorkflowRunnerId int, status pipelineConfig.TimelineStatus, statusDetail string) {
+	timeline := &pipelineConfig.PipelineStatusTimeline{
+		CdWorkflowRunnerId: cdWorkflowRunnerId,
+		Status:             status,
+		StatusDetail:       statusDetail,
+		StatusTime:         time.Now(),
+		AuditLog: sql.AuditLog{
+			CreatedBy: 1,
+			CreatedOn: time.Now(),
+			UpdatedBy: 1,
+			UpdatedOn: time.Now(),
+		},
+	}
+	err := impl.pipelineStatusTimelineRepository.SaveTimeline(timeline)
+	if err != nil {
+		impl.logger.Errorw("error in saving canary analysis timeline", "err", err, "timeline", timeline)
+	}
+}
+
+func getSingleSampleValue(value model.Value) (float64, bool) {
+	var sampleValue float64
+	switch v := value.(type) {
+	case model.Vector:
+		if len(v) == 0 {
+			return 0, false
+		}
+		sampleValue = float64(v[0].Value)
+	case *model.Scalar:
+		sampleValue = float64(v.Value)
+	default:
+		return 0, false
+	}
+	//division by zero for no requests, no data for this metric
+	if math.IsNaN(sampleValue) || math.IsInf(sampleValue, 0) {
+		return 0, false
+	}
+	return sampleValue, true
+}
+
+// ValidateCanaryAnalysisPolicy checks that an enabled policy has at least one step and every step has a positive interval
+func ValidateCanaryAnalysisPolicy(policyDto *bean2.CanaryAnalysisPolicyDto) error {
+	if !policyDto.Enabled {
+		return nil
+	}
+	if len(policyDto.Steps) == 0 {
+		return fmt.Errorf("at least one canary analysis step is required")
+	}
+	for i, step := range policyDto.Steps {
+		if step == nil || step.IntervalInMinutes <= 0 {
+			return fmt.Errorf("interval of canary analysis step %d must be greater than 0", i+1)
+		}
+	}
+	if policyDto.MaxErrorRateIncrease < 0 || policyDto.MaxLatencyIncreasePercent < 0 {
+		return fmt.Errorf("allowed error rate and latency increase can not be negative")
+	}
+	return nil
+}
+
+// RenderCanaryQuery replaces $namespace and $pods of a promQL query by the namespace and a regex matching exactly the given pods
+func RenderCanaryQuery(query string, namespace string, pods []string) string {
+	quotedPods := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		quotedPods = append(quotedPods, regexp.QuoteMeta(pod))
+	}
+	query = strings.ReplaceAll(query, "$namespace", namespace)
+	return strings.ReplaceAll(query, "$pods", strings.Join(quotedPods, "|"))
+}
+
+// EvaluateCanaryStep compares canary metrics with stable metrics, a metric missing for stable pods is compared with zero
+// error rate, latency is compared only when present for both
+func EvaluateCanaryStep(canary *CanaryMetrics, stable *CanaryMetrics, maxErrorRateIncrease float64, maxLatencyIncreasePercent float64) (bool, string) {
+	var results []string
+	passed := true
+	if canary.HasErrorRate {
+		stableErrorRate := 0.0
+		if stable.HasErrorRate {
+			stableErrorRate = stable.ErrorRate
+		}
+		results = append(results, fmt.Sprintf("error rate canary %.4f stable %.4f", canary.ErrorRate, stableErrorRate))
+		if canary.ErrorRate-stableErrorRate > maxErrorRateIncrease {
+			passed = false
+		}
+	}
+	if canary.HasLatency && stable.HasLatency {
+		results = append(results, fmt.Sprintf("latency canary %.4f stable %.4f", canary.Latency, stable.Latency))
+		if stable.Latency > 0 && (canary.Latency-stable.Latency)/stable.Latency*100 > maxLatencyIncreasePercent {
+			passed = false
+		}
+	}
+	return passed, strings.Join(results, ", ")
+}
@@ -0,0 +1,70 @@
+package pipeline
+
+import (
+	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"testing"
+)
+
+func TestRenderCanaryQuery(t *testing.T) {
+	query := `sum(rate(http_requests_total{namespace="$namespace",pod=~"$pods"}[1m]))`
+	tests := []struct {
+		name string
+		pods []string
+		want string
+	}{
+		{name: "single pod", pods: []string{"app-7d9f-x1"}, want: `sum(rate(http_requests_total{namespace="demo",pod=~"app-7d9f-x1"}[1m]))`},
+		{name: "multiple pods", pods: []string{"app-1", "app-2"}, want: `sum(rate(http_requests_total{namespace="demo",pod=~"app-1|app-2"}[1m]))`},
+		{name: "pod name quoted", pods: []string{"app.1"}, want: `sum(rate(http_requests_total{namespace="demo",pod=~"app\.1"}[1m]))`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderCanaryQuery(query, "demo", tt.pods); got != tt.want {
+				t.Errorf("RenderCanaryQuery() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateCanaryStep(t *testing.T) {
+	tests := []struct {
+		name   string
+		canary *CanaryMetrics
+		stable *CanaryMetrics
+		want   bool
+	}{
+		{name: "healthy canary", canary: &CanaryMetrics{ErrorRate: 0.01, HasErrorRate: true, Latency: 0.2, HasLatency: true}, stable: &CanaryMetrics{ErrorRate: 0.01, HasErrorRate: true, Latency: 0.2, HasLatency: true}, want: true},
+		{name: "error rate increased", canary: &CanaryMetrics{ErrorRate: 0.1, HasErrorRate: true}, stable: &CanaryMetrics{ErrorRate: 0.01, HasErrorRate: true}, want: false},
+		{name: "error rate without stable data", canary: &CanaryMetrics{ErrorRate: 0.06, HasErrorRate: true}, stable: &CanaryMetrics{}, want: false},
+		{name: "latency increased", canary: &CanaryMetrics{Latency: 0.3, HasLatency: true}, stable: &CanaryMetrics{Latency: 0.2, HasLatency: true}, want: false},
+		{name: "latency within limit", canary: &CanaryMetrics{Latency: 0.21, HasLatency: true}, stable: &CanaryMetrics{Latency: 0.2, HasLatency: true}, want: true},
+		{name: "latency without stable data", canary: &CanaryMetrics{Latency: 5, HasLatency: true}, stable: &CanaryMetrics{}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := EvaluateCanaryStep(tt.canary, tt.stable, 0.05, 20); got != tt.want {
+				t.Errorf("EvaluateCanaryStep() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateCanaryAnalysisPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  *bean.CanaryAnalysisPolicyDto
+		wantErr bool
+	}{
+		{name: "disabled policy", policy: &bean.CanaryAnalysisPolicyDto{}, wantErr: false},
+		{name: "no steps", policy: &bean.CanaryAnalysisPolicyDto{Enabled: true}, wantErr: true},
+		{name: "zero interval", policy: &bean.CanaryAnalysisPolicyDto{Enabled: true, Steps: []*bean.CanaryAnalysisStepDto{{IntervalInMinutes: 0}}}, wantErr: true},
+		{name: "negative threshold", policy: &bean.CanaryAnalysisPolicyDto{Enabled: true, MaxErrorRateIncrease: -1, Steps: []*bean.CanaryAnalysisStepDto{{IntervalInMinutes: 5}}}, wantErr: true},
+		{name: "valid policy", policy: &bean.CanaryAnalysisPolicyDto{Enabled: true, Steps: []*bean.CanaryAnalysisStepDto{{IntervalInMinutes: 5}, {IntervalInMinutes: 10}}}, wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateCanaryAnalysisPolicy(tt.policy); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCanaryAnalysisPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
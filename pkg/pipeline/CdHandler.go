@@ -81,6 +81,7 @@ type CdHandlerImpl struct {
 	application                      application.ServiceClient
 	argoUserService                  argo.ArgoUserService
 	deploymentFailureHandler         app.DeploymentFailureHandler
+	autoRollbackService              AutoRollbackService
 }
 
 func NewCdHandlerImpl(Logger *zap.SugaredLogger, cdConfig *CdConfig, userService user.UserService,
@@ -97,7 +98,7 @@ func NewCdHandlerImpl(Logger *zap.SugaredLogger, cdConfig *CdConfig, userService
 	appListingService app.AppListingService, appListingRepository repository.AppListingRepository,
 	pipelineStatusTimelineRepository pipelineConfig.PipelineStatusTimelineRepository,
 	application application.ServiceClient, argoUserService argo.ArgoUserService,
-	deploymentFailureHandler app.DeploymentFailureHandler) *CdHandlerImpl {
+	deploymentFailureHandler app.DeploymentFailureHandler, autoRollbackService AutoRollbackService) *CdHandlerImpl {
 	return &CdHandlerImpl{
 		Logger:                           Logger,
 		cdConfig:                         cdConfig,
@@ -120,6 +121,7 @@ func NewCdHandlerImpl(Logger *zap.SugaredLogger, cdConfig *CdConfig, userService
 		application:                      application,
 		argoUserService:                  argoUserService,
 		deploymentFailureHandler:         deploymentFailureHandler,
+		autoRollbackService:              autoRollbackService,
 	}
 }
 
@@ -134,6 +136,7 @@ func (impl *CdHandlerImpl) CheckArgoAppStatusPeriodicallyAndUpdateInDb(timeForDe
 	var newDeploymentStatuses []repository.DeploymentStatus
 	var newCdWfrs []pipelineConfig.CdWorkflowRunner
 	var timelines []pipelineConfig.PipelineStatusTimeline
+	var degradedCdWfrIds []int
 	for _, deploymentStatus := range deploymentStatuses {
 		timelineStatus, appStatus, statusMessage := impl.GetAppStatusByResourceTreeFetchFromArgo(deploymentStatus.AppName)
 		newDeploymentStatus := deploymentStatus
@@ -151,6 +154,9 @@ func (impl *CdHandlerImpl) CheckArgoAppStatusPeriodicallyAndUpdateInDb(timeForDe
 		}
 		cdWfr.Status = appStatus
 		newCdWfrs = append(newCdWfrs, cdWfr)
+		if appStatus == application.Degraded {
+			degradedCdWfrIds = append(degradedCdWfrIds, cdWfr.Id)
+		}
 		// creating cd pipeline status timeline for degraded app
 		timeline := pipelineConfig.PipelineStatusTimeline{
 			CdWorkflowRunnerId: cdWfr.Id,
@@ -202,6 +208,13 @@ func (impl *CdHandlerImpl) CheckArgoAppStatusPeriodicallyAndUpdateInDb(timeForDe
 		impl.Logger.Errorw("error on db transaction commit for", "err", err)
 		return err
 	}
+	for _, cdWfrId := range degradedCdWfrIds {
+		err = impl.autoRollbackService.HandleDeploymentDegraded(cdWfrId)
+		if err != nil {
+			//only log this error and continue for next degraded deployment
+			impl.Logger.Errorw("error in handling auto rollback for degraded deployment", "cdWfrId", cdWfrId, "err", err)
+		}
+	}
 	return nil
 }
 
@@ -286,6 +299,12 @@ func (impl *CdHandlerImpl) CheckHelmAppStatusPeriodicallyAndUpdateInDb(timeForDe
 				impl.Logger.Errorw("error on handling deployment success event", "cdWf", cdWf, "err", err)
 				return err
 			}
+		} else if cdWf.Status == application.Degraded {
+			err = impl.autoRollbackService.HandleDeploymentDegraded(cdWf.Id)
+			if err != nil {
+				//skip this error and continue for next workflow status
+				impl.Logger.Errorw("error in handling auto rollback for degraded deployment", "cdWf", cdWf, "err", err)
+			}
 		}
 	}
 	return nil
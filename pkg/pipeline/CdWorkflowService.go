@@ -35,6 +35,7 @@ import (
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
 	"github.com/devtron-labs/devtron/pkg/app"
 	bean2 "github.com/devtron-labs/devtron/pkg/bean"
+	bean3 "github.com/devtron-labs/devtron/pkg/pipeline/bean"
 	"go.uber.org/zap"
 	v12 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -54,11 +55,12 @@ type CdWorkflowService interface {
 const CD_WORKFLOW_NAME = "cd"
 
 type CdWorkflowServiceImpl struct {
-	Logger        *zap.SugaredLogger
-	config        *rest.Config
-	cdConfig      *CdConfig
-	appService    app.AppService
-	envRepository repository.EnvironmentRepository
+	Logger               *zap.SugaredLogger
+	config               *rest.Config
+	cdConfig             *CdConfig
+	appService           app.AppService
+	envRepository        repository.EnvironmentRepository
+	pipelineStageService PipelineStageService
 }
 
 type CdWorkflowRequest struct {
@@ -104,14 +106,17 @@ type CdWorkflowRequest struct {
 	DefaultAddressPoolSize     int                               `json:"defaultAddressPoolSize"`
 	DeploymentTriggeredBy      string                            `json:"deploymentTriggeredBy,omitempty"`
 	DeploymentTriggerTime      time.Time                         `json:"deploymentTriggerTime,omitempty"`
+	PrePostDeploySteps         []*bean3.StepObject               `json:"prePostDeploySteps"`
+	RefPlugins                 []*bean3.RefPluginObject          `json:"refPlugins"`
 }
 
 const PRE = "PRE"
 const POST = "POST"
 
-func NewCdWorkflowServiceImpl(Logger *zap.SugaredLogger, envRepository repository.EnvironmentRepository, cdConfig *CdConfig, appService app.AppService) *CdWorkflowServiceImpl {
+func NewCdWorkflowServiceImpl(Logger *zap.SugaredLogger, envRepository repository.EnvironmentRepository, cdConfig *CdConfig, appService app.AppService,
+	pipelineStageService PipelineStageService) *CdWorkflowServiceImpl {
 	return &CdWorkflowServiceImpl{Logger: Logger, config: cdConfig.ClusterConfig,
-		cdConfig: cdConfig, appService: appService, envRepository: envRepository}
+		cdConfig: cdConfig, appService: appService, envRepository: envRepository, pipelineStageService: pipelineStageService}
 }
 
 func (impl *CdWorkflowServiceImpl) SubmitWorkflow(workflowRequest *CdWorkflowRequest, pipeline *pipelineConfig.Pipeline, env *repository.Environment) (*v1alpha1.Workflow, error) {
@@ -123,6 +128,17 @@ func (impl *CdWorkflowServiceImpl) SubmitWorkflow(workflowRequest *CdWorkflowReq
 	if (workflowRequest.StageType == PRE && pipeline.RunPreStageInEnv) || (workflowRequest.StageType == POST && pipeline.RunPostStageInEnv) {
 		workflowRequest.IsExtRun = true
 	}
+	preDeploySteps, postDeploySteps, refPluginsData, err := impl.pipelineStageService.BuildPrePostAndRefPluginStepsDataForCdWfRequest(pipeline.Id)
+	if err != nil {
+		impl.Logger.Errorw("error in getting pre, post & refPlugin steps data for cd wf request", "err", err, "cdPipelineId", pipeline.Id)
+		return nil, err
+	}
+	if workflowRequest.StageType == PRE {
+		workflowRequest.PrePostDeploySteps = preDeploySteps
+	} else if workflowRequest.StageType == POST {
+		workflowRequest.PrePostDeploySteps = postDeploySteps
+	}
+	workflowRequest.RefPlugins = refPluginsData
 	ciCdTriggerEvent := CiCdTriggerEvent{
 		CdRequest: workflowRequest,
 	}
@@ -0,0 +1,204 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/bean"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+)
+
+const MaxCiBuildMatrixLegs = 16
+
+// docker image tags allow 128 chars
+const maxDockerImageTagLength = 128
+
+var ciBuildMatrixLegNameInvalidChars = regexp.MustCompile("[^a-z0-9.]+")
+
+// CiBuildMatrixLeg is one combination of the matrix, built in its own workflow node
+type CiBuildMatrixLeg struct {
+	Name           string
+	Platform       string
+	BuildArgs      map[string]string
+	DockerfilePath string
+}
+
+// ValidateCiPipelineBuildMatrix checks the build matrix of a ci pipeline, a manifest list can only combine images of
+// different platforms so legs of a manifest list matrix may only differ in platform
+func ValidateCiPipelineBuildMatrix(ciPipeline *bean.CiPipeline) error {
+	if ciPipeline == nil || ciPipeline.BuildMatrix == nil {
+		return nil
+	}
+	matrix := ciPipeline.BuildMatrix
+	if ciPipeline.IsExternal || ciPipeline.ParentCiPipeline > 0 {
+		return ciBuildMatrixError("build matrix is not supported for external or linked ci pipeline")
+	}
+	if len(matrix.Platforms) == 0 && len(matrix.BuildArgs) == 0 && len(matrix.DockerfilePaths) == 0 {
+		return ciBuildMatrixError("build matrix needs at least one platform, build args or dockerfile path")
+	}
+	if legs := len(ExpandCiBuildMatrix(matrix)); legs > MaxCiBuildMatrixLegs {
+		return ciBuildMatrixError(fmt.Sprintf("build matrix has %d legs, at most %d legs are supported", legs, MaxCiBuildMatrixLegs))
+	}
+	if hasDuplicates(matrix.Platforms) || hasDuplicates(matrix.DockerfilePaths) {
+		return ciBuildMatrixError("build matrix platforms and dockerfile paths must be unique")
+	}
+	switch GetCiBuildMatrixArtifactMode(matrix) {
+	case bean.CI_BUILD_MATRIX_MANIFEST_LIST:
+		if len(matrix.Platforms) == 0 || len(matrix.BuildArgs) > 1 || len(matrix.DockerfilePaths) > 1 {
+			return ciBuildMatrixError("manifest list build matrix can only vary platforms, use SEPARATE_TAGS to vary build args or dockerfile paths")
+		}
+	case bean.CI_BUILD_MATRIX_SEPARATE_TAGS:
+	default:
+		return ciBuildMatrixError(fmt.Sprintf("invalid build matrix artifact mode %s", matrix.ArtifactMode))
+	}
+	return nil
+}
+
+func ciBuildMatrixError(message string) error {
+	return &util.ApiError{
+		HttpStatusCode:  http.StatusBadRequest,
+		InternalMessage: message,
+		UserMessage:     message,
+	}
+}
+
+func hasDuplicates(values []string) bool {
+	seen := make(map[string]bool)
+	for _, value := range values {
+		if seen[value] {
+			return true
+		}
+		seen[value] = true
+	}
+	return false
+}
+
+// GetCiBuildMatrixArtifactMode defaults to a manifest list for multi platform matrices and separate tags otherwise
+func GetCiBuildMatrixArtifactMode(matrix *bean.CiBuildMatrix) bean.CiBuildMatrixArtifactMode {
+	if len(matrix.ArtifactMode) > 0 {
+		return matrix.ArtifactMode
+	}
+	if len(matrix.Platforms) > 1 && len(matrix.BuildArgs) <= 1 && len(matrix.DockerfilePaths) <= 1 {
+		return bean.CI_BUILD_MATRIX_MANIFEST_LIST
+	}
+	return bean.CI_BUILD_MATRIX_SEPARATE_TAGS
+}
+
+// ExpandCiBuildMatrix returns one leg for every combination of platform, build args and dockerfile path, leg names
+// only contain the dimensions which vary so that they stay short enough for image tags
+func ExpandCiBuildMatrix(matrix *bean.CiBuildMatrix) []*CiBuildMatrixLeg {
+	platforms := matrix.Platforms
+	if len(platforms) == 0 {
+		platforms = []string{""}
+	}
+	buildArgs := matrix.BuildArgs
+	if len(buildArgs) == 0 {
+		buildArgs = []map[string]string{nil}
+	}
+	dockerfilePaths := matrix.DockerfilePaths
+	if len(dockerfilePaths) == 0 {
+		dockerfilePaths = []string{""}
+	}
+	var legs []*CiBuildMatrixLeg
+	for _, platform := range platforms {
+		for argsIndex, args := range buildArgs {
+			for _, dockerfilePath := range dockerfilePaths {
+				var nameParts []string
+				if len(platforms) > 1 {
+					nameParts = append(nameParts, platform)
+				}
+				if len(buildArgs) > 1 {
+					nameParts = append(nameParts, fmt.Sprintf("args%d", argsIndex+1))
+				}
+				if len(dockerfilePaths) > 1 {
+					nameParts = append(nameParts, path.Base(dockerfilePath))
+				}
+				if len(nameParts) == 0 {
+					nameParts = append(nameParts, platform, path.Base(dockerfilePath))
+				}
+				legs = append(legs, &CiBuildMatrixLeg{
+					Name:           sanitizeCiBuildMatrixLegName(strings.Join(nameParts, "-")),
+					Platform:       platform,
+					BuildArgs:      args,
+					DockerfilePath: dockerfilePath,
+				})
+			}
+		}
+	}
+	for i, leg := range legs {
+		if len(leg.Name) == 0 {
+			leg.Name = fmt.Sprintf("leg-%d", i+1)
+		}
+	}
+	return legs
+}
+
+func sanitizeCiBuildMatrixLegName(name string) string {
+	name = ciBuildMatrixLegNameInvalidChars.ReplaceAllString(strings.ToLower(name), "-")
+	return strings.Trim(name, "-.")
+}
+
+// BuildCiBuildMatrixLegImageTag suffixes the image tag of the build with the leg name
+func BuildCiBuildMatrixLegImageTag(imageTag string, legName string) string {
+	legImageTag := legName
+	if len(imageTag) > 0 {
+		legImageTag = imageTag + "-" + legName
+	}
+	if len(legImageTag) > maxDockerImageTagLength {
+		legImageTag = legImageTag[:maxDockerImageTagLength]
+	}
+	return legImageTag
+}
+
+// BuildDockerImage returns the image reference pushed for a registry url, repository and tag
+func BuildDockerImage(registryUrl string, repository string, tag string) string {
+	return GetDockerRegistryHost(registryUrl) + "/" + repository + ":" + tag
+}
+
+func GetDockerRegistryHost(registryUrl string) string {
+	host := strings.TrimPrefix(strings.TrimPrefix(registryUrl, "https://"), "http://")
+	return strings.TrimSuffix(host, "/")
+}
+
+// BuildManifestListScript returns the script of the workflow node combining the platform images of the legs in one
+// manifest list
+func BuildManifestListScript(registryHost string, manifestImage string, legImages []string, login bool) string {
+	var script strings.Builder
+	script.WriteString("set -e\n")
+	if login {
+		script.WriteString(fmt.Sprintf("crane auth login %q -u \"$REGISTRY_USERNAME\" -p \"$REGISTRY_PASSWORD\"\n", registryHost))
+	}
+	script.WriteString(fmt.Sprintf("crane index append -t %q", manifestImage))
+	for _, legImage := range legImages {
+		script.WriteString(fmt.Sprintf(" -m %q", legImage))
+	}
+	script.WriteString("\n")
+	return script.String()
+}
+
+func GetCiBuildMatrix(ciPipeline *pipelineConfig.CiPipeline) (*bean.CiBuildMatrix, error) {
+	if ciPipeline == nil || len(ciPipeline.BuildMatrix) == 0 {
+		return nil, nil
+	}
+	matrix := &bean.CiBuildMatrix{}
+	err := json.Unmarshal([]byte(ciPipeline.BuildMatrix), matrix)
+	if err != nil {
+		return nil, err
+	}
+	return matrix, nil
+}
+
+func getCiBuildMatrixJson(matrix *bean.CiBuildMatrix) (string, error) {
+	if matrix == nil {
+		return "", nil
+	}
+	matrixJson, err := json.Marshal(matrix)
+	if err != nil {
+		return "", err
+	}
+	return string(matrixJson), nil
+}
@@ -0,0 +1,104 @@
+package pipeline
+
+import (
+	"github.com/devtron-labs/devtron/pkg/bean"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExpandCiBuildMatrix(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix *bean.CiBuildMatrix
+		want   []string
+	}{
+		{name: "platforms only", matrix: &bean.CiBuildMatrix{Platforms: []string{"linux/amd64", "linux/arm64"}}, want: []string{"linux-amd64", "linux-arm64"}},
+		{name: "single platform", matrix: &bean.CiBuildMatrix{Platforms: []string{"linux/arm64"}}, want: []string{"linux-arm64"}},
+		{
+			name:   "platforms and build args",
+			matrix: &bean.CiBuildMatrix{Platforms: []string{"linux/amd64", "linux/arm64"}, BuildArgs: []map[string]string{{"A": "1"}, {"A": "2"}}},
+			want:   []string{"linux-amd64-args1", "linux-amd64-args2", "linux-arm64-args1", "linux-arm64-args2"},
+		},
+		{name: "dockerfile paths", matrix: &bean.CiBuildMatrix{DockerfilePaths: []string{"build/Dockerfile.alpine", "build/Dockerfile.Debian"}}, want: []string{"dockerfile.alpine", "dockerfile.debian"}},
+		{name: "build args only", matrix: &bean.CiBuildMatrix{BuildArgs: []map[string]string{{"A": "1"}}}, want: []string{"leg-1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, leg := range ExpandCiBuildMatrix(tt.matrix) {
+				got = append(got, leg.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExpandCiBuildMatrix() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateCiPipelineBuildMatrix(t *testing.T) {
+	tests := []struct {
+		name       string
+		ciPipeline *bean.CiPipeline
+		wantErr    bool
+	}{
+		{name: "no matrix", ciPipeline: &bean.CiPipeline{}, wantErr: false},
+		{name: "manifest list", ciPipeline: &bean.CiPipeline{BuildMatrix: &bean.CiBuildMatrix{Platforms: []string{"linux/amd64", "linux/arm64"}}}, wantErr: false},
+		{name: "empty matrix", ciPipeline: &bean.CiPipeline{BuildMatrix: &bean.CiBuildMatrix{}}, wantErr: true},
+		{name: "linked pipeline", ciPipeline: &bean.CiPipeline{ParentCiPipeline: 2, BuildMatrix: &bean.CiBuildMatrix{Platforms: []string{"linux/amd64"}}}, wantErr: true},
+		{name: "duplicate platforms", ciPipeline: &bean.CiPipeline{BuildMatrix: &bean.CiBuildMatrix{Platforms: []string{"linux/amd64", "linux/amd64"}}}, wantErr: true},
+		{
+			name:       "manifest list with build args",
+			ciPipeline: &bean.CiPipeline{BuildMatrix: &bean.CiBuildMatrix{Platforms: []string{"linux/amd64"}, BuildArgs: []map[string]string{{"A": "1"}, {"A": "2"}}, ArtifactMode: bean.CI_BUILD_MATRIX_MANIFEST_LIST}},
+			wantErr:    true,
+		},
+		{
+			name:       "separate tags with build args",
+			ciPipeline: &bean.CiPipeline{BuildMatrix: &bean.CiBuildMatrix{Platforms: []string{"linux/amd64"}, BuildArgs: []map[string]string{{"A": "1"}, {"A": "2"}}}},
+			wantErr:    false,
+		},
+		{
+			name:       "too many legs",
+			ciPipeline: &bean.CiPipeline{BuildMatrix: &bean.CiBuildMatrix{Platforms: []string{"linux/amd64", "linux/arm64", "linux/arm/v7"}, BuildArgs: []map[string]string{{"A": "1"}, {"A": "2"}, {"A": "3"}}, DockerfilePaths: []string{"a", "b"}}},
+			wantErr:    true,
+		},
+		{name: "invalid artifact mode", ciPipeline: &bean.CiPipeline{BuildMatrix: &bean.CiBuildMatrix{Platforms: []string{"linux/amd64"}, ArtifactMode: "ZIP"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateCiPipelineBuildMatrix(tt.ciPipeline); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCiPipelineBuildMatrix() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuildCiBuildMatrixLegImageTag(t *testing.T) {
+	tests := []struct {
+		name     string
+		imageTag string
+		legName  string
+		want     string
+	}{
+		{name: "suffix leg name", imageTag: "abc123-4-12", legName: "linux-arm64", want: "abc123-4-12-linux-arm64"},
+		{name: "no image tag", imageTag: "", legName: "linux-arm64", want: "linux-arm64"},
+		{name: "truncate long tag", imageTag: strings.Repeat("a", 127), legName: "linux-arm64", want: strings.Repeat("a", 127) + "-"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BuildCiBuildMatrixLegImageTag(tt.imageTag, tt.legName); got != tt.want {
+				t.Errorf("BuildCiBuildMatrixLegImageTag() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildManifestListScript(t *testing.T) {
+	legImages := []string{"registry.io/app:1-linux-amd64", "registry.io/app:1-linux-arm64"}
+	want := "set -e\n" +
+		"crane auth login \"registry.io\" -u \"$REGISTRY_USERNAME\" -p \"$REGISTRY_PASSWORD\"\n" +
+		"crane index append -t \"registry.io/app:1\" -m \"registry.io/app:1-linux-amd64\" -m \"registry.io/app:1-linux-arm64\"\n"
+	if got := BuildManifestListScript(GetDockerRegistryHost("https://registry.io/"), "registry.io/app:1", legImages, true); got != want {
+		t.Errorf("BuildManifestListScript() = %v, want %v", got, want)
+	}
+}
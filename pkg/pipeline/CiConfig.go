@@ -76,6 +76,11 @@ type CiConfig struct {
 	BlobStorageGcpCredentialJson   string                       `env:"BLOB_STORAGE_GCP_CREDENTIALS_JSON"`
 	BuildLogTTLValue               int                          `json:"BUILD_LOG_TTL_VALUE_IN_SECS" envDefault:"3600"`
 	AzureAccountKey                string                       `env:"AZURE_ACCOUNT_KEY"`
+	CiBuildMatrixManifestImage     string                       `env:"CI_BUILD_MATRIX_MANIFEST_IMAGE" envDefault:"gcr.io/go-containerregistry/crane:debug"`
+	SbomFormat                     string                       `env:"CI_SBOM_FORMAT" envDefault:"cyclonedx-json"` //empty disables sbom generation
+	ImageSigningFormat             string                       `env:"CI_IMAGE_SIGNING_FORMAT"`                    //cosign or notation, empty disables image signing
+	ImageSigningKey                string                       `env:"CI_IMAGE_SIGNING_KEY"`                       //key reference of signing tool, e.g. k8s://devtron-ci/cosign-key
+	CiSbomLocationFormat           string                       `env:"CI_SBOM_LOCATION_FORMAT" envDefault:"%d/sbom.json"`
 	ClusterConfig                  *rest.Config
 	NodeLabel                      map[string]string
 }
@@ -37,6 +37,7 @@ import (
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
 	"github.com/devtron-labs/devtron/internal/util"
 	"github.com/devtron-labs/devtron/pkg/bean"
+	"github.com/devtron-labs/devtron/pkg/secretScan"
 	"github.com/devtron-labs/devtron/pkg/user"
 	util2 "github.com/devtron-labs/devtron/util/event"
 	"github.com/go-pg/pg"
@@ -59,12 +60,16 @@ type CiHandler interface {
 
 	GetBuildHistory(pipelineId int, offset int, size int) ([]WorkflowResponse, error)
 	DownloadCiWorkflowArtifacts(pipelineId int, buildId int) (*os.File, error)
+	//FetchCiWorkflowSbom downloads sbom uploaded by ci runner of the workflow from blob storage
+	FetchCiWorkflowSbom(ciWorkflowId int) ([]byte, error)
 	UpdateWorkflow(workflowStatus v1alpha1.WorkflowStatus) (int, error)
 
 	FetchCiStatusForTriggerView(appId int) ([]*pipelineConfig.CiWorkflowStatus, error)
 	RefreshMaterialByCiPipelineMaterialId(gitMaterialId int) (refreshRes *gitSensor.RefreshGitMaterialResponse, err error)
 	FetchMaterialInfoByArtifactId(ciArtifactId int) (*GitTriggerInfoResponse, error)
 	WriteToCreateTestSuites(pipelineId int, buildId int, triggeredBy int)
+	//TriggerScheduledCiPipelines builds the latest commit of every ci pipeline whose cron schedule fell in (from, to]
+	TriggerScheduledCiPipelines(from time.Time, to time.Time) error
 }
 
 type CiHandlerImpl struct {
@@ -82,12 +87,14 @@ type CiHandlerImpl struct {
 	eventFactory                 client.EventFactory
 	ciPipelineRepository         pipelineConfig.CiPipelineRepository
 	appListingRepository         repository.AppListingRepository
+	secretScanService            secretScan.SecretScanService
 }
 
 func NewCiHandlerImpl(Logger *zap.SugaredLogger, ciService CiService, ciPipelineMaterialRepository pipelineConfig.CiPipelineMaterialRepository,
 	gitSensorClient gitSensor.GitSensorClient, ciWorkflowRepository pipelineConfig.CiWorkflowRepository, workflowService WorkflowService,
 	ciLogService CiLogService, ciConfig *CiConfig, ciArtifactRepository repository.CiArtifactRepository, userService user.UserService, eventClient client.EventClient,
-	eventFactory client.EventFactory, ciPipelineRepository pipelineConfig.CiPipelineRepository, appListingRepository repository.AppListingRepository) *CiHandlerImpl {
+	eventFactory client.EventFactory, ciPipelineRepository pipelineConfig.CiPipelineRepository, appListingRepository repository.AppListingRepository,
+	secretScanService secretScan.SecretScanService) *CiHandlerImpl {
 	return &CiHandlerImpl{
 		Logger:                       Logger,
 		ciService:                    ciService,
@@ -103,6 +110,7 @@ func NewCiHandlerImpl(Logger *zap.SugaredLogger, ciService CiService, ciPipeline
 		eventFactory:                 eventFactory,
 		ciPipelineRepository:         ciPipelineRepository,
 		appListingRepository:         appListingRepository,
+		secretScanService:            secretScanService,
 	}
 }
 
@@ -143,6 +151,8 @@ type WorkflowResponse struct {
 	TriggeredByEmail   string                           `json:"triggeredByEmail"`
 	Stage              string                           `json:"stage"`
 	ArtifactId         int                              `json:"artifactId"`
+	MatrixLeg          string                           `json:"matrixLeg,omitempty"`
+	MatrixLegs         []WorkflowResponse               `json:"matrixLegs,omitempty"`
 }
 
 type GitTriggerInfoResponse struct {
@@ -353,6 +363,14 @@ func (impl *CiHandlerImpl) GetBuildHistory(pipelineId int, offset int, size int)
 		impl.Logger.Errorw("err", "err", err)
 		return nil, err
 	}
+	var workflowIds []int
+	for _, w := range workFlows {
+		workflowIds = append(workflowIds, w.Id)
+	}
+	matrixLegs, err := impl.getMatrixLegResponses(workflowIds)
+	if err != nil {
+		return nil, err
+	}
 	var ciWorkLowResponses []WorkflowResponse
 	for _, w := range workFlows {
 		wfResponse := WorkflowResponse{
@@ -373,12 +391,43 @@ func (impl *CiHandlerImpl) GetBuildHistory(pipelineId int, offset int, size int)
 			TriggeredByEmail:   w.EmailId,
 			ArtifactId:         w.CiArtifactId,
 			BlobStorageEnabled: w.BlobStorageEnabled,
+			MatrixLegs:         matrixLegs[w.Id],
 		}
 		ciWorkLowResponses = append(ciWorkLowResponses, wfResponse)
 	}
 	return ciWorkLowResponses, nil
 }
 
+func (impl *CiHandlerImpl) getMatrixLegResponses(parentWorkflowIds []int) (map[int][]WorkflowResponse, error) {
+	legWorkflows, err := impl.ciWorkflowRepository.FindMatrixLegsByParentIds(parentWorkflowIds)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.Logger.Errorw("error in getting matrix leg workflows", "err", err, "parentWorkflowIds", parentWorkflowIds)
+		return nil, err
+	}
+	matrixLegs := make(map[int][]WorkflowResponse)
+	for _, w := range legWorkflows {
+		matrixLegs[w.ParentCiWorkflowId] = append(matrixLegs[w.ParentCiWorkflowId], WorkflowResponse{
+			Id:                 w.Id,
+			Name:               w.Name,
+			Status:             w.Status,
+			PodStatus:          w.PodStatus,
+			Message:            w.Message,
+			StartedOn:          w.StartedOn,
+			FinishedOn:         w.FinishedOn,
+			CiPipelineId:       w.CiPipelineId,
+			Namespace:          w.Namespace,
+			LogLocation:        w.LogFilePath,
+			Artifact:           w.Image,
+			ArtifactId:         w.CiArtifactId,
+			TriggeredBy:        w.TriggeredBy,
+			TriggeredByEmail:   w.EmailId,
+			BlobStorageEnabled: w.BlobStorageEnabled,
+			MatrixLeg:          w.MatrixLeg,
+		})
+	}
+	return matrixLegs, nil
+}
+
 func (impl *CiHandlerImpl) CancelBuild(workflowId int) (int, error) {
 	workflow, err := impl.ciWorkflowRepository.FindById(workflowId)
 	if err != nil {
@@ -408,6 +457,23 @@ func (impl *CiHandlerImpl) CancelBuild(workflowId int) (int, error) {
 		impl.Logger.Errorw("cannot update deleted workflow status, but wf deleted", "err", err)
 		return 0, err
 	}
+	//legs of a matrix build are terminated along with the workflow
+	legWorkflows, err := impl.ciWorkflowRepository.FindByParentCiWorkflowId(workflow.Id)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.Logger.Errorw("cannot get matrix leg workflows", "err", err, "workflowId", workflow.Id)
+		return 0, err
+	}
+	for _, legWorkflow := range legWorkflows {
+		if legWorkflow.Status == string(v1alpha1.NodeSucceeded) || legWorkflow.Status == string(v1alpha1.NodeFailed) || legWorkflow.Status == string(v1alpha1.NodeError) {
+			continue
+		}
+		legWorkflow.Status = WorkflowCancel
+		err = impl.ciWorkflowRepository.UpdateWorkFlow(legWorkflow)
+		if err != nil {
+			impl.Logger.Errorw("cannot update cancelled matrix leg workflow status", "err", err, "legWorkflowId", legWorkflow.Id)
+			return 0, err
+		}
+	}
 	return workflow.Id, nil
 }
 
@@ -470,7 +536,13 @@ func (impl *CiHandlerImpl) FetchWorkflowDetails(appId int, pipelineId int, build
 		TriggeredBy:        workflow.TriggeredBy,
 		TriggeredByEmail:   triggeredByUser.EmailId,
 		Artifact:           ciArtifact.Image,
+		MatrixLeg:          workflow.MatrixLeg,
 	}
+	matrixLegs, err := impl.getMatrixLegResponses([]int{workflow.Id})
+	if err != nil {
+		return WorkflowResponse{}, err
+	}
+	workflowResponse.MatrixLegs = matrixLegs[workflow.Id]
 	return workflowResponse, nil
 }
 
@@ -637,6 +709,61 @@ func (impl *CiHandlerImpl) DownloadCiWorkflowArtifacts(pipelineId int, buildId i
 	return file, nil
 }
 
+func (impl *CiHandlerImpl) FetchCiWorkflowSbom(ciWorkflowId int) ([]byte, error) {
+	ciWorkflow, err := impl.ciWorkflowRepository.FindById(ciWorkflowId)
+	if err != nil {
+		impl.Logger.Errorw("unable to fetch ciWorkflow", "err", err, "ciWorkflowId", ciWorkflowId)
+		return nil, err
+	}
+	if !ciWorkflow.BlobStorageEnabled {
+		return nil, errors.New("sbom-not-stored-in-repository")
+	}
+	ciConfig, err := impl.ciWorkflowRepository.FindConfigByPipelineId(ciWorkflow.CiPipelineId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.Logger.Errorw("unable to fetch ciConfig", "err", err)
+		return nil, err
+	}
+	if ciConfig.LogsBucket == "" {
+		ciConfig.LogsBucket = impl.ciConfig.DefaultBuildLogsBucket
+	}
+	if ciConfig.CiCacheRegion == "" {
+		ciConfig.CiCacheRegion = impl.ciConfig.DefaultCacheBucketRegion
+	}
+	item := strconv.Itoa(ciWorkflow.Id) + "-sbom.json"
+	blobStorageService := blob_storage.NewBlobStorageServiceImpl(nil)
+	request := &blob_storage.BlobStorageRequest{
+		StorageType:    impl.ciConfig.CloudProvider,
+		SourceKey:      BuildSbomLocation(impl.ciConfig, ciWorkflow.Id),
+		DestinationKey: item,
+		AzureBlobBaseConfig: &blob_storage.AzureBlobBaseConfig{
+			Enabled:           impl.ciConfig.CloudProvider == BLOB_STORAGE_AZURE,
+			AccountName:       impl.ciConfig.AzureAccountName,
+			BlobContainerName: impl.ciConfig.AzureBlobContainerCiLog,
+			AccountKey:        impl.ciConfig.AzureAccountKey,
+		},
+		AwsS3BaseConfig: &blob_storage.AwsS3BaseConfig{
+			AccessKey:         impl.ciConfig.BlobStorageS3AccessKey,
+			Passkey:           impl.ciConfig.BlobStorageS3SecretKey,
+			EndpointUrl:       impl.ciConfig.BlobStorageS3Endpoint,
+			IsInSecure:        impl.ciConfig.BlobStorageS3EndpointInsecure,
+			BucketName:        ciConfig.LogsBucket,
+			Region:            ciConfig.CiCacheRegion,
+			VersioningEnabled: impl.ciConfig.BlobStorageS3BucketVersioned,
+		},
+		GcpBlobBaseConfig: &blob_storage.GcpBlobBaseConfig{
+			BucketName:             ciConfig.LogsBucket,
+			CredentialFileJsonData: impl.ciConfig.BlobStorageGcpCredentialJson,
+		},
+	}
+	defer os.Remove("/" + item)
+	_, _, err = blobStorageService.Get(request)
+	if err != nil {
+		impl.Logger.Errorw("error occurred while downloading sbom", "err", err, "request", request)
+		return nil, err
+	}
+	return os.ReadFile("/" + item)
+}
+
 func (impl *CiHandlerImpl) GetHistoricBuildLogs(pipelineId int, workflowId int, ciWorkflow *pipelineConfig.CiWorkflow) (map[string]string, error) {
 	ciConfig, err := impl.ciWorkflowRepository.FindConfigByPipelineId(pipelineId)
 	if err != nil && !util.IsErrNoRows(err) {
@@ -700,6 +827,10 @@ func (impl *CiHandlerImpl) extractWorkfowStatus(workflowStatus v1alpha1.Workflow
 	message := ""
 	logLocation := ""
 	for k, v := range workflowStatus.Nodes {
+		//matrix builds run as dag, the root node without boundary is the node of the whole workflow
+		if len(workflowStatus.Nodes) > 1 && len(v.BoundaryID) > 0 {
+			continue
+		}
 		impl.Logger.Infow("extractWorkflowStatus", "workflowName", k, "v", v)
 		workflowName = k
 		podStatus = string(v.Phase)
@@ -771,9 +902,59 @@ func (impl *CiHandlerImpl) UpdateWorkflow(workflowStatus v1alpha1.WorkflowStatus
 			impl.WriteToCreateTestSuites(savedWorkflow.CiPipelineId, workflowId, int(savedWorkflow.TriggeredBy))
 		}
 	}
+	err = impl.updateMatrixLegWorkflows(workflowStatus)
+	if err != nil {
+		impl.Logger.Errorw("error in updating matrix leg workflows", "err", err, "workflowId", savedWorkflow.Id)
+		return 0, err
+	}
 	return savedWorkflow.Id, nil
 }
 
+// updateMatrixLegWorkflows tracks status of every leg of a matrix build from the workflow node running the leg
+func (impl *CiHandlerImpl) updateMatrixLegWorkflows(workflowStatus v1alpha1.WorkflowStatus) error {
+	for nodeId, node := range workflowStatus.Nodes {
+		if node.Type != v1alpha1.NodeTypePod || !strings.HasPrefix(node.TemplateName, ciMatrixLegTemplatePrefix) {
+			continue
+		}
+		legWorkflowId, err := strconv.Atoi(strings.TrimPrefix(node.TemplateName, ciMatrixLegTemplatePrefix))
+		if err != nil {
+			impl.Logger.Errorw("invalid matrix leg template name", "err", err, "templateName", node.TemplateName)
+			continue
+		}
+		legWorkflow, err := impl.ciWorkflowRepository.FindById(legWorkflowId)
+		if err != nil {
+			impl.Logger.Errorw("cannot get saved matrix leg wf", "err", err, "legWorkflowId", legWorkflowId)
+			return err
+		}
+		status := string(node.Phase)
+		if !impl.stateChanged(status, status, node.Message, node.FinishedAt.Time, legWorkflow) && legWorkflow.Name == nodeId {
+			continue
+		}
+		//leg is marked succeeded on its ci complete event, a cancelled leg stays cancelled
+		if legWorkflow.Status != WorkflowCancel && legWorkflow.Status != string(v1alpha1.NodeSucceeded) {
+			legWorkflow.Status = status
+		}
+		legWorkflow.PodStatus = status
+		legWorkflow.Message = node.Message
+		legWorkflow.FinishedOn = node.FinishedAt.Time
+		//pod of the node is named by node id, logs of running legs are fetched by pod name
+		legWorkflow.Name = nodeId
+		if node.Outputs != nil && len(node.Outputs.Artifacts) > 0 {
+			if node.Outputs.Artifacts[0].S3 != nil {
+				legWorkflow.LogLocation = node.Outputs.Artifacts[0].S3.Key
+			} else if node.Outputs.Artifacts[0].GCS != nil {
+				legWorkflow.LogLocation = node.Outputs.Artifacts[0].GCS.Key
+			}
+		}
+		err = impl.ciWorkflowRepository.UpdateWorkFlow(legWorkflow)
+		if err != nil {
+			impl.Logger.Errorw("update matrix leg wf failed", "err", err, "legWorkflowId", legWorkflow.Id)
+			return err
+		}
+	}
+	return nil
+}
+
 func (impl *CiHandlerImpl) WriteCIFailEvent(ciWorkflow *pipelineConfig.CiWorkflow, ciImage string) {
 	event := impl.eventFactory.Build(util2.Fail, &ciWorkflow.CiPipelineId, ciWorkflow.CiPipeline.AppId, nil, util2.CI)
 	material := &client.MaterialTriggerInfo{}
@@ -782,12 +963,37 @@ func (impl *CiHandlerImpl) WriteCIFailEvent(ciWorkflow *pipelineConfig.CiWorkflo
 	event = impl.eventFactory.BuildExtraCIData(event, material, ciImage)
 	event.CiArtifactId = 0
 	event.UserId = int(ciWorkflow.TriggeredBy)
+	impl.addSecretLeaksToFailEvent(event.Payload, ciWorkflow.Id)
 	_, evtErr := impl.eventClient.WriteNotificationEvent(event)
 	if evtErr != nil {
 		impl.Logger.Errorw("error in writing event", "err", evtErr)
 	}
 }
 
+// addSecretLeaksToFailEvent reports the findings of pre build secret scan which failed the build, the result of a
+// requested scan is waited for as it is saved asynchronously from the secret scan event of ci runner
+func (impl *CiHandlerImpl) addSecretLeaksToFailEvent(payload *client.Payload, ciWorkflowId int) {
+	scanResult, err := impl.secretScanService.WaitForScanResult(ciWorkflowId)
+	if err != nil {
+		impl.Logger.Errorw("error in fetching secret scan result for ci fail event", "err", err, "ciWorkflowId", ciWorkflowId)
+		return
+	}
+	if scanResult == nil || !scanResult.Blocked {
+		return
+	}
+	for _, finding := range scanResult.Findings {
+		if finding.Action == secretScan.SECRET_SCAN_ACTION_BLOCK {
+			payload.SecretLeaks = append(payload.SecretLeaks, &client.SecretLeak{
+				RuleId: finding.RuleId,
+				File:   finding.File,
+				Line:   finding.Line,
+				Match:  finding.Match,
+			})
+		}
+	}
+	payload.FailureReason = secretScan.BuildSecretScanViolationMessage(scanResult.Findings)
+}
+
 func (impl *CiHandlerImpl) BuildPayload(ciWorkflow *pipelineConfig.CiWorkflow) *client.Payload {
 	payload := &client.Payload{}
 	payload.AppName = ciWorkflow.CiPipeline.App.AppName
@@ -1003,6 +1209,13 @@ func (impl *CiHandlerImpl) FetchCiStatusForTriggerView(appId int) ([]*pipelineCo
 		}
 		ciWorkflowStatus := &pipelineConfig.CiWorkflowStatus{}
 		ciWorkflowStatus.CiPipelineId = pipeline.Id
+		if len(pipeline.CronSchedule) > 0 {
+			ciWorkflowStatus.CronSchedule = pipeline.CronSchedule
+			nextRunOn, err := NextCronScheduleRun(pipeline.CronSchedule, time.Now())
+			if err == nil {
+				ciWorkflowStatus.NextScheduledRunOn = &nextRunOn
+			}
+		}
 		if workflow.Id > 0 {
 			ciWorkflowStatus.CiPipelineName = workflow.CiPipeline.Name
 			ciWorkflowStatus.CiStatus = workflow.Status
@@ -1176,3 +1389,78 @@ func (impl *CiHandlerImpl) listFiles(file *zip.File, payload map[string]interfac
 	}
 	return payload, nil
 }
+
+func (impl *CiHandlerImpl) TriggerScheduledCiPipelines(from time.Time, to time.Time) error {
+	pipelines, err := impl.ciPipelineRepository.FindAllScheduled()
+	if err != nil && err != pg.ErrNoRows {
+		impl.Logger.Errorw("error in fetching scheduled ci pipelines", "err", err)
+		return err
+	}
+	for _, pipeline := range pipelines {
+		due, err := IsCronScheduleDue(pipeline.CronSchedule, from, to)
+		if err != nil {
+			impl.Logger.Errorw("invalid cron schedule, skipping scheduled build", "ciPipelineId", pipeline.Id, "cronSchedule", pipeline.CronSchedule, "err", err)
+			continue
+		}
+		if !due {
+			continue
+		}
+		err = impl.triggerScheduledCiPipeline(pipeline)
+		if err != nil {
+			//only log this error and continue for next scheduled pipeline
+			impl.Logger.Errorw("error in triggering scheduled build", "ciPipelineId", pipeline.Id, "err", err)
+		}
+	}
+	return nil
+}
+
+func (impl *CiHandlerImpl) triggerScheduledCiPipeline(pipeline *pipelineConfig.CiPipeline) error {
+	//skipping this run if previous build is still in progress, scheduled builds never overlap
+	lastWorkflow, err := impl.ciWorkflowRepository.FindLastTriggeredWorkflow(pipeline.Id)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.Logger.Errorw("error in fetching last build", "ciPipelineId", pipeline.Id, "err", err)
+		return err
+	}
+	if lastWorkflow != nil && (lastWorkflow.Status == WorkflowStarting || lastWorkflow.Status == string(v1alpha1.NodePending) || lastWorkflow.Status == string(v1alpha1.NodeRunning)) {
+		impl.Logger.Infow("previous build in progress, skipping scheduled build", "ciPipelineId", pipeline.Id, "ciWorkflowId", lastWorkflow.Id)
+		return nil
+	}
+	ciMaterials, err := impl.ciPipelineMaterialRepository.GetByPipelineId(pipeline.Id)
+	if err != nil {
+		impl.Logger.Errorw("error in fetching ci materials", "ciPipelineId", pipeline.Id, "err", err)
+		return err
+	}
+	var ciPipelineMaterials []bean.CiPipelineMaterial
+	for _, ciMaterial := range ciMaterials {
+		if ciMaterial.Type != pipelineConfig.SOURCE_TYPE_BRANCH_FIXED {
+			return fmt.Errorf("scheduled build not supported for source type %s", ciMaterial.Type)
+		}
+		changesResp, err := impl.gitSensorClient.FetchChanges(&gitSensor.FetchScmChangesRequest{PipelineMaterialId: ciMaterial.Id})
+		if err != nil {
+			impl.Logger.Errorw("error in fetching latest commit", "ciPipelineMaterialId", ciMaterial.Id, "err", err)
+			return err
+		}
+		if changesResp == nil || len(changesResp.Commits) == 0 {
+			return fmt.Errorf("no commit found for ci pipeline material %d", ciMaterial.Id)
+		}
+		ciPipelineMaterials = append(ciPipelineMaterials, bean.CiPipelineMaterial{
+			Id:            ciMaterial.Id,
+			GitMaterialId: ciMaterial.GitMaterialId,
+			Type:          string(ciMaterial.Type),
+			Value:         ciMaterial.Value,
+			Active:        ciMaterial.Active,
+			GitCommit:     bean.GitCommit{Commit: changesResp.Commits[0].Commit},
+		})
+	}
+	ciTriggerRequest := bean.CiTriggerRequest{
+		PipelineId:         pipeline.Id,
+		CiPipelineMaterial: ciPipelineMaterials,
+		TriggeredBy:        1,
+	}
+	_, err = impl.HandleCIManual(ciTriggerRequest)
+	if err != nil {
+		return err
+	}
+	impl.Logger.Infow("scheduled build triggered", "ciPipelineId", pipeline.Id, "cronSchedule", pipeline.CronSchedule)
+	return nil
+}
@@ -0,0 +1,60 @@
+package pipeline
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/bean"
+	"github.com/robfig/cron/v3"
+	"net/http"
+	"time"
+)
+
+// ValidateCiPipelineCronSchedule checks the cron schedule of a ci pipeline, scheduled builds pick the latest commit
+// so only pipelines building fixed branches can be scheduled
+func ValidateCiPipelineCronSchedule(ciPipeline *bean.CiPipeline) error {
+	if ciPipeline == nil || len(ciPipeline.CronSchedule) == 0 {
+		return nil
+	}
+	if _, err := cron.ParseStandard(ciPipeline.CronSchedule); err != nil {
+		return &util.ApiError{
+			HttpStatusCode:  http.StatusBadRequest,
+			InternalMessage: err.Error(),
+			UserMessage:     fmt.Sprintf("invalid cron schedule %s", ciPipeline.CronSchedule),
+		}
+	}
+	if ciPipeline.IsExternal || ciPipeline.ParentCiPipeline > 0 {
+		return &util.ApiError{
+			HttpStatusCode:  http.StatusBadRequest,
+			InternalMessage: "cron schedule not supported for external or linked ci pipeline",
+			UserMessage:     "cron schedule is not supported for external or linked ci pipeline",
+		}
+	}
+	for _, material := range ciPipeline.CiMaterial {
+		if material.Source != nil && material.Source.Type != pipelineConfig.SOURCE_TYPE_BRANCH_FIXED {
+			return &util.ApiError{
+				HttpStatusCode:  http.StatusBadRequest,
+				InternalMessage: fmt.Sprintf("cron schedule not supported for source type %s", material.Source.Type),
+				UserMessage:     "cron schedule is only supported for ci pipelines building fixed branches",
+			}
+		}
+	}
+	return nil
+}
+
+// IsCronScheduleDue tells if the schedule has a run in (from, to]
+func IsCronScheduleDue(cronSchedule string, from time.Time, to time.Time) (bool, error) {
+	schedule, err := cron.ParseStandard(cronSchedule)
+	if err != nil {
+		return false, err
+	}
+	return !schedule.Next(from).After(to), nil
+}
+
+func NextCronScheduleRun(cronSchedule string, after time.Time) (time.Time, error) {
+	schedule, err := cron.ParseStandard(cronSchedule)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return schedule.Next(after), nil
+}
@@ -0,0 +1,60 @@
+package pipeline
+
+import (
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/pkg/bean"
+	"testing"
+	"time"
+)
+
+func TestIsCronScheduleDue(t *testing.T) {
+	tests := []struct {
+		name         string
+		cronSchedule string
+		from         time.Time
+		to           time.Time
+		want         bool
+		wantErr      bool
+	}{
+		{name: "nightly due", cronSchedule: "0 2 * * *", from: time.Date(2022, 8, 8, 1, 59, 0, 0, time.Local), to: time.Date(2022, 8, 8, 2, 0, 0, 0, time.Local), want: true},
+		{name: "nightly not due", cronSchedule: "0 2 * * *", from: time.Date(2022, 8, 8, 2, 0, 0, 0, time.Local), to: time.Date(2022, 8, 8, 2, 1, 0, 0, time.Local), want: false},
+		{name: "missed tick still due", cronSchedule: "0 2 * * *", from: time.Date(2022, 8, 8, 1, 58, 0, 0, time.Local), to: time.Date(2022, 8, 8, 2, 3, 0, 0, time.Local), want: true},
+		{name: "weekday schedule on weekend", cronSchedule: "0 2 * * 1-5", from: time.Date(2022, 8, 7, 1, 59, 0, 0, time.Local), to: time.Date(2022, 8, 7, 2, 0, 0, 0, time.Local), want: false},
+		{name: "invalid schedule", cronSchedule: "every night", from: time.Now(), to: time.Now(), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IsCronScheduleDue(tt.cronSchedule, tt.from, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("IsCronScheduleDue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("IsCronScheduleDue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateCiPipelineCronSchedule(t *testing.T) {
+	branchMaterial := &bean.CiMaterial{Source: &bean.SourceTypeConfig{Type: pipelineConfig.SOURCE_TYPE_BRANCH_FIXED, Value: "main"}}
+	webhookMaterial := &bean.CiMaterial{Source: &bean.SourceTypeConfig{Type: pipelineConfig.SOURCE_TYPE_WEBHOOK}}
+	tests := []struct {
+		name       string
+		ciPipeline *bean.CiPipeline
+		wantErr    bool
+	}{
+		{name: "no schedule", ciPipeline: &bean.CiPipeline{CiMaterial: []*bean.CiMaterial{webhookMaterial}}, wantErr: false},
+		{name: "valid schedule", ciPipeline: &bean.CiPipeline{CronSchedule: "0 2 * * *", CiMaterial: []*bean.CiMaterial{branchMaterial}}, wantErr: false},
+		{name: "invalid schedule", ciPipeline: &bean.CiPipeline{CronSchedule: "0 2 * *", CiMaterial: []*bean.CiMaterial{branchMaterial}}, wantErr: true},
+		{name: "linked pipeline", ciPipeline: &bean.CiPipeline{CronSchedule: "0 2 * * *", ParentCiPipeline: 3}, wantErr: true},
+		{name: "webhook material", ciPipeline: &bean.CiPipeline{CronSchedule: "0 2 * * *", CiMaterial: []*bean.CiMaterial{branchMaterial, webhookMaterial}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateCiPipelineCronSchedule(tt.ciPipeline); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCiPipelineCronSchedule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
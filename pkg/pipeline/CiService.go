@@ -18,12 +18,14 @@
 package pipeline
 
 import (
+	"encoding/json"
 	"fmt"
 	repository3 "github.com/devtron-labs/devtron/internal/sql/repository"
 	bean2 "github.com/devtron-labs/devtron/pkg/pipeline/bean"
 	"github.com/devtron-labs/devtron/pkg/pipeline/history"
 	"github.com/devtron-labs/devtron/pkg/pipeline/repository"
 	repository2 "github.com/devtron-labs/devtron/pkg/plugin/repository"
+	"github.com/devtron-labs/devtron/pkg/secretScan"
 	"github.com/devtron-labs/devtron/pkg/user"
 	"path/filepath"
 	"strconv"
@@ -60,6 +62,7 @@ type CiServiceImpl struct {
 	pipelineStageService          PipelineStageService
 	userService                   user.UserService
 	ciTemplateOverrideRepository  pipelineConfig.CiTemplateOverrideRepository
+	secretScanService             secretScan.SecretScanService
 }
 
 func NewCiServiceImpl(Logger *zap.SugaredLogger, workflowService WorkflowService,
@@ -69,7 +72,8 @@ func NewCiServiceImpl(Logger *zap.SugaredLogger, workflowService WorkflowService
 	prePostCiScriptHistoryService history.PrePostCiScriptHistoryService,
 	pipelineStageService PipelineStageService,
 	userService user.UserService,
-	ciTemplateOverrideRepository pipelineConfig.CiTemplateOverrideRepository) *CiServiceImpl {
+	ciTemplateOverrideRepository pipelineConfig.CiTemplateOverrideRepository,
+	secretScanService secretScan.SecretScanService) *CiServiceImpl {
 	return &CiServiceImpl{
 		Logger:                        Logger,
 		workflowService:               workflowService,
@@ -84,6 +88,7 @@ func NewCiServiceImpl(Logger *zap.SugaredLogger, workflowService WorkflowService
 		pipelineStageService:          pipelineStageService,
 		userService:                   userService,
 		ciTemplateOverrideRepository:  ciTemplateOverrideRepository,
+		secretScanService:             secretScanService,
 	}
 }
 
@@ -91,6 +96,7 @@ const WorkflowStarting = "Starting"
 const WorkflowInProgress = "Progressing"
 const WorkflowAborted = "Aborted"
 const WorkflowFailed = "Failed"
+const WorkflowQueued = "Queued"
 
 func (impl *CiServiceImpl) GetCiMaterials(pipelineId int, ciMaterials []*pipelineConfig.CiPipelineMaterial) ([]*pipelineConfig.CiPipelineMaterial, error) {
 	if !(len(ciMaterials) == 0) {
@@ -143,6 +149,18 @@ func (impl *CiServiceImpl) TriggerCiPipeline(trigger Trigger) (int, error) {
 		impl.Logger.Errorw("make workflow req", "err", err)
 		return 0, err
 	}
+	buildMatrix, err := GetCiBuildMatrix(pipeline)
+	if err != nil {
+		impl.Logger.Errorw("error in getting build matrix of ci pipeline", "err", err, "ciPipelineId", pipeline.Id)
+		return 0, err
+	}
+	if buildMatrix != nil {
+		err = impl.addCiBuildMatrixLegs(workflowRequest, buildMatrix, pipeline, savedCiWf, ciWorkflowConfig)
+		if err != nil {
+			impl.Logger.Errorw("error in building matrix legs of workflow", "err", err, "ciPipelineId", pipeline.Id)
+			return 0, err
+		}
+	}
 
 	createdWf, err := impl.executeCiPipeline(workflowRequest)
 	if err != nil {
@@ -277,6 +295,20 @@ func (impl *CiServiceImpl) buildDefaultArtifactLocation(ciWorkflowConfig *pipeli
 	return ArtifactLocation
 }
 
+// buildSbomLocation returns the key at which ci runner uploads sbom of the workflow, sbom is not generated without blob storage
+// as it is too large to be sent with ci complete event
+func (impl *CiServiceImpl) buildSbomLocation(savedWf *pipelineConfig.CiWorkflow) string {
+	if len(impl.ciConfig.SbomFormat) == 0 || !savedWf.BlobStorageEnabled {
+		return ""
+	}
+	return BuildSbomLocation(impl.ciConfig, savedWf.Id)
+}
+
+// BuildSbomLocation returns the key of sbom of a ci workflow in artifact bucket
+func BuildSbomLocation(ciConfig *CiConfig, ciWorkflowId int) string {
+	return fmt.Sprintf("%s/"+ciConfig.CiSbomLocationFormat, ciConfig.DefaultArtifactKeyPrefix, ciWorkflowId)
+}
+
 func (impl *CiServiceImpl) buildWfRequestForCiPipeline(pipeline *pipelineConfig.CiPipeline, trigger Trigger,
 	ciMaterials []*pipelineConfig.CiPipelineMaterial, savedWf *pipelineConfig.CiWorkflow,
 	ciWorkflowConfig *pipelineConfig.CiWorkflowConfig, ciPipelineScripts []*pipelineConfig.CiPipelineScript) (*WorkflowRequest, error) {
@@ -353,6 +385,17 @@ func (impl *CiServiceImpl) buildWfRequestForCiPipeline(pipeline *pipelineConfig.
 			return nil, err
 		}
 	}
+	secretScanRequest, err := impl.secretScanService.BuildSecretScanRequest(preCiSteps, ciMaterials)
+	if err != nil {
+		impl.Logger.Errorw("error in building secret scan request", "err", err, "ciPipelineId", pipeline.Id)
+		return nil, err
+	}
+	if secretScanRequest != nil {
+		err = impl.secretScanService.SaveScanRequested(savedWf.Id, trigger.TriggeredBy)
+		if err != nil {
+			return nil, err
+		}
+	}
 	dockerImageTag := impl.buildImageTag(commitHashes, pipeline.Id, savedWf.Id)
 	if ciWorkflowConfig.CiCacheBucket == "" {
 		ciWorkflowConfig.CiCacheBucket = impl.ciConfig.DefaultCacheBucket
@@ -438,6 +481,11 @@ func (impl *CiServiceImpl) buildWfRequestForCiPipeline(pipeline *pipelineConfig.
 		CacheLimit:                 impl.ciConfig.CacheLimit,
 		InvalidateCache:            trigger.InvalidateCache,
 		ScanEnabled:                pipeline.ScanEnabled,
+		SbomFormat:                 impl.ciConfig.SbomFormat,
+		SbomLocation:               impl.buildSbomLocation(savedWf),
+		ImageSigningFormat:         impl.ciConfig.ImageSigningFormat,
+		ImageSigningKey:            impl.ciConfig.ImageSigningKey,
+		SecretScan:                 secretScanRequest,
 		CloudProvider:              impl.ciConfig.CloudProvider,
 		DefaultAddressPoolBaseCidr: impl.ciConfig.DefaultAddressPoolBaseCidr,
 		DefaultAddressPoolSize:     impl.ciConfig.DefaultAddressPoolSize,
@@ -446,6 +494,7 @@ func (impl *CiServiceImpl) buildWfRequestForCiPipeline(pipeline *pipelineConfig.
 		RefPlugins:                 refPluginsData,
 		AppName:                    pipeline.App.AppName,
 		TriggerByAuthor:            user.EmailId,
+		CheckoutPath:               checkoutPath,
 	}
 
 	if ciWorkflowConfig.LogsBucket == "" {
@@ -509,6 +558,98 @@ func (impl *CiServiceImpl) buildWfRequestForCiPipeline(pipeline *pipelineConfig.
 	return workflowRequest, nil
 }
 
+// addCiBuildMatrixLegs saves a ci workflow for every leg of the matrix and adds the leg requests to the workflow request,
+// legs build from the same sources as the parent request and only override platform, build args and dockerfile
+func (impl *CiServiceImpl) addCiBuildMatrixLegs(workflowRequest *WorkflowRequest, buildMatrix *bean.CiBuildMatrix,
+	pipeline *pipelineConfig.CiPipeline, savedCiWf *pipelineConfig.CiWorkflow, ciWorkflowConfig *pipelineConfig.CiWorkflowConfig) error {
+	if len(workflowRequest.DockerImageTag) == 0 {
+		workflowRequest.DockerImageTag = strconv.Itoa(pipeline.Id) + "-" + strconv.Itoa(savedCiWf.Id)
+	}
+	var legImages []string
+	for _, leg := range ExpandCiBuildMatrix(buildMatrix) {
+		legImageTag := BuildCiBuildMatrixLegImageTag(workflowRequest.DockerImageTag, leg.Name)
+		legImage := BuildDockerImage(workflowRequest.DockerRegistryURL, workflowRequest.DockerRepository, legImageTag)
+		legWf := &pipelineConfig.CiWorkflow{
+			Name:               savedCiWf.Name + "-" + leg.Name,
+			Status:             WorkflowStarting,
+			StartedOn:          time.Now(),
+			CiPipelineId:       savedCiWf.CiPipelineId,
+			Namespace:          savedCiWf.Namespace,
+			BlobStorageEnabled: savedCiWf.BlobStorageEnabled,
+			GitTriggers:        savedCiWf.GitTriggers,
+			TriggeredBy:        savedCiWf.TriggeredBy,
+			ParentCiWorkflowId: savedCiWf.Id,
+			MatrixLeg:          leg.Name,
+			MatrixImage:        legImage,
+		}
+		err := impl.ciWorkflowRepository.SaveWorkFlow(legWf)
+		if err != nil {
+			impl.Logger.Errorw("saving matrix leg workflow error", "err", err, "leg", leg.Name)
+			return err
+		}
+
+		legRequest := *workflowRequest
+		legRequest.MatrixLegs = nil
+		legRequest.MatrixManifest = nil
+		legRequest.WorkflowId = legWf.Id
+		legRequest.WorkflowNamePrefix = strconv.Itoa(legWf.Id) + "-" + legWf.Name
+		legRequest.DockerImageTag = legImageTag
+		legRequest.CiCacheFileName = pipeline.Name + "-" + strconv.Itoa(pipeline.Id) + "-" + leg.Name + ".tar.gz"
+		if len(leg.Platform) > 0 {
+			legRequest.DockerBuildTargetPlatform = leg.Platform
+		}
+		if len(leg.DockerfilePath) > 0 {
+			legRequest.DockerFileLocation = filepath.Join(workflowRequest.CheckoutPath, leg.DockerfilePath)
+		}
+		if len(leg.BuildArgs) > 0 {
+			legArgs, err := json.Marshal(leg.BuildArgs)
+			if err != nil {
+				return err
+			}
+			merged, err := impl.mergeUtil.JsonPatch([]byte(workflowRequest.DockerBuildArgs), legArgs)
+			if err != nil {
+				impl.Logger.Errorw("error in merging matrix leg build args", "err", err, "leg", leg.Name)
+				return err
+			}
+			legRequest.DockerBuildArgs = string(merged)
+		}
+		legRequest.SbomLocation = impl.buildSbomLocation(legWf)
+		switch legRequest.CloudProvider {
+		case BLOB_STORAGE_S3:
+			legRequest.CiArtifactLocation, legRequest.CiArtifactBucket, legRequest.CiArtifactFileName = impl.buildS3ArtifactLocation(ciWorkflowConfig, legWf)
+		case BLOB_STORAGE_GCP, BLOB_STORAGE_AZURE:
+			legRequest.CiArtifactLocation = impl.buildDefaultArtifactLocation(ciWorkflowConfig, legWf)
+			legRequest.CiArtifactFileName = legRequest.CiArtifactLocation
+		}
+		workflowRequest.MatrixLegs = append(workflowRequest.MatrixLegs, &legRequest)
+		legImages = append(legImages, legImage)
+	}
+
+	if GetCiBuildMatrixArtifactMode(buildMatrix) != bean.CI_BUILD_MATRIX_MANIFEST_LIST {
+		return nil
+	}
+	manifest := &CiBuildMatrixManifestRequest{
+		Image:            BuildDockerImage(workflowRequest.DockerRegistryURL, workflowRequest.DockerRepository, workflowRequest.DockerImageTag),
+		LegImages:        legImages,
+		RegistryHost:     GetDockerRegistryHost(workflowRequest.DockerRegistryURL),
+		RegistryUsername: workflowRequest.DockerUsername,
+		RegistryPassword: workflowRequest.DockerPassword,
+	}
+	if workflowRequest.DockerRegistryType == repository3.REGISTRYTYPE_ECR {
+		username, password, err := util.GetEcrLoginCredentials(workflowRequest.AwsRegion, workflowRequest.AccessKey, workflowRequest.SecretKey)
+		if err != nil {
+			impl.Logger.Errorw("error in getting ecr login credentials for manifest list", "err", err, "registryUrl", workflowRequest.DockerRegistryURL)
+			return err
+		}
+		manifest.RegistryUsername = username
+		manifest.RegistryPassword = password
+	}
+	workflowRequest.MatrixManifest = manifest
+	//artifact of a manifest list build is saved once the whole workflow succeeds
+	savedCiWf.MatrixImage = manifest.Image
+	return impl.ciWorkflowRepository.UpdateWorkFlow(savedCiWf)
+}
+
 func buildCiStepsDataFromDockerBuildScripts(dockerBuildScripts []*bean.CiScript) []*bean2.StepObject {
 	//before plugin support, few variables were set as env vars in ci-runner
 	//these variables are now moved to global vars in plugin steps, but to avoid error in old scripts adding those variables in payload
@@ -142,6 +142,11 @@ func (impl DbPipelineOrchestratorImpl) PatchMaterialValue(createRequest *bean.Ci
 		impl.logger.Error(err)
 		return nil, err
 	}
+	buildMatrix, err := getCiBuildMatrixJson(createRequest.BuildMatrix)
+	if err != nil {
+		impl.logger.Errorw("error in marshaling build matrix", "err", err, "buildMatrix", createRequest.BuildMatrix)
+		return nil, err
+	}
 	dbConnection := impl.pipelineRepository.GetConnection()
 	tx, err := dbConnection.Begin()
 	if err != nil {
@@ -161,6 +166,8 @@ func (impl DbPipelineOrchestratorImpl) PatchMaterialValue(createRequest *bean.Ci
 		ParentCiPipeline:         createRequest.ParentCiPipeline,
 		ScanEnabled:              createRequest.ScanEnabled,
 		IsDockerConfigOverridden: createRequest.IsDockerConfigOverridden,
+		CronSchedule:             createRequest.CronSchedule,
+		BuildMatrix:              buildMatrix,
 		AuditLog:                 sql.AuditLog{UpdatedBy: userId, UpdatedOn: time.Now()},
 	}
 	err = impl.ciPipelineRepository.Update(ciPipelineObject, tx)
@@ -416,6 +423,11 @@ func (impl DbPipelineOrchestratorImpl) CreateCiConf(createRequest *bean.CiConfig
 			impl.logger.Errorw("err", "err", err)
 			return nil, err
 		}
+		buildMatrix, err := getCiBuildMatrixJson(ciPipeline.BuildMatrix)
+		if err != nil {
+			impl.logger.Errorw("error in marshaling build matrix", "err", err, "buildMatrix", ciPipeline.BuildMatrix)
+			return nil, err
+		}
 
 		dbConnection := impl.pipelineRepository.GetConnection()
 		tx, err := dbConnection.Begin()
@@ -438,6 +450,8 @@ func (impl DbPipelineOrchestratorImpl) CreateCiConf(createRequest *bean.CiConfig
 			Deleted:                  false,
 			ScanEnabled:              createRequest.ScanEnabled,
 			IsDockerConfigOverridden: ciPipeline.IsDockerConfigOverridden,
+			CronSchedule:             ciPipeline.CronSchedule,
+			BuildMatrix:              buildMatrix,
 			AuditLog:                 sql.AuditLog{UpdatedBy: createRequest.UserId, CreatedBy: createRequest.UserId, UpdatedOn: time.Now(), CreatedOn: time.Now()},
 		}
 		err = impl.ciPipelineRepository.Save(ciPipelineObject, tx)
@@ -1045,6 +1059,9 @@ func (impl DbPipelineOrchestratorImpl) CreateCDPipelines(pipelineRequest *bean.C
 	if len(pipelineRequest.PreStage.Config) > 0 {
 		preStageConfig = pipelineRequest.PreStage.Config
 		preTriggerType = pipelineRequest.PreStage.TriggerType
+	} else if pipelineRequest.PreDeployStage != nil && len(pipelineRequest.PreDeployStage.Steps) > 0 {
+		//plugin based pre deploy stage, trigger type is still taken from pre stage
+		preTriggerType = pipelineRequest.PreStage.TriggerType
 	}
 
 	postStageConfig := ""
@@ -1052,6 +1069,9 @@ func (impl DbPipelineOrchestratorImpl) CreateCDPipelines(pipelineRequest *bean.C
 	if len(pipelineRequest.PostStage.Config) > 0 {
 		postStageConfig = pipelineRequest.PostStage.Config
 		postTriggerType = pipelineRequest.PostStage.TriggerType
+	} else if pipelineRequest.PostDeployStage != nil && len(pipelineRequest.PostDeployStage.Steps) > 0 {
+		//plugin based post deploy stage, trigger type is still taken from post stage
+		postTriggerType = pipelineRequest.PostStage.TriggerType
 	}
 
 	preStageConfigMapSecretNames, err := json.Marshal(&pipelineRequest.PreStageConfigMapSecretNames)
@@ -1121,6 +1141,9 @@ func (impl DbPipelineOrchestratorImpl) UpdateCDPipeline(pipelineRequest *bean.CD
 	if len(pipelineRequest.PreStage.Config) > 0 {
 		preStageConfig = pipelineRequest.PreStage.Config
 		preTriggerType = pipelineRequest.PreStage.TriggerType
+	} else if pipelineRequest.PreDeployStage != nil && len(pipelineRequest.PreDeployStage.Steps) > 0 {
+		//plugin based pre deploy stage, trigger type is still taken from pre stage
+		preTriggerType = pipelineRequest.PreStage.TriggerType
 	}
 
 	postStageConfig := ""
@@ -1128,6 +1151,9 @@ func (impl DbPipelineOrchestratorImpl) UpdateCDPipeline(pipelineRequest *bean.CD
 	if len(pipelineRequest.PostStage.Config) > 0 {
 		postStageConfig = pipelineRequest.PostStage.Config
 		postTriggerType = pipelineRequest.PostStage.TriggerType
+	} else if pipelineRequest.PostDeployStage != nil && len(pipelineRequest.PostDeployStage.Steps) > 0 {
+		//plugin based post deploy stage, trigger type is still taken from post stage
+		postTriggerType = pipelineRequest.PostStage.TriggerType
 	}
 
 	preStageConfigMapSecretNames, err := json.Marshal(&pipelineRequest.PreStageConfigMapSecretNames)
@@ -22,12 +22,15 @@ import (
 	"encoding/json"
 	"fmt"
 	client "github.com/devtron-labs/devtron/api/helm-app"
+	client2 "github.com/devtron-labs/devtron/client/events"
 	app2 "github.com/devtron-labs/devtron/internal/sql/repository/app"
 	"github.com/devtron-labs/devtron/pkg/chart"
 	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
 	repository2 "github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/devtron-labs/devtron/pkg/deploymentApproval"
 	"github.com/devtron-labs/devtron/pkg/pipeline/history"
 	repository4 "github.com/devtron-labs/devtron/pkg/pipeline/history/repository"
+	repository5 "github.com/devtron-labs/devtron/pkg/pipeline/repository"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	util3 "github.com/devtron-labs/devtron/pkg/util"
 	"net/http"
@@ -51,7 +54,9 @@ import (
 	"github.com/devtron-labs/devtron/pkg/app"
 	"github.com/devtron-labs/devtron/pkg/attributes"
 	"github.com/devtron-labs/devtron/pkg/bean"
+	bean3 "github.com/devtron-labs/devtron/pkg/pipeline/bean"
 	util2 "github.com/devtron-labs/devtron/util"
+	util4 "github.com/devtron-labs/devtron/util/event"
 	"github.com/go-pg/pg"
 	"github.com/juju/errors"
 	"go.uber.org/zap"
@@ -86,6 +91,7 @@ type PipelineBuilder interface {
 	GetCdPipelinesForAppAndEnv(appId int, envId int) (cdPipelines *bean.CdPipelines, err error)
 	/*	CreateCdPipelines(cdPipelines bean.CdPipelines) (*bean.CdPipelines, error)*/
 	GetArtifactsByCDPipeline(cdPipelineId int, stage bean2.WorkflowType) (bean.CiArtifactResponse, error)
+	GetLatestArtifactOfParentStage(cdPipelineId int, stage bean2.WorkflowType) (*bean.CiArtifactBean, error)
 	FetchArtifactForRollback(cdPipelineId int) (bean.CiArtifactResponse, error)
 	FindAppsByTeamId(teamId int) ([]*AppBean, error)
 	GetAppListByTeamIds(teamIds []int, appType string) ([]*TeamAppBean, error)
@@ -148,6 +154,11 @@ type PipelineBuilderImpl struct {
 	deploymentGroupRepository        repository.DeploymentGroupRepository
 	ciPipelineMaterialRepository     pipelineConfig.CiPipelineMaterialRepository
 	ciTemplateOverrideRepository     pipelineConfig.CiTemplateOverrideRepository
+	deploymentApprovalService        deploymentApproval.DeploymentApprovalService
+	autoRollbackService              AutoRollbackService
+	canaryAnalysisService            CanaryAnalysisService
+	eventClient                      client2.EventClient
+	eventFactory                     client2.EventFactory
 }
 
 func NewPipelineBuilderImpl(logger *zap.SugaredLogger,
@@ -185,7 +196,11 @@ func NewPipelineBuilderImpl(logger *zap.SugaredLogger,
 	helmAppService client.HelmAppService,
 	deploymentGroupRepository repository.DeploymentGroupRepository,
 	ciPipelineMaterialRepository pipelineConfig.CiPipelineMaterialRepository,
-	ciTemplateOverrideRepository pipelineConfig.CiTemplateOverrideRepository) *PipelineBuilderImpl {
+	ciTemplateOverrideRepository pipelineConfig.CiTemplateOverrideRepository,
+	deploymentApprovalService deploymentApproval.DeploymentApprovalService,
+	autoRollbackService AutoRollbackService,
+	canaryAnalysisService CanaryAnalysisService,
+	eventClient client2.EventClient, eventFactory client2.EventFactory) *PipelineBuilderImpl {
 	return &PipelineBuilderImpl{
 		logger:                           logger,
 		dbPipelineOrchestrator:           dbPipelineOrchestrator,
@@ -227,6 +242,11 @@ func NewPipelineBuilderImpl(logger *zap.SugaredLogger,
 		deploymentGroupRepository:        deploymentGroupRepository,
 		ciPipelineMaterialRepository:     ciPipelineMaterialRepository,
 		ciTemplateOverrideRepository:     ciTemplateOverrideRepository,
+		deploymentApprovalService:        deploymentApprovalService,
+		autoRollbackService:              autoRollbackService,
+		canaryAnalysisService:            canaryAnalysisService,
+		eventClient:                      eventClient,
+		eventFactory:                     eventFactory,
 	}
 }
 
@@ -510,6 +530,12 @@ func (impl PipelineBuilderImpl) GetCiPipeline(appId int) (ciConfig *bean.CiConfi
 			AfterDockerBuildScripts:  afterDockerBuildScripts,
 			ScanEnabled:              pipeline.ScanEnabled,
 			IsDockerConfigOverridden: pipeline.IsDockerConfigOverridden,
+			CronSchedule:             pipeline.CronSchedule,
+		}
+		ciPipeline.BuildMatrix, err = GetCiBuildMatrix(pipeline)
+		if err != nil {
+			impl.logger.Errorw("error in unmarshalling build matrix", "err", err, "ciPipelineId", pipeline.Id)
+			return nil, err
 		}
 		if templateOverride, ok := templateOverrideMap[pipeline.Id]; ok {
 			ciPipeline.DockerConfigOverride = bean.DockerConfigOverride{
@@ -665,8 +691,46 @@ func (impl PipelineBuilderImpl) UpdateCiTemplate(updateRequest *bean.CiConfigReq
 	return originalCiConf, nil
 }
 
+func (impl PipelineBuilderImpl) validateCiPipelineStages(ciPipeline *bean.CiPipeline) error {
+	for _, stage := range []*bean3.PipelineStageDto{ciPipeline.PreBuildStage, ciPipeline.PostBuildStage} {
+		err := ValidatePipelineStageSteps(stage)
+		if err != nil {
+			impl.logger.Errorw("invalid steps in ci pipeline stage", "err", err, "stage", stage)
+			return err
+		}
+	}
+	return nil
+}
+
+func (impl PipelineBuilderImpl) validateCdPipelineStages(cdPipeline *bean.CDPipelineConfigObject) error {
+	for _, stage := range []*bean3.PipelineStageDto{cdPipeline.PreDeployStage, cdPipeline.PostDeployStage} {
+		err := ValidatePipelineStageSteps(stage)
+		if err != nil {
+			impl.logger.Errorw("invalid steps in cd pipeline stage", "err", err, "stage", stage)
+			return err
+		}
+	}
+	return nil
+}
+
 func (impl PipelineBuilderImpl) CreateCiPipeline(createRequest *bean.CiConfigRequest) (*bean.PipelineCreateResponse, error) {
 	impl.logger.Debugw("pipeline create request received", "req", createRequest)
+	for _, ciPipeline := range createRequest.CiPipelines {
+		err := ValidateCiPipelineCronSchedule(ciPipeline)
+		if err != nil {
+			impl.logger.Errorw("invalid cron schedule for ci pipeline", "err", err, "cronSchedule", ciPipeline.CronSchedule)
+			return nil, err
+		}
+		err = ValidateCiPipelineBuildMatrix(ciPipeline)
+		if err != nil {
+			impl.logger.Errorw("invalid build matrix for ci pipeline", "err", err, "buildMatrix", ciPipeline.BuildMatrix)
+			return nil, err
+		}
+		err = impl.validateCiPipelineStages(ciPipeline)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	//-----------fetch data
 	app, err := impl.appRepo.FindById(createRequest.AppId)
@@ -880,6 +944,20 @@ func (impl PipelineBuilderImpl) PatchCiPipeline(request *bean.CiPatchRequest) (c
 	ciConfig.UserId = request.UserId
 	if request.CiPipeline != nil {
 		ciConfig.ScanEnabled = request.CiPipeline.ScanEnabled
+		err = ValidateCiPipelineCronSchedule(request.CiPipeline)
+		if err != nil {
+			impl.logger.Errorw("invalid cron schedule for ci pipeline", "err", err, "cronSchedule", request.CiPipeline.CronSchedule)
+			return nil, err
+		}
+		err = ValidateCiPipelineBuildMatrix(request.CiPipeline)
+		if err != nil {
+			impl.logger.Errorw("invalid build matrix for ci pipeline", "err", err, "buildMatrix", request.CiPipeline.BuildMatrix)
+			return nil, err
+		}
+		err = impl.validateCiPipelineStages(request.CiPipeline)
+		if err != nil {
+			return nil, err
+		}
 	}
 	switch request.Action {
 	case bean.CREATE:
@@ -892,7 +970,12 @@ func (impl PipelineBuilderImpl) PatchCiPipeline(request *bean.CiPatchRequest) (c
 		}
 		return res, nil
 	case bean.UPDATE_SOURCE:
-		return impl.patchCiPipelineUpdateSource(ciConfig, request.CiPipeline)
+		res, err := impl.patchCiPipelineUpdateSource(ciConfig, request.CiPipeline)
+		if err != nil {
+			return nil, err
+		}
+		impl.writePipelineConfigChangedEvent(util4.CI, request.CiPipeline.Id, request.AppId, 0, "CI pipeline updated", request.UserId)
+		return res, nil
 	case bean.DELETE:
 		pipeline, err := impl.deletePipeline(request)
 		if err != nil {
@@ -1080,6 +1163,10 @@ func (impl PipelineBuilderImpl) CreateCdPipelines(pipelineCreateRequest *bean.Cd
 	}
 	envPipelineMap := make(map[int]string)
 	for _, pipeline := range pipelineCreateRequest.Pipelines {
+		err = impl.validateCdPipelineStages(pipeline)
+		if err != nil {
+			return nil, err
+		}
 		if envPipelineMap[pipeline.EnvironmentId] != "" {
 			err = &util.ApiError{
 				HttpStatusCode:  http.StatusBadRequest,
@@ -1192,6 +1279,9 @@ func (impl PipelineBuilderImpl) PatchCdPipelines(cdPipelines *bean.CDPatchReques
 		return impl.CreateCdPipelines(pipelineRequest, ctx)
 	case bean.CD_UPDATE:
 		err := impl.updateCdPipeline(ctx, cdPipelines.Pipeline, cdPipelines.UserId)
+		if err == nil {
+			impl.writePipelineConfigChangedEvent(util4.CD, cdPipelines.Pipeline.Id, cdPipelines.AppId, cdPipelines.Pipeline.EnvironmentId, "CD pipeline updated", cdPipelines.UserId)
+		}
 		return pipelineRequest, err
 	case bean.CD_DELETE:
 		err := impl.deleteCdPipeline(cdPipelines.Pipeline.Id, cdPipelines.UserId, ctx, cdPipelines.ForceDelete)
@@ -1201,6 +1291,16 @@ func (impl PipelineBuilderImpl) PatchCdPipelines(cdPipelines *bean.CDPatchReques
 	}
 }
 
+// writePipelineConfigChangedEvent notifies an update of ci or cd pipeline configuration
+func (impl PipelineBuilderImpl) writePipelineConfigChangedEvent(pipelineType util4.PipelineType, pipelineId int, appId int, envId int, configChange string, userId int32) {
+	event := impl.eventFactory.Build(util4.PipelineConfigChanged, &pipelineId, appId, &envId, pipelineType)
+	event = impl.eventFactory.BuildPipelineConfigChangedData(event, configChange, userId)
+	_, evtErr := impl.eventClient.WriteNotificationEvent(event)
+	if evtErr != nil {
+		impl.logger.Errorw("pipeline config changed event not sent", "error", evtErr, "pipelineId", pipelineId, "pipelineType", pipelineType)
+	}
+}
+
 func (impl PipelineBuilderImpl) deleteCdPipeline(pipelineId int, userId int32, ctx context.Context, forceDelete bool) (err error) {
 	//getting children CD pipeline details
 	appWorkflowMapping, err := impl.appWorkflowRepository.FindWFCDMappingByParentCDPipelineId(pipelineId)
@@ -1244,6 +1344,21 @@ func (impl PipelineBuilderImpl) deleteCdPipeline(pipelineId int, userId int32, c
 		impl.logger.Errorw("err in deleting pipeline from db", "id", pipeline, "err", err)
 		return err
 	}
+	//deleting pre & post cd stages, if any
+	preDeployStage, postDeployStage, err := impl.pipelineStageService.GetCdPipelineStageData(pipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in getting pre & post cd stage by cdPipelineId", "err", err, "cdPipelineId", pipelineId)
+		return err
+	}
+	for _, stage := range []*bean3.PipelineStageDto{preDeployStage, postDeployStage} {
+		if stage != nil && stage.Id > 0 {
+			err = impl.pipelineStageService.DeleteCdStage(stage, userId, tx)
+			if err != nil {
+				impl.logger.Errorw("error in deleting cd stage", "err", err, "stage", stage)
+				return err
+			}
+		}
+	}
 
 	//delete app workflow mapping
 	appWorkflowMapping, err = impl.appWorkflowRepository.FindWFCDMappingByCDPipelineId(pipelineId)
@@ -1480,12 +1595,46 @@ func (impl PipelineBuilderImpl) createCdPipeline(ctx context.Context, app *app2.
 		return 0, err
 	}
 
+	//stages are created after pipeline commit as pipeline_stage refers cd pipeline
+	if pipeline.PreDeployStage != nil && len(pipeline.PreDeployStage.Steps) > 0 {
+		err = impl.pipelineStageService.CreateCdStage(pipeline.PreDeployStage, repository5.PIPELINE_STAGE_TYPE_PRE_CD, pipelineId, userId)
+		if err != nil {
+			impl.logger.Errorw("error in creating pre cd stage", "err", err, "preDeployStage", pipeline.PreDeployStage, "cdPipelineId", pipelineId)
+			return pipelineId, err
+		}
+	}
+	if pipeline.PostDeployStage != nil && len(pipeline.PostDeployStage.Steps) > 0 {
+		err = impl.pipelineStageService.CreateCdStage(pipeline.PostDeployStage, repository5.PIPELINE_STAGE_TYPE_POST_CD, pipelineId, userId)
+		if err != nil {
+			impl.logger.Errorw("error in creating post cd stage", "err", err, "postDeployStage", pipeline.PostDeployStage, "cdPipelineId", pipelineId)
+			return pipelineId, err
+		}
+	}
+	if pipeline.AutoRollbackPolicy != nil {
+		err = impl.autoRollbackService.SavePolicy(pipelineId, pipeline.AutoRollbackPolicy, userId)
+		if err != nil {
+			impl.logger.Errorw("error in saving auto rollback policy", "err", err, "autoRollbackPolicy", pipeline.AutoRollbackPolicy, "cdPipelineId", pipelineId)
+			return pipelineId, err
+		}
+	}
+	if pipeline.CanaryAnalysisPolicy != nil {
+		err = impl.canaryAnalysisService.SavePolicy(pipelineId, pipeline.CanaryAnalysisPolicy, userId)
+		if err != nil {
+			impl.logger.Errorw("error in saving canary analysis policy", "err", err, "canaryAnalysisPolicy", pipeline.CanaryAnalysisPolicy, "cdPipelineId", pipelineId)
+			return pipelineId, err
+		}
+	}
+
 	impl.logger.Debugw("pipeline created with GitMaterialId ", "id", pipelineId, "pipeline", pipeline)
 	return pipelineId, nil
 }
 
 func (impl PipelineBuilderImpl) updateCdPipeline(ctx context.Context, pipeline *bean.CDPipelineConfigObject, userID int32) (err error) {
 
+	err = impl.validateCdPipelineStages(pipeline)
+	if err != nil {
+		return err
+	}
 	if len(pipeline.PreStage.Config) > 0 && !strings.Contains(pipeline.PreStage.Config, "beforeStages") {
 		err = &util.ApiError{
 			HttpStatusCode:  http.StatusBadRequest,
@@ -1595,6 +1744,36 @@ func (impl PipelineBuilderImpl) updateCdPipeline(ctx context.Context, pipeline *
 	if err != nil {
 		return err
 	}
+	if pipeline.PreDeployStage != nil {
+		//updating pre cd stage
+		err = impl.pipelineStageService.UpdateCdStage(pipeline.PreDeployStage, repository5.PIPELINE_STAGE_TYPE_PRE_CD, pipeline.Id, userID)
+		if err != nil {
+			impl.logger.Errorw("error in updating pre cd stage", "err", err, "preDeployStage", pipeline.PreDeployStage, "cdPipelineId", pipeline.Id)
+			return err
+		}
+	}
+	if pipeline.PostDeployStage != nil {
+		//updating post cd stage
+		err = impl.pipelineStageService.UpdateCdStage(pipeline.PostDeployStage, repository5.PIPELINE_STAGE_TYPE_POST_CD, pipeline.Id, userID)
+		if err != nil {
+			impl.logger.Errorw("error in updating post cd stage", "err", err, "postDeployStage", pipeline.PostDeployStage, "cdPipelineId", pipeline.Id)
+			return err
+		}
+	}
+	if pipeline.AutoRollbackPolicy != nil {
+		err = impl.autoRollbackService.SavePolicy(pipeline.Id, pipeline.AutoRollbackPolicy, userID)
+		if err != nil {
+			impl.logger.Errorw("error in updating auto rollback policy", "err", err, "autoRollbackPolicy", pipeline.AutoRollbackPolicy, "cdPipelineId", pipeline.Id)
+			return err
+		}
+	}
+	if pipeline.CanaryAnalysisPolicy != nil {
+		err = impl.canaryAnalysisService.SavePolicy(pipeline.Id, pipeline.CanaryAnalysisPolicy, userID)
+		if err != nil {
+			impl.logger.Errorw("error in updating canary analysis policy", "err", err, "canaryAnalysisPolicy", pipeline.CanaryAnalysisPolicy, "cdPipelineId", pipeline.Id)
+			return err
+		}
+	}
 	return nil
 }
 
@@ -1709,6 +1888,22 @@ func (impl PipelineBuilderImpl) GetCdPipelinesForApp(appId int) (cdPipelines *be
 			RunPostStageInEnv:             dbPipeline.RunPostStageInEnv,
 			DeploymentAppType:             dbPipeline.DeploymentAppType,
 		}
+		//getting pre & post deploy stage details
+		pipeline.PreDeployStage, pipeline.PostDeployStage, err = impl.pipelineStageService.GetCdPipelineStageData(dbPipeline.Id)
+		if err != nil {
+			impl.logger.Errorw("error in getting pre & post stage detail by cdPipelineId", "err", err, "cdPipelineId", dbPipeline.Id)
+			return cdPipelines, err
+		}
+		pipeline.AutoRollbackPolicy, err = impl.autoRollbackService.GetPolicy(dbPipeline.Id)
+		if err != nil {
+			impl.logger.Errorw("error in getting auto rollback policy by cdPipelineId", "err", err, "cdPipelineId", dbPipeline.Id)
+			return cdPipelines, err
+		}
+		pipeline.CanaryAnalysisPolicy, err = impl.canaryAnalysisService.GetPolicy(dbPipeline.Id)
+		if err != nil {
+			impl.logger.Errorw("error in getting canary analysis policy by cdPipelineId", "err", err, "cdPipelineId", dbPipeline.Id)
+			return cdPipelines, err
+		}
 		pipelines = append(pipelines, pipeline)
 	}
 	cdPipelines.Pipelines = pipelines
@@ -1741,14 +1936,34 @@ func (impl PipelineBuilderImpl) FetchConfigmapSecretsForCdStages(appId, envId, c
 
 func (impl PipelineBuilderImpl) GetArtifactsByCDPipeline(cdPipelineId int, stage bean2.WorkflowType) (bean.CiArtifactResponse, error) {
 	var ciArtifactsResponse bean.CiArtifactResponse
-	var err error
-	parentId, parentType, err := impl.GetCdParentDetails(cdPipelineId)
+	parentId, parentType, parentCdId, err := impl.getCdStageParent(cdPipelineId, stage)
 	if err != nil {
-		impl.logger.Errorw("error in getting cd parent details", "err", err, "cdPipelineId", cdPipelineId, "stage", stage)
 		return ciArtifactsResponse, err
 	}
+	ciArtifactsResponse, err = impl.GetArtifactsForCdStage(cdPipelineId, parentId, parentType, stage, parentCdId)
+	if err != nil {
+		impl.logger.Errorw("error in getting artifacts for cd", "err", err, "stage", stage, "cdPipelineId", cdPipelineId)
+		return ciArtifactsResponse, err
+	}
+	if stage == bean2.CD_WORKFLOW_TYPE_DEPLOY {
+		err = impl.setArtifactApprovalState(&ciArtifactsResponse)
+		if err != nil {
+			impl.logger.Errorw("error in setting approval state of artifacts", "err", err, "cdPipelineId", cdPipelineId)
+			return ciArtifactsResponse, err
+		}
+	}
+	return ciArtifactsResponse, nil
+}
+
+// getCdStageParent returns the stage whose artifacts are offered for the given stage of cd pipeline, along with
+// the parent cd pipeline used for checking latest image running on parent cd
+func (impl PipelineBuilderImpl) getCdStageParent(cdPipelineId int, stage bean2.WorkflowType) (parentId int, parentType bean2.WorkflowType, parentCdId int, err error) {
+	parentId, parentType, err = impl.GetCdParentDetails(cdPipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in getting cd parent details", "err", err, "cdPipelineId", cdPipelineId, "stage", stage)
+		return 0, "", 0, err
+	}
 	//setting parent cd id for checking latest image running on parent cd
-	parentCdId := 0
 	if parentType == bean2.CD_WORKFLOW_TYPE_POST || (parentType == bean2.CD_WORKFLOW_TYPE_DEPLOY && stage != bean2.CD_WORKFLOW_TYPE_POST) {
 		parentCdId = parentId
 	}
@@ -1756,20 +1971,67 @@ func (impl PipelineBuilderImpl) GetArtifactsByCDPipeline(cdPipelineId int, stage
 	if err != nil && err != pg.ErrNoRows {
 		impl.logger.Errorw("Error in getting cd pipeline details", err, "cdPipelineId", cdPipelineId)
 	}
-	if stage == bean2.CD_WORKFLOW_TYPE_DEPLOY && len(pipeline.PreStageConfig) > 0 {
-		parentId = cdPipelineId
-		parentType = bean2.CD_WORKFLOW_TYPE_PRE
+	if stage == bean2.CD_WORKFLOW_TYPE_DEPLOY {
+		preStageExists := len(pipeline.PreStageConfig) > 0
+		if !preStageExists {
+			preStageExists, err = impl.pipelineStageService.IsCdStageConfigured(cdPipelineId, repository5.PIPELINE_STAGE_TYPE_PRE_CD)
+			if err != nil {
+				impl.logger.Errorw("error in checking pre cd stage", "err", err, "cdPipelineId", cdPipelineId)
+				return 0, "", 0, err
+			}
+		}
+		if preStageExists {
+			parentId = cdPipelineId
+			parentType = bean2.CD_WORKFLOW_TYPE_PRE
+		}
 	}
 	if stage == bean2.CD_WORKFLOW_TYPE_POST {
 		parentId = cdPipelineId
 		parentType = bean2.CD_WORKFLOW_TYPE_DEPLOY
 	}
-	ciArtifactsResponse, err = impl.GetArtifactsForCdStage(cdPipelineId, parentId, parentType, stage, parentCdId)
+	return parentId, parentType, parentCdId, nil
+}
+
+// GetLatestArtifactOfParentStage returns the artifact of the latest successful run of the stage the cd material list
+// takes artifacts from for the given stage, nil if parent stage has no successful artifact yet
+func (impl PipelineBuilderImpl) GetLatestArtifactOfParentStage(cdPipelineId int, stage bean2.WorkflowType) (*bean.CiArtifactBean, error) {
+	parentId, parentType, _, err := impl.getCdStageParent(cdPipelineId, stage)
 	if err != nil {
-		impl.logger.Errorw("error in getting artifacts for cd", "err", err, "stage", stage, "cdPipelineId", cdPipelineId)
-		return ciArtifactsResponse, err
+		return nil, err
 	}
-	return ciArtifactsResponse, nil
+	//parent artifacts are built latest first, ci parent by artifact and cd parent stages by successful runner
+	ciArtifacts, err := impl.BuildArtifactsForParentStage(cdPipelineId, parentId, parentType, nil, make(map[int]int), 10, 0)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting artifacts of parent stage", "err", err, "parentStage", parentType, "cdPipelineId", cdPipelineId)
+		return nil, err
+	}
+	if len(ciArtifacts) == 0 {
+		return nil, nil
+	}
+	return &ciArtifacts[0], nil
+}
+
+func (impl PipelineBuilderImpl) setArtifactApprovalState(ciArtifactsResponse *bean.CiArtifactResponse) error {
+	var ciArtifactIds []int
+	for _, ciArtifact := range ciArtifactsResponse.CiArtifacts {
+		ciArtifactIds = append(ciArtifactIds, ciArtifact.Id)
+	}
+	approvalStates, err := impl.deploymentApprovalService.GetApprovalStateForArtifacts(ciArtifactsResponse.CdPipelineId, ciArtifactIds)
+	if err != nil {
+		return err
+	}
+	for i, ciArtifact := range ciArtifactsResponse.CiArtifacts {
+		approvalState, ok := approvalStates[ciArtifact.Id]
+		if !ok || !approvalState.ApprovalRequired {
+			continue
+		}
+		ciArtifactsResponse.ApprovalRequired = true
+		ciArtifactsResponse.RequiredApprovals = approvalState.RequiredApprovals
+		ciArtifactsResponse.CiArtifacts[i].ApprovalStatus = string(approvalState.Status)
+		ciArtifactsResponse.CiArtifacts[i].ApprovalRequestId = approvalState.ApprovalRequestId
+		ciArtifactsResponse.CiArtifacts[i].ApprovedBy = approvalState.ApprovedBy
+	}
+	return nil
 }
 
 func (impl PipelineBuilderImpl) GetCdParentDetails(cdPipelineId int) (parentId int, parentType bean2.WorkflowType, err error) {
@@ -1787,7 +2049,15 @@ func (impl PipelineBuilderImpl) GetCdParentDetails(cdPipelineId int) (parentId i
 			impl.logger.Errorw("Error in fetching cd pipeline details", err, "pipelineId", parentId)
 			return 0, "", err
 		}
-		if len(pipeline.PostStageConfig) > 0 {
+		postStageExists := len(pipeline.PostStageConfig) > 0
+		if !postStageExists {
+			postStageExists, err = impl.pipelineStageService.IsCdStageConfigured(parentId, repository5.PIPELINE_STAGE_TYPE_POST_CD)
+			if err != nil {
+				impl.logger.Errorw("error in checking post cd stage", "err", err, "pipelineId", parentId)
+				return 0, "", err
+			}
+		}
+		if postStageExists {
 			return parentId, bean2.CD_WORKFLOW_TYPE_POST, nil
 		} else {
 			return parentId, bean2.CD_WORKFLOW_TYPE_DEPLOY, nil
@@ -2278,6 +2548,24 @@ func (impl PipelineBuilderImpl) GetCdPipelineById(pipelineId int) (cdPipeline *b
 		RunPostStageInEnv:             dbPipeline.RunPostStageInEnv,
 		CdArgoSetup:                   environment.Cluster.CdArgoSetup,
 	}
+	//getting pre & post deploy stage details
+	preDeployStage, postDeployStage, err := impl.pipelineStageService.GetCdPipelineStageData(dbPipeline.Id)
+	if err != nil {
+		impl.logger.Errorw("error in getting pre & post stage detail by cdPipelineId", "err", err, "cdPipelineId", dbPipeline.Id)
+		return nil, err
+	}
+	cdPipeline.PreDeployStage = preDeployStage
+	cdPipeline.PostDeployStage = postDeployStage
+	cdPipeline.AutoRollbackPolicy, err = impl.autoRollbackService.GetPolicy(dbPipeline.Id)
+	if err != nil {
+		impl.logger.Errorw("error in getting auto rollback policy by cdPipelineId", "err", err, "cdPipelineId", dbPipeline.Id)
+		return nil, err
+	}
+	cdPipeline.CanaryAnalysisPolicy, err = impl.canaryAnalysisService.GetPolicy(dbPipeline.Id)
+	if err != nil {
+		impl.logger.Errorw("error in getting canary analysis policy by cdPipelineId", "err", err, "cdPipelineId", dbPipeline.Id)
+		return nil, err
+	}
 
 	return cdPipeline, err
 }
@@ -2373,6 +2661,12 @@ func (impl PipelineBuilderImpl) GetCiPipelineById(pipelineId int) (ciPipeline *b
 		AfterDockerBuildScripts:  afterDockerBuildScripts,
 		ScanEnabled:              pipeline.ScanEnabled,
 		IsDockerConfigOverridden: pipeline.IsDockerConfigOverridden,
+		CronSchedule:             pipeline.CronSchedule,
+	}
+	ciPipeline.BuildMatrix, err = GetCiBuildMatrix(pipeline)
+	if err != nil {
+		impl.logger.Errorw("error in unmarshalling build matrix", "err", err, "ciPipelineId", pipeline.Id)
+		return nil, err
 	}
 	if !ciPipeline.IsExternal && ciPipeline.IsDockerConfigOverridden {
 		templateOverride, err := impl.ciTemplateOverrideRepository.FindByCiPipelineId(ciPipeline.Id)
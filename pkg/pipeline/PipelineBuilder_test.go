@@ -0,0 +1,136 @@
+package pipeline
+
+import (
+	bean2 "github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/client/argocdServer/application"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/appWorkflow"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	repository5 "github.com/devtron-labs/devtron/pkg/pipeline/repository"
+	"go.uber.org/zap"
+	"testing"
+)
+
+type fakeAppWorkflowRepository struct {
+	appWorkflow.AppWorkflowRepository
+	mappings map[int]*appWorkflow.AppWorkflowMapping
+}
+
+func (repo *fakeAppWorkflowRepository) FindWFCDMappingByCDPipelineId(cdPipelineId int) ([]*appWorkflow.AppWorkflowMapping, error) {
+	return []*appWorkflow.AppWorkflowMapping{repo.mappings[cdPipelineId]}, nil
+}
+
+type fakePipelineRepository struct {
+	pipelineConfig.PipelineRepository
+	pipelines map[int]*pipelineConfig.Pipeline
+}
+
+func (repo *fakePipelineRepository) FindById(id int) (*pipelineConfig.Pipeline, error) {
+	return repo.pipelines[id], nil
+}
+
+// fakeCdWorkflowRepository serves runners of a pipeline stage latest first
+type fakeCdWorkflowRepository struct {
+	pipelineConfig.CdWorkflowRepository
+	runners []pipelineConfig.CdWorkflowRunner
+}
+
+func (repo *fakeCdWorkflowRepository) FindArtifactByPipelineIdAndRunnerType(pipelineId int, runnerType bean2.WorkflowType, limit int) ([]pipelineConfig.CdWorkflowRunner, error) {
+	var runners []pipelineConfig.CdWorkflowRunner
+	for _, runner := range repo.runners {
+		if runner.CdWorkflow.PipelineId == pipelineId && runner.WorkflowType == runnerType {
+			runners = append(runners, runner)
+		}
+	}
+	return runners, nil
+}
+
+type fakeCiArtifactRepository struct {
+	repository.CiArtifactRepository
+	artifacts []repository.CiArtifact
+}
+
+func (repo *fakeCiArtifactRepository) GetArtifactsByCDPipeline(cdPipelineId, limit int) ([]repository.CiArtifact, error) {
+	return repo.artifacts, nil
+}
+
+func TestGetLatestArtifactOfParentStage(t *testing.T) {
+	runner := func(pipelineId int, runnerType bean2.WorkflowType, status string, artifactId int) pipelineConfig.CdWorkflowRunner {
+		return pipelineConfig.CdWorkflowRunner{
+			WorkflowType: runnerType,
+			Status:       status,
+			CdWorkflow:   &pipelineConfig.CdWorkflow{PipelineId: pipelineId, CiArtifact: &repository.CiArtifact{Id: artifactId}},
+		}
+	}
+	tests := []struct {
+		name           string
+		cdPipelineId   int
+		stages         map[int]repository5.PipelineStageType
+		runners        []pipelineConfig.CdWorkflowRunner
+		wantArtifactId int
+	}{
+		{
+			name:           "ci parent deploys latest ci artifact",
+			cdPipelineId:   1,
+			wantArtifactId: 30,
+		},
+		{
+			name:           "pre stage deploys artifact of latest successful pre stage",
+			cdPipelineId:   1,
+			stages:         map[int]repository5.PipelineStageType{1: repository5.PIPELINE_STAGE_TYPE_PRE_CD},
+			runners:        []pipelineConfig.CdWorkflowRunner{runner(1, bean2.CD_WORKFLOW_TYPE_PRE, WorkflowFailed, 30), runner(1, bean2.CD_WORKFLOW_TYPE_PRE, application.SUCCEEDED, 20)},
+			wantArtifactId: 20,
+		},
+		{
+			name:           "parent cd deploys artifact of its latest healthy deployment",
+			cdPipelineId:   2,
+			runners:        []pipelineConfig.CdWorkflowRunner{runner(1, bean2.CD_WORKFLOW_TYPE_DEPLOY, application.Degraded, 30), runner(1, bean2.CD_WORKFLOW_TYPE_DEPLOY, application.Healthy, 20)},
+			wantArtifactId: 20,
+		},
+		{
+			name:           "parent cd post stage deploys artifact of its latest successful post stage",
+			cdPipelineId:   2,
+			stages:         map[int]repository5.PipelineStageType{1: repository5.PIPELINE_STAGE_TYPE_POST_CD},
+			runners:        []pipelineConfig.CdWorkflowRunner{runner(1, bean2.CD_WORKFLOW_TYPE_DEPLOY, application.Healthy, 30), runner(1, bean2.CD_WORKFLOW_TYPE_POST, application.SUCCEEDED, 10)},
+			wantArtifactId: 10,
+		},
+		{
+			name:         "parent cd without successful deployment has no artifact",
+			cdPipelineId: 2,
+			runners:      []pipelineConfig.CdWorkflowRunner{runner(1, bean2.CD_WORKFLOW_TYPE_DEPLOY, application.Degraded, 30)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stageRepository := &fakePipelineStageRepository{}
+			for cdPipelineId, stageType := range tt.stages {
+				stage := &repository5.PipelineStage{Id: cdPipelineId, Type: stageType, CdPipelineId: cdPipelineId}
+				stageRepository.stages = append(stageRepository.stages, stage)
+				stageRepository.steps = append(stageRepository.steps, &repository5.PipelineStageStep{Id: stage.Id, PipelineStageId: stage.Id, StepType: repository5.PIPELINE_STEP_TYPE_INLINE})
+			}
+			logger := zap.NewNop().Sugar()
+			impl := PipelineBuilderImpl{
+				logger: logger,
+				appWorkflowRepository: &fakeAppWorkflowRepository{mappings: map[int]*appWorkflow.AppWorkflowMapping{
+					1: {ComponentId: 1, ParentId: 5, ParentType: appWorkflow.CIPIPELINE},
+					2: {ComponentId: 2, ParentId: 1, ParentType: appWorkflow.CDPIPELINE},
+				}},
+				pipelineRepository:   &fakePipelineRepository{pipelines: map[int]*pipelineConfig.Pipeline{1: {Id: 1}, 2: {Id: 2}}},
+				cdWorkflowRepository: &fakeCdWorkflowRepository{runners: tt.runners},
+				ciArtifactRepository: &fakeCiArtifactRepository{artifacts: []repository.CiArtifact{{Id: 30}, {Id: 20}, {Id: 10}}},
+				pipelineStageService: NewPipelineStageService(logger, stageRepository, &fakeGlobalPluginRepository{}),
+			}
+			artifact, err := impl.GetLatestArtifactOfParentStage(tt.cdPipelineId, bean2.CD_WORKFLOW_TYPE_DEPLOY)
+			if err != nil {
+				t.Fatalf("GetLatestArtifactOfParentStage() error = %v", err)
+			}
+			gotArtifactId := 0
+			if artifact != nil {
+				gotArtifactId = artifact.Id
+			}
+			if gotArtifactId != tt.wantArtifactId {
+				t.Errorf("GetLatestArtifactOfParentStage() artifact = %d, want %d", gotArtifactId, tt.wantArtifactId)
+			}
+		})
+	}
+}
@@ -18,6 +18,15 @@ type PipelineStageService interface {
 	BuildPrePostAndRefPluginStepsDataForWfRequest(ciPipelineId int) ([]*bean.StepObject, []*bean.StepObject, []*bean.RefPluginObject, error)
 
 	GetCiPipelineStageDataDeepCopy(ciPipelineId int) (preCiStage *bean.PipelineStageDto, postCiStage *bean.PipelineStageDto, err error)
+
+	GetCdPipelineStageData(cdPipelineId int) (preCdStage *bean.PipelineStageDto, postCdStage *bean.PipelineStageDto, err error)
+	CreateCdStage(stageReq *bean.PipelineStageDto, stageType repository.PipelineStageType, cdPipelineId int, userId int32) error
+	UpdateCdStage(stageReq *bean.PipelineStageDto, stageType repository.PipelineStageType, cdPipelineId int, userId int32) error
+	DeleteCdStage(stageReq *bean.PipelineStageDto, userId int32, tx *pg.Tx) error
+	BuildPrePostAndRefPluginStepsDataForCdWfRequest(cdPipelineId int) ([]*bean.StepObject, []*bean.StepObject, []*bean.RefPluginObject, error)
+	IsCdStageConfigured(cdPipelineId int, stageType repository.PipelineStageType) (bool, error)
+
+	GetCdPipelineStageDataDeepCopy(cdPipelineId int) (preCdStage *bean.PipelineStageDto, postCdStage *bean.PipelineStageDto, err error)
 }
 
 func NewPipelineStageService(logger *zap.SugaredLogger,
@@ -65,6 +74,36 @@ func (impl *PipelineStageServiceImpl) GetCiPipelineStageDataDeepCopy(ciPipelineI
 	}
 	return preCiStage, postCiStage, nil
 }
+
+func (impl *PipelineStageServiceImpl) GetCdPipelineStageDataDeepCopy(cdPipelineId int) (*bean.PipelineStageDto, *bean.PipelineStageDto, error) {
+	//getting all stages by cd pipeline id
+	cdStages, err := impl.pipelineStageRepository.GetAllCdStagesByCdPipelineId(cdPipelineId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting all cdStages by cdPipelineId", "err", err, "cdPipelineId", cdPipelineId)
+		return nil, nil, err
+	}
+	var preCdStage *bean.PipelineStageDto
+	var postCdStage *bean.PipelineStageDto
+	for _, cdStage := range cdStages {
+		if cdStage.Type == repository.PIPELINE_STAGE_TYPE_PRE_CD {
+			preCdStage, err = impl.BuildCiStageDataDeepCopy(cdStage)
+			if err != nil {
+				impl.logger.Errorw("error in getting cd stage data", "err", err, "cdStage", cdStage)
+				return nil, nil, err
+			}
+		} else if cdStage.Type == repository.PIPELINE_STAGE_TYPE_POST_CD {
+			postCdStage, err = impl.BuildCiStageDataDeepCopy(cdStage)
+			if err != nil {
+				impl.logger.Errorw("error in getting cd stage data", "err", err, "cdStage", cdStage)
+				return nil, nil, err
+			}
+		} else {
+			impl.logger.Errorw("found improper stage mapped with cdPipeline", "cdPipelineId", cdPipelineId, "stage", cdStage)
+		}
+	}
+	return preCdStage, postCdStage, nil
+}
+
 func (impl *PipelineStageServiceImpl) BuildCiStageDataDeepCopy(ciStage *repository.PipelineStage) (*bean.PipelineStageDto, error) {
 	stageData := &bean.PipelineStageDto{
 		Name:        ciStage.Name,
@@ -85,6 +124,10 @@ func (impl *PipelineStageServiceImpl) BuildCiStageDataDeepCopy(ciStage *reposito
 			Description:         step.Description,
 			OutputDirectoryPath: step.OutputDirectoryPath,
 			StepType:            step.StepType,
+			Timeout:             step.Timeout,
+			RetryCount:          step.RetryCount,
+			RetryBackoff:        step.RetryBackoff,
+			ContinueOnError:     step.ContinueOnError,
 		}
 		if step.StepType == repository.PIPELINE_STEP_TYPE_INLINE {
 			inlineStepDetail, err := impl.BuildInlineStepDataDeepCopy(step)
@@ -276,6 +319,52 @@ func (impl *PipelineStageServiceImpl) GetCiPipelineStageData(ciPipelineId int) (
 	return preCiStage, postCiStage, nil
 }
 
+// GetCdPipelineStageData returns PRE_CD & POST_CD stages of a cd pipeline, built the same way as ci stages
+func (impl *PipelineStageServiceImpl) GetCdPipelineStageData(cdPipelineId int) (*bean.PipelineStageDto, *bean.PipelineStageDto, error) {
+	//getting all stages by cd pipeline id
+	cdStages, err := impl.pipelineStageRepository.GetAllCdStagesByCdPipelineId(cdPipelineId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting all cdStages by cdPipelineId", "err", err, "cdPipelineId", cdPipelineId)
+		return nil, nil, err
+	}
+	var preCdStage *bean.PipelineStageDto
+	var postCdStage *bean.PipelineStageDto
+	for _, cdStage := range cdStages {
+		if cdStage.Type == repository.PIPELINE_STAGE_TYPE_PRE_CD {
+			preCdStage, err = impl.BuildCiStageData(cdStage)
+			if err != nil {
+				impl.logger.Errorw("error in getting cd stage data", "err", err, "cdStage", cdStage)
+				return nil, nil, err
+			}
+		} else if cdStage.Type == repository.PIPELINE_STAGE_TYPE_POST_CD {
+			postCdStage, err = impl.BuildCiStageData(cdStage)
+			if err != nil {
+				impl.logger.Errorw("error in getting cd stage data", "err", err, "cdStage", cdStage)
+				return nil, nil, err
+			}
+		} else {
+			impl.logger.Errorw("found improper stage mapped with cdPipeline", "cdPipelineId", cdPipelineId, "stage", cdStage)
+		}
+	}
+	return preCdStage, postCdStage, nil
+}
+
+func (impl *PipelineStageServiceImpl) IsCdStageConfigured(cdPipelineId int, stageType repository.PipelineStageType) (bool, error) {
+	stage, err := impl.pipelineStageRepository.GetCdStageByCdPipelineIdAndStageType(cdPipelineId, stageType)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting cd stage by cdPipelineId and stageType", "err", err, "cdPipelineId", cdPipelineId, "stageType", stageType)
+		return false, err
+	} else if err == pg.ErrNoRows {
+		return false, nil
+	}
+	stepIds, err := impl.pipelineStageRepository.GetStepIdsByStageId(stage.Id)
+	if err != nil {
+		impl.logger.Errorw("error in getting stepIds by stageId", "err", err, "stageId", stage.Id)
+		return false, err
+	}
+	return len(stepIds) > 0, nil
+}
+
 func (impl *PipelineStageServiceImpl) BuildCiStageData(ciStage *repository.PipelineStage) (*bean.PipelineStageDto, error) {
 	stageData := &bean.PipelineStageDto{
 		Id:          ciStage.Id,
@@ -298,6 +387,10 @@ func (impl *PipelineStageServiceImpl) BuildCiStageData(ciStage *repository.Pipel
 			Description:         step.Description,
 			OutputDirectoryPath: step.OutputDirectoryPath,
 			StepType:            step.StepType,
+			Timeout:             step.Timeout,
+			RetryCount:          step.RetryCount,
+			RetryBackoff:        step.RetryBackoff,
+			ContinueOnError:     step.ContinueOnError,
 		}
 		if step.StepType == repository.PIPELINE_STEP_TYPE_INLINE {
 			inlineStepDetail, err := impl.BuildInlineStepData(step)
@@ -521,6 +614,10 @@ func (impl *PipelineStageServiceImpl) CreateStageSteps(steps []*bean.PipelineSta
 				ScriptId:            scriptEntryId,
 				OutputDirectoryPath: step.OutputDirectoryPath,
 				DependentOnStep:     dependentOnStep,
+				Timeout:             step.Timeout,
+				RetryCount:          step.RetryCount,
+				RetryBackoff:        step.RetryBackoff,
+				ContinueOnError:     step.ContinueOnError,
 				Deleted:             false,
 				AuditLog: sql.AuditLog{
 					CreatedOn: time.Now(),
@@ -549,6 +646,10 @@ func (impl *PipelineStageServiceImpl) CreateStageSteps(steps []*bean.PipelineSta
 				RefPluginId:         refPluginStepDetail.PluginId,
 				OutputDirectoryPath: step.OutputDirectoryPath,
 				DependentOnStep:     dependentOnStep,
+				Timeout:             step.Timeout,
+				RetryCount:          step.RetryCount,
+				RetryBackoff:        step.RetryBackoff,
+				ContinueOnError:     step.ContinueOnError,
 				Deleted:             false,
 				AuditLog: sql.AuditLog{
 					CreatedOn: time.Now(),
@@ -807,6 +908,41 @@ func (impl *PipelineStageServiceImpl) CreateConditionsEntryInDb(stepId int, cond
 
 //CreateCiStage and related methods ends
 
+//CreateCdStage and related methods starts
+func (impl *PipelineStageServiceImpl) CreateCdStage(stageReq *bean.PipelineStageDto, stageType repository.PipelineStageType, cdPipelineId int, userId int32) error {
+	stage := &repository.PipelineStage{
+		Name:         stageReq.Name,
+		Description:  stageReq.Description,
+		Type:         stageType,
+		Deleted:      false,
+		CdPipelineId: cdPipelineId,
+		AuditLog: sql.AuditLog{
+			CreatedOn: time.Now(),
+			CreatedBy: userId,
+			UpdatedOn: time.Now(),
+			UpdatedBy: userId,
+		},
+	}
+	stage, err := impl.pipelineStageRepository.CreateCiStage(stage)
+	if err != nil {
+		impl.logger.Errorw("error in creating entry for cdStage", "err", err, "cdStage", stage)
+		return err
+	}
+	indexNameString := make(map[int]string)
+	for _, step := range stageReq.Steps {
+		indexNameString[step.Index] = step.Name
+	}
+	//creating stage steps and all related data
+	err = impl.CreateStageSteps(stageReq.Steps, stage.Id, userId, indexNameString)
+	if err != nil {
+		impl.logger.Errorw("error in creating stage steps for cd stage", "err", err, "stageId", stage.Id)
+		return err
+	}
+	return nil
+}
+
+//CreateCdStage and related methods ends
+
 //UpdateCiStage and related methods starts
 func (impl *PipelineStageServiceImpl) UpdateCiStage(stageReq *bean.PipelineStageDto, stageType repository.PipelineStageType, ciPipelineId int, userId int32) error {
 	//getting stage by stageType and ciPipelineId
@@ -940,6 +1076,10 @@ func (impl *PipelineStageServiceImpl) UpdateStageSteps(steps []*bean.PipelineSta
 			StepType:            step.StepType,
 			OutputDirectoryPath: step.OutputDirectoryPath,
 			DependentOnStep:     dependentOnStep,
+			Timeout:             step.Timeout,
+			RetryCount:          step.RetryCount,
+			RetryBackoff:        step.RetryBackoff,
+			ContinueOnError:     step.ContinueOnError,
 			Deleted:             false,
 			AuditLog: sql.AuditLog{
 				CreatedOn: savedStep.CreatedOn,
@@ -1342,6 +1482,46 @@ func (impl *PipelineStageServiceImpl) UpdatePipelineStageStepConditions(stepId i
 
 //UpdateCiStage and related methods ends
 
+//UpdateCdStage and related methods starts
+func (impl *PipelineStageServiceImpl) UpdateCdStage(stageReq *bean.PipelineStageDto, stageType repository.PipelineStageType, cdPipelineId int, userId int32) error {
+	//getting stage by stageType and cdPipelineId
+	stageOld, err := impl.pipelineStageRepository.GetCdStageByCdPipelineIdAndStageType(cdPipelineId, stageType)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting stageId by cdPipelineId and stageType", "err", err, "cdPipelineId", cdPipelineId, "stageType", stageType)
+		return err
+	} else if err == pg.ErrNoRows {
+		//no stage found, creating new stage
+		stageReq.Id = 0
+		err = impl.CreateCdStage(stageReq, stageType, cdPipelineId, userId)
+		if err != nil {
+			impl.logger.Errorw("error in creating new cd stage", "err", err, "cdStageReq", stageReq)
+			return err
+		}
+	} else {
+		//stageId found, to handle as an update request
+		stageReq.Id = stageOld.Id
+		stageUpdateReq := stageOld
+		stageUpdateReq.Name = stageReq.Name
+		stageUpdateReq.Description = stageReq.Description
+		stageUpdateReq.UpdatedBy = userId
+		stageUpdateReq.UpdatedOn = time.Now()
+		_, err = impl.pipelineStageRepository.UpdateCiStage(stageUpdateReq)
+		if err != nil {
+			impl.logger.Errorw("error in updating entry for cdStage", "err", err, "cdStage", stageUpdateReq)
+			return err
+		}
+		// steps, variables & conditions are stage level entities, same filtering as ci stage applies here
+		err = impl.FilterAndActOnStepsInCiStageUpdateRequest(stageReq, userId)
+		if err != nil {
+			impl.logger.Errorw("error in filtering and performing actions on steps in cd stage update request", "err", err, "stageReq", stageReq)
+			return err
+		}
+	}
+	return nil
+}
+
+//UpdateCdStage and related methods ends
+
 //DeleteCiStage and related methods starts
 func (impl *PipelineStageServiceImpl) DeleteCiStage(stageReq *bean.PipelineStageDto, userId int32, tx *pg.Tx) error {
 	//marking stage deleted
@@ -1417,6 +1597,16 @@ func (impl *PipelineStageServiceImpl) DeleteCiStage(stageReq *bean.PipelineStage
 
 //DeleteCiStage and related methods starts
 
+func (impl *PipelineStageServiceImpl) DeleteCdStage(stageReq *bean.PipelineStageDto, userId int32, tx *pg.Tx) error {
+	//cd stage is stored in same tables as ci stage, deletion flow is identical
+	err := impl.DeleteCiStage(stageReq, userId, tx)
+	if err != nil {
+		impl.logger.Errorw("error in deleting cd stage", "err", err, "cdStageId", stageReq.Id)
+		return err
+	}
+	return nil
+}
+
 //BuildPrePostAndRefPluginStepsDataForWfRequest and related methods starts
 func (impl *PipelineStageServiceImpl) BuildPrePostAndRefPluginStepsDataForWfRequest(ciPipelineId int) ([]*bean.StepObject, []*bean.StepObject, []*bean.RefPluginObject, error) {
 	//get all stages By ciPipelineId
@@ -1456,6 +1646,44 @@ func (impl *PipelineStageServiceImpl) BuildPrePostAndRefPluginStepsDataForWfRequ
 	return preCiSteps, postCiSteps, refPluginsData, nil
 }
 
+func (impl *PipelineStageServiceImpl) BuildPrePostAndRefPluginStepsDataForCdWfRequest(cdPipelineId int) ([]*bean.StepObject, []*bean.StepObject, []*bean.RefPluginObject, error) {
+	//get all stages By cdPipelineId
+	cdStages, err := impl.pipelineStageRepository.GetAllCdStagesByCdPipelineId(cdPipelineId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting all cd stages by cdPipelineId", "err", err, "cdPipelineId", cdPipelineId)
+		return nil, nil, nil, err
+	}
+	var preCdSteps []*bean.StepObject
+	var postCdSteps []*bean.StepObject
+	var refPluginsData []*bean.RefPluginObject
+	var refPluginIds []int
+	for _, cdStage := range cdStages {
+		var refIds []int
+		if cdStage.Type == repository.PIPELINE_STAGE_TYPE_PRE_CD {
+			preCdSteps, refIds, err = impl.BuildCiStageDataForWfRequest(cdStage)
+			if err != nil {
+				impl.logger.Errorw("error in getting pre cd steps data for wf request", "err", err, "cdStage", cdStage)
+				return nil, nil, nil, err
+			}
+		} else if cdStage.Type == repository.PIPELINE_STAGE_TYPE_POST_CD {
+			postCdSteps, refIds, err = impl.BuildCiStageDataForWfRequest(cdStage)
+			if err != nil {
+				impl.logger.Errorw("error in getting post cd steps data for wf request", "err", err, "cdStage", cdStage)
+				return nil, nil, nil, err
+			}
+		}
+		refPluginIds = append(refPluginIds, refIds...)
+	}
+	if len(refPluginIds) > 0 {
+		refPluginsData, err = impl.BuildRefPluginStepDataForWfRequest(refPluginIds)
+		if err != nil {
+			impl.logger.Errorw("error in building ref plugin step data", "err", err, "refPluginIds", refPluginIds)
+			return nil, nil, nil, err
+		}
+	}
+	return preCdSteps, postCdSteps, refPluginsData, nil
+}
+
 func (impl *PipelineStageServiceImpl) BuildCiStageDataForWfRequest(ciStage *repository.PipelineStage) ([]*bean.StepObject, []int, error) {
 	//getting all steps for this stage
 	steps, err := impl.pipelineStageRepository.GetAllStepsByStageId(ciStage.Id)
@@ -1552,10 +1780,14 @@ func (impl *PipelineStageServiceImpl) GetRefPluginStepsByIds(refPluginIds []int,
 
 func (impl *PipelineStageServiceImpl) BuildCiStepDataForWfRequest(step *repository.PipelineStageStep) (*bean.StepObject, error) {
 	stepData := &bean.StepObject{
-		Name:          step.Name,
-		Index:         step.Index,
-		StepType:      string(step.StepType),
-		ArtifactPaths: step.OutputDirectoryPath,
+		Name:                  step.Name,
+		Index:                 step.Index,
+		StepType:              string(step.StepType),
+		ArtifactPaths:         step.OutputDirectoryPath,
+		TimeoutInSeconds:      step.Timeout,
+		RetryCount:            step.RetryCount,
+		RetryBackoffInSeconds: step.RetryBackoff,
+		ContinueOnError:       step.ContinueOnError,
 	}
 	if step.StepType == repository.PIPELINE_STEP_TYPE_INLINE {
 		//get script and mapping data
@@ -1650,6 +1882,10 @@ func (impl *PipelineStageServiceImpl) BuildVariableAndConditionDataForWfRequest(
 				variableData.VariableType = bean.VARIABLE_TYPE_REF_POST_CI
 			} else if variable.ReferenceVariableStage == repository.PIPELINE_STAGE_TYPE_PRE_CI {
 				variableData.VariableType = bean.VARIABLE_TYPE_REF_PRE_CI
+			} else if variable.ReferenceVariableStage == repository.PIPELINE_STAGE_TYPE_PRE_CD {
+				variableData.VariableType = bean.VARIABLE_TYPE_REF_PRE_CD
+			} else if variable.ReferenceVariableStage == repository.PIPELINE_STAGE_TYPE_POST_CD {
+				variableData.VariableType = bean.VARIABLE_TYPE_REF_POST_CD
 			}
 		}
 		if variable.VariableType == repository.PIPELINE_STAGE_STEP_VARIABLE_TYPE_INPUT {
@@ -0,0 +1,235 @@
+package pipeline
+
+import (
+	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/repository"
+	repository2 "github.com/devtron-labs/devtron/pkg/plugin/repository"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// fakePipelineStageRepository keeps stages and steps in memory, methods not used by cd stage flows are not implemented
+type fakePipelineStageRepository struct {
+	repository.PipelineStageRepository
+	stages []*repository.PipelineStage
+	steps  []*repository.PipelineStageStep
+}
+
+func (repo *fakePipelineStageRepository) CreateCiStage(stage *repository.PipelineStage) (*repository.PipelineStage, error) {
+	stage.Id = len(repo.stages) + 1
+	repo.stages = append(repo.stages, stage)
+	return stage, nil
+}
+
+func (repo *fakePipelineStageRepository) UpdateCiStage(stage *repository.PipelineStage) (*repository.PipelineStage, error) {
+	for i, existing := range repo.stages {
+		if existing.Id == stage.Id {
+			repo.stages[i] = stage
+		}
+	}
+	return stage, nil
+}
+
+func (repo *fakePipelineStageRepository) GetAllCdStagesByCdPipelineId(cdPipelineId int) ([]*repository.PipelineStage, error) {
+	var stages []*repository.PipelineStage
+	for _, stage := range repo.stages {
+		if stage.CdPipelineId == cdPipelineId && !stage.Deleted {
+			stages = append(stages, stage)
+		}
+	}
+	return stages, nil
+}
+
+func (repo *fakePipelineStageRepository) GetCdStageByCdPipelineIdAndStageType(cdPipelineId int, stageType repository.PipelineStageType) (*repository.PipelineStage, error) {
+	for _, stage := range repo.stages {
+		if stage.CdPipelineId == cdPipelineId && stage.Type == stageType && !stage.Deleted {
+			return stage, nil
+		}
+	}
+	return nil, pg.ErrNoRows
+}
+
+func (repo *fakePipelineStageRepository) GetStepIdsByStageId(stageId int) ([]int, error) {
+	var ids []int
+	for _, step := range repo.steps {
+		if step.PipelineStageId == stageId && !step.Deleted {
+			ids = append(ids, step.Id)
+		}
+	}
+	return ids, nil
+}
+
+func (repo *fakePipelineStageRepository) GetAllStepsByStageId(stageId int) ([]*repository.PipelineStageStep, error) {
+	var steps []*repository.PipelineStageStep
+	for _, step := range repo.steps {
+		if step.PipelineStageId == stageId && !step.Deleted {
+			steps = append(steps, step)
+		}
+	}
+	return steps, nil
+}
+
+func (repo *fakePipelineStageRepository) MarkStepsDeletedByStageId(stageId int) error {
+	for _, step := range repo.steps {
+		if step.PipelineStageId == stageId {
+			step.Deleted = true
+		}
+	}
+	return nil
+}
+
+func (repo *fakePipelineStageRepository) GetScriptDetailById(id int) (*repository.PluginPipelineScript, error) {
+	return &repository.PluginPipelineScript{Id: id, Type: repository2.SCRIPT_TYPE_SHELL, Script: "echo stage"}, nil
+}
+
+func (repo *fakePipelineStageRepository) GetScriptMappingDetailByScriptId(scriptId int) ([]*repository.ScriptPathArgPortMapping, error) {
+	return nil, pg.ErrNoRows
+}
+
+func (repo *fakePipelineStageRepository) GetVariablesByStepId(stepId int) ([]*repository.PipelineStageStepVariable, error) {
+	return nil, pg.ErrNoRows
+}
+
+func (repo *fakePipelineStageRepository) GetConditionsByStepId(stepId int) ([]*repository.PipelineStageStepCondition, error) {
+	return nil, pg.ErrNoRows
+}
+
+// fakeGlobalPluginRepository serves steps of ref plugins used by cd stages
+type fakeGlobalPluginRepository struct {
+	repository2.GlobalPluginRepository
+	steps []*repository2.PluginStep
+}
+
+func (repo *fakeGlobalPluginRepository) GetStepsByPluginIds(pluginIds []int) ([]*repository2.PluginStep, error) {
+	var steps []*repository2.PluginStep
+	for _, step := range repo.steps {
+		for _, pluginId := range pluginIds {
+			if step.PluginId == pluginId {
+				steps = append(steps, step)
+			}
+		}
+	}
+	return steps, nil
+}
+
+func (repo *fakeGlobalPluginRepository) GetScriptDetailById(id int) (*repository2.PluginPipelineScript, error) {
+	return &repository2.PluginPipelineScript{Id: id, Type: repository2.SCRIPT_TYPE_SHELL, Script: "echo plugin"}, nil
+}
+
+func (repo *fakeGlobalPluginRepository) GetScriptMappingDetailByScriptId(scriptId int) ([]*repository2.ScriptPathArgPortMapping, error) {
+	return nil, pg.ErrNoRows
+}
+
+func (repo *fakeGlobalPluginRepository) GetVariablesByStepId(stepId int) ([]*repository2.PluginStepVariable, error) {
+	return nil, pg.ErrNoRows
+}
+
+func (repo *fakeGlobalPluginRepository) GetConditionsByStepId(stepId int) ([]*repository2.PluginStepCondition, error) {
+	return nil, pg.ErrNoRows
+}
+
+func TestCdStageCreateUpdateAndFetch(t *testing.T) {
+	stageRepository := &fakePipelineStageRepository{}
+	impl := NewPipelineStageService(zap.NewNop().Sugar(), stageRepository, &fakeGlobalPluginRepository{})
+	cdPipelineId := 7
+
+	err := impl.CreateCdStage(&bean.PipelineStageDto{Name: "pre deploy"}, repository.PIPELINE_STAGE_TYPE_PRE_CD, cdPipelineId, 1)
+	if err != nil {
+		t.Fatalf("CreateCdStage() error = %v", err)
+	}
+	configured, err := impl.IsCdStageConfigured(cdPipelineId, repository.PIPELINE_STAGE_TYPE_PRE_CD)
+	if err != nil || configured {
+		t.Errorf("IsCdStageConfigured() = %v, %v, want false for stage without steps", configured, err)
+	}
+	preStage := stageRepository.stages[0]
+	stageRepository.steps = append(stageRepository.steps, &repository.PipelineStageStep{Id: 1, PipelineStageId: preStage.Id, Name: "migrate", StepType: repository.PIPELINE_STEP_TYPE_INLINE, ScriptId: 1})
+	configured, err = impl.IsCdStageConfigured(cdPipelineId, repository.PIPELINE_STAGE_TYPE_PRE_CD)
+	if err != nil || !configured {
+		t.Errorf("IsCdStageConfigured() = %v, %v, want true for stage with steps", configured, err)
+	}
+
+	// update of a stage which does not exist creates it
+	err = impl.UpdateCdStage(&bean.PipelineStageDto{Name: "post deploy"}, repository.PIPELINE_STAGE_TYPE_POST_CD, cdPipelineId, 1)
+	if err != nil {
+		t.Fatalf("UpdateCdStage() error = %v", err)
+	}
+	if len(stageRepository.stages) != 2 || stageRepository.stages[1].Type != repository.PIPELINE_STAGE_TYPE_POST_CD || stageRepository.stages[1].CdPipelineId != cdPipelineId {
+		t.Fatalf("UpdateCdStage() stages = %v, want post cd stage created", stageRepository.stages)
+	}
+
+	preCdStage, postCdStage, err := impl.GetCdPipelineStageData(cdPipelineId)
+	if err != nil {
+		t.Fatalf("GetCdPipelineStageData() error = %v", err)
+	}
+	if preCdStage == nil || preCdStage.Name != "pre deploy" || len(preCdStage.Steps) != 1 || preCdStage.Steps[0].InlineStepDetail == nil {
+		t.Errorf("GetCdPipelineStageData() pre cd stage = %v, want stage with inline step", preCdStage)
+	}
+	if postCdStage == nil || postCdStage.Name != "post deploy" || len(postCdStage.Steps) != 0 {
+		t.Errorf("GetCdPipelineStageData() post cd stage = %v, want stage without steps", postCdStage)
+	}
+
+	// update of existing stage keeps its id and removes steps missing in request
+	err = impl.UpdateCdStage(&bean.PipelineStageDto{Name: "pre deploy checks"}, repository.PIPELINE_STAGE_TYPE_PRE_CD, cdPipelineId, 1)
+	if err != nil {
+		t.Fatalf("UpdateCdStage() error = %v", err)
+	}
+	if len(stageRepository.stages) != 2 || stageRepository.stages[0].Name != "pre deploy checks" {
+		t.Errorf("UpdateCdStage() stages = %v, want pre cd stage renamed in place", stageRepository.stages)
+	}
+	configured, err = impl.IsCdStageConfigured(cdPipelineId, repository.PIPELINE_STAGE_TYPE_PRE_CD)
+	if err != nil || configured {
+		t.Errorf("IsCdStageConfigured() = %v, %v, want false after steps are removed", configured, err)
+	}
+}
+
+func TestBuildPrePostAndRefPluginStepsDataForCdWfRequest(t *testing.T) {
+	stageRepository := &fakePipelineStageRepository{
+		stages: []*repository.PipelineStage{
+			{Id: 1, Type: repository.PIPELINE_STAGE_TYPE_PRE_CD, CdPipelineId: 7},
+			{Id: 2, Type: repository.PIPELINE_STAGE_TYPE_POST_CD, CdPipelineId: 7},
+			{Id: 3, Type: repository.PIPELINE_STAGE_TYPE_PRE_CD, CdPipelineId: 8},
+		},
+		steps: []*repository.PipelineStageStep{
+			{Id: 1, PipelineStageId: 1, Name: "migrate", Index: 1, StepType: repository.PIPELINE_STEP_TYPE_INLINE, ScriptId: 1},
+			{Id: 2, PipelineStageId: 1, Name: "scan", Index: 2, StepType: repository.PIPELINE_STEP_TYPE_REF_PLUGIN, RefPluginId: 5},
+			{Id: 3, PipelineStageId: 2, Name: "smoke test", Index: 1, StepType: repository.PIPELINE_STEP_TYPE_INLINE, ScriptId: 2},
+			{Id: 4, PipelineStageId: 3, Name: "other pipeline", Index: 1, StepType: repository.PIPELINE_STEP_TYPE_INLINE, ScriptId: 3},
+		},
+	}
+	pluginRepository := &fakeGlobalPluginRepository{
+		steps: []*repository2.PluginStep{{Id: 1, PluginId: 5, Name: "scan image", Index: 1, StepType: repository2.PLUGIN_STEP_TYPE_INLINE, ScriptId: 4}},
+	}
+	impl := NewPipelineStageService(zap.NewNop().Sugar(), stageRepository, pluginRepository)
+	preCdSteps, postCdSteps, refPlugins, err := impl.BuildPrePostAndRefPluginStepsDataForCdWfRequest(7)
+	if err != nil {
+		t.Fatalf("BuildPrePostAndRefPluginStepsDataForCdWfRequest() error = %v", err)
+	}
+	stepNames := func(steps []*bean.StepObject) []string {
+		var names []string
+		for _, step := range steps {
+			names = append(names, step.Name)
+		}
+		sort.Strings(names)
+		return names
+	}
+	if got, want := stepNames(preCdSteps), []string{"migrate", "scan"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("pre cd steps = %v, want %v", got, want)
+	}
+	if got, want := stepNames(postCdSteps), []string{"smoke test"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("post cd steps = %v, want %v", got, want)
+	}
+	if len(refPlugins) != 1 || refPlugins[0].Id != 5 || !reflect.DeepEqual(stepNames(refPlugins[0].Steps), []string{"scan image"}) {
+		t.Errorf("ref plugins = %v, want plugin 5 with its steps", refPlugins)
+	}
+	for _, step := range preCdSteps {
+		if step.Name == "migrate" && step.Script != "echo stage" {
+			t.Errorf("inline step script = %s, want script of step", step.Script)
+		}
+		if step.Name == "scan" && step.RefPluginId != 5 {
+			t.Errorf("ref plugin step id = %d, want 5", step.RefPluginId)
+		}
+	}
+}
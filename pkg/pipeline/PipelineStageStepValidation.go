@@ -0,0 +1,44 @@
+package pipeline
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"net/http"
+)
+
+const (
+	MaxStageStepTimeoutInSeconds      = 24 * 60 * 60
+	MaxStageStepRetryCount            = 10
+	MaxStageStepRetryBackoffInSeconds = 60 * 60
+)
+
+// ValidatePipelineStageSteps checks timeout, retry count and retry backoff of every step of a stage
+func ValidatePipelineStageSteps(stage *bean.PipelineStageDto) error {
+	if stage == nil {
+		return nil
+	}
+	for _, step := range stage.Steps {
+		if step.Timeout < 0 || step.Timeout > MaxStageStepTimeoutInSeconds {
+			return stageStepError(fmt.Sprintf("timeout of step %s must be between 0 and %d seconds", step.Name, MaxStageStepTimeoutInSeconds))
+		}
+		if step.RetryCount < 0 || step.RetryCount > MaxStageStepRetryCount {
+			return stageStepError(fmt.Sprintf("retry count of step %s must be between 0 and %d", step.Name, MaxStageStepRetryCount))
+		}
+		if step.RetryBackoff < 0 || step.RetryBackoff > MaxStageStepRetryBackoffInSeconds {
+			return stageStepError(fmt.Sprintf("retry backoff of step %s must be between 0 and %d seconds", step.Name, MaxStageStepRetryBackoffInSeconds))
+		}
+		if step.RetryBackoff > 0 && step.RetryCount == 0 {
+			return stageStepError(fmt.Sprintf("retry backoff of step %s needs a retry count", step.Name))
+		}
+	}
+	return nil
+}
+
+func stageStepError(message string) error {
+	return &util.ApiError{
+		HttpStatusCode:  http.StatusBadRequest,
+		InternalMessage: message,
+		UserMessage:     message,
+	}
+}
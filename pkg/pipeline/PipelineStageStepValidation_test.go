@@ -0,0 +1,30 @@
+package pipeline
+
+import (
+	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"testing"
+)
+
+func TestValidatePipelineStageSteps(t *testing.T) {
+	tests := []struct {
+		name    string
+		step    *bean.PipelineStageStepDto
+		wantErr bool
+	}{
+		{name: "no retry or timeout", step: &bean.PipelineStageStepDto{Name: "test"}, wantErr: false},
+		{name: "retry with backoff", step: &bean.PipelineStageStepDto{Name: "test", Timeout: 600, RetryCount: 3, RetryBackoff: 10, ContinueOnError: true}, wantErr: false},
+		{name: "negative timeout", step: &bean.PipelineStageStepDto{Name: "test", Timeout: -1}, wantErr: true},
+		{name: "timeout above max", step: &bean.PipelineStageStepDto{Name: "test", Timeout: MaxStageStepTimeoutInSeconds + 1}, wantErr: true},
+		{name: "retry count above max", step: &bean.PipelineStageStepDto{Name: "test", RetryCount: MaxStageStepRetryCount + 1}, wantErr: true},
+		{name: "backoff above max", step: &bean.PipelineStageStepDto{Name: "test", RetryCount: 1, RetryBackoff: MaxStageStepRetryBackoffInSeconds + 1}, wantErr: true},
+		{name: "backoff without retry", step: &bean.PipelineStageStepDto{Name: "test", RetryBackoff: 10}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stage := &bean.PipelineStageDto{Steps: []*bean.PipelineStageStepDto{tt.step}}
+			if err := ValidatePipelineStageSteps(stage); (err != nil) != tt.wantErr {
+				t.Errorf("ValidatePipelineStageSteps() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -28,6 +28,8 @@ import (
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
 	util2 "github.com/devtron-labs/devtron/internal/util"
 	"github.com/devtron-labs/devtron/pkg/app"
+	"github.com/devtron-labs/devtron/pkg/bean"
+	"github.com/devtron-labs/devtron/pkg/sbom"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/devtron-labs/devtron/util/event"
 	"go.uber.org/zap"
@@ -44,11 +46,14 @@ type CiArtifactWebhookRequest struct {
 	PipelineName string          `json:"pipelineName"`
 	WorkflowId   *int            `json:"workflowId"`
 	UserId       int32           `json:"userId"`
+	Sbom         json.RawMessage `json:"sbom,omitempty"`       //CycloneDX or SPDX json
+	SbomDigest   string          `json:"sbomDigest,omitempty"` //sha256 digest of sbom uploaded by ci runner of the workflow
 }
 
 type WebhookService interface {
 	AuthenticateExternalCiWebhook(apiKey string) (int, error)
 	SaveCiArtifactWebhook(ciPipelineId int, request *CiArtifactWebhookRequest) (id int, err error)
+	SaveCiBuildMatrixManifestArtifact(ciWorkflowId int) (id int, err error)
 }
 
 type WebhookServiceImpl struct {
@@ -61,6 +66,7 @@ type WebhookServiceImpl struct {
 	eventFactory         client.EventFactory
 	workflowDagExecutor  WorkflowDagExecutor
 	ciHandler            CiHandler
+	sbomService          sbom.SbomService
 }
 
 func NewWebhookServiceImpl(
@@ -70,7 +76,8 @@ func NewWebhookServiceImpl(
 	appService app.AppService, eventClient client.EventClient,
 	eventFactory client.EventFactory,
 	ciWorkflowRepository pipelineConfig.CiWorkflowRepository,
-	workflowDagExecutor WorkflowDagExecutor, ciHandler CiHandler) *WebhookServiceImpl {
+	workflowDagExecutor WorkflowDagExecutor, ciHandler CiHandler,
+	sbomService sbom.SbomService) *WebhookServiceImpl {
 	return &WebhookServiceImpl{
 		ciArtifactRepository: ciArtifactRepository,
 		logger:               logger,
@@ -81,6 +88,7 @@ func NewWebhookServiceImpl(
 		ciWorkflowRepository: ciWorkflowRepository,
 		workflowDagExecutor:  workflowDagExecutor,
 		ciHandler:            ciHandler,
+		sbomService:          sbomService,
 	}
 }
 
@@ -127,6 +135,18 @@ func (impl WebhookServiceImpl) SaveCiArtifactWebhook(ciPipelineId int, request *
 			impl.logger.Errorw("update wf failed for id ", "err", err)
 			return 0, err
 		}
+		if savedWorkflow.ParentCiWorkflowId > 0 {
+			parentWorkflow, err := impl.ciWorkflowRepository.FindById(savedWorkflow.ParentCiWorkflowId)
+			if err != nil {
+				impl.logger.Errorw("cannot get parent wf of matrix leg", "err", err, "parentCiWorkflowId", savedWorkflow.ParentCiWorkflowId)
+				return 0, err
+			}
+			if len(parentWorkflow.MatrixImage) > 0 {
+				//leg images are combined in a manifest list, artifact is saved once the whole matrix succeeds
+				impl.logger.Infow("skipping artifact of manifest list matrix leg", "workflowId", savedWorkflow.Id, "image", request.Image)
+				return 0, nil
+			}
+		}
 	}
 
 	pipeline, err := impl.ciPipelineRepository.FindByCiAndAppDetailsById(ciPipelineId)
@@ -169,6 +189,8 @@ func (impl WebhookServiceImpl) SaveCiArtifactWebhook(ciPipelineId int, request *
 		impl.logger.Errorw("error in saving material", "err", err)
 		return 0, err
 	}
+	//artifact is usable without sbom, so failing to save it does not fail the build
+	impl.saveSbom(artifact, request)
 
 	childrenCi, err := impl.ciPipelineRepository.FindByParentCiPipelineId(ciPipelineId)
 	if err != nil && !util2.IsErrNoRows(err) {
@@ -232,6 +254,68 @@ func (impl WebhookServiceImpl) SaveCiArtifactWebhook(ciPipelineId int, request *
 	return artifact.Id, err
 }
 
+// SaveCiBuildMatrixManifestArtifact saves the manifest list of a succeeded matrix build as the artifact of the build
+func (impl WebhookServiceImpl) SaveCiBuildMatrixManifestArtifact(ciWorkflowId int) (id int, err error) {
+	savedWorkflow, err := impl.ciWorkflowRepository.FindById(ciWorkflowId)
+	if err != nil {
+		impl.logger.Errorw("cannot get saved wf", "err", err, "ciWorkflowId", ciWorkflowId)
+		return 0, err
+	}
+	if savedWorkflow.ParentCiWorkflowId > 0 || len(savedWorkflow.MatrixImage) == 0 {
+		return 0, nil
+	}
+	existingArtifact, err := impl.ciArtifactRepository.GetByWfId(ciWorkflowId)
+	if err != nil && !util2.IsErrNoRows(err) {
+		impl.logger.Errorw("error in getting artifact of wf", "err", err, "ciWorkflowId", ciWorkflowId)
+		return 0, err
+	}
+	if existingArtifact != nil && existingArtifact.Id > 0 {
+		return 0, nil
+	}
+	var materialInfos []repository.CiMaterialInfo
+	for _, gitTrigger := range savedWorkflow.GitTriggers {
+		modification := repository.Modification{
+			Revision:     gitTrigger.Commit,
+			ModifiedTime: gitTrigger.Date.Format(bean.LayoutRFC3339),
+			Author:       gitTrigger.Author,
+			Message:      gitTrigger.Message,
+			WebhookData: repository.WebhookData{
+				Id:              gitTrigger.WebhookData.Id,
+				EventActionType: gitTrigger.WebhookData.EventActionType,
+				Data:            gitTrigger.WebhookData.Data,
+			},
+		}
+		if gitTrigger.CiConfigureSourceType == pipelineConfig.SOURCE_TYPE_BRANCH_FIXED {
+			modification.Branch = gitTrigger.CiConfigureSourceValue
+		}
+		materialInfos = append(materialInfos, repository.CiMaterialInfo{
+			Material: repository.Material{
+				GitConfiguration: repository.GitConfiguration{URL: gitTrigger.GitRepoUrl},
+				Type:             "git",
+			},
+			Changed:       true,
+			Modifications: []repository.Modification{modification},
+		})
+	}
+	materialJson, err := json.Marshal(materialInfos)
+	if err != nil {
+		impl.logger.Errorw("unable to marshal material info", "err", err)
+		return 0, err
+	}
+	userId := savedWorkflow.TriggeredBy
+	if userId == 0 {
+		userId = 1 // system triggered build
+	}
+	request := &CiArtifactWebhookRequest{
+		Image:        savedWorkflow.MatrixImage,
+		MaterialInfo: materialJson,
+		DataSource:   "CI-RUNNER",
+		WorkflowId:   &savedWorkflow.Id,
+		UserId:       userId,
+	}
+	return impl.SaveCiArtifactWebhook(savedWorkflow.CiPipelineId, request)
+}
+
 func (impl *WebhookServiceImpl) WriteCISuccessEvent(request *CiArtifactWebhookRequest, pipeline *pipelineConfig.CiPipeline, artifact *repository.CiArtifact) {
 	event := impl.eventFactory.Build(util.Success, &pipeline.Id, pipeline.AppId, nil, util.CI)
 	event.CiArtifactId = artifact.Id
@@ -269,3 +353,27 @@ func (impl *WebhookServiceImpl) BuildPayload(request *CiArtifactWebhookRequest,
 	payload.DockerImageUrl = request.Image
 	return payload
 }
+
+// saveSbom saves sbom sent with the request, or the one uploaded by ci runner of the workflow to blob storage
+func (impl WebhookServiceImpl) saveSbom(artifact *repository.CiArtifact, request *CiArtifactWebhookRequest) {
+	content := request.Sbom
+	if len(content) == 0 && len(request.SbomDigest) > 0 && request.WorkflowId != nil {
+		var err error
+		content, err = impl.ciHandler.FetchCiWorkflowSbom(*request.WorkflowId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching sbom of ci workflow", "err", err, "ciWorkflowId", *request.WorkflowId)
+			return
+		}
+		if err = sbom.VerifySbomDigest(content, request.SbomDigest); err != nil {
+			impl.logger.Errorw("sbom of ci workflow does not match its digest", "err", err, "ciWorkflowId", *request.WorkflowId)
+			return
+		}
+	}
+	if len(content) == 0 {
+		return
+	}
+	err := impl.sbomService.SaveSbom(artifact, content, request.UserId)
+	if err != nil {
+		impl.logger.Errorw("error in saving sbom of artifact", "err", err, "ciArtifactId", artifact.Id)
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/argoproj/gitops-engine/pkg/health"
 	blob_storage "github.com/devtron-labs/common-lib/blob-storage"
 	"github.com/devtron-labs/devtron/util/argo"
+	"net/http"
 	"strconv"
 	"strings"
 	"time"
@@ -32,6 +33,7 @@ import (
 	repository2 "github.com/devtron-labs/devtron/pkg/cluster/repository"
 	history2 "github.com/devtron-labs/devtron/pkg/pipeline/history"
 	repository3 "github.com/devtron-labs/devtron/pkg/pipeline/history/repository"
+	repository4 "github.com/devtron-labs/devtron/pkg/pipeline/repository"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/devtron-labs/devtron/pkg/user/casbin"
 	util3 "github.com/devtron-labs/devtron/pkg/util"
@@ -47,6 +49,10 @@ import (
 	"github.com/devtron-labs/devtron/internal/util"
 	"github.com/devtron-labs/devtron/pkg/app"
 	bean2 "github.com/devtron-labs/devtron/pkg/bean"
+	"github.com/devtron-labs/devtron/pkg/deploymentApproval"
+	"github.com/devtron-labs/devtron/pkg/deploymentWindow"
+	"github.com/devtron-labs/devtron/pkg/imageSignature"
+	"github.com/devtron-labs/devtron/pkg/sbom"
 	"github.com/devtron-labs/devtron/pkg/user"
 	util4 "github.com/devtron-labs/devtron/util"
 	util2 "github.com/devtron-labs/devtron/util/event"
@@ -68,6 +74,8 @@ type WorkflowDagExecutor interface {
 	TriggerBulkDeploymentAsync(requests []*BulkTriggerRequest, UserId int32) (interface{}, error)
 	StopStartApp(stopRequest *StopAppRequest, ctx context.Context) (int, error)
 	TriggerBulkHibernateAsync(request StopDeploymentGroupRequest, ctx context.Context) (interface{}, error)
+	TriggerQueuedDeployments() error
+	TriggerDeploymentOnApproval(approvalRequest *deploymentApproval.ApprovalRequestDto, triggeredBy int32) error
 }
 
 type WorkflowDagExecutorImpl struct {
@@ -97,8 +105,17 @@ type WorkflowDagExecutorImpl struct {
 	prePostCdScriptHistoryService history2.PrePostCdScriptHistoryService
 	argoUserService               argo.ArgoUserService
 	cdPipelineStatusTimelineRepo  pipelineConfig.PipelineStatusTimelineRepository
+	pipelineStageService          PipelineStageService
+	deploymentWindowService       deploymentWindow.DeploymentWindowService
+	deploymentApprovalService     deploymentApproval.DeploymentApprovalService
+	imageSignatureService         imageSignature.ImageSignatureService
+	licensePolicyRepository       security.LicensePolicyRepository
+	sbomRepository                sbom.SbomRepository
 }
 
+// cdWorkflowRunnerMessageMaxLength is the length of cd_workflow_runner.message column, details longer than this go to timeline
+const cdWorkflowRunnerMessageMaxLength = 256
+
 type CiArtifactDTO struct {
 	Id                   int    `json:"id"`
 	PipelineId           int    `json:"pipelineId"` //id of the ci pipeline from which this webhook was triggered
@@ -142,7 +159,12 @@ func NewWorkflowDagExecutorImpl(Logger *zap.SugaredLogger, pipelineRepository pi
 	appWorkflowRepository appWorkflow.AppWorkflowRepository,
 	prePostCdScriptHistoryService history2.PrePostCdScriptHistoryService,
 	argoUserService argo.ArgoUserService,
-	cdPipelineStatusTimelineRepo pipelineConfig.PipelineStatusTimelineRepository) *WorkflowDagExecutorImpl {
+	cdPipelineStatusTimelineRepo pipelineConfig.PipelineStatusTimelineRepository,
+	pipelineStageService PipelineStageService,
+	deploymentWindowService deploymentWindow.DeploymentWindowService,
+	deploymentApprovalService deploymentApproval.DeploymentApprovalService,
+	imageSignatureService imageSignature.ImageSignatureService,
+	licensePolicyRepository security.LicensePolicyRepository, sbomRepository sbom.SbomRepository) *WorkflowDagExecutorImpl {
 	wde := &WorkflowDagExecutorImpl{logger: Logger,
 		pipelineRepository:            pipelineRepository,
 		cdWorkflowRepository:          cdWorkflowRepository,
@@ -169,6 +191,12 @@ func NewWorkflowDagExecutorImpl(Logger *zap.SugaredLogger, pipelineRepository pi
 		prePostCdScriptHistoryService: prePostCdScriptHistoryService,
 		argoUserService:               argoUserService,
 		cdPipelineStatusTimelineRepo:  cdPipelineStatusTimelineRepo,
+		pipelineStageService:          pipelineStageService,
+		deploymentWindowService:       deploymentWindowService,
+		deploymentApprovalService:     deploymentApprovalService,
+		imageSignatureService:         imageSignatureService,
+		licensePolicyRepository:       licensePolicyRepository,
+		sbomRepository:                sbomRepository,
 	}
 	err := util4.AddStream(wde.pubsubClient.JetStrCtxt, util4.ORCHESTRATOR_STREAM, util4.CI_RUNNER_STREAM)
 	if err != nil {
@@ -246,9 +274,29 @@ func (impl *WorkflowDagExecutorImpl) HandleCiSuccessEvent(artifact *repository.C
 	return nil
 }
 
-func (impl *WorkflowDagExecutorImpl) triggerStage(cdWf *pipelineConfig.CdWorkflow, pipeline *pipelineConfig.Pipeline, artifact *repository.CiArtifact, applyAuth bool, async bool, triggeredBy int32) error {
-	var err error
+// isPreStageConfigured checks for both yaml based and plugin based pre deploy stage
+func (impl *WorkflowDagExecutorImpl) isPreStageConfigured(pipeline *pipelineConfig.Pipeline) (bool, error) {
 	if len(pipeline.PreStageConfig) > 0 {
+		return true, nil
+	}
+	return impl.pipelineStageService.IsCdStageConfigured(pipeline.Id, repository4.PIPELINE_STAGE_TYPE_PRE_CD)
+}
+
+// isPostStageConfigured checks for both yaml based and plugin based post deploy stage
+func (impl *WorkflowDagExecutorImpl) isPostStageConfigured(pipeline *pipelineConfig.Pipeline) (bool, error) {
+	if len(pipeline.PostStageConfig) > 0 {
+		return true, nil
+	}
+	return impl.pipelineStageService.IsCdStageConfigured(pipeline.Id, repository4.PIPELINE_STAGE_TYPE_POST_CD)
+}
+
+func (impl *WorkflowDagExecutorImpl) triggerStage(cdWf *pipelineConfig.CdWorkflow, pipeline *pipelineConfig.Pipeline, artifact *repository.CiArtifact, applyAuth bool, async bool, triggeredBy int32) error {
+	preStageExists, err := impl.isPreStageConfigured(pipeline)
+	if err != nil {
+		impl.logger.Errorw("error in checking pre stage for pipeline", "err", err, "pipelineId", pipeline.Id)
+		return err
+	}
+	if preStageExists {
 		// pre stage exists
 		if pipeline.PreTriggerType == pipelineConfig.TRIGGER_TYPE_AUTOMATIC {
 			impl.logger.Debugw("trigger pre stage for pipeline", "artifactId", artifact.Id, "pipelineId", pipeline.Id)
@@ -265,16 +313,32 @@ func (impl *WorkflowDagExecutorImpl) triggerStage(cdWf *pipelineConfig.CdWorkflo
 }
 
 func (impl *WorkflowDagExecutorImpl) triggerStageForBulk(cdWf *pipelineConfig.CdWorkflow, pipeline *pipelineConfig.Pipeline, artifact *repository.CiArtifact, applyAuth bool, async bool, triggeredBy int32) error {
-	var err error
-	if len(pipeline.PreStageConfig) > 0 {
+	preStageExists, err := impl.isPreStageConfigured(pipeline)
+	if err != nil {
+		impl.logger.Errorw("error in checking pre stage for pipeline", "err", err, "pipelineId", pipeline.Id)
+		return err
+	}
+	if preStageExists {
 		//pre stage exists
 		impl.logger.Debugw("trigger pre stage for pipeline", "artifactId", artifact.Id, "pipelineId", pipeline.Id)
 		err = impl.TriggerPreStage(cdWf, artifact, pipeline, artifact.UpdatedBy, applyAuth) //TODO handle error here
 		return err
 	} else {
+		// bulk deployments are user triggered, super admin can deploy outside deployment window
+		isSuperAdmin, err := impl.user.IsSuperAdmin(int(triggeredBy))
+		if err != nil {
+			impl.logger.Errorw("error in checking super admin", "err", err, "userId", triggeredBy)
+			return err
+		}
+		// bulk deployments are rejected like manual triggers for unapproved artifacts, approval may be revoked after request is accepted
+		err = impl.checkDeploymentApprovalForManualTrigger(artifact.Id, pipeline)
+		if err != nil {
+			impl.logger.Errorw("deployment approval check failed for bulk trigger", "err", err, "pipelineId", pipeline.Id, "ciArtifactId", artifact.Id)
+			return err
+		}
 		// trigger deployment
 		impl.logger.Debugw("trigger cd for pipeline", "artifactId", artifact.Id, "pipelineId", pipeline.Id)
-		err = impl.TriggerDeployment(cdWf, artifact, pipeline, applyAuth, async, triggeredBy)
+		err = impl.triggerDeployment(cdWf, artifact, pipeline, applyAuth, async, triggeredBy, isSuperAdmin)
 		return err
 	}
 }
@@ -718,7 +782,12 @@ func (impl *WorkflowDagExecutorImpl) HandleDeploymentSuccessEvent(gitHash string
 		impl.logger.Errorw("error in fetching cd workflow by id", "pipelineOverride", pipelineOverride)
 		return err
 	}
-	if len(pipelineOverride.Pipeline.PostStageConfig) > 0 {
+	postStageExists, err := impl.isPostStageConfigured(pipelineOverride.Pipeline)
+	if err != nil {
+		impl.logger.Errorw("error in checking post stage for pipeline", "err", err, "pipelineId", pipelineOverride.PipelineId)
+		return err
+	}
+	if postStageExists {
 		if pipelineOverride.Pipeline.PostTriggerType == pipelineConfig.TRIGGER_TYPE_AUTOMATIC &&
 			pipelineOverride.DeploymentType != models.DEPLOYMENTTYPE_STOP &&
 			pipelineOverride.DeploymentType != models.DEPLOYMENTTYPE_START {
@@ -778,6 +847,15 @@ func (impl *WorkflowDagExecutorImpl) HandlePostStageSuccessEvent(cdWorkflowId in
 
 // Only used for auto trigger
 func (impl *WorkflowDagExecutorImpl) TriggerDeployment(cdWf *pipelineConfig.CdWorkflow, artifact *repository.CiArtifact, pipeline *pipelineConfig.Pipeline, applyAuth bool, async bool, triggeredBy int32) error {
+	//artifacts not approved yet are held back and approval is requested, deployment is triggered once approved
+	approved, err := impl.checkDeploymentApprovalForAutoTrigger(cdWf, artifact, pipeline, triggeredBy)
+	if err != nil || !approved {
+		return err
+	}
+	return impl.triggerDeployment(cdWf, artifact, pipeline, applyAuth, async, triggeredBy, false)
+}
+
+func (impl *WorkflowDagExecutorImpl) triggerDeployment(cdWf *pipelineConfig.CdWorkflow, artifact *repository.CiArtifact, pipeline *pipelineConfig.Pipeline, applyAuth bool, async bool, triggeredBy int32, bypassDeploymentWindow bool) error {
 	//in case of manual ci RBAC need to apply, this method used for auto cd deployment
 	if applyAuth {
 		user, err := impl.user.GetById(triggeredBy)
@@ -818,7 +896,7 @@ func (impl *WorkflowDagExecutorImpl) TriggerDeployment(cdWf *pipelineConfig.CdWo
 		Namespace:    impl.cdConfig.DefaultNamespace,
 		CdWorkflowId: cdWf.Id,
 	}
-	savedWfr, err := impl.cdWorkflowRepository.SaveWorkFlowRunner(runner)
+	_, err := impl.cdWorkflowRepository.SaveWorkFlowRunner(runner)
 	if err != nil {
 		return err
 	}
@@ -840,8 +918,27 @@ func (impl *WorkflowDagExecutorImpl) TriggerDeployment(cdWf *pipelineConfig.CdWo
 	if err != nil {
 		impl.logger.Errorw("error in creating timeline status for deployment initiation", "err", err, "timeline", timeline)
 	}
+	//checking deployment window of environment, automatic deployments outside window are queued
+	windowEvaluation, err := impl.deploymentWindowService.EvaluateDeploymentWindow(pipeline.EnvironmentId, triggeredAt)
+	if err != nil {
+		impl.logger.Errorw("error in evaluating deployment window", "err", err, "envId", pipeline.EnvironmentId)
+		return err
+	}
+	if !windowEvaluation.Allowed {
+		if !bypassDeploymentWindow {
+			return impl.queueDeployment(cdWf, runner, artifact, pipeline, triggeredBy, windowEvaluation.Reason)
+		}
+		impl.saveDeploymentWindowTimeline(runner.Id, pipelineConfig.TIMELINE_STATUS_WINDOW_OVERRIDDEN, fmt.Sprintf("Deployment window overridden by super admin, %s.", windowEvaluation.Reason))
+	}
+	return impl.deployArtifact(cdWf, runner, artifact, pipeline, async, triggeredAt)
+}
+
+// deployArtifact checks image vulnerability and triggers deployment for an already created deploy runner
+func (impl *WorkflowDagExecutorImpl) deployArtifact(cdWf *pipelineConfig.CdWorkflow, runner *pipelineConfig.CdWorkflowRunner, artifact *repository.CiArtifact, pipeline *pipelineConfig.Pipeline, async bool, triggeredAt time.Time) error {
+	var err error
 	//checking vulnerability for deploying image
 	isVulnerable := false
+	var blockedCves []*security.BlockedCve
 	if len(artifact.ImageDigest) > 0 {
 		var cveStores []*security.CveStore
 		imageScanResult, err := impl.scanResultRepository.FindByImageDigest(artifact.ImageDigest)
@@ -857,18 +954,18 @@ func (impl *WorkflowDagExecutorImpl) TriggerDeployment(cdWf *pipelineConfig.CdWo
 			impl.logger.Errorw("error while fetching env", "err", err)
 			return err
 		}
-		blockCveList, err := impl.cvePolicyRepository.GetBlockedCVEList(cveStores, env.ClusterId, pipeline.EnvironmentId, pipeline.AppId, false)
+		blockedCves, err = impl.cvePolicyRepository.GetBlockedCves(cveStores, env.ClusterId, pipeline.EnvironmentId, pipeline.AppId, false)
 		if err != nil {
 			impl.logger.Errorw("error while fetching blocked cve list", "err", err)
 			return err
 		}
-		if len(blockCveList) > 0 {
+		if len(blockedCves) > 0 {
 			isVulnerable = true
 		}
 	}
 	if isVulnerable == true {
 		runner.Status = WorkflowFailed
-		runner.Message = "Found vulnerability on image"
+		runner.Message = BuildVulnerabilityMessage(blockedCves)
 		runner.FinishedOn = time.Now()
 		err = impl.cdWorkflowRepository.UpdateWorkFlowRunner(runner)
 		if err != nil {
@@ -883,7 +980,7 @@ func (impl *WorkflowDagExecutorImpl) TriggerDeployment(cdWf *pipelineConfig.CdWo
 		timeline := &pipelineConfig.PipelineStatusTimeline{
 			CdWorkflowRunnerId: runner.Id,
 			Status:             pipelineConfig.TIMELINE_STATUS_DEPLOYMENT_FAILED,
-			StatusDetail:       "Deployment failed: Vulnerability policy violated.",
+			StatusDetail:       fmt.Sprintf("Deployment failed: Vulnerability policy violated. %s", BuildVulnerabilityDetail(blockedCves)),
 			StatusTime:         time.Now(),
 			AuditLog: sql.AuditLog{
 				CreatedBy: 1,
@@ -896,10 +993,19 @@ func (impl *WorkflowDagExecutorImpl) TriggerDeployment(cdWf *pipelineConfig.CdWo
 		if err != nil {
 			impl.logger.Errorw("error in creating timeline status for deployment fail - cve policy violation", "err", err, "timeline", timeline)
 		}
+		impl.writeImageScanBlockedEvent(runner, artifact, pipeline, blockedCves)
 		return nil
 	}
+	compliant, err := impl.checkLicensePolicy(runner, artifact, pipeline)
+	if err != nil || !compliant {
+		return err
+	}
+	verified, err := impl.checkImageSignature(runner, artifact, pipeline)
+	if err != nil || !verified {
+		return err
+	}
 
-	err = impl.appService.TriggerCD(artifact, cdWf.Id, savedWfr.Id, pipeline, async, triggeredAt)
+	err = impl.appService.TriggerCD(artifact, cdWf.Id, runner.Id, pipeline, async, triggeredAt)
 	err1 := impl.updatePreviousDeploymentStatus(runner, pipeline.Id, err, triggeredAt)
 	if err1 != nil || err != nil {
 		impl.logger.Errorw("error while update previous cd workflow runners", "err", err, "runner", runner, "pipelineId", pipeline.Id)
@@ -908,6 +1014,464 @@ func (impl *WorkflowDagExecutorImpl) TriggerDeployment(cdWf *pipelineConfig.CdWo
 	return nil
 }
 
+// queueDeployment parks an automatic deployment triggered outside deployment window, it is triggered by
+// TriggerQueuedDeployments once the window opens
+func (impl *WorkflowDagExecutorImpl) queueDeployment(cdWf *pipelineConfig.CdWorkflow, runner *pipelineConfig.CdWorkflowRunner, artifact *repository.CiArtifact, pipeline *pipelineConfig.Pipeline, triggeredBy int32, reason string) error {
+	runner.Status = WorkflowQueued
+	runner.Message = fmt.Sprintf("Deployment queued, %s", reason)
+	err := impl.cdWorkflowRepository.UpdateWorkFlowRunner(runner)
+	if err != nil {
+		impl.logger.Errorw("error in updating queued runner status", "err", err, "runner", runner)
+		return err
+	}
+	queue := &deploymentWindow.DeploymentQueue{
+		PipelineId:         pipeline.Id,
+		EnvironmentId:      pipeline.EnvironmentId,
+		CiArtifactId:       artifact.Id,
+		CdWorkflowId:       cdWf.Id,
+		CdWorkflowRunnerId: runner.Id,
+		TriggeredBy:        triggeredBy,
+		QueuedOn:           time.Now(),
+		AuditLog:           sql.AuditLog{CreatedOn: time.Now(), CreatedBy: 1, UpdatedOn: time.Now(), UpdatedBy: 1},
+	}
+	err = impl.deploymentWindowService.QueueDeployment(queue)
+	if err != nil {
+		impl.logger.Errorw("error in queueing deployment", "err", err, "pipelineId", pipeline.Id)
+		return err
+	}
+	impl.saveDeploymentWindowTimeline(runner.Id, pipelineConfig.TIMELINE_STATUS_DEPLOYMENT_QUEUED, fmt.Sprintf("Deployment queued, %s. It will be triggered once the deployment window opens.", reason))
+	return nil
+}
+
+// TriggerQueuedDeployments triggers deployments queued outside deployment window if the window of their environment is open now
+func (impl *WorkflowDagExecutorImpl) TriggerQueuedDeployments() error {
+	queuedDeployments, err := impl.deploymentWindowService.GetAllQueuedDeployments()
+	if err != nil {
+		impl.logger.Errorw("error in getting queued deployments", "err", err)
+		return err
+	}
+	for _, queuedDeployment := range queuedDeployments {
+		windowEvaluation, err := impl.deploymentWindowService.EvaluateDeploymentWindow(queuedDeployment.EnvironmentId, time.Now())
+		if err != nil {
+			impl.logger.Errorw("error in evaluating deployment window", "err", err, "envId", queuedDeployment.EnvironmentId)
+			continue
+		}
+		if !windowEvaluation.Allowed {
+			continue
+		}
+		queuedDeployment.Status, err = impl.triggerQueuedDeployment(queuedDeployment)
+		if err != nil {
+			impl.logger.Errorw("error in triggering queued deployment", "err", err, "queuedDeployment", queuedDeployment)
+		}
+		queuedDeployment.ProcessedOn = time.Now()
+		queuedDeployment.UpdatedOn = time.Now()
+		queuedDeployment.UpdatedBy = 1
+		err = impl.deploymentWindowService.UpdateQueuedDeployment(queuedDeployment)
+		if err != nil {
+			impl.logger.Errorw("error in updating queued deployment", "err", err, "queuedDeployment", queuedDeployment)
+		}
+	}
+	return nil
+}
+
+func (impl *WorkflowDagExecutorImpl) triggerQueuedDeployment(queuedDeployment *deploymentWindow.DeploymentQueue) (deploymentWindow.DeploymentQueueStatus, error) {
+	runner, err := impl.cdWorkflowRepository.FindWorkflowRunnerById(queuedDeployment.CdWorkflowRunnerId)
+	if err != nil {
+		return deploymentWindow.DEPLOYMENT_QUEUE_STATUS_FAILED, err
+	}
+	if runner.Status != WorkflowQueued {
+		//a newer deployment has already taken over this runner
+		impl.logger.Infow("skipping queued deployment as runner is not queued anymore", "runnerId", runner.Id, "status", runner.Status)
+		return deploymentWindow.DEPLOYMENT_QUEUE_STATUS_SUPERSEDED, nil
+	}
+	pipeline, err := impl.pipelineRepository.FindById(queuedDeployment.PipelineId)
+	if err != nil {
+		return deploymentWindow.DEPLOYMENT_QUEUE_STATUS_FAILED, err
+	}
+	artifact, err := impl.ciArtifactRepository.Get(queuedDeployment.CiArtifactId)
+	if err != nil {
+		return deploymentWindow.DEPLOYMENT_QUEUE_STATUS_FAILED, err
+	}
+	cdWf, err := impl.cdWorkflowRepository.FindById(queuedDeployment.CdWorkflowId)
+	if err != nil {
+		return deploymentWindow.DEPLOYMENT_QUEUE_STATUS_FAILED, err
+	}
+	triggeredAt := time.Now()
+	runner.Status = WorkflowInProgress
+	runner.Message = ""
+	runner.StartedOn = triggeredAt
+	err = impl.cdWorkflowRepository.UpdateWorkFlowRunner(runner)
+	if err != nil {
+		return deploymentWindow.DEPLOYMENT_QUEUE_STATUS_FAILED, err
+	}
+	impl.saveDeploymentWindowTimeline(runner.Id, pipelineConfig.TIMELINE_STATUS_DEPLOYMENT_INITIATED, "Deployment window opened, queued deployment initiated.")
+	err = impl.deployArtifact(cdWf, runner, artifact, pipeline, false, triggeredAt)
+	if err != nil {
+		return deploymentWindow.DEPLOYMENT_QUEUE_STATUS_FAILED, err
+	}
+	return deploymentWindow.DEPLOYMENT_QUEUE_STATUS_TRIGGERED, nil
+}
+
+// checkDeploymentWindowForManualTrigger rejects a manual deployment outside deployment window unless user is super admin,
+// the decision is recorded in timeline of the runner
+func (impl *WorkflowDagExecutorImpl) checkDeploymentWindowForManualTrigger(runner *pipelineConfig.CdWorkflowRunner, pipeline *pipelineConfig.Pipeline, userId int32, triggeredAt time.Time) error {
+	windowEvaluation, err := impl.deploymentWindowService.EvaluateDeploymentWindow(pipeline.EnvironmentId, triggeredAt)
+	if err != nil {
+		impl.logger.Errorw("error in evaluating deployment window", "err", err, "envId", pipeline.EnvironmentId)
+		return err
+	}
+	if windowEvaluation.Allowed {
+		return nil
+	}
+	isSuperAdmin, err := impl.user.IsSuperAdmin(int(userId))
+	if err != nil {
+		impl.logger.Errorw("error in checking super admin", "err", err, "userId", userId)
+		return err
+	}
+	if isSuperAdmin {
+		impl.saveDeploymentWindowTimeline(runner.Id, pipelineConfig.TIMELINE_STATUS_WINDOW_OVERRIDDEN, fmt.Sprintf("Deployment window overridden by super admin, %s.", windowEvaluation.Reason))
+		return nil
+	}
+	runner.Status = WorkflowFailed
+	runner.Message = fmt.Sprintf("Deployment blocked, %s", windowEvaluation.Reason)
+	runner.FinishedOn = time.Now()
+	err = impl.cdWorkflowRepository.UpdateWorkFlowRunner(runner)
+	if err != nil {
+		impl.logger.Errorw("error in updating status", "err", err)
+		return err
+	}
+	impl.saveDeploymentWindowTimeline(runner.Id, pipelineConfig.TIMELINE_STATUS_DEPLOYMENT_BLOCKED, fmt.Sprintf("Deployment blocked, %s.", windowEvaluation.Reason))
+	return &util.ApiError{
+		HttpStatusCode:  http.StatusForbidden,
+		InternalMessage: fmt.Sprintf("deployment blocked for pipeline %d, %s", pipeline.Id, windowEvaluation.Reason),
+		UserMessage:     fmt.Sprintf("Deployment not allowed, %s", windowEvaluation.Reason),
+	}
+}
+
+// checkDeploymentWindowForBulkTrigger rejects the bulk request if any pipeline is outside its deployment window, unless user is super admin
+func (impl *WorkflowDagExecutorImpl) checkDeploymentWindowForBulkTrigger(requests []*BulkTriggerRequest, userId int32) error {
+	var blockedPipelines []string
+	for _, request := range requests {
+		pipeline, err := impl.pipelineRepository.FindById(request.PipelineId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching pipeline", "err", err, "pipelineId", request.PipelineId)
+			return err
+		}
+		windowEvaluation, err := impl.deploymentWindowService.EvaluateDeploymentWindow(pipeline.EnvironmentId, time.Now())
+		if err != nil {
+			impl.logger.Errorw("error in evaluating deployment window", "err", err, "envId", pipeline.EnvironmentId)
+			return err
+		}
+		if !windowEvaluation.Allowed {
+			blockedPipelines = append(blockedPipelines, fmt.Sprintf("%s (%s)", pipeline.Name, windowEvaluation.Reason))
+		}
+	}
+	if len(blockedPipelines) == 0 {
+		return nil
+	}
+	isSuperAdmin, err := impl.user.IsSuperAdmin(int(userId))
+	if err != nil {
+		impl.logger.Errorw("error in checking super admin", "err", err, "userId", userId)
+		return err
+	}
+	if isSuperAdmin {
+		return nil
+	}
+	return &util.ApiError{
+		HttpStatusCode:  http.StatusForbidden,
+		InternalMessage: fmt.Sprintf("deployment blocked for pipelines %s", strings.Join(blockedPipelines, ", ")),
+		UserMessage:     fmt.Sprintf("Deployment not allowed for %s", strings.Join(blockedPipelines, ", ")),
+	}
+}
+
+// checkDeploymentApprovalForBulkTrigger rejects the bulk request if any artifact has not collected required approvals
+// for its pipeline, approval is not requested on behalf of bulk triggers
+func (impl *WorkflowDagExecutorImpl) checkDeploymentApprovalForBulkTrigger(requests []*BulkTriggerRequest) error {
+	var unapprovedPipelines []string
+	for _, request := range requests {
+		pipeline, err := impl.pipelineRepository.FindById(request.PipelineId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching pipeline", "err", err, "pipelineId", request.PipelineId)
+			return err
+		}
+		approvalEvaluation, err := impl.deploymentApprovalService.EvaluateApproval(pipeline.Id, request.CiArtifactId)
+		if err != nil {
+			impl.logger.Errorw("error in evaluating deployment approval", "err", err, "pipelineId", pipeline.Id, "ciArtifactId", request.CiArtifactId)
+			return err
+		}
+		if !approvalEvaluation.Approved {
+			unapprovedPipelines = append(unapprovedPipelines, fmt.Sprintf("%s (%d of %d approvals)", pipeline.Name, approvalEvaluation.ApprovalCount, approvalEvaluation.RequiredApprovals))
+		}
+	}
+	if len(unapprovedPipelines) == 0 {
+		return nil
+	}
+	return &util.ApiError{
+		HttpStatusCode:  http.StatusForbidden,
+		InternalMessage: fmt.Sprintf("artifacts not approved for pipelines %s", strings.Join(unapprovedPipelines, ", ")),
+		UserMessage:     fmt.Sprintf("Artifact is not approved for deployment on %s", strings.Join(unapprovedPipelines, ", ")),
+	}
+}
+
+// checkDeploymentApprovalForManualTrigger rejects manual deployment of an artifact which has not collected required approvals
+func (impl *WorkflowDagExecutorImpl) checkDeploymentApprovalForManualTrigger(ciArtifactId int, pipeline *pipelineConfig.Pipeline) error {
+	approvalEvaluation, err := impl.deploymentApprovalService.EvaluateApproval(pipeline.Id, ciArtifactId)
+	if err != nil {
+		impl.logger.Errorw("error in evaluating deployment approval", "err", err, "pipelineId", pipeline.Id, "ciArtifactId", ciArtifactId)
+		return err
+	}
+	if approvalEvaluation.Approved {
+		return nil
+	}
+	userMessage := fmt.Sprintf("Artifact is not approved for deployment, %d of %d approvals received", approvalEvaluation.ApprovalCount, approvalEvaluation.RequiredApprovals)
+	if approvalEvaluation.Status == deploymentApproval.APPROVAL_STATUS_REJECTED {
+		userMessage = "Artifact is rejected for deployment"
+	}
+	return &util.ApiError{
+		HttpStatusCode:  http.StatusForbidden,
+		InternalMessage: fmt.Sprintf("artifact %d is not approved for pipeline %d, status %s", ciArtifactId, pipeline.Id, approvalEvaluation.Status),
+		UserMessage:     userMessage,
+	}
+}
+
+// checkDeploymentApprovalForAutoTrigger returns false if artifact is not approved for the pipeline, approval is
+// requested on behalf of the trigger unless the artifact was rejected. Workflow of the trigger is held with the request
+// so that deployment continues after its pre stage once approved
+func (impl *WorkflowDagExecutorImpl) checkDeploymentApprovalForAutoTrigger(cdWf *pipelineConfig.CdWorkflow, artifact *repository.CiArtifact, pipeline *pipelineConfig.Pipeline, triggeredBy int32) (bool, error) {
+	approvalEvaluation, err := impl.deploymentApprovalService.EvaluateApproval(pipeline.Id, artifact.Id)
+	if err != nil {
+		impl.logger.Errorw("error in evaluating deployment approval", "err", err, "pipelineId", pipeline.Id, "ciArtifactId", artifact.Id)
+		return false, err
+	}
+	if approvalEvaluation.Approved {
+		return true, nil
+	}
+	if approvalEvaluation.Status == deploymentApproval.APPROVAL_STATUS_REJECTED {
+		impl.logger.Infow("skipping deployment of rejected artifact", "pipelineId", pipeline.Id, "ciArtifactId", artifact.Id)
+		return false, nil
+	}
+	var cdWorkflowId int
+	if cdWf != nil {
+		cdWorkflowId = cdWf.Id
+	}
+	_, err = impl.deploymentApprovalService.RequestApproval(pipeline.Id, artifact.Id, triggeredBy, true, cdWorkflowId)
+	if err != nil {
+		impl.logger.Errorw("error in requesting deployment approval", "err", err, "pipelineId", pipeline.Id, "ciArtifactId", artifact.Id)
+		return false, err
+	}
+	impl.logger.Infow("deployment waiting for approval", "pipelineId", pipeline.Id, "ciArtifactId", artifact.Id)
+	return false, nil
+}
+
+// TriggerDeploymentOnApproval triggers deployment of an approved artifact on automatic pipelines, where it was held back
+// for approval. Deployment continues in the held workflow, request is superseded when pipeline has deployed a newer
+// artifact meanwhile
+func (impl *WorkflowDagExecutorImpl) TriggerDeploymentOnApproval(approvalRequest *deploymentApproval.ApprovalRequestDto, triggeredBy int32) error {
+	pipeline, err := impl.pipelineRepository.FindById(approvalRequest.PipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching pipeline", "err", err, "pipelineId", approvalRequest.PipelineId)
+		return err
+	}
+	if pipeline.TriggerType != pipelineConfig.TRIGGER_TYPE_AUTOMATIC {
+		return nil
+	}
+	artifact, err := impl.ciArtifactRepository.Get(approvalRequest.CiArtifactId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching artifact", "err", err, "ciArtifactId", approvalRequest.CiArtifactId)
+		return err
+	}
+	lastDeployRunner, err := impl.cdWorkflowRepository.FindLastStatusByPipelineIdAndRunnerType(pipeline.Id, bean.CD_WORKFLOW_TYPE_DEPLOY)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching last deployment of pipeline", "err", err, "pipelineId", pipeline.Id)
+		return err
+	}
+	if err == nil && IsApprovedArtifactSuperseded(artifact, lastDeployRunner.CdWorkflow) {
+		impl.logger.Infow("skipping deployment of approved artifact, pipeline has deployed a newer artifact", "pipelineId", pipeline.Id, "ciArtifactId", artifact.Id, "deployedCiArtifactId", lastDeployRunner.CdWorkflow.CiArtifactId)
+		return impl.deploymentApprovalService.MarkRequestSuperseded(approvalRequest.Id, triggeredBy)
+	}
+	var cdWf *pipelineConfig.CdWorkflow
+	if approvalRequest.CdWorkflowId > 0 {
+		cdWf, err = impl.cdWorkflowRepository.FindById(approvalRequest.CdWorkflowId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching held workflow of approval request", "err", err, "cdWorkflowId", approvalRequest.CdWorkflowId)
+			return err
+		}
+		if cdWf.PipelineId != pipeline.Id || cdWf.CiArtifactId != artifact.Id {
+			return fmt.Errorf("held workflow %d does not belong to pipeline %d and artifact %d", cdWf.Id, pipeline.Id, artifact.Id)
+		}
+	}
+	return impl.TriggerDeployment(cdWf, artifact, pipeline, false, false, triggeredBy)
+}
+
+// IsApprovedArtifactSuperseded returns true when last deployed workflow of the pipeline runs an artifact built after the
+// approved artifact, deploying the approved artifact then would roll back the environment
+func IsApprovedArtifactSuperseded(approvedArtifact *repository.CiArtifact, lastDeployedWf *pipelineConfig.CdWorkflow) bool {
+	if lastDeployedWf == nil {
+		return false
+	}
+	//artifact ids grow with build order
+	return lastDeployedWf.CiArtifactId > approvedArtifact.Id
+}
+
+// checkImageSignature verifies the artifact against signature policies of the environment, an unverified image fails
+// the deploy runner and a failure event is sent
+func (impl *WorkflowDagExecutorImpl) checkImageSignature(runner *pipelineConfig.CdWorkflowRunner, artifact *repository.CiArtifact, pipeline *pipelineConfig.Pipeline) (bool, error) {
+	env, err := impl.envRepository.FindById(pipeline.EnvironmentId)
+	if err != nil {
+		impl.logger.Errorw("error while fetching env", "err", err, "envId", pipeline.EnvironmentId)
+		return false, err
+	}
+	evaluation, err := impl.imageSignatureService.VerifyImageSignature(artifact.Image, artifact.ImageDigest, pipeline.AppId, env.ClusterId, pipeline.EnvironmentId)
+	if err != nil {
+		impl.logger.Errorw("error in verifying image signature", "err", err, "image", artifact.Image, "pipelineId", pipeline.Id)
+		return false, err
+	}
+	if evaluation.Verified {
+		return true, nil
+	}
+	runner.Status = WorkflowFailed
+	runner.Message = TruncateRunnerMessage(fmt.Sprintf("Image signature verification failed, %s", evaluation.Reason))
+	runner.FinishedOn = time.Now()
+	err = impl.cdWorkflowRepository.UpdateWorkFlowRunner(runner)
+	if err != nil {
+		impl.logger.Errorw("error in updating status", "err", err, "runner", runner.Id)
+		return false, err
+	}
+	timeline := &pipelineConfig.PipelineStatusTimeline{
+		CdWorkflowRunnerId: runner.Id,
+		Status:             pipelineConfig.TIMELINE_STATUS_DEPLOYMENT_FAILED,
+		StatusDetail:       fmt.Sprintf("Deployment failed: Image signature verification failed, %s.", evaluation.Reason),
+		StatusTime:         time.Now(),
+		AuditLog: sql.AuditLog{
+			CreatedBy: 1,
+			CreatedOn: time.Now(),
+			UpdatedBy: 1,
+			UpdatedOn: time.Now(),
+		},
+	}
+	err = impl.cdPipelineStatusTimelineRepo.SaveTimeline(timeline)
+	if err != nil {
+		impl.logger.Errorw("error in creating timeline status for deployment fail - image signature verification", "err", err, "timeline", timeline)
+	}
+	event := impl.eventFactory.Build(util2.Fail, &pipeline.Id, pipeline.AppId, &pipeline.EnvironmentId, util2.CD)
+	event.CdWorkflowType = bean.CD_WORKFLOW_TYPE_DEPLOY
+	event.CdWorkflowRunnerId = runner.Id
+	event.CiArtifactId = artifact.Id
+	event.UserId = int(runner.TriggeredBy)
+	event.Payload = &client.Payload{
+		Stage:          string(bean.CD_WORKFLOW_TYPE_DEPLOY),
+		DockerImageUrl: artifact.Image,
+		FailureReason:  fmt.Sprintf("Image signature verification failed, %s", evaluation.Reason),
+	}
+	_, evtErr := impl.eventClient.WriteNotificationEvent(event)
+	if evtErr != nil {
+		impl.logger.Errorw("CD failure event not sent", "error", evtErr)
+	}
+	return false, nil
+}
+
+// checkLicensePolicy enforces license policies on the packages of the sbom of an artifact, the runner is failed and a
+// failure event is sent if a package license is blocked. Artifacts without sbom are blocked by a blocking missing sbom
+// policy, otherwise they are deployed with a warning in the timeline
+func (impl *WorkflowDagExecutorImpl) checkLicensePolicy(runner *pipelineConfig.CdWorkflowRunner, artifact *repository.CiArtifact, pipeline *pipelineConfig.Pipeline) (bool, error) {
+	env, err := impl.envRepository.FindById(pipeline.EnvironmentId)
+	if err != nil {
+		impl.logger.Errorw("error while fetching env", "err", err, "envId", pipeline.EnvironmentId)
+		return false, err
+	}
+	licensePolicy, err := impl.licensePolicyRepository.GetApplicablePolicies(env.ClusterId, pipeline.EnvironmentId, pipeline.AppId, false)
+	if err != nil {
+		impl.logger.Errorw("error while fetching license policies", "err", err, "pipelineId", pipeline.Id)
+		return false, err
+	}
+	if len(licensePolicy) == 0 {
+		return true, nil
+	}
+	var components []*sbom.CiArtifactSbomComponent
+	if len(artifact.ImageDigest) > 0 {
+		components, err = impl.sbomRepository.FindComponentsByImageDigest(artifact.ImageDigest)
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error in fetching sbom components", "err", err, "digest", artifact.ImageDigest)
+			return false, err
+		}
+	}
+	var blockedLicenses []*security.BlockedLicense
+	if len(components) == 0 {
+		blockedLicenses = security.EnforceMissingSbomPolicy(licensePolicy)
+		if len(blockedLicenses) == 0 {
+			impl.saveLicensePolicyTimeline(runner.Id, pipelineConfig.TIMELINE_STATUS_SBOM_MISSING, fmt.Sprintf("No sbom found for image %s, license policies are not enforced.", artifact.Image))
+			return true, nil
+		}
+	} else {
+		blockedLicenses = security.EnforceLicensePolicy(sbom.GetPackageLicenses(components), licensePolicy)
+	}
+	if len(blockedLicenses) == 0 {
+		return true, nil
+	}
+	runner.Status = WorkflowFailed
+	runner.Message = BuildLicenseViolationMessage(blockedLicenses)
+	runner.FinishedOn = time.Now()
+	err = impl.cdWorkflowRepository.UpdateWorkFlowRunner(runner)
+	if err != nil {
+		impl.logger.Errorw("error in updating status", "err", err, "runner", runner.Id)
+		return false, err
+	}
+	impl.saveLicensePolicyTimeline(runner.Id, pipelineConfig.TIMELINE_STATUS_DEPLOYMENT_FAILED, fmt.Sprintf("Deployment failed: License policy violated. %s", BuildLicenseViolationDetail(blockedLicenses)))
+	event := impl.eventFactory.Build(util2.Fail, &pipeline.Id, pipeline.AppId, &pipeline.EnvironmentId, util2.CD)
+	event.CdWorkflowType = bean.CD_WORKFLOW_TYPE_DEPLOY
+	event.CdWorkflowRunnerId = runner.Id
+	event.CiArtifactId = artifact.Id
+	event.UserId = int(runner.TriggeredBy)
+	event.Payload = &client.Payload{
+		Stage:          string(bean.CD_WORKFLOW_TYPE_DEPLOY),
+		DockerImageUrl: artifact.Image,
+		FailureReason:  BuildLicenseViolationDetail(blockedLicenses),
+	}
+	_, evtErr := impl.eventClient.WriteNotificationEvent(event)
+	if evtErr != nil {
+		impl.logger.Errorw("CD failure event not sent", "error", evtErr)
+	}
+	return false, nil
+}
+
+func (impl *WorkflowDagExecutorImpl) saveLicensePolicyTimeline(runnerId int, status pipelineConfig.TimelineStatus, statusDetail string) {
+	timeline := &pipelineConfig.PipelineStatusTimeline{
+		CdWorkflowRunnerId: runnerId,
+		Status:             status,
+		StatusDetail:       statusDetail,
+		StatusTime:         time.Now(),
+		AuditLog: sql.AuditLog{
+			CreatedBy: 1,
+			CreatedOn: time.Now(),
+			UpdatedBy: 1,
+			UpdatedOn: time.Now(),
+		},
+	}
+	err := impl.cdPipelineStatusTimelineRepo.SaveTimeline(timeline)
+	if err != nil {
+		impl.logger.Errorw("error in creating timeline status for license policy", "err", err, "timeline", timeline)
+	}
+}
+
+func (impl *WorkflowDagExecutorImpl) saveDeploymentWindowTimeline(runnerId int, status pipelineConfig.TimelineStatus, statusDetail string) {
+	timeline := &pipelineConfig.PipelineStatusTimeline{
+		CdWorkflowRunnerId: runnerId,
+		Status:             status,
+		StatusDetail:       statusDetail,
+		StatusTime:         time.Now(),
+		AuditLog: sql.AuditLog{
+			CreatedBy: 1,
+			CreatedOn: time.Now(),
+			UpdatedBy: 1,
+			UpdatedOn: time.Now(),
+		},
+	}
+	err := impl.cdPipelineStatusTimelineRepo.SaveTimeline(timeline)
+	if err != nil {
+		impl.logger.Errorw("error in creating timeline status for deployment window", "err", err, "timeline", timeline)
+	}
+}
+
 func (impl *WorkflowDagExecutorImpl) updatePreviousDeploymentStatus(currentRunner *pipelineConfig.CdWorkflowRunner, pipelineId int, err error, triggeredAt time.Time) error {
 	if err != nil {
 		//creating cd pipeline status timeline for deployment failed
@@ -937,7 +1501,7 @@ func (impl *WorkflowDagExecutorImpl) updatePreviousDeploymentStatus(currentRunne
 		}
 		impl.logger.Errorw("error in triggering cd WF, setting wf status as fail ", "wfId", currentRunner.Id, "err", err)
 		currentRunner.Status = WorkflowFailed
-		currentRunner.Message = err.Error()
+		currentRunner.Message = TruncateRunnerMessage(err.Error())
 		currentRunner.FinishedOn = triggeredAt
 		err = impl.cdWorkflowRepository.UpdateWorkFlowRunner(currentRunner)
 		if err != nil {
@@ -1043,6 +1607,14 @@ func (impl *WorkflowDagExecutorImpl) StopStartApp(stopRequest *StopAppRequest, c
 		impl.logger.Errorw("error in stopping app", "err", err, "appId", stopRequest.AppId, "envId", stopRequest.EnvironmentId)
 		return 0, err
 	}
+	if stopRequest.RequestType == STOP {
+		event := impl.eventFactory.Build(util2.AppHibernated, &pipeline.Id, pipeline.AppId, &pipeline.EnvironmentId, util2.CD)
+		event = impl.eventFactory.BuildAppHibernatedData(event, stopRequest.UserId)
+		_, evtErr := impl.eventClient.WriteNotificationEvent(event)
+		if evtErr != nil {
+			impl.logger.Errorw("app hibernated event not sent", "error", evtErr, "appId", stopRequest.AppId, "envId", stopRequest.EnvironmentId)
+		}
+	}
 	return id, err
 }
 
@@ -1072,6 +1644,11 @@ func (impl *WorkflowDagExecutorImpl) ManualCdTrigger(overrideRequest *bean.Value
 		if overrideRequest.DeploymentType == models.DEPLOYMENTTYPE_UNKNOWN {
 			overrideRequest.DeploymentType = models.DEPLOYMENTTYPE_DEPLOY
 		}
+		err = impl.checkDeploymentApprovalForManualTrigger(overrideRequest.CiArtifactId, cdPipeline)
+		if err != nil {
+			impl.logger.Errorw("deployment approval check failed for manual trigger", "err", err, "pipelineId", cdPipeline.Id, "ciArtifactId", overrideRequest.CiArtifactId)
+			return 0, err
+		}
 		cdWf, err := impl.cdWorkflowRepository.FindByWorkflowIdAndRunnerType(overrideRequest.CdWorkflowId, bean.CD_WORKFLOW_TYPE_PRE)
 		if err != nil && !util.IsErrNoRows(err) {
 			impl.logger.Errorw("err", "err", err)
@@ -1126,6 +1703,12 @@ func (impl *WorkflowDagExecutorImpl) ManualCdTrigger(overrideRequest *bean.Value
 		if err != nil {
 			impl.logger.Errorw("error in creating timeline status for deployment initiation", "err", err, "timeline", timeline)
 		}
+		//checking deployment window of environment
+		err = impl.checkDeploymentWindowForManualTrigger(runner, cdPipeline, overrideRequest.UserId, triggeredAt)
+		if err != nil {
+			impl.logger.Errorw("deployment window check failed for manual trigger", "err", err, "pipelineId", cdPipeline.Id)
+			return 0, err
+		}
 		//checking vulnerability for deploying image
 		artifact, err := impl.ciArtifactRepository.Get(overrideRequest.CiArtifactId)
 		if err != nil {
@@ -1133,6 +1716,7 @@ func (impl *WorkflowDagExecutorImpl) ManualCdTrigger(overrideRequest *bean.Value
 			return 0, err
 		}
 		isVulnerable := false
+		var blockedCves []*security.BlockedCve
 		if len(artifact.ImageDigest) > 0 {
 			var cveStores []*security.CveStore
 			imageScanResult, err := impl.scanResultRepository.FindByImageDigest(artifact.ImageDigest)
@@ -1143,12 +1727,12 @@ func (impl *WorkflowDagExecutorImpl) ManualCdTrigger(overrideRequest *bean.Value
 			for _, item := range imageScanResult {
 				cveStores = append(cveStores, &item.CveStore)
 			}
-			blockCveList, err := impl.cvePolicyRepository.GetBlockedCVEList(cveStores, cdPipeline.Environment.ClusterId, cdPipeline.EnvironmentId, cdPipeline.AppId, false)
+			blockedCves, err = impl.cvePolicyRepository.GetBlockedCves(cveStores, cdPipeline.Environment.ClusterId, cdPipeline.EnvironmentId, cdPipeline.AppId, false)
 			if err != nil {
 				impl.logger.Errorw("error while fetching env", "err", err)
 				return 0, err
 			}
-			if len(blockCveList) > 0 {
+			if len(blockedCves) > 0 {
 				isVulnerable = true
 			}
 		}
@@ -1162,7 +1746,7 @@ func (impl *WorkflowDagExecutorImpl) ManualCdTrigger(overrideRequest *bean.Value
 				StartedOn:    triggeredAt,
 				Namespace:    impl.cdConfig.DefaultNamespace,
 				CdWorkflowId: cdWorkflowId,
-				Message:      "Found vulnerability on image",
+				Message:      BuildVulnerabilityMessage(blockedCves),
 			}
 			_, err := impl.cdWorkflowRepository.SaveWorkFlowRunner(runner)
 			if err != nil {
@@ -1173,7 +1757,7 @@ func (impl *WorkflowDagExecutorImpl) ManualCdTrigger(overrideRequest *bean.Value
 			timeline := &pipelineConfig.PipelineStatusTimeline{
 				CdWorkflowRunnerId: runner.Id,
 				Status:             pipelineConfig.TIMELINE_STATUS_DEPLOYMENT_FAILED,
-				StatusDetail:       "Deployment failed: Vulnerability policy violated.",
+				StatusDetail:       fmt.Sprintf("Deployment failed: Vulnerability policy violated. %s", BuildVulnerabilityDetail(blockedCves)),
 				StatusTime:         time.Now(),
 				AuditLog: sql.AuditLog{
 					CreatedBy: 1,
@@ -1186,8 +1770,23 @@ func (impl *WorkflowDagExecutorImpl) ManualCdTrigger(overrideRequest *bean.Value
 			if err != nil {
 				impl.logger.Errorw("error in creating timeline status for deployment fail - cve policy violation", "err", err, "timeline", timeline)
 			}
+			impl.writeImageScanBlockedEvent(runner, artifact, cdPipeline, blockedCves)
 			return 0, fmt.Errorf("found vulnerability for image digest %s", artifact.ImageDigest)
 		}
+		compliant, err := impl.checkLicensePolicy(savedWfr, artifact, cdPipeline)
+		if err != nil {
+			return 0, err
+		}
+		if !compliant {
+			return 0, fmt.Errorf("found license policy violation for image %s", artifact.Image)
+		}
+		verified, err := impl.checkImageSignature(savedWfr, artifact, cdPipeline)
+		if err != nil {
+			return 0, err
+		}
+		if !verified {
+			return 0, fmt.Errorf("image signature verification failed for image %s", artifact.Image)
+		}
 
 		releaseId, err = impl.appService.TriggerRelease(overrideRequest, ctx, triggeredAt, overrideRequest.UserId, savedWfr.Id)
 		//	return after error handling
@@ -1199,6 +1798,7 @@ func (impl *WorkflowDagExecutorImpl) ManualCdTrigger(overrideRequest *bean.Value
 			impl.logger.Errorw("error while update previous cd workflow runners", "err", err, "runner", runner, "pipelineId", cdPipeline.Id)
 			return 0, err
 		}
+		overrideRequest.CdWorkflowRunnerId = savedWfr.Id
 	} else if overrideRequest.CdWorkflowType == bean.CD_WORKFLOW_TYPE_POST {
 		cdWfRunner, err := impl.cdWorkflowRepository.FindByWorkflowIdAndRunnerType(overrideRequest.CdWorkflowId, bean.CD_WORKFLOW_TYPE_DEPLOY)
 		if err != nil && !util.IsErrNoRows(err) {
@@ -1236,6 +1836,17 @@ type BulkTriggerRequest struct {
 }
 
 func (impl *WorkflowDagExecutorImpl) TriggerBulkDeploymentAsync(requests []*BulkTriggerRequest, UserId int32) (interface{}, error) {
+	//checking deployment windows before accepting bulk request
+	err := impl.checkDeploymentWindowForBulkTrigger(requests, UserId)
+	if err != nil {
+		impl.logger.Errorw("deployment window check failed for bulk trigger", "err", err, "req", requests)
+		return nil, err
+	}
+	err = impl.checkDeploymentApprovalForBulkTrigger(requests)
+	if err != nil {
+		impl.logger.Errorw("deployment approval check failed for bulk trigger", "err", err, "req", requests)
+		return nil, err
+	}
 	var cdWorkflows []*pipelineConfig.CdWorkflow
 	for _, request := range requests {
 		cdWf := &pipelineConfig.CdWorkflow{
@@ -1246,7 +1857,7 @@ func (impl *WorkflowDagExecutorImpl) TriggerBulkDeploymentAsync(requests []*Bulk
 		}
 		cdWorkflows = append(cdWorkflows, cdWf)
 	}
-	err := impl.cdWorkflowRepository.SaveWorkFlows(cdWorkflows...)
+	err = impl.cdWorkflowRepository.SaveWorkFlows(cdWorkflows...)
 	if err != nil {
 		impl.logger.Errorw("error in saving wfs", "req", requests, "err", err)
 		return nil, err
@@ -1431,3 +2042,68 @@ func (impl *WorkflowDagExecutorImpl) buildACDContext() (acdContext context.Conte
 	ctx = context.WithValue(ctx, "token", acdToken)
 	return ctx, nil
 }
+
+// writeImageScanBlockedEvent notifies a deployment which failed as its image has cves blocked by cve policy
+func (impl *WorkflowDagExecutorImpl) writeImageScanBlockedEvent(runner *pipelineConfig.CdWorkflowRunner, artifact *repository.CiArtifact, pipeline *pipelineConfig.Pipeline, blockedCves []*security.BlockedCve) {
+	event := impl.eventFactory.Build(util2.ImageScanBlocked, &pipeline.Id, pipeline.AppId, &pipeline.EnvironmentId, util2.CD)
+	event = impl.eventFactory.BuildImageScanBlockedData(event, runner, artifact, blockedCves)
+	_, evtErr := impl.eventClient.WriteNotificationEvent(event)
+	if evtErr != nil {
+		impl.logger.Errorw("image scan blocked event not sent", "error", evtErr, "runnerId", runner.Id)
+	}
+}
+
+// BuildVulnerabilityMessage summarises blocked cves by count and the policy rules which blocked them, it is kept
+// within the runner message length, see BuildVulnerabilityDetail for the full list
+func BuildVulnerabilityMessage(blockedCves []*security.BlockedCve) string {
+	var rules []string
+	ruleAdded := make(map[string]bool)
+	for _, blockedCve := range blockedCves {
+		if !ruleAdded[blockedCve.Rule] {
+			ruleAdded[blockedCve.Rule] = true
+			rules = append(rules, blockedCve.Rule)
+		}
+	}
+	return TruncateRunnerMessage(fmt.Sprintf("%d CVEs blocked by policy %s", len(blockedCves), strings.Join(rules, ", ")))
+}
+
+// BuildVulnerabilityDetail lists the blocked cves along with the policy rule which blocked them
+func BuildVulnerabilityDetail(blockedCves []*security.BlockedCve) string {
+	var cves []string
+	for _, blockedCve := range blockedCves {
+		cves = append(cves, fmt.Sprintf("%s (%s)", blockedCve.Cve.Name, blockedCve.Rule))
+	}
+	return "Found vulnerability on image: " + strings.Join(cves, ", ")
+}
+
+// TruncateRunnerMessage caps a message to the length of cd_workflow_runner.message column
+func TruncateRunnerMessage(message string) string {
+	runes := []rune(message)
+	if len(runes) <= cdWorkflowRunnerMessageMaxLength {
+		return message
+	}
+	return string(runes[:cdWorkflowRunnerMessageMaxLength-3]) + "..."
+}
+
+const missingSbomViolationMessage = "No sbom found on image, blocked by missing sbom license policy"
+
+// BuildLicenseViolationMessage summarises blocked packages by count, it is kept within the runner message length, see
+// BuildLicenseViolationDetail for the full list
+func BuildLicenseViolationMessage(blockedLicenses []*security.BlockedLicense) string {
+	if len(blockedLicenses) == 1 && blockedLicenses[0].Rule == security.LicenseBlockedByMissingSbomPolicy {
+		return missingSbomViolationMessage
+	}
+	return TruncateRunnerMessage(fmt.Sprintf("%d packages blocked by license policy", len(blockedLicenses)))
+}
+
+// BuildLicenseViolationDetail lists the blocked packages along with the licenses which blocked them
+func BuildLicenseViolationDetail(blockedLicenses []*security.BlockedLicense) string {
+	var packages []string
+	for _, blockedLicense := range blockedLicenses {
+		if blockedLicense.Rule == security.LicenseBlockedByMissingSbomPolicy {
+			return missingSbomViolationMessage
+		}
+		packages = append(packages, fmt.Sprintf("%s@%s (%s)", blockedLicense.Package.Package, blockedLicense.Package.Version, strings.Join(blockedLicense.Licenses, ", ")))
+	}
+	return "Found blocked license on image: " + strings.Join(packages, ", ")
+}
@@ -0,0 +1,88 @@
+package pipeline
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/security"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestBuildVulnerabilityMessage(t *testing.T) {
+	var blockedCves []*security.BlockedCve
+	for i := 0; i < 100; i++ {
+		blockedCves = append(blockedCves, &security.BlockedCve{Cve: &security.CveStore{Name: fmt.Sprintf("CVE-2022-%05d", i)}, Rule: "critical severity"})
+	}
+	blockedCves = append(blockedCves, &security.BlockedCve{Cve: &security.CveStore{Name: "CVE-2022-99999"}, Rule: "package openssl"})
+	got := BuildVulnerabilityMessage(blockedCves)
+	want := "101 CVEs blocked by policy critical severity, package openssl"
+	if got != want {
+		t.Errorf("BuildVulnerabilityMessage() = %s, want %s", got, want)
+	}
+	if detail := BuildVulnerabilityDetail(blockedCves); !strings.Contains(detail, "CVE-2022-99999 (package openssl)") {
+		t.Errorf("BuildVulnerabilityDetail() = %s, missing blocked cve", detail)
+	}
+}
+
+func TestBuildLicenseViolationMessage(t *testing.T) {
+	var blockedLicenses []*security.BlockedLicense
+	for i := 0; i < 100; i++ {
+		blockedLicenses = append(blockedLicenses, &security.BlockedLicense{
+			Package:  &security.PackageLicense{Package: fmt.Sprintf("package-%d", i), Version: "1.0.0"},
+			Licenses: []string{"AGPL-3.0-only"},
+			Rule:     security.LicenseBlockedByLicensePolicy,
+		})
+	}
+	if got, want := BuildLicenseViolationMessage(blockedLicenses), "100 packages blocked by license policy"; got != want {
+		t.Errorf("BuildLicenseViolationMessage() = %s, want %s", got, want)
+	}
+	if detail := BuildLicenseViolationDetail(blockedLicenses); !strings.Contains(detail, "package-99@1.0.0 (AGPL-3.0-only)") {
+		t.Errorf("BuildLicenseViolationDetail() = %s, missing blocked package", detail)
+	}
+	missingSbom := security.EnforceMissingSbomPolicy(map[string]*security.LicensePolicy{"no-sbom": {License: security.MissingSbomLicense, Action: security.Block}})
+	if got := BuildLicenseViolationMessage(missingSbom); got != missingSbomViolationMessage {
+		t.Errorf("BuildLicenseViolationMessage() = %s, want %s", got, missingSbomViolationMessage)
+	}
+}
+
+func TestTruncateRunnerMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		wantLen int
+	}{
+		{name: "short message", message: "Deployment blocked", wantLen: 18},
+		{name: "long message", message: strings.Repeat("a", 1000), wantLen: cdWorkflowRunnerMessageMaxLength},
+		{name: "long multi byte message", message: strings.Repeat("é", 300), wantLen: cdWorkflowRunnerMessageMaxLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TruncateRunnerMessage(tt.message); utf8.RuneCountInString(got) != tt.wantLen {
+				t.Errorf("TruncateRunnerMessage() length = %d, want %d", utf8.RuneCountInString(got), tt.wantLen)
+			}
+		})
+	}
+}
+
+func TestIsApprovedArtifactSuperseded(t *testing.T) {
+	approvedArtifact := &repository.CiArtifact{Id: 10}
+	tests := []struct {
+		name           string
+		lastDeployedWf *pipelineConfig.CdWorkflow
+		want           bool
+	}{
+		{name: "never deployed", lastDeployedWf: nil, want: false},
+		{name: "older artifact deployed", lastDeployedWf: &pipelineConfig.CdWorkflow{CiArtifactId: 8}, want: false},
+		{name: "approved artifact deployed", lastDeployedWf: &pipelineConfig.CdWorkflow{CiArtifactId: 10}, want: false},
+		{name: "newer artifact deployed", lastDeployedWf: &pipelineConfig.CdWorkflow{CiArtifactId: 12}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsApprovedArtifactSuperseded(approvedArtifact, tt.lastDeployedWf); got != tt.want {
+				t.Errorf("IsApprovedArtifactSuperseded() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -20,6 +20,7 @@ package pipeline
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	blob_storage "github.com/devtron-labs/common-lib/blob-storage"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"net/url"
@@ -33,6 +34,7 @@ import (
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
 	"github.com/devtron-labs/devtron/pkg/bean"
 	bean2 "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/secretScan"
 	"go.uber.org/zap"
 	v12 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -99,6 +101,11 @@ type WorkflowRequest struct {
 	CiArtifactRegion           string                            `json:"ciArtifactRegion"`
 	InvalidateCache            bool                              `json:"invalidateCache"`
 	ScanEnabled                bool                              `json:"scanEnabled"`
+	SbomFormat                 string                            `json:"sbomFormat,omitempty"`   //cyclonedx-json or spdx-json
+	SbomLocation               string                            `json:"sbomLocation,omitempty"` //key in artifact bucket where sbom is uploaded, only its digest is sent back with ci complete event
+	ImageSigningFormat         string                            `json:"imageSigningFormat,omitempty"`
+	ImageSigningKey            string                            `json:"imageSigningKey,omitempty"`
+	SecretScan                 *secretScan.SecretScanRequest     `json:"secretScan,omitempty"`
 	CloudProvider              blob_storage.BlobStorageType      `json:"cloudProvider"`
 	BlobStorageConfigured      bool                              `json:"blobStorageConfigured"`
 	BlobStorageS3Config        *blob_storage.BlobStorageS3Config `json:"blobStorageS3Config"`
@@ -111,6 +118,18 @@ type WorkflowRequest struct {
 	RefPlugins                 []*bean2.RefPluginObject          `json:"refPlugins"`
 	AppName                    string                            `json:"appName"`
 	TriggerByAuthor            string                            `json:"triggerByAuthor"`
+	CheckoutPath               string                            `json:"-"`
+	MatrixLegs                 []*WorkflowRequest                `json:"-"` //legs of a matrix build, run as parallel nodes of this workflow
+	MatrixManifest             *CiBuildMatrixManifestRequest     `json:"-"`
+}
+
+// CiBuildMatrixManifestRequest combines the images of matrix legs in a manifest list once all legs succeed
+type CiBuildMatrixManifestRequest struct {
+	Image            string
+	LegImages        []string
+	RegistryHost     string
+	RegistryUsername string
+	RegistryPassword string
 }
 
 const BLOB_STORAGE_AZURE = "AZURE"
@@ -175,6 +194,7 @@ func NewWorkflowServiceImpl(Logger *zap.SugaredLogger, ciConfig *CiConfig) *Work
 
 const ciEvent = "CI"
 const cdStage = "CD"
+const ciMatrixLegTemplatePrefix = "ci-leg-"
 
 func (impl *WorkflowServiceImpl) SubmitWorkflow(workflowRequest *WorkflowRequest) (*v1alpha1.Workflow, error) {
 	containerEnvVariables := []v12.EnvVar{{Name: "IMAGE_SCANNER_ENDPOINT", Value: impl.ciConfig.ImageScannerEndpoint}}
@@ -183,6 +203,64 @@ func (impl *WorkflowServiceImpl) SubmitWorkflow(workflowRequest *WorkflowRequest
 		containerEnvVariables = append(containerEnvVariables, miniCred...)
 	}
 
+	wfClient, err := impl.getClientInstance(workflowRequest.Namespace)
+	if err != nil {
+		impl.Logger.Errorw("cannot build wf client", "err", err)
+		return nil, err
+	}
+
+	ttl := int32(impl.ciConfig.BuildLogTTLValue)
+	var templates []v1alpha1.Template
+	if len(workflowRequest.MatrixLegs) == 0 {
+		ciTemplate, err := impl.buildCiTemplate("ci", workflowRequest, containerEnvVariables)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, ciTemplate)
+	} else {
+		templates, err = impl.buildCiMatrixTemplates(workflowRequest, containerEnvVariables)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		ciWorkflow = v1alpha1.Workflow{
+			ObjectMeta: v1.ObjectMeta{
+				GenerateName: workflowRequest.WorkflowNamePrefix + "-",
+				Labels:       map[string]string{"devtron.ai/workflow-purpose": "ci"},
+			},
+			Spec: v1alpha1.WorkflowSpec{
+				ServiceAccountName: impl.ciConfig.WorkflowServiceAccount,
+				//NodeSelector:            map[string]string{impl.ciConfig.TaintKey: impl.ciConfig.TaintValue},
+				//Tolerations:             []v12.Toleration{{Key: impl.ciConfig.TaintKey, Value: impl.ciConfig.TaintValue, Operator: v12.TolerationOpEqual, Effect: v12.TaintEffectNoSchedule}},
+				Entrypoint: "ci",
+				TTLStrategy: &v1alpha1.TTLStrategy{
+					SecondsAfterCompletion: &ttl,
+				},
+				Templates: templates,
+			},
+		}
+	)
+	if impl.ciConfig.TaintKey != "" || impl.ciConfig.TaintValue != "" {
+		ciWorkflow.Spec.Tolerations = []v12.Toleration{{Key: impl.ciConfig.TaintKey, Value: impl.ciConfig.TaintValue, Operator: v12.TolerationOpEqual, Effect: v12.TaintEffectNoSchedule}}
+	}
+	if len(impl.ciConfig.NodeLabel) > 0 {
+		ciWorkflow.Spec.NodeSelector = impl.ciConfig.NodeLabel
+	}
+	wfTemplate, err := json.Marshal(ciWorkflow)
+	if err != nil {
+		impl.Logger.Errorw("marshal error", "err", err)
+	}
+	impl.Logger.Debug("---->", string(wfTemplate))
+
+	createdWf, err := wfClient.Create(context.Background(), &ciWorkflow, v1.CreateOptions{}) // submit the hello world workflow
+	impl.Logger.Debug("workflow submitted: " + createdWf.Name)
+	impl.checkErr(err)
+	return createdWf, err
+}
+
+func (impl *WorkflowServiceImpl) buildCiTemplate(templateName string, workflowRequest *WorkflowRequest, containerEnvVariables []v12.EnvVar) (v1alpha1.Template, error) {
 	ciCdTriggerEvent := CiCdTriggerEvent{
 		Type:      ciEvent,
 		CiRequest: workflowRequest,
@@ -191,16 +269,10 @@ func (impl *WorkflowServiceImpl) SubmitWorkflow(workflowRequest *WorkflowRequest
 	workflowJson, err := json.Marshal(&ciCdTriggerEvent)
 	if err != nil {
 		impl.Logger.Errorw("err", err)
-		return nil, err
+		return v1alpha1.Template{}, err
 	}
 	impl.Logger.Debugw("workflowRequest ---->", "workflowJson", string(workflowJson))
 
-	wfClient, err := impl.getClientInstance(workflowRequest.Namespace)
-	if err != nil {
-		impl.Logger.Errorw("cannot build wf client", "err", err)
-		return nil, err
-	}
-
 	privileged := true
 	blobStorageConfigured := workflowRequest.BlobStorageConfigured
 	archiveLogs := blobStorageConfigured
@@ -210,7 +282,6 @@ func (impl *WorkflowServiceImpl) SubmitWorkflow(workflowRequest *WorkflowRequest
 
 	reqCpu := impl.ciConfig.ReqCpu
 	reqMem := impl.ciConfig.ReqMem
-	ttl := int32(impl.ciConfig.BuildLogTTLValue)
 
 	gcpBlobConfig := workflowRequest.GcpBlobConfig
 	blobStorageS3Config := workflowRequest.BlobStorageS3Config
@@ -273,75 +344,87 @@ func (impl *WorkflowServiceImpl) SubmitWorkflow(workflowRequest *WorkflowRequest
 		}
 	}
 
-	var (
-		ciWorkflow = v1alpha1.Workflow{
-			ObjectMeta: v1.ObjectMeta{
-				GenerateName: workflowRequest.WorkflowNamePrefix + "-",
-				Labels:       map[string]string{"devtron.ai/workflow-purpose": "ci"},
+	ciTemplate := v1alpha1.Template{
+		Name: templateName,
+		Container: &v12.Container{
+			Env:   containerEnvVariables,
+			Image: workflowRequest.CiImage, //TODO need to check whether trigger buildx image or normal image
+			Args:  []string{string(workflowJson)},
+			SecurityContext: &v12.SecurityContext{
+				Privileged: &privileged,
 			},
-			Spec: v1alpha1.WorkflowSpec{
-				ServiceAccountName: impl.ciConfig.WorkflowServiceAccount,
-				//NodeSelector:            map[string]string{impl.ciConfig.TaintKey: impl.ciConfig.TaintValue},
-				//Tolerations:             []v12.Toleration{{Key: impl.ciConfig.TaintKey, Value: impl.ciConfig.TaintValue, Operator: v12.TolerationOpEqual, Effect: v12.TaintEffectNoSchedule}},
-				Entrypoint: "ci",
-				TTLStrategy: &v1alpha1.TTLStrategy{
-					SecondsAfterCompletion: &ttl,
+			Resources: v12.ResourceRequirements{
+				Limits: v12.ResourceList{
+					"cpu":    resource.MustParse(limitCpu),
+					"memory": resource.MustParse(limitMem),
 				},
-				Templates: []v1alpha1.Template{
-					{
-						Name: "ci",
-						Container: &v12.Container{
-							Env:   containerEnvVariables,
-							Image: workflowRequest.CiImage, //TODO need to check whether trigger buildx image or normal image
-							Args:  []string{string(workflowJson)},
-							SecurityContext: &v12.SecurityContext{
-								Privileged: &privileged,
-							},
-							Resources: v12.ResourceRequirements{
-								Limits: v12.ResourceList{
-									"cpu":    resource.MustParse(limitCpu),
-									"memory": resource.MustParse(limitMem),
-								},
-								Requests: v12.ResourceList{
-									"cpu":    resource.MustParse(reqCpu),
-									"memory": resource.MustParse(reqMem),
-								},
-							},
-							Ports: []v12.ContainerPort{{
-								//exposed for user specific data from ci container
-								Name:          "app-data",
-								ContainerPort: 9102,
-							}},
-						},
-						ActiveDeadlineSeconds: &intstr.IntOrString{
-							IntVal: int32(workflowRequest.ActiveDeadlineSeconds),
-						},
-						ArchiveLocation: &v1alpha1.ArtifactLocation{
-							ArchiveLogs: &archiveLogs,
-							S3:          s3Artifact,
-							GCS:         gcsArtifact,
-						},
-					},
+				Requests: v12.ResourceList{
+					"cpu":    resource.MustParse(reqCpu),
+					"memory": resource.MustParse(reqMem),
 				},
 			},
-		}
-	)
-	if impl.ciConfig.TaintKey != "" || impl.ciConfig.TaintValue != "" {
-		ciWorkflow.Spec.Tolerations = []v12.Toleration{{Key: impl.ciConfig.TaintKey, Value: impl.ciConfig.TaintValue, Operator: v12.TolerationOpEqual, Effect: v12.TaintEffectNoSchedule}}
+			Ports: []v12.ContainerPort{{
+				//exposed for user specific data from ci container
+				Name:          "app-data",
+				ContainerPort: 9102,
+			}},
+		},
+		ActiveDeadlineSeconds: &intstr.IntOrString{
+			IntVal: int32(workflowRequest.ActiveDeadlineSeconds),
+		},
+		ArchiveLocation: &v1alpha1.ArtifactLocation{
+			ArchiveLogs: &archiveLogs,
+			S3:          s3Artifact,
+			GCS:         gcsArtifact,
+		},
 	}
-	if len(impl.ciConfig.NodeLabel) > 0 {
-		ciWorkflow.Spec.NodeSelector = impl.ciConfig.NodeLabel
+	return ciTemplate, nil
+}
+
+// buildCiMatrixTemplates runs every matrix leg as a parallel task of the ci dag, the manifest list of leg images is
+// created once all legs succeed
+func (impl *WorkflowServiceImpl) buildCiMatrixTemplates(workflowRequest *WorkflowRequest, containerEnvVariables []v12.EnvVar) ([]v1alpha1.Template, error) {
+	var templates []v1alpha1.Template
+	dag := &v1alpha1.DAGTemplate{}
+	var legTaskNames []string
+	for _, legRequest := range workflowRequest.MatrixLegs {
+		legTemplate, err := impl.buildCiTemplate(GetCiMatrixLegTemplateName(legRequest.WorkflowId), legRequest, containerEnvVariables)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, legTemplate)
+		legTaskName := fmt.Sprintf("leg-%d", legRequest.WorkflowId)
+		legTaskNames = append(legTaskNames, legTaskName)
+		dag.Tasks = append(dag.Tasks, v1alpha1.DAGTask{Name: legTaskName, Template: legTemplate.Name})
 	}
-	wfTemplate, err := json.Marshal(ciWorkflow)
-	if err != nil {
-		impl.Logger.Errorw("marshal error", "err", err)
+	manifest := workflowRequest.MatrixManifest
+	if manifest != nil {
+		script := BuildManifestListScript(manifest.RegistryHost, manifest.Image, manifest.LegImages, len(manifest.RegistryUsername) > 0)
+		templates = append(templates, v1alpha1.Template{
+			Name: "manifest",
+			Container: &v12.Container{
+				Image:   impl.ciConfig.CiBuildMatrixManifestImage,
+				Command: []string{"sh", "-c"},
+				Args:    []string{script},
+				Env: []v12.EnvVar{
+					{Name: "REGISTRY_USERNAME", Value: manifest.RegistryUsername},
+					{Name: "REGISTRY_PASSWORD", Value: manifest.RegistryPassword},
+				},
+			},
+			ActiveDeadlineSeconds: &intstr.IntOrString{
+				IntVal: int32(workflowRequest.ActiveDeadlineSeconds),
+			},
+		})
+		dag.Tasks = append(dag.Tasks, v1alpha1.DAGTask{Name: "manifest", Template: "manifest", Dependencies: legTaskNames})
 	}
-	impl.Logger.Debug("---->", string(wfTemplate))
+	templates = append(templates, v1alpha1.Template{Name: "ci", DAG: dag})
+	return templates, nil
+}
 
-	createdWf, err := wfClient.Create(context.Background(), &ciWorkflow, v1.CreateOptions{}) // submit the hello world workflow
-	impl.Logger.Debug("workflow submitted: " + createdWf.Name)
-	impl.checkErr(err)
-	return createdWf, err
+// GetCiMatrixLegTemplateName is the template of the leg run as ci workflow with given id, used to map workflow nodes
+// back to the leg
+func GetCiMatrixLegTemplateName(legCiWorkflowId int) string {
+	return fmt.Sprintf("%s%d", ciMatrixLegTemplatePrefix, legCiWorkflowId)
 }
 
 func (impl *WorkflowServiceImpl) getClientInstance(namespace string) (v1alpha12.WorkflowInterface, error) {
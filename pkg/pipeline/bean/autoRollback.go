@@ -0,0 +1,6 @@
+package bean
+
+type AutoRollbackPolicyDto struct {
+	Enabled                    bool `json:"enabled"`
+	HealthCheckWindowInMinutes int  `json:"healthCheckWindowInMinutes" validate:"min=0"`
+}
@@ -0,0 +1,18 @@
+package bean
+
+type CanaryAnalysisPolicyDto struct {
+	Enabled bool `json:"enabled"`
+	//promQL queries, $namespace and $pods are replaced by namespace and regex of canary or stable pod names
+	ErrorRateQuery string `json:"errorRateQuery"`
+	LatencyQuery   string `json:"latencyQuery"`
+	//canary fails a step if its error rate is more than stable error rate by this much (absolute, ratio of requests)
+	MaxErrorRateIncrease float64 `json:"maxErrorRateIncrease" validate:"min=0"`
+	//canary fails a step if its latency is more than stable latency by this percentage
+	MaxLatencyIncreasePercent float64                  `json:"maxLatencyIncreasePercent" validate:"min=0"`
+	Steps                     []*CanaryAnalysisStepDto `json:"steps" validate:"dive"`
+}
+
+type CanaryAnalysisStepDto struct {
+	//metrics are compared once this much time has passed since previous step
+	IntervalInMinutes int `json:"intervalInMinutes" validate:"min=1"`
+}
@@ -9,7 +9,7 @@ type PipelineStageDto struct {
 	Id          int                          `json:"id"`
 	Name        string                       `json:"name,omitempty"`
 	Description string                       `json:"description,omitempty"`
-	Type        repository.PipelineStageType `json:"type,omitempty" validate:"omitempty,oneof=PRE_CI POST_CI"`
+	Type        repository.PipelineStageType `json:"type,omitempty" validate:"omitempty,oneof=PRE_CI POST_CI PRE_CD POST_CD"`
 	Steps       []*PipelineStageStepDto      `json:"steps"`
 }
 
@@ -22,6 +22,10 @@ type PipelineStageStepDto struct {
 	OutputDirectoryPath []string                    `json:"outputDirectoryPath"`
 	InlineStepDetail    *InlineStepDetailDto        `json:"inlineStepDetail"`
 	RefPluginStepDetail *RefPluginStepDetailDto     `json:"pluginRefStepDetail"`
+	Timeout             int                         `json:"timeout,omitempty"`      //in seconds, 0 means no step timeout
+	RetryCount          int                         `json:"retryCount,omitempty"`   //retries after a failed attempt
+	RetryBackoff        int                         `json:"retryBackoff,omitempty"` //in seconds, doubled after every retry
+	ContinueOnError     bool                        `json:"continueOnError,omitempty"`
 }
 
 type InlineStepDetailDto struct {
@@ -6,6 +6,8 @@ const (
 	VARIABLE_TYPE_VALUE       = "VALUE"
 	VARIABLE_TYPE_REF_PRE_CI  = "REF_PRE_CI"
 	VARIABLE_TYPE_REF_POST_CI = "REF_POST_CI"
+	VARIABLE_TYPE_REF_PRE_CD  = "REF_PRE_CD"
+	VARIABLE_TYPE_REF_POST_CD = "REF_POST_CD"
 	VARIABLE_TYPE_REF_GLOBAL  = "REF_GLOBAL"
 	VARIABLE_TYPE_REF_PLUGIN  = "REF_PLUGIN"
 )
@@ -34,6 +36,10 @@ type StepObject struct {
 	SourceCodeMount          *MountPath         `json:"sourceCodeMount"`   // destination path - mountCodeToContainerPath
 	ExtraVolumeMounts        []*MountPath       `json:"extraVolumeMounts"` // filePathMapping
 	ArtifactPaths            []string           `json:"artifactPaths"`
+	TimeoutInSeconds         int                `json:"timeoutInSeconds,omitempty"`
+	RetryCount               int                `json:"retryCount,omitempty"`
+	RetryBackoffInSeconds    int                `json:"retryBackoffInSeconds,omitempty"` //doubled after every retry
+	ContinueOnError          bool               `json:"continueOnError,omitempty"`
 }
 
 type VariableObject struct {
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type AutoRollbackStatus string
+
+const (
+	AUTO_ROLLBACK_STATUS_TRIGGERED AutoRollbackStatus = "TRIGGERED"
+	AUTO_ROLLBACK_STATUS_FAILED    AutoRollbackStatus = "FAILED"
+)
+
+// AutoRollbackPolicy is the opt-in auto rollback config of a cd pipeline, a deployment degrading within
+// HealthCheckWindowInMinutes of its trigger is rolled back to the previous healthy artifact
+type AutoRollbackPolicy struct {
+	tableName                  struct{} `sql:"cd_pipeline_auto_rollback_policy" pg:",discard_unknown_columns"`
+	Id                         int      `sql:"id,pk"`
+	PipelineId                 int      `sql:"pipeline_id,notnull"`
+	Enabled                    bool     `sql:"enabled,notnull"`
+	HealthCheckWindowInMinutes int      `sql:"health_check_window_in_minutes,notnull"`
+	sql.AuditLog
+}
+
+// AutoRollbackHistory records every auto rollback attempt, it is also used to never roll back a degraded
+// deployment twice or roll back a deployment which was itself an auto rollback
+type AutoRollbackHistory struct {
+	tableName                  struct{}           `sql:"cd_pipeline_auto_rollback_history" pg:",discard_unknown_columns"`
+	Id                         int                `sql:"id,pk"`
+	PipelineId                 int                `sql:"pipeline_id,notnull"`
+	DegradedCdWorkflowRunnerId int                `sql:"degraded_cd_workflow_runner_id,notnull"`
+	DegradedCiArtifactId       int                `sql:"degraded_ci_artifact_id,notnull"`
+	RollbackCiArtifactId       int                `sql:"rollback_ci_artifact_id"`
+	RollbackCdWorkflowRunnerId int                `sql:"rollback_cd_workflow_runner_id"`
+	Status                     AutoRollbackStatus `sql:"status,notnull"`
+	Message                    string             `sql:"message"`
+	sql.AuditLog
+}
+
+type AutoRollbackRepository interface {
+	SavePolicy(policy *AutoRollbackPolicy) error
+	UpdatePolicy(policy *AutoRollbackPolicy) error
+	FindPolicyByPipelineId(pipelineId int) (*AutoRollbackPolicy, error)
+	SaveHistory(history *AutoRollbackHistory) error
+	UpdateHistory(history *AutoRollbackHistory) error
+	FindHistoryByCdWorkflowRunnerId(cdWorkflowRunnerId int) ([]*AutoRollbackHistory, error)
+}
+
+func NewAutoRollbackRepositoryImpl(logger *zap.SugaredLogger,
+	dbConnection *pg.DB) *AutoRollbackRepositoryImpl {
+	return &AutoRollbackRepositoryImpl{
+		logger:       logger,
+		dbConnection: dbConnection,
+	}
+}
+
+type AutoRollbackRepositoryImpl struct {
+	logger       *zap.SugaredLogger
+	dbConnection *pg.DB
+}
+
+func (impl *AutoRollbackRepositoryImpl) SavePolicy(policy *AutoRollbackPolicy) error {
+	err := impl.dbConnection.Insert(policy)
+	if err != nil {
+		impl.logger.Errorw("error in saving auto rollback policy", "err", err, "policy", policy)
+		return err
+	}
+	return nil
+}
+
+func (impl *AutoRollbackRepositoryImpl) UpdatePolicy(policy *AutoRollbackPolicy) error {
+	err := impl.dbConnection.Update(policy)
+	if err != nil {
+		impl.logger.Errorw("error in updating auto rollback policy", "err", err, "policy", policy)
+		return err
+	}
+	return nil
+}
+
+func (impl *AutoRollbackRepositoryImpl) FindPolicyByPipelineId(pipelineId int) (*AutoRollbackPolicy, error) {
+	policy := &AutoRollbackPolicy{}
+	err := impl.dbConnection.Model(policy).
+		Where("pipeline_id = ?", pipelineId).
+		Select()
+	if err != nil {
+		if err != pg.ErrNoRows {
+			impl.logger.Errorw("error in getting auto rollback policy by pipelineId", "err", err, "pipelineId", pipelineId)
+		}
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (impl *AutoRollbackRepositoryImpl) SaveHistory(history *AutoRollbackHistory) error {
+	err := impl.dbConnection.Insert(history)
+	if err != nil {
+		impl.logger.Errorw("error in saving auto rollback history", "err", err, "history", history)
+		return err
+	}
+	return nil
+}
+
+func (impl *AutoRollbackRepositoryImpl) UpdateHistory(history *AutoRollbackHistory) error {
+	err := impl.dbConnection.Update(history)
+	if err != nil {
+		impl.logger.Errorw("error in updating auto rollback history", "err", err, "history", history)
+		return err
+	}
+	return nil
+}
+
+func (impl *AutoRollbackRepositoryImpl) FindHistoryByCdWorkflowRunnerId(cdWorkflowRunnerId int) ([]*AutoRollbackHistory, error) {
+	var histories []*AutoRollbackHistory
+	err := impl.dbConnection.Model(&histories).
+		WhereOr("degraded_cd_workflow_runner_id = ?", cdWorkflowRunnerId).
+		WhereOr("rollback_cd_workflow_runner_id = ?", cdWorkflowRunnerId).
+		Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting auto rollback history by runnerId", "err", err, "cdWorkflowRunnerId", cdWorkflowRunnerId)
+		return nil, err
+	}
+	return histories, nil
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type CanaryAnalysisStatus string
+
+const (
+	CANARY_ANALYSIS_STATUS_RUNNING   CanaryAnalysisStatus = "RUNNING"
+	CANARY_ANALYSIS_STATUS_PROMOTED  CanaryAnalysisStatus = "PROMOTED"
+	CANARY_ANALYSIS_STATUS_ABORTED   CanaryAnalysisStatus = "ABORTED"
+	CANARY_ANALYSIS_STATUS_CANCELLED CanaryAnalysisStatus = "CANCELLED"
+)
+
+// CanaryAnalysisPolicy is the opt-in canary analysis config of a cd pipeline deploying with CANARY strategy,
+// Steps is the json of analysis steps
+type CanaryAnalysisPolicy struct {
+	tableName                 struct{} `sql:"cd_pipeline_canary_analysis_policy" pg:",discard_unknown_columns"`
+	Id                        int      `sql:"id,pk"`
+	PipelineId                int      `sql:"pipeline_id,notnull"`
+	Enabled                   bool     `sql:"enabled,notnull"`
+	ErrorRateQuery            string   `sql:"error_rate_query"`
+	LatencyQuery              string   `sql:"latency_query"`
+	MaxErrorRateIncrease      float64  `sql:"max_error_rate_increase,notnull"`
+	MaxLatencyIncreasePercent float64  `sql:"max_latency_increase_percent,notnull"`
+	Steps                     string   `sql:"steps"`
+	sql.AuditLog
+}
+
+// CanaryAnalysisRun is the analysis of a single canary deployment, CurrentStep is the index of the step to be
+// analysed at NextAnalysisOn
+type CanaryAnalysisRun struct {
+	tableName          struct{}             `sql:"cd_pipeline_canary_analysis_run" pg:",discard_unknown_columns"`
+	Id                 int                  `sql:"id,pk"`
+	PipelineId         int                  `sql:"pipeline_id,notnull"`
+	CdWorkflowRunnerId int                  `sql:"cd_workflow_runner_id,notnull"`
+	CurrentStep        int                  `sql:"current_step,notnull"`
+	Status             CanaryAnalysisStatus `sql:"status,notnull"`
+	Message            string               `sql:"message"`
+	NextAnalysisOn     time.Time            `sql:"next_analysis_on"`
+	sql.AuditLog
+}
+
+type CanaryAnalysisRepository interface {
+	SavePolicy(policy *CanaryAnalysisPolicy) error
+	UpdatePolicy(policy *CanaryAnalysisPolicy) error
+	FindPolicyByPipelineId(pipelineId int) (*CanaryAnalysisPolicy, error)
+	FindAllEnabledPolicies() ([]*CanaryAnalysisPolicy, error)
+	SaveRun(run *CanaryAnalysisRun) error
+	UpdateRun(run *CanaryAnalysisRun) error
+	FindRunByCdWorkflowRunnerId(cdWorkflowRunnerId int) (*CanaryAnalysisRun, error)
+	FindAllRunningRuns() ([]*CanaryAnalysisRun, error)
+}
+
+func NewCanaryAnalysisRepositoryImpl(logger *zap.SugaredLogger,
+	dbConnection *pg.DB) *CanaryAnalysisRepositoryImpl {
+	return &CanaryAnalysisRepositoryImpl{
+		logger:       logger,
+		dbConnection: dbConnection,
+	}
+}
+
+type CanaryAnalysisRepositoryImpl struct {
+	logger       *zap.SugaredLogger
+	dbConnection *pg.DB
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) SavePolicy(policy *CanaryAnalysisPolicy) error {
+	err := impl.dbConnection.Insert(policy)
+	if err != nil {
+		impl.logger.Errorw("error in saving canary analysis policy", "err", err, "policy", policy)
+		return err
+	}
+	return nil
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) UpdatePolicy(policy *CanaryAnalysisPolicy) error {
+	err := impl.dbConnection.Update(policy)
+	if err != nil {
+		impl.logger.Errorw("error in updating canary analysis policy", "err", err, "policy", policy)
+		return err
+	}
+	return nil
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) FindPolicyByPipelineId(pipelineId int) (*CanaryAnalysisPolicy, error) {
+	policy := &CanaryAnalysisPolicy{}
+	err := impl.dbConnection.Model(policy).
+		Where("pipeline_id = ?", pipelineId).
+		Select()
+	if err != nil {
+		if err != pg.ErrNoRows {
+			impl.logger.Errorw("error in getting canary analysis policy by pipelineId", "err", err, "pipelineId", pipelineId)
+		}
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) FindAllEnabledPolicies() ([]*CanaryAnalysisPolicy, error) {
+	var policies []*CanaryAnalysisPolicy
+	err := impl.dbConnection.Model(&policies).
+		Where("enabled = ?", true).
+		Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting enabled canary analysis policies", "err", err)
+		return nil, err
+	}
+	return policies, nil
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) SaveRun(run *CanaryAnalysisRun) error {
+	err := impl.dbConnection.Insert(run)
+	if err != nil {
+		impl.logger.Errorw("error in saving canary analysis run", "err", err, "run", run)
+		return err
+	}
+	return nil
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) UpdateRun(run *CanaryAnalysisRun) error {
+	err := impl.dbConnection.Update(run)
+	if err != nil {
+		impl.logger.Errorw("error in updating canary analysis run", "err", err, "run", run)
+		return err
+	}
+	return nil
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) FindRunByCdWorkflowRunnerId(cdWorkflowRunnerId int) (*CanaryAnalysisRun, error) {
+	run := &CanaryAnalysisRun{}
+	err := impl.dbConnection.Model(run).
+		Where("cd_workflow_runner_id = ?", cdWorkflowRunnerId).
+		Select()
+	if err != nil {
+		if err != pg.ErrNoRows {
+			impl.logger.Errorw("error in getting canary analysis run by runnerId", "err", err, "cdWorkflowRunnerId", cdWorkflowRunnerId)
+		}
+		return nil, err
+	}
+	return run, nil
+}
+
+func (impl *CanaryAnalysisRepositoryImpl) FindAllRunningRuns() ([]*CanaryAnalysisRun, error) {
+	var runs []*CanaryAnalysisRun
+	err := impl.dbConnection.Model(&runs).
+		Where("status = ?", CANARY_ANALYSIS_STATUS_RUNNING).
+		Order("id ASC").
+		Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting running canary analysis runs", "err", err)
+		return nil, err
+	}
+	return runs, nil
+}
@@ -61,6 +61,10 @@ type PipelineStageStep struct {
 	RefPluginId         int              `sql:"ref_plugin_id"` //id of plugin used as reference
 	OutputDirectoryPath []string         `sql:"output_directory_path" pg:",array"`
 	DependentOnStep     string           `sql:"dependent_on_step"`
+	Timeout             int              `sql:"timeout,notnull"`
+	RetryCount          int              `sql:"retry_count,notnull"`
+	RetryBackoff        int              `sql:"retry_backoff,notnull"`
+	ContinueOnError     bool             `sql:"continue_on_error,notnull"`
 	Deleted             bool             `sql:"deleted,notnull"`
 	sql.AuditLog
 }
@@ -142,6 +146,10 @@ type PipelineStageRepository interface {
 	GetAllCiStagesByCiPipelineId(ciPipelineId int) ([]*PipelineStage, error)
 	GetCiStageByCiPipelineIdAndStageType(ciPipelineId int, stageType PipelineStageType) (*PipelineStage, error)
 
+	GetAllCdStagesByCdPipelineId(cdPipelineId int) ([]*PipelineStage, error)
+	GetCdStageByCdPipelineIdAndStageType(cdPipelineId int, stageType PipelineStageType) (*PipelineStage, error)
+	GetCdStagesWithStepsByCdPipelineIds(cdPipelineIds []int) ([]*PipelineStage, error)
+
 	GetStepIdsByStageId(stageId int) ([]int, error)
 	CreatePipelineStageStep(step *PipelineStageStep) (*PipelineStageStep, error)
 	UpdatePipelineStageStep(step *PipelineStageStep) (*PipelineStageStep, error)
@@ -229,6 +237,49 @@ func (impl *PipelineStageRepositoryImpl) GetCiStageByCiPipelineIdAndStageType(ci
 	return &pipelineStage, nil
 }
 
+func (impl *PipelineStageRepositoryImpl) GetAllCdStagesByCdPipelineId(cdPipelineId int) ([]*PipelineStage, error) {
+	var pipelineStages []*PipelineStage
+	err := impl.dbConnection.Model(&pipelineStages).
+		Where("cd_pipeline_id = ?", cdPipelineId).
+		Where("deleted = ?", false).Select()
+	if err != nil {
+		impl.logger.Errorw("err in getting all cd stages by cdPipelineId", "err", err, "cdPipelineId", cdPipelineId)
+		return nil, err
+	}
+	return pipelineStages, nil
+}
+
+func (impl *PipelineStageRepositoryImpl) GetCdStageByCdPipelineIdAndStageType(cdPipelineId int, stageType PipelineStageType) (*PipelineStage, error) {
+	var pipelineStage PipelineStage
+	err := impl.dbConnection.Model(&pipelineStage).
+		Where("cd_pipeline_id = ?", cdPipelineId).
+		Where("type = ?", stageType).
+		Where("deleted = ?", false).Select()
+	if err != nil {
+		impl.logger.Errorw("err in getting cd stage by cdPipelineId", "err", err, "cdPipelineId", cdPipelineId)
+		return nil, err
+	}
+	return &pipelineStage, nil
+}
+
+// GetCdStagesWithStepsByCdPipelineIds returns plugin based cd stages of pipelines which have at least one active step
+func (impl *PipelineStageRepositoryImpl) GetCdStagesWithStepsByCdPipelineIds(cdPipelineIds []int) ([]*PipelineStage, error) {
+	var pipelineStages []*PipelineStage
+	if len(cdPipelineIds) == 0 {
+		return pipelineStages, nil
+	}
+	err := impl.dbConnection.Model(&pipelineStages).
+		Where("cd_pipeline_id in (?)", pg.In(cdPipelineIds)).
+		Where("deleted = ?", false).
+		Where("exists (select 1 from pipeline_stage_step pss where pss.pipeline_stage_id = pipeline_stage.id and pss.deleted = false)").
+		Select()
+	if err != nil {
+		impl.logger.Errorw("err in getting cd stages with steps by cdPipelineIds", "err", err, "cdPipelineIds", cdPipelineIds)
+		return nil, err
+	}
+	return pipelineStages, nil
+}
+
 func (impl *PipelineStageRepositoryImpl) CreateCiStage(ciStage *PipelineStage) (*PipelineStage, error) {
 	err := impl.dbConnection.Insert(ciStage)
 	if err != nil {
@@ -157,6 +157,7 @@ type PluginStepCondition struct {
 type GlobalPluginRepository interface {
 	GetMetaDataForAllPlugins() ([]*PluginMetadata, error)
 	GetMetaDataByPluginId(pluginId int) (*PluginMetadata, error)
+	GetMetaDataByName(pluginName string) (*PluginMetadata, error)
 	GetAllPluginTags() ([]*PluginTag, error)
 	GetAllPluginTagRelations() ([]*PluginTagRelation, error)
 	GetTagsByPluginId(pluginId int) ([]string, error)
@@ -237,6 +238,17 @@ func (impl *GlobalPluginRepositoryImpl) GetMetaDataByPluginId(pluginId int) (*Pl
 	return &plugin, nil
 }
 
+func (impl *GlobalPluginRepositoryImpl) GetMetaDataByName(pluginName string) (*PluginMetadata, error) {
+	var plugin PluginMetadata
+	err := impl.dbConnection.Model(&plugin).Where("name = ?", pluginName).
+		Where("deleted = ?", false).Select()
+	if err != nil {
+		impl.logger.Errorw("err in getting plugin by name", "err", err, "pluginName", pluginName)
+		return nil, err
+	}
+	return &plugin, nil
+}
+
 func (impl *GlobalPluginRepositoryImpl) GetStepsByPluginIds(pluginIds []int) ([]*PluginStep, error) {
 	var pluginSteps []*PluginStep
 	err := impl.dbConnection.Model(&pluginSteps).
@@ -0,0 +1,140 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package sbom
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+)
+
+type SbomFormat string
+
+const (
+	SBOM_FORMAT_CYCLONEDX SbomFormat = "CYCLONEDX"
+	SBOM_FORMAT_SPDX      SbomFormat = "SPDX"
+)
+
+// CiArtifactSbom is the software bill of materials of an image, saved once per image digest
+type CiArtifactSbom struct {
+	tableName    struct{}   `sql:"ci_artifact_sbom" pg:",discard_unknown_columns"`
+	Id           int        `sql:"id,pk"`
+	CiArtifactId int        `sql:"ci_artifact_id,notnull"`
+	ImageDigest  string     `sql:"image_digest,notnull"`
+	Format       SbomFormat `sql:"format,notnull"`
+	SpecVersion  string     `sql:"spec_version"`
+	Content      string     `sql:"content,notnull"`
+	sql.AuditLog
+}
+
+// CiArtifactSbomComponent is a package listed in a sbom, kept separately to query artifacts by package
+type CiArtifactSbomComponent struct {
+	tableName struct{} `sql:"ci_artifact_sbom_component" pg:",discard_unknown_columns"`
+	Id        int      `sql:"id,pk"`
+	SbomId    int      `sql:"sbom_id,notnull"`
+	Name      string   `sql:"name,notnull"`
+	Version   string   `sql:"version"`
+	Purl      string   `sql:"purl"`
+	Type      string   `sql:"type"`
+	License   string   `sql:"license"`
+}
+
+// DeployedPackageArtifact is an artifact containing a package along with where it is deployed
+type DeployedPackageArtifact struct {
+	CiArtifactId    int    `sql:"ci_artifact_id" json:"ciArtifactId"`
+	Image           string `sql:"image" json:"image"`
+	ImageDigest     string `sql:"image_digest" json:"imageDigest"`
+	Package         string `sql:"package" json:"package"`
+	Version         string `sql:"version" json:"version"`
+	Purl            string `sql:"purl" json:"purl,omitempty"`
+	ObjectType      string `sql:"object_type" json:"objectType"`
+	AppId           int    `sql:"app_id" json:"appId,omitempty"`
+	AppName         string `sql:"app_name" json:"appName,omitempty"`
+	EnvironmentId   int    `sql:"env_id" json:"environmentId,omitempty"`
+	EnvironmentName string `sql:"environment_name" json:"environmentName,omitempty"`
+	ClusterId       int    `sql:"cluster_id" json:"clusterId"`
+	ClusterName     string `sql:"cluster_name" json:"clusterName"`
+}
+
+type SbomRepository interface {
+	Save(sbom *CiArtifactSbom, components []*CiArtifactSbomComponent) error
+	FindByImageDigest(imageDigest string) (*CiArtifactSbom, error)
+	FindDeployedArtifactsByPackage(name string, version string) ([]*DeployedPackageArtifact, error)
+	FindComponentsByImageDigest(imageDigest string) ([]*CiArtifactSbomComponent, error)
+}
+
+type SbomRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewSbomRepositoryImpl(dbConnection *pg.DB) *SbomRepositoryImpl {
+	return &SbomRepositoryImpl{dbConnection: dbConnection}
+}
+
+func (impl SbomRepositoryImpl) Save(sbom *CiArtifactSbom, components []*CiArtifactSbomComponent) error {
+	return impl.dbConnection.RunInTransaction(func(tx *pg.Tx) error {
+		err := tx.Insert(sbom)
+		if err != nil {
+			return err
+		}
+		for _, component := range components {
+			component.SbomId = sbom.Id
+		}
+		if len(components) > 0 {
+			_, err = tx.Model(&components).Insert()
+		}
+		return err
+	})
+}
+
+func (impl SbomRepositoryImpl) FindByImageDigest(imageDigest string) (*CiArtifactSbom, error) {
+	sbom := &CiArtifactSbom{}
+	err := impl.dbConnection.Model(sbom).
+		Where("image_digest = ?", imageDigest).
+		Order("id DESC").Limit(1).
+		Select()
+	return sbom, err
+}
+
+// FindDeployedArtifactsByPackage returns deployed artifacts whose sbom lists the package, any version matches if
+// version is empty
+func (impl SbomRepositoryImpl) FindDeployedArtifactsByPackage(name string, version string) ([]*DeployedPackageArtifact, error) {
+	var artifacts []*DeployedPackageArtifact
+	query := "SELECT DISTINCT s.ci_artifact_id, ca.image, s.image_digest, c.name AS package, c.version, c.purl," +
+		" info.object_type, a.id AS app_id, a.app_name, info.env_id, e.environment_name, info.cluster_id, cl.cluster_name" +
+		" FROM ci_artifact_sbom_component c" +
+		" INNER JOIN ci_artifact_sbom s ON s.id = c.sbom_id" +
+		" INNER JOIN ci_artifact ca ON ca.id = s.ci_artifact_id" +
+		" INNER JOIN image_scan_execution_history his ON his.image_hash = s.image_digest" +
+		" INNER JOIN image_scan_deploy_info info ON his.id = ANY (info.image_scan_execution_history_id)" +
+		" LEFT JOIN app a ON a.id = info.scan_object_meta_id AND info.object_type IN ('app', 'chart')" +
+		" LEFT JOIN environment e ON e.id = info.env_id" +
+		" LEFT JOIN cluster cl ON cl.id = info.cluster_id" +
+		" WHERE c.name = ? AND (? = '' OR c.version = ?)" +
+		" ORDER BY s.ci_artifact_id DESC"
+	_, err := impl.dbConnection.Query(&artifacts, query, name, version, version)
+	return artifacts, err
+}
+
+// FindComponentsByImageDigest returns the packages listed in the latest sbom of an image digest
+func (impl SbomRepositoryImpl) FindComponentsByImageDigest(imageDigest string) ([]*CiArtifactSbomComponent, error) {
+	var components []*CiArtifactSbomComponent
+	query := "SELECT c.* FROM ci_artifact_sbom_component c" +
+		" WHERE c.sbom_id = (SELECT s.id FROM ci_artifact_sbom s WHERE s.image_digest = ? ORDER BY s.id DESC LIMIT 1)" +
+		" ORDER BY c.id ASC"
+	_, err := impl.dbConnection.Query(&components, query, imageDigest)
+	return components, err
+}
@@ -0,0 +1,298 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package sbom
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/security"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type SbomService interface {
+	SaveSbom(ciArtifact *repository.CiArtifact, content json.RawMessage, userId int32) error
+	GetSbom(ciArtifactId int) (*SbomDto, error)
+	FindDeployedArtifactsByPackage(name string, version string) ([]*DeployedPackageArtifact, error)
+}
+
+type SbomServiceImpl struct {
+	logger               *zap.SugaredLogger
+	sbomRepository       SbomRepository
+	ciArtifactRepository repository.CiArtifactRepository
+	ciPipelineRepository pipelineConfig.CiPipelineRepository
+}
+
+type SbomDto struct {
+	CiArtifactId int             `json:"ciArtifactId"`
+	AppId        int             `json:"appId"`
+	ImageDigest  string          `json:"imageDigest"`
+	Format       SbomFormat      `json:"format"`
+	SpecVersion  string          `json:"specVersion"`
+	Content      json.RawMessage `json:"content"`
+}
+
+// ParsedSbom is the format and the flattened package list of a CycloneDX or SPDX json document
+type ParsedSbom struct {
+	Format      SbomFormat
+	SpecVersion string
+	Components  []*CiArtifactSbomComponent
+}
+
+type cycloneDxComponent struct {
+	Type       string                `json:"type"`
+	Name       string                `json:"name"`
+	Version    string                `json:"version"`
+	Purl       string                `json:"purl"`
+	Licenses   []*cycloneDxLicense   `json:"licenses"`
+	Components []*cycloneDxComponent `json:"components"`
+}
+
+type cycloneDxLicense struct {
+	License *struct {
+		Id   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"license"`
+	Expression string `json:"expression"`
+}
+
+type cycloneDxDocument struct {
+	BomFormat   string                `json:"bomFormat"`
+	SpecVersion string                `json:"specVersion"`
+	Components  []*cycloneDxComponent `json:"components"`
+}
+
+type spdxDocument struct {
+	SpdxVersion string `json:"spdxVersion"`
+	Packages    []struct {
+		Name             string `json:"name"`
+		VersionInfo      string `json:"versionInfo"`
+		LicenseConcluded string `json:"licenseConcluded"`
+		LicenseDeclared  string `json:"licenseDeclared"`
+		ExternalRefs     []struct {
+			ReferenceType    string `json:"referenceType"`
+			ReferenceLocator string `json:"referenceLocator"`
+		} `json:"externalRefs"`
+	} `json:"packages"`
+}
+
+func NewSbomServiceImpl(logger *zap.SugaredLogger, sbomRepository SbomRepository,
+	ciArtifactRepository repository.CiArtifactRepository,
+	ciPipelineRepository pipelineConfig.CiPipelineRepository) *SbomServiceImpl {
+	return &SbomServiceImpl{
+		logger:               logger,
+		sbomRepository:       sbomRepository,
+		ciArtifactRepository: ciArtifactRepository,
+		ciPipelineRepository: ciPipelineRepository,
+	}
+}
+
+// VerifySbomDigest checks content against its sha256 digest, digest may be prefixed with "sha256:"
+func VerifySbomDigest(content []byte, digest string) error {
+	sum := sha256.Sum256(content)
+	if hex.EncodeToString(sum[:]) != strings.TrimPrefix(strings.ToLower(digest), "sha256:") {
+		return fmt.Errorf("sbom digest mismatch, expected %s", digest)
+	}
+	return nil
+}
+
+// ParseSbom detects whether content is a CycloneDX or SPDX json document and lists its packages
+func ParseSbom(content []byte) (*ParsedSbom, error) {
+	var cycloneDx cycloneDxDocument
+	err := json.Unmarshal(content, &cycloneDx)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sbom json: %s", err.Error())
+	}
+	if cycloneDx.BomFormat == "CycloneDX" {
+		parsedSbom := &ParsedSbom{Format: SBOM_FORMAT_CYCLONEDX, SpecVersion: cycloneDx.SpecVersion}
+		var addComponents func(components []*cycloneDxComponent)
+		addComponents = func(components []*cycloneDxComponent) {
+			for _, component := range components {
+				if len(component.Name) > 0 {
+					parsedSbom.Components = append(parsedSbom.Components, &CiArtifactSbomComponent{
+						Name:    component.Name,
+						Version: component.Version,
+						Purl:    component.Purl,
+						Type:    component.Type,
+						License: cycloneDxLicenseExpression(component.Licenses),
+					})
+				}
+				addComponents(component.Components)
+			}
+		}
+		addComponents(cycloneDx.Components)
+		return parsedSbom, nil
+	}
+	var spdx spdxDocument
+	err = json.Unmarshal(content, &spdx)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sbom json: %s", err.Error())
+	}
+	if strings.HasPrefix(spdx.SpdxVersion, "SPDX-") {
+		parsedSbom := &ParsedSbom{Format: SBOM_FORMAT_SPDX, SpecVersion: strings.TrimPrefix(spdx.SpdxVersion, "SPDX-")}
+		for _, pkg := range spdx.Packages {
+			component := &CiArtifactSbomComponent{Name: pkg.Name, Version: pkg.VersionInfo, License: spdxLicenseExpression(pkg.LicenseConcluded)}
+			if len(component.License) == 0 {
+				component.License = spdxLicenseExpression(pkg.LicenseDeclared)
+			}
+			for _, ref := range pkg.ExternalRefs {
+				if ref.ReferenceType == "purl" {
+					component.Purl = ref.ReferenceLocator
+				}
+			}
+			parsedSbom.Components = append(parsedSbom.Components, component)
+		}
+		return parsedSbom, nil
+	}
+	return nil, fmt.Errorf("unsupported sbom format, only CycloneDX and SPDX json are supported")
+}
+
+// cycloneDxLicenseExpression joins the licenses of a component into a single spdx expression
+func cycloneDxLicenseExpression(licenses []*cycloneDxLicense) string {
+	var expressions []string
+	for _, license := range licenses {
+		if len(license.Expression) > 0 {
+			expressions = append(expressions, license.Expression)
+		} else if license.License != nil && len(license.License.Id) > 0 {
+			expressions = append(expressions, license.License.Id)
+		} else if license.License != nil && len(license.License.Name) > 0 {
+			expressions = append(expressions, license.License.Name)
+		}
+	}
+	if len(expressions) > 1 {
+		for i, expression := range expressions {
+			if strings.Contains(expression, " ") {
+				expressions[i] = "(" + expression + ")"
+			}
+		}
+	}
+	return strings.Join(expressions, " AND ")
+}
+
+// spdxLicenseExpression drops the NOASSERTION and NONE placeholders of spdx documents
+func spdxLicenseExpression(license string) string {
+	license = strings.TrimSpace(license)
+	if license == "NOASSERTION" || license == "NONE" {
+		return ""
+	}
+	return license
+}
+
+// GetPackageLicenses lists the licenses of sbom components for license policy enforcement
+func GetPackageLicenses(components []*CiArtifactSbomComponent) []*security.PackageLicense {
+	var packages []*security.PackageLicense
+	for _, component := range components {
+		packages = append(packages, &security.PackageLicense{Package: component.Name, Version: component.Version, License: component.License})
+	}
+	return packages
+}
+
+// SaveSbom saves the sbom of an artifact unless the image digest already has one
+func (impl SbomServiceImpl) SaveSbom(ciArtifact *repository.CiArtifact, content json.RawMessage, userId int32) error {
+	if len(content) == 0 {
+		return nil
+	}
+	if len(ciArtifact.ImageDigest) == 0 {
+		return fmt.Errorf("sbom can not be saved for artifact without image digest")
+	}
+	existingSbom, err := impl.sbomRepository.FindByImageDigest(ciArtifact.ImageDigest)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching sbom", "err", err, "imageDigest", ciArtifact.ImageDigest)
+		return err
+	}
+	if err == nil && existingSbom.Id > 0 {
+		impl.logger.Infow("sbom already exists for image digest", "imageDigest", ciArtifact.ImageDigest, "ciArtifactId", existingSbom.CiArtifactId)
+		return nil
+	}
+	parsedSbom, err := ParseSbom(content)
+	if err != nil {
+		impl.logger.Errorw("error in parsing sbom", "err", err, "ciArtifactId", ciArtifact.Id)
+		return err
+	}
+	sbom := &CiArtifactSbom{
+		CiArtifactId: ciArtifact.Id,
+		ImageDigest:  ciArtifact.ImageDigest,
+		Format:       parsedSbom.Format,
+		SpecVersion:  parsedSbom.SpecVersion,
+		Content:      string(content),
+		AuditLog:     sql.AuditLog{CreatedOn: time.Now(), CreatedBy: userId, UpdatedOn: time.Now(), UpdatedBy: userId},
+	}
+	err = impl.sbomRepository.Save(sbom, parsedSbom.Components)
+	if err != nil {
+		impl.logger.Errorw("error in saving sbom", "err", err, "ciArtifactId", ciArtifact.Id)
+		return err
+	}
+	return nil
+}
+
+// GetSbom returns the sbom of the image digest of an artifact, artifacts of linked ci pipelines share the sbom
+func (impl SbomServiceImpl) GetSbom(ciArtifactId int) (*SbomDto, error) {
+	ciArtifact, err := impl.ciArtifactRepository.Get(ciArtifactId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching ci artifact", "err", err, "ciArtifactId", ciArtifactId)
+		return nil, err
+	}
+	ciPipeline, err := impl.ciPipelineRepository.FindById(ciArtifact.PipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching ci pipeline", "err", err, "ciPipelineId", ciArtifact.PipelineId)
+		return nil, err
+	}
+	sbom, err := impl.sbomRepository.FindByImageDigest(ciArtifact.ImageDigest)
+	if err == pg.ErrNoRows || len(ciArtifact.ImageDigest) == 0 {
+		return nil, &util.ApiError{
+			HttpStatusCode:  http.StatusNotFound,
+			InternalMessage: "sbom not found",
+			UserMessage:     fmt.Sprintf("sbom not found for artifact %d", ciArtifactId),
+		}
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching sbom", "err", err, "ciArtifactId", ciArtifactId)
+		return nil, err
+	}
+	return &SbomDto{
+		CiArtifactId: ciArtifact.Id,
+		AppId:        ciPipeline.AppId,
+		ImageDigest:  sbom.ImageDigest,
+		Format:       sbom.Format,
+		SpecVersion:  sbom.SpecVersion,
+		Content:      json.RawMessage(sbom.Content),
+	}, nil
+}
+
+func (impl SbomServiceImpl) FindDeployedArtifactsByPackage(name string, version string) ([]*DeployedPackageArtifact, error) {
+	if len(strings.TrimSpace(name)) == 0 {
+		return nil, &util.ApiError{
+			HttpStatusCode:  http.StatusBadRequest,
+			InternalMessage: "package name is missing",
+			UserMessage:     "package name is required",
+		}
+	}
+	artifacts, err := impl.sbomRepository.FindDeployedArtifactsByPackage(strings.TrimSpace(name), strings.TrimSpace(version))
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployed artifacts by package", "err", err, "package", name, "version", version)
+		return nil, err
+	}
+	return artifacts, nil
+}
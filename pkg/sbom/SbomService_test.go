@@ -0,0 +1,113 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+package sbom
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSbom(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    *ParsedSbom
+		wantErr bool
+	}{
+		{
+			name: "cyclonedx with nested components",
+			content: `{"bomFormat":"CycloneDX","specVersion":"1.4","components":[
+				{"type":"library","name":"openssl","version":"1.1.1k","purl":"pkg:deb/debian/openssl@1.1.1k",
+				"components":[{"type":"library","name":"libssl","version":"1.1.1k"}]}]}`,
+			want: &ParsedSbom{Format: SBOM_FORMAT_CYCLONEDX, SpecVersion: "1.4", Components: []*CiArtifactSbomComponent{
+				{Name: "openssl", Version: "1.1.1k", Purl: "pkg:deb/debian/openssl@1.1.1k", Type: "library"},
+				{Name: "libssl", Version: "1.1.1k", Type: "library"},
+			}},
+		},
+		{
+			name: "spdx with purl",
+			content: `{"spdxVersion":"SPDX-2.3","packages":[{"name":"zlib","versionInfo":"1.2.11",
+				"externalRefs":[{"referenceCategory":"PACKAGE-MANAGER","referenceType":"purl","referenceLocator":"pkg:apk/alpine/zlib@1.2.11"}]}]}`,
+			want: &ParsedSbom{Format: SBOM_FORMAT_SPDX, SpecVersion: "2.3", Components: []*CiArtifactSbomComponent{
+				{Name: "zlib", Version: "1.2.11", Purl: "pkg:apk/alpine/zlib@1.2.11"},
+			}},
+		},
+		{
+			name: "cyclonedx licenses",
+			content: `{"bomFormat":"CycloneDX","specVersion":"1.4","components":[
+				{"type":"library","name":"mysql-connector","version":"8.0.30","licenses":[{"license":{"id":"GPL-2.0-only"}},{"expression":"MIT OR Apache-2.0"}]},
+				{"type":"library","name":"internal-lib","version":"1.0","licenses":[{"license":{"name":"Proprietary"}}]}]}`,
+			want: &ParsedSbom{Format: SBOM_FORMAT_CYCLONEDX, SpecVersion: "1.4", Components: []*CiArtifactSbomComponent{
+				{Name: "mysql-connector", Version: "8.0.30", Type: "library", License: "GPL-2.0-only AND (MIT OR Apache-2.0)"},
+				{Name: "internal-lib", Version: "1.0", Type: "library", License: "Proprietary"},
+			}},
+		},
+		{
+			name: "spdx licenses",
+			content: `{"spdxVersion":"SPDX-2.3","packages":[{"name":"busybox","versionInfo":"1.35.0","licenseConcluded":"GPL-2.0-only"},
+				{"name":"musl","versionInfo":"1.2.3","licenseConcluded":"NOASSERTION","licenseDeclared":"MIT"},
+				{"name":"unknown","versionInfo":"1.0","licenseConcluded":"NOASSERTION","licenseDeclared":"NONE"}]}`,
+			want: &ParsedSbom{Format: SBOM_FORMAT_SPDX, SpecVersion: "2.3", Components: []*CiArtifactSbomComponent{
+				{Name: "busybox", Version: "1.35.0", License: "GPL-2.0-only"},
+				{Name: "musl", Version: "1.2.3", License: "MIT"},
+				{Name: "unknown", Version: "1.0"},
+			}},
+		},
+		{name: "unsupported format", content: `{"name":"syft"}`, wantErr: true},
+		{name: "invalid json", content: `{"bomFormat":`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSbom([]byte(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseSbom() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSbom() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifySbomDigest(t *testing.T) {
+	content := []byte(`{"bomFormat":"CycloneDX"}`)
+	tests := []struct {
+		name    string
+		digest  string
+		wantErr bool
+	}{
+		{name: "matching digest", digest: sha256Hex(content)},
+		{name: "prefixed digest", digest: "sha256:" + sha256Hex(content)},
+		{name: "mismatched digest", digest: strings.Repeat("0", 64), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := VerifySbomDigest(content, tt.digest); (err != nil) != tt.wantErr {
+				t.Errorf("VerifySbomDigest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func sha256Hex(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,160 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package secretScan
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+)
+
+// SecretScanFinding is a secret found in git materials by the pre build scan of a ci workflow
+type SecretScanFinding struct {
+	tableName     struct{} `sql:"ci_workflow_secret_scan_finding" pg:",discard_unknown_columns"`
+	Id            int      `sql:"id,pk"`
+	CiWorkflowId  int      `sql:"ci_workflow_id,notnull"`
+	GitMaterialId int      `sql:"git_material_id,notnull"`
+	RuleId        string   `sql:"rule_id,notnull"`
+	FilePath      string   `sql:"file_path,notnull"`
+	Line          int      `sql:"line"`
+	RedactedMatch string   `sql:"redacted_match"`
+	Fingerprint   string   `sql:"fingerprint,notnull"`
+	Action        string   `sql:"action,notnull"`
+	sql.AuditLog
+}
+
+// SecretScanAllowList is an allow list entry of a git material
+type SecretScanAllowList struct {
+	tableName     struct{} `sql:"secret_scan_allow_list" pg:",discard_unknown_columns"`
+	Id            int      `sql:"id,pk"`
+	GitMaterialId int      `sql:"git_material_id,notnull"`
+	RuleId        string   `sql:"rule_id"`
+	PathPattern   string   `sql:"path_pattern"`
+	Fingerprint   string   `sql:"fingerprint"`
+	Reason        string   `sql:"reason"`
+	Deleted       bool     `sql:"deleted,notnull"`
+	sql.AuditLog
+}
+
+// CiWorkflowSecretScan tracks the scan requested from ci runner for a ci workflow until its result is saved
+type CiWorkflowSecretScan struct {
+	tableName    struct{} `sql:"ci_workflow_secret_scan" pg:",discard_unknown_columns"`
+	Id           int      `sql:"id,pk"`
+	CiWorkflowId int      `sql:"ci_workflow_id,notnull"`
+	Status       string   `sql:"status,notnull"`
+	Blocked      bool     `sql:"blocked,notnull"`
+	sql.AuditLog
+}
+
+type SecretScanRepository interface {
+	SaveScan(scan *CiWorkflowSecretScan) error
+	FindScanByCiWorkflowId(ciWorkflowId int) (*CiWorkflowSecretScan, error)
+	SaveFindings(ciWorkflowId int, findings []*SecretScanFinding, scan *CiWorkflowSecretScan) error
+	FindFindingsByCiWorkflowId(ciWorkflowId int) ([]*SecretScanFinding, error)
+	SaveAllowListEntry(entry *SecretScanAllowList) error
+	UpdateAllowListEntry(entry *SecretScanAllowList) error
+	FindAllowListEntryById(id int) (*SecretScanAllowList, error)
+	FindAllowListByGitMaterialIds(gitMaterialIds []int) ([]*SecretScanAllowList, error)
+}
+
+type SecretScanRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewSecretScanRepositoryImpl(dbConnection *pg.DB) *SecretScanRepositoryImpl {
+	return &SecretScanRepositoryImpl{dbConnection: dbConnection}
+}
+
+func (impl SecretScanRepositoryImpl) SaveScan(scan *CiWorkflowSecretScan) error {
+	return impl.dbConnection.Insert(scan)
+}
+
+func (impl SecretScanRepositoryImpl) FindScanByCiWorkflowId(ciWorkflowId int) (*CiWorkflowSecretScan, error) {
+	scan := &CiWorkflowSecretScan{}
+	err := impl.dbConnection.Model(scan).
+		Where("ci_workflow_id = ?", ciWorkflowId).
+		Select()
+	return scan, err
+}
+
+// SaveFindings replaces findings recorded for a ci workflow and marks its scan completed in the same transaction, so
+// that findings are complete once the scan is seen completed
+func (impl SecretScanRepositoryImpl) SaveFindings(ciWorkflowId int, findings []*SecretScanFinding, scan *CiWorkflowSecretScan) error {
+	return impl.dbConnection.RunInTransaction(func(tx *pg.Tx) error {
+		_, err := tx.Model((*SecretScanFinding)(nil)).Where("ci_workflow_id = ?", ciWorkflowId).Delete()
+		if err != nil {
+			return err
+		}
+		for _, finding := range findings {
+			err = tx.Insert(finding)
+			if err != nil {
+				return err
+			}
+		}
+		existingScan := &CiWorkflowSecretScan{}
+		err = tx.Model(existingScan).Where("ci_workflow_id = ?", ciWorkflowId).Select()
+		if err == pg.ErrNoRows {
+			return tx.Insert(scan)
+		} else if err != nil {
+			return err
+		}
+		existingScan.Status = scan.Status
+		existingScan.Blocked = scan.Blocked
+		existingScan.UpdatedOn = scan.UpdatedOn
+		existingScan.UpdatedBy = scan.UpdatedBy
+		return tx.Update(existingScan)
+	})
+}
+
+func (impl SecretScanRepositoryImpl) FindFindingsByCiWorkflowId(ciWorkflowId int) ([]*SecretScanFinding, error) {
+	var findings []*SecretScanFinding
+	err := impl.dbConnection.Model(&findings).
+		Where("ci_workflow_id = ?", ciWorkflowId).
+		Order("id ASC").
+		Select()
+	return findings, err
+}
+
+func (impl SecretScanRepositoryImpl) SaveAllowListEntry(entry *SecretScanAllowList) error {
+	return impl.dbConnection.Insert(entry)
+}
+
+func (impl SecretScanRepositoryImpl) UpdateAllowListEntry(entry *SecretScanAllowList) error {
+	return impl.dbConnection.Update(entry)
+}
+
+func (impl SecretScanRepositoryImpl) FindAllowListEntryById(id int) (*SecretScanAllowList, error) {
+	entry := &SecretScanAllowList{}
+	err := impl.dbConnection.Model(entry).
+		Where("id = ?", id).
+		Where("deleted = ?", false).
+		Select()
+	return entry, err
+}
+
+func (impl SecretScanRepositoryImpl) FindAllowListByGitMaterialIds(gitMaterialIds []int) ([]*SecretScanAllowList, error) {
+	var entries []*SecretScanAllowList
+	if len(gitMaterialIds) == 0 {
+		return entries, nil
+	}
+	err := impl.dbConnection.Model(&entries).
+		Where("git_material_id in (?)", pg.In(gitMaterialIds)).
+		Where("deleted = ?", false).
+		Order("id ASC").
+		Select()
+	return entries, err
+}
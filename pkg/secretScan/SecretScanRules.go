@@ -0,0 +1,175 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package secretScan
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+const (
+	RULE_AWS_ACCESS_KEY              = "AWS_ACCESS_KEY"
+	RULE_AWS_SECRET_KEY              = "AWS_SECRET_KEY"
+	RULE_GCP_API_KEY                 = "GCP_API_KEY"
+	RULE_GCP_SERVICE_ACCOUNT_KEY     = "GCP_SERVICE_ACCOUNT_KEY"
+	RULE_PRIVATE_KEY                 = "PRIVATE_KEY"
+	RULE_GITHUB_TOKEN                = "GITHUB_TOKEN"
+	RULE_GITLAB_TOKEN                = "GITLAB_TOKEN"
+	RULE_SLACK_TOKEN                 = "SLACK_TOKEN"
+	RULE_GENERIC_HIGH_ENTROPY_SECRET = "GENERIC_HIGH_ENTROPY_SECRET"
+	SECRET_SCAN_ACTION_ALLOW         = "allow"
+	SECRET_SCAN_ACTION_WARN          = "warn"
+	SECRET_SCAN_ACTION_BLOCK         = "block"
+	// SECRET_SCAN_PLUGIN_NAME is the preset plugin which runs the scan when added to pre build stage
+	SECRET_SCAN_PLUGIN_NAME = "Secret Leak Scan"
+	// SECRET_SCAN_FAIL_ON_FINDINGS_VARIABLE is the input variable of the plugin step which fails the build on findings
+	SECRET_SCAN_FAIL_ON_FINDINGS_VARIABLE = "FailOnFindings"
+	// SECRET_SCAN_RESULT_PATH is where ci runner writes the scan event, the plugin step fails when it is missing
+	SECRET_SCAN_RESULT_PATH = "/devtroncd/secret-scan/result.json"
+	// SECRET_SCAN_STATUS_REQUESTED is the status of a scan sent to ci runner until its result is saved
+	SECRET_SCAN_STATUS_REQUESTED = "REQUESTED"
+	SECRET_SCAN_STATUS_COMPLETED = "COMPLETED"
+)
+
+// SecretScanRule is a built-in detection rule sent to ci runner. Regex is in RE2 syntax, its first capture group is the
+// secret when present and the whole match otherwise. Secrets whose shannon entropy is below Entropy are not reported
+type SecretScanRule struct {
+	Id          string  `json:"id"`
+	Description string  `json:"description"`
+	Regex       string  `json:"regex"`
+	Entropy     float64 `json:"entropy,omitempty"`
+}
+
+var SecretScanRules = []*SecretScanRule{
+	{Id: RULE_AWS_ACCESS_KEY, Description: "AWS access key id", Regex: `\b((?:AKIA|ASIA|AGPA|AIDA|AROA|ANPA|ANVA)[A-Z0-9]{16})\b`},
+	{Id: RULE_AWS_SECRET_KEY, Description: "AWS secret access key", Regex: `(?i)aws[A-Za-z0-9_.-]{0,20}(?:secret|private)[A-Za-z0-9_.-]{0,20}\s*[:=]\s*['"]?([A-Za-z0-9/+=]{40})\b`, Entropy: 4},
+	{Id: RULE_GCP_API_KEY, Description: "Google cloud api key", Regex: `\b(AIza[0-9A-Za-z_\-]{35})\b`},
+	{Id: RULE_GCP_SERVICE_ACCOUNT_KEY, Description: "Google cloud service account key file", Regex: `"private_key_id"\s*:\s*"([a-f0-9]{40})"`},
+	{Id: RULE_PRIVATE_KEY, Description: "Private key", Regex: `-----BEGIN (?:RSA |DSA |EC |OPENSSH |PGP |ENCRYPTED )?PRIVATE KEY(?: BLOCK)?-----`},
+	{Id: RULE_GITHUB_TOKEN, Description: "GitHub personal access or app token", Regex: `\b((?:ghp|gho|ghu|ghs|ghr)_[A-Za-z0-9]{36})\b`},
+	{Id: RULE_GITLAB_TOKEN, Description: "GitLab personal access token", Regex: `\b(glpat-[A-Za-z0-9_\-]{20})\b`},
+	{Id: RULE_SLACK_TOKEN, Description: "Slack token", Regex: `\b(xox[baprs]-[A-Za-z0-9-]{10,48})\b`},
+	{Id: RULE_GENERIC_HIGH_ENTROPY_SECRET, Description: "High entropy value assigned to a secret, token, password or key", Regex: `(?i)(?:secret|token|passwd|password|api[_-]?key|access[_-]?key|auth)[A-Za-z0-9_.-]{0,20}['"]?\s*[:=]\s*['"]?([A-Za-z0-9/+=_.\-]{16,})`, Entropy: 4.5},
+}
+
+// GetSecretScanRule returns the built-in rule of an id, nil is returned for unknown rules
+func GetSecretScanRule(ruleId string) *SecretScanRule {
+	for _, rule := range SecretScanRules {
+		if rule.Id == ruleId {
+			return rule
+		}
+	}
+	return nil
+}
+
+// SecretFinding is a secret found by ci runner in a git material. File is relative to checkout path of the material,
+// Match is redacted by ci runner and Fingerprint is the sha256 of rule, file and secret which identifies the secret
+// across commits
+type SecretFinding struct {
+	GitMaterialId int    `json:"gitMaterialId"`
+	RuleId        string `json:"ruleId"`
+	File          string `json:"file"`
+	Line          int    `json:"line"`
+	Match         string `json:"match"`
+	Fingerprint   string `json:"fingerprint"`
+	Action        string `json:"action"`
+}
+
+// AllowListEntry suppresses findings of a repo. Empty fields match every finding, PathPattern is a glob on the file
+// and a pattern ending with / matches every file of the directory
+type AllowListEntry struct {
+	Id            int    `json:"id"`
+	GitMaterialId int    `json:"gitMaterialId" validate:"required"`
+	RuleId        string `json:"ruleId,omitempty"`
+	PathPattern   string `json:"pathPattern,omitempty"`
+	Fingerprint   string `json:"fingerprint,omitempty"`
+	Reason        string `json:"reason"`
+}
+
+// Matches returns true when the entry allows the finding
+func (entry *AllowListEntry) Matches(finding *SecretFinding) bool {
+	if entry.GitMaterialId != finding.GitMaterialId {
+		return false
+	}
+	if len(entry.RuleId) > 0 && entry.RuleId != finding.RuleId {
+		return false
+	}
+	if len(entry.Fingerprint) > 0 && entry.Fingerprint != finding.Fingerprint {
+		return false
+	}
+	if len(entry.PathPattern) > 0 {
+		if strings.HasSuffix(entry.PathPattern, "/") {
+			return strings.HasPrefix(finding.File, entry.PathPattern)
+		}
+		matched, err := path.Match(entry.PathPattern, finding.File)
+		return err == nil && matched
+	}
+	return true
+}
+
+// ValidateAllowListEntry checks that an entry is scoped by rule, path or fingerprint so that it never allows every
+// secret of a repo
+func ValidateAllowListEntry(entry *AllowListEntry) error {
+	if len(entry.RuleId) == 0 && len(entry.PathPattern) == 0 && len(entry.Fingerprint) == 0 {
+		return fmt.Errorf("allow list entry needs a rule, path pattern or fingerprint")
+	}
+	if len(entry.RuleId) > 0 && GetSecretScanRule(entry.RuleId) == nil {
+		return fmt.Errorf("unsupported secret scan rule %s", entry.RuleId)
+	}
+	if len(entry.PathPattern) > 0 && !strings.HasSuffix(entry.PathPattern, "/") {
+		if _, err := path.Match(entry.PathPattern, ""); err != nil {
+			return fmt.Errorf("invalid path pattern %s", entry.PathPattern)
+		}
+	}
+	return nil
+}
+
+// ApplySecretScanPolicy sets the action of findings, allow listed findings are allowed and others block the build when
+// the plugin step fails on findings. Returns true when any finding blocks the build
+func ApplySecretScanPolicy(findings []*SecretFinding, allowList []*AllowListEntry, failOnFindings bool) bool {
+	blocked := false
+	for _, finding := range findings {
+		finding.Action = SECRET_SCAN_ACTION_WARN
+		for _, entry := range allowList {
+			if entry.Matches(finding) {
+				finding.Action = SECRET_SCAN_ACTION_ALLOW
+				break
+			}
+		}
+		if finding.Action == SECRET_SCAN_ACTION_WARN && failOnFindings {
+			finding.Action = SECRET_SCAN_ACTION_BLOCK
+			blocked = true
+		}
+	}
+	return blocked
+}
+
+// BuildSecretScanViolationMessage summarises findings which failed a build
+func BuildSecretScanViolationMessage(findings []*SecretFinding) string {
+	var violations []string
+	for _, finding := range findings {
+		if finding.Action == SECRET_SCAN_ACTION_BLOCK {
+			violations = append(violations, fmt.Sprintf("%s in %s:%d", finding.RuleId, finding.File, finding.Line))
+		}
+	}
+	if len(violations) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("secret leak detected: %s", strings.Join(violations, "; "))
+}
@@ -0,0 +1,380 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package secretScan
+
+import (
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/pipeline/repository"
+	pluginRepository "github.com/devtron-labs/devtron/pkg/plugin/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"strconv"
+	"time"
+)
+
+const secretScanResultPollInterval = 2 * time.Second
+
+type SecretScanConfig struct {
+	Enabled bool `env:"SECRET_SCAN_ENABLED" envDefault:"true"`
+	// ResultWaitTimeout is how long, in seconds, the ci fail event waits for the result of a requested scan
+	ResultWaitTimeout int `env:"SECRET_SCAN_RESULT_WAIT_TIMEOUT" envDefault:"60"`
+}
+
+func GetSecretScanConfig() (*SecretScanConfig, error) {
+	cfg := &SecretScanConfig{}
+	err := env.Parse(cfg)
+	if err != nil {
+		fmt.Println("failed to parse secret scan config: " + err.Error())
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// SecretScanRequest is sent to ci runner in workflow request, ci runner scans checked out materials before it runs the
+// pre build step at StepIndex, writes the SecretScanEvent to ResultPath and publishes it. The plugin step fails when
+// ResultPath is missing, or when it is blocked and FailOnFindings is set
+type SecretScanRequest struct {
+	StepIndex      int                   `json:"stepIndex"`
+	ResultPath     string                `json:"resultPath"`
+	FailOnFindings bool                  `json:"failOnFindings"`
+	Rules          []*SecretScanRule     `json:"rules"`
+	Materials      []*SecretScanMaterial `json:"materials"`
+}
+
+type SecretScanMaterial struct {
+	GitMaterialId int               `json:"gitMaterialId"`
+	CheckoutPath  string            `json:"checkoutPath"`
+	AllowList     []*AllowListEntry `json:"allowList"`
+}
+
+// SecretScanEvent is published by ci runner on CI-SECRET-SCAN topic after the scan, before the build continues or fails.
+// Blocked is decided by ci runner from the allow list of the request and fails the plugin step with FailOnFindings
+type SecretScanEvent struct {
+	WorkflowId     int              `json:"workflowId"`
+	PipelineId     int              `json:"pipelineId"`
+	FailOnFindings bool             `json:"failOnFindings"`
+	Blocked        bool             `json:"blocked"`
+	Findings       []*SecretFinding `json:"findings"`
+}
+
+type SecretScanResult struct {
+	CiWorkflowId int              `json:"ciWorkflowId"`
+	AppId        int              `json:"-"`
+	Blocked      bool             `json:"blocked"`
+	Findings     []*SecretFinding `json:"findings"`
+}
+
+type SecretScanService interface {
+	// BuildSecretScanRequest returns the scan to run in ci runner when pre build stage has the secret scan plugin step,
+	// nil is returned otherwise
+	BuildSecretScanRequest(preCiSteps []*bean.StepObject, ciMaterials []*pipelineConfig.CiPipelineMaterial) (*SecretScanRequest, error)
+	// SaveScanRequested records that the scan is sent to ci runner, results of requested scans are waited for
+	SaveScanRequested(ciWorkflowId int, userId int32) error
+	SaveScanResult(event *SecretScanEvent) error
+	GetScanResult(ciWorkflowId int) (*SecretScanResult, error)
+	// WaitForScanResult returns the result of the scan requested for a ci workflow once it is saved, nil is returned
+	// when no scan was requested or the result is not saved within ResultWaitTimeout
+	WaitForScanResult(ciWorkflowId int) (*SecretScanResult, error)
+	GetAllowList(gitMaterialId int) ([]*AllowListEntry, error)
+	GetAllowListEntry(id int) (*AllowListEntry, error)
+	SaveAllowListEntry(entry *AllowListEntry, userId int32) (*AllowListEntry, error)
+	DeleteAllowListEntry(id int, userId int32) error
+	GetAppIdByGitMaterialId(gitMaterialId int) (int, error)
+}
+
+type SecretScanServiceImpl struct {
+	logger                 *zap.SugaredLogger
+	secretScanConfig       *SecretScanConfig
+	secretScanRepository   SecretScanRepository
+	globalPluginRepository pluginRepository.GlobalPluginRepository
+	ciWorkflowRepository   pipelineConfig.CiWorkflowRepository
+	materialRepository     pipelineConfig.MaterialRepository
+}
+
+func NewSecretScanServiceImpl(logger *zap.SugaredLogger, secretScanConfig *SecretScanConfig,
+	secretScanRepository SecretScanRepository, globalPluginRepository pluginRepository.GlobalPluginRepository,
+	ciWorkflowRepository pipelineConfig.CiWorkflowRepository, materialRepository pipelineConfig.MaterialRepository) *SecretScanServiceImpl {
+	return &SecretScanServiceImpl{
+		logger:                 logger,
+		secretScanConfig:       secretScanConfig,
+		secretScanRepository:   secretScanRepository,
+		globalPluginRepository: globalPluginRepository,
+		ciWorkflowRepository:   ciWorkflowRepository,
+		materialRepository:     materialRepository,
+	}
+}
+
+func (impl SecretScanServiceImpl) BuildSecretScanRequest(preCiSteps []*bean.StepObject, ciMaterials []*pipelineConfig.CiPipelineMaterial) (*SecretScanRequest, error) {
+	if !impl.secretScanConfig.Enabled || len(preCiSteps) == 0 {
+		return nil, nil
+	}
+	plugin, err := impl.globalPluginRepository.GetMetaDataByName(SECRET_SCAN_PLUGIN_NAME)
+	if err == pg.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching secret scan plugin", "err", err)
+		return nil, err
+	}
+	var scanStep *bean.StepObject
+	for _, step := range preCiSteps {
+		if step.StepType == string(repository.PIPELINE_STEP_TYPE_REF_PLUGIN) && step.RefPluginId == plugin.Id {
+			scanStep = step
+			break
+		}
+	}
+	if scanStep == nil {
+		return nil, nil
+	}
+	request := &SecretScanRequest{StepIndex: scanStep.Index, ResultPath: SECRET_SCAN_RESULT_PATH, Rules: SecretScanRules}
+	for _, variable := range scanStep.InputVars {
+		if variable.Name == SECRET_SCAN_FAIL_ON_FINDINGS_VARIABLE {
+			request.FailOnFindings, _ = strconv.ParseBool(variable.Value)
+		}
+	}
+	var gitMaterialIds []int
+	for _, ciMaterial := range ciMaterials {
+		gitMaterialIds = append(gitMaterialIds, ciMaterial.GitMaterialId)
+	}
+	allowList, err := impl.getAllowList(gitMaterialIds)
+	if err != nil {
+		return nil, err
+	}
+	for _, ciMaterial := range ciMaterials {
+		material := &SecretScanMaterial{GitMaterialId: ciMaterial.GitMaterialId, AllowList: []*AllowListEntry{}}
+		if ciMaterial.GitMaterial != nil {
+			material.CheckoutPath = ciMaterial.GitMaterial.CheckoutPath
+		}
+		for _, entry := range allowList {
+			if entry.GitMaterialId == ciMaterial.GitMaterialId {
+				material.AllowList = append(material.AllowList, entry)
+			}
+		}
+		request.Materials = append(request.Materials, material)
+	}
+	return request, nil
+}
+
+func (impl SecretScanServiceImpl) SaveScanRequested(ciWorkflowId int, userId int32) error {
+	scan := &CiWorkflowSecretScan{
+		CiWorkflowId: ciWorkflowId,
+		Status:       SECRET_SCAN_STATUS_REQUESTED,
+		AuditLog:     sql.AuditLog{CreatedOn: time.Now(), CreatedBy: userId, UpdatedOn: time.Now(), UpdatedBy: userId},
+	}
+	err := impl.secretScanRepository.SaveScan(scan)
+	if err != nil {
+		impl.logger.Errorw("error in saving requested secret scan", "err", err, "ciWorkflowId", ciWorkflowId)
+		return err
+	}
+	return nil
+}
+
+func (impl SecretScanServiceImpl) SaveScanResult(event *SecretScanEvent) error {
+	gitMaterialIds := make([]int, 0, len(event.Findings))
+	for _, finding := range event.Findings {
+		gitMaterialIds = append(gitMaterialIds, finding.GitMaterialId)
+	}
+	allowList, err := impl.getAllowList(gitMaterialIds)
+	if err != nil {
+		return err
+	}
+	blocked := ApplySecretScanPolicy(event.Findings, allowList, event.FailOnFindings)
+	findings := make([]*SecretScanFinding, 0, len(event.Findings))
+	for _, finding := range event.Findings {
+		findings = append(findings, &SecretScanFinding{
+			CiWorkflowId:  event.WorkflowId,
+			GitMaterialId: finding.GitMaterialId,
+			RuleId:        finding.RuleId,
+			FilePath:      finding.File,
+			Line:          finding.Line,
+			RedactedMatch: finding.Match,
+			Fingerprint:   finding.Fingerprint,
+			Action:        finding.Action,
+			AuditLog:      sql.AuditLog{CreatedOn: time.Now(), CreatedBy: 1, UpdatedOn: time.Now(), UpdatedBy: 1},
+		})
+	}
+	scan := &CiWorkflowSecretScan{
+		CiWorkflowId: event.WorkflowId,
+		Status:       SECRET_SCAN_STATUS_COMPLETED,
+		Blocked:      blocked,
+		AuditLog:     sql.AuditLog{CreatedOn: time.Now(), CreatedBy: 1, UpdatedOn: time.Now(), UpdatedBy: 1},
+	}
+	err = impl.secretScanRepository.SaveFindings(event.WorkflowId, findings, scan)
+	if err != nil {
+		impl.logger.Errorw("error in saving secret scan findings", "err", err, "ciWorkflowId", event.WorkflowId)
+		return err
+	}
+	return nil
+}
+
+func (impl SecretScanServiceImpl) GetScanResult(ciWorkflowId int) (*SecretScanResult, error) {
+	ciWorkflow, err := impl.ciWorkflowRepository.FindById(ciWorkflowId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching ci workflow", "err", err, "ciWorkflowId", ciWorkflowId)
+		return nil, err
+	}
+	findings, err := impl.secretScanRepository.FindFindingsByCiWorkflowId(ciWorkflowId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching secret scan findings", "err", err, "ciWorkflowId", ciWorkflowId)
+		return nil, err
+	}
+	result := &SecretScanResult{CiWorkflowId: ciWorkflowId, Findings: []*SecretFinding{}}
+	if ciWorkflow.CiPipeline != nil {
+		result.AppId = ciWorkflow.CiPipeline.AppId
+	}
+	for _, finding := range findings {
+		result.Findings = append(result.Findings, &SecretFinding{
+			GitMaterialId: finding.GitMaterialId,
+			RuleId:        finding.RuleId,
+			File:          finding.FilePath,
+			Line:          finding.Line,
+			Match:         finding.RedactedMatch,
+			Fingerprint:   finding.Fingerprint,
+			Action:        finding.Action,
+		})
+		if finding.Action == SECRET_SCAN_ACTION_BLOCK {
+			result.Blocked = true
+		}
+	}
+	return result, nil
+}
+
+func (impl SecretScanServiceImpl) WaitForScanResult(ciWorkflowId int) (*SecretScanResult, error) {
+	deadline := time.Now().Add(time.Duration(impl.secretScanConfig.ResultWaitTimeout) * time.Second)
+	for {
+		scan, err := impl.secretScanRepository.FindScanByCiWorkflowId(ciWorkflowId)
+		if err == pg.ErrNoRows {
+			return nil, nil
+		} else if err != nil {
+			impl.logger.Errorw("error in fetching secret scan", "err", err, "ciWorkflowId", ciWorkflowId)
+			return nil, err
+		}
+		if scan.Status == SECRET_SCAN_STATUS_COMPLETED {
+			return impl.GetScanResult(ciWorkflowId)
+		}
+		if time.Now().After(deadline) {
+			impl.logger.Warnw("secret scan result not received", "ciWorkflowId", ciWorkflowId)
+			return nil, nil
+		}
+		time.Sleep(secretScanResultPollInterval)
+	}
+}
+
+func (impl SecretScanServiceImpl) GetAllowList(gitMaterialId int) ([]*AllowListEntry, error) {
+	return impl.getAllowList([]int{gitMaterialId})
+}
+
+func (impl SecretScanServiceImpl) getAllowList(gitMaterialIds []int) ([]*AllowListEntry, error) {
+	entries, err := impl.secretScanRepository.FindAllowListByGitMaterialIds(gitMaterialIds)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching secret scan allow list", "err", err, "gitMaterialIds", gitMaterialIds)
+		return nil, err
+	}
+	allowList := make([]*AllowListEntry, 0, len(entries))
+	for _, entry := range entries {
+		allowList = append(allowList, allowListEntryOf(entry))
+	}
+	return allowList, nil
+}
+
+func (impl SecretScanServiceImpl) GetAllowListEntry(id int) (*AllowListEntry, error) {
+	entry, err := impl.secretScanRepository.FindAllowListEntryById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching secret scan allow list entry", "err", err, "id", id)
+		return nil, err
+	}
+	return allowListEntryOf(entry), nil
+}
+
+// SaveAllowListEntry creates an allow list entry, or updates it when id is set
+func (impl SecretScanServiceImpl) SaveAllowListEntry(request *AllowListEntry, userId int32) (*AllowListEntry, error) {
+	err := ValidateAllowListEntry(request)
+	if err != nil {
+		return nil, err
+	}
+	entry := &SecretScanAllowList{}
+	if request.Id > 0 {
+		entry, err = impl.secretScanRepository.FindAllowListEntryById(request.Id)
+		if err != nil {
+			impl.logger.Errorw("error in fetching secret scan allow list entry", "err", err, "id", request.Id)
+			return nil, err
+		}
+		if entry.GitMaterialId != request.GitMaterialId {
+			return nil, fmt.Errorf("git material of allow list entry cannot be changed")
+		}
+	}
+	entry.GitMaterialId = request.GitMaterialId
+	entry.RuleId = request.RuleId
+	entry.PathPattern = request.PathPattern
+	entry.Fingerprint = request.Fingerprint
+	entry.Reason = request.Reason
+	entry.UpdatedOn = time.Now()
+	entry.UpdatedBy = userId
+	if entry.Id > 0 {
+		err = impl.secretScanRepository.UpdateAllowListEntry(entry)
+	} else {
+		entry.CreatedOn = time.Now()
+		entry.CreatedBy = userId
+		err = impl.secretScanRepository.SaveAllowListEntry(entry)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving secret scan allow list entry", "err", err, "entry", entry)
+		return nil, err
+	}
+	return allowListEntryOf(entry), nil
+}
+
+func (impl SecretScanServiceImpl) DeleteAllowListEntry(id int, userId int32) error {
+	entry, err := impl.secretScanRepository.FindAllowListEntryById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching secret scan allow list entry", "err", err, "id", id)
+		return err
+	}
+	entry.Deleted = true
+	entry.UpdatedOn = time.Now()
+	entry.UpdatedBy = userId
+	err = impl.secretScanRepository.UpdateAllowListEntry(entry)
+	if err != nil {
+		impl.logger.Errorw("error in deleting secret scan allow list entry", "err", err, "id", id)
+		return err
+	}
+	return nil
+}
+
+func (impl SecretScanServiceImpl) GetAppIdByGitMaterialId(gitMaterialId int) (int, error) {
+	material, err := impl.materialRepository.FindById(gitMaterialId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching git material", "err", err, "gitMaterialId", gitMaterialId)
+		return 0, err
+	}
+	return material.AppId, nil
+}
+
+func allowListEntryOf(entry *SecretScanAllowList) *AllowListEntry {
+	return &AllowListEntry{
+		Id:            entry.Id,
+		GitMaterialId: entry.GitMaterialId,
+		RuleId:        entry.RuleId,
+		PathPattern:   entry.PathPattern,
+		Fingerprint:   entry.Fingerprint,
+		Reason:        entry.Reason,
+	}
+}
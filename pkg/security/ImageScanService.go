@@ -18,6 +18,7 @@
 package security
 
 import (
+	client "github.com/devtron-labs/devtron/client/events"
 	"github.com/devtron-labs/devtron/internal/sql/repository/app"
 	repository2 "github.com/devtron-labs/devtron/pkg/team"
 	"time"
@@ -37,6 +38,10 @@ type ImageScanService interface {
 	FetchExecutionDetailResult(request *ImageScanRequest) (*ImageScanExecutionDetail, error)
 	FetchMinScanResultByAppIdAndEnvId(request *ImageScanRequest) (*ImageScanExecutionDetail, error)
 	VulnerabilityExposure(request *security.VulnerabilityRequest) (*security.VulnerabilityExposureListingResponse, error)
+	RescanDeployedImages() error
+	ExportVulnerabilityReport(request *VulnerabilityReportRequest) (*VulnerabilityReport, error)
+	MaterialiseSecurityPosture() error
+	GetSecurityPosture(request *SecurityPostureRequest) (*SecurityPostureResponse, error)
 }
 
 type ImageScanServiceImpl struct {
@@ -54,6 +59,10 @@ type ImageScanServiceImpl struct {
 	policyService                 PolicyService
 	pipelineRepository            pipelineConfig.PipelineRepository
 	ciPipelineRepository          pipelineConfig.CiPipelineRepository
+	ciTemplateRepository          pipelineConfig.CiTemplateRepository
+	eventClient                   client.EventClient
+	eventFactory                  client.EventFactory
+	securityPostureRepository     security.SecurityPostureRepository
 }
 
 type ImageScanRequest struct {
@@ -111,6 +120,7 @@ type Vulnerabilities struct {
 	CVersion   string `json:"currentVersion"`
 	FVersion   string `json:"fixedVersion"`
 	Permission string `json:"permission"`
+	Image      string `json:"image,omitempty"`
 }
 
 type SeverityCount struct {
@@ -125,7 +135,9 @@ func NewImageScanServiceImpl(Logger *zap.SugaredLogger, scanHistoryRepository se
 	userService user.UserService, teamRepository repository2.TeamRepository,
 	appRepository app.AppRepository,
 	envService cluster.EnvironmentService, ciArtifactRepository repository.CiArtifactRepository, policyService PolicyService,
-	pipelineRepository pipelineConfig.PipelineRepository, ciPipelineRepository pipelineConfig.CiPipelineRepository) *ImageScanServiceImpl {
+	pipelineRepository pipelineConfig.PipelineRepository, ciPipelineRepository pipelineConfig.CiPipelineRepository,
+	ciTemplateRepository pipelineConfig.CiTemplateRepository, eventClient client.EventClient, eventFactory client.EventFactory,
+	securityPostureRepository security.SecurityPostureRepository) *ImageScanServiceImpl {
 	return &ImageScanServiceImpl{Logger: Logger, scanHistoryRepository: scanHistoryRepository, scanResultRepository: scanResultRepository,
 		scanObjectMetaRepository: scanObjectMetaRepository, cveStoreRepository: cveStoreRepository,
 		imageScanDeployInfoRepository: imageScanDeployInfoRepository,
@@ -137,6 +149,10 @@ func NewImageScanServiceImpl(Logger *zap.SugaredLogger, scanHistoryRepository se
 		policyService:                 policyService,
 		pipelineRepository:            pipelineRepository,
 		ciPipelineRepository:          ciPipelineRepository,
+		ciTemplateRepository:          ciTemplateRepository,
+		eventClient:                   eventClient,
+		eventFactory:                  eventFactory,
+		securityPostureRepository:     securityPostureRepository,
 	}
 }
 
@@ -350,6 +366,7 @@ func (impl ImageScanServiceImpl) FetchExecutionDetailResult(request *ImageScanRe
 				FVersion: item.CveStore.FixedVersion,
 				Package:  item.CveStore.Package,
 				Severity: item.CveStore.Severity.String(),
+				Image:    item.ImageScanExecutionHistory.Image,
 				//Permission: "BLOCK", TODO
 			}
 			if item.CveStore.Severity == security.Critical {
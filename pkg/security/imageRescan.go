@@ -0,0 +1,163 @@
+package security
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/sql/repository/security"
+	util "github.com/devtron-labs/devtron/util/event"
+	"github.com/go-pg/pg"
+	"time"
+)
+
+// GetNewlyDiscoveredCves returns cves found by the latest scan of an image which were not found by the previous scan
+func GetNewlyDiscoveredCves(previous []*security.ImageScanExecutionResult, latest []*security.ImageScanExecutionResult) []*security.CveStore {
+	previousCves := make(map[string]bool)
+	for _, result := range previous {
+		previousCves[result.CveStoreName] = true
+	}
+	var newCves []*security.CveStore
+	for _, result := range latest {
+		if previousCves[result.CveStoreName] {
+			continue
+		}
+		previousCves[result.CveStoreName] = true
+		cve := result.CveStore
+		if len(cve.Name) == 0 {
+			cve.Name = result.CveStoreName
+		}
+		newCves = append(newCves, &cve)
+	}
+	return newCves
+}
+
+// RescanDeployedImages scans every image digest recorded in deploy info against the latest vulnerability database and
+// notifies about cves which were not found by the previous scan of the image
+func (impl ImageScanServiceImpl) RescanDeployedImages() error {
+	deployInfos, err := impl.imageScanDeployInfoRepository.FindAll()
+	if err != nil {
+		impl.Logger.Errorw("error in fetching image scan deploy info", "err", err)
+		return err
+	}
+	var historyIds []int
+	for _, deployInfo := range deployInfos {
+		historyIds = append(historyIds, deployInfo.ImageScanExecutionHistoryId...)
+	}
+	if len(historyIds) == 0 {
+		return nil
+	}
+	histories, err := impl.scanHistoryRepository.FindByIds(historyIds)
+	if err != nil {
+		impl.Logger.Errorw("error in fetching image scan history", "err", err, "ids", historyIds)
+		return err
+	}
+	historyById := make(map[int]*security.ImageScanExecutionHistory)
+	for _, history := range histories {
+		historyById[history.Id] = history
+	}
+
+	//every digest is scanned once, deploy infos running it are notified
+	rescannedHistory := make(map[string]*security.ImageScanExecutionHistory)
+	newCvesByDigest := make(map[string][]*security.CveStore)
+	for _, deployInfo := range deployInfos {
+		updated := false
+		for i, historyId := range deployInfo.ImageScanExecutionHistoryId {
+			history, ok := historyById[historyId]
+			if !ok || len(history.ImageHash) == 0 {
+				continue
+			}
+			latestHistory, ok := rescannedHistory[history.ImageHash]
+			if !ok {
+				var newCves []*security.CveStore
+				latestHistory, newCves, err = impl.rescanImage(history, deployInfo)
+				if err != nil {
+					impl.Logger.Errorw("error in rescanning deployed image", "err", err, "image", history.Image)
+					continue
+				}
+				rescannedHistory[history.ImageHash] = latestHistory
+				newCvesByDigest[history.ImageHash] = newCves
+			}
+			if newCves := newCvesByDigest[history.ImageHash]; len(newCves) > 0 {
+				impl.notifyNewlyDiscoveredCves(latestHistory, newCves, deployInfo)
+			}
+			if latestHistory.Id != historyId {
+				deployInfo.ImageScanExecutionHistoryId[i] = latestHistory.Id
+				updated = true
+			}
+		}
+		if updated {
+			deployInfo.UpdatedOn = time.Now()
+			deployInfo.UpdatedBy = 1
+			err = impl.imageScanDeployInfoRepository.Update(deployInfo)
+			if err != nil {
+				impl.Logger.Errorw("error in updating image scan deploy info", "err", err, "id", deployInfo.Id)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (impl ImageScanServiceImpl) rescanImage(history *security.ImageScanExecutionHistory, deployInfo *security.ImageScanDeployInfo) (*security.ImageScanExecutionHistory, []*security.CveStore, error) {
+	previousHistory, err := impl.scanHistoryRepository.FindByImageDigest(history.ImageHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	previousResults, err := impl.scanResultRepository.FetchByScanExecutionId(previousHistory.Id)
+	if err != nil && err != pg.ErrNoRows {
+		return nil, nil, err
+	}
+	scanEvent := &ScanEvent{Image: history.Image, ImageDigest: history.ImageHash, UserId: 1}
+	if deployInfo.ObjectType == security.ScanObjectType_APP && deployInfo.ScanObjectMetaId > 0 {
+		ciTemplate, err := impl.ciTemplateRepository.FindByAppId(deployInfo.ScanObjectMetaId)
+		if err != nil && err != pg.ErrNoRows {
+			return nil, nil, err
+		} else if ciTemplate != nil && ciTemplate.DockerRegistry != nil {
+			scanEvent.DockerRegistryId = ciTemplate.DockerRegistry.Id
+		}
+	}
+	err = impl.policyService.SendEventToClairUtility(scanEvent)
+	if err != nil {
+		return nil, nil, err
+	}
+	latestHistory, err := impl.scanHistoryRepository.FindByImageDigest(history.ImageHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	if latestHistory.Id == previousHistory.Id {
+		return nil, nil, fmt.Errorf("image scanner recorded no new scan of image %s", history.Image)
+	}
+	latestResults, err := impl.scanResultRepository.FetchByScanExecutionId(latestHistory.Id)
+	if err != nil && err != pg.ErrNoRows {
+		return nil, nil, err
+	}
+	return latestHistory, GetNewlyDiscoveredCves(previousResults, latestResults), nil
+}
+
+func (impl ImageScanServiceImpl) notifyNewlyDiscoveredCves(history *security.ImageScanExecutionHistory, newCves []*security.CveStore, deployInfo *security.ImageScanDeployInfo) {
+	var appId int
+	if deployInfo.ObjectType != security.ScanObjectType_POD {
+		appId = deployInfo.ScanObjectMetaId
+	}
+	event := impl.eventFactory.Build(util.NewCveDiscovered, nil, appId, &deployInfo.EnvId, util.CD)
+	event = impl.eventFactory.BuildNewCveDiscoveredData(event, history.Image, history.ImageHash, newCves)
+	if appId > 0 {
+		app, err := impl.appRepository.FindById(appId)
+		if err != nil {
+			impl.Logger.Errorw("error in fetching app of deployed image", "err", err, "appId", appId)
+		} else {
+			event.TeamId = app.TeamId
+			event.Payload.AppName = app.AppName
+		}
+	}
+	if deployInfo.EnvId > 0 {
+		env, err := impl.envService.FindById(deployInfo.EnvId)
+		if err != nil {
+			impl.Logger.Errorw("error in fetching env of deployed image", "err", err, "envId", deployInfo.EnvId)
+		} else {
+			event.Payload.EnvName = env.Environment
+		}
+	}
+	_, err := impl.eventClient.WriteNotificationEvent(event)
+	if err != nil {
+		impl.Logger.Errorw("error in writing newly discovered cve event", "err", err, "image", history.Image, "deployInfoId", deployInfo.Id)
+	}
+}
@@ -0,0 +1,65 @@
+package security
+
+import (
+	"github.com/devtron-labs/devtron/internal/sql/repository/security"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestGetNewlyDiscoveredCves(t *testing.T) {
+	result := func(name string, severity security.Severity) *security.ImageScanExecutionResult {
+		return &security.ImageScanExecutionResult{CveStoreName: name, CveStore: security.CveStore{Name: name, Severity: severity}}
+	}
+	tests := []struct {
+		name     string
+		previous []*security.ImageScanExecutionResult
+		latest   []*security.ImageScanExecutionResult
+		want     []string
+	}{
+		{name: "no change", previous: []*security.ImageScanExecutionResult{result("CVE-1", security.Low)}, latest: []*security.ImageScanExecutionResult{result("CVE-1", security.Low)}, want: nil},
+		{name: "first scan", previous: nil, latest: []*security.ImageScanExecutionResult{result("CVE-1", security.Low), result("CVE-2", security.Critical)}, want: []string{"CVE-1", "CVE-2"}},
+		{name: "new cve", previous: []*security.ImageScanExecutionResult{result("CVE-1", security.Low)}, latest: []*security.ImageScanExecutionResult{result("CVE-1", security.Low), result("CVE-2", security.Critical)}, want: []string{"CVE-2"}},
+		{name: "fixed cve", previous: []*security.ImageScanExecutionResult{result("CVE-1", security.Low), result("CVE-2", security.Critical)}, latest: []*security.ImageScanExecutionResult{result("CVE-1", security.Low)}, want: nil},
+		{name: "duplicate findings", previous: nil, latest: []*security.ImageScanExecutionResult{result("CVE-3", security.Moderate), result("CVE-3", security.Moderate)}, want: []string{"CVE-3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, cve := range GetNewlyDiscoveredCves(tt.previous, tt.latest) {
+				got = append(got, cve.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetNewlyDiscoveredCves() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendEventToClairUtility(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		wantErr    bool
+	}{
+		{name: "scan accepted", statusCode: http.StatusOK},
+		{name: "scan rejected", statusCode: http.StatusBadRequest, wantErr: true},
+		{name: "scanner failure", statusCode: http.StatusInternalServerError, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+			}))
+			defer server.Close()
+			impl := &PolicyServiceImpl{logger: zap.NewNop().Sugar(), client: server.Client(), ciConfig: &pipeline.CiConfig{ImageScannerEndpoint: server.URL}}
+			err := impl.SendEventToClairUtility(&ScanEvent{Image: "quay.io/devtron/test:1"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SendEventToClairUtility() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package security
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository/security"
+	"github.com/devtron-labs/devtron/pkg/sbom"
+	"github.com/go-pg/pg"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ValidateLicensePolicy checks that a license policy names a license and allows or blocks it
+func ValidateLicensePolicy(policy *bean.LicensePolicy) error {
+	if policy == nil {
+		return fmt.Errorf("license policy is missing")
+	}
+	license := strings.TrimSpace(policy.License)
+	if len(license) == 0 {
+		return fmt.Errorf("license is required")
+	}
+	if strings.ContainsAny(license, "()") || len(strings.Fields(license)) > 1 && !strings.Contains(strings.ToUpper(license), " WITH ") {
+		return fmt.Errorf("license %s must be a single license identifier", license)
+	}
+	if policy.Action != "allow" && policy.Action != "block" {
+		return fmt.Errorf("license policy only supports allow or block action")
+	}
+	return nil
+}
+
+// SaveLicensePolicy creates a license policy, or updates its action when id is set or the license already has a policy
+// in same scope
+func (impl *PolicyServiceImpl) SaveLicensePolicy(request *bean.LicensePolicy, userId int32) (*bean.IdVulnerabilityPolicyResult, error) {
+	err := ValidateLicensePolicy(request)
+	if err != nil {
+		return nil, err
+	}
+	action, err := impl.parsePolicyAction(string(request.Action))
+	if err != nil {
+		return nil, err
+	}
+	policy := &security.LicensePolicy{}
+	if request.Id > 0 {
+		policy, err = impl.licensePolicyRepository.GetById(request.Id)
+		if err != nil {
+			impl.logger.Errorw("error in fetching license policy", "err", err, "id", request.Id)
+			return nil, err
+		}
+		policy.Action = action
+		policy.UpdatedOn = time.Now()
+		policy.UpdatedBy = userId
+		policy, err = impl.licensePolicyRepository.UpdatePolicy(policy)
+	} else {
+		policy.Global = request.ClusterId == 0 && request.EnvId == 0 && request.AppId == 0
+		policy.ClusterId = request.ClusterId
+		policy.EnvironmentId = request.EnvId
+		policy.AppId = request.AppId
+		policy.License = strings.TrimSpace(request.License)
+		policy.Action = action
+		policy.CreatedOn = time.Now()
+		policy.CreatedBy = userId
+		policy.UpdatedOn = time.Now()
+		policy.UpdatedBy = userId
+		policy, err = impl.licensePolicyRepository.SavePolicy(policy)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving license policy", "err", err)
+		return nil, fmt.Errorf("error in saving license policy")
+	}
+	return &bean.IdVulnerabilityPolicyResult{Id: policy.Id}, nil
+}
+
+func (impl *PolicyServiceImpl) DeleteLicensePolicy(id int, userId int32) (*bean.IdVulnerabilityPolicyResult, error) {
+	policy, err := impl.licensePolicyRepository.GetById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching license policy", "err", err, "id", id)
+		return nil, err
+	}
+	policy.Deleted = true
+	policy.UpdatedOn = time.Now()
+	policy.UpdatedBy = userId
+	policy, err = impl.licensePolicyRepository.UpdatePolicy(policy)
+	if err != nil {
+		impl.logger.Errorw("error in deleting license policy", "err", err, "id", id)
+		return nil, err
+	}
+	return &bean.IdVulnerabilityPolicyResult{Id: policy.Id}, nil
+}
+
+func (impl *PolicyServiceImpl) GetLicensePolicy(id int) (*security.LicensePolicy, error) {
+	policy, err := impl.licensePolicyRepository.GetById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching license policy", "err", err, "id", id)
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (impl *PolicyServiceImpl) getApplicableLicensePolicies(clusterId, envId, appId int, isAppstore bool) (map[string]*security.LicensePolicy, error) {
+	policyLevel, err := security.GetApplicablePolicyLevel(clusterId, envId, appId, isAppstore)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := impl.getLicensePolicies(policyLevel, clusterId, envId, appId)
+	if err != nil {
+		return nil, err
+	}
+	return security.GetApplicableLicensePolicies(policies), nil
+}
+
+func (impl *PolicyServiceImpl) getLicensePolicies(policyLevel security.PolicyLevel, clusterId, envId, appId int) ([]*security.LicensePolicy, error) {
+	policies, err := impl.licensePolicyRepository.GetPolicies(policyLevel, clusterId, envId, appId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching license policies", "level", policyLevel, "err", err)
+		return nil, err
+	}
+	var applicablePolicies []*security.LicensePolicy
+	for _, policy := range security.GetApplicableLicensePolicies(policies) {
+		applicablePolicies = append(applicablePolicies, policy)
+	}
+	sort.Slice(applicablePolicies, func(i, j int) bool {
+		return applicablePolicies[i].License < applicablePolicies[j].License
+	})
+	return applicablePolicies, nil
+}
+
+// GetImageDigestFromReference returns the digest an image reference is pinned to, empty for a tag reference
+func GetImageDigestFromReference(image string) string {
+	if index := strings.LastIndex(image, "@"); index >= 0 {
+		return image[index+1:]
+	}
+	return ""
+}
+
+// getImageDigest resolves the digest of an image, a tag reference is resolved through the ci artifact built for it
+func (impl *PolicyServiceImpl) getImageDigest(image string) (string, error) {
+	if imageDigest := GetImageDigestFromReference(image); len(imageDigest) > 0 {
+		return imageDigest, nil
+	}
+	artifact, err := impl.ciArtifactRepository.GetByImage(image)
+	if err == pg.ErrNoRows {
+		return "", nil
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching ci artifact of image", "err", err, "image", image)
+		return "", err
+	}
+	return artifact.ImageDigest, nil
+}
+
+// getBlockedLicenses enforces license policies on the packages listed in the sbom of an image digest, an image
+// without sbom is blocked only by a blocking missing sbom policy
+func (impl *PolicyServiceImpl) getBlockedLicenses(imageDigest string, licensePolicy map[string]*security.LicensePolicy) ([]*security.BlockedLicense, error) {
+	if len(licensePolicy) == 0 {
+		return nil, nil
+	}
+	var components []*sbom.CiArtifactSbomComponent
+	if len(imageDigest) > 0 {
+		var err error
+		components, err = impl.sbomRepository.FindComponentsByImageDigest(imageDigest)
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error in fetching sbom components", "err", err, "digest", imageDigest)
+			return nil, err
+		}
+	}
+	if len(components) == 0 {
+		return security.EnforceMissingSbomPolicy(licensePolicy), nil
+	}
+	return security.EnforceLicensePolicy(sbom.GetPackageLicenses(components), licensePolicy), nil
+}
+
+func (impl *PolicyServiceImpl) licensePolicyBuilder(policyLevel security.PolicyLevel, policies []*security.LicensePolicy) []*bean.LicensePolicy {
+	licensePolicies := make([]*bean.LicensePolicy, 0, len(policies))
+	for _, policy := range policies {
+		licensePolicies = append(licensePolicies, &bean.LicensePolicy{
+			Id:           policy.Id,
+			ClusterId:    policy.ClusterId,
+			EnvId:        policy.EnvironmentId,
+			AppId:        policy.AppId,
+			License:      policy.License,
+			Action:       bean.VulnerabilityAction(policy.Action.String()),
+			PolicyOrigin: policy.PolicyLevel().String(),
+			Inherited:    policy.PolicyLevel() != policyLevel,
+		})
+	}
+	return licensePolicies
+}
@@ -0,0 +1,193 @@
+package security
+
+import (
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/security"
+	"github.com/devtron-labs/devtron/pkg/sbom"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateLicensePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  *bean.LicensePolicy
+		wantErr bool
+	}{
+		{name: "block license", policy: &bean.LicensePolicy{License: "AGPL-3.0-only", Action: "block"}, wantErr: false},
+		{name: "any license", policy: &bean.LicensePolicy{License: "*", Action: "allow"}, wantErr: false},
+		{name: "license with exception", policy: &bean.LicensePolicy{License: "GPL-2.0-only WITH Classpath-exception-2.0", Action: "allow"}, wantErr: false},
+		{name: "missing license", policy: &bean.LicensePolicy{License: " ", Action: "block"}, wantErr: true},
+		{name: "expression", policy: &bean.LicensePolicy{License: "MIT OR GPL-3.0-only", Action: "block"}, wantErr: true},
+		{name: "inherit action", policy: &bean.LicensePolicy{License: "MIT", Action: "inherit"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateLicensePolicy(tt.policy); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateLicensePolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEnforceLicensePolicy(t *testing.T) {
+	packages := []*security.PackageLicense{
+		{Package: "mongo-driver", Version: "1.0", License: "AGPL-3.0-only"},
+		{Package: "dual", Version: "1.0", License: "MIT OR AGPL-3.0-only"},
+		{Package: "combined", Version: "1.0", License: "Apache-2.0 AND (agpl-3.0-only OR GPL-3.0-only)"},
+		{Package: "jdk", Version: "11", License: "GPL-2.0-only WITH Classpath-exception-2.0"},
+		{Package: "unknown", Version: "1.0"},
+		{Package: "broken", Version: "1.0", License: "(MIT AND"},
+	}
+	tests := []struct {
+		name     string
+		policies []*security.LicensePolicy
+		want     []string
+	}{
+		{name: "no policy"},
+		{
+			name:     "block license",
+			policies: []*security.LicensePolicy{{Global: true, License: "AGPL-3.0-only", Action: security.Block}},
+			want:     []string{"mongo-driver:AGPL-3.0-only"},
+		},
+		{
+			name: "block every alternative",
+			policies: []*security.LicensePolicy{
+				{Global: true, License: "AGPL-3.0-only", Action: security.Block},
+				{Global: true, License: "GPL-3.0-only", Action: security.Block},
+			},
+			want: []string{"mongo-driver:AGPL-3.0-only", "combined:agpl-3.0-only,GPL-3.0-only"},
+		},
+		{
+			name: "allow list",
+			policies: []*security.LicensePolicy{
+				{Global: true, License: "*", Action: security.Block},
+				{Global: true, License: "MIT", Action: security.Allow},
+				{Global: true, License: "GPL-2.0-only WITH Classpath-exception-2.0", Action: security.Allow},
+			},
+			want: []string{"mongo-driver:AGPL-3.0-only", "combined:Apache-2.0,agpl-3.0-only,GPL-3.0-only", "broken:(MIT AND"},
+		},
+		{
+			name: "environment overrides global",
+			policies: []*security.LicensePolicy{
+				{Global: true, License: "AGPL-3.0-only", Action: security.Block},
+				{EnvironmentId: 2, License: "AGPL-3.0-only", Action: security.Allow},
+				{Global: true, License: "GPL-2.0-only", Action: security.Block},
+			},
+			want: []string{"jdk:GPL-2.0-only"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, blockedLicense := range security.EnforceLicensePolicy(packages, security.GetApplicableLicensePolicies(tt.policies)) {
+				got = append(got, blockedLicense.Package.Package+":"+strings.Join(blockedLicense.Licenses, ","))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EnforceLicensePolicy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnforceMissingSbomPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		policies []*security.LicensePolicy
+		want     bool
+	}{
+		{name: "no policy", want: false},
+		{name: "any license does not apply", policies: []*security.LicensePolicy{{Global: true, License: "*", Action: security.Block}}, want: false},
+		{name: "block missing sbom", policies: []*security.LicensePolicy{{Global: true, License: "no-sbom", Action: security.Block}}, want: true},
+		{
+			name: "environment warns",
+			policies: []*security.LicensePolicy{
+				{Global: true, License: security.MissingSbomLicense, Action: security.Block},
+				{EnvironmentId: 2, License: security.MissingSbomLicense, Action: security.Allow},
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blocked := security.EnforceMissingSbomPolicy(security.GetApplicableLicensePolicies(tt.policies))
+			if got := len(blocked) > 0; got != tt.want {
+				t.Errorf("EnforceMissingSbomPolicy() blocked = %v, want %v", got, tt.want)
+			}
+			if tt.want && blocked[0].Rule != security.LicenseBlockedByMissingSbomPolicy {
+				t.Errorf("EnforceMissingSbomPolicy() rule = %v", blocked[0].Rule)
+			}
+		})
+	}
+}
+
+type fakeSbomRepository struct {
+	sbom.SbomRepository
+	componentsByDigest map[string][]*sbom.CiArtifactSbomComponent
+}
+
+func (repo *fakeSbomRepository) FindComponentsByImageDigest(imageDigest string) ([]*sbom.CiArtifactSbomComponent, error) {
+	return repo.componentsByDigest[imageDigest], nil
+}
+
+type fakeCiArtifactRepository struct {
+	repository.CiArtifactRepository
+	artifactsByImage map[string]*repository.CiArtifact
+}
+
+func (repo *fakeCiArtifactRepository) GetByImage(image string) (*repository.CiArtifact, error) {
+	artifact, ok := repo.artifactsByImage[image]
+	if !ok {
+		return &repository.CiArtifact{}, pg.ErrNoRows
+	}
+	return artifact, nil
+}
+
+func TestGetBlockedLicensesOfImage(t *testing.T) {
+	licensePolicy := security.GetApplicableLicensePolicies([]*security.LicensePolicy{
+		{Global: true, License: "AGPL-3.0-only", Action: security.Block},
+		{Global: true, License: security.MissingSbomLicense, Action: security.Block},
+	})
+	impl := &PolicyServiceImpl{
+		logger: zap.NewNop().Sugar(),
+		sbomRepository: &fakeSbomRepository{componentsByDigest: map[string][]*sbom.CiArtifactSbomComponent{
+			"sha256:aaa": {{Name: "mongo-driver", Version: "1.0", License: "AGPL-3.0-only"}},
+			"sha256:bbb": {{Name: "gin", Version: "1.9", License: "MIT"}},
+		}},
+		ciArtifactRepository: &fakeCiArtifactRepository{artifactsByImage: map[string]*repository.CiArtifact{
+			"registry/app:v1": {Image: "registry/app:v1", ImageDigest: "sha256:aaa"},
+		}},
+	}
+	tests := []struct {
+		name  string
+		image string
+		want  string
+	}{
+		{name: "tag resolved to digest of its artifact", image: "registry/app:v1", want: security.LicenseBlockedByLicensePolicy},
+		{name: "digest reference", image: "registry/app:v2@sha256:bbb", want: ""},
+		{name: "tag without artifact has no sbom", image: "registry/other:v1", want: security.LicenseBlockedByMissingSbomPolicy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imageDigest, err := impl.getImageDigest(tt.image)
+			if err != nil {
+				t.Fatalf("getImageDigest() error = %v", err)
+			}
+			blocked, err := impl.getBlockedLicenses(imageDigest, licensePolicy)
+			if err != nil {
+				t.Fatalf("getBlockedLicenses() error = %v", err)
+			}
+			got := ""
+			if len(blocked) > 0 {
+				got = blocked[0].Rule
+			}
+			if got != tt.want {
+				t.Errorf("getBlockedLicenses() rule = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
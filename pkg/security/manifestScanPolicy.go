@@ -0,0 +1,137 @@
+package security
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository/security"
+	"github.com/devtron-labs/devtron/pkg/manifestScan"
+	"github.com/go-pg/pg"
+	"sort"
+	"time"
+)
+
+// ValidateManifestScanPolicy checks that a manifest scan policy names a built-in rule and warns or blocks on it
+func ValidateManifestScanPolicy(policy *bean.ManifestScanPolicy) error {
+	if policy == nil {
+		return fmt.Errorf("manifest scan policy is missing")
+	}
+	if policy.RuleId != security.AnyManifestScanRule && manifestScan.GetManifestScanRule(policy.RuleId) == nil {
+		return fmt.Errorf("unsupported manifest scan rule %s", policy.RuleId)
+	}
+	if policy.Action != manifestScan.MANIFEST_SCAN_ACTION_WARN && policy.Action != manifestScan.MANIFEST_SCAN_ACTION_BLOCK {
+		return fmt.Errorf("manifest scan policy only supports warn or block action")
+	}
+	return nil
+}
+
+// manifestScanPolicyAction stores warn as allow, findings of allowed rules are reported without failing the deployment
+func manifestScanPolicyAction(action bean.VulnerabilityAction) security.PolicyAction {
+	if action == manifestScan.MANIFEST_SCAN_ACTION_BLOCK {
+		return security.Block
+	}
+	return security.Allow
+}
+
+// SaveManifestScanPolicy creates a manifest scan policy, or updates its action when id is set or the rule already has
+// a policy in same scope
+func (impl *PolicyServiceImpl) SaveManifestScanPolicy(request *bean.ManifestScanPolicy, userId int32) (*bean.IdVulnerabilityPolicyResult, error) {
+	err := ValidateManifestScanPolicy(request)
+	if err != nil {
+		return nil, err
+	}
+	policy := &security.ManifestScanPolicy{}
+	if request.Id > 0 {
+		policy, err = impl.manifestScanPolicyRepository.GetById(request.Id)
+		if err != nil {
+			impl.logger.Errorw("error in fetching manifest scan policy", "err", err, "id", request.Id)
+			return nil, err
+		}
+		policy.Action = manifestScanPolicyAction(request.Action)
+		policy.FailOpen = request.FailOpen
+		policy.UpdatedOn = time.Now()
+		policy.UpdatedBy = userId
+		policy, err = impl.manifestScanPolicyRepository.UpdatePolicy(policy)
+	} else {
+		policy.Global = request.ClusterId == 0 && request.EnvId == 0 && request.AppId == 0
+		policy.ClusterId = request.ClusterId
+		policy.EnvironmentId = request.EnvId
+		policy.AppId = request.AppId
+		policy.RuleId = request.RuleId
+		policy.Action = manifestScanPolicyAction(request.Action)
+		policy.FailOpen = request.FailOpen
+		policy.CreatedOn = time.Now()
+		policy.CreatedBy = userId
+		policy.UpdatedOn = time.Now()
+		policy.UpdatedBy = userId
+		policy, err = impl.manifestScanPolicyRepository.SavePolicy(policy)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving manifest scan policy", "err", err)
+		return nil, fmt.Errorf("error in saving manifest scan policy")
+	}
+	return &bean.IdVulnerabilityPolicyResult{Id: policy.Id}, nil
+}
+
+func (impl *PolicyServiceImpl) DeleteManifestScanPolicy(id int, userId int32) (*bean.IdVulnerabilityPolicyResult, error) {
+	policy, err := impl.manifestScanPolicyRepository.GetById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching manifest scan policy", "err", err, "id", id)
+		return nil, err
+	}
+	policy.Deleted = true
+	policy.UpdatedOn = time.Now()
+	policy.UpdatedBy = userId
+	policy, err = impl.manifestScanPolicyRepository.UpdatePolicy(policy)
+	if err != nil {
+		impl.logger.Errorw("error in deleting manifest scan policy", "err", err, "id", id)
+		return nil, err
+	}
+	return &bean.IdVulnerabilityPolicyResult{Id: policy.Id}, nil
+}
+
+func (impl *PolicyServiceImpl) GetManifestScanPolicy(id int) (*security.ManifestScanPolicy, error) {
+	policy, err := impl.manifestScanPolicyRepository.GetById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching manifest scan policy", "err", err, "id", id)
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (impl *PolicyServiceImpl) getManifestScanPolicies(policyLevel security.PolicyLevel, clusterId, envId, appId int) ([]*security.ManifestScanPolicy, error) {
+	policies, err := impl.manifestScanPolicyRepository.GetPolicies(policyLevel, clusterId, envId, appId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching manifest scan policies", "level", policyLevel, "err", err)
+		return nil, err
+	}
+	var applicablePolicies []*security.ManifestScanPolicy
+	for _, policy := range security.GetApplicableManifestScanPolicies(policies) {
+		applicablePolicies = append(applicablePolicies, policy)
+	}
+	sort.Slice(applicablePolicies, func(i, j int) bool {
+		return applicablePolicies[i].RuleId < applicablePolicies[j].RuleId
+	})
+	return applicablePolicies, nil
+}
+
+func (impl *PolicyServiceImpl) manifestScanPolicyBuilder(policyLevel security.PolicyLevel, policies []*security.ManifestScanPolicy) []*bean.ManifestScanPolicy {
+	manifestScanPolicies := make([]*bean.ManifestScanPolicy, 0, len(policies))
+	for _, policy := range policies {
+		action := bean.VulnerabilityAction(manifestScan.MANIFEST_SCAN_ACTION_WARN)
+		if policy.Action == security.Block {
+			action = manifestScan.MANIFEST_SCAN_ACTION_BLOCK
+		}
+		manifestScanPolicies = append(manifestScanPolicies, &bean.ManifestScanPolicy{
+			Id:           policy.Id,
+			ClusterId:    policy.ClusterId,
+			EnvId:        policy.EnvironmentId,
+			AppId:        policy.AppId,
+			RuleId:       policy.RuleId,
+			Action:       action,
+			FailOpen:     policy.FailOpen,
+			PolicyOrigin: policy.PolicyLevel().String(),
+			Inherited:    policy.PolicyLevel() != policyLevel,
+		})
+	}
+	return manifestScanPolicies
+}
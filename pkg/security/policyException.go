@@ -0,0 +1,105 @@
+package security
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository/security"
+	util "github.com/devtron-labs/devtron/util/event"
+	"strings"
+	"time"
+)
+
+// MaxCveExceptionValidity bounds how long a vulnerability can be allowed by an exception
+const MaxCveExceptionValidity = 365 * 24 * time.Hour
+
+// ValidateCvePolicyException checks that an exception only allows a vulnerability, is justified, owned and expires
+// within MaxCveExceptionValidity
+func ValidateCvePolicyException(action security.PolicyAction, exception *bean.VulnerabilityPolicyException, now time.Time) error {
+	if exception == nil {
+		return nil
+	}
+	if action != security.Allow {
+		return fmt.Errorf("exception is only supported with allow action")
+	}
+	if len(strings.TrimSpace(exception.Justification)) == 0 {
+		return fmt.Errorf("justification is required for exception")
+	}
+	if len(strings.TrimSpace(exception.Owner)) == 0 {
+		return fmt.Errorf("owner is required for exception")
+	}
+	if !exception.ExpiresOn.After(now) {
+		return fmt.Errorf("expiry of exception must be in future")
+	}
+	if exception.ExpiresOn.After(now.Add(MaxCveExceptionValidity)) {
+		return fmt.Errorf("exception can not be valid for more than %d days", int(MaxCveExceptionValidity.Hours()/24))
+	}
+	return nil
+}
+
+func setCvePolicyException(policy *security.CvePolicy, exception *bean.VulnerabilityPolicyException) {
+	if exception == nil {
+		policy.Justification = ""
+		policy.Owner = ""
+		policy.ExpiresOn = time.Time{}
+	} else {
+		policy.Justification = strings.TrimSpace(exception.Justification)
+		policy.Owner = strings.TrimSpace(exception.Owner)
+		policy.ExpiresOn = exception.ExpiresOn
+	}
+	policy.ExpiryNotifiedOn = time.Time{}
+}
+
+func getCvePolicyException(policy *security.CvePolicy) *bean.VulnerabilityPolicyException {
+	if !policy.IsException() {
+		return nil
+	}
+	return &bean.VulnerabilityPolicyException{
+		Justification: policy.Justification,
+		Owner:         policy.Owner,
+		ExpiresOn:     policy.ExpiresOn,
+	}
+}
+
+// NotifyExpiringCveExceptions sends a notification once for every exception expiring within notifyBefore
+func (impl *PolicyServiceImpl) NotifyExpiringCveExceptions(notifyBefore time.Duration) error {
+	now := time.Now()
+	exceptions, err := impl.cvePolicyRepository.GetExceptionsToNotifyExpiry(now, now.Add(notifyBefore))
+	if err != nil {
+		impl.logger.Errorw("error in fetching expiring cve exceptions", "err", err)
+		return err
+	}
+	for _, exception := range exceptions {
+		event := impl.eventFactory.Build(util.CveExceptionExpiring, nil, exception.AppId, &exception.EnvironmentId, util.CD)
+		event = impl.eventFactory.BuildCveExceptionExpiringData(event, exception)
+		if exception.AppId > 0 {
+			app, err := impl.apRepository.FindById(exception.AppId)
+			if err != nil {
+				impl.logger.Errorw("error in fetching app of cve exception", "err", err, "appId", exception.AppId)
+				return err
+			}
+			event.TeamId = app.TeamId
+			event.Payload.AppName = app.AppName
+		}
+		if exception.EnvironmentId > 0 {
+			env, err := impl.environmentService.FindById(exception.EnvironmentId)
+			if err != nil {
+				impl.logger.Errorw("error in fetching environment of cve exception", "err", err, "envId", exception.EnvironmentId)
+				return err
+			}
+			event.Payload.EnvName = env.Environment
+		}
+		_, err = impl.eventClient.WriteNotificationEvent(event)
+		if err != nil {
+			impl.logger.Errorw("error in writing cve exception expiry event", "err", err, "policyId", exception.Id)
+			continue
+		}
+		exception.ExpiryNotifiedOn = now
+		exception.UpdatedOn = now
+		_, err = impl.cvePolicyRepository.UpdatePolicy(exception)
+		if err != nil {
+			impl.logger.Errorw("error in marking cve exception expiry notified", "err", err, "policyId", exception.Id)
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package security
+
+import (
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository/security"
+	"testing"
+	"time"
+)
+
+func TestValidateCvePolicyException(t *testing.T) {
+	now := time.Date(2022, 8, 8, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		action    security.PolicyAction
+		exception *bean.VulnerabilityPolicyException
+		wantErr   bool
+	}{
+		{name: "no exception", action: security.Block, exception: nil, wantErr: false},
+		{name: "valid exception", action: security.Allow, exception: &bean.VulnerabilityPolicyException{Justification: "not reachable", Owner: "owner@example.com", ExpiresOn: now.Add(30 * 24 * time.Hour)}, wantErr: false},
+		{name: "exception on block", action: security.Block, exception: &bean.VulnerabilityPolicyException{Justification: "not reachable", Owner: "owner@example.com", ExpiresOn: now.Add(time.Hour)}, wantErr: true},
+		{name: "missing justification", action: security.Allow, exception: &bean.VulnerabilityPolicyException{Justification: " ", Owner: "owner@example.com", ExpiresOn: now.Add(time.Hour)}, wantErr: true},
+		{name: "missing owner", action: security.Allow, exception: &bean.VulnerabilityPolicyException{Justification: "not reachable", ExpiresOn: now.Add(time.Hour)}, wantErr: true},
+		{name: "expiry in past", action: security.Allow, exception: &bean.VulnerabilityPolicyException{Justification: "not reachable", Owner: "owner@example.com", ExpiresOn: now}, wantErr: true},
+		{name: "expiry beyond max validity", action: security.Allow, exception: &bean.VulnerabilityPolicyException{Justification: "not reachable", Owner: "owner@example.com", ExpiresOn: now.Add(MaxCveExceptionValidity + time.Hour)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateCvePolicyException(tt.action, tt.exception, now); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCvePolicyException() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHasBlockedCVEWithExpiredException(t *testing.T) {
+	critical := security.Critical
+	cves := []*security.CveStore{{Name: "CVE-2022-0001", Severity: security.Critical}}
+	severityPolicy := map[security.Severity]*security.CvePolicy{
+		security.Critical: {Action: security.Block, Severity: &critical},
+	}
+	tests := []struct {
+		name      string
+		expiresOn time.Time
+		want      bool
+	}{
+		{name: "permanent allow", expiresOn: time.Time{}, want: false},
+		{name: "active exception", expiresOn: time.Now().Add(time.Hour), want: false},
+		{name: "expired exception", expiresOn: time.Now().Add(-time.Hour), want: true},
+	}
+	impl := &PolicyServiceImpl{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cvePolicy := map[string]*security.CvePolicy{
+				"CVE-2022-0001": {Action: security.Allow, Severity: &critical, CVEStoreId: "CVE-2022-0001", ExpiresOn: tt.expiresOn},
+			}
+			if got := impl.HasBlockedCVE(cves, cvePolicy, severityPolicy, nil); got != tt.want {
+				t.Errorf("HasBlockedCVE() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package security
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository/security"
+	"strings"
+	"time"
+)
+
+func parseSeverity(severity string) (security.Severity, error) {
+	switch severity {
+	case "critical":
+		return security.Critical, nil
+	case "moderate":
+		return security.Moderate, nil
+	case "low":
+		return security.Low, nil
+	}
+	return security.Low, fmt.Errorf("unsupported Severity %s", severity)
+}
+
+// ValidateVulnerabilityPolicyRule checks that fix available and threshold rules block a severity and that package rules
+// name a package
+func ValidateVulnerabilityPolicyRule(rule *bean.VulnerabilityPolicyRule) error {
+	if rule == nil {
+		return fmt.Errorf("rule is missing")
+	}
+	if len(rule.Severity) > 0 {
+		if _, err := parseSeverity(rule.Severity); err != nil {
+			return err
+		}
+	}
+	switch rule.RuleType {
+	case bean.VulnerabilityPolicyRuleFixAvailable, bean.VulnerabilityPolicyRuleThreshold:
+		if len(rule.Severity) == 0 {
+			return fmt.Errorf("severity is required for %s rule", rule.RuleType)
+		}
+		if rule.Action != "block" {
+			return fmt.Errorf("%s rule only supports block action", rule.RuleType)
+		}
+		if len(rule.Package) > 0 {
+			return fmt.Errorf("package is not supported for %s rule", rule.RuleType)
+		}
+		if rule.RuleType == bean.VulnerabilityPolicyRuleThreshold && rule.Threshold < 0 {
+			return fmt.Errorf("threshold can not be negative")
+		}
+	case bean.VulnerabilityPolicyRulePackage:
+		if len(strings.TrimSpace(rule.Package)) == 0 {
+			return fmt.Errorf("package is required for %s rule", rule.RuleType)
+		}
+		if rule.Action != "allow" && rule.Action != "block" {
+			return fmt.Errorf("%s rule only supports allow or block action", rule.RuleType)
+		}
+	default:
+		return fmt.Errorf("unsupported rule type %s", rule.RuleType)
+	}
+	return nil
+}
+
+// SavePolicyRule creates a rule, or updates it when id is set
+func (impl *PolicyServiceImpl) SavePolicyRule(request *bean.VulnerabilityPolicyRule, userId int32) (*bean.IdVulnerabilityPolicyResult, error) {
+	err := ValidateVulnerabilityPolicyRule(request)
+	if err != nil {
+		return nil, err
+	}
+	action, err := impl.parsePolicyAction(string(request.Action))
+	if err != nil {
+		return nil, err
+	}
+	var severity *security.Severity
+	if len(request.Severity) > 0 {
+		ruleSeverity, _ := parseSeverity(request.Severity)
+		severity = &ruleSeverity
+	}
+	rule := &security.CvePolicyRule{}
+	if request.Id > 0 {
+		rule, err = impl.cvePolicyRuleRepository.GetRuleById(request.Id)
+		if err != nil {
+			impl.logger.Errorw("error in fetching policy rule", "err", err, "id", request.Id)
+			return nil, err
+		}
+	} else {
+		rule.Global = request.ClusterId == 0 && request.EnvId == 0 && request.AppId == 0
+		rule.ClusterId = request.ClusterId
+		rule.EnvironmentId = request.EnvId
+		rule.AppId = request.AppId
+		rule.CreatedOn = time.Now()
+		rule.CreatedBy = userId
+	}
+	rule.RuleType = security.CvePolicyRuleType(request.RuleType)
+	rule.Action = action
+	rule.Severity = severity
+	rule.Package = strings.TrimSpace(request.Package)
+	rule.Threshold = request.Threshold
+	rule.UpdatedOn = time.Now()
+	rule.UpdatedBy = userId
+	if rule.Id > 0 {
+		rule, err = impl.cvePolicyRuleRepository.UpdateRule(rule)
+	} else {
+		rule, err = impl.cvePolicyRuleRepository.SaveRule(rule)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving policy rule", "err", err)
+		return nil, fmt.Errorf("error in saving policy rule")
+	}
+	return &bean.IdVulnerabilityPolicyResult{Id: rule.Id}, nil
+}
+
+func (impl *PolicyServiceImpl) DeletePolicyRule(id int, userId int32) (*bean.IdVulnerabilityPolicyResult, error) {
+	rule, err := impl.cvePolicyRuleRepository.GetRuleById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching policy rule", "err", err, "id", id)
+		return nil, err
+	}
+	rule.Deleted = true
+	rule.UpdatedOn = time.Now()
+	rule.UpdatedBy = userId
+	rule, err = impl.cvePolicyRuleRepository.UpdateRule(rule)
+	if err != nil {
+		impl.logger.Errorw("error in deleting policy rule", "err", err, "id", id)
+		return nil, err
+	}
+	return &bean.IdVulnerabilityPolicyResult{Id: rule.Id}, nil
+}
+
+func (impl *PolicyServiceImpl) GetPolicyRule(id int) (*security.CvePolicyRule, error) {
+	rule, err := impl.cvePolicyRuleRepository.GetRuleById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching policy rule", "err", err, "id", id)
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (impl *PolicyServiceImpl) GetApplicableRules(clusterId, envId, appId int, isAppstore bool) ([]*security.CvePolicyRule, error) {
+	policyLevel, err := security.GetApplicablePolicyLevel(clusterId, envId, appId, isAppstore)
+	if err != nil {
+		return nil, err
+	}
+	return impl.getRules(policyLevel, clusterId, envId, appId)
+}
+
+func (impl *PolicyServiceImpl) getRules(policyLevel security.PolicyLevel, clusterId, envId, appId int) ([]*security.CvePolicyRule, error) {
+	rules, err := impl.cvePolicyRuleRepository.GetRules(policyLevel, clusterId, envId, appId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching policy rules", "level", policyLevel, "err", err)
+		return nil, err
+	}
+	return security.GetApplicableRules(rules), nil
+}
+
+func (impl *PolicyServiceImpl) vulnerabilityPolicyRuleBuilder(policyLevel security.PolicyLevel, rules []*security.CvePolicyRule) []*bean.VulnerabilityPolicyRule {
+	var policyRules []*bean.VulnerabilityPolicyRule
+	for _, rule := range rules {
+		policyRule := &bean.VulnerabilityPolicyRule{
+			Id:           rule.Id,
+			ClusterId:    rule.ClusterId,
+			EnvId:        rule.EnvironmentId,
+			AppId:        rule.AppId,
+			RuleType:     bean.VulnerabilityPolicyRuleType(rule.RuleType),
+			Action:       bean.VulnerabilityAction(rule.Action.String()),
+			Package:      rule.Package,
+			Threshold:    rule.Threshold,
+			PolicyOrigin: rule.PolicyLevel().String(),
+			Inherited:    rule.PolicyLevel() != policyLevel,
+		}
+		if rule.Severity != nil {
+			policyRule.Severity = rule.Severity.String()
+		}
+		policyRules = append(policyRules, policyRule)
+	}
+	return policyRules
+}
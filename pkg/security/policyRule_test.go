@@ -0,0 +1,149 @@
+package security
+
+import (
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository/security"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestValidateVulnerabilityPolicyRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    *bean.VulnerabilityPolicyRule
+		wantErr bool
+	}{
+		{name: "fix available", rule: &bean.VulnerabilityPolicyRule{RuleType: bean.VulnerabilityPolicyRuleFixAvailable, Action: "block", Severity: "critical"}, wantErr: false},
+		{name: "fix available without severity", rule: &bean.VulnerabilityPolicyRule{RuleType: bean.VulnerabilityPolicyRuleFixAvailable, Action: "block"}, wantErr: true},
+		{name: "fix available allow", rule: &bean.VulnerabilityPolicyRule{RuleType: bean.VulnerabilityPolicyRuleFixAvailable, Action: "allow", Severity: "critical"}, wantErr: true},
+		{name: "package", rule: &bean.VulnerabilityPolicyRule{RuleType: bean.VulnerabilityPolicyRulePackage, Action: "allow", Package: "openssl"}, wantErr: false},
+		{name: "package without name", rule: &bean.VulnerabilityPolicyRule{RuleType: bean.VulnerabilityPolicyRulePackage, Action: "allow", Package: " "}, wantErr: true},
+		{name: "threshold", rule: &bean.VulnerabilityPolicyRule{RuleType: bean.VulnerabilityPolicyRuleThreshold, Action: "block", Severity: "moderate", Threshold: 10}, wantErr: false},
+		{name: "negative threshold", rule: &bean.VulnerabilityPolicyRule{RuleType: bean.VulnerabilityPolicyRuleThreshold, Action: "block", Severity: "moderate", Threshold: -1}, wantErr: true},
+		{name: "unsupported severity", rule: &bean.VulnerabilityPolicyRule{RuleType: bean.VulnerabilityPolicyRuleThreshold, Action: "block", Severity: "high"}, wantErr: true},
+		{name: "unsupported rule type", rule: &bean.VulnerabilityPolicyRule{RuleType: "LICENSE", Action: "block"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateVulnerabilityPolicyRule(tt.rule); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateVulnerabilityPolicyRule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEnforceCvePolicyWithRules(t *testing.T) {
+	now := time.Date(2022, 8, 8, 10, 0, 0, 0, time.UTC)
+	critical, moderate, low := security.Critical, security.Moderate, security.Low
+	cves := []*security.CveStore{
+		{Name: "CVE-1", Severity: security.Critical, Package: "openssl", FixedVersion: "1.1.1q"},
+		{Name: "CVE-2", Severity: security.Critical, Package: "libc"},
+		{Name: "CVE-3", Severity: security.Critical, Package: "zlib", FixedVersion: "1.2.12"},
+		{Name: "CVE-4", Severity: security.Moderate, Package: "curl"},
+		{Name: "CVE-5", Severity: security.Moderate, Package: "curl"},
+		{Name: "CVE-6", Severity: security.Low, Package: "bash"},
+	}
+	severityPolicy := map[security.Severity]*security.CvePolicy{
+		security.Critical: {Action: security.Block, Severity: &critical},
+		security.Moderate: {Action: security.Allow, Severity: &moderate},
+		security.Low:      {Action: security.Allow, Severity: &low},
+	}
+	tests := []struct {
+		name      string
+		cvePolicy map[string]*security.CvePolicy
+		rules     []*security.CvePolicyRule
+		want      []string
+	}{
+		{name: "severity policy only", want: []string{"CVE-1:SEVERITY_POLICY", "CVE-2:SEVERITY_POLICY", "CVE-3:SEVERITY_POLICY"}},
+		{
+			name:  "fix available",
+			rules: []*security.CvePolicyRule{{RuleType: security.CvePolicyRuleFixAvailable, Action: security.Block, Severity: &critical}},
+			want:  []string{"CVE-1:FIX_AVAILABLE(severity=critical)", "CVE-3:FIX_AVAILABLE(severity=critical)"},
+		},
+		{
+			name: "package allowed over fix available",
+			rules: []*security.CvePolicyRule{
+				{RuleType: security.CvePolicyRuleFixAvailable, Action: security.Block, Severity: &critical},
+				{RuleType: security.CvePolicyRulePackage, Action: security.Allow, Package: "zlib"},
+			},
+			want: []string{"CVE-1:FIX_AVAILABLE(severity=critical)"},
+		},
+		{
+			name:      "cve policy over package",
+			cvePolicy: map[string]*security.CvePolicy{"CVE-3": {Action: security.Block, CVEStoreId: "CVE-3"}},
+			rules:     []*security.CvePolicyRule{{RuleType: security.CvePolicyRulePackage, Action: security.Allow, Package: "zlib"}},
+			want:      []string{"CVE-1:SEVERITY_POLICY", "CVE-2:SEVERITY_POLICY", "CVE-3:CVE_POLICY"},
+		},
+		{
+			name: "severity specific package rule",
+			rules: []*security.CvePolicyRule{
+				{RuleType: security.CvePolicyRulePackage, Action: security.Allow, Package: "curl"},
+				{RuleType: security.CvePolicyRulePackage, Action: security.Block, Package: "curl", Severity: &moderate},
+			},
+			want: []string{"CVE-1:SEVERITY_POLICY", "CVE-2:SEVERITY_POLICY", "CVE-3:SEVERITY_POLICY", "CVE-4:PACKAGE(package=curl,severity=moderate)", "CVE-5:PACKAGE(package=curl,severity=moderate)"},
+		},
+		{
+			name:  "threshold exceeded",
+			rules: []*security.CvePolicyRule{{RuleType: security.CvePolicyRuleThreshold, Action: security.Block, Severity: &moderate, Threshold: 1}},
+			want:  []string{"CVE-1:SEVERITY_POLICY", "CVE-2:SEVERITY_POLICY", "CVE-3:SEVERITY_POLICY", "CVE-4:THRESHOLD(severity=moderate,threshold=1)", "CVE-5:THRESHOLD(severity=moderate,threshold=1)"},
+		},
+		{
+			name: "threshold not exceeded after package allow",
+			rules: []*security.CvePolicyRule{
+				{RuleType: security.CvePolicyRuleThreshold, Action: security.Block, Severity: &moderate, Threshold: 1},
+				{RuleType: security.CvePolicyRulePackage, Action: security.Allow, Package: "curl", Severity: &moderate},
+			},
+			want: []string{"CVE-1:SEVERITY_POLICY", "CVE-2:SEVERITY_POLICY", "CVE-3:SEVERITY_POLICY"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, blockedCve := range security.EnforceCvePolicy(cves, tt.cvePolicy, severityPolicy, tt.rules, now) {
+				got = append(got, blockedCve.Cve.Name+":"+blockedCve.Rule)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EnforceCvePolicy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetApplicableRules(t *testing.T) {
+	critical := security.Critical
+	globalRule := &security.CvePolicyRule{Id: 1, Global: true, RuleType: security.CvePolicyRuleFixAvailable, Severity: &critical}
+	envRule := &security.CvePolicyRule{Id: 2, EnvironmentId: 3, RuleType: security.CvePolicyRuleFixAvailable, Severity: &critical}
+	packageRule := &security.CvePolicyRule{Id: 3, Global: true, RuleType: security.CvePolicyRulePackage, Package: "openssl"}
+	got := security.GetApplicableRules([]*security.CvePolicyRule{globalRule, packageRule, envRule})
+	want := []*security.CvePolicyRule{envRule, packageRule}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetApplicableRules() = %v, want %v", got, want)
+	}
+}
+
+func TestHasBlockedCVEWithRules(t *testing.T) {
+	moderate := security.Moderate
+	cves := []*security.CveStore{{Name: "CVE-1", Severity: security.Moderate, Package: "openssl", FixedVersion: "1.1.1q"}}
+	severityPolicy := map[security.Severity]*security.CvePolicy{
+		security.Moderate: {Action: security.Allow, Severity: &moderate},
+	}
+	tests := []struct {
+		name  string
+		rules []*security.CvePolicyRule
+		want  bool
+	}{
+		{name: "allowed by severity policy", want: false},
+		{name: "blocked by fix available rule", rules: []*security.CvePolicyRule{{RuleType: security.CvePolicyRuleFixAvailable, Action: security.Block, Severity: &moderate}}, want: true},
+		{name: "blocked by package rule", rules: []*security.CvePolicyRule{{RuleType: security.CvePolicyRulePackage, Action: security.Block, Package: "openssl"}}, want: true},
+		{name: "blocked by threshold rule", rules: []*security.CvePolicyRule{{RuleType: security.CvePolicyRuleThreshold, Action: security.Block, Severity: &moderate, Threshold: 0}}, want: true},
+	}
+	impl := &PolicyServiceImpl{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := impl.HasBlockedCVE(cves, nil, severityPolicy, tt.rules); got != tt.want {
+				t.Errorf("HasBlockedCVE() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
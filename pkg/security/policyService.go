@@ -28,12 +28,14 @@ import (
 	"time"
 
 	"github.com/devtron-labs/devtron/api/bean"
+	client "github.com/devtron-labs/devtron/client/events"
 	"github.com/devtron-labs/devtron/internal/sql/repository"
 	"github.com/devtron-labs/devtron/internal/sql/repository/chartConfig"
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
 	"github.com/devtron-labs/devtron/internal/sql/repository/security"
 	"github.com/devtron-labs/devtron/pkg/cluster"
 	"github.com/devtron-labs/devtron/pkg/pipeline"
+	"github.com/devtron-labs/devtron/pkg/sbom"
 	"github.com/go-pg/pg"
 	"go.uber.org/zap"
 )
@@ -47,7 +49,19 @@ type PolicyService interface {
 	VerifyImage(verifyImageRequest *VerifyImageRequest) (map[string][]*VerifyImageResponse, error)
 	GetCvePolicy(id int, userId int32) (*security.CvePolicy, error)
 	GetApplicablePolicy(clusterId, envId, appId int, isAppstore bool) (map[string]*security.CvePolicy, map[security.Severity]*security.CvePolicy, error)
-	HasBlockedCVE(cves []*security.CveStore, cvePolicy map[string]*security.CvePolicy, severityPolicy map[security.Severity]*security.CvePolicy) bool
+	GetApplicableRules(clusterId, envId, appId int, isAppstore bool) ([]*security.CvePolicyRule, error)
+	HasBlockedCVE(cves []*security.CveStore, cvePolicy map[string]*security.CvePolicy, severityPolicy map[security.Severity]*security.CvePolicy, rules []*security.CvePolicyRule) bool
+	NotifyExpiringCveExceptions(notifyBefore time.Duration) error
+	SendEventToClairUtility(event *ScanEvent) error
+	SavePolicyRule(request *bean.VulnerabilityPolicyRule, userId int32) (*bean.IdVulnerabilityPolicyResult, error)
+	DeletePolicyRule(id int, userId int32) (*bean.IdVulnerabilityPolicyResult, error)
+	GetPolicyRule(id int) (*security.CvePolicyRule, error)
+	SaveLicensePolicy(request *bean.LicensePolicy, userId int32) (*bean.IdVulnerabilityPolicyResult, error)
+	DeleteLicensePolicy(id int, userId int32) (*bean.IdVulnerabilityPolicyResult, error)
+	GetLicensePolicy(id int) (*security.LicensePolicy, error)
+	SaveManifestScanPolicy(request *bean.ManifestScanPolicy, userId int32) (*bean.IdVulnerabilityPolicyResult, error)
+	DeleteManifestScanPolicy(id int, userId int32) (*bean.IdVulnerabilityPolicyResult, error)
+	GetManifestScanPolicy(id int) (*security.ManifestScanPolicy, error)
 }
 type PolicyServiceImpl struct {
 	environmentService            cluster.EnvironmentService
@@ -66,6 +80,12 @@ type PolicyServiceImpl struct {
 	scanHistoryRepository         security.ImageScanHistoryRepository
 	cveStoreRepository            security.CveStoreRepository
 	ciTemplateRepository          pipelineConfig.CiTemplateRepository
+	cvePolicyRuleRepository       security.CvePolicyRuleRepository
+	eventClient                   client.EventClient
+	eventFactory                  client.EventFactory
+	licensePolicyRepository       security.LicensePolicyRepository
+	sbomRepository                sbom.SbomRepository
+	manifestScanPolicyRepository  security.ManifestScanPolicyRepository
 }
 
 func NewPolicyServiceImpl(environmentService cluster.EnvironmentService,
@@ -80,7 +100,10 @@ func NewPolicyServiceImpl(environmentService cluster.EnvironmentService,
 	imageScanObjectMetaRepository security.ImageScanObjectMetaRepository, client *http.Client,
 	ciArtifactRepository repository.CiArtifactRepository, ciConfig *pipeline.CiConfig,
 	scanHistoryRepository security.ImageScanHistoryRepository, cveStoreRepository security.CveStoreRepository,
-	ciTemplateRepository pipelineConfig.CiTemplateRepository) *PolicyServiceImpl {
+	ciTemplateRepository pipelineConfig.CiTemplateRepository, eventClient client.EventClient,
+	eventFactory client.EventFactory, cvePolicyRuleRepository security.CvePolicyRuleRepository,
+	licensePolicyRepository security.LicensePolicyRepository, sbomRepository sbom.SbomRepository,
+	manifestScanPolicyRepository security.ManifestScanPolicyRepository) *PolicyServiceImpl {
 	return &PolicyServiceImpl{
 		environmentService:            environmentService,
 		logger:                        logger,
@@ -98,6 +121,12 @@ func NewPolicyServiceImpl(environmentService cluster.EnvironmentService,
 		scanHistoryRepository:         scanHistoryRepository,
 		cveStoreRepository:            cveStoreRepository,
 		ciTemplateRepository:          ciTemplateRepository,
+		eventClient:                   eventClient,
+		eventFactory:                  eventFactory,
+		cvePolicyRuleRepository:       cvePolicyRuleRepository,
+		licensePolicyRepository:       licensePolicyRepository,
+		sbomRepository:                sbomRepository,
+		manifestScanPolicyRepository:  manifestScanPolicyRepository,
 	}
 }
 
@@ -127,6 +156,8 @@ type VerifyImageResponse struct {
 	Package      string
 	Version      string
 	FixedVersion string
+	License      string
+	Rule         string
 }
 
 type ScanEvent struct {
@@ -161,8 +192,13 @@ func (impl *PolicyServiceImpl) SendEventToClairUtility(event *ScanEvent) error {
 		impl.logger.Errorw("error while UpdateJiraTransition request ", "err", err)
 		return err
 	}
+	defer resp.Body.Close()
 	impl.logger.Debugw("response from test suit create api", "status code", resp.StatusCode)
-	return err
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		impl.logger.Errorw("image scanner rejected scan event", "status code", resp.StatusCode, "image", event.Image)
+		return fmt.Errorf("image scanner responded with status %d", resp.StatusCode)
+	}
+	return nil
 }
 
 func (impl *PolicyServiceImpl) VerifyImage(verifyImageRequest *VerifyImageRequest) (map[string][]*VerifyImageResponse, error) {
@@ -199,6 +235,15 @@ func (impl *PolicyServiceImpl) VerifyImage(verifyImageRequest *VerifyImageReques
 	if err != nil {
 		impl.logger.Errorw("error in generating applicable policy", "err", err)
 	}
+	rules, err := impl.GetApplicableRules(clusterId, envId, appId, isAppStore)
+	if err != nil {
+		impl.logger.Errorw("error in generating applicable policy rules", "err", err)
+	}
+	licensePolicy, err := impl.getApplicableLicensePolicies(clusterId, envId, appId, isAppStore)
+	if err != nil {
+		impl.logger.Errorw("error in generating applicable license policy", "err", err)
+		return nil, err
+	}
 
 	var objectType string
 	var typeId int
@@ -249,15 +294,36 @@ func (impl *PolicyServiceImpl) VerifyImage(verifyImageRequest *VerifyImageReques
 				scanResultsIdMap[scanResult.ImageScanExecutionHistoryId] = scanResult.ImageScanExecutionHistoryId
 			}
 		}
-		blockedCves := impl.enforceCvePolicy(cveStores, cvePolicy, severityPolicy)
+		blockedCves := impl.enforceCvePolicy(cveStores, cvePolicy, severityPolicy, rules)
 		impl.logger.Debugw("blocked cve for image", "image", image, "blocked", blockedCves)
-		for _, cve := range blockedCves {
+		for _, blockedCve := range blockedCves {
+			cve := blockedCve.Cve
 			vr := &VerifyImageResponse{
 				Name:         cve.Name,
 				Severity:     cve.Severity.String(),
 				Package:      cve.Package,
 				Version:      cve.Version,
 				FixedVersion: cve.FixedVersion,
+				Rule:         blockedCve.Rule,
+			}
+			imageBlockedCves[image] = append(imageBlockedCves[image], vr)
+		}
+		//sbom is looked up by digest as the deploy gate does, a tag may since point to another image
+		imageDigest, err := impl.getImageDigest(image)
+		if err != nil {
+			return nil, err
+		}
+		blockedLicenses, err := impl.getBlockedLicenses(imageDigest, licensePolicy)
+		if err != nil {
+			return nil, err
+		}
+		for _, blockedLicense := range blockedLicenses {
+			vr := &VerifyImageResponse{
+				Name:    strings.Join(blockedLicense.Licenses, ","),
+				Package: blockedLicense.Package.Package,
+				Version: blockedLicense.Package.Version,
+				License: blockedLicense.Package.License,
+				Rule:    blockedLicense.Rule,
 			}
 			imageBlockedCves[image] = append(imageBlockedCves[image], vr)
 		}
@@ -328,24 +394,8 @@ func (impl *PolicyServiceImpl) VerifyImage(verifyImageRequest *VerifyImageReques
 }
 
 //image(cve), appId, envId
-func (impl *PolicyServiceImpl) enforceCvePolicy(cves []*security.CveStore, cvePolicy map[string]*security.CvePolicy, severityPolicy map[security.Severity]*security.CvePolicy) (blockedCVE []*security.CveStore) {
-
-	for _, cve := range cves {
-		if policy, ok := cvePolicy[cve.Name]; ok {
-			if policy.Action == security.Allow {
-				continue
-			} else {
-				blockedCVE = append(blockedCVE, cve)
-			}
-		} else {
-			if severityPolicy[cve.Severity] != nil && severityPolicy[cve.Severity].Action == security.Allow {
-				continue
-			} else {
-				blockedCVE = append(blockedCVE, cve)
-			}
-		}
-	}
-	return blockedCVE
+func (impl *PolicyServiceImpl) enforceCvePolicy(cves []*security.CveStore, cvePolicy map[string]*security.CvePolicy, severityPolicy map[security.Severity]*security.CvePolicy, rules []*security.CvePolicyRule) []*security.BlockedCve {
+	return security.EnforceCvePolicy(cves, cvePolicy, severityPolicy, rules, time.Now())
 }
 
 func (impl *PolicyServiceImpl) GetApplicablePolicy(clusterId, envId, appId int, isAppstore bool) (map[string]*security.CvePolicy, map[security.Severity]*security.CvePolicy, error) {
@@ -370,7 +420,12 @@ func (impl *PolicyServiceImpl) GetApplicablePolicy(clusterId, envId, appId int,
 func (impl *PolicyServiceImpl) getApplicablePolicies(policies []*security.CvePolicy) (map[string]*security.CvePolicy, map[security.Severity]*security.CvePolicy) {
 	cvePolicy := make(map[string][]*security.CvePolicy)
 	severityPolicy := make(map[security.Severity][]*security.CvePolicy)
+	now := time.Now()
 	for _, policy := range policies {
+		//expired exceptions fall back to the policy inherited from higher level
+		if policy.IsExpired(now) {
+			continue
+		}
 		if policy.CVEStoreId != "" {
 			cvePolicy[policy.CveStore.Name] = append(cvePolicy[policy.CveStore.Name], policy)
 		} else {
@@ -473,6 +528,10 @@ func (impl *PolicyServiceImpl) SavePolicy(request bean.CreateVulnerabilityPolicy
 		}
 		severity = cveStore.Severity
 	}
+	err = ValidateCvePolicyException(action, request.Exception, time.Now())
+	if err != nil {
+		return nil, err
+	}
 	policy := &security.CvePolicy{
 		Global:        isGlobal,
 		ClusterId:     request.ClusterId,
@@ -488,6 +547,7 @@ func (impl *PolicyServiceImpl) SavePolicy(request bean.CreateVulnerabilityPolicy
 			UpdatedBy: userId,
 		},
 	}
+	setCvePolicyException(policy, request.Exception)
 	policy, err = impl.cvePolicyRepository.SavePolicy(policy)
 	if err != nil {
 		impl.logger.Errorw("error in saving policy", "err", err)
@@ -513,7 +573,12 @@ func (impl *PolicyServiceImpl) UpdatePolicy(updatePolicyParams bean.UpdatePolicy
 			impl.logger.Errorw("error in fetching policy ", "id", updatePolicyParams.Id)
 			return nil, err
 		}
+		err = ValidateCvePolicyException(policyAction, updatePolicyParams.Exception, time.Now())
+		if err != nil {
+			return nil, err
+		}
 		policy.Action = policyAction
+		setCvePolicyException(policy, updatePolicyParams.Exception)
 		policy.UpdatedOn = time.Now()
 		policy.UpdatedBy = userId
 		policy, err = impl.cvePolicyRepository.UpdatePolicy(policy)
@@ -568,7 +633,22 @@ func (impl *PolicyServiceImpl) GetPolicies(policyLevel security.PolicyLevel, clu
 		if err != nil {
 			return nil, err
 		}
+		rules, err := impl.getRules(policyLevel, clusterId, environmentId, appId)
+		if err != nil {
+			return nil, err
+		}
+		licensePolicies, err := impl.getLicensePolicies(policyLevel, clusterId, environmentId, appId)
+		if err != nil {
+			return nil, err
+		}
+		manifestScanPolicies, err := impl.getManifestScanPolicies(policyLevel, clusterId, environmentId, appId)
+		if err != nil {
+			return nil, err
+		}
 		vulnerabilityPolicy := impl.vulnerabilityPolicyBuilder(policyLevel, cvePolicy, severityPolicy)
+		vulnerabilityPolicy.Rules = impl.vulnerabilityPolicyRuleBuilder(policyLevel, rules)
+		vulnerabilityPolicy.Licenses = impl.licensePolicyBuilder(policyLevel, licensePolicies)
+		vulnerabilityPolicy.ManifestScanPolicies = impl.manifestScanPolicyBuilder(policyLevel, manifestScanPolicies)
 		vulnerabilityPolicyResult.Policies = append(vulnerabilityPolicyResult.Policies, vulnerabilityPolicy)
 	} else if policyLevel == security.Cluster {
 		if clusterId == 0 {
@@ -585,7 +665,22 @@ func (impl *PolicyServiceImpl) GetPolicies(policyLevel security.PolicyLevel, clu
 		if err != nil {
 			return nil, err
 		}
+		rules, err := impl.getRules(policyLevel, clusterId, environmentId, appId)
+		if err != nil {
+			return nil, err
+		}
+		licensePolicies, err := impl.getLicensePolicies(policyLevel, clusterId, environmentId, appId)
+		if err != nil {
+			return nil, err
+		}
+		manifestScanPolicies, err := impl.getManifestScanPolicies(policyLevel, clusterId, environmentId, appId)
+		if err != nil {
+			return nil, err
+		}
 		vulnerabilityPolicy := impl.vulnerabilityPolicyBuilder(policyLevel, cvePolicy, severityPolicy)
+		vulnerabilityPolicy.Rules = impl.vulnerabilityPolicyRuleBuilder(policyLevel, rules)
+		vulnerabilityPolicy.Licenses = impl.licensePolicyBuilder(policyLevel, licensePolicies)
+		vulnerabilityPolicy.ManifestScanPolicies = impl.manifestScanPolicyBuilder(policyLevel, manifestScanPolicies)
 		vulnerabilityPolicy.Name = cluster.ClusterName
 		vulnerabilityPolicy.ClusterId = clusterId
 		vulnerabilityPolicyResult.Policies = append(vulnerabilityPolicyResult.Policies, vulnerabilityPolicy)
@@ -603,7 +698,22 @@ func (impl *PolicyServiceImpl) GetPolicies(policyLevel security.PolicyLevel, clu
 		if err != nil {
 			return nil, err
 		}
+		rules, err := impl.getRules(policyLevel, clusterId, environmentId, appId)
+		if err != nil {
+			return nil, err
+		}
+		licensePolicies, err := impl.getLicensePolicies(policyLevel, clusterId, environmentId, appId)
+		if err != nil {
+			return nil, err
+		}
+		manifestScanPolicies, err := impl.getManifestScanPolicies(policyLevel, clusterId, environmentId, appId)
+		if err != nil {
+			return nil, err
+		}
 		vulnerabilityPolicy := impl.vulnerabilityPolicyBuilder(policyLevel, cvePolicy, severityPolicy)
+		vulnerabilityPolicy.Rules = impl.vulnerabilityPolicyRuleBuilder(policyLevel, rules)
+		vulnerabilityPolicy.Licenses = impl.licensePolicyBuilder(policyLevel, licensePolicies)
+		vulnerabilityPolicy.ManifestScanPolicies = impl.manifestScanPolicyBuilder(policyLevel, manifestScanPolicies)
 		vulnerabilityPolicy.Name = env.Environment
 		vulnerabilityPolicy.EnvId = env.Id
 		vulnerabilityPolicyResult.Policies = append(vulnerabilityPolicyResult.Policies, vulnerabilityPolicy)
@@ -631,7 +741,22 @@ func (impl *PolicyServiceImpl) GetPolicies(policyLevel security.PolicyLevel, clu
 			if err != nil {
 				return nil, err
 			}
+			rules, err := impl.getRules(policyLevel, env.ClusterId, env.Id, appId)
+			if err != nil {
+				return nil, err
+			}
+			licensePolicies, err := impl.getLicensePolicies(policyLevel, env.ClusterId, env.Id, appId)
+			if err != nil {
+				return nil, err
+			}
+			manifestScanPolicies, err := impl.getManifestScanPolicies(policyLevel, env.ClusterId, env.Id, appId)
+			if err != nil {
+				return nil, err
+			}
 			vulnerabilityPolicy := impl.vulnerabilityPolicyBuilder(policyLevel, cvePolicy, severityPolicy)
+			vulnerabilityPolicy.Rules = impl.vulnerabilityPolicyRuleBuilder(policyLevel, rules)
+			vulnerabilityPolicy.Licenses = impl.licensePolicyBuilder(policyLevel, licensePolicies)
+			vulnerabilityPolicy.ManifestScanPolicies = impl.manifestScanPolicyBuilder(policyLevel, manifestScanPolicies)
 			vulnerabilityPolicy.Name = fmt.Sprintf("%s/%s", app.AppName, env.Environment)
 			vulnerabilityPolicy.EnvId = env.Id
 			vulnerabilityPolicy.AppId = appId
@@ -654,6 +779,7 @@ func (impl *PolicyServiceImpl) vulnerabilityPolicyBuilder(policyLevel security.P
 			},
 			PolicyOrigin: v.PolicyLevel().String(),
 			Severity:     v.Severity.String(),
+			Exception:    getCvePolicyException(v),
 		}
 		vulnerabilityPolicy.Severities = append(vulnerabilityPolicy.Severities, severityPolicy)
 	}
@@ -668,6 +794,7 @@ func (impl *PolicyServiceImpl) vulnerabilityPolicyBuilder(policyLevel security.P
 				},
 				PolicyOrigin: v.PolicyLevel().String(),
 				Severity:     v.Severity.String(),
+				Exception:    getCvePolicyException(v),
 			},
 			Name: v.CVEStoreId,
 		}
@@ -706,27 +833,20 @@ func (impl *PolicyServiceImpl) GetBlockedCVEList(cves []*security.CveStore, clus
 	if err != nil {
 		return nil, err
 	}
-	blockedCve := impl.enforceCvePolicy(cves, cvePolicy, severityPolicy)
-	return blockedCve, nil
+	rules, err := impl.GetApplicableRules(clusterId, envId, appId, isAppstore)
+	if err != nil {
+		return nil, err
+	}
+	var blockedCveList []*security.CveStore
+	for _, blockedCve := range impl.enforceCvePolicy(cves, cvePolicy, severityPolicy, rules) {
+		blockedCveList = append(blockedCveList, blockedCve.Cve)
+	}
+	return blockedCveList, nil
 }
 
-func (impl *PolicyServiceImpl) HasBlockedCVE(cves []*security.CveStore, cvePolicy map[string]*security.CvePolicy, severityPolicy map[security.Severity]*security.CvePolicy) bool {
-	for _, cve := range cves {
-		if policy, ok := cvePolicy[cve.Name]; ok {
-			if policy.Action == security.Allow {
-				continue
-			} else {
-				return true
-			}
-		} else {
-			if severityPolicy[cve.Severity] != nil && severityPolicy[cve.Severity].Action == security.Allow {
-				continue
-			} else {
-				return true
-			}
-		}
-	}
-	return false
+// HasBlockedCVE returns true when policies and rules block any of the cves, same as deploy gate
+func (impl *PolicyServiceImpl) HasBlockedCVE(cves []*security.CveStore, cvePolicy map[string]*security.CvePolicy, severityPolicy map[security.Severity]*security.CvePolicy, rules []*security.CvePolicyRule) bool {
+	return len(impl.enforceCvePolicy(cves, cvePolicy, severityPolicy, rules)) > 0
 }
 
 func (impl *PolicyServiceImpl) GetCvePolicy(id int, userId int32) (*security.CvePolicy, error) {
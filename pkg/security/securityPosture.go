@@ -0,0 +1,397 @@
+package security
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/sql/repository/app"
+	"github.com/devtron-labs/devtron/internal/sql/repository/security"
+	"github.com/go-pg/pg"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultSecurityPostureDays = 30
+	maxSecurityPostureDays     = 365
+	securityPostureBatchSize   = 500
+	securityPostureDateLayout  = "2006-01-02"
+)
+
+type SecurityPostureRequest struct {
+	ScopeType security.PostureScopeType `json:"scopeType"`
+	ScopeIds  []int                     `json:"scopeIds"`
+	Days      int                       `json:"days"`
+}
+
+type SecurityPostureResponse struct {
+	ScopeType security.PostureScopeType `json:"scopeType"`
+	Days      int                       `json:"days"`
+	Scopes    []*ScopeSecurityPosture   `json:"scopes"`
+}
+
+// ScopeSecurityPosture is the open cves of a team, app, environment or cluster as of the latest snapshot along with
+// remediation and daily trend over the requested days
+type ScopeSecurityPosture struct {
+	ScopeId                  int                     `json:"scopeId"`
+	ScopeName                string                  `json:"scopeName"`
+	Critical                 int                     `json:"critical"`
+	Moderate                 int                     `json:"moderate"`
+	Low                      int                     `json:"low"`
+	Total                    int                     `json:"total"`
+	RemediatedCount          int                     `json:"remediatedCount"`
+	MeanTimeToRemediateHours float64                 `json:"meanTimeToRemediateHours"`
+	Trend                    []*SecurityPostureTrend `json:"trend"`
+}
+
+type SecurityPostureTrend struct {
+	Date       string `json:"date"`
+	Critical   int    `json:"critical"`
+	Moderate   int    `json:"moderate"`
+	Low        int    `json:"low"`
+	Remediated int    `json:"remediated"`
+}
+
+// SecurityPostureScopeNames resolves names of scopes while materialising snapshots
+type SecurityPostureScopeNames map[security.PostureScopeType]map[int]string
+
+func postureFindingKey(deployInfoId int, cveName string) string {
+	return strconv.Itoa(deployInfoId) + "/" + cveName
+}
+
+// GetSecurityPostureFindings lists the cves of every deployed object from the latest scans of its images, a cve is first
+// seen at the earliest scan reporting it
+func GetSecurityPostureFindings(deployInfos []*security.ImageScanDeployInfo, resultsByHistoryId map[int][]*security.ImageScanExecutionResult, teamIdByAppId map[int]int, now time.Time) []*security.SecurityPostureFinding {
+	var findings []*security.SecurityPostureFinding
+	for _, deployInfo := range deployInfos {
+		findingByCve := make(map[string]*security.SecurityPostureFinding)
+		var cveNames []string
+		for _, historyId := range deployInfo.ImageScanExecutionHistoryId {
+			for _, result := range resultsByHistoryId[historyId] {
+				firstSeenOn := result.ImageScanExecutionHistory.ExecutionTime
+				if firstSeenOn.IsZero() {
+					firstSeenOn = now
+				}
+				finding, ok := findingByCve[result.CveStoreName]
+				if !ok {
+					finding = &security.SecurityPostureFinding{
+						ImageScanDeployInfoId: deployInfo.Id,
+						EnvId:                 deployInfo.EnvId,
+						ClusterId:             deployInfo.ClusterId,
+						CveStoreName:          result.CveStoreName,
+						Severity:              result.CveStore.Severity,
+						FirstSeenOn:           firstSeenOn,
+					}
+					if deployInfo.ObjectType != security.ScanObjectType_POD {
+						finding.AppId = deployInfo.ScanObjectMetaId
+						finding.TeamId = teamIdByAppId[deployInfo.ScanObjectMetaId]
+					}
+					findingByCve[result.CveStoreName] = finding
+					cveNames = append(cveNames, result.CveStoreName)
+				} else if firstSeenOn.Before(finding.FirstSeenOn) {
+					finding.FirstSeenOn = firstSeenOn
+				}
+			}
+		}
+		for _, cveName := range cveNames {
+			findings = append(findings, findingByCve[cveName])
+		}
+	}
+	return findings
+}
+
+// DiffSecurityPostureFindings returns current findings which are not open yet, and open findings which are no longer
+// current marked remediated
+func DiffSecurityPostureFindings(openFindings []*security.SecurityPostureFinding, currentFindings []*security.SecurityPostureFinding, now time.Time) ([]*security.SecurityPostureFinding, []*security.SecurityPostureFinding) {
+	open := make(map[string]bool)
+	for _, finding := range openFindings {
+		open[postureFindingKey(finding.ImageScanDeployInfoId, finding.CveStoreName)] = true
+	}
+	current := make(map[string]bool)
+	var newFindings []*security.SecurityPostureFinding
+	for _, finding := range currentFindings {
+		key := postureFindingKey(finding.ImageScanDeployInfoId, finding.CveStoreName)
+		current[key] = true
+		if !open[key] {
+			newFindings = append(newFindings, finding)
+		}
+	}
+	var remediatedFindings []*security.SecurityPostureFinding
+	for _, finding := range openFindings {
+		if !current[postureFindingKey(finding.ImageScanDeployInfoId, finding.CveStoreName)] {
+			finding.RemediatedOn = now
+			remediatedFindings = append(remediatedFindings, finding)
+		}
+	}
+	return newFindings, remediatedFindings
+}
+
+type postureScope struct {
+	scopeType security.PostureScopeType
+	scopeId   int
+}
+
+func postureScopesOf(finding *security.SecurityPostureFinding) []postureScope {
+	var scopes []postureScope
+	if finding.TeamId > 0 {
+		scopes = append(scopes, postureScope{security.PostureScopeTeam, finding.TeamId})
+	}
+	if finding.AppId > 0 {
+		scopes = append(scopes, postureScope{security.PostureScopeApp, finding.AppId})
+	}
+	if finding.EnvId > 0 {
+		scopes = append(scopes, postureScope{security.PostureScopeEnvironment, finding.EnvId})
+	}
+	if finding.ClusterId > 0 {
+		scopes = append(scopes, postureScope{security.PostureScopeCluster, finding.ClusterId})
+	}
+	return scopes
+}
+
+// BuildSecurityPostureSnapshots counts distinct open cves of every scope by severity, along with count and hours to
+// remediate of findings remediated on the day
+func BuildSecurityPostureSnapshots(snapshotDate time.Time, openFindings []*security.SecurityPostureFinding, remediatedFindings []*security.SecurityPostureFinding, scopeNames SecurityPostureScopeNames, now time.Time) []*security.SecurityPostureSnapshot {
+	snapshotByScope := make(map[postureScope]*security.SecurityPostureSnapshot)
+	var scopes []postureScope
+	snapshotOf := func(scope postureScope) *security.SecurityPostureSnapshot {
+		snapshot, ok := snapshotByScope[scope]
+		if !ok {
+			snapshot = &security.SecurityPostureSnapshot{
+				SnapshotDate: snapshotDate,
+				ScopeType:    scope.scopeType,
+				ScopeId:      scope.scopeId,
+				ScopeName:    scopeNames[scope.scopeType][scope.scopeId],
+				CreatedOn:    now,
+			}
+			snapshotByScope[scope] = snapshot
+			scopes = append(scopes, scope)
+		}
+		return snapshot
+	}
+	counted := make(map[postureScope]map[string]bool)
+	for _, finding := range openFindings {
+		for _, scope := range postureScopesOf(finding) {
+			snapshot := snapshotOf(scope)
+			if counted[scope] == nil {
+				counted[scope] = make(map[string]bool)
+			}
+			if counted[scope][finding.CveStoreName] {
+				continue
+			}
+			counted[scope][finding.CveStoreName] = true
+			switch finding.Severity {
+			case security.Critical:
+				snapshot.CriticalCount++
+			case security.Moderate:
+				snapshot.ModerateCount++
+			default:
+				snapshot.LowCount++
+			}
+		}
+	}
+	for _, finding := range remediatedFindings {
+		for _, scope := range postureScopesOf(finding) {
+			snapshot := snapshotOf(scope)
+			snapshot.RemediatedCount++
+			snapshot.RemediationHours += finding.RemediatedOn.Sub(finding.FirstSeenOn).Hours()
+		}
+	}
+	var snapshots []*security.SecurityPostureSnapshot
+	for _, scope := range scopes {
+		snapshots = append(snapshots, snapshotByScope[scope])
+	}
+	return snapshots
+}
+
+// BuildSecurityPosture aggregates snapshots of the last days per scope, open cves are taken from the latest snapshot
+// and days without snapshot of a scope count as no open cves
+func BuildSecurityPosture(snapshots []*security.SecurityPostureSnapshot, scopeType security.PostureScopeType, days int, today time.Time) *SecurityPostureResponse {
+	response := &SecurityPostureResponse{ScopeType: scopeType, Days: days, Scopes: make([]*ScopeSecurityPosture, 0)}
+	var latestDate string
+	for _, snapshot := range snapshots {
+		if date := snapshot.SnapshotDate.Format(securityPostureDateLayout); date > latestDate {
+			latestDate = date
+		}
+	}
+	var dates []string
+	for i := days - 1; i >= 0; i-- {
+		dates = append(dates, today.AddDate(0, 0, -i).Format(securityPostureDateLayout))
+	}
+	postureByScopeId := make(map[int]*ScopeSecurityPosture)
+	snapshotByScopeAndDate := make(map[int]map[string]*security.SecurityPostureSnapshot)
+	remediationHours := make(map[int]float64)
+	for _, snapshot := range snapshots {
+		posture, ok := postureByScopeId[snapshot.ScopeId]
+		if !ok {
+			posture = &ScopeSecurityPosture{ScopeId: snapshot.ScopeId}
+			postureByScopeId[snapshot.ScopeId] = posture
+			snapshotByScopeAndDate[snapshot.ScopeId] = make(map[string]*security.SecurityPostureSnapshot)
+			response.Scopes = append(response.Scopes, posture)
+		}
+		if len(snapshot.ScopeName) > 0 {
+			posture.ScopeName = snapshot.ScopeName
+		}
+		date := snapshot.SnapshotDate.Format(securityPostureDateLayout)
+		snapshotByScopeAndDate[snapshot.ScopeId][date] = snapshot
+		if date == latestDate {
+			posture.Critical = snapshot.CriticalCount
+			posture.Moderate = snapshot.ModerateCount
+			posture.Low = snapshot.LowCount
+			posture.Total = snapshot.CriticalCount + snapshot.ModerateCount + snapshot.LowCount
+		}
+		posture.RemediatedCount += snapshot.RemediatedCount
+		remediationHours[snapshot.ScopeId] += snapshot.RemediationHours
+	}
+	for _, posture := range response.Scopes {
+		if posture.RemediatedCount > 0 {
+			posture.MeanTimeToRemediateHours = remediationHours[posture.ScopeId] / float64(posture.RemediatedCount)
+		}
+		for _, date := range dates {
+			trend := &SecurityPostureTrend{Date: date}
+			if snapshot, ok := snapshotByScopeAndDate[posture.ScopeId][date]; ok {
+				trend.Critical = snapshot.CriticalCount
+				trend.Moderate = snapshot.ModerateCount
+				trend.Low = snapshot.LowCount
+				trend.Remediated = snapshot.RemediatedCount
+			}
+			posture.Trend = append(posture.Trend, trend)
+		}
+	}
+	sort.SliceStable(response.Scopes, func(i, j int) bool {
+		if response.Scopes[i].Critical != response.Scopes[j].Critical {
+			return response.Scopes[i].Critical > response.Scopes[j].Critical
+		}
+		return response.Scopes[i].Total > response.Scopes[j].Total
+	})
+	return response
+}
+
+func postureDate(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// MaterialiseSecurityPosture records cves of deployed images as findings and saves the snapshot of the day for every
+// team, app, environment and cluster
+func (impl ImageScanServiceImpl) MaterialiseSecurityPosture() error {
+	now := time.Now()
+	deployInfos, err := impl.imageScanDeployInfoRepository.FindAll()
+	if err != nil && err != pg.ErrNoRows {
+		impl.Logger.Errorw("error in fetching image scan deploy info", "err", err)
+		return err
+	}
+	var historyIds []int
+	for _, deployInfo := range deployInfos {
+		historyIds = append(historyIds, deployInfo.ImageScanExecutionHistoryId...)
+	}
+	resultsByHistoryId := make(map[int][]*security.ImageScanExecutionResult)
+	for start := 0; start < len(historyIds); start += securityPostureBatchSize {
+		end := start + securityPostureBatchSize
+		if end > len(historyIds) {
+			end = len(historyIds)
+		}
+		results, err := impl.scanResultRepository.FetchByScanExecutionIds(historyIds[start:end])
+		if err != nil && err != pg.ErrNoRows {
+			impl.Logger.Errorw("error in fetching image scan results", "err", err)
+			return err
+		}
+		for _, result := range results {
+			resultsByHistoryId[result.ImageScanExecutionHistoryId] = append(resultsByHistoryId[result.ImageScanExecutionHistoryId], result)
+		}
+	}
+	var appIds []*int
+	for _, deployInfo := range deployInfos {
+		if deployInfo.ObjectType != security.ScanObjectType_POD && deployInfo.ScanObjectMetaId > 0 {
+			appIds = append(appIds, &deployInfo.ScanObjectMetaId)
+		}
+	}
+	var apps []*app.App
+	if len(appIds) > 0 {
+		apps, err = impl.appRepository.FindByIds(appIds)
+		if err != nil && err != pg.ErrNoRows {
+			impl.Logger.Errorw("error in fetching apps", "err", err)
+			return err
+		}
+	}
+	teams, err := impl.teamRepository.FindAllActive()
+	if err != nil && err != pg.ErrNoRows {
+		impl.Logger.Errorw("error in fetching teams", "err", err)
+		return err
+	}
+	envs, err := impl.envService.GetAllActive()
+	if err != nil && err != pg.ErrNoRows {
+		impl.Logger.Errorw("error in fetching environments", "err", err)
+		return err
+	}
+	scopeNames := SecurityPostureScopeNames{
+		security.PostureScopeTeam:        make(map[int]string),
+		security.PostureScopeApp:         make(map[int]string),
+		security.PostureScopeEnvironment: make(map[int]string),
+		security.PostureScopeCluster:     make(map[int]string),
+	}
+	teamIdByAppId := make(map[int]int)
+	for _, app := range apps {
+		teamIdByAppId[app.Id] = app.TeamId
+		scopeNames[security.PostureScopeApp][app.Id] = app.AppName
+	}
+	for _, team := range teams {
+		scopeNames[security.PostureScopeTeam][team.Id] = team.Name
+	}
+	for _, env := range envs {
+		scopeNames[security.PostureScopeEnvironment][env.Id] = env.Environment
+		scopeNames[security.PostureScopeCluster][env.ClusterId] = env.ClusterName
+	}
+
+	openFindings, err := impl.securityPostureRepository.FindOpenFindings()
+	if err != nil && err != pg.ErrNoRows {
+		impl.Logger.Errorw("error in fetching open security posture findings", "err", err)
+		return err
+	}
+	currentFindings := GetSecurityPostureFindings(deployInfos, resultsByHistoryId, teamIdByAppId, now)
+	newFindings, remediatedFindings := DiffSecurityPostureFindings(openFindings, currentFindings, now)
+	err = impl.securityPostureRepository.SaveFindings(newFindings, remediatedFindings)
+	if err != nil {
+		impl.Logger.Errorw("error in saving security posture findings", "err", err)
+		return err
+	}
+
+	snapshotDate := postureDate(now)
+	openFindings, err = impl.securityPostureRepository.FindOpenFindings()
+	if err != nil && err != pg.ErrNoRows {
+		impl.Logger.Errorw("error in fetching open security posture findings", "err", err)
+		return err
+	}
+	remediatedFindings, err = impl.securityPostureRepository.FindFindingsRemediatedBetween(snapshotDate, snapshotDate.AddDate(0, 0, 1))
+	if err != nil && err != pg.ErrNoRows {
+		impl.Logger.Errorw("error in fetching remediated security posture findings", "err", err)
+		return err
+	}
+	snapshots := BuildSecurityPostureSnapshots(snapshotDate, openFindings, remediatedFindings, scopeNames, now)
+	err = impl.securityPostureRepository.SaveSnapshots(snapshotDate, snapshots)
+	if err != nil {
+		impl.Logger.Errorw("error in saving security posture snapshots", "err", err, "date", snapshotDate)
+		return err
+	}
+	impl.Logger.Infow("security posture materialised", "date", snapshotDate, "newFindings", len(newFindings), "remediatedFindings", len(remediatedFindings), "snapshots", len(snapshots))
+	return nil
+}
+
+func (impl ImageScanServiceImpl) GetSecurityPosture(request *SecurityPostureRequest) (*SecurityPostureResponse, error) {
+	switch request.ScopeType {
+	case security.PostureScopeTeam, security.PostureScopeApp, security.PostureScopeEnvironment, security.PostureScopeCluster:
+	default:
+		return nil, fmt.Errorf("unsupported scope type %s", request.ScopeType)
+	}
+	days := request.Days
+	if days <= 0 {
+		days = defaultSecurityPostureDays
+	} else if days > maxSecurityPostureDays {
+		return nil, fmt.Errorf("days can not be more than %d", maxSecurityPostureDays)
+	}
+	today := postureDate(time.Now())
+	snapshots, err := impl.securityPostureRepository.FindSnapshots(request.ScopeType, request.ScopeIds, today.AddDate(0, 0, -(days-1)))
+	if err != nil && err != pg.ErrNoRows {
+		impl.Logger.Errorw("error in fetching security posture snapshots", "err", err, "request", request)
+		return nil, err
+	}
+	return BuildSecurityPosture(snapshots, request.ScopeType, days, today), nil
+}
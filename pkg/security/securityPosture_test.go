@@ -0,0 +1,103 @@
+package security
+
+import (
+	"github.com/devtron-labs/devtron/internal/sql/repository/security"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSecurityPostureFindings(t *testing.T) {
+	now := time.Date(2022, 8, 8, 10, 0, 0, 0, time.UTC)
+	firstScan, secondScan := now.Add(-72*time.Hour), now.Add(-24*time.Hour)
+	deployInfos := []*security.ImageScanDeployInfo{
+		{Id: 1, ImageScanExecutionHistoryId: []int{10, 11}, ScanObjectMetaId: 5, ObjectType: security.ScanObjectType_APP, EnvId: 2, ClusterId: 1},
+		{Id: 2, ImageScanExecutionHistoryId: []int{20}, ScanObjectMetaId: 9, ObjectType: security.ScanObjectType_POD, ClusterId: 1},
+	}
+	resultsByHistoryId := map[int][]*security.ImageScanExecutionResult{
+		10: {
+			{CveStoreName: "CVE-1", CveStore: security.CveStore{Severity: security.Critical}, ImageScanExecutionHistory: security.ImageScanExecutionHistory{ExecutionTime: secondScan}},
+		},
+		11: {
+			{CveStoreName: "CVE-1", CveStore: security.CveStore{Severity: security.Critical}, ImageScanExecutionHistory: security.ImageScanExecutionHistory{ExecutionTime: firstScan}},
+			{CveStoreName: "CVE-2", CveStore: security.CveStore{Severity: security.Low}, ImageScanExecutionHistory: security.ImageScanExecutionHistory{ExecutionTime: firstScan}},
+		},
+		20: {
+			{CveStoreName: "CVE-1", CveStore: security.CveStore{Severity: security.Critical}},
+		},
+	}
+	current := GetSecurityPostureFindings(deployInfos, resultsByHistoryId, map[int]int{5: 3}, now)
+	want := []*security.SecurityPostureFinding{
+		{ImageScanDeployInfoId: 1, AppId: 5, TeamId: 3, EnvId: 2, ClusterId: 1, CveStoreName: "CVE-1", Severity: security.Critical, FirstSeenOn: firstScan},
+		{ImageScanDeployInfoId: 1, AppId: 5, TeamId: 3, EnvId: 2, ClusterId: 1, CveStoreName: "CVE-2", Severity: security.Low, FirstSeenOn: firstScan},
+		{ImageScanDeployInfoId: 2, ClusterId: 1, CveStoreName: "CVE-1", Severity: security.Critical, FirstSeenOn: now},
+	}
+	if !reflect.DeepEqual(current, want) {
+		t.Fatalf("GetSecurityPostureFindings() = %+v, want %+v", current, want)
+	}
+
+	open := []*security.SecurityPostureFinding{
+		{Id: 1, ImageScanDeployInfoId: 1, AppId: 5, TeamId: 3, EnvId: 2, ClusterId: 1, CveStoreName: "CVE-1", Severity: security.Critical, FirstSeenOn: firstScan},
+		{Id: 2, ImageScanDeployInfoId: 1, AppId: 5, TeamId: 3, EnvId: 2, ClusterId: 1, CveStoreName: "CVE-3", Severity: security.Moderate, FirstSeenOn: firstScan},
+	}
+	newFindings, remediatedFindings := DiffSecurityPostureFindings(open, current, now)
+	if len(newFindings) != 2 || newFindings[0].CveStoreName != "CVE-2" || newFindings[1].ImageScanDeployInfoId != 2 {
+		t.Errorf("DiffSecurityPostureFindings() new = %+v", newFindings)
+	}
+	if len(remediatedFindings) != 1 || remediatedFindings[0].Id != 2 || !remediatedFindings[0].RemediatedOn.Equal(now) {
+		t.Errorf("DiffSecurityPostureFindings() remediated = %+v", remediatedFindings)
+	}
+
+	snapshotDate := postureDate(now)
+	names := SecurityPostureScopeNames{security.PostureScopeApp: {5: "payments"}}
+	snapshots := BuildSecurityPostureSnapshots(snapshotDate, current, remediatedFindings, names, now)
+	counts := make(map[security.PostureScopeType]map[int][4]int)
+	for _, snapshot := range snapshots {
+		if counts[snapshot.ScopeType] == nil {
+			counts[snapshot.ScopeType] = make(map[int][4]int)
+		}
+		counts[snapshot.ScopeType][snapshot.ScopeId] = [4]int{snapshot.CriticalCount, snapshot.ModerateCount, snapshot.LowCount, snapshot.RemediatedCount}
+		if snapshot.ScopeType == security.PostureScopeApp && (snapshot.ScopeName != "payments" || snapshot.RemediationHours != 72) {
+			t.Errorf("BuildSecurityPostureSnapshots() app snapshot = %+v", snapshot)
+		}
+	}
+	wantCounts := map[security.PostureScopeType]map[int][4]int{
+		security.PostureScopeTeam:        {3: {1, 0, 1, 1}},
+		security.PostureScopeApp:         {5: {1, 0, 1, 1}},
+		security.PostureScopeEnvironment: {2: {1, 0, 1, 1}},
+		//same cve on app and pod is counted once for the cluster
+		security.PostureScopeCluster: {1: {1, 0, 1, 1}},
+	}
+	if !reflect.DeepEqual(counts, wantCounts) {
+		t.Errorf("BuildSecurityPostureSnapshots() = %v, want %v", counts, wantCounts)
+	}
+}
+
+func TestBuildSecurityPosture(t *testing.T) {
+	today := time.Date(2022, 8, 8, 0, 0, 0, 0, time.UTC)
+	yesterday := today.AddDate(0, 0, -1)
+	snapshots := []*security.SecurityPostureSnapshot{
+		{SnapshotDate: yesterday, ScopeId: 1, ScopeName: "payments", CriticalCount: 3, LowCount: 1, RemediatedCount: 1, RemediationHours: 10},
+		{SnapshotDate: yesterday, ScopeId: 2, ScopeName: "orders", ModerateCount: 4},
+		{SnapshotDate: today, ScopeId: 1, ScopeName: "payments", CriticalCount: 1, LowCount: 1, RemediatedCount: 2, RemediationHours: 50},
+	}
+	response := BuildSecurityPosture(snapshots, security.PostureScopeApp, 3, today)
+	if len(response.Scopes) != 2 {
+		t.Fatalf("BuildSecurityPosture() scopes = %d, want 2", len(response.Scopes))
+	}
+	payments, orders := response.Scopes[0], response.Scopes[1]
+	if payments.ScopeId != 1 || payments.Critical != 1 || payments.Total != 2 || payments.RemediatedCount != 3 || payments.MeanTimeToRemediateHours != 20 {
+		t.Errorf("BuildSecurityPosture() payments = %+v", payments)
+	}
+	//orders has no open cve in latest snapshot
+	if orders.Total != 0 || len(orders.Trend) != 3 {
+		t.Errorf("BuildSecurityPosture() orders = %+v", orders)
+	}
+	var trend []int
+	for _, point := range payments.Trend {
+		trend = append(trend, point.Critical)
+	}
+	if !reflect.DeepEqual(trend, []int{0, 3, 1}) || payments.Trend[2].Date != "2022-08-08" {
+		t.Errorf("BuildSecurityPosture() trend = %v", trend)
+	}
+}
@@ -0,0 +1,352 @@
+package security
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/sql/repository/security"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type VulnerabilityReportFormat string
+
+const (
+	VulnerabilityReportFormatSarif VulnerabilityReportFormat = "sarif"
+	VulnerabilityReportFormatCsv   VulnerabilityReportFormat = "csv"
+	VulnerabilityReportFormatJson  VulnerabilityReportFormat = "json"
+)
+
+// VulnerabilityReportSchemaVersion is bumped on any incompatible change of VulnerabilityReport
+const VulnerabilityReportSchemaVersion = "1.0"
+
+// VulnerabilityReportRequest selects a scan by deploy info or artifact, or the current exposure of an app on an
+// environment when only app and env are set
+type VulnerabilityReportRequest struct {
+	ImageScanDeployInfoId int
+	ArtifactId            int
+	AppId                 int
+	EnvId                 int
+	VulnerabilityReportFilter
+}
+
+type VulnerabilityReportFilter struct {
+	Severities   []string
+	Package      string
+	FixAvailable *bool
+}
+
+type VulnerabilityReport struct {
+	SchemaVersion   string                      `json:"schemaVersion"`
+	GeneratedOn     time.Time                   `json:"generatedOn"`
+	Target          *VulnerabilityReportTarget  `json:"target"`
+	Summary         *VulnerabilityReportSummary `json:"summary"`
+	Vulnerabilities []*VulnerabilityReportEntry `json:"vulnerabilities"`
+}
+
+type VulnerabilityReportTarget struct {
+	AppId         int       `json:"appId,omitempty"`
+	AppName       string    `json:"appName,omitempty"`
+	EnvId         int       `json:"envId,omitempty"`
+	EnvName       string    `json:"envName,omitempty"`
+	ArtifactId    int       `json:"artifactId,omitempty"`
+	ObjectType    string    `json:"objectType,omitempty"`
+	Images        []string  `json:"images"`
+	ExecutionTime time.Time `json:"executionTime"`
+}
+
+type VulnerabilityReportSummary struct {
+	Critical int `json:"critical"`
+	Moderate int `json:"moderate"`
+	Low      int `json:"low"`
+	Total    int `json:"total"`
+}
+
+type VulnerabilityReportEntry struct {
+	CveId            string `json:"cveId"`
+	Severity         string `json:"severity"`
+	Package          string `json:"package"`
+	InstalledVersion string `json:"installedVersion"`
+	FixedVersion     string `json:"fixedVersion"`
+	FixAvailable     bool   `json:"fixAvailable"`
+	Image            string `json:"image"`
+	Policy           string `json:"policy,omitempty"`
+}
+
+// ExportVulnerabilityReport builds a report from scan execution detail, applying the severity, package and fix
+// availability filters of the request
+func (impl ImageScanServiceImpl) ExportVulnerabilityReport(request *VulnerabilityReportRequest) (*VulnerabilityReport, error) {
+	scanRequest := &ImageScanRequest{
+		ImageScanDeployInfoId: request.ImageScanDeployInfoId,
+		ArtifactId:            request.ArtifactId,
+		AppId:                 request.AppId,
+		EnvId:                 request.EnvId,
+	}
+	if scanRequest.ImageScanDeployInfoId == 0 && scanRequest.ArtifactId == 0 {
+		if request.AppId == 0 || request.EnvId == 0 {
+			return nil, fmt.Errorf("imageScanDeployInfoId, artifactId or appId with envId is required")
+		}
+		deployInfo, err := impl.imageScanDeployInfoRepository.FetchByAppIdAndEnvId(request.AppId, request.EnvId, []string{security.ScanObjectType_APP, security.ScanObjectType_CHART})
+		if err != nil {
+			impl.Logger.Errorw("error in fetching deploy info for vulnerability report", "err", err, "appId", request.AppId, "envId", request.EnvId)
+			return nil, err
+		}
+		scanRequest.ImageScanDeployInfoId = deployInfo.Id
+	}
+	executionDetail, err := impl.FetchExecutionDetailResult(scanRequest)
+	if err != nil {
+		impl.Logger.Errorw("error in fetching scan execution detail for vulnerability report", "err", err, "request", scanRequest)
+		return nil, err
+	}
+	return BuildVulnerabilityReport(executionDetail, request.VulnerabilityReportFilter, time.Now()), nil
+}
+
+// BuildVulnerabilityReport converts execution detail into the stable report schema, findings are ordered by severity
+// and cve id so that exports of the same scan are identical
+func BuildVulnerabilityReport(executionDetail *ImageScanExecutionDetail, filter VulnerabilityReportFilter, generatedOn time.Time) *VulnerabilityReport {
+	report := &VulnerabilityReport{
+		SchemaVersion: VulnerabilityReportSchemaVersion,
+		GeneratedOn:   generatedOn,
+		Target: &VulnerabilityReportTarget{
+			AppId:         executionDetail.AppId,
+			AppName:       executionDetail.AppName,
+			EnvId:         executionDetail.EnvId,
+			EnvName:       executionDetail.EnvName,
+			ArtifactId:    executionDetail.ArtifactId,
+			ObjectType:    executionDetail.ObjectType,
+			Images:        make([]string, 0),
+			ExecutionTime: executionDetail.ExecutionTime,
+		},
+		Summary:         &VulnerabilityReportSummary{},
+		Vulnerabilities: make([]*VulnerabilityReportEntry, 0),
+	}
+	images := make(map[string]bool)
+	for _, vulnerability := range executionDetail.Vulnerabilities {
+		if len(vulnerability.Image) > 0 && !images[vulnerability.Image] {
+			images[vulnerability.Image] = true
+			report.Target.Images = append(report.Target.Images, vulnerability.Image)
+		}
+		entry := &VulnerabilityReportEntry{
+			CveId:            vulnerability.CVEName,
+			Severity:         vulnerability.Severity,
+			Package:          vulnerability.Package,
+			InstalledVersion: vulnerability.CVersion,
+			FixedVersion:     vulnerability.FVersion,
+			FixAvailable:     len(vulnerability.FVersion) > 0,
+			Image:            vulnerability.Image,
+			Policy:           vulnerability.Permission,
+		}
+		if !filter.matches(entry) {
+			continue
+		}
+		report.Vulnerabilities = append(report.Vulnerabilities, entry)
+		switch entry.Severity {
+		case security.Critical.String():
+			report.Summary.Critical++
+		case security.Moderate.String():
+			report.Summary.Moderate++
+		case security.Low.String():
+			report.Summary.Low++
+		}
+	}
+	report.Summary.Total = len(report.Vulnerabilities)
+	sort.Strings(report.Target.Images)
+	sort.SliceStable(report.Vulnerabilities, func(i, j int) bool {
+		a, b := report.Vulnerabilities[i], report.Vulnerabilities[j]
+		if severityRank(a.Severity) != severityRank(b.Severity) {
+			return severityRank(a.Severity) > severityRank(b.Severity)
+		}
+		if a.CveId != b.CveId {
+			return a.CveId < b.CveId
+		}
+		if a.Package != b.Package {
+			return a.Package < b.Package
+		}
+		return a.Image < b.Image
+	})
+	return report
+}
+
+func (filter VulnerabilityReportFilter) matches(entry *VulnerabilityReportEntry) bool {
+	if len(filter.Severities) > 0 {
+		matched := false
+		for _, severity := range filter.Severities {
+			if strings.EqualFold(severity, entry.Severity) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(filter.Package) > 0 && !strings.EqualFold(filter.Package, entry.Package) {
+		return false
+	}
+	if filter.FixAvailable != nil && *filter.FixAvailable != entry.FixAvailable {
+		return false
+	}
+	return true
+}
+
+func severityRank(severity string) int {
+	parsed, err := parseSeverity(severity)
+	if err != nil {
+		return -1
+	}
+	return int(parsed)
+}
+
+// RenderVulnerabilityReport returns the report content along with its content type and file extension
+func RenderVulnerabilityReport(report *VulnerabilityReport, format VulnerabilityReportFormat) ([]byte, string, string, error) {
+	switch format {
+	case VulnerabilityReportFormatSarif:
+		content, err := json.MarshalIndent(buildSarifLog(report), "", "  ")
+		return content, "application/sarif+json", "sarif", err
+	case VulnerabilityReportFormatCsv:
+		content, err := renderVulnerabilityReportCsv(report)
+		return content, "text/csv", "csv", err
+	case VulnerabilityReportFormatJson:
+		content, err := json.MarshalIndent(report, "", "  ")
+		return content, "application/json", "json", err
+	}
+	return nil, "", "", fmt.Errorf("unsupported report format %s, supported formats are sarif, csv and json", format)
+}
+
+func renderVulnerabilityReportCsv(report *VulnerabilityReport) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	rows := [][]string{{"cve_id", "severity", "package", "installed_version", "fixed_version", "fix_available", "image", "policy", "app", "environment"}}
+	for _, entry := range report.Vulnerabilities {
+		rows = append(rows, []string{entry.CveId, entry.Severity, entry.Package, entry.InstalledVersion, entry.FixedVersion,
+			strconv.FormatBool(entry.FixAvailable), entry.Image, entry.Policy, report.Target.AppName, report.Target.EnvName})
+	}
+	err := writer.WriteAll(rows)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// sarif 2.1.0 subset consumed by github code scanning
+type sarifLog struct {
+	Version string      `json:"version"`
+	Schema  string      `json:"$schema"`
+	Runs    []*sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    *sarifTool     `json:"tool"`
+	Results []*sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver *sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string       `json:"name"`
+	InformationUri string       `json:"informationUri"`
+	Rules          []*sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	Id                   string                  `json:"id"`
+	Name                 string                  `json:"name"`
+	ShortDescription     *sarifMessage           `json:"shortDescription"`
+	DefaultConfiguration *sarifRuleConfiguration `json:"defaultConfiguration"`
+	Properties           map[string]interface{}  `json:"properties"`
+}
+
+type sarifRuleConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleId    string           `json:"ruleId"`
+	RuleIndex int              `json:"ruleIndex"`
+	Level     string           `json:"level"`
+	Message   *sarifMessage    `json:"message"`
+	Locations []*sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation *sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	Uri string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+func buildSarifLog(report *VulnerabilityReport) *sarifLog {
+	driver := &sarifDriver{Name: "Devtron", InformationUri: "https://devtron.ai", Rules: make([]*sarifRule, 0)}
+	results := make([]*sarifResult, 0)
+	ruleIndex := make(map[string]int)
+	for _, entry := range report.Vulnerabilities {
+		level, securitySeverity := sarifLevel(entry.Severity)
+		index, ok := ruleIndex[entry.CveId]
+		if !ok {
+			index = len(driver.Rules)
+			ruleIndex[entry.CveId] = index
+			driver.Rules = append(driver.Rules, &sarifRule{
+				Id:                   entry.CveId,
+				Name:                 "OsPackageVulnerability",
+				ShortDescription:     &sarifMessage{Text: fmt.Sprintf("%s in %s", entry.CveId, entry.Package)},
+				DefaultConfiguration: &sarifRuleConfiguration{Level: level},
+				Properties: map[string]interface{}{
+					"security-severity": securitySeverity,
+					"tags":              []string{"vulnerability", "security", entry.Severity},
+				},
+			})
+		}
+		location := entry.Image
+		if len(location) == 0 {
+			location = "image"
+		}
+		results = append(results, &sarifResult{
+			RuleId:    entry.CveId,
+			RuleIndex: index,
+			Level:     level,
+			Message: &sarifMessage{Text: fmt.Sprintf("Package: %s\nInstalled Version: %s\nFixed Version: %s\nSeverity: %s",
+				entry.Package, entry.InstalledVersion, entry.FixedVersion, entry.Severity)},
+			Locations: []*sarifLocation{{
+				PhysicalLocation: &sarifPhysicalLocation{
+					ArtifactLocation: &sarifArtifactLocation{Uri: location},
+					Region:           &sarifRegion{StartLine: 1},
+				},
+			}},
+		})
+	}
+	return &sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs:    []*sarifRun{{Tool: &sarifTool{Driver: driver}, Results: results}},
+	}
+}
+
+// sarifLevel maps severity to sarif level and to security-severity score used by github to rank alerts
+func sarifLevel(severity string) (string, string) {
+	switch severity {
+	case security.Critical.String():
+		return "error", "9.0"
+	case security.Moderate.String():
+		return "warning", "5.5"
+	}
+	return "note", "2.0"
+}
@@ -0,0 +1,95 @@
+package security
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildVulnerabilityReport(t *testing.T) {
+	now := time.Date(2022, 8, 8, 10, 0, 0, 0, time.UTC)
+	executionDetail := &ImageScanExecutionDetail{
+		AppId:   1,
+		AppName: "payments",
+		Vulnerabilities: []*Vulnerabilities{
+			{CVEName: "CVE-3", Severity: "low", Package: "bash", CVersion: "5.0"},
+			{CVEName: "CVE-2", Severity: "critical", Package: "openssl", CVersion: "1.1.1k", FVersion: "1.1.1q", Image: "devtron/app:v1"},
+			{CVEName: "CVE-1", Severity: "critical", Package: "zlib", CVersion: "1.2.11", Image: "devtron/app:v1"},
+			{CVEName: "CVE-4", Severity: "moderate", Package: "OpenSSL", CVersion: "1.1.1k", FVersion: "1.1.1l", Image: "devtron/sidecar:v1"},
+		},
+	}
+	fixAvailable, noFix := true, false
+	tests := []struct {
+		name   string
+		filter VulnerabilityReportFilter
+		want   []string
+	}{
+		{name: "no filter", want: []string{"CVE-1", "CVE-2", "CVE-4", "CVE-3"}},
+		{name: "severity", filter: VulnerabilityReportFilter{Severities: []string{"Critical"}}, want: []string{"CVE-1", "CVE-2"}},
+		{name: "package", filter: VulnerabilityReportFilter{Package: "openssl"}, want: []string{"CVE-2", "CVE-4"}},
+		{name: "fix available", filter: VulnerabilityReportFilter{FixAvailable: &fixAvailable}, want: []string{"CVE-2", "CVE-4"}},
+		{name: "no fix", filter: VulnerabilityReportFilter{Severities: []string{"critical", "low"}, FixAvailable: &noFix}, want: []string{"CVE-1", "CVE-3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := BuildVulnerabilityReport(executionDetail, tt.filter, now)
+			var got []string
+			for _, entry := range report.Vulnerabilities {
+				got = append(got, entry.CveId)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BuildVulnerabilityReport() = %v, want %v", got, tt.want)
+			}
+			if report.Summary.Total != len(tt.want) {
+				t.Errorf("BuildVulnerabilityReport() total = %d, want %d", report.Summary.Total, len(tt.want))
+			}
+		})
+	}
+	report := BuildVulnerabilityReport(executionDetail, VulnerabilityReportFilter{}, now)
+	if want := []string{"devtron/app:v1", "devtron/sidecar:v1"}; !reflect.DeepEqual(report.Target.Images, want) {
+		t.Errorf("BuildVulnerabilityReport() images = %v, want %v", report.Target.Images, want)
+	}
+}
+
+func TestRenderVulnerabilityReport(t *testing.T) {
+	report := BuildVulnerabilityReport(&ImageScanExecutionDetail{
+		AppName: "payments",
+		EnvName: "prod",
+		Vulnerabilities: []*Vulnerabilities{
+			{CVEName: "CVE-1", Severity: "critical", Package: "openssl", CVersion: "1.1.1k", FVersion: "1.1.1q", Image: "devtron/app:v1", Permission: "BLOCK"},
+			{CVEName: "CVE-1", Severity: "critical", Package: "openssl", CVersion: "1.1.1k", FVersion: "1.1.1q", Image: "devtron/sidecar:v1", Permission: "BLOCK"},
+			{CVEName: "CVE-2", Severity: "low", Package: "bash", CVersion: "5.0", Image: "devtron/app:v1", Permission: "WHITELISTED"},
+		},
+	}, VulnerabilityReportFilter{}, time.Date(2022, 8, 8, 10, 0, 0, 0, time.UTC))
+
+	content, contentType, extension, err := RenderVulnerabilityReport(report, VulnerabilityReportFormatSarif)
+	if err != nil || contentType != "application/sarif+json" || extension != "sarif" {
+		t.Fatalf("RenderVulnerabilityReport() sarif contentType = %s, extension = %s, err = %v", contentType, extension, err)
+	}
+	sarif := &sarifLog{}
+	if err = json.Unmarshal(content, sarif); err != nil {
+		t.Fatalf("RenderVulnerabilityReport() sarif is not json: %v", err)
+	}
+	run := sarif.Runs[0]
+	if sarif.Version != "2.1.0" || len(run.Tool.Driver.Rules) != 2 || len(run.Results) != 3 {
+		t.Errorf("RenderVulnerabilityReport() sarif version = %s, rules = %d, results = %d", sarif.Version, len(run.Tool.Driver.Rules), len(run.Results))
+	}
+	if result := run.Results[2]; result.RuleIndex != 1 || result.Level != "note" {
+		t.Errorf("RenderVulnerabilityReport() sarif result = %+v", result)
+	}
+
+	content, contentType, _, err = RenderVulnerabilityReport(report, VulnerabilityReportFormatCsv)
+	if err != nil || contentType != "text/csv" {
+		t.Fatalf("RenderVulnerabilityReport() csv contentType = %s, err = %v", contentType, err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 4 || lines[1] != "CVE-1,critical,openssl,1.1.1k,1.1.1q,true,devtron/app:v1,BLOCK,payments,prod" {
+		t.Errorf("RenderVulnerabilityReport() csv = %v", lines)
+	}
+
+	if _, _, _, err = RenderVulnerabilityReport(report, "xml"); err == nil {
+		t.Errorf("RenderVulnerabilityReport() expected error for unsupported format")
+	}
+}
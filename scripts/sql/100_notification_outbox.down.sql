@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS notification_delivery_event_id_idx;
+
+DROP INDEX IF EXISTS notification_delivery_status_next_attempt_on_idx;
+
+DROP TABLE "public"."notification_delivery" CASCADE;
+
+DROP SEQUENCE IF EXISTS id_seq_notification_delivery;
+
+DROP TABLE "public"."notification_event" CASCADE;
+
+DROP SEQUENCE IF EXISTS id_seq_notification_event;
@@ -0,0 +1,45 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_notification_event;
+
+-- Table Definition
+CREATE TABLE "public"."notification_event"
+(
+    "id"            integer     NOT NULL DEFAULT nextval('id_seq_notification_event'::regclass),
+    "event_type_id" int4        NOT NULL,
+    "pipeline_type" varchar(50),
+    "pipeline_id"   int4,
+    "app_id"        int4,
+    "env_id"        int4,
+    "team_id"       int4,
+    "payload"       text        NOT NULL,
+    "created_on"    timestamptz,
+    "created_by"    int4,
+    "updated_on"    timestamptz,
+    "updated_by"    int4,
+    PRIMARY KEY ("id")
+);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_notification_delivery;
+
+-- Table Definition
+CREATE TABLE "public"."notification_delivery"
+(
+    "id"              integer     NOT NULL DEFAULT nextval('id_seq_notification_delivery'::regclass),
+    "event_id"        int4        NOT NULL,
+    "channel"         varchar(50) NOT NULL,
+    "config_id"       int4,
+    "status"          varchar(50) NOT NULL,
+    "attempts"        int4        NOT NULL DEFAULT 0,
+    "next_attempt_on" timestamptz,
+    "delivered_on"    timestamptz,
+    "last_error"      text,
+    "created_on"      timestamptz,
+    "created_by"      int4,
+    "updated_on"      timestamptz,
+    "updated_by"      int4,
+    CONSTRAINT "notification_delivery_event_id_fkey" FOREIGN KEY ("event_id") REFERENCES "public"."notification_event" ("id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS notification_delivery_status_next_attempt_on_idx ON notification_delivery (status, next_attempt_on);
+
+CREATE INDEX IF NOT EXISTS notification_delivery_event_id_idx ON notification_delivery (event_id);
@@ -0,0 +1,5 @@
+DELETE FROM "public"."notification_templates" WHERE "event_type_id" IN (4, 5, 7, 8, 9, 10, 11, 12);
+
+ALTER TABLE "public"."cluster" DROP COLUMN IF EXISTS "unreachable_notified_on";
+
+DELETE FROM "public"."event" WHERE "id" IN (7, 8, 9, 10, 11, 12);
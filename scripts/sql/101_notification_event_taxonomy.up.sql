@@ -0,0 +1,498 @@
+INSERT INTO "public"."event" ("id", "event_type", "description") VALUES
+('7', 'DEPLOYMENT_DEGRADED', ''),
+('8', 'DEPLOYMENT_HEALTHY', ''),
+('9', 'IMAGE_SCAN_BLOCKED', ''),
+('10', 'PIPELINE_CONFIG_CHANGED', ''),
+('11', 'APP_HIBERNATED', ''),
+('12', 'CLUSTER_UNREACHABLE', '');
+
+ALTER TABLE "public"."cluster" ADD COLUMN IF NOT EXISTS "unreachable_notified_on" timestamptz;
+
+---- approval requested template for CD slack
+INSERT INTO "public"."notification_templates" ("channel_type", "node_type", "event_type_id", "template_name", "template_payload") VALUES
+('slack', 'CD', '4', 'CD approval requested template', '{
+    "text": ":raised_hand: Deployment approval requested | Application > {{appName}}",
+    "blocks": [{
+            "type": "section",
+            "text": {
+                "type": "mrkdwn",
+                "text": ":raised_hand: *Deployment approval requested*\n<!date^{{eventTime}}^{date_long} {time} | \"-\"> \n Requested by {{triggeredBy}}"
+            }
+        },
+        {
+            "type": "divider"
+        },
+        {
+            "type": "section",
+            "fields": [
+                {
+                    "type": "mrkdwn",
+                    "text": "*Application*\n{{appName}}"
+                },
+                {
+                    "type": "mrkdwn",
+                    "text": "*Environment*\n{{envName}}"
+                },
+                {
+                    "type": "mrkdwn",
+                    "text": "*Image*\n`{{dockerImageUrl}}`"
+                },
+                {
+                    "type": "mrkdwn",
+                    "text": "*Approvers*\n{{#approverEmailIds}}{{.}} {{/approverEmailIds}}"
+                }
+            ]
+        },
+        {
+            "type": "actions",
+            "elements": [{
+                "type": "button",
+                "text": {
+                    "type": "plain_text",
+                    "text": "View Details"
+                }
+                {{#appDetailLink}}
+                ,
+                "url": "{{& appDetailLink}}"
+                {{/appDetailLink}}
+            }]
+        }
+    ]
+}');
+
+---- approval requested template for CD ses/smtp
+INSERT INTO "public"."notification_templates" ("channel_type", "node_type", "event_type_id", "template_name", "template_payload") VALUES
+('ses', 'CD', '4', 'CD approval requested ses template', '{"from": "{{fromEmail}}",
+ "to": "{{toEmail}}",
+ "subject": "Deployment approval requested for app: {{appName}}",
+ "html": "<h2 style=\"color:#0066cc;\">Deployment Approval Requested</h2><span>{{eventTime}}</span><br><span>Requested by <strong>{{triggeredBy}}</strong></span><br><br><hr><br><span>Application: <strong>{{appName}}</strong></span>&nbsp;&nbsp;|&nbsp;&nbsp;<span>Environment: <strong>{{envName}}</strong></span><br><span>Image: <strong>{{dockerImageUrl}}</strong></span><br><br>"}');
+
+---- cve exception expiring template for CD slack
+INSERT INTO "public"."notification_templates" ("channel_type", "node_type", "event_type_id", "template_name", "template_payload") VALUES
+('slack', 'CD', '5', 'CD cve exception expiring template', '{
+    "text": ":hourglass: CVE exception expiring | Application > {{appName}}",
+    "blocks": [{
+            "type": "section",
+            "text": {
+                "type": "mrkdwn",
+                "text": ":hourglass: *CVE exception expiring*\n<!date^{{eventTime}}^{date_long} {time} | \"-\"> \n Owner {{#cveException}}{{owner}}{{/cveException}}"
+            }
+        },
+        {
+            "type": "divider"
+        },
+        {
+            "type": "section",
+            "fields": [
+                {
+                    "type": "mrkdwn",
+                    "text": "*CVE*\n{{#cveException}}{{cveId}} ({{severity}}){{/cveException}}"
+                },
+                {
+                    "type": "mrkdwn",
+                    "text": "*Expires on*\n{{#cveException}}{{expiresOn}}{{/cveException}}"
+                },
+                {
+                    "type": "mrkdwn",
+                    "text": "*Application*\n{{appName}}"
+                },
+                {
+                    "type": "mrkdwn",
+                    "text": "*Environment*\n{{envName}}"
+                }
+            ]
+        },
+        {
+            "type": "actions",
+            "elements": [{
+                "type": "button",
+                "text": {
+                    "type": "plain_text",
+                    "text": "View Details"
+                }
+                {{#appDetailLink}}
+                ,
+                "url": "{{& appDetailLink}}"
+                {{/appDetailLink}}
+            }]
+        }
+    ]
+}');
+
+---- cve exception expiring template for CD ses/smtp
+INSERT INTO "public"."notification_templates" ("channel_type", "node_type", "event_type_id", "template_name", "template_payload") VALUES
+('ses', 'CD', '5', 'CD cve exception expiring ses template', '{"from": "{{fromEmail}}",
+ "to": "{{toEmail}}",
+ "subject": "CVE exception expiring",
+ "html": "<h2 style=\"color:#ff7e5b;\">CVE Exception Expiring</h2><span>{{eventTime}}</span><br><span>Owner <strong>{{#cveException}}{{owner}}{{/cveException}}</strong></span><br><br><hr><br><span>{{#cveException}}CVE: <strong>{{cveId}} ({{severity}})</strong></span>&nbsp;&nbsp;|&nbsp;&nbsp;<span>Policy level: <strong>{{policyLevel}}</strong></span><br><span>Expires on: <strong>{{expiresOn}}</strong></span><br><span>Justification: {{justification}}{{/cveException}}</span><br><br>"}');
+
+---- deployment degraded template for CD slack
+INSERT INTO "public"."notification_templates" ("channel_type", "node_type", "event_type_id", "template_name", "template_payload") VALUES
+('slack', 'CD', '7', 'CD deployment degraded template', '{
+    "text": ":warning: Deployment degraded | Application > {{appName}}",
+    "blocks": [{
+            "type": "section",
+            "text": {
+                "type": "mrkdwn",
+                "text": ":warning: *Deployment degraded after sync*\n<!date^{{eventTime}}^{date_long} {time} | \"-\"> \n Triggered by {{triggeredBy}}"
+            }
+        },
+        {
+            "type": "divider"
+        },
+        {
+            "type": "section",
+            "fields": [
+                {
+                    "type": "mrkdwn",
+                    "text": "*Application*\n{{appName}}"
+                },
+                {
+                    "type": "mrkdwn",
+                    "text": "*Environment*\n{{envName}}"
+                },
+                {
+                    "type": "mrkdwn",
+                    "text": "*Health status*\n{{healthStatus}}"
+                },
+                {
+                    "type": "mrkdwn",
+                    "text": "*Image*\n`{{dockerImageUrl}}`"
+                }
+            ]
+        },
+        {
+            "type": "actions",
+            "elements": [{
+                "type": "button",
+                "text": {
+                    "type": "plain_text",
+                    "text": "View Details"
+                }
+                {{#appDetailLink}}
+                ,
+                "url": "{{& appDetailLink}}"
+                {{/appDetailLink}}
+            }]
+        }
+    ]
+}');
+
+---- deployment degraded template for CD ses/smtp
+INSERT INTO "public"."notification_templates" ("channel_type", "node_type", "event_type_id", "template_name", "template_payload") VALUES
+('ses', 'CD', '7', 'CD deployment degraded ses template', '{"from": "{{fromEmail}}",
+ "to": "{{toEmail}}",
+ "subject": "Deployment degraded for app: {{appName}} in environment: {{envName}}",
+ "html": "<h2 style=\"color:#f33e3e;\">Deployment Degraded</h2><span>{{eventTime}}</span><br><span>Triggered by <strong>{{triggeredBy}}</strong></span><br><br><hr><br><span>Application: <strong>{{appName}}</strong></span>&nbsp;&nbsp;|&nbsp;&nbsp;<span>Environment: <strong>{{envName}}</strong></span><br><span>Health status: <strong>{{healthStatus}}</strong></span><br><br>"}');
+
+---- deployment healthy template for CD slack
+INSERT INTO "public"."notification_templates" ("channel_type", "node_type", "event_type_id", "template_name", "template_payload") VALUES
+('slack', 'CD', '8', 'CD deployment healthy template', '{
+    "text": ":white_check_mark: Deployment healthy | Application > {{appName}}",
+    "blocks": [{
+            "type": "section",
+            "text": {
+                "type": "mrkdwn",
+                "text": ":white_check_mark: *Deployment healthy after sync*\n<!date^{{eventTime}}^{date_long} {time} | \"-\"> \n Triggered by {{triggeredBy}}"
+            }
+        },
+        {
+            "type": "divider"
+        },
+        {
+            "type": "section",
+            "fields": [
+                {
+                    "type": "mrkdwn",
+                    "text": "*Application*\n{{appName}}"
+                },
+                {
+                    "type": "mrkdwn",
+                    "text": "*Environment*\n{{envName}}"
+                },
+                {
+                    "type": "mrkdwn",
+                    "text": "*Health status*\n{{healthStatus}}"
+                },
+                {
+                    "type": "mrkdwn",
+                    "text": "*Image*\n`{{dockerImageUrl}}`"
+                }
+            ]
+        },
+        {
+            "type": "actions",
+            "elements": [{
+                "type": "button",
+                "text": {
+                    "type": "plain_text",
+                    "text": "View Details"
+                }
+                {{#appDetailLink}}
+                ,
+                "url": "{{& appDetailLink}}"
+                {{/appDetailLink}}
+            }]
+        }
+    ]
+}');
+
+---- deployment healthy template for CD ses/smtp
+INSERT INTO "public"."notification_templates" ("channel_type", "node_type", "event_type_id", "template_name", "template_payload") VALUES
+('ses', 'CD', '8', 'CD deployment healthy ses template', '{"from": "{{fromEmail}}",
+ "to": "{{toEmail}}",
+ "subject": "Deployment healthy for app: {{appName}} in environment: {{envName}}",
+ "html": "<h2 style=\"color:#1dad70;\">Deployment Healthy</h2><span>{{eventTime}}</span><br><span>Triggered by <strong>{{triggeredBy}}</strong></span><br><br><hr><br><span>Application: <strong>{{appName}}</strong></span>&nbsp;&nbsp;|&nbsp;&nbsp;<span>Environment: <strong>{{envName}}</strong></span><br><span>Health status: <strong>{{healthStatus}}</strong></span><br><br>"}');
+
+---- image scan blocked template for CD slack
+INSERT INTO "public"."notification_templates" ("channel_type", "node_type", "event_type_id", "template_name", "template_payload") VALUES
+('slack', 'CD', '9', 'CD image scan blocked template', '{
+    "text": ":no_entry: Deployment blocked by image scan | Application > {{appName}}",
+    "blocks": [{
+            "type": "section",
+            "text": {
+                "type": "mrkdwn",
+                "text": ":no_entry: *Deployment blocked by vulnerability policy*\n<!date^{{eventTime}}^{date_long} {time} | \"-\"> \n Triggered by {{triggeredBy}}"
+            }
+        },
+        {
+            "type": "divider"
+        },
+        {
+            "type": "section",
+            "fields": [
+                {
+                    "type": "mrkdwn",
+                    "text": "*Application*\n{{appName}}"
+                },
+                {
+                    "type": "mrkdwn",
+                    "text": "*Environment*\n{{envName}}"
+                },
+                {
+                    "type": "mrkdwn",
+                    "text": "*Image*\n`{{dockerImageUrl}}`"
+                },
+                {
+                    "type": "mrkdwn",
+                    "text": "*Blocked CVEs*\n{{#blockedCves}}{{name}} ({{severity}}) {{/blockedCves}}"
+                }
+            ]
+        },
+        {
+            "type": "actions",
+            "elements": [{
+                "type": "button",
+                "text": {
+                    "type": "plain_text",
+                    "text": "View Details"
+                }
+                {{#appDetailLink}}
+                ,
+                "url": "{{& appDetailLink}}"
+                {{/appDetailLink}}
+            }]
+        }
+    ]
+}');
+
+---- image scan blocked template for CD ses/smtp
+INSERT INTO "public"."notification_templates" ("channel_type", "node_type", "event_type_id", "template_name", "template_payload") VALUES
+('ses', 'CD', '9', 'CD image scan blocked ses template', '{"from": "{{fromEmail}}",
+ "to": "{{toEmail}}",
+ "subject": "Deployment blocked by image scan for app: {{appName}}",
+ "html": "<h2 style=\"color:#f33e3e;\">Deployment Blocked By Image Scan</h2><span>{{eventTime}}</span><br><span>Triggered by <strong>{{triggeredBy}}</strong></span><br><br><hr><br><span>Application: <strong>{{appName}}</strong></span>&nbsp;&nbsp;|&nbsp;&nbsp;<span>Environment: <strong>{{envName}}</strong></span><br><span>Image: <strong>{{dockerImageUrl}}</strong></span><br><span>Blocked CVEs: {{#blockedCves}}<strong>{{name}}</strong> ({{severity}}) {{/blockedCves}}</span><br><br>"}');
+
+---- pipeline config changed template for CI slack
+INSERT INTO "public"."notification_templates" ("channel_type", "node_type", "event_type_id", "template_name", "template_payload") VALUES
+('slack', 'CI', '10', 'CI pipeline config changed template', '{
+    "text": ":pencil2: Pipeline configuration changed | Application > {{appName}}",
+    "blocks": [{
+            "type": "section",
+            "text": {
+                "type": "mrkdwn",
+                "text": ":pencil2: *{{configChange}}*\n<!date^{{eventTime}}^{date_long} {time} | \"-\"> \n Changed by {{triggeredBy}}"
+            }
+        },
+        {
+            "type": "divider"
+        },
+        {
+            "type": "section",
+            "fields": [
+                {
+                    "type": "mrkdwn",
+                    "text": "*Application*\n{{appName}}"
+                },
+                {
+                    "type": "mrkdwn",
+                    "text": "*Pipeline*\n{{pipelineName}}"
+                }
+            ]
+        },
+        {
+            "type": "actions",
+            "elements": [{
+                "type": "button",
+                "text": {
+                    "type": "plain_text",
+                    "text": "View Details"
+                }
+                {{#appDetailLink}}
+                ,
+                "url": "{{& appDetailLink}}"
+                {{/appDetailLink}}
+            }]
+        }
+    ]
+}');
+
+---- pipeline config changed template for CI ses/smtp
+INSERT INTO "public"."notification_templates" ("channel_type", "node_type", "event_type_id", "template_name", "template_payload") VALUES
+('ses', 'CI', '10', 'CI pipeline config changed ses template', '{"from": "{{fromEmail}}",
+ "to": "{{toEmail}}",
+ "subject": "Pipeline configuration changed for app: {{appName}}",
+ "html": "<h2 style=\"color:#0066cc;\">Pipeline Configuration Changed</h2><span>{{eventTime}}</span><br><span>Changed by <strong>{{triggeredBy}}</strong></span><br><br><hr><br><span>Application: <strong>{{appName}}</strong></span>&nbsp;&nbsp;|&nbsp;&nbsp;<span>Pipeline: <strong>{{pipelineName}}</strong></span><br><span>{{configChange}}</span><br><br>"}');
+
+---- pipeline config changed template for CD slack
+INSERT INTO "public"."notification_templates" ("channel_type", "node_type", "event_type_id", "template_name", "template_payload") VALUES
+('slack', 'CD', '10', 'CD pipeline config changed template', '{
+    "text": ":pencil2: Pipeline configuration changed | Application > {{appName}}",
+    "blocks": [{
+            "type": "section",
+            "text": {
+                "type": "mrkdwn",
+                "text": ":pencil2: *{{configChange}}*\n<!date^{{eventTime}}^{date_long} {time} | \"-\"> \n Changed by {{triggeredBy}}"
+            }
+        },
+        {
+            "type": "divider"
+        },
+        {
+            "type": "section",
+            "fields": [
+                {
+                    "type": "mrkdwn",
+                    "text": "*Application*\n{{appName}}"
+                },
+                {
+                    "type": "mrkdwn",
+                    "text": "*Environment*\n{{envName}}"
+                },
+                {
+                    "type": "mrkdwn",
+                    "text": "*Pipeline*\n{{pipelineName}}"
+                }
+            ]
+        },
+        {
+            "type": "actions",
+            "elements": [{
+                "type": "button",
+                "text": {
+                    "type": "plain_text",
+                    "text": "View Details"
+                }
+                {{#appDetailLink}}
+                ,
+                "url": "{{& appDetailLink}}"
+                {{/appDetailLink}}
+            }]
+        }
+    ]
+}');
+
+---- pipeline config changed template for CD ses/smtp
+INSERT INTO "public"."notification_templates" ("channel_type", "node_type", "event_type_id", "template_name", "template_payload") VALUES
+('ses', 'CD', '10', 'CD pipeline config changed ses template', '{"from": "{{fromEmail}}",
+ "to": "{{toEmail}}",
+ "subject": "Pipeline configuration changed for app: {{appName}}",
+ "html": "<h2 style=\"color:#0066cc;\">Pipeline Configuration Changed</h2><span>{{eventTime}}</span><br><span>Changed by <strong>{{triggeredBy}}</strong></span><br><br><hr><br><span>Application: <strong>{{appName}}</strong></span>&nbsp;&nbsp;|&nbsp;&nbsp;<span>Environment: <strong>{{envName}}</strong></span><br><span>{{configChange}}</span><br><br>"}');
+
+---- app hibernated template for CD slack
+INSERT INTO "public"."notification_templates" ("channel_type", "node_type", "event_type_id", "template_name", "template_payload") VALUES
+('slack', 'CD', '11', 'CD app hibernated template', '{
+    "text": ":zzz: Application hibernated | Application > {{appName}}",
+    "blocks": [{
+            "type": "section",
+            "text": {
+                "type": "mrkdwn",
+                "text": ":zzz: *Application hibernated*\n<!date^{{eventTime}}^{date_long} {time} | \"-\"> \n Hibernated by {{triggeredBy}}"
+            }
+        },
+        {
+            "type": "divider"
+        },
+        {
+            "type": "section",
+            "fields": [
+                {
+                    "type": "mrkdwn",
+                    "text": "*Application*\n{{appName}}"
+                },
+                {
+                    "type": "mrkdwn",
+                    "text": "*Environment*\n{{envName}}"
+                }
+            ]
+        },
+        {
+            "type": "actions",
+            "elements": [{
+                "type": "button",
+                "text": {
+                    "type": "plain_text",
+                    "text": "View Details"
+                }
+                {{#appDetailLink}}
+                ,
+                "url": "{{& appDetailLink}}"
+                {{/appDetailLink}}
+            }]
+        }
+    ]
+}');
+
+---- app hibernated template for CD ses/smtp
+INSERT INTO "public"."notification_templates" ("channel_type", "node_type", "event_type_id", "template_name", "template_payload") VALUES
+('ses', 'CD', '11', 'CD app hibernated ses template', '{"from": "{{fromEmail}}",
+ "to": "{{toEmail}}",
+ "subject": "App hibernated: {{appName}} in environment: {{envName}}",
+ "html": "<h2 style=\"color:#767d84;\">Application Hibernated</h2><span>{{eventTime}}</span><br><span>Hibernated by <strong>{{triggeredBy}}</strong></span><br><br><hr><br><span>Application: <strong>{{appName}}</strong></span>&nbsp;&nbsp;|&nbsp;&nbsp;<span>Environment: <strong>{{envName}}</strong></span><br><br>"}');
+
+---- cluster unreachable template for CD slack
+INSERT INTO "public"."notification_templates" ("channel_type", "node_type", "event_type_id", "template_name", "template_payload") VALUES
+('slack', 'CD', '12', 'CD cluster unreachable template', '{
+    "text": ":red_circle: Cluster unreachable",
+    "blocks": [{
+            "type": "section",
+            "text": {
+                "type": "mrkdwn",
+                "text": ":red_circle: *Cluster unreachable*\n<!date^{{eventTime}}^{date_long} {time} | \"-\">"
+            }
+        },
+        {
+            "type": "divider"
+        },
+        {
+            "type": "section",
+            "fields": [
+                {
+                    "type": "mrkdwn",
+                    "text": "*Cluster*\n{{#cluster}}{{clusterName}}{{/cluster}}"
+                },
+                {
+                    "type": "mrkdwn",
+                    "text": "*Error*\n{{#cluster}}{{errorInConnecting}}{{/cluster}}"
+                }
+            ]
+        }
+    ]
+}');
+
+---- cluster unreachable template for CD ses/smtp
+INSERT INTO "public"."notification_templates" ("channel_type", "node_type", "event_type_id", "template_name", "template_payload") VALUES
+('ses', 'CD', '12', 'CD cluster unreachable ses template', '{"from": "{{fromEmail}}",
+ "to": "{{toEmail}}",
+ "subject": "Cluster unreachable: {{#cluster}}{{clusterName}}{{/cluster}}",
+ "html": "<h2 style=\"color:#f33e3e;\">Cluster Unreachable</h2><span>{{eventTime}}</span><br><span>Connection check failed</span><br><br><hr><br><span>{{#cluster}}Cluster: <strong>{{clusterName}}</strong></span>&nbsp;&nbsp;|&nbsp;&nbsp;<span>Server URL: <strong>{{serverUrl}}</strong></span><br><span>Error: {{errorInConnecting}}{{/cluster}}</span><br><br>"}');
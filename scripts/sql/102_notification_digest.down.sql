@@ -0,0 +1,10 @@
+DELETE FROM "public"."notification_templates" WHERE "event_type_id" = 13;
+
+DROP TABLE IF EXISTS "public"."notification_batch";
+
+DROP SEQUENCE IF EXISTS id_seq_notification_batch;
+
+ALTER TABLE "public"."notification_settings" DROP COLUMN IF EXISTS "dedup_window_minutes";
+ALTER TABLE "public"."notification_settings" DROP COLUMN IF EXISTS "delivery_mode";
+
+DELETE FROM "public"."event" WHERE "id" = 13;
@@ -0,0 +1,90 @@
+INSERT INTO "public"."event" ("id", "event_type", "description") VALUES ('13', 'NOTIFICATION_DIGEST', '');
+
+ALTER TABLE "public"."notification_settings" ADD COLUMN IF NOT EXISTS "delivery_mode" varchar(50);
+ALTER TABLE "public"."notification_settings" ADD COLUMN IF NOT EXISTS "dedup_window_minutes" int4;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_notification_batch;
+
+-- Table Definition
+CREATE TABLE "public"."notification_batch"
+(
+    "id"                      integer     NOT NULL DEFAULT nextval('id_seq_notification_batch'::regclass),
+    "notification_setting_id" int4        NOT NULL,
+    "batch_key"               varchar(250) NOT NULL,
+    "delivery_mode"           varchar(50) NOT NULL,
+    "event_ids"               integer[],
+    "event_count"             int4        NOT NULL DEFAULT 0,
+    "window_start"            timestamptz,
+    "flush_on"                timestamptz,
+    "created_on"              timestamptz,
+    "created_by"              int4,
+    "updated_on"              timestamptz,
+    "updated_by"              int4,
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS notification_batch_setting_id_batch_key_idx ON notification_batch (notification_setting_id, batch_key);
+
+CREATE INDEX IF NOT EXISTS notification_batch_flush_on_idx ON notification_batch (flush_on);
+
+---- notification digest template for CI slack
+INSERT INTO "public"."notification_templates" ("channel_type", "node_type", "event_type_id", "template_name", "template_payload") VALUES
+('slack', 'CI', '13', 'CI notification digest template', '{
+    "text": ":bell: {{#digest}}{{title}}{{/digest}}",
+    "blocks": [{
+            "type": "section",
+            "text": {
+                "type": "mrkdwn",
+                "text": ":bell: *{{#digest}}{{title}}{{/digest}}*\n<!date^{{eventTime}}^{date_long} {time} | \"-\"> \n {{#digest}}{{totalCount}} notifications from {{from}} to {{to}}{{/digest}}"
+            }
+        },
+        {
+            "type": "divider"
+        },
+        {
+            "type": "section",
+            "text": {
+                "type": "mrkdwn",
+                "text": "{{#digest}}{{#items}}*{{eventName}}* | {{appName}} {{envName}} {{pipelineName}} | {{count}} times, last at {{lastEventTime}}\n{{/items}}{{/digest}}"
+            }
+        }
+    ]
+}');
+
+---- notification digest template for CI ses/smtp
+INSERT INTO "public"."notification_templates" ("channel_type", "node_type", "event_type_id", "template_name", "template_payload") VALUES
+('ses', 'CI', '13', 'CI notification digest ses template', '{"from": "{{fromEmail}}",
+ "to": "{{toEmail}}",
+ "subject": "{{#digest}}{{title}}: {{totalCount}} notifications{{/digest}}",
+ "html": "<h2 style=\"color:#767d84;\">{{#digest}}{{title}}{{/digest}}</h2><span>{{eventTime}}</span><br><span>{{#digest}}{{totalCount}} notifications from {{from}} to {{to}}{{/digest}}</span><br><br><hr><br>{{#digest}}{{#items}}<span><strong>{{eventName}}</strong>&nbsp;&nbsp;|&nbsp;&nbsp;{{appName}} {{envName}} {{pipelineName}}&nbsp;&nbsp;|&nbsp;&nbsp;{{count}} times, last at {{lastEventTime}}</span><br>{{/items}}{{/digest}}<br>"}');
+
+---- notification digest template for CD slack
+INSERT INTO "public"."notification_templates" ("channel_type", "node_type", "event_type_id", "template_name", "template_payload") VALUES
+('slack', 'CD', '13', 'CD notification digest template', '{
+    "text": ":bell: {{#digest}}{{title}}{{/digest}}",
+    "blocks": [{
+            "type": "section",
+            "text": {
+                "type": "mrkdwn",
+                "text": ":bell: *{{#digest}}{{title}}{{/digest}}*\n<!date^{{eventTime}}^{date_long} {time} | \"-\"> \n {{#digest}}{{totalCount}} notifications from {{from}} to {{to}}{{/digest}}"
+            }
+        },
+        {
+            "type": "divider"
+        },
+        {
+            "type": "section",
+            "text": {
+                "type": "mrkdwn",
+                "text": "{{#digest}}{{#items}}*{{eventName}}* | {{appName}} {{envName}} {{pipelineName}} | {{count}} times, last at {{lastEventTime}}\n{{/items}}{{/digest}}"
+            }
+        }
+    ]
+}');
+
+---- notification digest template for CD ses/smtp
+INSERT INTO "public"."notification_templates" ("channel_type", "node_type", "event_type_id", "template_name", "template_payload") VALUES
+('ses', 'CD', '13', 'CD notification digest ses template', '{"from": "{{fromEmail}}",
+ "to": "{{toEmail}}",
+ "subject": "{{#digest}}{{title}}: {{totalCount}} notifications{{/digest}}",
+ "html": "<h2 style=\"color:#767d84;\">{{#digest}}{{title}}{{/digest}}</h2><span>{{eventTime}}</span><br><span>{{#digest}}{{totalCount}} notifications from {{from}} to {{to}}{{/digest}}</span><br><br><hr><br>{{#digest}}{{#items}}<span><strong>{{eventName}}</strong>&nbsp;&nbsp;|&nbsp;&nbsp;{{appName}} {{envName}} {{pipelineName}}&nbsp;&nbsp;|&nbsp;&nbsp;{{count}} times, last at {{lastEventTime}}</span><br>{{/items}}{{/digest}}<br>"}');
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS notification_inbox_user_id_id_idx;
+
+DROP INDEX IF EXISTS notification_inbox_user_id_read_idx;
+
+DROP TABLE IF EXISTS "public"."notification_inbox";
+
+DROP SEQUENCE IF EXISTS id_seq_notification_inbox;
@@ -0,0 +1,33 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_notification_inbox;
+
+-- Table Definition
+CREATE TABLE "public"."notification_inbox"
+(
+    "id"            integer      NOT NULL DEFAULT nextval('id_seq_notification_inbox'::regclass),
+    "user_id"       int4         NOT NULL,
+    "event_type_id" int4         NOT NULL,
+    "pipeline_type" varchar(50),
+    "pipeline_id"   int4,
+    "app_id"        int4,
+    "env_id"        int4,
+    "team_id"       int4,
+    "title"         varchar(250) NOT NULL,
+    "app_name"      varchar(250),
+    "env_name"      varchar(250),
+    "pipeline_name" varchar(250),
+    "stage"         varchar(50),
+    "triggered_by"  varchar(250),
+    "link"          text,
+    "read"          bool         NOT NULL DEFAULT false,
+    "read_on"       timestamptz,
+    "created_on"    timestamptz,
+    "created_by"    int4,
+    "updated_on"    timestamptz,
+    "updated_by"    int4,
+    CONSTRAINT "notification_inbox_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS notification_inbox_user_id_read_idx ON notification_inbox (user_id, read);
+
+CREATE INDEX IF NOT EXISTS notification_inbox_user_id_id_idx ON notification_inbox (user_id, id);
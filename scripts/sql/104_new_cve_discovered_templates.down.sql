@@ -0,0 +1 @@
+DELETE FROM "public"."notification_templates" WHERE "event_type_id" = 6 AND "node_type" = 'CD' AND "channel_type" IN ('slack', 'ses');
@@ -0,0 +1,44 @@
+---- new cve discovered template for CD slack
+INSERT INTO "public"."notification_templates" ("channel_type", "node_type", "event_type_id", "template_name", "template_payload") VALUES
+('slack', 'CD', '6', 'CD new cve discovered template', '{
+    "text": ":mag: New CVEs discovered on deployed image | Application > {{appName}}",
+    "blocks": [{
+            "type": "section",
+            "text": {
+                "type": "mrkdwn",
+                "text": ":mag: *New CVEs discovered on deployed image*\n<!date^{{eventTime}}^{date_long} {time} | \"-\">"
+            }
+        },
+        {
+            "type": "divider"
+        },
+        {
+            "type": "section",
+            "fields": [
+                {
+                    "type": "mrkdwn",
+                    "text": "*Application*\n{{appName}}"
+                },
+                {
+                    "type": "mrkdwn",
+                    "text": "*Environment*\n{{envName}}"
+                },
+                {
+                    "type": "mrkdwn",
+                    "text": "*Image*\n`{{dockerImageUrl}}`"
+                },
+                {
+                    "type": "mrkdwn",
+                    "text": "*New CVEs*\n{{#newCves}}{{#cves}}{{name}} ({{severity}}) {{/cves}}{{/newCves}}"
+                }
+            ]
+        }
+    ]
+}');
+
+---- new cve discovered template for CD ses/smtp
+INSERT INTO "public"."notification_templates" ("channel_type", "node_type", "event_type_id", "template_name", "template_payload") VALUES
+('ses', 'CD', '6', 'CD new cve discovered ses template', '{"from": "{{fromEmail}}",
+ "to": "{{toEmail}}",
+ "subject": "New CVEs discovered on deployed image of app: {{appName}}",
+ "html": "<h2 style=\"color:#ff7e5b;\">New CVEs Discovered On Deployed Image</h2><span>{{eventTime}}</span><br><br><hr><br><span>Application: <strong>{{appName}}</strong></span>&nbsp;&nbsp;|&nbsp;&nbsp;<span>Environment: <strong>{{envName}}</strong></span><br><span>Image: <strong>{{dockerImageUrl}}</strong></span><br><span>New CVEs: {{#newCves}}{{#cves}}<strong>{{name}}</strong> ({{severity}}) {{#fixedVersion}}fixed in {{fixedVersion}}{{/fixedVersion}} {{/cves}}{{/newCves}}</span><br><br>"}');
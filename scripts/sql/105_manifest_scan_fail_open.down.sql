@@ -0,0 +1 @@
+ALTER TABLE "public"."manifest_scan_policy" DROP COLUMN IF EXISTS "fail_open";
@@ -0,0 +1 @@
+ALTER TABLE "public"."manifest_scan_policy" ADD COLUMN IF NOT EXISTS "fail_open" boolean NOT NULL DEFAULT false;
@@ -0,0 +1,7 @@
+UPDATE "public"."plugin_pipeline_script"
+SET script = 'echo "secret leak scan of git materials is run by ci runner, FailOnFindings: $FailOnFindings"', updated_on = 'now()'
+WHERE id IN (SELECT script_id FROM "public"."plugin_step" WHERE plugin_id IN (SELECT id FROM "public"."plugin_metadata" WHERE name = 'Secret Leak Scan'));
+
+DROP TABLE "public"."ci_workflow_secret_scan" CASCADE;
+
+DROP SEQUENCE IF EXISTS id_seq_ci_workflow_secret_scan;
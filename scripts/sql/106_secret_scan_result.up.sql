@@ -0,0 +1,32 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_ci_workflow_secret_scan;
+
+-- Table Definition
+CREATE TABLE "public"."ci_workflow_secret_scan"
+(
+    "id"             integer     NOT NULL DEFAULT nextval('id_seq_ci_workflow_secret_scan'::regclass),
+    "ci_workflow_id" integer     NOT NULL,
+    "status"         varchar(50) NOT NULL,
+    "blocked"        boolean     NOT NULL,
+    "created_on"     timestamptz,
+    "created_by"     int4,
+    "updated_on"     timestamptz,
+    "updated_by"     int4,
+    CONSTRAINT "ci_workflow_secret_scan_ci_workflow_id_fkey" FOREIGN KEY ("ci_workflow_id") REFERENCES "public"."ci_workflow" ("id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ci_workflow_secret_scan_ci_workflow_id_idx ON "public"."ci_workflow_secret_scan" ("ci_workflow_id");
+
+--- the scan result is written by ci runner, the step fails when the scan did not run or found secrets which are not allow listed
+
+UPDATE "public"."plugin_pipeline_script"
+SET script = 'if [ ! -f "/devtroncd/secret-scan/result.json" ]; then
+  echo "secret leak scan did not run, ci runner does not support secret scan of git materials"
+  exit 1
+fi
+if [ "$FailOnFindings" = "true" ] && grep -q ''"blocked": *true'' /devtroncd/secret-scan/result.json; then
+  echo "secret leak scan found secrets which are not allow listed"
+  exit 1
+fi
+echo "secret leak scan of git materials passed"', updated_on = 'now()'
+WHERE id IN (SELECT script_id FROM "public"."plugin_step" WHERE plugin_id IN (SELECT id FROM "public"."plugin_metadata" WHERE name = 'Secret Leak Scan'));
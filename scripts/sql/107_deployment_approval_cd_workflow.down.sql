@@ -0,0 +1,3 @@
+ALTER TABLE "public"."deployment_approval_request" DROP CONSTRAINT IF EXISTS "deployment_approval_request_cd_workflow_id_fkey";
+
+ALTER TABLE "public"."deployment_approval_request" DROP COLUMN IF EXISTS "cd_workflow_id";
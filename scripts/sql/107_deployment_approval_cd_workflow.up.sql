@@ -0,0 +1,3 @@
+ALTER TABLE "public"."deployment_approval_request" ADD COLUMN IF NOT EXISTS "cd_workflow_id" integer;
+
+ALTER TABLE "public"."deployment_approval_request" ADD CONSTRAINT "deployment_approval_request_cd_workflow_id_fkey" FOREIGN KEY ("cd_workflow_id") REFERENCES "public"."cd_workflow" ("id");
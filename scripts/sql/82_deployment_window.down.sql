@@ -0,0 +1,7 @@
+DROP TABLE "public"."deployment_queue" CASCADE;
+
+DROP SEQUENCE IF EXISTS id_seq_deployment_queue;
+
+DROP TABLE "public"."deployment_window" CASCADE;
+
+DROP SEQUENCE IF EXISTS id_seq_deployment_window;
@@ -0,0 +1,49 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_deployment_window;
+
+-- Table Definition
+CREATE TABLE "public"."deployment_window"
+(
+    "id"                          integer NOT NULL DEFAULT nextval('id_seq_deployment_window'::regclass),
+    "environment_id"              integer NOT NULL,
+    "name"                        varchar(250) NOT NULL,
+    "type"                        varchar(50) NOT NULL,
+    "timezone"                    varchar(100),
+    "week_days"                   text[],
+    "start_time"                  varchar(10),
+    "end_time"                    varchar(10),
+    "start_date"                  timestamptz,
+    "end_date"                    timestamptz,
+    "active"                      boolean NOT NULL,
+    "created_on"                  timestamptz,
+    "created_by"                  int4,
+    "updated_on"                  timestamptz,
+    "updated_by"                  int4,
+    CONSTRAINT "deployment_window_environment_id_fkey" FOREIGN KEY ("environment_id") REFERENCES "public"."environment" ("id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_deployment_queue;
+
+-- Table Definition
+CREATE TABLE "public"."deployment_queue"
+(
+    "id"                          integer NOT NULL DEFAULT nextval('id_seq_deployment_queue'::regclass),
+    "pipeline_id"                 integer NOT NULL,
+    "environment_id"              integer NOT NULL,
+    "ci_artifact_id"              integer NOT NULL,
+    "cd_workflow_id"              integer NOT NULL,
+    "cd_workflow_runner_id"       integer NOT NULL,
+    "triggered_by"                int4,
+    "status"                      varchar(50) NOT NULL,
+    "queued_on"                   timestamptz NOT NULL,
+    "processed_on"                timestamptz,
+    "created_on"                  timestamptz,
+    "created_by"                  int4,
+    "updated_on"                  timestamptz,
+    "updated_by"                  int4,
+    CONSTRAINT "deployment_queue_pipeline_id_fkey" FOREIGN KEY ("pipeline_id") REFERENCES "public"."pipeline" ("id"),
+    CONSTRAINT "deployment_queue_cd_workflow_runner_id_fkey" FOREIGN KEY ("cd_workflow_runner_id") REFERENCES "public"."cd_workflow_runner" ("id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX "deployment_queue_status_idx" ON "public"."deployment_queue" USING BTREE ("status");
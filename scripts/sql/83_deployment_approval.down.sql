@@ -0,0 +1,13 @@
+DELETE FROM "public"."event" WHERE "id" = 4;
+
+DROP TABLE "public"."deployment_approval_user_data" CASCADE;
+
+DROP SEQUENCE IF EXISTS id_seq_deployment_approval_user_data;
+
+DROP TABLE "public"."deployment_approval_request" CASCADE;
+
+DROP SEQUENCE IF EXISTS id_seq_deployment_approval_request;
+
+DROP TABLE "public"."deployment_approval_policy" CASCADE;
+
+DROP SEQUENCE IF EXISTS id_seq_deployment_approval_policy;
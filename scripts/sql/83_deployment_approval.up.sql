@@ -0,0 +1,62 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_deployment_approval_policy;
+
+-- Table Definition
+CREATE TABLE "public"."deployment_approval_policy"
+(
+    "id"                          integer NOT NULL DEFAULT nextval('id_seq_deployment_approval_policy'::regclass),
+    "pipeline_id"                 integer NOT NULL,
+    "required_approvals"          integer NOT NULL,
+    "approver_user_ids"           integer[],
+    "approver_role_group_ids"     integer[],
+    "active"                      boolean NOT NULL,
+    "created_on"                  timestamptz,
+    "created_by"                  int4,
+    "updated_on"                  timestamptz,
+    "updated_by"                  int4,
+    CONSTRAINT "deployment_approval_policy_pipeline_id_fkey" FOREIGN KEY ("pipeline_id") REFERENCES "public"."pipeline" ("id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_deployment_approval_request;
+
+-- Table Definition
+CREATE TABLE "public"."deployment_approval_request"
+(
+    "id"                          integer NOT NULL DEFAULT nextval('id_seq_deployment_approval_request'::regclass),
+    "pipeline_id"                 integer NOT NULL,
+    "ci_artifact_id"              integer NOT NULL,
+    "status"                      varchar(50) NOT NULL,
+    "requested_by"                int4 NOT NULL,
+    "auto_trigger"                boolean NOT NULL DEFAULT false,
+    "active"                      boolean NOT NULL,
+    "created_on"                  timestamptz,
+    "created_by"                  int4,
+    "updated_on"                  timestamptz,
+    "updated_by"                  int4,
+    CONSTRAINT "deployment_approval_request_pipeline_id_fkey" FOREIGN KEY ("pipeline_id") REFERENCES "public"."pipeline" ("id"),
+    CONSTRAINT "deployment_approval_request_ci_artifact_id_fkey" FOREIGN KEY ("ci_artifact_id") REFERENCES "public"."ci_artifact" ("id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX "deployment_approval_request_pipeline_id_ci_artifact_id_idx" ON "public"."deployment_approval_request" USING BTREE ("pipeline_id", "ci_artifact_id");
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_deployment_approval_user_data;
+
+-- Table Definition
+CREATE TABLE "public"."deployment_approval_user_data"
+(
+    "id"                          integer NOT NULL DEFAULT nextval('id_seq_deployment_approval_user_data'::regclass),
+    "approval_request_id"         integer NOT NULL,
+    "user_id"                     int4 NOT NULL,
+    "user_response"               varchar(50) NOT NULL,
+    "comment"                     text,
+    "created_on"                  timestamptz,
+    "created_by"                  int4,
+    "updated_on"                  timestamptz,
+    "updated_by"                  int4,
+    CONSTRAINT "deployment_approval_user_data_approval_request_id_fkey" FOREIGN KEY ("approval_request_id") REFERENCES "public"."deployment_approval_request" ("id"),
+    UNIQUE ("approval_request_id", "user_id"),
+    PRIMARY KEY ("id")
+);
+
+INSERT INTO "public"."event" ("id", "event_type", "description") VALUES ('4', 'APPROVAL_REQUESTED', '');
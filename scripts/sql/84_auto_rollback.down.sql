@@ -0,0 +1,7 @@
+DROP TABLE "public"."cd_pipeline_auto_rollback_history" CASCADE;
+
+DROP SEQUENCE IF EXISTS id_seq_cd_pipeline_auto_rollback_history;
+
+DROP TABLE "public"."cd_pipeline_auto_rollback_policy" CASCADE;
+
+DROP SEQUENCE IF EXISTS id_seq_cd_pipeline_auto_rollback_policy;
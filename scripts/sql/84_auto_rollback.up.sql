@@ -0,0 +1,42 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_cd_pipeline_auto_rollback_policy;
+
+-- Table Definition
+CREATE TABLE "public"."cd_pipeline_auto_rollback_policy"
+(
+    "id"                             integer NOT NULL DEFAULT nextval('id_seq_cd_pipeline_auto_rollback_policy'::regclass),
+    "pipeline_id"                    integer NOT NULL,
+    "enabled"                        boolean NOT NULL,
+    "health_check_window_in_minutes" integer NOT NULL,
+    "created_on"                     timestamptz,
+    "created_by"                     int4,
+    "updated_on"                     timestamptz,
+    "updated_by"                     int4,
+    CONSTRAINT "cd_pipeline_auto_rollback_policy_pipeline_id_fkey" FOREIGN KEY ("pipeline_id") REFERENCES "public"."pipeline" ("id"),
+    CONSTRAINT "cd_pipeline_auto_rollback_policy_pipeline_id_key" UNIQUE ("pipeline_id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_cd_pipeline_auto_rollback_history;
+
+-- Table Definition
+CREATE TABLE "public"."cd_pipeline_auto_rollback_history"
+(
+    "id"                             integer NOT NULL DEFAULT nextval('id_seq_cd_pipeline_auto_rollback_history'::regclass),
+    "pipeline_id"                    integer NOT NULL,
+    "degraded_cd_workflow_runner_id" integer NOT NULL,
+    "degraded_ci_artifact_id"        integer NOT NULL,
+    "rollback_ci_artifact_id"        integer,
+    "rollback_cd_workflow_runner_id" integer,
+    "status"                         varchar(50) NOT NULL,
+    "message"                        text,
+    "created_on"                     timestamptz,
+    "created_by"                     int4,
+    "updated_on"                     timestamptz,
+    "updated_by"                     int4,
+    CONSTRAINT "cd_pipeline_auto_rollback_history_pipeline_id_fkey" FOREIGN KEY ("pipeline_id") REFERENCES "public"."pipeline" ("id"),
+    CONSTRAINT "cd_pipeline_auto_rollback_history_degraded_wfr_id_fkey" FOREIGN KEY ("degraded_cd_workflow_runner_id") REFERENCES "public"."cd_workflow_runner" ("id"),
+    CONSTRAINT "cd_pipeline_auto_rollback_history_rollback_wfr_id_fkey" FOREIGN KEY ("rollback_cd_workflow_runner_id") REFERENCES "public"."cd_workflow_runner" ("id"),
+    -- a degraded deployment is rolled back at most once
+    CONSTRAINT "cd_pipeline_auto_rollback_history_degraded_wfr_id_key" UNIQUE ("degraded_cd_workflow_runner_id"),
+    PRIMARY KEY ("id")
+);
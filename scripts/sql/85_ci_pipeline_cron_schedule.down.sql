@@ -0,0 +1 @@
+ALTER TABLE "public"."ci_pipeline" DROP COLUMN IF EXISTS "cron_schedule";
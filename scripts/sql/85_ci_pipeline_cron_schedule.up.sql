@@ -0,0 +1 @@
+ALTER TABLE "public"."ci_pipeline" ADD COLUMN IF NOT EXISTS "cron_schedule" varchar(100) NOT NULL DEFAULT '';
@@ -0,0 +1,7 @@
+DROP TABLE "public"."deployment_schedule_run" CASCADE;
+
+DROP SEQUENCE IF EXISTS id_seq_deployment_schedule_run;
+
+DROP TABLE "public"."deployment_schedule" CASCADE;
+
+DROP SEQUENCE IF EXISTS id_seq_deployment_schedule;
@@ -0,0 +1,50 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_deployment_schedule;
+
+-- Table Definition
+CREATE TABLE "public"."deployment_schedule"
+(
+    "id"                          integer NOT NULL DEFAULT nextval('id_seq_deployment_schedule'::regclass),
+    "name"                        varchar(250) NOT NULL,
+    "target_type"                 varchar(50) NOT NULL,
+    "pipeline_id"                 integer,
+    "deployment_group_id"         integer,
+    "action"                      varchar(50) NOT NULL,
+    "cron_schedule"               varchar(100) NOT NULL,
+    "timezone"                    varchar(100) NOT NULL,
+    "max_retries"                 integer NOT NULL DEFAULT 0,
+    "active"                      boolean NOT NULL,
+    "created_on"                  timestamptz,
+    "created_by"                  int4,
+    "updated_on"                  timestamptz,
+    "updated_by"                  int4,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX "deployment_schedule_pipeline_id_idx" ON "public"."deployment_schedule" USING BTREE ("pipeline_id");
+CREATE INDEX "deployment_schedule_deployment_group_id_idx" ON "public"."deployment_schedule" USING BTREE ("deployment_group_id");
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_deployment_schedule_run;
+
+-- Table Definition
+CREATE TABLE "public"."deployment_schedule_run"
+(
+    "id"                          integer NOT NULL DEFAULT nextval('id_seq_deployment_schedule_run'::regclass),
+    "schedule_id"                 integer NOT NULL,
+    "action"                      varchar(50) NOT NULL,
+    "attempt"                     integer NOT NULL,
+    "status"                      varchar(50) NOT NULL,
+    "message"                     text,
+    "scheduled_on"                timestamptz NOT NULL,
+    "executed_on"                 timestamptz NOT NULL,
+    "retry_pending"               boolean NOT NULL DEFAULT false,
+    "next_retry_on"               timestamptz,
+    "created_on"                  timestamptz,
+    "created_by"                  int4,
+    "updated_on"                  timestamptz,
+    "updated_by"                  int4,
+    CONSTRAINT "deployment_schedule_run_schedule_id_fkey" FOREIGN KEY ("schedule_id") REFERENCES "public"."deployment_schedule" ("id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX "deployment_schedule_run_schedule_id_idx" ON "public"."deployment_schedule_run" USING BTREE ("schedule_id");
+CREATE INDEX "deployment_schedule_run_retry_pending_idx" ON "public"."deployment_schedule_run" USING BTREE ("retry_pending");
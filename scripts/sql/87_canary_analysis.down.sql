@@ -0,0 +1,7 @@
+DROP TABLE "public"."cd_pipeline_canary_analysis_run" CASCADE;
+
+DROP SEQUENCE IF EXISTS id_seq_cd_pipeline_canary_analysis_run;
+
+DROP TABLE "public"."cd_pipeline_canary_analysis_policy" CASCADE;
+
+DROP SEQUENCE IF EXISTS id_seq_cd_pipeline_canary_analysis_policy;
@@ -0,0 +1,46 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_cd_pipeline_canary_analysis_policy;
+
+-- Table Definition
+CREATE TABLE "public"."cd_pipeline_canary_analysis_policy"
+(
+    "id"                           integer NOT NULL DEFAULT nextval('id_seq_cd_pipeline_canary_analysis_policy'::regclass),
+    "pipeline_id"                  integer NOT NULL,
+    "enabled"                      boolean NOT NULL,
+    "error_rate_query"             text,
+    "latency_query"                text,
+    "max_error_rate_increase"      float8  NOT NULL,
+    "max_latency_increase_percent" float8  NOT NULL,
+    "steps"                        text,
+    "created_on"                   timestamptz,
+    "created_by"                   int4,
+    "updated_on"                   timestamptz,
+    "updated_by"                   int4,
+    CONSTRAINT "cd_pipeline_canary_analysis_policy_pipeline_id_fkey" FOREIGN KEY ("pipeline_id") REFERENCES "public"."pipeline" ("id"),
+    CONSTRAINT "cd_pipeline_canary_analysis_policy_pipeline_id_key" UNIQUE ("pipeline_id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_cd_pipeline_canary_analysis_run;
+
+-- Table Definition
+CREATE TABLE "public"."cd_pipeline_canary_analysis_run"
+(
+    "id"                    integer NOT NULL DEFAULT nextval('id_seq_cd_pipeline_canary_analysis_run'::regclass),
+    "pipeline_id"           integer NOT NULL,
+    "cd_workflow_runner_id" integer NOT NULL,
+    "current_step"          integer NOT NULL,
+    "status"                varchar(50) NOT NULL,
+    "message"               text,
+    "next_analysis_on"      timestamptz,
+    "created_on"            timestamptz,
+    "created_by"            int4,
+    "updated_on"            timestamptz,
+    "updated_by"            int4,
+    CONSTRAINT "cd_pipeline_canary_analysis_run_pipeline_id_fkey" FOREIGN KEY ("pipeline_id") REFERENCES "public"."pipeline" ("id"),
+    CONSTRAINT "cd_pipeline_canary_analysis_run_wfr_id_fkey" FOREIGN KEY ("cd_workflow_runner_id") REFERENCES "public"."cd_workflow_runner" ("id"),
+    -- a deployment is analysed at most once
+    CONSTRAINT "cd_pipeline_canary_analysis_run_wfr_id_key" UNIQUE ("cd_workflow_runner_id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS cd_pipeline_canary_analysis_run_status_idx ON public.cd_pipeline_canary_analysis_run (status);
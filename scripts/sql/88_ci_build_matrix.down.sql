@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS "ci_workflow_parent_ci_workflow_id_idx";
+
+ALTER TABLE "public"."ci_workflow" DROP CONSTRAINT IF EXISTS "ci_workflow_parent_ci_workflow_id_fkey";
+ALTER TABLE "public"."ci_workflow" DROP COLUMN IF EXISTS "matrix_image";
+ALTER TABLE "public"."ci_workflow" DROP COLUMN IF EXISTS "matrix_leg";
+ALTER TABLE "public"."ci_workflow" DROP COLUMN IF EXISTS "parent_ci_workflow_id";
+
+ALTER TABLE "public"."ci_pipeline" DROP COLUMN IF EXISTS "build_matrix";
@@ -0,0 +1,8 @@
+ALTER TABLE "public"."ci_pipeline" ADD COLUMN IF NOT EXISTS "build_matrix" text NOT NULL DEFAULT '';
+
+ALTER TABLE "public"."ci_workflow" ADD COLUMN IF NOT EXISTS "parent_ci_workflow_id" integer;
+ALTER TABLE "public"."ci_workflow" ADD COLUMN IF NOT EXISTS "matrix_leg" varchar(250);
+ALTER TABLE "public"."ci_workflow" ADD COLUMN IF NOT EXISTS "matrix_image" text;
+ALTER TABLE "public"."ci_workflow" ADD CONSTRAINT "ci_workflow_parent_ci_workflow_id_fkey" FOREIGN KEY ("parent_ci_workflow_id") REFERENCES "public"."ci_workflow" ("id");
+
+CREATE INDEX IF NOT EXISTS "ci_workflow_parent_ci_workflow_id_idx" ON "public"."ci_workflow" ("parent_ci_workflow_id");
@@ -0,0 +1,4 @@
+ALTER TABLE "public"."pipeline_stage_step" DROP COLUMN IF EXISTS "continue_on_error";
+ALTER TABLE "public"."pipeline_stage_step" DROP COLUMN IF EXISTS "retry_backoff";
+ALTER TABLE "public"."pipeline_stage_step" DROP COLUMN IF EXISTS "retry_count";
+ALTER TABLE "public"."pipeline_stage_step" DROP COLUMN IF EXISTS "timeout";
@@ -0,0 +1,4 @@
+ALTER TABLE "public"."pipeline_stage_step" ADD COLUMN IF NOT EXISTS "timeout" integer NOT NULL DEFAULT 0;
+ALTER TABLE "public"."pipeline_stage_step" ADD COLUMN IF NOT EXISTS "retry_count" integer NOT NULL DEFAULT 0;
+ALTER TABLE "public"."pipeline_stage_step" ADD COLUMN IF NOT EXISTS "retry_backoff" integer NOT NULL DEFAULT 0;
+ALTER TABLE "public"."pipeline_stage_step" ADD COLUMN IF NOT EXISTS "continue_on_error" boolean NOT NULL DEFAULT false;
@@ -0,0 +1,8 @@
+DELETE FROM "public"."event" WHERE "id" = 5;
+
+DROP INDEX IF EXISTS "cve_policy_control_expires_on_idx";
+
+ALTER TABLE "public"."cve_policy_control" DROP COLUMN IF EXISTS "expiry_notified_on";
+ALTER TABLE "public"."cve_policy_control" DROP COLUMN IF EXISTS "expires_on";
+ALTER TABLE "public"."cve_policy_control" DROP COLUMN IF EXISTS "owner";
+ALTER TABLE "public"."cve_policy_control" DROP COLUMN IF EXISTS "justification";
@@ -0,0 +1,8 @@
+ALTER TABLE "public"."cve_policy_control" ADD COLUMN IF NOT EXISTS "justification" text;
+ALTER TABLE "public"."cve_policy_control" ADD COLUMN IF NOT EXISTS "owner" varchar(250);
+ALTER TABLE "public"."cve_policy_control" ADD COLUMN IF NOT EXISTS "expires_on" timestamptz;
+ALTER TABLE "public"."cve_policy_control" ADD COLUMN IF NOT EXISTS "expiry_notified_on" timestamptz;
+
+CREATE INDEX IF NOT EXISTS "cve_policy_control_expires_on_idx" ON "public"."cve_policy_control" ("expires_on");
+
+INSERT INTO "public"."event" ("id", "event_type", "description") VALUES ('5', 'CVE_EXCEPTION_EXPIRING', '');
@@ -0,0 +1,3 @@
+DROP TABLE "public"."cve_policy_rule" CASCADE;
+
+DROP SEQUENCE IF EXISTS id_seq_cve_policy_rule;
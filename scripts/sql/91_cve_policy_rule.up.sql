@@ -0,0 +1,25 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_cve_policy_rule;
+
+-- Table Definition
+CREATE TABLE "public"."cve_policy_rule"
+(
+    "id"         integer     NOT NULL DEFAULT nextval('id_seq_cve_policy_rule'::regclass),
+    "global"     boolean     NOT NULL,
+    "cluster_id" integer,
+    "env_id"     integer,
+    "app_id"     integer,
+    "rule_type"  varchar(50) NOT NULL,
+    "action"     integer     NOT NULL,
+    "severity"   integer,
+    "package"    varchar(250),
+    "threshold"  integer     NOT NULL DEFAULT 0,
+    "deleted"    boolean     NOT NULL,
+    "created_on" timestamptz,
+    "created_by" int4,
+    "updated_on" timestamptz,
+    "updated_by" int4,
+    CONSTRAINT "cve_policy_rule_cluster_id_fkey" FOREIGN KEY ("cluster_id") REFERENCES "public"."cluster" ("id"),
+    CONSTRAINT "cve_policy_rule_env_id_fkey" FOREIGN KEY ("env_id") REFERENCES "public"."environment" ("id"),
+    CONSTRAINT "cve_policy_rule_app_id_fkey" FOREIGN KEY ("app_id") REFERENCES "public"."app" ("id"),
+    PRIMARY KEY ("id")
+);
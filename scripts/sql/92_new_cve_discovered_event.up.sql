@@ -0,0 +1 @@
+INSERT INTO "public"."event" ("id", "event_type", "description") VALUES ('6', 'NEW_CVE_DISCOVERED', '');
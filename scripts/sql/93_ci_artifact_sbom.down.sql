@@ -0,0 +1,7 @@
+DROP TABLE "public"."ci_artifact_sbom_component" CASCADE;
+
+DROP SEQUENCE IF EXISTS id_seq_ci_artifact_sbom_component;
+
+DROP TABLE "public"."ci_artifact_sbom" CASCADE;
+
+DROP SEQUENCE IF EXISTS id_seq_ci_artifact_sbom;
@@ -0,0 +1,37 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_ci_artifact_sbom;
+
+-- Table Definition
+CREATE TABLE "public"."ci_artifact_sbom"
+(
+    "id"             integer      NOT NULL DEFAULT nextval('id_seq_ci_artifact_sbom'::regclass),
+    "ci_artifact_id" integer      NOT NULL,
+    "image_digest"   varchar(250) NOT NULL,
+    "format"         varchar(50)  NOT NULL,
+    "spec_version"   varchar(50),
+    "content"        text         NOT NULL,
+    "created_on"     timestamptz,
+    "created_by"     int4,
+    "updated_on"     timestamptz,
+    "updated_by"     int4,
+    CONSTRAINT "ci_artifact_sbom_ci_artifact_id_fkey" FOREIGN KEY ("ci_artifact_id") REFERENCES "public"."ci_artifact" ("id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS ci_artifact_sbom_image_digest_idx ON public.ci_artifact_sbom (image_digest);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_ci_artifact_sbom_component;
+
+-- Table Definition
+CREATE TABLE "public"."ci_artifact_sbom_component"
+(
+    "id"      integer      NOT NULL DEFAULT nextval('id_seq_ci_artifact_sbom_component'::regclass),
+    "sbom_id" integer      NOT NULL,
+    "name"    varchar(250) NOT NULL,
+    "version" varchar(250),
+    "purl"    text,
+    "type"    varchar(50),
+    CONSTRAINT "ci_artifact_sbom_component_sbom_id_fkey" FOREIGN KEY ("sbom_id") REFERENCES "public"."ci_artifact_sbom" ("id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS ci_artifact_sbom_component_name_version_idx ON public.ci_artifact_sbom_component (name, version);
@@ -0,0 +1,3 @@
+DROP TABLE "public"."image_signature_policy" CASCADE;
+
+DROP SEQUENCE IF EXISTS id_seq_image_signature_policy;
@@ -0,0 +1,20 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_image_signature_policy;
+
+-- Table Definition
+CREATE TABLE "public"."image_signature_policy"
+(
+    "id"             integer      NOT NULL DEFAULT nextval('id_seq_image_signature_policy'::regclass),
+    "name"           varchar(250) NOT NULL,
+    "cluster_id"     integer,
+    "environment_id" integer,
+    "format"         varchar(50)  NOT NULL,
+    "public_key"     text         NOT NULL,
+    "active"         boolean      NOT NULL,
+    "created_on"     timestamptz,
+    "created_by"     int4,
+    "updated_on"     timestamptz,
+    "updated_by"     int4,
+    CONSTRAINT "image_signature_policy_cluster_id_fkey" FOREIGN KEY ("cluster_id") REFERENCES "public"."cluster" ("id"),
+    CONSTRAINT "image_signature_policy_environment_id_fkey" FOREIGN KEY ("environment_id") REFERENCES "public"."environment" ("id"),
+    PRIMARY KEY ("id")
+);
@@ -0,0 +1,5 @@
+ALTER TABLE "public"."ci_artifact_sbom_component" DROP COLUMN IF EXISTS "license";
+
+DROP TABLE "public"."license_policy" CASCADE;
+
+DROP SEQUENCE IF EXISTS id_seq_license_policy;
@@ -0,0 +1,24 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_license_policy;
+
+-- Table Definition
+CREATE TABLE "public"."license_policy"
+(
+    "id"         integer      NOT NULL DEFAULT nextval('id_seq_license_policy'::regclass),
+    "global"     boolean      NOT NULL,
+    "cluster_id" integer,
+    "env_id"     integer,
+    "app_id"     integer,
+    "license"    varchar(250) NOT NULL,
+    "action"     integer      NOT NULL,
+    "deleted"    boolean      NOT NULL,
+    "created_on" timestamptz,
+    "created_by" int4,
+    "updated_on" timestamptz,
+    "updated_by" int4,
+    CONSTRAINT "license_policy_cluster_id_fkey" FOREIGN KEY ("cluster_id") REFERENCES "public"."cluster" ("id"),
+    CONSTRAINT "license_policy_env_id_fkey" FOREIGN KEY ("env_id") REFERENCES "public"."environment" ("id"),
+    CONSTRAINT "license_policy_app_id_fkey" FOREIGN KEY ("app_id") REFERENCES "public"."app" ("id"),
+    PRIMARY KEY ("id")
+);
+
+ALTER TABLE "public"."ci_artifact_sbom_component" ADD COLUMN IF NOT EXISTS "license" text;
@@ -0,0 +1,7 @@
+DROP TABLE "public"."security_posture_snapshot" CASCADE;
+
+DROP SEQUENCE IF EXISTS id_seq_security_posture_snapshot;
+
+DROP TABLE "public"."security_posture_finding" CASCADE;
+
+DROP SEQUENCE IF EXISTS id_seq_security_posture_finding;
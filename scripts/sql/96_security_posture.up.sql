@@ -0,0 +1,42 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_security_posture_finding;
+
+-- Table Definition
+CREATE TABLE "public"."security_posture_finding"
+(
+    "id"                        integer      NOT NULL DEFAULT nextval('id_seq_security_posture_finding'::regclass),
+    "image_scan_deploy_info_id" integer      NOT NULL,
+    "app_id"                    integer,
+    "team_id"                   integer,
+    "env_id"                    integer,
+    "cluster_id"                integer      NOT NULL,
+    "cve_store_name"            varchar(255) NOT NULL,
+    "severity"                  integer      NOT NULL,
+    "first_seen_on"             timestamptz  NOT NULL,
+    "remediated_on"             timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS security_posture_finding_open_idx ON "public"."security_posture_finding" (image_scan_deploy_info_id) WHERE remediated_on IS NULL;
+CREATE INDEX IF NOT EXISTS security_posture_finding_remediated_on_idx ON "public"."security_posture_finding" (remediated_on);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_security_posture_snapshot;
+
+-- Table Definition
+CREATE TABLE "public"."security_posture_snapshot"
+(
+    "id"                integer          NOT NULL DEFAULT nextval('id_seq_security_posture_snapshot'::regclass),
+    "snapshot_date"     date             NOT NULL,
+    "scope_type"        varchar(50)      NOT NULL,
+    "scope_id"          integer          NOT NULL,
+    "scope_name"        varchar(250),
+    "critical_count"    integer          NOT NULL DEFAULT 0,
+    "moderate_count"    integer          NOT NULL DEFAULT 0,
+    "low_count"         integer          NOT NULL DEFAULT 0,
+    "remediated_count"  integer          NOT NULL DEFAULT 0,
+    "remediation_hours" double precision NOT NULL DEFAULT 0,
+    "created_on"        timestamptz      NOT NULL,
+    PRIMARY KEY ("id"),
+    UNIQUE ("snapshot_date", "scope_type", "scope_id")
+);
+
+CREATE INDEX IF NOT EXISTS security_posture_snapshot_scope_idx ON "public"."security_posture_snapshot" (scope_type, snapshot_date);
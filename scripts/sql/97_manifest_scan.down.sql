@@ -0,0 +1,7 @@
+DROP TABLE "public"."manifest_scan_result" CASCADE;
+
+DROP SEQUENCE IF EXISTS id_seq_manifest_scan_result;
+
+DROP TABLE "public"."manifest_scan_policy" CASCADE;
+
+DROP SEQUENCE IF EXISTS id_seq_manifest_scan_policy;
@@ -0,0 +1,48 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_manifest_scan_policy;
+
+-- Table Definition
+CREATE TABLE "public"."manifest_scan_policy"
+(
+    "id"         integer      NOT NULL DEFAULT nextval('id_seq_manifest_scan_policy'::regclass),
+    "global"     boolean      NOT NULL,
+    "cluster_id" integer,
+    "env_id"     integer,
+    "app_id"     integer,
+    "rule_id"    varchar(100) NOT NULL,
+    "action"     integer      NOT NULL,
+    "deleted"    boolean      NOT NULL,
+    "created_on" timestamptz,
+    "created_by" int4,
+    "updated_on" timestamptz,
+    "updated_by" int4,
+    CONSTRAINT "manifest_scan_policy_cluster_id_fkey" FOREIGN KEY ("cluster_id") REFERENCES "public"."cluster" ("id"),
+    CONSTRAINT "manifest_scan_policy_env_id_fkey" FOREIGN KEY ("env_id") REFERENCES "public"."environment" ("id"),
+    CONSTRAINT "manifest_scan_policy_app_id_fkey" FOREIGN KEY ("app_id") REFERENCES "public"."app" ("id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_manifest_scan_result;
+
+-- Table Definition
+CREATE TABLE "public"."manifest_scan_result"
+(
+    "id"                    integer      NOT NULL DEFAULT nextval('id_seq_manifest_scan_result'::regclass),
+    "cd_workflow_runner_id" integer      NOT NULL,
+    "app_id"                integer      NOT NULL,
+    "env_id"                integer      NOT NULL,
+    "rule_id"               varchar(100) NOT NULL,
+    "severity"              varchar(50)  NOT NULL,
+    "kind"                  varchar(250) NOT NULL,
+    "resource_name"         varchar(250) NOT NULL,
+    "container"             varchar(250),
+    "message"               text         NOT NULL,
+    "action"                varchar(50)  NOT NULL,
+    "created_on"            timestamptz,
+    "created_by"            int4,
+    "updated_on"            timestamptz,
+    "updated_by"            int4,
+    CONSTRAINT "manifest_scan_result_cd_workflow_runner_id_fkey" FOREIGN KEY ("cd_workflow_runner_id") REFERENCES "public"."cd_workflow_runner" ("id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS manifest_scan_result_cd_workflow_runner_id_idx ON "public"."manifest_scan_result" ("cd_workflow_runner_id");
@@ -0,0 +1,17 @@
+DELETE FROM "public"."plugin_step_variable" WHERE plugin_step_id IN (SELECT id FROM "public"."plugin_step" WHERE plugin_id IN (SELECT id FROM "public"."plugin_metadata" WHERE name = 'Secret Leak Scan'));
+
+DELETE FROM "public"."plugin_step" WHERE plugin_id IN (SELECT id FROM "public"."plugin_metadata" WHERE name = 'Secret Leak Scan');
+
+DELETE FROM "public"."plugin_pipeline_script" WHERE script LIKE 'echo "secret leak scan of git materials%';
+
+DELETE FROM "public"."plugin_tag_relation" WHERE plugin_id IN (SELECT id FROM "public"."plugin_metadata" WHERE name = 'Secret Leak Scan');
+
+DELETE FROM "public"."plugin_metadata" WHERE name = 'Secret Leak Scan';
+
+DROP TABLE "public"."secret_scan_allow_list" CASCADE;
+
+DROP SEQUENCE IF EXISTS id_seq_secret_scan_allow_list;
+
+DROP TABLE "public"."ci_workflow_secret_scan_finding" CASCADE;
+
+DROP SEQUENCE IF EXISTS id_seq_ci_workflow_secret_scan_finding;
@@ -0,0 +1,79 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_ci_workflow_secret_scan_finding;
+
+-- Table Definition
+CREATE TABLE "public"."ci_workflow_secret_scan_finding"
+(
+    "id"              integer      NOT NULL DEFAULT nextval('id_seq_ci_workflow_secret_scan_finding'::regclass),
+    "ci_workflow_id"  integer      NOT NULL,
+    "git_material_id" integer      NOT NULL,
+    "rule_id"         varchar(100) NOT NULL,
+    "file_path"       text         NOT NULL,
+    "line"            integer,
+    "redacted_match"  text,
+    "fingerprint"     varchar(100) NOT NULL,
+    "action"          varchar(50)  NOT NULL,
+    "created_on"      timestamptz,
+    "created_by"      int4,
+    "updated_on"      timestamptz,
+    "updated_by"      int4,
+    CONSTRAINT "ci_workflow_secret_scan_finding_ci_workflow_id_fkey" FOREIGN KEY ("ci_workflow_id") REFERENCES "public"."ci_workflow" ("id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS ci_workflow_secret_scan_finding_ci_workflow_id_idx ON "public"."ci_workflow_secret_scan_finding" ("ci_workflow_id");
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_secret_scan_allow_list;
+
+-- Table Definition
+CREATE TABLE "public"."secret_scan_allow_list"
+(
+    "id"              integer      NOT NULL DEFAULT nextval('id_seq_secret_scan_allow_list'::regclass),
+    "git_material_id" integer      NOT NULL,
+    "rule_id"         varchar(100),
+    "path_pattern"    text,
+    "fingerprint"     varchar(100),
+    "reason"          text,
+    "deleted"         boolean      NOT NULL,
+    "created_on"      timestamptz,
+    "created_by"      int4,
+    "updated_on"      timestamptz,
+    "updated_by"      int4,
+    CONSTRAINT "secret_scan_allow_list_git_material_id_fkey" FOREIGN KEY ("git_material_id") REFERENCES "public"."git_material" ("id"),
+    PRIMARY KEY ("id")
+);
+
+
+--- secret leak scan plugin, the scan is run by ci runner on checked out git materials
+
+INSERT INTO "public"."plugin_metadata" ("id", "name", "description", "type", "icon", "deleted", "created_on",
+                                        "created_by", "updated_on", "updated_by")
+VALUES (nextval('id_seq_plugin_metadata'), 'Secret Leak Scan',
+        'Scans checked out source for leaked credentials like cloud keys, private keys and tokens before the build, findings can be allow listed per repository.',
+        'PRESET',
+        NULL, 'f', 'now()', '1', 'now()', '1');
+
+INSERT INTO "public"."plugin_tag_relation" ("id", "tag_id", "plugin_id", "created_on", "created_by", "updated_on",
+                                            "updated_by")
+VALUES (nextval('id_seq_plugin_tag_relation'), 3,
+        (select currval('id_seq_plugin_metadata')), 'now()', '1', 'now()', '1'),
+       (nextval('id_seq_plugin_tag_relation'), (select id from plugin_tag where name = 'DevSecOps' and deleted = false limit 1),
+        (select currval('id_seq_plugin_metadata')), 'now()', '1', 'now()', '1');
+
+INSERT INTO "public"."plugin_pipeline_script" ("id", "script", "type", "deleted", "created_on", "created_by",
+                                               "updated_on", "updated_by")
+VALUES (nextval('id_seq_plugin_pipeline_script'), 'echo "secret leak scan of git materials is run by ci runner, FailOnFindings: $FailOnFindings"',
+        'SHELL', 'f', 'now()', '1', 'now()', '1');
+
+INSERT INTO "public"."plugin_step" ("id", "plugin_id", "name", "description", "index", "step_type", "script_id",
+                                    "deleted", "created_on", "created_by", "updated_on", "updated_by")
+VALUES ((nextval('id_seq_plugin_step')), (select currval('id_seq_plugin_metadata')), 'Step 1',
+        'Step 1 - Secret Leak Scan', '1', 'INLINE', (select currval('id_seq_plugin_pipeline_script')), 'f',
+        'now()', '1', 'now()', '1');
+
+INSERT INTO "public"."plugin_step_variable" ("id", "plugin_step_id", "name", "format", "description", "is_exposed",
+                                             "allow_empty_value", "variable_type", "value_type", "default_value",
+                                             "variable_step_index", "deleted", "created_on", "created_by", "updated_on",
+                                             "updated_by")
+VALUES ((nextval('id_seq_plugin_step_variable')), (select currval('id_seq_plugin_step')), 'FailOnFindings',
+        'BOOL', 'Fail the build when a secret which is not allow listed is found.', 't', 'f', 'INPUT', 'NEW', 'false',
+        '1', 'f', 'now()', '1', 'now()', '1');
@@ -0,0 +1,3 @@
+DROP TABLE "public"."webhook_config" CASCADE;
+
+DROP SEQUENCE IF EXISTS id_seq_webhook_config;
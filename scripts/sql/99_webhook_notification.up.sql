@@ -0,0 +1,21 @@
+CREATE SEQUENCE IF NOT EXISTS id_seq_webhook_config;
+
+-- Table Definition
+CREATE TABLE "public"."webhook_config"
+(
+    "id"           integer      NOT NULL DEFAULT nextval('id_seq_webhook_config'::regclass),
+    "web_hook_url" varchar(250) NOT NULL,
+    "config_name"  varchar(250) NOT NULL,
+    "header"       jsonb,
+    "payload"      text,
+    "secret"       text,
+    "description"  varchar(500),
+    "owner_id"     int4,
+    "team_id"      int4,
+    "deleted"      boolean      NOT NULL DEFAULT false,
+    "created_on"   timestamptz,
+    "created_by"   int4,
+    "updated_on"   timestamptz,
+    "updated_by"   int4,
+    PRIMARY KEY ("id")
+);
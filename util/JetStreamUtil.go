@@ -43,6 +43,9 @@ const (
 	CI_COMPLETE_TOPIC                 string = "CI-COMPLETE"
 	CI_COMPLETE_GROUP                 string = "CI-COMPLETE_GROUP-1"
 	CI_COMPLETE_DURABLE               string = "CI-COMPLETE_DURABLE-1"
+	CI_SECRET_SCAN_TOPIC              string = "CI-SECRET-SCAN"
+	CI_SECRET_SCAN_GROUP              string = "CI-SECRET-SCAN_GROUP-1"
+	CI_SECRET_SCAN_DURABLE            string = "CI-SECRET-SCAN_DURABLE-1"
 	APPLICATION_STATUS_UPDATE_TOPIC   string = "APPLICATION_STATUS_UPDATE"
 	APPLICATION_STATUS_UPDATE_GROUP   string = "APPLICATION_STATUS_UPDATE_GROUP-1"
 	APPLICATION_STATUS_UPDATE_DURABLE string = "APPLICATION_STATUS_UPDATE_DURABLE-1"
@@ -63,7 +66,7 @@ const (
 )
 
 var ORCHESTRATOR_SUBJECTS = []string{BULK_APPSTORE_DEPLOY_TOPIC, BULK_DEPLOY_TOPIC, BULK_HIBERNATE_TOPIC, CD_SUCCESS, WEBHOOK_EVENT_TOPIC}
-var CI_RUNNER_SUBJECTS = []string{CI_COMPLETE_TOPIC, CD_STAGE_COMPLETE_TOPIC}
+var CI_RUNNER_SUBJECTS = []string{CI_COMPLETE_TOPIC, CD_STAGE_COMPLETE_TOPIC, CI_SECRET_SCAN_TOPIC}
 var KUBEWATCH_SUBJECTS = []string{APPLICATION_STATUS_UPDATE_TOPIC, CRON_EVENTS, WORKFLOW_STATUS_UPDATE_TOPIC, CD_WORKFLOW_STATUS_UPDATE}
 var GIT_SENSOR_SUBJECTS = []string{NEW_CI_MATERIAL_TOPIC}
 
@@ -99,7 +102,33 @@ func AddStream(js nats.JetStreamContext, streamNames ...string) error {
 			}
 		} else if err != nil {
 			log.Fatal("Error while getting stream info", "stream name", streamName, "error", err)
+		} else if subjects := missingSubjects(streamInfo.Config.Subjects, GetStreamSubjects(streamName)); len(subjects) > 0 {
+			//streams created by older versions do not have subjects added later
+			streamConfig := streamInfo.Config
+			streamConfig.Subjects = append(streamConfig.Subjects, subjects...)
+			_, err = js.UpdateStream(&streamConfig)
+			if err != nil {
+				log.Print("Error while adding subjects to stream", "stream name", streamName, "error", err)
+				return err
+			}
 		}
 	}
 	return err
 }
+
+func missingSubjects(existing []string, required []string) []string {
+	var missing []string
+	for _, subject := range required {
+		found := false
+		for _, existingSubject := range existing {
+			if existingSubject == subject {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, subject)
+		}
+	}
+	return missing
+}
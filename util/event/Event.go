@@ -22,20 +22,84 @@ type EventType int
 const Trigger EventType = 1
 const Success EventType = 2
 const Fail EventType = 3
+const ApprovalRequested EventType = 4
+const CveExceptionExpiring EventType = 5
+const NewCveDiscovered EventType = 6
+const DeploymentDegraded EventType = 7
+const DeploymentHealthy EventType = 8
+const ImageScanBlocked EventType = 9
+const PipelineConfigChanged EventType = 10
+const AppHibernated EventType = 11
+const ClusterUnreachable EventType = 12
+
+// NotificationDigest summarises events batched by a de-duplicating or digest notification setting, it is not
+// subscribable and is only sent to the setting which batched the events
+const NotificationDigest EventType = 13
+
+var eventTypeNames = map[EventType]string{
+	Trigger:               "Trigger",
+	Success:               "Success",
+	Fail:                  "Failure",
+	ApprovalRequested:     "Approval requested",
+	CveExceptionExpiring:  "CVE exception expiring",
+	NewCveDiscovered:      "New CVE discovered",
+	DeploymentDegraded:    "Deployment degraded",
+	DeploymentHealthy:     "Deployment healthy",
+	ImageScanBlocked:      "Image scan blocked",
+	PipelineConfigChanged: "Pipeline config changed",
+	AppHibernated:         "App hibernated",
+	ClusterUnreachable:    "Cluster unreachable",
+	NotificationDigest:    "Notification digest",
+}
+
+func (eventType EventType) String() string {
+	return eventTypeNames[eventType]
+}
 
 type PipelineType string
 
 const CI PipelineType = "CI"
 const CD PipelineType = "CD"
 
+// SubscribableEventTypes are event types notification settings of a pipeline type can subscribe to
+var SubscribableEventTypes = map[PipelineType][]EventType{
+	CI: {Trigger, Success, Fail, PipelineConfigChanged},
+	CD: {Trigger, Success, Fail, ApprovalRequested, CveExceptionExpiring, NewCveDiscovered, DeploymentDegraded,
+		DeploymentHealthy, ImageScanBlocked, PipelineConfigChanged, AppHibernated, ClusterUnreachable},
+}
+
+// IsSubscribableEventType returns true when settings of pipeline type can subscribe to event type
+func IsSubscribableEventType(pipelineType PipelineType, eventTypeId int) bool {
+	for _, eventType := range SubscribableEventTypes[pipelineType] {
+		if int(eventType) == eventTypeId {
+			return true
+		}
+	}
+	return false
+}
+
+// DeliveryMode of a notification setting decides when events matching it are sent
+type DeliveryMode string
+
+const (
+	// DeliveryImmediate sends every event as it happens, settings without a mode are immediate
+	DeliveryImmediate DeliveryMode = "IMMEDIATE"
+	// DeliveryDedup sends first event of a pipeline and event type, repeats within the dedup window are sent as one
+	// summary with a count once the window ends
+	DeliveryDedup        DeliveryMode = "DEDUP"
+	DeliveryHourlyDigest DeliveryMode = "HOURLY_DIGEST"
+	DeliveryDailyDigest  DeliveryMode = "DAILY_DIGEST"
+)
+
 type Level string
 
 type Channel string
 
 const (
-	Slack Channel = "slack"
-	SES   Channel = "ses"
-	SMTP  Channel = "smtp"
+	Slack   Channel = "slack"
+	SES     Channel = "ses"
+	SMTP    Channel = "smtp"
+	Webhook Channel = "webhook"
 )
 
 type UpdateType string
@@ -43,4 +107,5 @@ type UpdateType string
 const (
 	UpdateEvents     UpdateType = "events"
 	UpdateRecipients UpdateType = "recipients"
+	UpdateDelivery   UpdateType = "delivery"
 )